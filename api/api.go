@@ -240,16 +240,30 @@ func NewServerRoutes(s Server) *mux.Router {
 	m.Handle(AEGetCSVShortRef.String(), s.Middleware(GetBodyCSVHandler(s.Instance))).Methods(http.MethodGet)
 	routeParams = newrefRouteParams(qhttp.AEGet, false, true, http.MethodGet)
 	handleRefRoute(m, routeParams, s.Middleware(GetHandler(s.Instance, qhttp.AEGet.String())))
+	m.Handle(AEActivityFeed.String(), s.Middleware(ActivityFeedHandler(s.Instance))).Methods(http.MethodGet)
 	m.Handle(AEUnpack.String(), s.Middleware(UnpackHandler(AEUnpack.NoTrailingSlash())))
-	m.Handle(AESaveByUpload.String(), s.Middleware(SaveByUploadHandler(s.Instance, AESaveByUpload.NoTrailingSlash())))
+	if cfg.API.ReadOnly {
+		m.Handle(AESaveByUpload.String(), s.Middleware(lib.ReadOnlyHandler))
+	} else {
+		m.Handle(AESaveByUpload.String(), s.Middleware(SaveByUploadHandler(s.Instance, AESaveByUpload.NoTrailingSlash())))
+	}
 
 	// sync/protocol endpoints
 	if cfg.RemoteServer != nil && cfg.RemoteServer.Enabled {
 		log.Info("running in `remote` mode")
 
-		m.Handle(qhttp.AERemoteDSync.String(), s.Middleware(s.Instance.RemoteServer().DsyncHTTPHandler()))
-		m.Handle(qhttp.AERemoteLogSync.String(), s.Middleware(s.Instance.RemoteServer().LogsyncHTTPHandler()))
-		m.Handle(qhttp.AERemoteRefs.String(), s.Middleware(s.Instance.RemoteServer().RefsHTTPHandler()))
+		dsyncHandler := s.Instance.RemoteServer().DsyncHTTPHandler()
+		logsyncHandler := s.Instance.RemoteServer().LogsyncHTTPHandler()
+		refsHandler := s.Instance.RemoteServer().RefsHTTPHandler()
+		if cfg.API.ReadOnly {
+			// read-only remotes can be pulled from, but not pushed to
+			dsyncHandler = readOnlyMethods(dsyncHandler)
+			logsyncHandler = readOnlyMethods(logsyncHandler)
+			refsHandler = readOnlyMethods(refsHandler)
+		}
+		m.Handle(qhttp.AERemoteDSync.String(), s.Middleware(dsyncHandler))
+		m.Handle(qhttp.AERemoteLogSync.String(), s.Middleware(logsyncHandler))
+		m.Handle(qhttp.AERemoteRefs.String(), s.Middleware(refsHandler))
 	}
 
 	return m
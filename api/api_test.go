@@ -12,6 +12,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	golog "github.com/ipfs/go-log"
 	ma "github.com/multiformats/go-multiaddr"
 	manet "github.com/multiformats/go-multiaddr/net"
+	"github.com/qri-io/dataset"
 	apispec "github.com/qri-io/qri/api/spec"
 	"github.com/qri-io/qri/automation"
 	"github.com/qri-io/qri/base/dsfs"
@@ -27,6 +29,7 @@ import (
 	testcfg "github.com/qri-io/qri/config/test"
 	"github.com/qri-io/qri/event"
 	"github.com/qri-io/qri/lib"
+	qhttp "github.com/qri-io/qri/lib/http"
 	"github.com/qri-io/qri/logbook"
 	"github.com/qri-io/qri/p2p"
 	"github.com/qri-io/qri/repo"
@@ -316,3 +319,93 @@ func NewFilesRequest(method, endpoint, url string, filePaths, params map[string]
 
 	return req, nil
 }
+
+func TestReadOnlyMethods(t *testing.T) {
+	h := readOnlyMethods(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	cases := []struct {
+		method     string
+		expectCode int
+	}{
+		{http.MethodGet, http.StatusOK},
+		{http.MethodHead, http.StatusOK},
+		{http.MethodOptions, http.StatusOK},
+		{http.MethodPost, http.StatusForbidden},
+		{http.MethodPut, http.StatusForbidden},
+		{http.MethodPatch, http.StatusForbidden},
+		{http.MethodDelete, http.StatusForbidden},
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest(c.method, "/remote/dsync", nil))
+		if w.Code != c.expectCode {
+			t.Errorf("%s status code mismatch. want: %d, got: %d", c.method, c.expectCode, w.Code)
+		}
+	}
+}
+
+func TestReadOnlyServer(t *testing.T) {
+	run := NewAPITestRunner(t)
+	defer run.Delete()
+
+	ds := dataset.Dataset{
+		Name: "test_ds",
+		Meta: &dataset.Meta{
+			Title: "title zero",
+		},
+	}
+	run.SaveDataset(&ds, "testdata/cities/data.csv")
+	// a second version to diff against
+	ds.Meta = &dataset.Meta{Title: "title one"}
+	run.SaveDataset(&ds, "testdata/cities/data.csv")
+
+	run.Inst.GetConfig().API.ReadOnly = true
+	ts := run.MustTestServer(t)
+	defer ts.Close()
+
+	cases := []struct {
+		endpoint   qhttp.APIEndpoint
+		body       string
+		expectCode int
+	}{
+		{qhttp.AEGet, `{"ref":"peer/test_ds"}`, http.StatusOK},
+		{qhttp.AEList, `{}`, http.StatusOK},
+		{qhttp.AEActivity, `{"ref":"peer/test_ds"}`, http.StatusOK},
+		{qhttp.AEDiff, `{"leftPath":"peer/test_ds","UseLeftPrevVersion":true}`, http.StatusOK},
+		{qhttp.AESave, `{"ref":"peer/test_ds","dataset":{"meta":{"title":"title two"}}}`, http.StatusForbidden},
+		{qhttp.AERemove, `{"ref":"peer/test_ds"}`, http.StatusForbidden},
+		{qhttp.AERename, `{"current":"peer/test_ds","next":"peer/renamed"}`, http.StatusForbidden},
+		{qhttp.AEPush, `{"ref":"peer/test_ds"}`, http.StatusForbidden},
+		{AESaveByUpload, ``, http.StatusForbidden},
+	}
+
+	for _, c := range cases {
+		t.Run(c.endpoint.String(), func(t *testing.T) {
+			res, err := http.Post(ts.URL+c.endpoint.String(), "application/json", strings.NewReader(c.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+			data, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != c.expectCode {
+				t.Fatalf("status code mismatch. want: %d, got: %d. body: %s", c.expectCode, res.StatusCode, string(data))
+			}
+			if c.expectCode == http.StatusForbidden && !strings.Contains(string(data), lib.ErrReadOnly.Error()) {
+				t.Errorf("expected read-only error message, got: %s", string(data))
+			}
+		})
+	}
+
+	// the dataset is unchanged
+	got, err := run.Inst.Dataset().Get(run.Ctx, &lib.GetParams{Ref: "peer/test_ds"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title := got.Value.(*dataset.Dataset).Meta.Title; title != "title one" {
+		t.Errorf("expected read-only server to leave the dataset unchanged, got title %q", title)
+	}
+}
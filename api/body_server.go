@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/api/util"
+	"github.com/qri-io/qri/lib"
+)
+
+// BodyServer is a small HTTP server that exposes only the body of a single
+// dataset. It's intended for quick sharing of data with tools that can read
+// from a URL, and is distinct from the full JSON API served by Server
+type BodyServer struct {
+	inst *lib.Instance
+	ref  string
+}
+
+// NewBodyServer creates a body server for the given dataset reference
+func NewBodyServer(inst *lib.Instance, ref string) BodyServer {
+	return BodyServer{inst: inst, ref: ref}
+}
+
+// Serve listens on the given address & serves the dataset body until the
+// passed-in context is cancelled. It will block while the server is running
+func (s BodyServer) Serve(ctx context.Context, addr string) error {
+	server := &http.Server{
+		Addr:    addr,
+		Handler: s.Handler(),
+	}
+
+	go func() {
+		<-ctx.Done()
+		log.Info("shutting down body server")
+		server.Close()
+	}()
+
+	return server.ListenAndServe()
+}
+
+// Handler returns the http handler that responds with the dataset body
+func (s BodyServer) Handler() http.HandlerFunc {
+	return BodyHandler(s.inst, s.ref)
+}
+
+// BodyHandler responds to GET & HEAD requests with the entire body of the
+// dataset at ref. The response format is determined by content negotiation:
+// "Accept: text/csv" or a "format=csv" query param returns csv, all other
+// requests are answered with json. Bodies are streamed, and range requests
+// are supported
+// Examples:
+// curl http://localhost:8080/
+// curl -H "Accept: text/csv" http://localhost:8080/
+// curl -H "Range: bytes=0-99" http://localhost:8080/?format=csv
+func BodyHandler(inst *lib.Instance, ref string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			util.NotFoundHandler(w, r)
+			return
+		}
+
+		format, err := negotiateBodyFormat(r)
+		if err != nil {
+			util.WriteErrResponse(w, http.StatusNotAcceptable, err)
+			return
+		}
+		df, err := dataset.ParseDataFormatString(format)
+		if err != nil {
+			util.WriteErrResponse(w, http.StatusNotAcceptable, err)
+			return
+		}
+
+		body, err := inst.OpenBodyStream(r.Context(), ref, df)
+		if err != nil {
+			util.RespondWithError(w, err)
+			return
+		}
+		defer body.Close()
+
+		if r.Method == http.MethodGet {
+			publishDownloadEvent(r.Context(), inst, ref)
+		}
+		w.Header().Set("Content-Type", extensionToMimeType("."+format))
+		// ServeContent handles Range & HEAD requests for us
+		http.ServeContent(w, r, "body."+format, time.Time{}, body)
+	}
+}
+
+// negotiateBodyFormat picks a body format from a request, preferring an
+// explicit format query param over the Accept header
+func negotiateBodyFormat(r *http.Request) (string, error) {
+	switch format := r.FormValue("format"); format {
+	case "csv", "json":
+		return format, nil
+	case "":
+	default:
+		return "", fmt.Errorf("unsupported body format %q, must be one of 'csv' or 'json'", format)
+	}
+
+	accept := r.Header["Accept"]
+	switch {
+	case arrayContains(accept, "text/csv"):
+		return "csv", nil
+	default:
+		return "json", nil
+	}
+}
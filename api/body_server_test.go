@@ -0,0 +1,78 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+)
+
+func TestBodyHandler(t *testing.T) {
+	run := NewAPITestRunner(t)
+	defer run.Delete()
+
+	ds := dataset.Dataset{
+		Name: "test_ds",
+		Meta: &dataset.Meta{
+			Title: "title one",
+		},
+	}
+	run.SaveDataset(&ds, "testdata/cities/data.csv")
+
+	h := NewBodyServer(run.Inst, "peer/test_ds").Handler()
+
+	expectCSV := "city,pop,avg_age,in_usa\ntoronto,40000000,55.5,false\nnew york,8500000,44.4,true\nchicago,300000,44.4,true\nchatham,35000,65.25,true\nraleigh,250000,50.65,true\n"
+	expectJSON := `[["toronto",40000000,55.5,false],["new york",8500000,44.4,true],["chicago",300000,44.4,true],["chatham",35000,65.25,true],["raleigh",250000,50.65,true]]`
+
+	cases := []struct {
+		description string
+		method      string
+		url         string
+		headers     map[string]string
+		expectCode  int
+		expectType  string
+		expectBody  string
+	}{
+		{"default json", http.MethodGet, "/", nil, 200, "application/json", expectJSON},
+		{"accept csv", http.MethodGet, "/", map[string]string{"Accept": "text/csv"}, 200, "text/csv", expectCSV},
+		{"format param csv", http.MethodGet, "/?format=csv", nil, 200, "text/csv", expectCSV},
+		{"format param overrides accept", http.MethodGet, "/?format=json", map[string]string{"Accept": "text/csv"}, 200, "application/json", expectJSON},
+		{"csv range", http.MethodGet, "/?format=csv", map[string]string{"Range": "bytes=0-3"}, 206, "text/csv", "city"},
+		{"json range", http.MethodGet, "/", map[string]string{"Range": "bytes=12-19"}, 206, "application/json", "40000000"},
+		{"head", http.MethodHead, "/?format=csv", nil, 200, "text/csv", ""},
+		{"bad format", http.MethodGet, "/?format=xlsx", nil, 406, "", ""},
+		{"bad method", http.MethodPost, "/", nil, 404, "", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			req := httptest.NewRequest(c.method, c.url, nil)
+			for k, v := range c.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			h(w, req)
+			res := w.Result()
+
+			if res.StatusCode != c.expectCode {
+				t.Fatalf("status code mismatch. want: %d, got: %d", c.expectCode, res.StatusCode)
+			}
+			if c.expectType == "" {
+				return
+			}
+			if got := res.Header.Get("Content-Type"); got != c.expectType {
+				t.Errorf("content type mismatch. want: %q, got: %q", c.expectType, got)
+			}
+			data, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(c.expectBody, string(data)); diff != "" {
+				t.Errorf("body mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -22,6 +22,9 @@ const (
 	AEGetCSVFullRef qhttp.APIEndpoint = "/ds/get/{username}/{name}/at/{fs}/{hash}/body.csv"
 	// AEGetCSVShortRef is the route used to get a body as a csv
 	AEGetCSVShortRef qhttp.APIEndpoint = "/ds/get/{username}/{name}/body.csv"
+	// AEActivityFeed is the route used to get dataset version history as an
+	// Atom feed
+	AEActivityFeed qhttp.APIEndpoint = "/ds/activity/{username}/{name}/feed.atom"
 	// AEUnpack unpacks a zip file and sends it back
 	AEUnpack qhttp.APIEndpoint = "/ds/unpack"
 	// AESaveByUpload is the route used to save a dataset using a multipart form file in the request
@@ -85,6 +85,51 @@ func GetHandler(inst *lib.Instance, routePrefix string) http.HandlerFunc {
 			writeFileResponse(w, outBytes, "body.csv", "csv")
 			return
 
+		case format == "html":
+			// Example:
+			// curl http://localhost:2503/ds/get/b5/world_bank_population/body?format=html
+			if p.Selector != "body" {
+				util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("can only get html of the body component, selector must be 'body'"))
+				return
+			}
+			outBytes, err := inst.Dataset().GetHTML(r.Context(), p)
+			if err != nil {
+				util.RespondWithError(w, err)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write(outBytes)
+			return
+
+		case format == "ndjson":
+			// Example:
+			// curl http://localhost:2503/ds/get/b5/world_bank_population/body?format=ndjson
+			if p.Selector != "body" {
+				util.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("can only get ndjson of the body component, selector must be 'body'"))
+				return
+			}
+			p.Format = format
+			res, err := inst.Dataset().Get(r.Context(), p)
+			if err != nil {
+				util.RespondWithError(w, err)
+				return
+			}
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Write(res.Bytes)
+			return
+
+		case format == "dcat":
+			// Example:
+			// curl http://localhost:2503/ds/get/b5/world_bank_population/meta?format=dcat
+			outBytes, err := inst.Dataset().GetDCAT(r.Context(), p)
+			if err != nil {
+				util.RespondWithError(w, err)
+				return
+			}
+			w.Header().Set("Content-Type", "application/ld+json")
+			w.Write(outBytes)
+			return
+
 		case format == "zip", arrayContains(r.Header["Accept"], "application/zip"):
 			// Examples:
 			// curl -H "Accept: application/zip" http://localhost:2503/ds/get/world_bank_population
@@ -141,6 +186,36 @@ func validateZipRequest(r *http.Request, p *lib.GetParams) error {
 	return nil
 }
 
+// ActivityFeedHandler is a handler for returning dataset version history as
+// an Atom feed
+// Example:
+// curl http://localhost:2503/ds/activity/b5/world_bank_population/feed.atom
+func ActivityFeedHandler(inst *lib.Instance) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			util.NotFoundHandler(w, r)
+			return
+		}
+
+		p := &lib.ActivityParams{Ref: r.FormValue("ref")}
+		p.Limit = util.ReqParamInt(r, "limit", 0)
+		p.Offset = util.ReqParamInt(r, "offset", 0)
+		p.SetNonZeroDefaults()
+		if err := p.Validate(); err != nil {
+			util.WriteErrResponse(w, http.StatusBadRequest, err)
+			return
+		}
+
+		outBytes, err := inst.Dataset().ActivityFeed(r.Context(), p)
+		if err != nil {
+			util.RespondWithError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		w.Write(outBytes)
+	}
+}
+
 // UnpackHandler unpacks a zip file and sends it back as json
 func UnpackHandler(routePrefix string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -80,6 +80,59 @@ func TestGetBodyCSVHandler(t *testing.T) {
 	assertStatusCode(t, "get body.csv with incorrect http method", actualStatusCode, 400)
 }
 
+func TestGetBodyNDJSONHandler(t *testing.T) {
+	run := NewAPITestRunner(t)
+	defer run.Delete()
+
+	ds := dataset.Dataset{
+		Name: "test_ds",
+		Meta: &dataset.Meta{
+			Title: "title one",
+		},
+	}
+	run.SaveDataset(&ds, "testdata/cities/data.csv")
+
+	actualStatusCode, actualBody := APICall("/get/peer/test_ds/body?format=ndjson&limit=2&offset=1", GetHandler(run.Inst, ""), map[string]string{"username": "peer", "name": "test_ds", "selector": "body"})
+	assertStatusCode(t, "get ndjson body", actualStatusCode, 200)
+	expectBody := "[\"new york\",8500000,44.4,true]\n[\"chicago\",300000,44.4,true]\n"
+	if diff := cmp.Diff(expectBody, actualBody); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	actualStatusCode, _ = APICall("/get/peer/test_ds/meta?format=ndjson", GetHandler(run.Inst, ""), map[string]string{"username": "peer", "name": "test_ds", "selector": "meta"})
+	assertStatusCode(t, "get ndjson of a non-body component", actualStatusCode, 400)
+}
+
+func TestActivityFeedHandler(t *testing.T) {
+	run := NewAPITestRunner(t)
+	defer run.Delete()
+
+	ds := dataset.Dataset{
+		Name: "test_ds",
+		Meta: &dataset.Meta{
+			Title:       "title one",
+			Description: "cities of the world",
+		},
+	}
+	run.SaveDataset(&ds, "testdata/cities/data.csv")
+
+	actualStatusCode, actualBody := APICall("/ds/activity/peer/test_ds/feed.atom", ActivityFeedHandler(run.Inst), map[string]string{"username": "peer", "name": "test_ds"})
+	assertStatusCode(t, "get activity feed", actualStatusCode, 200)
+	for _, expect := range []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom">`,
+		"<title>title one</title>",
+		"<subtitle>cities of the world</subtitle>",
+		"<entry>",
+	} {
+		if !strings.Contains(actualBody, expect) {
+			t.Errorf("expected feed to contain %q, got:\n%s", expect, actualBody)
+		}
+	}
+
+	actualStatusCode, _ = APICallWithParams("POST", "/ds/activity/peer/test_ds/feed.atom", nil, ActivityFeedHandler(run.Inst), nil)
+	assertStatusCode(t, "get activity feed with incorrect http method", actualStatusCode, 404)
+}
+
 func TestDatasetGet(t *testing.T) {
 	run := NewAPITestRunner(t)
 	defer run.Delete()
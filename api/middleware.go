@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/qri-io/qri/api/util"
 	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/lib"
 )
 
 // Middleware handles request logging
@@ -30,6 +31,19 @@ func (s Server) mwFunc(handler http.HandlerFunc, shouldLog bool) http.HandlerFun
 	}
 }
 
+// readOnlyMethods only passes requests that read data to next, rejecting
+// requests with methods that write, like PUT, POST, PATCH & DELETE
+func readOnlyMethods(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+		default:
+			lib.ReadOnlyHandler(w, r)
+		}
+	}
+}
+
 // corsMiddleware adds Cross-Origin Resource Sharing headers for any request
 // who's origin matches one of allowedOrigins
 func corsMiddleware(allowedOrigins []string) mux.MiddlewareFunc {
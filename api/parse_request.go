@@ -32,6 +32,17 @@ func parseGetParamsFromRequest(r *http.Request, p *lib.GetParams) error {
 	}
 
 	p.Selector = r.FormValue("selector")
+	if cols := r.FormValue("columns"); cols != "" {
+		for _, col := range strings.Split(cols, ",") {
+			if col = strings.TrimSpace(col); col != "" {
+				p.Columns = append(p.Columns, col)
+			}
+		}
+	}
+	p.CSVNulls = util.ReqParamBool(r, "csvNulls", false)
+	p.NullToken = r.FormValue("nullToken")
+	p.CSVBOM = util.ReqParamBool(r, "csvBOM", false)
+	p.CSVCRLF = util.ReqParamBool(r, "csvCRLF", false)
 
 	p.All = util.ReqParamBool(r, "all", true)
 	p.Limit = util.ReqParamInt(r, "limit", 0)
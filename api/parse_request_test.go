@@ -74,6 +74,17 @@ func TestParseGetParamsFromRequest(t *testing.T) {
 			},
 			map[string]string{"ref": "peer/my_ds", "selector": "body", "all": "true"},
 		},
+		{
+			"get request with columns",
+			"/get/peer/my_ds/body",
+			&lib.GetParams{
+				Ref:      "peer/my_ds",
+				Selector: "body",
+				All:      true,
+				Columns:  []string{"title", "duration"},
+			},
+			map[string]string{"ref": "peer/my_ds", "selector": "body", "columns": "title, duration"},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.description, func(t *testing.T) {
@@ -9,6 +9,7 @@ import (
 
 	golog "github.com/ipfs/go-log"
 	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/dsref"
 	"github.com/qri-io/qri/repo"
 )
@@ -49,6 +50,10 @@ func RespondWithError(w http.ResponseWriter, err error) {
 		WriteErrResponse(w, http.StatusBadRequest, err)
 		return
 	}
+	if errors.Is(err, base.ErrBodyRowOutOfRange) {
+		WriteErrResponse(w, http.StatusBadRequest, err)
+		return
+	}
 	var perr *dsref.ParseError
 	if errors.As(err, &perr) {
 		WriteErrResponse(w, http.StatusBadRequest, err)
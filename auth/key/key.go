@@ -16,6 +16,16 @@ var log = logger.Logger("key")
 // strictly a peerID.
 type ID = peer.ID
 
+// Signer produces a cryptographic signature of data. Signing through a Signer
+// instead of a private key lets keys held outside the process, like in a
+// hardware security module or a remote signing service, sign qri data. A
+// crypto.PrivKey is a Signer
+type Signer interface {
+	Sign(data []byte) ([]byte, error)
+}
+
+var _ Signer = (crypto.PrivKey)(nil)
+
 // DecodeID parses an ID string
 func DecodeID(s string) (ID, error) {
 	pid, err := peer.Decode(s)
@@ -2,6 +2,7 @@ package automation
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -29,6 +30,10 @@ var NowFunc = func() *time.Time {
 	return &now
 }
 
+// ApplyWorkflowID is the workflow ID runs of ephemeral applies are stored
+// under. Applied workflows aren't saved, so they have no ID of their own
+const ApplyWorkflowID = workflow.ID("apply")
+
 // OrchestratorOptions encapsulate runtime configuration for NewOrchestrator
 type OrchestratorOptions struct {
 	WorkflowStore workflow.Store
@@ -49,6 +54,10 @@ type WorkflowRunParams struct {
 	Secrets      map[string]string
 	OutputWidth  int
 	OutputHeight int
+	// json-encoded state checkpointed by a previous run to resume from
+	ResumeState json.RawMessage
+	// Trigger records what started the run, eg: run.TriggerScheduled
+	Trigger string
 }
 
 // Orchestrator manages automation in qri
@@ -292,7 +301,7 @@ func (o *Orchestrator) handleTrigger(ctx context.Context, e event.Event) error {
 				log.Debugw("handleTrigger: error saving workflow", "id", wtp.WorkflowID, "err", err)
 			}
 			runID := run.NewID()
-			runFunc := o.runWorkflowFactory(wf, runID)
+			runFunc := o.runWorkflowFactory(wf, runID, WorkflowRunParams{Trigger: run.TriggerScheduled})
 			if err := o.runQueue.Push(ctx, wf.OwnerID.Encode(), runID, "run", runFunc); err != nil {
 
 				log.Debugw("handleTrigger: error queuing workflow", "err", err)
@@ -302,9 +311,9 @@ func (o *Orchestrator) handleTrigger(ctx context.Context, e event.Event) error {
 	return nil
 }
 
-func (o *Orchestrator) runWorkflowFactory(wf *workflow.Workflow, runID string) runQueueFunc {
+func (o *Orchestrator) runWorkflowFactory(wf *workflow.Workflow, runID string, params WorkflowRunParams) runQueueFunc {
 	return func(ctx context.Context) error {
-		return o.runWorkflow(ctx, wf, runID)
+		return o.runWorkflow(ctx, wf, runID, params)
 	}
 }
 
@@ -318,11 +327,43 @@ func (o *Orchestrator) RunWorkflow(ctx context.Context, wid workflow.ID, runID s
 		return "", err
 	}
 
-	runFunc := o.runWorkflowFactory(wf, runID)
+	runFunc := o.runWorkflowFactory(wf, runID, WorkflowRunParams{Trigger: run.TriggerManual})
+	return runID, o.runQueue.Push(ctx, wf.OwnerID.Encode(), runID, "run", runFunc)
+}
+
+// ResumeWorkflow runs a workflow, giving the transform the state the run with
+// ID resumeRunID last checkpointed
+func (o *Orchestrator) ResumeWorkflow(ctx context.Context, wid workflow.ID, runID, resumeRunID string) (string, error) {
+	if runID == "" {
+		runID = run.NewID()
+	}
+	wf, err := o.GetWorkflow(ctx, wid)
+	if err != nil {
+		return "", err
+	}
+	state, err := o.ResumeState(ctx, resumeRunID)
+	if err != nil {
+		return "", err
+	}
+
+	runFunc := o.runWorkflowFactory(wf, runID, WorkflowRunParams{Trigger: run.TriggerManual, ResumeState: state})
 	return runID, o.runQueue.Push(ctx, wf.OwnerID.Encode(), runID, "run", runFunc)
 }
 
-func (o *Orchestrator) runWorkflow(ctx context.Context, wf *workflow.Workflow, runID string) error {
+// ResumeState fetches the state a run last checkpointed. It's an error if the
+// run didn't checkpoint
+func (o *Orchestrator) ResumeState(ctx context.Context, runID string) (json.RawMessage, error) {
+	rs, err := o.RunInfo(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("resuming run %q: %w", runID, err)
+	}
+	if rs.Checkpoint == nil {
+		return nil, fmt.Errorf("run %q has no checkpoint to resume from", runID)
+	}
+	return rs.Checkpoint, nil
+}
+
+func (o *Orchestrator) runWorkflow(ctx context.Context, wf *workflow.Workflow, runID string, params WorkflowRunParams) error {
 	wid := wf.ID
 	log.Debugw("runWorkflow, workflow", "id", wid)
 
@@ -338,22 +379,15 @@ func (o *Orchestrator) runWorkflow(ctx context.Context, wf *workflow.Workflow, r
 	}(wf)
 
 	if o.runs != nil {
-		r := &run.State{ID: runID, WorkflowID: wid}
-		if _, err := o.runs.Create(ctx, r); err != nil {
+		if err := o.createRun(ctx, runID, wid, params.Trigger); err != nil {
 			return err
 		}
-
-		handler := runEventsHandler(o.runs)
-		o.bus.SubscribeID(handler, runID)
-		// TODO (b5): event bus needs an unsubscribe mechanism
-		// defer o.bus.UnsubscribeID(runID)
 	}
 
 	// need to replace w/ log collector
 	streams := ioes.NewDiscardIOStreams()
 
-	// TODO(dustmop): Retrieve params from enqueued run, pass them into RunAndCommit
-	err := o.runner.RunAndCommit(ctx, runID, wf, streams, WorkflowRunParams{})
+	err := o.runner.RunAndCommit(ctx, runID, wf, streams, params)
 	go func(wf *workflow.Workflow) {
 		runStatus := run.RSFailed
 		if err == nil {
@@ -410,11 +444,36 @@ func (o *Orchestrator) applyWorkflow(ctx context.Context, scriptOutput io.Writer
 		// TODO (ramfox): defer unsubscribe to id
 	}
 
+	// record the run, so the state it checkpoints can be resumed from
+	if o.runs != nil {
+		wid := wf.ID
+		if wid == "" {
+			wid = ApplyWorkflowID
+		}
+		if err := o.createRun(ctx, runID, wid, params.Trigger); err != nil {
+			return err
+		}
+	}
+
 	// TODO (ramfox): when we understand what it means to dryrun a hook, this should wait for the err, iterator thought the hooks
 	// for this workflow, and emit the events for hooks that this orchestrator understands
 	return o.runner.RunEphemeral(ctx, runID, wf, ds, true, params)
 }
 
+// createRun stores a new run state, subscribing it to the run's events
+func (o *Orchestrator) createRun(ctx context.Context, runID string, wid workflow.ID, trigger string) error {
+	r := &run.State{ID: runID, WorkflowID: wid, Trigger: trigger}
+	if _, err := o.runs.Create(ctx, r); err != nil {
+		return err
+	}
+
+	handler := runEventsHandler(o.runs)
+	o.bus.SubscribeID(handler, runID)
+	// TODO (b5): event bus needs an unsubscribe mechanism
+	// defer o.bus.UnsubscribeID(runID)
+	return nil
+}
+
 // CancelRun cancels the run of the given runID
 func (o *Orchestrator) CancelRun(ctx context.Context, runID string) {
 	log.Debugw("orchestrator.CancelRun", "runID", runID)
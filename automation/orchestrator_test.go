@@ -2,6 +2,8 @@ package automation
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -316,6 +318,7 @@ func TestRunStoreEvents(t *testing.T) {
 		ID:         runID,
 		WorkflowID: wf.ID,
 		Status:     run.RSRunning,
+		Trigger:    run.TriggerManual,
 	}
 
 	// event 1
@@ -429,6 +432,73 @@ func TestRunStoreEvents(t *testing.T) {
 	<-transformStopped
 }
 
+func TestResumeWorkflow(t *testing.T) {
+	ctx := context.Background()
+	bus := event.NewBus(ctx)
+	runStore := run.NewMemStore()
+	workflowStore := workflow.NewMemStore()
+	wf, err := workflowStore.Put(ctx, &workflow.Workflow{
+		InitID:  "dataset_id",
+		OwnerID: "owner_id",
+		Created: &time.Time{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &checkpointRunner{bus: bus, done: make(chan json.RawMessage)}
+	o, err := NewOrchestrator(ctx, bus, runner, OrchestratorOptions{
+		WorkflowStore: workflowStore,
+		RunStore:      runStore,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer o.Stop()
+
+	// the first run checkpoints, then fails
+	firstRunID, err := o.RunWorkflow(ctx, wf.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state := <-runner.done; state != nil {
+		t.Errorf("expected first run not to resume, got state: %s", state)
+	}
+	if _, err := o.ResumeState(ctx, firstRunID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := o.ResumeWorkflow(ctx, wf.ID, "", firstRunID); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(`{"page":1}`, string(<-runner.done)); diff != "" {
+		t.Errorf("resume state mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := o.ResumeWorkflow(ctx, wf.ID, "", "unknown"); err == nil {
+		t.Error("expected resuming an unknown run to error")
+	}
+}
+
+// a workflow runner that checkpoints & fails when it isn't resuming
+type checkpointRunner struct {
+	bus  event.Bus
+	done chan json.RawMessage
+}
+
+func (r *checkpointRunner) RunAndCommit(ctx context.Context, runID string, wf *workflow.Workflow, streams ioes.IOStreams, params WorkflowRunParams) error {
+	defer func() { r.done <- params.ResumeState }()
+	if params.ResumeState != nil {
+		return nil
+	}
+	r.bus.PublishID(ctx, event.ETTransformCheckpoint, runID, event.TransformCheckpoint{State: json.RawMessage(`{"page":1}`)})
+	return fmt.Errorf("interrupted")
+}
+
+func (r *checkpointRunner) RunEphemeral(ctx context.Context, runID string, wf *workflow.Workflow, ds *dataset.Dataset, wait bool, params WorkflowRunParams) error {
+	return nil
+}
+
 func confirmStoredRun(ctx context.Context, t *testing.T, s run.Store, expect *run.State) {
 	t.Helper()
 	got, err := s.Get(ctx, expect.ID)
@@ -63,6 +63,15 @@ const (
 	RSSkipped = Status("skipped")
 )
 
+const (
+	// TriggerManual marks a run started by a person, eg: `qri save --apply`
+	TriggerManual = "manual"
+	// TriggerScheduled marks a run started by a workflow trigger
+	TriggerScheduled = "scheduled"
+	// TriggerWebhook marks a run started by an incoming webhook request
+	TriggerWebhook = "webhook"
+)
+
 // State is a passable, cachable data structure that describes the execution of
 // a transform. State structs can act as a sink of transform events, collapsing
 // the state transition of multiple transform events into a single structure
@@ -76,6 +85,12 @@ type State struct {
 	StopTime   *time.Time   `json:"stopTime"`
 	Duration   int64        `json:"duration"`
 	Steps      []*StepState `json:"steps"`
+	// Trigger describes what started the run, usually one of TriggerManual,
+	// TriggerScheduled or TriggerWebhook
+	Trigger string `json:"trigger,omitempty"`
+	// Checkpoint is the most recent json-encoded state the transform script
+	// checkpointed, if any. Subsequent runs can resume from this state
+	Checkpoint json.RawMessage `json:"checkpoint,omitempty"`
 }
 
 // NewState returns a new *State with the given runID
@@ -109,6 +124,8 @@ func (rs *State) Copy() *State {
 		StopTime:   rs.StopTime,
 		Duration:   rs.Duration,
 		Steps:      rs.Steps,
+		Trigger:    rs.Trigger,
+		Checkpoint: rs.Checkpoint,
 	}
 	return run
 }
@@ -170,6 +187,11 @@ func (rs *State) AddTransformEvent(e event.Event) error {
 		event.ETTransformError,
 		event.ETTransformDatasetPreview:
 		return rs.appendStepOutputLog(e)
+	case event.ETTransformCheckpoint:
+		if cp, ok := e.Payload.(event.TransformCheckpoint); ok {
+			rs.Checkpoint = cp.State
+		}
+		return nil
 	case event.ETTransformCanceled:
 		return nil
 	}
@@ -43,6 +43,32 @@ func TestStateAddTransformEvent(t *testing.T) {
 	}
 }
 
+func TestStateAddCheckpointEvent(t *testing.T) {
+	runID := NewID()
+	rs := NewState(runID)
+	for _, state := range []string{`{"page":1}`, `{"page":2}`} {
+		e := event.Event{Type: event.ETTransformCheckpoint, SessionID: runID, Payload: event.TransformCheckpoint{State: json.RawMessage(state)}}
+		if err := rs.AddTransformEvent(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if diff := cmp.Diff(`{"page":2}`, string(rs.Checkpoint)); diff != "" {
+		t.Errorf("expected latest checkpoint to be retained (-want +got):\n%s", diff)
+	}
+
+	data, err := json.Marshal(rs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &State{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(rs, got); diff != "" {
+		t.Errorf("checkpoint round trip mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func getStates(runID string) []struct {
 	e event.Event
 	r *State
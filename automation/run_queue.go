@@ -75,11 +75,14 @@ func (r *runQueue) listenForCancelations(ctx context.Context) {
 		select {
 		case cancelRunID := <-r.cancelCh:
 			r.clk.Lock()
-			if _, ok := r.cancels[cancelRunID]; !ok {
+			cancel, ok := r.cancels[cancelRunID]
+			r.clk.Unlock()
+			if !ok {
+				// a run that hasn't started yet is dropped from the queue
+				r.removeQueued(cancelRunID)
 				continue
 			}
-			r.cancels[cancelRunID]()
-			r.clk.Unlock()
+			cancel()
 		case <-ctx.Done():
 			return
 		}
@@ -98,6 +101,17 @@ func (r *runQueue) removeRunCancel(runID string) {
 	delete(r.cancels, runID)
 }
 
+func (r *runQueue) removeQueued(runID string) {
+	r.qlk.Lock()
+	defer r.qlk.Unlock()
+	for i, info := range r.queue {
+		if info.runID == runID {
+			r.queue = append(r.queue[:i], r.queue[i+1:]...)
+			return
+		}
+	}
+}
+
 func (r *runQueue) pollQueue(ctx context.Context, interval time.Duration) {
 	for {
 		select {
@@ -87,3 +87,39 @@ func TestRunQueueCancel(t *testing.T) {
 		t.Errorf(gotMsg)
 	}
 }
+
+func TestRunQueueCancelQueued(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rq := NewRunQueue(ctx, event.NilBus, 50*time.Millisecond, 1)
+	ran := make(chan string, 2)
+	f := func(runID string) runQueueFunc {
+		return func(ctx context.Context) error {
+			ran <- runID
+			return nil
+		}
+	}
+
+	// canceling before the queue polls drops the run from the queue
+	if err := rq.Push(ctx, "owner", "first", "apply", f("first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := rq.Push(ctx, "owner", "second", "apply", f("second")); err != nil {
+		t.Fatal(err)
+	}
+	rq.Cancel("first")
+
+	select {
+	case got := <-ran:
+		if got != "second" {
+			t.Errorf("expected canceled run not to run, got %q", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for queued run")
+	}
+	select {
+	case got := <-ran:
+		t.Errorf("expected canceled run not to run, got %q", got)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
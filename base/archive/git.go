@@ -0,0 +1,195 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+// WriteGitFastImport writes the history of a dataset as a git fast-import
+// stream, one git commit per version on the named branch. next returns
+// versions oldest first, opened so their body & script files can be read,
+// and io.EOF after the last version. Versions are loaded & written one at a
+// time, so only one version is held in memory. Each commit holds the full set of component files for its version: body,
+// meta, structure, readme, transform & viz. Commit titles, messages,
+// timestamps and authors carry over, and the version path is recorded in a
+// "Qri-Path" trailer, so the stream can be piped to "git fast-import"
+func WriteGitFastImport(w io.Writer, branch string, next func() (*dataset.Dataset, error)) error {
+	if branch == "" {
+		branch = "main"
+	}
+	for i := 0; ; i++ {
+		ds, err := next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		files, err := gitVersionFiles(ds)
+		if err != nil {
+			return fmt.Errorf("version %s: %w", ds.Path, err)
+		}
+
+		ident := gitIdent(ds)
+		fmt.Fprintf(w, "commit refs/heads/%s\n", branch)
+		fmt.Fprintf(w, "mark :%d\n", i+1)
+		fmt.Fprintf(w, "author %s\n", ident)
+		fmt.Fprintf(w, "committer %s\n", ident)
+		writeGitData(w, []byte(gitCommitMessage(ds)))
+		if i > 0 {
+			fmt.Fprintf(w, "from :%d\n", i)
+		}
+		io.WriteString(w, "deleteall\n")
+		for _, f := range files {
+			fmt.Fprintf(w, "M 644 inline %s\n", f.name)
+			writeGitData(w, f.data)
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+}
+
+// VersionList iterates a list of versions ordered oldest first, for passing
+// versions that are already loaded to WriteGitFastImport
+func VersionList(versions []*dataset.Dataset) func() (*dataset.Dataset, error) {
+	i := 0
+	return func() (*dataset.Dataset, error) {
+		if i >= len(versions) {
+			return nil, io.EOF
+		}
+		i++
+		return versions[i-1], nil
+	}
+}
+
+type gitFile struct {
+	name string
+	data []byte
+}
+
+// gitVersionFiles lists the component files of a dataset version
+func gitVersionFiles(ds *dataset.Dataset) ([]gitFile, error) {
+	files := []gitFile{}
+	if ds.BodyFile() != nil {
+		data, err := readAndReplace(ds.BodyFile(), ds.SetBodyFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading body: %w", err)
+		}
+		name := "body"
+		if ds.Structure != nil && ds.Structure.Format != "" {
+			name = "body." + ds.Structure.Format
+		}
+		files = append(files, gitFile{name, data})
+	}
+	if ds.Meta != nil {
+		md := &dataset.Meta{}
+		md.Assign(ds.Meta)
+		md.Path = ""
+		data, err := json.MarshalIndent(md, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, gitFile{"meta.json", append(data, '\n')})
+	}
+	if ds.Readme != nil && ds.Readme.ScriptFile() != nil {
+		data, err := readAndReplace(ds.Readme.ScriptFile(), ds.Readme.SetScriptFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading readme: %w", err)
+		}
+		files = append(files, gitFile{"readme.md", data})
+	}
+	if ds.Structure != nil {
+		st := &dataset.Structure{}
+		st.Assign(ds.Structure)
+		st.Path = ""
+		data, err := json.MarshalIndent(st, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, gitFile{"structure.json", append(data, '\n')})
+	}
+	if ds.Transform != nil && ds.Transform.ScriptFile() != nil {
+		data, err := readAndReplace(ds.Transform.ScriptFile(), ds.Transform.SetScriptFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading transform: %w", err)
+		}
+		files = append(files, gitFile{"transform.star", data})
+	}
+	if ds.Viz != nil && ds.Viz.ScriptFile() != nil {
+		data, err := readAndReplace(ds.Viz.ScriptFile(), ds.Viz.SetScriptFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading viz: %w", err)
+		}
+		files = append(files, gitFile{"template.html", data})
+	}
+	return files, nil
+}
+
+// readAndReplace reads a file, replacing it with an in-memory copy so it can
+// be read again
+func readAndReplace(f qfs.File, set func(qfs.File)) ([]byte, error) {
+	defer f.Close()
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	set(qfs.NewMemfileBytes(f.FileName(), data))
+	return data, nil
+}
+
+// gitIdent formats the author of a version as a git identity with a
+// timestamp, falling back to the dataset's username
+func gitIdent(ds *dataset.Dataset) string {
+	name, email := ds.Peername, ""
+	if ds.Commit != nil && ds.Commit.Author != nil {
+		if ds.Commit.Author.Fullname != "" {
+			name = ds.Commit.Author.Fullname
+		}
+		email = ds.Commit.Author.Email
+	}
+	if name == "" {
+		name = "qri"
+	}
+	// angle brackets & newlines would end the identity early
+	clean := strings.NewReplacer("<", "", ">", "", "\n", " ")
+	ts := "0 +0000"
+	if ds.Commit != nil && !ds.Commit.Timestamp.IsZero() {
+		ts = fmt.Sprintf("%d %s", ds.Commit.Timestamp.Unix(), ds.Commit.Timestamp.Format("-0700"))
+	}
+	return fmt.Sprintf("%s <%s> %s", clean.Replace(name), clean.Replace(email), ts)
+}
+
+// gitCommitMessage combines the commit title & message of a version with a
+// trailer recording the version path
+func gitCommitMessage(ds *dataset.Dataset) string {
+	b := &strings.Builder{}
+	if ds.Commit != nil {
+		b.WriteString(ds.Commit.Title)
+		if ds.Commit.Message != "" && ds.Commit.Message != ds.Commit.Title {
+			b.WriteString("\n\n")
+			b.WriteString(ds.Commit.Message)
+		}
+	}
+	if ds.Path != "" {
+		b.WriteString("\n\nQri-Path: ")
+		b.WriteString(ds.Path)
+	}
+	b.WriteString("\n")
+	return strings.TrimLeft(b.String(), "\n")
+}
+
+// writeGitData writes a length-prefixed data block
+func writeGitData(w io.Writer, data []byte) {
+	fmt.Fprintf(w, "data %d\n", len(data))
+	w.Write(data)
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		io.WriteString(w, "\n")
+	}
+}
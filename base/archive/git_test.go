@@ -0,0 +1,114 @@
+package archive
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestWriteGitFastImport(t *testing.T) {
+	ts := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	first := &dataset.Dataset{
+		Peername: "peer",
+		Path:     "/mem/QmFirst",
+		Commit: &dataset.Commit{
+			Title:     "created dataset",
+			Timestamp: ts,
+			Author:    &dataset.User{Fullname: "Ada <Lovelace>", Email: "ada@example.com"},
+		},
+		Structure: &dataset.Structure{Format: "csv"},
+	}
+	first.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte("a,1\n")))
+
+	second := &dataset.Dataset{
+		Peername: "peer",
+		Path:     "/mem/QmSecond",
+		Commit: &dataset.Commit{
+			Title:     "added meta",
+			Message:   "meta:\n\tadded title",
+			Timestamp: ts.Add(time.Hour),
+		},
+		Meta: &dataset.Meta{Title: "numbers"},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := WriteGitFastImport(buf, "", VersionList([]*dataset.Dataset{first, second})); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `commit refs/heads/main
+mark :1
+author Ada Lovelace <ada@example.com> 1609556645 +0000
+committer Ada Lovelace <ada@example.com> 1609556645 +0000
+data 40
+created dataset
+
+Qri-Path: /mem/QmFirst
+deleteall
+M 644 inline body.csv
+data 4
+a,1
+M 644 inline structure.json
+data 39
+{
+  "format": "csv",
+  "qri": "st:0"
+}
+
+commit refs/heads/main
+mark :2
+author peer <> 1609560245 +0000
+committer peer <> 1609560245 +0000
+data 56
+added meta
+
+meta:
+	added title
+
+Qri-Path: /mem/QmSecond
+from :1
+deleteall
+M 644 inline meta.json
+data 42
+{
+  "qri": "md:0",
+  "title": "numbers"
+}
+
+`
+	if diff := cmp.Diff(expect, buf.String()); diff != "" {
+		t.Errorf("stream mismatch (-want +got):\n%s", diff)
+	}
+
+	// files are replaced as they're read, so a version can be written again
+	buf.Reset()
+	if err := WriteGitFastImport(buf, "qri", VersionList([]*dataset.Dataset{first})); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("commit refs/heads/qri\n")) || !bytes.Contains(buf.Bytes(), []byte("a,1\n")) {
+		t.Errorf("expected body to be written again on branch qri, got:\n%s", buf.String())
+	}
+}
+
+func TestWriteGitFastImportLoadError(t *testing.T) {
+	loaded := 0
+	next := func() (*dataset.Dataset, error) {
+		if loaded == 1 {
+			return nil, fmt.Errorf("loading failed")
+		}
+		loaded++
+		return &dataset.Dataset{Path: "/mem/QmFirst", Commit: &dataset.Commit{Title: "first"}}, nil
+	}
+	buf := &bytes.Buffer{}
+	if err := WriteGitFastImport(buf, "", next); err == nil || err.Error() != "loading failed" {
+		t.Errorf("expected load error, got: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("mark :1\n")) {
+		t.Errorf("expected versions before the error to be written, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,95 @@
+package base
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/dsref"
+)
+
+// AtomNamespace is the XML namespace of Atom feed documents
+const AtomNamespace = "http://www.w3.org/2005/Atom"
+
+type atomFeed struct {
+	XMLName  xml.Name     `xml:"feed"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	ID       string       `xml:"id"`
+	Title    string       `xml:"title"`
+	Subtitle string       `xml:"subtitle,omitempty"`
+	Updated  string       `xml:"updated"`
+	Author   *atomPerson  `xml:"author,omitempty"`
+	Link     *atomLink    `xml:"link,omitempty"`
+	Entries  []*atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  *atomPerson `xml:"author,omitempty"`
+	Summary string      `xml:"summary,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+// ActivityAtom writes dataset version history as an Atom feed document. Each
+// version is an entry titled with its commit title, with the commit message
+// as a summary. md supplies the feed title & subtitle, falling back to the
+// dataset reference when md has no title. Entry IDs are URNs of version paths
+func ActivityAtom(ref dsref.Ref, md *dataset.Meta, versions []dsref.VersionInfo) ([]byte, error) {
+	alias := ref.Alias()
+	feed := &atomFeed{
+		Xmlns:   AtomNamespace,
+		ID:      "urn:qri:" + alias,
+		Title:   alias,
+		Updated: atomTime(time.Time{}),
+		Author:  &atomPerson{Name: ref.Username},
+	}
+	if md != nil {
+		if md.Title != "" {
+			feed.Title = md.Title
+		}
+		feed.Subtitle = md.Description
+		if md.HomeURL != "" {
+			feed.Link = &atomLink{Href: md.HomeURL}
+		}
+	}
+
+	for i, vi := range versions {
+		if i == 0 {
+			// versions are listed newest first
+			feed.Updated = atomTime(vi.CommitTime)
+		}
+		entry := &atomEntry{
+			ID:      "urn:qri:" + strings.TrimPrefix(vi.Path, "/"),
+			Title:   vi.CommitTitle,
+			Updated: atomTime(vi.CommitTime),
+			Summary: vi.CommitMessage,
+		}
+		if entry.Title == "" {
+			entry.Title = vi.Path
+		}
+		if vi.Username != "" && vi.Username != ref.Username {
+			entry.Author = &atomPerson{Name: vi.Username}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,80 @@
+package base
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/dsref"
+)
+
+func TestActivityAtom(t *testing.T) {
+	ref := dsref.Ref{Username: "b5", Name: "precip"}
+	md := &dataset.Meta{
+		Title:       "Precipitation",
+		Description: "daily rainfall",
+	}
+	versions := []dsref.VersionInfo{
+		{
+			Username:      "b5",
+			Name:          "precip",
+			Path:          "/ipfs/QmTwo",
+			CommitTime:    time.Date(2021, 3, 2, 12, 0, 0, 0, time.UTC),
+			CommitTitle:   "updated body",
+			CommitMessage: "added march",
+		},
+		{
+			Username:   "b5",
+			Name:       "precip",
+			Path:       "/ipfs/QmOne",
+			CommitTime: time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	got, err := ActivityAtom(ref, md, versions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <id>urn:qri:b5/precip</id>
+  <title>Precipitation</title>
+  <subtitle>daily rainfall</subtitle>
+  <updated>2021-03-02T12:00:00Z</updated>
+  <author>
+    <name>b5</name>
+  </author>
+  <entry>
+    <id>urn:qri:ipfs/QmTwo</id>
+    <title>updated body</title>
+    <updated>2021-03-02T12:00:00Z</updated>
+    <summary>added march</summary>
+  </entry>
+  <entry>
+    <id>urn:qri:ipfs/QmOne</id>
+    <title>/ipfs/QmOne</title>
+    <updated>2021-03-01T12:00:00Z</updated>
+  </entry>
+</feed>`
+	if diff := cmp.Diff(expect, string(got)); diff != "" {
+		t.Errorf("feed mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = ActivityAtom(ref, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <id>urn:qri:b5/precip</id>
+  <title>b5/precip</title>
+  <updated>0001-01-01T00:00:00Z</updated>
+  <author>
+    <name>b5</name>
+  </author>
+</feed>`
+	if diff := cmp.Diff(expect, string(got)); diff != "" {
+		t.Errorf("empty feed mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -4,16 +4,22 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/tabular"
 	"github.com/qri-io/qfs"
 )
 
 // ErrNoBodyToInline is an error returned when a dataset has no body for inlining
 var ErrNoBodyToInline = fmt.Errorf("no body to inline")
 
+// ErrBodyRowOutOfRange is an error returned when a requested body row index
+// is past the last row of a body
+var ErrBodyRowOutOfRange = fmt.Errorf("body row out of range")
+
 // ReadBodyBytes grabs some or all of a dataset's body, writing an output in the desired format
 func ReadBodyBytes(ds *dataset.Dataset, format dataset.DataFormat, fcfg dataset.FormatConfig, limit, offset int, all bool) (data []byte, err error) {
 	if ds == nil {
@@ -45,6 +51,38 @@ func ReadBodyBytes(ds *dataset.Dataset, format dataset.DataFormat, fcfg dataset.
 	return data, nil
 }
 
+// WriteBody streams the entire body of a dataset to w in the desired format,
+// one entry at a time. The stored format config is kept when the format
+// doesn't change
+func WriteBody(w io.Writer, ds *dataset.Dataset, format dataset.DataFormat) error {
+	if ds == nil {
+		return fmt.Errorf("can't load body from a nil dataset")
+	}
+	file := ds.BodyFile()
+	if file == nil {
+		return fmt.Errorf("no body file to read")
+	}
+
+	st := &dataset.Structure{
+		Format: format.String(),
+		Schema: ds.Structure.Schema,
+	}
+	if ds.Structure.DataFormat() == format {
+		st.FormatConfig = ds.Structure.FormatConfig
+	}
+	ew, err := dsio.NewEntryWriter(st, w)
+	if err != nil {
+		return err
+	}
+	err = eachRow(ds.Structure, file, func(_ int, ent dsio.Entry) error {
+		return ew.WriteEntry(ent)
+	})
+	if err != nil {
+		return err
+	}
+	return ew.Close()
+}
+
 // GetBody takes returns the Body as a go-native structure,
 // using limit, offset, and all parameters to determine what part of the Body to return
 func GetBody(ds *dataset.Dataset, limit, offset int, all bool) (interface{}, error) {
@@ -57,7 +95,7 @@ func GetBody(ds *dataset.Dataset, limit, offset int, all bool) (interface{}, err
 		return nil, fmt.Errorf("no body file to read")
 	}
 
-	rr, err := dsio.NewEntryReader(ds.Structure, file)
+	rr, err := newBodyReader(ds.Structure, file)
 	if err != nil {
 		return nil, fmt.Errorf("error allocating data reader: %s", err)
 	}
@@ -71,6 +109,99 @@ func GetBody(ds *dataset.Dataset, limit, offset int, all bool) (interface{}, err
 	return ReadEntries(rr)
 }
 
+// GetBodyRow returns a single body row by index, stopping reading once the
+// row is found. The result is shaped like GetBody's: an array holding the row,
+// or an object holding the row's key & value for object bodies. It's an
+// ErrBodyRowOutOfRange error if the body has index rows or fewer
+func GetBodyRow(ds *dataset.Dataset, index int) (interface{}, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("can't load body from a nil dataset")
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("%w: row index %d is negative", ErrBodyRowOutOfRange, index)
+	}
+
+	file := ds.BodyFile()
+	if file == nil {
+		return nil, fmt.Errorf("no body file to read")
+	}
+
+	tlt, err := dsio.GetTopLevelType(ds.Structure)
+	if err != nil {
+		return nil, err
+	}
+
+	var row interface{}
+	rows := 0
+	err = eachRow(ds.Structure, file, func(i int, ent dsio.Entry) error {
+		rows++
+		if i < index {
+			return nil
+		}
+		if tlt == "object" {
+			row = map[string]interface{}{ent.Key: ent.Value}
+		} else {
+			row = []interface{}{ent.Value}
+		}
+		return errStopRows
+	})
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, fmt.Errorf("%w: row %d requested, body has %d rows", ErrBodyRowOutOfRange, index, rows)
+	}
+	return row, nil
+}
+
+// BodyScalar returns the value of a body with a single row holding a single
+// value, like the result of an aggregation. it's an error if the body has
+// more than one row or the row has more than one value
+func BodyScalar(ds *dataset.Dataset) (interface{}, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("can't load body from a nil dataset")
+	}
+
+	file := ds.BodyFile()
+	if file == nil {
+		return nil, fmt.Errorf("no body file to read")
+	}
+
+	rr, err := dsio.NewEntryReader(ds.Structure, file)
+	if err != nil {
+		return nil, fmt.Errorf("error allocating data reader: %s", err)
+	}
+	ent, err := rr.ReadEntry()
+	if err != nil {
+		if err.Error() == "EOF" {
+			return nil, fmt.Errorf("body is empty, expected a single value")
+		}
+		return nil, err
+	}
+	if _, err := rr.ReadEntry(); err == nil {
+		return nil, fmt.Errorf("body has more than one row, expected a single value")
+	} else if err.Error() != "EOF" {
+		return nil, err
+	}
+
+	switch row := ent.Value.(type) {
+	case []interface{}:
+		if len(row) != 1 {
+			return nil, fmt.Errorf("body row has %d values, expected a single value", len(row))
+		}
+		return row[0], nil
+	case map[string]interface{}:
+		if len(row) != 1 {
+			return nil, fmt.Errorf("body row has %d values, expected a single value", len(row))
+		}
+		for _, v := range row {
+			return v, nil
+		}
+	}
+	// bodies that are an object with a single key hold the value directly
+	return ent.Value, nil
+}
+
 // ReadEntries reads entries and returns them as a native go array or map
 func ReadEntries(reader dsio.EntryReader) (interface{}, error) {
 	obj := make(map[string]interface{})
@@ -163,3 +294,34 @@ func ConvertBodyFormat(bodyFile qfs.File, fromSt, toSt *dataset.Structure) (qfs.
 
 	return qfs.NewMemfileReader(toSt.BodyFilename(), buffer), nil
 }
+
+// InlineBodyFile serializes body, a go-native array or object of entries, to
+// a file of the given data format. The returned file is named for the format
+// so the body's structure can be detected from it
+func InlineBodyFile(body interface{}, format dataset.DataFormat) (qfs.File, error) {
+	if body == nil {
+		return nil, ErrNoBodyToInline
+	}
+	if format == dataset.UnknownDataFormat {
+		format = dataset.JSONDataFormat
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("encoding inline body: %w", err)
+	}
+	jsonSt := &dataset.Structure{
+		Format: dataset.JSONDataFormat.String(),
+		Schema: dataset.BaseSchemaArray,
+	}
+	jsonFile := qfs.NewMemfileBytes(jsonSt.BodyFilename(), data)
+	if format == dataset.JSONDataFormat {
+		return jsonFile, nil
+	}
+
+	st := &dataset.Structure{
+		Format: format.String(),
+		Schema: tabular.BaseTabularSchema,
+	}
+	return ConvertBodyFormat(jsonFile, jsonSt, st)
+}
@@ -0,0 +1,169 @@
+package base
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+// BodyCodec reads & writes dataset bodies in a format qri doesn't support
+// natively. Bodies are exchanged in qri's go-native representation: a slice
+// of entries for array bodies, or a map of entries for object bodies. Tabular
+// entries are slices of values
+type BodyCodec interface {
+	// Decode reads a complete body
+	Decode(r io.Reader) (interface{}, error)
+	// Encode writes a complete body
+	Encode(w io.Writer, body interface{}) error
+}
+
+var (
+	bodyCodecsLk sync.RWMutex
+	bodyCodecs   = map[string]BodyCodec{}
+)
+
+// builtinBodyFormats are the formats bodies can be fetched as without a
+// registered codec. they're encoded by the caller, and can't be registered or
+// unregistered
+var builtinBodyFormats = []string{"json", "csv", "ndjson"}
+
+func isBuiltinBodyFormat(name string) bool {
+	for _, f := range builtinBodyFormats {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisterBodyFormat adds a body format, making it available to save from
+// files with a matching extension & to get bodies as. Registered bodies are
+// decoded when they're opened & stored as JSON. name is case-insensitive and
+// must not be the name of a format qri supports natively. Embedding
+// applications should register formats at startup, before any instance is
+// created
+func RegisterBodyFormat(name string, codec BodyCodec) error {
+	name = strings.ToLower(name)
+	if name == "" || strings.ContainsAny(name, "./\\") {
+		return fmt.Errorf("invalid body format name %q", name)
+	}
+	if codec == nil {
+		return fmt.Errorf("body format %q: codec is required", name)
+	}
+	if isBuiltinBodyFormat(name) {
+		return fmt.Errorf("body format %q is built in and can't be registered", name)
+	}
+	if _, err := dataset.ParseDataFormatString(name); err == nil {
+		return fmt.Errorf("body format %q is supported natively and can't be registered", name)
+	}
+
+	bodyCodecsLk.Lock()
+	defer bodyCodecsLk.Unlock()
+	if _, exists := bodyCodecs[name]; exists {
+		return fmt.Errorf("body format %q is already registered", name)
+	}
+	bodyCodecs[name] = codec
+	return nil
+}
+
+// UnregisterBodyFormat removes a registered body format, returning false if
+// no format was registered under name
+func UnregisterBodyFormat(name string) bool {
+	name = strings.ToLower(name)
+	bodyCodecsLk.Lock()
+	defer bodyCodecsLk.Unlock()
+	if _, exists := bodyCodecs[name]; !exists {
+		return false
+	}
+	delete(bodyCodecs, name)
+	return true
+}
+
+// BodyCodecFor returns the codec registered for a body format
+func BodyCodecFor(name string) (BodyCodec, bool) {
+	bodyCodecsLk.RLock()
+	defer bodyCodecsLk.RUnlock()
+	codec, ok := bodyCodecs[strings.ToLower(name)]
+	return codec, ok
+}
+
+// IsBodyFormat returns true if bodies can be fetched as name, either a built-in
+// format or one registered with RegisterBodyFormat
+func IsBodyFormat(name string) bool {
+	name = strings.ToLower(name)
+	if isBuiltinBodyFormat(name) {
+		return true
+	}
+	_, ok := BodyCodecFor(name)
+	return ok
+}
+
+// BodyFormats lists the formats bodies can be fetched as, built-in formats
+// first followed by registered formats in name order
+func BodyFormats() []string {
+	bodyCodecsLk.RLock()
+	registered := make([]string, 0, len(bodyCodecs))
+	for name := range bodyCodecs {
+		registered = append(registered, name)
+	}
+	bodyCodecsLk.RUnlock()
+	sort.Strings(registered)
+	return append(append([]string{}, builtinBodyFormats...), registered...)
+}
+
+// BodyFormatFromFilename returns the registered body format a filename's
+// extension matches. Natively supported formats are detected by
+// detect.Structure instead
+func BodyFormatFromFilename(filename string) (string, bool) {
+	name := strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	if _, ok := BodyCodecFor(name); !ok {
+		return "", false
+	}
+	return name, true
+}
+
+// DecodeBodyFile converts a body file in a registered format to JSON, returning
+// files in any other format unchanged
+func DecodeBodyFile(file qfs.File) (qfs.File, error) {
+	if file == nil {
+		return nil, nil
+	}
+	format, ok := BodyFormatFromFilename(file.FileName())
+	if !ok {
+		return file, nil
+	}
+	codec, _ := BodyCodecFor(format)
+	defer file.Close()
+
+	body, err := codec.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s body: %w", format, err)
+	}
+	return InlineBodyFile(body, dataset.JSONDataFormat)
+}
+
+// EncodeBody reads a dataset body & writes it in a registered body format,
+// using limit, offset, and all parameters to determine what part of the body
+// to write
+func EncodeBody(ds *dataset.Dataset, format string, limit, offset int, all bool) ([]byte, error) {
+	codec, ok := BodyCodecFor(format)
+	if !ok {
+		return nil, fmt.Errorf("unknown body format %q", format)
+	}
+	body, err := GetBody(ds, limit, offset, all)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err := codec.Encode(buf, body); err != nil {
+		return nil, fmt.Errorf("encoding %s body: %w", format, err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,128 @@
+package base
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+// psvCodec reads & writes pipe-separated rows of strings
+type psvCodec struct{}
+
+func (psvCodec) Decode(r io.Reader) (interface{}, error) {
+	rows := []interface{}{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		row := []interface{}{}
+		for _, v := range strings.Split(sc.Text(), "|") {
+			row = append(row, v)
+		}
+		rows = append(rows, row)
+	}
+	return rows, sc.Err()
+}
+
+func (psvCodec) Encode(w io.Writer, body interface{}) error {
+	rows, ok := body.([]interface{})
+	if !ok {
+		return fmt.Errorf("psv bodies must be arrays")
+	}
+	for _, r := range rows {
+		vals := []string{}
+		for _, v := range r.([]interface{}) {
+			vals = append(vals, fmt.Sprintf("%v", v))
+		}
+		if _, err := io.WriteString(w, strings.Join(vals, "|")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRegisterBodyFormat(t *testing.T) {
+	if err := RegisterBodyFormat("PSV", psvCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	defer UnregisterBodyFormat("psv")
+
+	bad := []struct {
+		name  string
+		codec BodyCodec
+		err   string
+	}{
+		{"psv", psvCodec{}, `body format "psv" is already registered`},
+		{"ndjson", psvCodec{}, `body format "ndjson" is built in and can't be registered`},
+		{"xlsx", psvCodec{}, `body format "xlsx" is supported natively and can't be registered`},
+		{"", psvCodec{}, `invalid body format name ""`},
+		{"p.sv", psvCodec{}, `invalid body format name "p.sv"`},
+		{"bsv", nil, `body format "bsv": codec is required`},
+	}
+	for _, c := range bad {
+		err := RegisterBodyFormat(c.name, c.codec)
+		if err == nil || err.Error() != c.err {
+			t.Errorf("register %q error mismatch. want: %q, got: %v", c.name, c.err, err)
+		}
+	}
+
+	if diff := cmp.Diff([]string{"json", "csv", "ndjson", "psv"}, BodyFormats()); diff != "" {
+		t.Errorf("body formats mismatch (-want +got):\n%s", diff)
+	}
+	if !IsBodyFormat("ndjson") || !IsBodyFormat("PSV") || IsBodyFormat("xml") {
+		t.Error("expected ndjson & psv to be body formats, and xml not to be")
+	}
+	if _, ok := BodyFormatFromFilename("body.ndjson"); ok {
+		t.Error("expected built-in formats not to match filenames")
+	}
+
+	if format, ok := BodyFormatFromFilename("/path/to/Body.PSV"); !ok || format != "psv" {
+		t.Errorf("expected filename to match psv format, got: %q %t", format, ok)
+	}
+	if _, ok := BodyFormatFromFilename("body.csv"); ok {
+		t.Errorf("expected csv filename not to match a registered format")
+	}
+
+	ctx := context.Background()
+	fs := qfs.NewMemFS()
+	path, err := fs.Put(ctx, qfs.NewMemfileBytes("body.psv", []byte("a|1\nb|2\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := &dataset.Dataset{BodyPath: path}
+	if err := OpenDataset(ctx, fs, ds); err != nil {
+		t.Fatal(err)
+	}
+	if name := ds.BodyFile().FileName(); name != "body.json" {
+		t.Errorf("expected decoded body to be json, got: %q", name)
+	}
+
+	ds.Structure = &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	data, err := EncodeBody(ds, "psv", 0, 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("a|1\nb|2\n", string(data)); diff != "" {
+		t.Errorf("encoded body mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestUnregisterBodyFormat(t *testing.T) {
+	if UnregisterBodyFormat("psv") {
+		t.Error("expected unregistering a missing format to return false")
+	}
+	if err := RegisterBodyFormat("psv", psvCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	if !UnregisterBodyFormat("PSV") {
+		t.Error("expected unregistering a registered format to return true")
+	}
+	if _, ok := BodyCodecFor("psv"); ok {
+		t.Error("expected unregistered format to be removed")
+	}
+}
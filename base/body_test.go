@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"strings"
@@ -70,6 +71,43 @@ func TestReadBodyBytes(t *testing.T) {
 	}
 }
 
+func TestWriteBody(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"headerRow": true},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "pop", "type": "integer"},
+				},
+			},
+		},
+	}
+	body := "city,pop\ntoronto,1\nchatham,2\n"
+
+	cases := []struct {
+		format dataset.DataFormat
+		expect string
+	}{
+		{dataset.CSVDataFormat, body},
+		{dataset.JSONDataFormat, `[["toronto",1],["chatham",2]]`},
+	}
+	for _, c := range cases {
+		ds := &dataset.Dataset{Structure: st}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte(body)))
+		buf := &bytes.Buffer{}
+		if err := WriteBody(buf, ds, c.format); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(c.expect, buf.String()); diff != "" {
+			t.Errorf("%s output mismatch (-want +got):\n%s", c.format, diff)
+		}
+	}
+}
+
 func TestConvertBodyFormat(t *testing.T) {
 	jsonStructure := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
 	csvStructure := &dataset.Structure{Format: "csv", Schema: tabular.BaseTabularSchema}
@@ -145,6 +183,131 @@ func TestConvertBodyFormat(t *testing.T) {
 	}
 }
 
+func TestGetBodyRow(t *testing.T) {
+	newDs := func(format, body string, schema map[string]interface{}) *dataset.Dataset {
+		ds := &dataset.Dataset{Structure: &dataset.Structure{Format: format, Schema: schema}}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body."+format, []byte(body)))
+		return ds
+	}
+	csvSchema := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":  "array",
+			"items": []interface{}{map[string]interface{}{"title": "letter", "type": "string"}},
+		},
+	}
+
+	good := []struct {
+		ds     *dataset.Dataset
+		index  int
+		expect interface{}
+	}{
+		{newDs("json", `[[1],[2],[3]]`, dataset.BaseSchemaArray), 1, []interface{}{[]interface{}{int64(2)}}},
+		{newDs("json", `{"a":1,"b":2}`, dataset.BaseSchemaObject), 1, map[string]interface{}{"b": int64(2)}},
+		{newDs("csv", "a\nb\nc\n", csvSchema), 2, []interface{}{[]interface{}{"c"}}},
+		// reading stops at the requested row, malformed data after it isn't read
+		{newDs("json", `[[1],[2],[3`, dataset.BaseSchemaArray), 0, []interface{}{[]interface{}{int64(1)}}},
+	}
+	for i, c := range good {
+		got, err := GetBodyRow(c.ds, c.index)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %s", i, err)
+			continue
+		}
+		if diff := cmp.Diff(c.expect, got); diff != "" {
+			t.Errorf("case %d: value mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+
+	_, err := GetBodyRow(newDs("json", `[[1],[2]]`, dataset.BaseSchemaArray), 2)
+	if !errors.Is(err, ErrBodyRowOutOfRange) {
+		t.Errorf("expected ErrBodyRowOutOfRange, got: %v", err)
+	}
+	if expect := "body row out of range: row 2 requested, body has 2 rows"; err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. want: %q, got: %v", expect, err)
+	}
+}
+
+func TestBodyScalar(t *testing.T) {
+	newDs := func(body string, schema map[string]interface{}) *dataset.Dataset {
+		ds := &dataset.Dataset{Structure: &dataset.Structure{Format: "json", Schema: schema}}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(body)))
+		return ds
+	}
+
+	good := []struct {
+		body   string
+		schema map[string]interface{}
+		expect interface{}
+	}{
+		{`[[42]]`, dataset.BaseSchemaArray, int64(42)},
+		{`[{"total":"a"}]`, dataset.BaseSchemaArray, "a"},
+		{`{"total":true}`, dataset.BaseSchemaObject, true},
+	}
+	for _, c := range good {
+		got, err := BodyScalar(newDs(c.body, c.schema))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c.body, err)
+			continue
+		}
+		if diff := cmp.Diff(c.expect, got); diff != "" {
+			t.Errorf("%s: value mismatch (-want +got):\n%s", c.body, diff)
+		}
+	}
+
+	bad := []struct {
+		body   string
+		expect string
+	}{
+		{`[]`, "body is empty, expected a single value"},
+		{`[[1],[2]]`, "body has more than one row, expected a single value"},
+		{`[[1,2]]`, "body row has 2 values, expected a single value"},
+	}
+	for _, c := range bad {
+		_, err := BodyScalar(newDs(c.body, dataset.BaseSchemaArray))
+		if err == nil || err.Error() != c.expect {
+			t.Errorf("%s: error mismatch. want: %q, got: %v", c.body, c.expect, err)
+		}
+	}
+}
+
+func TestInlineBodyFile(t *testing.T) {
+	rows := [][]interface{}{{"a", 1}, {"b", 2}}
+
+	got, err := InlineBodyFile(rows, dataset.CSVDataFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.FileName() != "body.csv" {
+		t.Errorf("filename mismatch. want: %q, got: %q", "body.csv", got.FileName())
+	}
+	data, err := ioutil.ReadAll(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("a,1\nb,2\n", string(data)); diff != "" {
+		t.Errorf("csv body mismatch (-want +got):\n%s", diff)
+	}
+
+	objs := []map[string]interface{}{{"a": 1}}
+	if got, err = InlineBodyFile(objs, dataset.UnknownDataFormat); err != nil {
+		t.Fatal(err)
+	}
+	if got.FileName() != "body.json" {
+		t.Errorf("filename mismatch. want: %q, got: %q", "body.json", got.FileName())
+	}
+	if data, err = ioutil.ReadAll(got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(`[{"a":1}]`, string(data)); diff != "" {
+		t.Errorf("json body mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err = InlineBodyFile(nil, dataset.JSONDataFormat); err != ErrNoBodyToInline {
+		t.Errorf("expected nil body to return ErrNoBodyToInline, got: %v", err)
+	}
+}
+
 func TestReadEntriesArray(t *testing.T) {
 	cases := []struct {
 		description           string
@@ -0,0 +1,375 @@
+package base
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/tabular"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base/dsfs"
+)
+
+// ColumnBlocksKey is the structure format config key that records the layout
+// of a body that is also stored as one block per column. The value is an
+// object with a "rows" field of either "array" or "object", and a "columns"
+// list of {"title", "path"} objects in column order
+//
+// Column blocks are experimental & opt-in. The body is always stored in full
+// as well, so readers that don't know about column blocks keep working, at the
+// cost of storing body values twice. Blocks are linked from the dataset root
+// node, so they travel with the dataset when it's pushed, pulled or pinned
+const ColumnBlocksKey = dsfs.ColumnBlocksKey
+
+// ColumnBlock is a single column of body values stored in its own block
+type ColumnBlock struct {
+	Title string `json:"title"`
+	Path  string `json:"path"`
+}
+
+// WriteColumnBlocks stores each column of a dataset body as a separate JSON
+// array block, recording the layout in the structure format config. Body rows
+// must all be arrays or all be objects. The body file is consumed and replaced
+// with an in-memory copy so the full body can still be written
+func WriteColumnBlocks(ctx context.Context, fs qfs.Filesystem, ds *dataset.Dataset) error {
+	body := ds.BodyFile()
+	if body == nil || ds.Structure == nil {
+		return fmt.Errorf("columnar storage requires a body")
+	}
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes(body.FileName(), data))
+
+	rows, cols, values, err := bodyColumnValues(ds)
+	ds.SetBodyFile(qfs.NewMemfileBytes(body.FileName(), data))
+	if err != nil {
+		return fmt.Errorf("columnar storage: %w", err)
+	}
+
+	blocks := make([]interface{}, len(cols))
+	for i, title := range cols {
+		col := make([]interface{}, len(values))
+		for j, row := range values {
+			col[j] = row[i]
+		}
+		colData, err := json.Marshal(col)
+		if err != nil {
+			return err
+		}
+		path, err := fs.Put(ctx, qfs.NewMemfileBytes(fmt.Sprintf("column_%d.json", i), colData))
+		if err != nil {
+			return fmt.Errorf("writing block for column %q: %w", title, err)
+		}
+		blocks[i] = map[string]interface{}{"title": title, "path": path}
+	}
+
+	if ds.Structure.FormatConfig == nil {
+		ds.Structure.FormatConfig = map[string]interface{}{}
+	}
+	ds.Structure.FormatConfig[ColumnBlocksKey] = map[string]interface{}{
+		"rows":    rows,
+		"columns": blocks,
+	}
+	return nil
+}
+
+// DropColumnBlocks removes any column block layout from a structure
+func DropColumnBlocks(st *dataset.Structure) {
+	if st == nil || st.FormatConfig == nil {
+		return
+	}
+	delete(st.FormatConfig, ColumnBlocksKey)
+	if len(st.FormatConfig) == 0 {
+		st.FormatConfig = nil
+	}
+}
+
+// HasColumnBlocks reports if a structure records a column block layout
+func HasColumnBlocks(st *dataset.Structure) bool {
+	_, _, ok := columnBlocks(st)
+	return ok
+}
+
+// columnBlocks reads the column block layout of a structure
+func columnBlocks(st *dataset.Structure) (rows string, blocks []ColumnBlock, ok bool) {
+	if st == nil || st.FormatConfig == nil {
+		return "", nil, false
+	}
+	layout, ok := st.FormatConfig[ColumnBlocksKey].(map[string]interface{})
+	if !ok {
+		return "", nil, false
+	}
+	rows, _ = layout["rows"].(string)
+	cols, _ := layout["columns"].([]interface{})
+	for _, c := range cols {
+		m, _ := c.(map[string]interface{})
+		title, _ := m["title"].(string)
+		path, _ := m["path"].(string)
+		if path == "" {
+			return "", nil, false
+		}
+		blocks = append(blocks, ColumnBlock{Title: title, Path: path})
+	}
+	return rows, blocks, len(blocks) > 0
+}
+
+// ReadColumns reads the named columns of a dataset body, returning rows in
+// the body's row shape that only contain those columns. Bodies with a column
+// block layout only read the blocks of the selected columns, all other
+// bodies are read from an opened body file
+func ReadColumns(ctx context.Context, fs qfs.Filesystem, ds *dataset.Dataset, columns []string, limit, offset int, all bool) ([]interface{}, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns selected")
+	}
+
+	var (
+		rows   string
+		values [][]interface{}
+	)
+	if layoutRows, blocks, ok := columnBlocks(ds.Structure); ok {
+		rows = layoutRows
+		byTitle := map[string]string{}
+		titles := make([]string, len(blocks))
+		for i, b := range blocks {
+			byTitle[b.Title] = b.Path
+			titles[i] = b.Title
+		}
+		for i, name := range columns {
+			path, ok := byTitle[name]
+			if !ok {
+				return nil, unknownColumnError(name, titles)
+			}
+			col, err := readColumnBlock(ctx, fs, path)
+			if err != nil {
+				return nil, fmt.Errorf("reading column %q: %w", name, err)
+			}
+			if i == 0 {
+				values = make([][]interface{}, len(col))
+				for j := range values {
+					values[j] = make([]interface{}, len(columns))
+				}
+			} else if len(col) != len(values) {
+				return nil, fmt.Errorf("column %q has %d values, expected %d", name, len(col), len(values))
+			}
+			for j, v := range col {
+				values[j][i] = v
+			}
+		}
+	} else {
+		var (
+			cols    []string
+			allVals [][]interface{}
+			err     error
+		)
+		if rows, cols, allVals, err = bodyColumnValues(ds); err != nil {
+			return nil, err
+		}
+		idx := make([]int, len(columns))
+		for i, name := range columns {
+			idx[i] = -1
+			for j, c := range cols {
+				if c == name {
+					idx[i] = j
+				}
+			}
+			if idx[i] == -1 {
+				return nil, unknownColumnError(name, cols)
+			}
+		}
+		values = make([][]interface{}, len(allVals))
+		for j, row := range allVals {
+			values[j] = make([]interface{}, len(columns))
+			for i, k := range idx {
+				values[j][i] = row[k]
+			}
+		}
+	}
+
+	if !all {
+		if offset > len(values) {
+			offset = len(values)
+		}
+		values = values[offset:]
+		if limit >= 0 && limit < len(values) {
+			values = values[:limit]
+		}
+	}
+
+	res := make([]interface{}, len(values))
+	for j, row := range values {
+		if rows == "object" {
+			obj := map[string]interface{}{}
+			for i, name := range columns {
+				obj[name] = row[i]
+			}
+			res[j] = obj
+			continue
+		}
+		res[j] = row
+	}
+	return res, nil
+}
+
+// ColumnsCSV encodes rows returned by ReadColumns as csv, with a header row
+// of column names. null values are written as empty fields, objects & arrays
+// as json, and floats without exponents
+func ColumnsCSV(columns []string, rows []interface{}, enc CSVEncoding) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc.writeBOM(buf)
+	w := enc.newWriter(buf)
+	if err := w.Write(columns); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, name := range columns {
+			var v interface{}
+			switch r := row.(type) {
+			case []interface{}:
+				if len(r) != len(columns) {
+					return nil, fmt.Errorf("row has %d values, expected %d", len(r), len(columns))
+				}
+				v = r[i]
+			case map[string]interface{}:
+				v = r[name]
+			default:
+				return nil, fmt.Errorf("unexpected row type %T", row)
+			}
+			str, err := csvValue(v)
+			if err != nil {
+				return nil, err
+			}
+			record[i] = str
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func csvValue(v interface{}) (string, error) {
+	switch x := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return x, nil
+	case float64:
+		// %v switches to exponents for large & small floats, which csv readers
+		// won't parse as the same number
+		return strconv.FormatFloat(x, 'f', -1, 64), nil
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(x)
+		return string(data), err
+	}
+	return fmt.Sprintf("%v", v), nil
+}
+
+// unknownColumnError reports a column name that isn't in the list of
+// available columns
+func unknownColumnError(name string, available []string) error {
+	return fmt.Errorf("unknown column %q, available columns: %s", name, strings.Join(available, ", "))
+}
+
+func readColumnBlock(ctx context.Context, fs qfs.Filesystem, path string) ([]interface{}, error) {
+	f, err := fs.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	col := []interface{}{}
+	dec := json.NewDecoder(f)
+	dec.UseNumber()
+	if err := dec.Decode(&col); err != nil {
+		return nil, err
+	}
+	// match the integer & float values body readers produce
+	for i, v := range col {
+		if num, ok := v.(json.Number); ok {
+			if n, err := num.Int64(); err == nil {
+				col[i] = n
+			} else if f, err := num.Float64(); err == nil {
+				col[i] = f
+			}
+		}
+	}
+	return col, nil
+}
+
+// bodyColumnValues reads an opened body into rows of column values, along with
+// the column titles & the shape of body rows, either "array" or "object".
+// array rows take column titles from the schema, object rows use the sorted
+// set of keys across all rows. Errors read as a continuation of the caller's
+// subject, eg: "SQL queries require a tabular schema to name columns"
+func bodyColumnValues(ds *dataset.Dataset) (rows string, cols []string, values [][]interface{}, err error) {
+	body, err := GetBody(ds, -1, 0, true)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	entries, ok := body.([]interface{})
+	if !ok {
+		return "", nil, nil, fmt.Errorf("require a body that is an array of rows")
+	}
+
+	var objectRows []map[string]interface{}
+	for _, entry := range entries {
+		switch row := entry.(type) {
+		case []interface{}:
+			values = append(values, row)
+		case map[string]interface{}:
+			objectRows = append(objectRows, row)
+		default:
+			return "", nil, nil, fmt.Errorf("require body rows to be arrays or objects, found %T", entry)
+		}
+	}
+	if len(values) > 0 && len(objectRows) > 0 {
+		return "", nil, nil, fmt.Errorf("require body rows to all be arrays or all be objects")
+	}
+
+	if len(objectRows) > 0 {
+		keys := map[string]bool{}
+		for _, row := range objectRows {
+			for key := range row {
+				if !keys[key] {
+					keys[key] = true
+					cols = append(cols, key)
+				}
+			}
+		}
+		sort.Strings(cols)
+		for _, row := range objectRows {
+			vals := make([]interface{}, len(cols))
+			for i, col := range cols {
+				vals[i] = row[col]
+			}
+			values = append(values, vals)
+		}
+		return "object", cols, values, nil
+	}
+
+	if ds.Structure == nil {
+		return "", nil, nil, fmt.Errorf("require a tabular schema to name columns")
+	}
+	tcols, _, err := tabular.ColumnsFromJSONSchema(ds.Structure.Schema)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("require a tabular schema to name columns: %w", err)
+	}
+	for _, col := range tcols {
+		cols = append(cols, col.Title)
+	}
+	for i, row := range values {
+		if len(row) != len(cols) {
+			return "", nil, nil, fmt.Errorf("require body rows to match the schema, row %d has %d values, schema defines %d columns", i, len(row), len(cols))
+		}
+	}
+	return "array", cols, values, nil
+}
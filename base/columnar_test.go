@@ -0,0 +1,116 @@
+package base
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestColumnBlocks(t *testing.T) {
+	ctx := context.Background()
+	fs := qfs.NewMemFS()
+
+	ds := &dataset.Dataset{
+		Structure: &dataset.Structure{
+			Format: "json",
+			Schema: map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "array",
+					"items": []interface{}{
+						map[string]interface{}{"title": "region", "type": "string"},
+						map[string]interface{}{"title": "amount", "type": "integer"},
+						map[string]interface{}{"title": "rate", "type": "number"},
+					},
+				},
+			},
+		},
+	}
+	body := `[["east",10,0.5],["west",5,1.5],["north",2,2]]`
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(body)))
+
+	// reading without blocks falls back to the body
+	got, err := ReadColumns(ctx, fs, ds, []string{"amount", "region"}, -1, 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []interface{}{
+		[]interface{}{int64(10), "east"},
+		[]interface{}{int64(5), "west"},
+		[]interface{}{int64(2), "north"},
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("body columns mismatch (-want +got):\n%s", diff)
+	}
+
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(body)))
+	if err := WriteColumnBlocks(ctx, fs, ds); err != nil {
+		t.Fatal(err)
+	}
+	if !HasColumnBlocks(ds.Structure) {
+		t.Fatal("expected structure to record column blocks")
+	}
+	// the full body remains readable after writing blocks
+	if full, err := GetBody(ds, -1, 0, true); err != nil {
+		t.Fatal(err)
+	} else if len(full.([]interface{})) != 3 {
+		t.Errorf("expected full body to have 3 rows, got: %v", full)
+	}
+
+	// reading with blocks doesn't need a body
+	ds.SetBodyFile(nil)
+	if got, err = ReadColumns(ctx, fs, ds, []string{"rate"}, 1, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	expect = []interface{}{[]interface{}{1.5}}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("block columns mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := ReadColumns(ctx, fs, ds, []string{"city"}, -1, 0, true); err == nil || err.Error() != `unknown column "city", available columns: region, amount, rate` {
+		t.Errorf("expected unknown column error, got: %v", err)
+	}
+
+	if got, err = ReadColumns(ctx, fs, ds, []string{"rate", "region"}, 1, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ColumnsCSV([]string{"rate", "region"}, got, CSVEncoding{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("rate,region\n1.5,west\n", string(data)); diff != "" {
+		t.Errorf("csv mismatch (-want +got):\n%s", diff)
+	}
+
+	data, err = ColumnsCSV([]string{"big", "small"}, []interface{}{[]interface{}{1e21, 0.0000001}}, CSVEncoding{CRLF: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("big,small\r\n1000000000000000000000,0.0000001\r\n", string(data)); diff != "" {
+		t.Errorf("csv float mismatch (-want +got):\n%s", diff)
+	}
+
+	DropColumnBlocks(ds.Structure)
+	if HasColumnBlocks(ds.Structure) {
+		t.Error("expected dropping column blocks to remove the layout")
+	}
+
+	objects := &dataset.Dataset{Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}}
+	objects.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(`[{"a":1,"b":true},{"a":2,"c":"x"}]`)))
+	if err := WriteColumnBlocks(ctx, fs, objects); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = ReadColumns(ctx, fs, objects, []string{"c"}, -1, 0, true); err != nil {
+		t.Fatal(err)
+	}
+	expect = []interface{}{
+		map[string]interface{}{"c": nil},
+		map[string]interface{}{"c": "x"},
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("object rows mismatch (-want +got):\n%s", diff)
+	}
+}
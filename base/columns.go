@@ -0,0 +1,355 @@
+package base
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/qfs"
+)
+
+// ReadColumnDescriptions parses a "data dictionary" csv that maps column
+// names to descriptions. Each row must have the form "column,description".
+// A leading header row of exactly "column,description" is skipped
+func ReadColumnDescriptions(r io.Reader) (map[string]string, error) {
+	rdr := csv.NewReader(r)
+	rdr.FieldsPerRecord = 2
+	rdr.TrimLeadingSpace = true
+
+	descriptions := map[string]string{}
+	for i := 0; ; i++ {
+		row, err := rdr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading column descriptions: %w", err)
+		}
+		if i == 0 && strings.ToLower(row[0]) == "column" && strings.ToLower(row[1]) == "description" {
+			continue
+		}
+		descriptions[row[0]] = row[1]
+	}
+	return descriptions, nil
+}
+
+// SetColumnDescriptions writes descriptions into the column schemas of a
+// tabular structure, matching on column title. The names of any descriptions
+// that don't match a column are returned in sorted order
+func SetColumnDescriptions(st *dataset.Structure, descriptions map[string]string) (unknown []string, err error) {
+	cols, err := schemaColumnItems(st)
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]bool{}
+	for _, col := range cols {
+		title, _ := col["title"].(string)
+		if desc, ok := descriptions[title]; ok {
+			col["description"] = desc
+			found[title] = true
+		}
+	}
+
+	for name := range descriptions {
+		if !found[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// ColumnMeta is descriptive metadata for a single column, stored in the
+// column's schema. Unit is stored as "unit", Example as the first of the JSON
+// schema "examples"
+type ColumnMeta struct {
+	Description string `json:"description,omitempty"`
+	Unit        string `json:"unit,omitempty"`
+	Example     string `json:"example,omitempty"`
+}
+
+// SetColumnMeta writes metadata into the schema of the named column. Empty
+// fields of meta leave the column's existing values untouched. Both tabular
+// schemas & schemas that describe rows as objects are supported
+func SetColumnMeta(st *dataset.Structure, column string, meta ColumnMeta) error {
+	if meta == (ColumnMeta{}) {
+		return fmt.Errorf("no column metadata to set")
+	}
+	col, err := columnSchema(st, column)
+	if err != nil {
+		return err
+	}
+	if meta.Description != "" {
+		col["description"] = meta.Description
+	}
+	if meta.Unit != "" {
+		col["unit"] = meta.Unit
+	}
+	if meta.Example != "" {
+		col["examples"] = []interface{}{meta.Example}
+	}
+	return nil
+}
+
+// columnSchema returns the schema of the named column. the returned map is
+// the schema's own value, so modifying it modifies the schema
+func columnSchema(st *dataset.Structure, column string) (map[string]interface{}, error) {
+	if st == nil || st.Schema == nil {
+		return nil, fmt.Errorf("dataset has no schema")
+	}
+	if items, ok := st.Schema["items"].(map[string]interface{}); ok {
+		if t, _ := items["type"].(string); t == "object" {
+			props, _ := items["properties"].(map[string]interface{})
+			col, ok := props[column].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("column %q not found", column)
+			}
+			return col, nil
+		}
+	}
+
+	cols, err := schemaColumnItems(st)
+	if err != nil {
+		return nil, err
+	}
+	for _, col := range cols {
+		if title, _ := col["title"].(string); title == column {
+			return col, nil
+		}
+	}
+	return nil, fmt.Errorf("column %q not found", column)
+}
+
+// RenameColumn changes the name of a column in a structure schema from one
+// name to another. Tabular schemas have the matching column title changed,
+// schemas that describe rows as objects have the matching property renamed.
+// objectRows is true for the latter case, which means body entries are keyed
+// by column name and must be renamed with RenameBodyKeys. Schemas that don't
+// describe columns at all, like the default array schema, are left untouched
+// and also report objectRows, leaving column checks to RenameBodyKeys.
+// RenameColumn errors if from doesn't exist or to is already in use
+func RenameColumn(st *dataset.Structure, from, to string) (objectRows bool, err error) {
+	if from == "" || to == "" {
+		return false, fmt.Errorf("column names cannot be empty")
+	}
+	if from == to {
+		return false, fmt.Errorf("column is already named %q", to)
+	}
+	if st == nil || st.Schema == nil {
+		return false, fmt.Errorf("dataset has no schema")
+	}
+
+	items, ok := st.Schema["items"].(map[string]interface{})
+	if !ok {
+		// schema doesn't describe rows
+		return true, nil
+	}
+	if t, _ := items["type"].(string); t == "object" {
+		return true, renameSchemaProperty(items, from, to)
+	}
+
+	cols, err := schemaColumnItems(st)
+	if err != nil {
+		return false, err
+	}
+	var match map[string]interface{}
+	for _, col := range cols {
+		title, _ := col["title"].(string)
+		if title == to {
+			return false, fmt.Errorf("column %q already exists", to)
+		}
+		if title == from {
+			match = col
+		}
+	}
+	if match == nil {
+		return false, fmt.Errorf("column %q not found", from)
+	}
+	match["title"] = to
+	return false, nil
+}
+
+// renameSchemaProperty renames a property of an object row schema, a schema
+// that doesn't list properties is left as-is
+func renameSchemaProperty(items map[string]interface{}, from, to string) error {
+	props, ok := items["properties"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if _, exists := props[to]; exists {
+		return fmt.Errorf("column %q already exists", to)
+	}
+	col, ok := props[from]
+	if !ok {
+		return fmt.Errorf("column %q not found", from)
+	}
+	delete(props, from)
+	props[to] = col
+
+	if required, ok := items["required"].([]interface{}); ok {
+		for i, name := range required {
+			if name == from {
+				required[i] = to
+			}
+		}
+	}
+	return nil
+}
+
+// RenameBodyKeys rewrites a body where each entry is an object, renaming the
+// from key of every entry to to. The body is read with the given structure
+// and written back in the same format. It errors if no entry has a from key,
+// or any entry already has a to key
+func RenameBodyKeys(st *dataset.Structure, body qfs.File, from, to string) (qfs.File, error) {
+	rdr, err := dsio.NewEntryReader(st, body)
+	if err != nil {
+		return nil, fmt.Errorf("reading body: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+	wr, err := dsio.NewEntryWriter(st, buf)
+	if err != nil {
+		return nil, fmt.Errorf("writing body: %w", err)
+	}
+
+	found := false
+	for {
+		ent, err := rdr.ReadEntry()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading body: %w", err)
+		}
+		if row, ok := ent.Value.(map[string]interface{}); ok {
+			if _, exists := row[to]; exists {
+				return nil, fmt.Errorf("column %q already exists", to)
+			}
+			if v, ok := row[from]; ok {
+				found = true
+				delete(row, from)
+				row[to] = v
+			}
+		}
+		if err = wr.WriteEntry(ent); err != nil {
+			return nil, fmt.Errorf("writing body: %w", err)
+		}
+	}
+	if err = wr.Close(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("column %q not found", from)
+	}
+
+	return qfs.NewMemfileBytes(fmt.Sprintf("body.%s", st.Format), buf.Bytes()), nil
+}
+
+// WidenSchema builds a tabular schema for a body that adds columns to a
+// previous schema. Columns are listed in the order the body schema gives them,
+// columns the previous schema defines keep their previous definition, and
+// added columns also accept null so rows without a value stay valid. Every
+// previous column must be in the body, removing columns requires an explicit
+// schema. The titles of added columns are returned in body order
+func WidenSchema(prev, body *dataset.Structure) (schema map[string]interface{}, added []string, err error) {
+	prevCols, err := schemaColumnItems(prev)
+	if err != nil {
+		return nil, nil, fmt.Errorf("widening schema: previous %w", err)
+	}
+	bodyCols, err := schemaColumnItems(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("widening schema: body %w", err)
+	}
+
+	byTitle := map[string]map[string]interface{}{}
+	for _, col := range prevCols {
+		title, _ := col["title"].(string)
+		byTitle[title] = col
+	}
+
+	items := make([]interface{}, 0, len(bodyCols))
+	found := map[string]bool{}
+	for _, col := range bodyCols {
+		title, _ := col["title"].(string)
+		if prevCol, ok := byTitle[title]; ok {
+			found[title] = true
+			items = append(items, prevCol)
+			continue
+		}
+		col["type"] = nullableType(col["type"])
+		items = append(items, col)
+		added = append(added, title)
+	}
+
+	var missing []string
+	for _, col := range prevCols {
+		if title, _ := col["title"].(string); !found[title] {
+			missing = append(missing, title)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("body is missing columns: %s. removing columns requires saving an explicit schema", strings.Join(missing, ", "))
+	}
+
+	schema = map[string]interface{}{}
+	for key, val := range prev.Schema {
+		schema[key] = val
+	}
+	rowSchema := map[string]interface{}{}
+	for key, val := range prev.Schema["items"].(map[string]interface{}) {
+		rowSchema[key] = val
+	}
+	rowSchema["items"] = items
+	schema["items"] = rowSchema
+	return schema, added, nil
+}
+
+// nullableType adds "null" to a JSON schema type value
+func nullableType(t interface{}) interface{} {
+	switch v := t.(type) {
+	case string:
+		if v == "null" {
+			return v
+		}
+		return []interface{}{v, "null"}
+	case []interface{}:
+		for _, x := range v {
+			if x == "null" {
+				return v
+			}
+		}
+		return append(v, "null")
+	}
+	return t
+}
+
+// schemaColumnItems returns the per-column schemas of a tabular structure.
+// returned maps are the schema's own values, so modifying them modifies the
+// schema
+func schemaColumnItems(st *dataset.Structure) ([]map[string]interface{}, error) {
+	if st == nil || st.Schema == nil {
+		return nil, fmt.Errorf("dataset has no schema")
+	}
+	items, ok := st.Schema["items"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema is not tabular: top level 'items' property must be an object")
+	}
+	itemArr, ok := items["items"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema is not tabular: items.items must be an array")
+	}
+
+	cols := make([]map[string]interface{}, 0, len(itemArr))
+	for i, item := range itemArr {
+		col, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("schema is not tabular: column %d schema should be an object", i)
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
@@ -0,0 +1,296 @@
+package base
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestReadColumnDescriptions(t *testing.T) {
+	data := "column,description\ncity,name of the city\npop, \"population, in people\"\n"
+	got, err := ReadColumnDescriptions(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{
+		"city": "name of the city",
+		"pop":  "population, in people",
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := ReadColumnDescriptions(strings.NewReader("city,name,extra\n")); err == nil {
+		t.Error("expected rows with the wrong number of fields to error")
+	}
+}
+
+func TestSetColumnDescriptions(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "csv",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "pop", "type": "integer"},
+				},
+			},
+		},
+	}
+
+	unknown, err := SetColumnDescriptions(st, map[string]string{
+		"city":    "name of the city",
+		"zebra":   "not a column",
+		"missing": "also not a column",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"missing", "zebra"}, unknown); diff != "" {
+		t.Errorf("unknown columns mismatch (-want +got):\n%s", diff)
+	}
+
+	expect := []interface{}{
+		map[string]interface{}{"title": "city", "type": "string", "description": "name of the city"},
+		map[string]interface{}{"title": "pop", "type": "integer"},
+	}
+	got := st.Schema["items"].(map[string]interface{})["items"]
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("schema mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := SetColumnDescriptions(&dataset.Structure{Schema: map[string]interface{}{"type": "object"}}, nil); err == nil {
+		t.Error("expected non-tabular schema to error")
+	}
+}
+
+func TestSetColumnMeta(t *testing.T) {
+	st := &dataset.Structure{
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "amount", "type": "number", "description": "total"},
+				},
+			},
+		},
+	}
+	if err := SetColumnMeta(st, "amount", ColumnMeta{Unit: "USD", Example: "12.50"}); err != nil {
+		t.Fatal(err)
+	}
+	expect := []interface{}{
+		map[string]interface{}{"title": "amount", "type": "number", "description": "total", "unit": "USD", "examples": []interface{}{"12.50"}},
+	}
+	got := st.Schema["items"].(map[string]interface{})["items"]
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("schema mismatch (-want +got):\n%s", diff)
+	}
+
+	objSt := &dataset.Structure{
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"height": map[string]interface{}{"type": "number"}},
+			},
+		},
+	}
+	if err := SetColumnMeta(objSt, "height", ColumnMeta{Unit: "meters"}); err != nil {
+		t.Fatal(err)
+	}
+	props := objSt.Schema["items"].(map[string]interface{})["properties"].(map[string]interface{})
+	if diff := cmp.Diff(map[string]interface{}{"type": "number", "unit": "meters"}, props["height"]); diff != "" {
+		t.Errorf("property schema mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := SetColumnMeta(st, "missing", ColumnMeta{Unit: "USD"}); err == nil || err.Error() != `column "missing" not found` {
+		t.Errorf("expected missing column error, got: %v", err)
+	}
+	if err := SetColumnMeta(st, "amount", ColumnMeta{}); err == nil {
+		t.Error("expected empty column metadata to error")
+	}
+}
+
+func TestWidenSchema(t *testing.T) {
+	tabular := func(cols ...interface{}) *dataset.Structure {
+		return &dataset.Structure{
+			Format: "csv",
+			Schema: map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type":  "array",
+					"items": cols,
+				},
+			},
+		}
+	}
+	prev := tabular(
+		map[string]interface{}{"title": "city", "type": "string", "description": "name of the city"},
+		map[string]interface{}{"title": "pop", "type": "integer"},
+	)
+	body := tabular(
+		map[string]interface{}{"title": "city", "type": "string"},
+		map[string]interface{}{"title": "pop", "type": "integer"},
+		map[string]interface{}{"title": "area", "type": "number"},
+	)
+
+	schema, added, err := WidenSchema(prev, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"area"}, added); diff != "" {
+		t.Errorf("added columns mismatch (-want +got):\n%s", diff)
+	}
+	expect := []interface{}{
+		map[string]interface{}{"title": "city", "type": "string", "description": "name of the city"},
+		map[string]interface{}{"title": "pop", "type": "integer"},
+		map[string]interface{}{"title": "area", "type": []interface{}{"number", "null"}},
+	}
+	if diff := cmp.Diff(expect, schema["items"].(map[string]interface{})["items"]); diff != "" {
+		t.Errorf("schema mismatch (-want +got):\n%s", diff)
+	}
+	if len(prev.Schema["items"].(map[string]interface{})["items"].([]interface{})) != 2 {
+		t.Error("expected previous schema to be left unchanged")
+	}
+
+	narrower := tabular(map[string]interface{}{"title": "city", "type": "string"})
+	_, _, err = WidenSchema(prev, narrower)
+	expectErr := "body is missing columns: pop. removing columns requires saving an explicit schema"
+	if err == nil || err.Error() != expectErr {
+		t.Errorf("error mismatch. want: %q, got: %v", expectErr, err)
+	}
+
+	if _, _, err := WidenSchema(prev, &dataset.Structure{Schema: dataset.BaseSchemaArray}); err == nil {
+		t.Error("expected a body without columns to error")
+	}
+}
+
+func TestRenameColumn(t *testing.T) {
+	tabular := func() *dataset.Structure {
+		return &dataset.Structure{
+			Format: "csv",
+			Schema: map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "array",
+					"items": []interface{}{
+						map[string]interface{}{"title": "city", "type": "string"},
+						map[string]interface{}{"title": "pop", "type": "integer"},
+					},
+				},
+			},
+		}
+	}
+
+	st := tabular()
+	objectRows, err := RenameColumn(st, "pop", "population")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objectRows {
+		t.Errorf("expected tabular schema to not have object rows")
+	}
+	expect := []interface{}{
+		map[string]interface{}{"title": "city", "type": "string"},
+		map[string]interface{}{"title": "population", "type": "integer"},
+	}
+	got := st.Schema["items"].(map[string]interface{})["items"]
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("schema mismatch (-want +got):\n%s", diff)
+	}
+
+	bad := []struct {
+		from, to, err string
+	}{
+		{"pop", "city", `column "city" already exists`},
+		{"zebra", "stripes", `column "zebra" not found`},
+		{"pop", "pop", `column is already named "pop"`},
+		{"", "pop", "column names cannot be empty"},
+	}
+	for _, c := range bad {
+		_, err := RenameColumn(tabular(), c.from, c.to)
+		if err == nil || err.Error() != c.err {
+			t.Errorf("rename %q to %q: error mismatch. want: %q, got: %v", c.from, c.to, c.err, err)
+		}
+	}
+
+	objSt := &dataset.Structure{
+		Format: "json",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"pop"},
+				"properties": map[string]interface{}{
+					"city": map[string]interface{}{"type": "string"},
+					"pop":  map[string]interface{}{"type": "integer"},
+				},
+			},
+		},
+	}
+	if objectRows, err = RenameColumn(objSt, "pop", "population"); err != nil {
+		t.Fatal(err)
+	}
+	if !objectRows {
+		t.Errorf("expected object row schema to report object rows")
+	}
+	expectItems := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"population"},
+		"properties": map[string]interface{}{
+			"city":       map[string]interface{}{"type": "string"},
+			"population": map[string]interface{}{"type": "integer"},
+		},
+	}
+	if diff := cmp.Diff(expectItems, objSt.Schema["items"]); diff != "" {
+		t.Errorf("object schema mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRenameBodyKeys(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "json",
+		Schema: dataset.BaseSchemaArray,
+	}
+	body := qfs.NewMemfileBytes("body.json", []byte(`[{"city":"toronto","pop":40000000},{"city":"chicago","pop":300000}]`))
+
+	f, err := RenameBodyKeys(st, body, "pop", "population")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `[{"city":"toronto","population":40000000},{"city":"chicago","population":300000}]`
+	if diff := cmp.Diff(expect, string(data)); diff != "" {
+		t.Errorf("body mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRenameBodyKeysErrors(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "json",
+		Schema: dataset.BaseSchemaArray,
+	}
+	cases := []struct {
+		from, to, err string
+	}{
+		{"zebra", "stripes", `column "zebra" not found`},
+		{"pop", "city", `column "city" already exists`},
+	}
+	for _, c := range cases {
+		body := qfs.NewMemfileBytes("body.json", []byte(`[{"city":"toronto","pop":40000000}]`))
+		_, err := RenameBodyKeys(st, body, c.from, c.to)
+		if err == nil || err.Error() != c.err {
+			t.Errorf("rename %q to %q: error mismatch. want: %q, got: %v", c.from, c.to, c.err, err)
+		}
+	}
+}
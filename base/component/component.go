@@ -139,6 +139,8 @@ type BaseComponent struct {
 	Subcomponents  map[string]Component
 	ProblemKind    string
 	ProblemMessage string
+	// ParseErr describes the problem when the source file failed to parse
+	ParseErr *ParseError
 	// File information:
 	ModTime    time.Time
 	SourceFile string
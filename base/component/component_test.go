@@ -1,6 +1,9 @@
 package component
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/qri-io/dataset"
@@ -76,3 +79,39 @@ func TestToDataset(t *testing.T) {
 		t.Errorf("expected commit.message \"%s\", got \"%s\"", "test", ds.Commit.Message)
 	}
 }
+
+func TestLoadFileParseError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "component_parse_error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cases := []struct {
+		format, data string
+		line, col    int
+	}{
+		{"json", "{\n  \"title\": \"a\",\n  \"description\" \"b\"\n}", 3, 17},
+		{"yaml", "title: a\ndescription: b: c\n", 2, 0},
+	}
+	for _, c := range cases {
+		path := filepath.Join(dir, "meta."+c.format)
+		if err := ioutil.WriteFile(path, []byte(c.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		bc := &BaseComponent{SourceFile: path, Format: c.format}
+		if _, err := bc.LoadFile(); err == nil {
+			t.Fatalf("%s: expected parse error, got nil", c.format)
+		}
+		pe := bc.ParseErr
+		if pe == nil {
+			t.Fatalf("%s: expected ParseErr to be set", c.format)
+		}
+		if pe.Line != c.line || pe.Column != c.col {
+			t.Errorf("%s: expected position %d:%d, got %d:%d", c.format, c.line, c.col, pe.Line, pe.Column)
+		}
+		if bc.ProblemKind != "parse" {
+			t.Errorf("%s: expected problem kind %q, got %q", c.format, "parse", bc.ProblemKind)
+		}
+	}
+}
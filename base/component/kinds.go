@@ -768,14 +768,12 @@ func (bc *BaseComponent) LoadFile() (map[string]interface{}, error) {
 	switch bc.Format {
 	case "json":
 		if err = json.Unmarshal(data, &fields); err != nil {
-			bc.SetErrorAsProblem("parse", err)
-			return nil, err
+			return nil, bc.setParseError(data, err)
 		}
 		return fields, nil
 	case "yaml":
 		if err = yaml.Unmarshal(data, &fields); err != nil {
-			bc.SetErrorAsProblem("parse", err)
-			return nil, err
+			return nil, bc.setParseError(data, err)
 		}
 		return fields, nil
 	case "html", "md", "star":
@@ -791,6 +789,14 @@ func (bc *BaseComponent) SetErrorAsProblem(kind string, err error) {
 	bc.ProblemMessage = err.Error()
 }
 
+// setParseError records an error parsing the source file as a problem,
+// returning the error with the position it occurred at
+func (bc *BaseComponent) setParseError(data []byte, err error) error {
+	bc.ParseErr = newParseError(bc.SourceFile, bc.Format, data, err)
+	bc.SetErrorAsProblem("parse", bc.ParseErr)
+	return bc.ParseErr
+}
+
 // GetSubcomponent returns the component with the given name
 func (bc *BaseComponent) GetSubcomponent(name string) Component {
 	return bc.Subcomponents[name]
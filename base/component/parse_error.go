@@ -0,0 +1,78 @@
+package component
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParseError describes a component file that failed to parse. Line & Column
+// are 1-indexed, and are zero when the parser doesn't report a position. YAML
+// errors only report a line
+type ParseError struct {
+	SourceFile string `json:"sourceFile"`
+	Format     string `json:"format"`
+	Line       int    `json:"line,omitempty"`
+	Column     int    `json:"column,omitempty"`
+	Message    string `json:"message"`
+}
+
+// Error implements the error interface
+func (e *ParseError) Error() string {
+	pos := e.SourceFile
+	if e.Line > 0 {
+		pos += fmt.Sprintf(":%d", e.Line)
+		if e.Column > 0 {
+			pos += fmt.Sprintf(":%d", e.Column)
+		}
+	}
+	return fmt.Sprintf("%s: %s", pos, e.Message)
+}
+
+// yamlLineRe matches the line number yaml errors lead with,
+// eg: "yaml: line 3: mapping values are not allowed in this context"
+var yamlLineRe = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// newParseError builds a ParseError from an error returned parsing data
+func newParseError(sourceFile, format string, data []byte, err error) *ParseError {
+	pe := &ParseError{
+		SourceFile: sourceFile,
+		Format:     format,
+		Message:    err.Error(),
+	}
+
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		pe.Line, pe.Column = offsetPosition(data, syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		pe.Line, pe.Column = offsetPosition(data, typeErr.Offset)
+	default:
+		if m := yamlLineRe.FindStringSubmatch(pe.Message); m != nil {
+			pe.Line, _ = strconv.Atoi(m[1])
+			pe.Message = strings.TrimPrefix(pe.Message, m[0])
+		}
+	}
+	return pe
+}
+
+// offsetPosition converts a byte offset into a 1-indexed line & column. json
+// errors report the offset just past the byte that caused the error
+func offsetPosition(data []byte, offset int64) (line, col int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	if offset > 0 {
+		offset--
+	}
+	before := data[:offset]
+	line = 1 + strings.Count(string(before), "\n")
+	col = int(offset) - strings.LastIndex(string(before), "\n")
+	return line, col
+}
@@ -0,0 +1,217 @@
+package base
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base/dsfs"
+)
+
+// ComponentHashes hashes the content of each component of a dataset
+// separately, returning hex-encoded sha256 digests keyed by component name:
+// body, meta, schema, transform, readme & viz. Like Fingerprint, digests are
+// taken over a canonical encoding of decoded content, so they don't change if
+// a version is re-stored with different chunking or encoding. Components are
+// read from their files if set, or from fs by path otherwise. The body is
+// hashed one entry at a time & its file is consumed
+func ComponentHashes(ctx context.Context, fs qfs.Filesystem, ds *dataset.Dataset) (map[string]string, error) {
+	hashes, err := metaComponentHashes(ctx, fs, ds)
+	if err != nil {
+		return nil, err
+	}
+
+	f := ds.BodyFile()
+	if f == nil {
+		if ds.BodyPath == "" || fs == nil {
+			return hashes, nil
+		}
+		if f, err = dsfs.LoadBody(ctx, fs, ds); err != nil {
+			return nil, fmt.Errorf("reading body: %w", err)
+		}
+	}
+	defer f.Close()
+	if ds.Structure == nil {
+		return nil, fmt.Errorf("a structure is required to read the body")
+	}
+	h := sha256.New()
+	if err := writeFingerprintBody(h, ds.Structure, f); err != nil {
+		return nil, fmt.Errorf("reading body: %w", err)
+	}
+	hashes["body"] = hex.EncodeToString(h.Sum(nil))
+	return hashes, nil
+}
+
+// metaComponentHashes hashes every component except the body. script files
+// are buffered & replaced, so ds can still be saved afterward
+func metaComponentHashes(ctx context.Context, fs qfs.Filesystem, ds *dataset.Dataset) (map[string]string, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("can't hash components of a nil dataset")
+	}
+
+	// fingerprintComponents consumes script files, give it a copy of each
+	// component with a buffered script
+	content := &dataset.Dataset{Meta: ds.Meta, Structure: ds.Structure}
+	if ds.Transform != nil {
+		data, err := bufferScript(ctx, fs, ds.Transform.ScriptFile(), ds.Transform.ScriptPath, ds.Transform.SetScriptFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading transform script: %w", err)
+		}
+		content.Transform = &dataset.Transform{}
+		content.Transform.Assign(ds.Transform)
+		content.Transform.SetScriptFile(qfs.NewMemfileBytes("transform.star", data))
+	}
+	if ds.Readme != nil {
+		data, err := bufferScript(ctx, fs, ds.Readme.ScriptFile(), ds.Readme.ScriptPath, ds.Readme.SetScriptFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading readme: %w", err)
+		}
+		content.Readme = &dataset.Readme{}
+		content.Readme.Assign(ds.Readme)
+		content.Readme.SetScriptFile(qfs.NewMemfileBytes("readme.md", data))
+	}
+	if ds.Viz != nil {
+		data, err := bufferScript(ctx, fs, ds.Viz.ScriptFile(), ds.Viz.ScriptPath, ds.Viz.SetScriptFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading viz script: %w", err)
+		}
+		content.Viz = &dataset.Viz{}
+		content.Viz.Assign(ds.Viz)
+		content.Viz.SetScriptFile(qfs.NewMemfileBytes("template.html", data))
+	}
+
+	doc, err := fingerprintComponents(content)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make(map[string]string, len(doc)+1)
+	for name, val := range doc {
+		data, err := json.Marshal(val)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		hashes[name] = hex.EncodeToString(sum[:])
+	}
+	return hashes, nil
+}
+
+// hashComponents records the component hashes of a version being saved in
+// its provenance. a new body is hashed as it's written, so it's only read once
+func hashComponents(ctx context.Context, fs qfs.Filesystem, ds *dataset.Dataset, sw *SaveSwitches) error {
+	hashes, err := metaComponentHashes(ctx, fs, ds)
+	if err != nil {
+		return err
+	}
+	if body := ds.BodyFile(); body != nil {
+		if ds.Structure == nil {
+			return fmt.Errorf("a structure is required to read the body")
+		}
+		ds.SetBodyFile(newHashingBodyFile(ds.Structure, body, hashes))
+	} else if ds.BodyPath != "" {
+		bodyHashes, err := ComponentHashes(ctx, fs, &dataset.Dataset{Structure: ds.Structure, BodyPath: ds.BodyPath})
+		if err != nil {
+			return err
+		}
+		hashes["body"] = bodyHashes["body"]
+	}
+
+	prov := &dsfs.Provenance{}
+	if sw.Provenance != nil {
+		*prov = *sw.Provenance
+	}
+	prov.ComponentHashes = hashes
+	sw.Provenance = prov
+	return nil
+}
+
+// bufferScript reads a script from its file, replacing the file with an
+// in-memory copy, or from fs by path if the file isn't set
+func bufferScript(ctx context.Context, fs qfs.Filesystem, f qfs.File, path string, set func(qfs.File)) ([]byte, error) {
+	if f == nil {
+		if path == "" || fs == nil {
+			return nil, nil
+		}
+		var err error
+		if f, err = fs.Get(ctx, path); err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return ioutil.ReadAll(f)
+	}
+	defer f.Close()
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	set(qfs.NewMemfileBytes(f.FileName(), data))
+	return data, nil
+}
+
+// hashingBodyFile hashes a body as it's read, adding the body digest to
+// hashes once the body has been read to the end. a body that fails to
+// decode is reported as a read error
+type hashingBodyFile struct {
+	qfs.File
+	pw     *io.PipeWriter
+	done   chan error
+	once   sync.Once
+	hashes map[string]string
+}
+
+var _ qfs.File = (*hashingBodyFile)(nil)
+
+// newHashingBodyFile wraps a body file so reading it also hashes its content
+func newHashingBodyFile(st *dataset.Structure, f qfs.File, hashes map[string]string) *hashingBodyFile {
+	pr, pw := io.Pipe()
+	hf := &hashingBodyFile{File: f, pw: pw, done: make(chan error, 1), hashes: hashes}
+	go func() {
+		h := sha256.New()
+		err := writeFingerprintBody(h, st, pr)
+		// drain anything the decoder didn't read so writes never block
+		io.Copy(ioutil.Discard, pr)
+		if err == nil {
+			hashes["body"] = hex.EncodeToString(h.Sum(nil))
+		}
+		hf.done <- err
+	}()
+	return hf
+}
+
+// Read implements the io.Reader interface
+func (hf *hashingBodyFile) Read(p []byte) (int, error) {
+	n, err := hf.File.Read(p)
+	if n > 0 {
+		if _, werr := hf.pw.Write(p[:n]); werr != nil {
+			return n, fmt.Errorf("hashing body: %w", werr)
+		}
+	}
+	if err == io.EOF {
+		if herr := hf.finish(); herr != nil {
+			return n, fmt.Errorf("hashing body: %w", herr)
+		}
+	}
+	return n, err
+}
+
+// Close implements the io.Closer interface
+func (hf *hashingBodyFile) Close() error {
+	hf.pw.CloseWithError(fmt.Errorf("body closed before it was read"))
+	return hf.File.Close()
+}
+
+// finish waits for hashing to complete once the body has been read
+func (hf *hashingBodyFile) finish() (err error) {
+	hf.once.Do(func() {
+		hf.pw.Close()
+		err = <-hf.done
+	})
+	return err
+}
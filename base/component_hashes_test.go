@@ -0,0 +1,89 @@
+package base
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base/dsfs"
+)
+
+func TestComponentHashes(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+
+	ds := run.BuildDataset("hashed", "json")
+	ds.Meta = &dataset.Meta{Title: "hashed dataset"}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(`[1,2,3]`)))
+	ref, err := run.saveDataset(ds, SaveSwitches{HashComponents: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := dsfs.LoadDataset(run.Context, run.Repo.Filesystem(), ref.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Commit.Message != "created dataset" {
+		t.Errorf("expected hashes to stay out of the commit message, got: %q", saved.Commit.Message)
+	}
+	prov, err := dsfs.LoadProvenance(run.Context, run.Repo.Filesystem(), ref.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := prov.ComponentHashes
+	for _, name := range []string{"body", "meta", "schema"} {
+		if recorded[name] == "" {
+			t.Errorf("expected a %s hash to be recorded, got: %v", name, recorded)
+		}
+	}
+
+	got, err := ComponentHashes(run.Context, run.Repo.Filesystem(), saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(recorded, got); diff != "" {
+		t.Errorf("stored components don't match recorded hashes (-recorded +got):\n%s", diff)
+	}
+
+	saved, err = dsfs.LoadDataset(run.Context, run.Repo.Filesystem(), ref.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved.Meta.Title = "tampered"
+	if got, err = ComponentHashes(run.Context, run.Repo.Filesystem(), saved); err != nil {
+		t.Fatal(err)
+	}
+	if got["meta"] == recorded["meta"] {
+		t.Errorf("expected changed meta to change the meta hash")
+	}
+	if got["body"] != recorded["body"] {
+		t.Errorf("expected body hash to be unchanged")
+	}
+	// a body that isn't changing is hashed from the previous version
+	ds = &dataset.Dataset{Peername: ds.Peername, Name: ds.Name, Meta: &dataset.Meta{Title: "retitled"}}
+	next, err := run.saveDataset(ds, SaveSwitches{HashComponents: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prov, err = dsfs.LoadProvenance(run.Context, run.Repo.Filesystem(), next.Path); err != nil {
+		t.Fatal(err)
+	}
+	if prov.ComponentHashes["body"] != recorded["body"] {
+		t.Errorf("expected unchanged body hash to be recorded, got: %v", prov.ComponentHashes)
+	}
+}
+
+func TestHashingBodyFileReadError(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	hashes := map[string]string{}
+	f := newHashingBodyFile(st, qfs.NewMemfileBytes("body.json", []byte(`[1,2,`)), hashes)
+	if _, err := ioutil.ReadAll(f); err == nil {
+		t.Error("expected reading a body that can't be decoded to error")
+	}
+	if _, ok := hashes["body"]; ok {
+		t.Error("expected no body hash for a body that can't be decoded")
+	}
+}
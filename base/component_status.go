@@ -3,6 +3,7 @@ package base
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -37,20 +38,38 @@ var (
 type StatusItem struct {
 	Component string `json:"component"`
 	Type      string `json:"type"`
+	// Message describes the problem with a component that has one
+	Message string `json:"message,omitempty"`
+	// ParseError is set when the component file failed to parse
+	ParseError *component.ParseError `json:"parseError,omitempty"`
 }
 
 // MarshalJSON marshals a StatusItem
 func (si StatusItem) MarshalJSON() ([]byte, error) {
 	obj := struct {
-		Component string `json:"component"`
-		Type      string `json:"type"`
+		Component  string                `json:"component"`
+		Type       string                `json:"type"`
+		Message    string                `json:"message,omitempty"`
+		ParseError *component.ParseError `json:"parseError,omitempty"`
 	}{
-		Component: si.Component,
-		Type:      si.Type,
+		Component:  si.Component,
+		Type:       si.Type,
+		Message:    si.Message,
+		ParseError: si.ParseError,
 	}
 	return json.Marshal(obj)
 }
 
+// problemStatus describes a component with a problem
+func problemStatus(name string, comp component.Component) StatusItem {
+	return StatusItem{
+		Component:  name,
+		Type:       comp.Base().ProblemKind,
+		Message:    comp.Base().ProblemMessage,
+		ParseError: comp.Base().ParseErr,
+	}
+}
+
 // ComponentStatus owns functionality to get change status about components
 type ComponentStatus struct {
 	fs *muxfs.Mux
@@ -105,6 +124,30 @@ func (cs *ComponentStatus) WhatChanged(ctx context.Context, ref dsref.Ref) (chan
 	return changes, nil
 }
 
+// DirectoryStatus compares the component files in a directory to a stored
+// version of a dataset, listing how each component in the directory differs
+// from the version at path. Files are only read, never written
+func (cs *ComponentStatus) DirectoryStatus(ctx context.Context, dir, path string) ([]StatusItem, error) {
+	ds, err := dsfs.LoadDataset(ctx, cs.fs, path)
+	if err != nil {
+		return nil, err
+	}
+	stored := component.ConvertDatasetToComponents(ds, cs.fs)
+	stored.Base().RemoveSubcomponent("commit")
+	stored.DropDerivedValues()
+
+	working, err := component.ListDirectoryComponents(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := component.ExpandListedComponents(working, cs.fs); err != nil {
+		return nil, err
+	}
+	working.Base().RemoveSubcomponent("commit")
+
+	return cs.calculateStateTransition(ctx, stored, working)
+}
+
 // calculateStateTransition calculates the differences between two versions of a dataset.
 func (cs *ComponentStatus) calculateStateTransition(ctx context.Context, prev, next component.Component) (changes []StatusItem, err error) {
 
@@ -113,10 +156,7 @@ func (cs *ComponentStatus) calculateStateTransition(ctx context.Context, prev, n
 	// See if the dataset itself has a problem.
 	dsComp := next.Base().GetSubcomponent("dataset")
 	if dsComp != nil && dsComp.Base().ProblemKind != "" {
-		changes = append(changes, StatusItem{
-			Component: "dataset",
-			Type:      dsComp.Base().ProblemKind,
-		})
+		changes = append(changes, problemStatus("dataset", dsComp))
 	}
 
 	for _, compName := range component.AllSubcomponentNames() {
@@ -125,10 +165,7 @@ func (cs *ComponentStatus) calculateStateTransition(ctx context.Context, prev, n
 
 		// Next component might have a problem, such as a parse error, or permission problem.
 		if nextComp != nil && nextComp.Base().ProblemKind != "" {
-			changes = append(changes, StatusItem{
-				Component: compName,
-				Type:      nextComp.Base().ProblemKind,
-			})
+			changes = append(changes, problemStatus(compName, nextComp))
 			continue
 		}
 
@@ -153,10 +190,13 @@ func (cs *ComponentStatus) calculateStateTransition(ctx context.Context, prev, n
 
 		isEqual, err := prevComp.Compare(nextComp)
 		if err != nil {
-			changes = append(changes, StatusItem{
+			item := StatusItem{
 				Component: compName,
 				Type:      STParseError,
-			})
+				Message:   err.Error(),
+			}
+			errors.As(err, &item.ParseError)
+			changes = append(changes, item)
 			continue
 		}
 
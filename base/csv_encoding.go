@@ -0,0 +1,77 @@
+package base
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+)
+
+// UTF8BOM is the byte order mark spreadsheet programs like Excel look for to
+// read a CSV file as UTF-8
+var UTF8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// CSVEncoding configures the bytes of CSV output for programs that expect
+// more than plain UTF-8 with LF line endings. The zero value changes nothing
+type CSVEncoding struct {
+	// prepend a UTF-8 byte order mark
+	BOM bool
+	// end lines with CRLF instead of LF, including line breaks within quoted
+	// fields, matching encoding/csv's UseCRLF
+	CRLF bool
+}
+
+// Encode applies the encoding to CSV data written by encoding/csv. With CRLF
+// set, records are read & written again with a csv.Writer that uses CRLF,
+// so line breaks are only changed where the CSV syntax allows
+func (e CSVEncoding) Encode(data []byte) ([]byte, error) {
+	if !e.BOM && !e.CRLF {
+		return data, nil
+	}
+	data = bytes.TrimPrefix(data, UTF8BOM)
+	buf := bytes.NewBuffer(make([]byte, 0, len(data)+len(UTF8BOM)))
+	e.writeBOM(buf)
+	if !e.CRLF {
+		buf.Write(data)
+		return buf.Bytes(), nil
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	w := e.newWriter(buf)
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if err := w.Write(rec); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// newWriter allocates a csv writer that ends lines as the encoding requires.
+// the byte order mark isn't written, see writeBOM
+func (e CSVEncoding) newWriter(w io.Writer) *csv.Writer {
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = e.CRLF
+	return cw
+}
+
+// writeBOM writes the byte order mark if the encoding has one
+func (e CSVEncoding) writeBOM(buf *bytes.Buffer) {
+	if e.BOM {
+		buf.Write(UTF8BOM)
+	}
+}
+
+// lineEnd is the text that ends lines
+func (e CSVEncoding) lineEnd() string {
+	if e.CRLF {
+		return "\r\n"
+	}
+	return "\n"
+}
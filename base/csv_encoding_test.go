@@ -0,0 +1,40 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCSVEncoding(t *testing.T) {
+	data := []byte("city,note\ntoronto,\"two\nlines\"\nchatham,\"say \"\"hi\"\"\"\r\n")
+
+	cases := []struct {
+		description string
+		enc         CSVEncoding
+		expect      string
+	}{
+		{"zero value", CSVEncoding{}, string(data)},
+		{"bom", CSVEncoding{BOM: true}, "\xEF\xBB\xBF" + string(data)},
+		{"crlf", CSVEncoding{CRLF: true},
+			"city,note\r\ntoronto,\"two\r\nlines\"\r\nchatham,\"say \"\"hi\"\"\"\r\n"},
+		{"bom & crlf", CSVEncoding{BOM: true, CRLF: true},
+			"\xEF\xBB\xBFcity,note\r\ntoronto,\"two\r\nlines\"\r\nchatham,\"say \"\"hi\"\"\"\r\n"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			got, err := c.enc.Encode(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(c.expect, string(got)); diff != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := (CSVEncoding{CRLF: true}).Encode([]byte("a,\"b\n")); err == nil {
+		t.Error("expected malformed csv to error")
+	}
+}
@@ -0,0 +1,148 @@
+package base
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/tabular"
+)
+
+// NullCSVBody reads a dataset body & writes it as CSV that keeps null values
+// apart from empty strings, see NullCSV. The header row is written if the
+// dataset is a CSV with a header row, matching ReadBodyBytes
+func NullCSVBody(ds *dataset.Dataset, limit, offset int, all bool, null string, enc CSVEncoding) ([]byte, error) {
+	body, err := GetBody(ds, limit, offset, all)
+	if err != nil {
+		return nil, err
+	}
+	rows, ok := body.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("csv output requires a body that is an array of rows")
+	}
+
+	var header []string
+	if ds.Structure != nil && dsio.HasHeaderRow(ds.Structure) {
+		if cols, _, err := tabular.ColumnsFromJSONSchema(ds.Structure.Schema); err == nil {
+			header = cols.Titles()
+		}
+	}
+	return NullCSV(header, rows, null, enc)
+}
+
+// NullCSV writes rows as CSV, using the type of each value to tell nulls apart
+// from empty strings. nulls are written as the unquoted null token, which may
+// be empty. Empty strings & strings that equal the null token are always
+// quoted. Each row must be an array of values, nested values are written as
+// JSON. header is written as the first row if it isn't empty
+func NullCSV(header []string, rows []interface{}, null string, enc CSVEncoding) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc.writeBOM(buf)
+	eol := enc.lineEnd()
+	if len(header) > 0 {
+		vals := make([]interface{}, len(header))
+		for i, h := range header {
+			vals[i] = h
+		}
+		if err := writeNullCSVRecord(buf, vals, null, eol); err != nil {
+			return nil, err
+		}
+	}
+	for i, r := range rows {
+		row, ok := r.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("row %d: expected array value to write csv row, got %T", i+1, r)
+		}
+		if err := writeNullCSVRecord(buf, row, null, eol); err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// ColumnsNullCSV encodes rows returned by ReadColumns as CSV with a header row
+// of column names, keeping null values apart from empty strings like NullCSV
+func ColumnsNullCSV(columns []string, rows []interface{}, null string, enc CSVEncoding) ([]byte, error) {
+	vals := make([]interface{}, len(rows))
+	for i, row := range rows {
+		switch r := row.(type) {
+		case []interface{}:
+			if len(r) != len(columns) {
+				return nil, fmt.Errorf("row has %d values, expected %d", len(r), len(columns))
+			}
+			vals[i] = r
+		case map[string]interface{}:
+			rec := make([]interface{}, len(columns))
+			for j, name := range columns {
+				rec[j] = r[name]
+			}
+			vals[i] = rec
+		default:
+			return nil, fmt.Errorf("unexpected row type %T", row)
+		}
+	}
+	return NullCSV(columns, vals, null, enc)
+}
+
+func writeNullCSVRecord(buf *bytes.Buffer, row []interface{}, null, eol string) error {
+	for i, v := range row {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if v == nil {
+			buf.WriteString(null)
+			continue
+		}
+
+		var s string
+		switch x := v.(type) {
+		case string:
+			s = x
+			if s == "" || s == null {
+				writeQuotedCSVField(buf, s, eol)
+				continue
+			}
+		case float64:
+			s = strconv.FormatFloat(x, 'f', -1, 64)
+		case map[string]interface{}, []interface{}:
+			data, err := json.Marshal(x)
+			if err != nil {
+				return err
+			}
+			s = string(data)
+		default:
+			s = fmt.Sprintf("%v", x)
+		}
+
+		if csvFieldNeedsQuotes(s) {
+			writeQuotedCSVField(buf, s, eol)
+		} else {
+			buf.WriteString(s)
+		}
+	}
+	buf.WriteString(eol)
+	return nil
+}
+
+// csvFieldNeedsQuotes matches the quoting rules of encoding/csv
+func csvFieldNeedsQuotes(s string) bool {
+	if s == `\.` {
+		return true
+	}
+	return strings.ContainsAny(s, ",\"\r\n") || s[0] == ' ' || s[0] == '\t'
+}
+
+// writeQuotedCSVField quotes a field. like encoding/csv with UseCRLF, line
+// breaks in the field become CRLF when eol is CRLF
+func writeQuotedCSVField(buf *bytes.Buffer, s, eol string) {
+	if eol == "\r\n" {
+		s = strings.ReplaceAll(strings.ReplaceAll(s, "\r", ""), "\n", eol)
+	}
+	buf.WriteByte('"')
+	buf.WriteString(strings.ReplaceAll(s, `"`, `""`))
+	buf.WriteByte('"')
+}
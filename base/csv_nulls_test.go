@@ -0,0 +1,73 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNullCSV(t *testing.T) {
+	rows := []interface{}{
+		[]interface{}{"a", "", nil, int64(1)},
+		[]interface{}{`\N`, "b,c", 1.5, true},
+		[]interface{}{nil, `say "hi"`, []interface{}{"x"}, false},
+	}
+
+	cases := []struct {
+		description string
+		header      []string
+		null        string
+		expect      string
+	}{
+		{"empty null token", nil, "",
+			"a,\"\",,1\n\\N,\"b,c\",1.5,true\n,\"say \"\"hi\"\"\",\"[\"\"x\"\"]\",false\n"},
+		{"null token", []string{"one", "two", "three", "four"}, `\N`,
+			"one,two,three,four\na,\"\",\\N,1\n\"\\N\",\"b,c\",1.5,true\n\\N,\"say \"\"hi\"\"\",\"[\"\"x\"\"]\",false\n"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			got, err := NullCSV(c.header, rows, c.null, CSVEncoding{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(c.expect, string(got)); diff != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := NullCSV(nil, []interface{}{map[string]interface{}{"a": 1}}, "", CSVEncoding{}); err == nil {
+		t.Error("expected object rows to error")
+	}
+}
+
+func TestColumnsNullCSV(t *testing.T) {
+	rows := []interface{}{
+		map[string]interface{}{"name": "", "count": nil},
+		[]interface{}{"x", int64(2)},
+	}
+	got, err := ColumnsNullCSV([]string{"name", "count"}, rows, "NULL", CSVEncoding{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "name,count\n\"\",NULL\nx,2\n"
+	if diff := cmp.Diff(expect, string(got)); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNullCSVEncoding(t *testing.T) {
+	rows := []interface{}{
+		[]interface{}{"", nil},
+		[]interface{}{"two\nlines", "x"},
+	}
+	got, err := NullCSV([]string{"a", "b"}, rows, "", CSVEncoding{BOM: true, CRLF: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "\xEF\xBB\xBFa,b\r\n\"\",\r\n\"two\r\nlines\",x\r\n"
+	if diff := cmp.Diff(expect, string(got)); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -26,6 +26,15 @@ func OpenDataset(ctx context.Context, fsys qfs.Filesystem, ds *dataset.Dataset)
 			log.Debug(err)
 			return fmt.Errorf("opening body file: %w", err)
 		}
+		// bodies in registered formats are stored as JSON
+		body, err := DecodeBodyFile(ds.BodyFile())
+		if err != nil {
+			return err
+		}
+		ds.SetBodyFile(body)
+		if err := InferTSVStructure(ds); err != nil {
+			return err
+		}
 	}
 	if ds.Transform != nil && ds.Transform.ScriptFile() == nil {
 		if err = ds.Transform.OpenScriptFile(ctx, fsys); err != nil {
@@ -0,0 +1,215 @@
+package base
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/qfs"
+)
+
+// dbDriverNames maps connection string schemes to the names database drivers
+// register with database/sql. schemes that aren't listed are used as the
+// driver name
+var dbDriverNames = map[string]string{
+	"postgres":   "postgres",
+	"postgresql": "postgres",
+	"mysql":      "mysql",
+	"sqlite":     "sqlite3",
+	"sqlite3":    "sqlite3",
+}
+
+// DBBody runs a query against a database, returning a JSON body file &
+// tabular structure holding the result. The database is chosen by the scheme
+// of the connection string, eg. "postgres://user@host/db". The driver for the
+// scheme must be registered with database/sql by the program calling DBBody,
+// the qri command registers a postgres driver. Column types are inferred from
+// the database types of the result columns. Rows are streamed into the
+// returned body file as it's read, the database connection stays open until
+// the file is read to the end or closed
+func DBBody(ctx context.Context, connString, query string) (qfs.File, *dataset.Structure, error) {
+	driverName, dsn, err := dbDriver(connString)
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connecting to database: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("running database query: %w", err)
+	}
+
+	colTypes, err := rows.ColumnTypes()
+	if err != nil {
+		rows.Close()
+		db.Close()
+		return nil, nil, err
+	}
+	types := make([]string, len(colTypes))
+	items := make([]interface{}, len(colTypes))
+	for i, ct := range colTypes {
+		types[i] = dbColumnType(ct.DatabaseTypeName())
+		items[i] = map[string]interface{}{
+			"title": ct.Name(),
+			"type":  []interface{}{types[i], "null"},
+		}
+	}
+	st := &dataset.Structure{
+		Format: dataset.JSONDataFormat.String(),
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":  "array",
+				"items": items,
+			},
+		},
+	}
+
+	r, w := io.Pipe()
+	go func() {
+		defer db.Close()
+		defer rows.Close()
+		w.CloseWithError(writeDBRows(rows, colTypes, types, st, w))
+	}()
+
+	return qfs.NewMemfileReader(st.BodyFilename(), r), st, nil
+}
+
+// writeDBRows writes the rows of a database query result to w as entries of
+// st
+func writeDBRows(rows *sql.Rows, colTypes []*sql.ColumnType, types []string, st *dataset.Structure, w io.Writer) error {
+	ew, err := dsio.NewEntryWriter(st, w)
+	if err != nil {
+		return err
+	}
+	vals := make([]interface{}, len(colTypes))
+	scan := make([]interface{}, len(colTypes))
+	for i := range vals {
+		scan[i] = &vals[i]
+	}
+	for i := 0; rows.Next(); i++ {
+		if err := rows.Scan(scan...); err != nil {
+			return fmt.Errorf("reading row %d: %w", i, err)
+		}
+		row := make([]interface{}, len(vals))
+		for j, v := range vals {
+			if row[j], err = dbValue(v, types[j]); err != nil {
+				return fmt.Errorf("row %d column %q: %w", i, colTypes[j].Name(), err)
+			}
+		}
+		if err := ew.WriteEntry(dsio.Entry{Index: i, Value: row}); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("reading database query result: %w", err)
+	}
+	return ew.Close()
+}
+
+// RedactConnString removes credentials from a database connection string,
+// leaving a string that's safe to record in dataset history
+func RedactConnString(connString string) string {
+	u, err := url.Parse(connString)
+	if err != nil || u.Scheme == "" {
+		return ""
+	}
+	u.User = nil
+	q := u.Query()
+	for key := range q {
+		if k := strings.ToLower(key); k == "password" || k == "user" || k == "username" {
+			q.Del(key)
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// dbDriver picks the database/sql driver & data source name for a connection
+// string
+func dbDriver(connString string) (driverName, dsn string, err error) {
+	u, err := url.Parse(connString)
+	if err != nil || u.Scheme == "" {
+		return "", "", fmt.Errorf("invalid database connection string, expected a URL like postgres://user@host/db")
+	}
+	driverName, ok := dbDriverNames[u.Scheme]
+	if !ok {
+		driverName = u.Scheme
+	}
+	registered := false
+	for _, name := range sql.Drivers() {
+		if name == driverName {
+			registered = true
+			break
+		}
+	}
+	if !registered {
+		return "", "", fmt.Errorf("no database driver for %q is registered, qri must be built with the %s driver", u.Scheme, driverName)
+	}
+
+	// postgres drivers accept URLs, other drivers take the connection string
+	// without the scheme
+	dsn = connString
+	if driverName != "postgres" {
+		dsn = strings.TrimPrefix(connString, u.Scheme+"://")
+	}
+	return driverName, dsn, nil
+}
+
+// dbColumnType maps a database column type name to a JSON schema type
+func dbColumnType(dbType string) string {
+	t := strings.ToUpper(dbType)
+	switch {
+	case strings.Contains(t, "INT") || strings.Contains(t, "SERIAL"):
+		return "integer"
+	case strings.Contains(t, "FLOAT") || strings.Contains(t, "DOUBLE") || strings.Contains(t, "REAL") ||
+		strings.Contains(t, "NUMERIC") || strings.Contains(t, "DECIMAL"):
+		return "number"
+	case strings.HasPrefix(t, "BOOL"):
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// dbValue converts a value scanned from a database to a body value of the
+// column type
+func dbValue(v interface{}, colType string) (interface{}, error) {
+	switch x := v.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return dbTextValue(string(x), colType)
+	case string:
+		return dbTextValue(x, colType)
+	case time.Time:
+		return x.UTC().Format(time.RFC3339Nano), nil
+	default:
+		return x, nil
+	}
+}
+
+// dbTextValue parses values drivers return as text, like numeric columns
+func dbTextValue(s, colType string) (interface{}, error) {
+	switch colType {
+	case "integer":
+		return strconv.ParseInt(s, 10, 64)
+	case "number":
+		return strconv.ParseFloat(s, 64)
+	case "boolean":
+		return strconv.ParseBool(s)
+	default:
+		return s, nil
+	}
+}
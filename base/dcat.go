@@ -0,0 +1,89 @@
+package base
+
+import (
+	"github.com/qri-io/dataset"
+)
+
+// DCATContext is the JSON-LD context of documents created by MetaDCAT,
+// mapping vocabulary prefixes to their namespaces
+var DCATContext = map[string]interface{}{
+	"dcat":   "http://www.w3.org/ns/dcat#",
+	"dct":    "http://purl.org/dc/terms/",
+	"schema": "http://schema.org/",
+}
+
+// MetaDCAT maps a meta component onto a DCAT Dataset JSON-LD document, with
+// schema.org equivalents for harvesters that only read schema.org. Meta
+// fields without a DCAT mapping are omitted
+func MetaDCAT(md *dataset.Meta) map[string]interface{} {
+	doc := map[string]interface{}{
+		"@context": DCATContext,
+		"@type":    []interface{}{"dcat:Dataset", "schema:Dataset"},
+	}
+	if md == nil {
+		return doc
+	}
+
+	setText := func(val string, keys ...string) {
+		if val == "" {
+			return
+		}
+		for _, key := range keys {
+			doc[key] = val
+		}
+	}
+	setList := func(vals []string, keys ...string) {
+		if len(vals) == 0 {
+			return
+		}
+		list := make([]interface{}, len(vals))
+		for i, v := range vals {
+			list[i] = v
+		}
+		for _, key := range keys {
+			doc[key] = list
+		}
+	}
+	setLink := func(url string, keys ...string) {
+		if url == "" {
+			return
+		}
+		for _, key := range keys {
+			doc[key] = map[string]interface{}{"@id": url}
+		}
+	}
+
+	setText(md.Title, "dct:title", "schema:name")
+	setText(md.Description, "dct:description", "schema:description")
+	setText(md.Identifier, "dct:identifier", "schema:identifier")
+	setText(md.AccrualPeriodicity, "dct:accrualPeriodicity")
+	setText(md.Version, "schema:version")
+	setList(md.Keywords, "dcat:keyword", "schema:keywords")
+	setList(md.Language, "dct:language", "schema:inLanguage")
+	setList(md.Theme, "dcat:theme")
+	setLink(md.HomeURL, "dcat:landingPage", "schema:url")
+
+	if md.License != nil {
+		if md.License.URL != "" {
+			setLink(md.License.URL, "dct:license", "schema:license")
+		} else {
+			setText(md.License.Type, "dct:license", "schema:license")
+		}
+	}
+
+	if md.AccessURL != "" || md.DownloadURL != "" {
+		dist := map[string]interface{}{
+			"@type": []interface{}{"dcat:Distribution", "schema:DataDownload"},
+		}
+		if md.AccessURL != "" {
+			dist["dcat:accessURL"] = map[string]interface{}{"@id": md.AccessURL}
+		}
+		if md.DownloadURL != "" {
+			dist["dcat:downloadURL"] = map[string]interface{}{"@id": md.DownloadURL}
+			dist["schema:contentUrl"] = md.DownloadURL
+		}
+		doc["dcat:distribution"] = []interface{}{dist}
+	}
+
+	return doc
+}
@@ -0,0 +1,55 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+)
+
+func TestMetaDCAT(t *testing.T) {
+	md := &dataset.Meta{
+		Title:       "World Bank Population",
+		Description: "population by country",
+		Keywords:    []string{"population", "census"},
+		License:     &dataset.License{Type: "CC-BY-4.0", URL: "https://creativecommons.org/licenses/by/4.0/"},
+		HomeURL:     "https://data.worldbank.org",
+		DownloadURL: "https://data.worldbank.org/pop.csv",
+		ReadmeURL:   "https://data.worldbank.org/readme",
+	}
+
+	expect := map[string]interface{}{
+		"@context":           DCATContext,
+		"@type":              []interface{}{"dcat:Dataset", "schema:Dataset"},
+		"dct:title":          "World Bank Population",
+		"schema:name":        "World Bank Population",
+		"dct:description":    "population by country",
+		"schema:description": "population by country",
+		"dcat:keyword":       []interface{}{"population", "census"},
+		"schema:keywords":    []interface{}{"population", "census"},
+		"dct:license":        map[string]interface{}{"@id": "https://creativecommons.org/licenses/by/4.0/"},
+		"schema:license":     map[string]interface{}{"@id": "https://creativecommons.org/licenses/by/4.0/"},
+		"dcat:landingPage":   map[string]interface{}{"@id": "https://data.worldbank.org"},
+		"schema:url":         map[string]interface{}{"@id": "https://data.worldbank.org"},
+		"dcat:distribution": []interface{}{
+			map[string]interface{}{
+				"@type":             []interface{}{"dcat:Distribution", "schema:DataDownload"},
+				"dcat:downloadURL":  map[string]interface{}{"@id": "https://data.worldbank.org/pop.csv"},
+				"schema:contentUrl": "https://data.worldbank.org/pop.csv",
+			},
+		},
+	}
+	if diff := cmp.Diff(expect, MetaDCAT(md)); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+
+	// licenses without a url use the license type
+	got := MetaDCAT(&dataset.Meta{License: &dataset.License{Type: "MIT"}})
+	if got["dct:license"] != "MIT" {
+		t.Errorf("expected license type to be used, got: %v", got["dct:license"])
+	}
+
+	if got := MetaDCAT(nil); len(got) != 2 {
+		t.Errorf("expected nil meta to only have a context and type, got: %v", got)
+	}
+}
@@ -0,0 +1,230 @@
+package base
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+// DecimateBody streams the body of a dataset, returning about n rows that
+// keep the overall shape of the body for plotting. The first & last rows are
+// always kept. Without a valueColumn rows are picked at an even stride. With
+// one, rows are picked with the largest-triangle-three-buckets algorithm,
+// which keeps the peaks & dips of the column's numeric values, using row
+// position as the x axis. Only two buckets of rows are held in memory at a
+// time. The body's row count is read from its structure, bodies with n rows
+// or fewer are returned in full
+func DecimateBody(ds *dataset.Dataset, n int, valueColumn string) ([]interface{}, error) {
+	if ds == nil {
+		return nil, fmt.Errorf("can't load body from a nil dataset")
+	}
+	if n < 2 {
+		return nil, fmt.Errorf("decimating needs at least 2 rows to keep the first & last")
+	}
+	file := ds.BodyFile()
+	if file == nil {
+		return nil, fmt.Errorf("no body file to read")
+	}
+	if ds.Structure == nil || ds.Structure.Entries <= 0 {
+		return nil, fmt.Errorf("decimating requires the body's row count, which the structure doesn't record")
+	}
+	total := ds.Structure.Entries
+
+	col := newRowColumn(ds.Structure, valueColumn)
+	if valueColumn != "" {
+		if err := col.missing(); err != nil {
+			return nil, err
+		}
+	}
+
+	var d decimator
+	if total <= n {
+		d = &keepAll{}
+	} else if valueColumn == "" || n < 3 {
+		// with fewer than 3 rows there are no buckets between the first & last
+		d = newStrideDecimator(total, n)
+	} else {
+		d = newLTTBDecimator(total, n, func(row interface{}) float64 {
+			return rowNumber(col, row)
+		})
+	}
+
+	err := eachRow(ds.Structure, file, func(i int, ent dsio.Entry) error {
+		d.add(i, ent.Value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d.rows(), nil
+}
+
+// decimator picks rows from a stream of body rows
+type decimator interface {
+	add(i int, row interface{})
+	rows() []interface{}
+}
+
+// keepAll is a decimator that keeps every row
+type keepAll struct{ kept []interface{} }
+
+func (d *keepAll) add(i int, row interface{}) { d.kept = append(d.kept, row) }
+func (d *keepAll) rows() []interface{}        { return d.kept }
+
+// strideDecimator keeps rows at evenly spaced indexes
+type strideDecimator struct {
+	keep map[int]bool
+	kept []interface{}
+}
+
+func newStrideDecimator(total, n int) *strideDecimator {
+	keep := make(map[int]bool, n)
+	for i := 0; i < n; i++ {
+		keep[int(math.Round(float64(i)*float64(total-1)/float64(n-1)))] = true
+	}
+	return &strideDecimator{keep: keep, kept: make([]interface{}, 0, n)}
+}
+
+func (d *strideDecimator) add(i int, row interface{}) {
+	if d.keep[i] {
+		d.kept = append(d.kept, row)
+	}
+}
+
+func (d *strideDecimator) rows() []interface{} { return d.kept }
+
+type plotPoint struct {
+	x, y float64
+	row  interface{}
+}
+
+// lttbDecimator implements largest-triangle-three-buckets. the first & last
+// rows are buckets of their own, the rows between are split into n-2 buckets.
+// a point is picked from a bucket once the next bucket is complete, making
+// the largest triangle with the last picked point & the next bucket's average
+type lttbDecimator struct {
+	total, n int
+	every    float64
+	value    func(row interface{}) float64
+
+	last      plotPoint
+	pending   []plotPoint
+	hasPend   bool
+	filling   []plotPoint
+	fillingID int
+	kept      []interface{}
+}
+
+func newLTTBDecimator(total, n int, value func(row interface{}) float64) *lttbDecimator {
+	return &lttbDecimator{
+		total: total,
+		n:     n,
+		every: float64(total-2) / float64(n-2),
+		value: value,
+		kept:  make([]interface{}, 0, n),
+	}
+}
+
+// bucket returns the bucket row i belongs to
+func (d *lttbDecimator) bucket(i int) int {
+	switch {
+	case i == 0:
+		return 0
+	case i >= d.total-1:
+		return d.n - 1
+	}
+	b := int(float64(i-1)/d.every) + 1
+	if b > d.n-2 {
+		b = d.n - 2
+	}
+	return b
+}
+
+func (d *lttbDecimator) add(i int, row interface{}) {
+	id := d.bucket(i)
+	if id != d.fillingID && len(d.filling) > 0 {
+		d.complete(d.fillingID, d.filling)
+		d.filling = nil
+	}
+	d.fillingID = id
+	d.filling = append(d.filling, plotPoint{x: float64(i), y: d.value(row), row: row})
+}
+
+// complete handles a bucket with all of its rows read
+func (d *lttbDecimator) complete(id int, bucket []plotPoint) {
+	if id == 0 {
+		d.last = bucket[0]
+		d.kept = append(d.kept, bucket[0].row)
+		return
+	}
+	if d.hasPend {
+		d.pick(d.pending, bucket)
+	}
+	d.pending, d.hasPend = bucket, true
+}
+
+// pick keeps the point of bucket making the largest triangle with the last
+// picked point & the average of next
+func (d *lttbDecimator) pick(bucket, next []plotPoint) {
+	var avgX, avgY float64
+	count := 0
+	for _, p := range next {
+		avgX += p.x
+		if !math.IsNaN(p.y) {
+			avgY += p.y
+			count++
+		}
+	}
+	avgX /= float64(len(next))
+	if count > 0 {
+		avgY /= float64(count)
+	} else {
+		avgY = d.last.y
+	}
+
+	picked, maxArea := bucket[0], -1.0
+	for _, p := range bucket {
+		area := math.Abs((d.last.x-avgX)*(p.y-d.last.y) - (d.last.x-p.x)*(avgY-d.last.y))
+		if area > maxArea {
+			picked, maxArea = p, area
+		}
+	}
+	d.last = picked
+	d.kept = append(d.kept, picked.row)
+}
+
+func (d *lttbDecimator) rows() []interface{} {
+	if len(d.filling) > 0 {
+		d.complete(d.fillingID, d.filling)
+		d.filling = nil
+	}
+	if d.hasPend {
+		// the final bucket is the last row on its own
+		d.kept = append(d.kept, d.pending[len(d.pending)-1].row)
+		d.hasPend = false
+	}
+	return d.kept
+}
+
+// rowNumber reads a numeric column value from a row, returning NaN for
+// values that aren't numbers
+func rowNumber(col rowColumn, row interface{}) float64 {
+	val, _, _ := col.value(0, row)
+	switch x := val.(type) {
+	case int:
+		return float64(x)
+	case int64:
+		return float64(x)
+	case float64:
+		return x
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(x), 64); err == nil {
+			return f
+		}
+	}
+	return math.NaN()
+}
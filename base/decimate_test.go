@@ -0,0 +1,73 @@
+package base
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestDecimateBody(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "array",
+			"items": []interface{}{
+				map[string]interface{}{"title": "t", "type": "integer"},
+				map[string]interface{}{"title": "v", "type": "integer"},
+			},
+		},
+	}
+	// a flat series with a single spike at row 5
+	values := []int{0, 0, 0, 0, 0, 9, 0, 0, 0, 0}
+	b := &strings.Builder{}
+	for i, v := range values {
+		fmt.Fprintf(b, "%d,%d\n", i, v)
+	}
+	newDs := func(entries int) *dataset.Dataset {
+		ds := &dataset.Dataset{Structure: &dataset.Structure{Format: "csv", Schema: schema, Entries: entries}}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte(b.String())))
+		return ds
+	}
+	row := func(t, v int) interface{} { return []interface{}{int64(t), int64(v)} }
+
+	got, err := DecimateBody(newDs(10), 4, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []interface{}{row(0, 0), row(3, 0), row(6, 0), row(9, 0)}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("stride mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = DecimateBody(newDs(10), 4, "v")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the spike is kept, along with the row leading up to it
+	expect = []interface{}{row(0, 0), row(4, 0), row(5, 9), row(9, 0)}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("largest triangle mismatch (-want +got):\n%s", diff)
+	}
+
+	if got, err = DecimateBody(newDs(10), 20, "v"); err != nil {
+		t.Fatal(err)
+	} else if len(got) != 10 {
+		t.Errorf("expected a short body to be returned in full, got %d rows", len(got))
+	}
+
+	_, err = DecimateBody(newDs(0), 4, "")
+	expectErr := "decimating requires the body's row count, which the structure doesn't record"
+	if err == nil || err.Error() != expectErr {
+		t.Errorf("error mismatch. expected: %q, got: %v", expectErr, err)
+	}
+
+	_, err = DecimateBody(newDs(10), 4, "value")
+	expectErr = `unknown column "value", available columns: t, v`
+	if err == nil || err.Error() != expectErr {
+		t.Errorf("error mismatch. expected: %q, got: %v", expectErr, err)
+	}
+}
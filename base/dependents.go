@@ -0,0 +1,64 @@
+package base
+
+import (
+	"context"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base/dsfs"
+	"github.com/qri-io/qri/base/params"
+	"github.com/qri-io/qri/collection"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/profile"
+)
+
+// Dependents scans the head version of every dataset in a user's collection
+// for transforms that loaded the dataset ref names. Transforms record the
+// datasets they load as resources, matched by name or by any of the version
+// paths in versionPaths. Datasets that fail to load are skipped
+func Dependents(ctx context.Context, fs qfs.Filesystem, s collection.Set, pid profile.ID, ref dsref.Ref, versionPaths map[string]bool) ([]dsref.VersionInfo, error) {
+	vis, err := s.List(ctx, pid, params.ListAll)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []dsref.VersionInfo{}
+	for _, vi := range vis {
+		if vi.InitID == ref.InitID || vi.Path == "" {
+			continue
+		}
+		ds, err := dsfs.LoadDataset(ctx, fs, vi.Path)
+		if err != nil {
+			log.Debugw("loading dataset to check dependencies", "ref", vi.SimpleRef(), "err", err)
+			continue
+		}
+		if transformLoads(ds.Transform, ref, versionPaths) {
+			res = append(res, vi)
+		}
+	}
+	return res, nil
+}
+
+// transformLoads reports if any resource of a transform is a version of ref
+func transformLoads(tf *dataset.Transform, ref dsref.Ref, versionPaths map[string]bool) bool {
+	if tf == nil {
+		return false
+	}
+	for key, rsc := range tf.Resources {
+		if versionPaths[key] {
+			return true
+		}
+		if rsc == nil {
+			continue
+		}
+		// resources are recorded as "username/name@path"
+		r, err := dsref.Parse(rsc.Path)
+		if err != nil {
+			continue
+		}
+		if versionPaths[r.Path] || (r.Username == ref.Username && r.Name == ref.Name) {
+			return true
+		}
+	}
+	return false
+}
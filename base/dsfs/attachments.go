@@ -0,0 +1,127 @@
+package dsfs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+// AttachmentsMetaKey is the meta field that lists the files attached to a
+// dataset, mapping each attachment name to the path of its content
+const AttachmentsMetaKey = "attachments"
+
+// attachmentLinkPrefix prefixes the names of attachment links in the root
+// node of a dataset, keeping attachment content in the dataset DAG so it's
+// included when the dataset is pushed & pulled
+const attachmentLinkPrefix = "attachment_"
+
+// ValidateAttachmentName returns an error if name can't be used to name an
+// attachment
+func ValidateAttachmentName(name string) error {
+	if name == "" {
+		return fmt.Errorf("attachment name is required")
+	}
+	if strings.ContainsAny(name, "/\\") {
+		return fmt.Errorf("invalid attachment name %q, names can't contain slashes", name)
+	}
+	return nil
+}
+
+// Attachments returns the files attached to a dataset through its meta
+// component, keyed by name
+func Attachments(md *dataset.Meta) map[string]string {
+	if md == nil {
+		return nil
+	}
+	var att map[string]string
+	switch x := md.Meta()[AttachmentsMetaKey].(type) {
+	case map[string]string:
+		att = make(map[string]string, len(x))
+		for name, path := range x {
+			att[name] = path
+		}
+	case map[string]interface{}:
+		att = make(map[string]string, len(x))
+		for name, v := range x {
+			if path, ok := v.(string); ok {
+				att[name] = path
+			}
+		}
+	}
+	return att
+}
+
+// AttachmentNames lists the names of files attached to a dataset in
+// alphabetical order
+func AttachmentNames(md *dataset.Meta) []string {
+	att := Attachments(md)
+	names := make([]string, 0, len(att))
+	for name := range att {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetAttachment records the path of an attachment in a meta component,
+// replacing any attachment with the same name
+func SetAttachment(md *dataset.Meta, name, path string) error {
+	if err := ValidateAttachmentName(name); err != nil {
+		return err
+	}
+	att := map[string]interface{}{}
+	for n, p := range Attachments(md) {
+		att[n] = p
+	}
+	att[name] = path
+	return md.SetArbitrary(AttachmentsMetaKey, att)
+}
+
+// LoadAttachment opens a file attached to a dataset
+func LoadAttachment(ctx context.Context, fs qfs.Filesystem, ds *dataset.Dataset, name string) (qfs.File, error) {
+	path, ok := Attachments(ds.Meta)[name]
+	if !ok {
+		return nil, fmt.Errorf("%w named %q", ErrNoAttachment, name)
+	}
+	return fs.Get(ctx, path)
+}
+
+// attachmentsFile links the content of each attachment listed in meta into
+// the dataset root node
+func attachmentsFile(src qfs.Filesystem, dst qfs.MerkleDagStore, prev, ds *dataset.Dataset, added qfs.Links, sw *SaveSwitches) error {
+	md := ds.Meta
+	if md == nil && usePrevComponent(sw, "md") && prev != nil {
+		md = prev.Meta
+	}
+	att := Attachments(md)
+	if len(att) == 0 {
+		return errNoComponent
+	}
+
+	for name, path := range att {
+		lnk, err := pathLink(dst, attachmentLinkPrefix+name, path)
+		if err != nil {
+			return fmt.Errorf("attachment %q: %w", name, err)
+		}
+		added.Add(lnk)
+	}
+	return nil
+}
+
+// pathLink creates a named link to content already stored in dst by path
+func pathLink(dst qfs.MerkleDagStore, name, path string) (qfs.Link, error) {
+	prefix := fmt.Sprintf("/%s/", dst.(qfs.Filesystem).Type())
+	if !strings.HasPrefix(path, prefix) {
+		return qfs.Link{}, fmt.Errorf("can't link to path %q outside of the %s filesystem", path, dst.(qfs.Filesystem).Type())
+	}
+	id, err := cid.Parse(strings.TrimPrefix(path, prefix))
+	if err != nil {
+		return qfs.Link{}, err
+	}
+	return qfs.Link{Name: name, Cid: id, IsFile: true}, nil
+}
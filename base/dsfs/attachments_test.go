@@ -0,0 +1,41 @@
+package dsfs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestLoadAttachment(t *testing.T) {
+	ctx := context.Background()
+	fs := qfs.NewMemFS()
+	path, err := fs.Put(ctx, qfs.NewMemfileBytes("codebook.txt", []byte("codes")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds := &dataset.Dataset{Meta: &dataset.Meta{}}
+	if err := SetAttachment(ds.Meta, "codebook.txt", path); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetAttachment(ds.Meta, "gone.txt", "/mem/QmNotStored"); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := LoadAttachment(ctx, fs, ds, "codebook.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if _, err := LoadAttachment(ctx, fs, ds, "missing.pdf"); !errors.Is(err, ErrNoAttachment) {
+		t.Errorf("expected loading an unlisted attachment to return ErrNoAttachment, got: %v", err)
+	}
+	// listed attachments that fail to load return the filesystem error
+	if _, err := LoadAttachment(ctx, fs, ds, "gone.txt"); err == nil || errors.Is(err, ErrNoAttachment) {
+		t.Errorf("expected a filesystem error loading missing attachment content, got: %v", err)
+	}
+}
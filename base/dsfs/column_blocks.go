@@ -0,0 +1,60 @@
+package dsfs
+
+import (
+	"fmt"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+// ColumnBlocksKey is the structure format config key that records the layout
+// of a body that is also stored as one block per column
+const ColumnBlocksKey = "columnBlocks"
+
+// columnBlockLinkPrefix prefixes the names of column block links in the root
+// node of a dataset, keeping column blocks in the dataset DAG so they're
+// included when the dataset is pushed, pulled & pinned
+const columnBlockLinkPrefix = "column_"
+
+// ColumnBlockPaths lists the paths of the column blocks a structure records,
+// in column order
+func ColumnBlockPaths(st *dataset.Structure) []string {
+	if st == nil || st.FormatConfig == nil {
+		return nil
+	}
+	layout, ok := st.FormatConfig[ColumnBlocksKey].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	cols, _ := layout["columns"].([]interface{})
+	paths := make([]string, 0, len(cols))
+	for _, c := range cols {
+		m, _ := c.(map[string]interface{})
+		if path, ok := m["path"].(string); ok && path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// columnBlocksFile links each column block of the structure into the dataset
+// root node
+func columnBlocksFile(src qfs.Filesystem, dst qfs.MerkleDagStore, prev, ds *dataset.Dataset, added qfs.Links, sw *SaveSwitches) error {
+	st := ds.Structure
+	if st == nil && usePrevComponent(sw, "st") && prev != nil {
+		st = prev.Structure
+	}
+	paths := ColumnBlockPaths(st)
+	if len(paths) == 0 {
+		return errNoComponent
+	}
+
+	for i, path := range paths {
+		lnk, err := pathLink(dst, fmt.Sprintf("%s%d", columnBlockLinkPrefix, i), path)
+		if err != nil {
+			return fmt.Errorf("column block %d: %w", i, err)
+		}
+		added.Add(lnk)
+	}
+	return nil
+}
@@ -8,11 +8,11 @@ import (
 	"path/filepath"
 	"time"
 
-	crypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/dsio"
 	"github.com/qri-io/deepdiff"
 	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/auth/key"
 	"github.com/qri-io/qri/base/friendly"
 	"github.com/qri-io/qri/base/toqtype"
 	"github.com/qri-io/qri/event"
@@ -38,7 +38,7 @@ const (
 	BodyTooBig BodyAction = "too_big"
 )
 
-func commitFileAddFunc(ctx context.Context, privKey crypto.PrivKey, publisher event.Publisher) writeComponentFunc {
+func commitFileAddFunc(ctx context.Context, signer key.Signer, publisher event.Publisher) writeComponentFunc {
 	return func(src qfs.Filesystem, dst qfs.MerkleDagStore, prev, ds *dataset.Dataset, added qfs.Links, sw *SaveSwitches) error {
 		if ds.Commit == nil {
 			return errNoComponent
@@ -61,6 +61,10 @@ func commitFileAddFunc(ctx context.Context, privKey crypto.PrivKey, publisher ev
 			return fmt.Errorf("saving failed: %w", err)
 		}
 
+		if err := confirmMinChangeRows(ctx, ds, prev, sw); err != nil {
+			return fmt.Errorf("saving failed: %w", err)
+		}
+
 		if err := EnsureCommitTitleAndMessage(ctx, src, ds, prev, sw.bodyAct, sw.FileHint, sw.ForceIfNoChanges); err != nil {
 			log.Debugf("EnsureCommitTitleAndMessage: %s", err)
 			return fmt.Errorf("saving failed: %w", err)
@@ -69,7 +73,7 @@ func commitFileAddFunc(ctx context.Context, privKey crypto.PrivKey, publisher ev
 		ds.DropTransientValues()
 		setComponentRefs(dst, ds, bodyFilename(ds), added)
 
-		signedBytes, err := privKey.Sign(ds.SigningBytes())
+		signedBytes, err := signer.Sign(ds.SigningBytes())
 		if err != nil {
 			log.Debug(err.Error())
 			return fmt.Errorf("signing commit: %w", err)
@@ -154,6 +158,57 @@ func confirmByteChangesExist(ds, prev *dataset.Dataset, force bool, dst qfs.Merk
 	return ErrNoChanges
 }
 
+// confirmMinChangeRows returns an early error if fewer body rows than
+// sw.MinChangeRows differ from the previous version. Forced saves & new
+// datasets skip the check. Bodies too big to diff are assumed to have changed
+// enough, as there's no cheap way to count rows that changed
+func confirmMinChangeRows(ctx context.Context, ds, prev *dataset.Dataset, sw *SaveSwitches) error {
+	if sw.MinChangeRows <= 0 || sw.ForceIfNoChanges || prev == nil || prev.IsEmpty() {
+		return nil
+	}
+
+	changed := 0
+	switch sw.bodyAct {
+	case BodyTooBig:
+		log.Debugf("body too big to count changed rows. skipping change threshold check")
+		return nil
+	case BodySame:
+	default:
+		inlinePrevBody(prev)
+		if prev.Body == nil || ds.Body == nil {
+			log.Debugf("missing body data to count changed rows. skipping change threshold check")
+			return nil
+		}
+		deltas, _, err := deepdiff.New().StatDiff(ctx, prev.Body, ds.Body)
+		if err != nil {
+			return err
+		}
+		changed = friendly.ChangedRows(deltas)
+	}
+
+	if changed < sw.MinChangeRows {
+		log.Debugw("confirmMinChangeRows", "changed", changed, "min", sw.MinChangeRows)
+		return fmt.Errorf("%w: %d rows changed, need at least %d", ErrBelowChangeThreshold, changed, sw.MinChangeRows)
+	}
+	return nil
+}
+
+// inlinePrevBody reads the body file of a previous version into prev.Body if
+// it's small enough to diff & hasn't been read already
+func inlinePrevBody(prev *dataset.Dataset) {
+	if prev.Body != nil || prev.BodyFile() == nil {
+		return
+	}
+	if prev.Structure != nil && prev.Structure.Length < BodySizeSmallEnoughToDiff {
+		log.Debugf("inlining body file to calculate a diff")
+		if prevReader, err := dsio.NewEntryReader(prev.Structure, prev.BodyFile()); err == nil {
+			if prevBodyData, err := dsio.ReadAll(prevReader); err == nil {
+				prev.Body = prevBodyData
+			}
+		}
+	}
+}
+
 // EnsureCommitTitleAndMessage creates the commit and title, message, skipping
 // if both title and message are set. If no values are provided a commit
 // description is generated by examining changes between the two versions
@@ -201,17 +256,7 @@ func generateCommitDescriptions(ctx context.Context, fs qfs.Filesystem, ds, prev
 
 	// Inline body if it is a reasonable size, to get message about how the body has changed.
 	if bodyAct != BodySame {
-		// If previous version had bodyfile, read it and assign it
-		if prev.Structure != nil && prev.Structure.Length < BodySizeSmallEnoughToDiff {
-			if prev.BodyFile() != nil {
-				log.Debugf("inlining body file to calculate a diff")
-				if prevReader, err := dsio.NewEntryReader(prev.Structure, prev.BodyFile()); err == nil {
-					if prevBodyData, err := dsio.ReadAll(prevReader); err == nil {
-						prev.Body = prevBodyData
-					}
-				}
-			}
-		}
+		inlinePrevBody(prev)
 	}
 
 	// Read the transform files to see if they changed.
@@ -8,12 +8,12 @@ import (
 	"sync"
 	"time"
 
-	crypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/dsio"
 	"github.com/qri-io/dataset/dsstats"
 	"github.com/qri-io/jsonschema"
 	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/auth/key"
 	"github.com/qri-io/qri/event"
 )
 
@@ -21,7 +21,7 @@ type computeFieldsFile struct {
 	*sync.Mutex
 
 	publisher event.Publisher // optional bus to publish progress events to
-	pk        crypto.PrivKey  // key for signing version
+	signer    key.Signer      // signs the version
 	sw        *SaveSwitches
 
 	ds, prev *dataset.Dataset
@@ -51,7 +51,7 @@ var (
 func newComputeFieldsFile(
 	ctx context.Context,
 	pub event.Publisher,
-	pk crypto.PrivKey,
+	signer key.Signer,
 	ds *dataset.Dataset,
 	prev *dataset.Dataset,
 	sw *SaveSwitches) (qfs.File, error) {
@@ -85,7 +85,7 @@ func newComputeFieldsFile(
 	cff := &computeFieldsFile{
 		Mutex:      &sync.Mutex{},
 		publisher:  pub,
-		pk:         pk,
+		signer:     signer,
 		sw:         sw,
 		ds:         ds,
 		prev:       prev,
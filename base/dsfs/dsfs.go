@@ -12,6 +12,9 @@ var (
 	// ErrNoChanges indicates a save failed because no values changed, and
 	// force-saving was disabled
 	ErrNoChanges = fmt.Errorf("no changes")
+	// ErrBelowChangeThreshold indicates a save failed because fewer body rows
+	// changed than the save required, and force-saving was disabled
+	ErrBelowChangeThreshold = fmt.Errorf("below change threshold")
 	// ErrNoReadme is the error for asking a dataset without a readme component
 	// for readme info
 	ErrNoReadme = fmt.Errorf("this dataset has no readme component")
@@ -21,6 +24,12 @@ var (
 	// ErrNoViz is the error for asking a dataset without a viz component for
 	// viz info
 	ErrNoViz = fmt.Errorf("this dataset has no viz component")
+	// ErrNoProvenance is the error for asking a version that wasn't saved with
+	// provenance for provenance info
+	ErrNoProvenance = fmt.Errorf("this version has no provenance")
+	// ErrNoAttachment is the error for loading an attachment a dataset doesn't
+	// list
+	ErrNoAttachment = fmt.Errorf("dataset has no attachment")
 	// ErrStrictMode indicates a dataset failed validation when it is required to
 	// pass (Structure.Strict == true)
 	ErrStrictMode = fmt.Errorf("dataset body did not validate against schema in strict-mode")
@@ -66,6 +66,8 @@ const (
 	PackageFileRenderedReadme
 	// PackageFileStats isolates the statistical metadata component
 	PackageFileStats
+	// PackageFileProvenance records how a version was produced
+	PackageFileProvenance
 )
 
 // filenames maps PackageFile to their filename counterparts
@@ -86,6 +88,7 @@ var filenames = map[PackageFile]string{
 	PackageFileReadmeScript:      "readme.md",
 	PackageFileRenderedReadme:    "readme.html",
 	PackageFileStats:             "stats.json",
+	PackageFileProvenance:        "provenance.json",
 }
 
 // String implements the io.Stringer interface for PackageFile
@@ -0,0 +1,69 @@
+package dsfs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ipfs/go-path/resolver"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+// Provenance records how a version was produced. It's stored in a file linked
+// from the root node of the version it describes & isn't inherited by later
+// versions, unlike dataset components
+type Provenance struct {
+	// BodySource is the query the body was generated from, if any
+	BodySource *BodySource `json:"bodySource,omitempty"`
+	// ComponentHashes are sha256 digests of the content of each component,
+	// keyed by component name, see base.ComponentHashes. the provenance file
+	// is part of the version, so recorded hashes can't change without changing
+	// the version path
+	ComponentHashes map[string]string `json:"componentHashes,omitempty"`
+}
+
+// BodySource describes a query that generated a body
+type BodySource struct {
+	// Syntax of the query, eg. "sql"
+	Syntax string `json:"syntax"`
+	// Query text
+	Query string `json:"query"`
+	// Source is a reference to the dataset the query selected from, or the
+	// connection string of the database it ran against without credentials
+	Source string `json:"source,omitempty"`
+	// SourcePath is the path of the source version the query ran against
+	SourcePath string `json:"sourcePath,omitempty"`
+}
+
+// LoadProvenance reads the provenance of the version at path, returning
+// ErrNoProvenance if the version wasn't saved with any
+func LoadProvenance(ctx context.Context, fs qfs.Filesystem, path string) (*Provenance, error) {
+	data, err := fileBytes(fs.Get(ctx, strings.TrimSuffix(path, PackageFileDataset.Filename())+PackageFileProvenance.Filename()))
+	if err != nil {
+		// versions without provenance have no link to it in their root node
+		if errors.Is(err, qfs.ErrNotFound) || errors.As(err, &resolver.ErrNoLink{}) {
+			return nil, ErrNoProvenance
+		}
+		return nil, fmt.Errorf("loading provenance file: %w", err)
+	}
+	p := &Provenance{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("unmarshaling provenance: %w", err)
+	}
+	return p, nil
+}
+
+// provenanceFile writes the provenance set on the save switches
+func provenanceFile(src qfs.Filesystem, dst qfs.MerkleDagStore, prev, ds *dataset.Dataset, added qfs.Links, sw *SaveSwitches) error {
+	if sw.Provenance == nil {
+		return errNoComponent
+	}
+	data, err := json.Marshal(sw.Provenance)
+	if err != nil {
+		return err
+	}
+	return writePackageFile(dst, NewMemfileBytes(PackageFileProvenance.String(), data), added)
+}
@@ -0,0 +1,58 @@
+package dsfs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	testkeys "github.com/qri-io/qri/auth/key/test"
+	"github.com/qri-io/qri/event"
+)
+
+func TestLoadProvenance(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ipfs, destroy, err := makeTestIPFSRepo(ctx, "")
+	if err != nil {
+		t.Fatalf("error creating IPFS test repo: %s", err)
+	}
+	defer destroy()
+
+	pk := testkeys.GetKeyData(10).PrivKey
+	prov := &Provenance{BodySource: &BodySource{Syntax: "sql", Query: "SELECT * FROM source", Source: "me/src", SourcePath: "/ipfs/QmSource"}}
+
+	for _, fs := range []qfs.Filesystem{qfs.NewMemFS(), ipfs} {
+		newDataset := func() *dataset.Dataset {
+			ds := &dataset.Dataset{
+				Commit:    &dataset.Commit{Title: "initial commit"},
+				Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray},
+			}
+			ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(`[[1]]`)))
+			return ds
+		}
+
+		path, err := WriteDataset(ctx, fs, fs, nil, newDataset(), event.NilBus, pk, SaveSwitches{Provenance: prov})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := LoadProvenance(ctx, fs, path)
+		if err != nil {
+			t.Fatalf("%s: %s", fs.Type(), err)
+		}
+		if diff := cmp.Diff(prov, got); diff != "" {
+			t.Errorf("%s: provenance mismatch (-want +got):\n%s", fs.Type(), diff)
+		}
+
+		path, err = WriteDataset(ctx, fs, fs, nil, newDataset(), event.NilBus, pk, SaveSwitches{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadProvenance(ctx, fs, path); !errors.Is(err, ErrNoProvenance) {
+			t.Errorf("%s: expected ErrNoProvenance, got: %v", fs.Type(), err)
+		}
+	}
+}
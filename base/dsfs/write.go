@@ -9,11 +9,14 @@ import (
 	"time"
 
 	cid "github.com/ipfs/go-cid"
-	crypto "github.com/libp2p/go-libp2p-core/crypto"
+	files "github.com/ipfs/go-ipfs-files"
+	coreiface "github.com/ipfs/interface-go-ipfs-core"
+	caopts "github.com/ipfs/interface-go-ipfs-core/options"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/dsviz"
 	"github.com/qri-io/dataset/validate"
 	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/auth/key"
 	"github.com/qri-io/qri/dsref"
 	"github.com/qri-io/qri/event"
 )
@@ -50,6 +53,28 @@ type SaveSwitches struct {
 	ConvertFormatToPrev bool
 	// ForceIfNoChanges is whether the save should be forced even if no changes are detected
 	ForceIfNoChanges bool
+	// MinChangeRows is the fewest body rows that must differ from the previous
+	// version for the save to proceed. zero disables the check
+	MinChangeRows int
+	// ColumnarStorage additionally stores each body column in its own block,
+	// see base.WriteColumnBlocks
+	ColumnarStorage bool
+	// ChunkSize is the size in bytes of the blocks the body is split into when
+	// written to a store that chunks files, like IPFS. zero uses the store's
+	// default chunking. must not exceed MaxChunkSize, and saving with a chunk
+	// size to a store that doesn't chunk files is an error
+	ChunkSize int
+	// AllowSchemaWiden lets a body with columns the previous schema doesn't
+	// define add those columns to the schema, see base.WidenSchema
+	AllowSchemaWiden bool
+	// RequiredMeta lists meta fields a version must have to be saved, see
+	// base.MissingMetaFields
+	RequiredMeta []string
+	// HashComponents records a hash of the content of each component in the
+	// version's provenance, see base.ComponentHashes
+	HashComponents bool
+	// Provenance records how the version was produced, see dsfs.Provenance
+	Provenance *Provenance
 	// ShouldRender is deprecated, controls whether viz should be rendered
 	ShouldRender bool
 	// NewName is whether a new dataset should be created, guaranteeing there's no previous version
@@ -71,7 +96,7 @@ type SaveSwitches struct {
 // Store is where we're going to store the data
 // Dataset to be saved
 // Prev is the previous version or nil if there isn't one
-// Pk is the private key for cryptographically signing
+// Signer cryptographically signs the commit, usually the author's private key
 // Sw is switches that control how the save happens
 // Returns the immutable path if no error
 func CreateDataset(
@@ -81,11 +106,11 @@ func CreateDataset(
 	pub event.Publisher,
 	ds *dataset.Dataset,
 	prev *dataset.Dataset,
-	pk crypto.PrivKey,
+	signer key.Signer,
 	sw SaveSwitches,
 ) (string, error) {
-	if pk == nil {
-		return "", fmt.Errorf("private key is required to create a dataset")
+	if signer == nil {
+		return "", fmt.Errorf("a signer is required to create a dataset")
 	}
 
 	if err := DerefDataset(ctx, source, ds); err != nil {
@@ -125,7 +150,7 @@ func CreateDataset(
 		}
 	}()
 
-	path, err := WriteDataset(ctx, source, destination, prev, ds, pub, pk, sw)
+	path, err := WriteDataset(ctx, source, destination, prev, ds, pub, signer, sw)
 	if err != nil {
 		log.Debug(err.Error())
 		if evtErr := pub.Publish(ctx, event.ETDatasetSaveCompleted, event.DsSaveEvent{
@@ -172,7 +197,7 @@ func WriteDataset(
 	prev *dataset.Dataset,
 	ds *dataset.Dataset,
 	publisher event.Publisher,
-	pk crypto.PrivKey,
+	signer key.Signer,
 	sw SaveSwitches,
 ) (string, error) {
 	dstStore, ok := dst.(qfs.MerkleDagStore)
@@ -206,15 +231,18 @@ func WriteDataset(
 	// the call order of these functions is important, funcs later in the slice
 	// may rely on writeFiles fields set by eariler functions
 	writeFuncs := []writeComponentFunc{
-		bodyFileFunc(ctx, pk, publisher),      // no deps
-		metadataFile,                          // no deps
-		transformFile,                         // no deps
-		structureFile,                         // requires bdoy if it exists
-		statsFile,                             // requires body, structure if they exist
-		readmeFile,                            // no deps
-		vizFilesAddFunc(ctx, sw),              // requires body, meta, transform, structure, stats, readme if they exist
-		commitFileAddFunc(ctx, pk, publisher), // requires meta, transform, body, structure, stats, readme, vizScript, vizRendered if they exist
-		writeDatasetFile,                      // requires all other components
+		bodyFileFunc(ctx, signer, publisher), // no deps
+		metadataFile,                         // no deps
+		attachmentsFile,                      // no deps
+		columnBlocksFile,                     // no deps
+		transformFile,                        // no deps
+		structureFile,                        // requires bdoy if it exists
+		statsFile,                            // requires body, structure if they exist
+		readmeFile,                           // no deps
+		provenanceFile,                       // no deps
+		vizFilesAddFunc(ctx, sw),             // requires body, meta, transform, structure, stats, readme if they exist
+		commitFileAddFunc(ctx, signer, publisher), // requires meta, transform, body, structure, stats, readme, vizScript, vizRendered if they exist
+		writeDatasetFile, // requires all other components
 	}
 
 	for _, fileFunc := range writeFuncs {
@@ -242,7 +270,7 @@ type writeComponentFunc func(src qfs.Filesystem, dst qfs.MerkleDagStore, prev, d
 
 var errNoComponent = errors.New("no component")
 
-func bodyFileFunc(ctx context.Context, pk crypto.PrivKey, publisher event.Publisher) writeComponentFunc {
+func bodyFileFunc(ctx context.Context, signer key.Signer, publisher event.Publisher) writeComponentFunc {
 	return func(src qfs.Filesystem, dst qfs.MerkleDagStore, prev, ds *dataset.Dataset, added qfs.Links, sw *SaveSwitches) error {
 		if ds.BodyFile() == nil {
 			if usePrevComponent(sw, "bd") && prev != nil && prev.BodyPath != "" {
@@ -257,7 +285,7 @@ func bodyFileFunc(ctx context.Context, pk crypto.PrivKey, publisher event.Publis
 
 		sw.bodyAct = BodyDefault
 		bodyFilename := bodyFilename(ds)
-		cff, err := newComputeFieldsFile(ctx, publisher, pk, ds, prev, sw)
+		cff, err := newComputeFieldsFile(ctx, publisher, signer, ds, prev, sw)
 		if err != nil {
 			return err
 		}
@@ -267,7 +295,7 @@ func bodyFileFunc(ctx context.Context, pk crypto.PrivKey, publisher event.Publis
 			return err
 		}
 
-		if err := writePackageFile(dst, f, added); err != nil {
+		if err := writeChunkedFile(ctx, dst, f, sw.ChunkSize, added); err != nil {
 			return err
 		}
 		if err := <-cff.(doneProcessingFile).DoneProcessing(); err != nil {
@@ -524,6 +552,55 @@ func writePackageFile(s qfs.MerkleDagStore, f fs.File, added qfs.Links) error {
 	return nil
 }
 
+// MaxChunkSize is the largest block size in bytes a body can be chunked into
+const MaxChunkSize = 1024 * 1024
+
+// chunkingStore is implemented by merkle dag stores backed by IPFS, which
+// split files into blocks as they're added
+type chunkingStore interface {
+	CoreAPI() coreiface.CoreAPI
+}
+
+// writeChunkedFile writes a file split into blocks of chunkSize bytes. files
+// are written with writePackageFile if chunkSize is zero. it's an error to set
+// a chunk size for a store that doesn't chunk files
+func writeChunkedFile(ctx context.Context, s qfs.MerkleDagStore, f fs.File, chunkSize int, added qfs.Links) error {
+	if chunkSize < 0 || chunkSize > MaxChunkSize {
+		return fmt.Errorf("chunk size must be between 0 and %d bytes, got %d", MaxChunkSize, chunkSize)
+	}
+	if chunkSize == 0 {
+		return writePackageFile(s, f, added)
+	}
+	cs, ok := s.(chunkingStore)
+	if !ok {
+		return fmt.Errorf("chunk size isn't supported by %s storage, only by stores that split files into blocks like IPFS", s.Type())
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	capi := cs.CoreAPI()
+	p, err := capi.Unixfs().Add(ctx, files.NewReaderFile(f),
+		caopts.Unixfs.CidVersion(0),
+		caopts.Unixfs.Chunker(fmt.Sprintf("size-%d", chunkSize)),
+	)
+	if err != nil {
+		return err
+	}
+	stored, err := capi.Unixfs().Get(ctx, p)
+	if err != nil {
+		return err
+	}
+	size, err := stored.Size()
+	if err != nil {
+		return err
+	}
+
+	added.Add(qfs.Link{Name: fi.Name(), Cid: p.Root(), Size: size, IsFile: !fi.IsDir()})
+	return nil
+}
+
 func bodyFilename(ds *dataset.Dataset) string {
 	if ds.Structure == nil {
 		return ""
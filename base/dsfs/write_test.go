@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -130,7 +131,7 @@ func TestCreateDataset(t *testing.T) {
 		if err == nil {
 			t.Fatal("expected call without prvate key to error")
 		}
-		pkReqErrMsg := "private key is required to create a dataset"
+		pkReqErrMsg := "a signer is required to create a dataset"
 		if err.Error() != pkReqErrMsg {
 			t.Fatalf("error mismatch.\nwant: %q\ngot:  %q", pkReqErrMsg, err.Error())
 		}
@@ -235,6 +236,82 @@ func TestDatasetSaveCustomTimestamp(t *testing.T) {
 	}
 }
 
+func TestCreateDatasetChunkSize(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fs, destroy, err := makeTestIPFSRepo(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer destroy()
+	privKey := testkeys.GetKeyData(10).PrivKey
+
+	rows := make([]string, 200)
+	for i := range rows {
+		rows[i] = fmt.Sprintf("[%d,\"row number %d\"]", i, i)
+	}
+	body := []byte("[" + strings.Join(rows, ",") + "]")
+
+	bodyBlocks := func(t *testing.T, chunkSize int) int {
+		ds := &dataset.Dataset{
+			Commit:    &dataset.Commit{Title: fmt.Sprintf("chunk size %d", chunkSize)},
+			Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray},
+		}
+		ds.SetBodyFile(qfs.NewMemfileBytes("/body.json", body))
+
+		path, err := CreateDataset(ctx, fs, fs, event.NilBus, ds, nil, privKey, SaveSwitches{ChunkSize: chunkSize, ForceIfNoChanges: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := LoadDataset(ctx, fs, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := cidFromIPFSPath(got.BodyPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// qfs.Links are keyed by name, read chunk links from the ipfs node
+		nd, err := fs.CoreAPI().Dag().Get(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(nd.Links())
+	}
+
+	// the default chunker fits the body in a single block
+	if n := bodyBlocks(t, 0); n != 0 {
+		t.Errorf("expected default chunking to store the body in one block, got %d links", n)
+	}
+	if n := bodyBlocks(t, 256); n < len(body)/256 {
+		t.Errorf("expected body to be split into at least %d blocks, got %d", len(body)/256, n)
+	}
+
+	ds := &dataset.Dataset{Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}}
+	ds.SetBodyFile(qfs.NewMemfileBytes("/body.json", body))
+	if _, err := CreateDataset(ctx, fs, fs, event.NilBus, ds, nil, privKey, SaveSwitches{ChunkSize: MaxChunkSize + 1}); err == nil {
+		t.Errorf("expected chunk size larger than MaxChunkSize to error")
+	}
+}
+
+func TestCreateDatasetChunkSizeUnsupported(t *testing.T) {
+	ctx := context.Background()
+	fs := qfs.NewMemFS()
+	privKey := testkeys.GetKeyData(10).PrivKey
+
+	ds := &dataset.Dataset{
+		Commit:    &dataset.Commit{Title: "chunked"},
+		Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("/body.json", []byte("[1,2,3]")))
+
+	_, err := CreateDataset(ctx, fs, fs, event.NilBus, ds, nil, privKey, SaveSwitches{ChunkSize: 256})
+	if expect := "chunk size isn't supported by mem storage, only by stores that split files into blocks like IPFS"; err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. want: %q, got: %v", expect, err)
+	}
+}
+
 func TestDatasetSaveEvents(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -0,0 +1,155 @@
+package base
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/qfs"
+)
+
+// MaxDuplicateLocations caps the number of duplicates a DuplicateReport
+// lists. Duplicates beyond the cap are still counted
+const MaxDuplicateLocations = 100
+
+// DuplicateReport describes the duplicate rows of a dataset body
+type DuplicateReport struct {
+	// column rows were compared by, empty when whole rows were compared
+	Key string `json:"key,omitempty"`
+	// number of rows read
+	Rows int `json:"rows"`
+	// number of rows repeating an earlier row. the first occurrence of a row
+	// isn't counted
+	Duplicates int `json:"duplicates"`
+	// the first MaxDuplicateLocations duplicates, in body order
+	Locations []DuplicateRow `json:"locations,omitempty"`
+}
+
+// DuplicateRow locates a single duplicate row
+type DuplicateRow struct {
+	// index of the duplicate row
+	Index int `json:"index"`
+	// index of the first row it repeats
+	FirstIndex int `json:"firstIndex"`
+	// repeated key value, only set when comparing by key
+	Key interface{} `json:"key,omitempty"`
+}
+
+// FindDuplicateRows streams the body of a dataset, reporting rows that repeat
+// an earlier row. When key names a column only that column's values are
+// compared, otherwise whole rows must match. Rows without a key value are
+// never duplicates. Memory is bounded by keeping a hash of each distinct row
+// instead of the row itself
+func FindDuplicateRows(ds *dataset.Dataset, key string) (*DuplicateReport, error) {
+	col, err := duplicatesColumn(ds, key)
+	if err != nil {
+		return nil, err
+	}
+	return scanDuplicateRows(ds, col, nil)
+}
+
+// DropDuplicateRows returns a new body for a dataset without rows that
+// repeat an earlier row, compared the same way as FindDuplicateRows. The
+// first occurrence of every row is kept. The body is streamed as the
+// returned file is read, errors reading the original body are returned from
+// Read
+func DropDuplicateRows(ds *dataset.Dataset, key string) (qfs.File, error) {
+	col, err := duplicatesColumn(ds, key)
+	if err != nil {
+		return nil, err
+	}
+
+	r, pw := io.Pipe()
+	w, err := dsio.NewEntryWriter(ds.Structure, pw)
+	if err != nil {
+		return nil, fmt.Errorf("error allocating data writer: %s", err)
+	}
+	go func() {
+		kept := 0
+		_, err := scanDuplicateRows(ds, col, func(ent dsio.Entry) error {
+			ent.Index = kept
+			kept++
+			return w.WriteEntry(ent)
+		})
+		if err == nil {
+			err = w.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	name := "body." + strings.ToLower(ds.Structure.Format)
+	return qfs.NewMemfileReader(name, r), nil
+}
+
+// duplicatesColumn checks a dataset body can be checked for duplicates,
+// returning the column rows are compared by
+func duplicatesColumn(ds *dataset.Dataset, key string) (rowColumn, error) {
+	if ds == nil || ds.Structure == nil {
+		return rowColumn{}, fmt.Errorf("can't check a dataset without a structure for duplicates")
+	}
+	if ds.BodyFile() == nil {
+		return rowColumn{}, fmt.Errorf("no body file to read")
+	}
+	if ds.Structure.Schema["type"] != "array" {
+		return rowColumn{}, fmt.Errorf("only bodies that are arrays of rows can be checked for duplicates")
+	}
+	col := newRowColumn(ds.Structure, key)
+	if key != "" {
+		if err := col.missing(); err != nil {
+			return rowColumn{}, err
+		}
+	}
+	return col, nil
+}
+
+// scanDuplicateRows reads every body row, passing rows that aren't
+// duplicates to keep if it's non-nil
+func scanDuplicateRows(ds *dataset.Dataset, col rowColumn, keep func(ent dsio.Entry) error) (*DuplicateReport, error) {
+	key := col.title
+	report := &DuplicateReport{Key: key}
+	seen := map[[sha256.Size]byte]int{}
+	err := eachRow(ds.Structure, ds.BodyFile(), func(i int, ent dsio.Entry) error {
+		report.Rows++
+
+		val := ent.Value
+		if key != "" {
+			var err error
+			if val, _, err = col.value(i, ent.Value); err != nil {
+				return err
+			}
+		}
+
+		if val != nil || key == "" {
+			data, err := json.Marshal(val)
+			if err != nil {
+				return fmt.Errorf("row %d: %w", i, err)
+			}
+			sum := sha256.Sum256(data)
+			if first, ok := seen[sum]; ok {
+				report.Duplicates++
+				if len(report.Locations) < MaxDuplicateLocations {
+					loc := DuplicateRow{Index: i, FirstIndex: first}
+					if key != "" {
+						loc.Key = val
+					}
+					report.Locations = append(report.Locations, loc)
+				}
+				return nil
+			}
+			seen[sum] = i
+		}
+
+		if keep != nil {
+			return keep(ent)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,78 @@
+package base
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestFindDuplicateRows(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"headerRow": false},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "city", "type": "string"},
+				},
+			},
+		},
+	}
+	newDs := func() *dataset.Dataset {
+		ds := &dataset.Dataset{Structure: st}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte("1,toronto\n2,chatham\n1,toronto\n3,chatham\n")))
+		return ds
+	}
+
+	report, err := FindDuplicateRows(newDs(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := &DuplicateReport{
+		Rows:       4,
+		Duplicates: 1,
+		Locations:  []DuplicateRow{{Index: 2, FirstIndex: 0}},
+	}
+	if diff := cmp.Diff(expect, report); diff != "" {
+		t.Errorf("report mismatch (-want +got):\n%s", diff)
+	}
+
+	report, err = FindDuplicateRows(newDs(), "city")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect = &DuplicateReport{
+		Key:        "city",
+		Rows:       4,
+		Duplicates: 2,
+		Locations: []DuplicateRow{
+			{Index: 2, FirstIndex: 0, Key: "toronto"},
+			{Index: 3, FirstIndex: 1, Key: "chatham"},
+		},
+	}
+	if diff := cmp.Diff(expect, report); diff != "" {
+		t.Errorf("report mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := FindDuplicateRows(newDs(), "country"); err == nil {
+		t.Error("expected an unknown key column to error")
+	}
+
+	body, err := DropDuplicateRows(newDs(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("1,toronto\n2,chatham\n3,chatham\n", string(data)); diff != "" {
+		t.Errorf("body mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,182 @@
+package base
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/qfs"
+)
+
+// Fingerprint hashes the content of a dataset, returning a hex-encoded
+// sha256 digest. Fingerprints depend only on decoded content, so datasets
+// with the same content have the same fingerprint regardless of body format,
+// storage paths, chunking, or history. Commits & stats aren't part of the
+// content. When bodyOnly is true only the body is hashed, otherwise meta,
+// schema, transform, readme & viz scripts are included. The body is hashed
+// one entry at a time, so the whole body is never held in memory. ds must be
+// opened with OpenDataset, Fingerprint consumes its files
+func Fingerprint(ds *dataset.Dataset, bodyOnly bool) (string, error) {
+	if ds == nil {
+		return "", fmt.Errorf("can't fingerprint a nil dataset")
+	}
+
+	// content is hashed as canonical json: the body alone, or an object of
+	// components with keys in sorted order. "body" sorts before every other
+	// component name, so it's written first & streamed
+	h := sha256.New()
+	if !bodyOnly {
+		h.Write([]byte(`{"body":`))
+	}
+	if err := writeFingerprintBody(h, ds.Structure, ds.BodyFile()); err != nil {
+		return "", fmt.Errorf("reading body: %w", err)
+	}
+
+	if !bodyOnly {
+		doc, err := fingerprintComponents(ds)
+		if err != nil {
+			return "", err
+		}
+		keys := make([]string, 0, len(doc))
+		for k := range doc {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			key, _ := json.Marshal(k)
+			val, err := json.Marshal(doc[k])
+			if err != nil {
+				return "", err
+			}
+			h.Write([]byte(","))
+			h.Write(key)
+			h.Write([]byte(":"))
+			h.Write(val)
+		}
+		h.Write([]byte("}"))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeFingerprintBody writes a body file to w as json, one entry at a time.
+// array bodies are written in body order. object bodies are written with keys
+// in the order they're read, as sorting them would mean holding the whole
+// body. a nil file is written as null
+func writeFingerprintBody(w io.Writer, st *dataset.Structure, file io.Reader) error {
+	if file == nil {
+		_, err := w.Write([]byte("null"))
+		return err
+	}
+	start, end := "[", "]"
+	if tlt, err := dsio.GetTopLevelType(st); err == nil && tlt == "object" {
+		start, end = "{", "}"
+	}
+	if _, err := w.Write([]byte(start)); err != nil {
+		return err
+	}
+	err := eachRow(st, file, func(i int, ent dsio.Entry) error {
+		if i > 0 {
+			if _, err := w.Write([]byte(",")); err != nil {
+				return err
+			}
+		}
+		if start == "{" {
+			key, _ := json.Marshal(ent.Key)
+			if _, err := w.Write(append(key, ':')); err != nil {
+				return err
+			}
+		}
+		data, err := json.Marshal(ent.Value)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write([]byte(end))
+	return err
+}
+
+// fingerprintComponents collects the content of non-body components, leaving
+// out fields that describe storage rather than content
+func fingerprintComponents(ds *dataset.Dataset) (map[string]interface{}, error) {
+	doc := map[string]interface{}{}
+
+	if ds.Meta != nil {
+		data, err := ds.Meta.MarshalJSONObject()
+		if err != nil {
+			return nil, err
+		}
+		md := map[string]interface{}{}
+		if err := json.Unmarshal(data, &md); err != nil {
+			return nil, err
+		}
+		delete(md, "path")
+		delete(md, "qri")
+		if len(md) > 0 {
+			doc["meta"] = md
+		}
+	}
+
+	if ds.Structure != nil && ds.Structure.Schema != nil {
+		doc["schema"] = ds.Structure.Schema
+	}
+
+	if ds.Transform != nil {
+		script, err := fingerprintScript(ds.Transform.ScriptFile())
+		if err != nil {
+			return nil, fmt.Errorf("reading transform script: %w", err)
+		}
+		steps := make([]map[string]interface{}, len(ds.Transform.Steps))
+		for i, st := range ds.Transform.Steps {
+			steps[i] = map[string]interface{}{
+				"name":     st.Name,
+				"syntax":   st.Syntax,
+				"category": st.Category,
+				"script":   st.Script,
+			}
+		}
+		doc["transform"] = map[string]interface{}{
+			"syntax": ds.Transform.Syntax,
+			"config": ds.Transform.Config,
+			"script": script,
+			"steps":  steps,
+		}
+	}
+
+	if ds.Readme != nil {
+		script, err := fingerprintScript(ds.Readme.ScriptFile())
+		if err != nil {
+			return nil, fmt.Errorf("reading readme: %w", err)
+		}
+		doc["readme"] = script
+	}
+
+	if ds.Viz != nil {
+		script, err := fingerprintScript(ds.Viz.ScriptFile())
+		if err != nil {
+			return nil, fmt.Errorf("reading viz script: %w", err)
+		}
+		doc["viz"] = script
+	}
+
+	return doc, nil
+}
+
+func fingerprintScript(f qfs.File) (string, error) {
+	if f == nil {
+		return "", nil
+	}
+	defer f.Close()
+	data, err := ioutil.ReadAll(f)
+	return string(data), err
+}
@@ -0,0 +1,78 @@
+package base
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestFingerprint(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "array",
+			"items": []interface{}{
+				map[string]interface{}{"title": "city", "type": "string"},
+				map[string]interface{}{"title": "pop", "type": "integer"},
+			},
+		},
+	}
+	newDs := func(filename, body string, md *dataset.Meta) *dataset.Dataset {
+		format := "csv"
+		if filename == "body.json" {
+			format = "json"
+		}
+		ds := &dataset.Dataset{
+			Meta:      md,
+			Structure: &dataset.Structure{Format: format, Schema: schema},
+		}
+		ds.SetBodyFile(qfs.NewMemfileBytes(filename, []byte(body)))
+		return ds
+	}
+	mustFingerprint := func(ds *dataset.Dataset, bodyOnly bool) string {
+		t.Helper()
+		fp, err := Fingerprint(ds, bodyOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fp
+	}
+
+	csvFp := mustFingerprint(newDs("body.csv", "toronto,40000000\nnew york,8500000\n", &dataset.Meta{Title: "cities", Path: "/mem/a"}), false)
+	jsonFp := mustFingerprint(newDs("body.json", `[["toronto",40000000],["new york",8500000]]`, &dataset.Meta{Title: "cities", Path: "/mem/b"}), false)
+	if len(csvFp) != 64 {
+		t.Errorf("expected a hex sha256 digest, got %q", csvFp)
+	}
+	if csvFp != jsonFp {
+		t.Errorf("expected the same content in different formats to have the same fingerprint. csv: %s json: %s", csvFp, jsonFp)
+	}
+
+	retitled := newDs("body.csv", "toronto,40000000\nnew york,8500000\n", &dataset.Meta{Title: "big cities"})
+	if fp := mustFingerprint(retitled, false); fp == csvFp {
+		t.Error("expected changing meta to change the fingerprint")
+	}
+	bodyFp := mustFingerprint(newDs("body.csv", "toronto,40000000\nnew york,8500000\n", &dataset.Meta{Title: "cities"}), true)
+	retitled = newDs("body.csv", "toronto,40000000\nnew york,8500000\n", &dataset.Meta{Title: "big cities"})
+	if fp := mustFingerprint(retitled, true); fp != bodyFp {
+		t.Error("expected changing meta not to change the body fingerprint")
+	}
+	if fp := mustFingerprint(newDs("body.csv", "toronto,40000001\nnew york,8500000\n", nil), true); fp == bodyFp {
+		t.Error("expected changing the body to change the body fingerprint")
+	}
+}
+
+func TestFingerprintObjectBody(t *testing.T) {
+	ds := &dataset.Dataset{Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaObject}}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(`{"a":1,"b":[2,3]}`)))
+	got, err := Fingerprint(ds, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte(`{"a":1,"b":[2,3]}`))
+	if expect := hex.EncodeToString(sum[:]); got != expect {
+		t.Errorf("expected object body to hash as its json encoding. want: %s got: %s", expect, got)
+	}
+}
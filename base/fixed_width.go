@@ -0,0 +1,150 @@
+package base
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/tabular"
+)
+
+const (
+	// OverflowTruncate cuts values longer than their column width, ending
+	// them with TruncateMarker
+	OverflowTruncate = "truncate"
+	// OverflowError fails when a value is longer than its column width
+	OverflowError = "error"
+)
+
+// TruncateMarker replaces the last character of a truncated fixed-width value
+const TruncateMarker = "~"
+
+// FixedWidthBody reads a tabular dataset body & writes it as fixed-width text,
+// see FixedWidth
+func FixedWidthBody(ds *dataset.Dataset, limit, offset int, all bool, widths []int, overflow string) ([]byte, error) {
+	body, err := GetBody(ds, limit, offset, all)
+	if err != nil {
+		return nil, err
+	}
+	entries, ok := body.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("fixed-width output requires a tabular body")
+	}
+
+	var keys []string
+	if ds.Structure != nil {
+		if cols, _, err := tabular.ColumnsFromJSONSchema(ds.Structure.Schema); err == nil {
+			keys = cols.Titles()
+		}
+	}
+	rows := make([]interface{}, len(entries))
+	for i, entry := range entries {
+		switch e := entry.(type) {
+		case []interface{}:
+			rows[i] = e
+		case map[string]interface{}:
+			keys = mergeKeys(keys, e)
+			vals := make([]interface{}, len(keys))
+			for j, key := range keys {
+				vals[j] = e[key]
+			}
+			rows[i] = vals
+		default:
+			rows[i] = []interface{}{e}
+		}
+	}
+	return FixedWidth(rows, widths, overflow)
+}
+
+// FixedWidth writes rows as fixed-width text, one line per row with no header.
+// Each value is left-aligned & padded with spaces to the width of its column,
+// nested values are written as JSON. When widths is empty each column is as
+// wide as its longest value, plus a space separating it from the next column.
+// overflow sets how values longer than their column are handled, and defaults
+// to OverflowTruncate. Rows & columns in errors are numbered from 1
+func FixedWidth(rows []interface{}, widths []int, overflow string) ([]byte, error) {
+	switch overflow {
+	case "":
+		overflow = OverflowTruncate
+	case OverflowTruncate, OverflowError:
+	default:
+		return nil, fmt.Errorf("unknown overflow %q, must be %s or %s", overflow, OverflowTruncate, OverflowError)
+	}
+
+	table := make([][]string, len(rows))
+	numCols := 0
+	for i, r := range rows {
+		row, ok := r.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("row %d is not an array", i+1)
+		}
+		table[i] = make([]string, len(row))
+		for j, v := range row {
+			s, err := csvValue(v)
+			if err != nil {
+				return nil, err
+			}
+			table[i][j] = s
+		}
+		if len(row) > numCols {
+			numCols = len(row)
+		}
+	}
+
+	if len(widths) == 0 {
+		widths = autoWidths(table, numCols)
+	} else {
+		if len(widths) != numCols {
+			return nil, fmt.Errorf("got %d widths for %d columns", len(widths), numCols)
+		}
+		for i, w := range widths {
+			if w < 1 {
+				return nil, fmt.Errorf("width of column %d must be greater than zero", i+1)
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	for i, row := range table {
+		for j, w := range widths {
+			s := ""
+			if j < len(row) {
+				s = row[j]
+			}
+			if n := utf8.RuneCountInString(s); n > w {
+				if overflow == OverflowError {
+					return nil, fmt.Errorf("row %d column %d: value %q is longer than width %d", i+1, j+1, s, w)
+				}
+				s = string([]rune(s)[:w-1]) + TruncateMarker
+			}
+			buf.WriteString(s)
+			buf.WriteString(strings.Repeat(" ", w-utf8.RuneCountInString(s)))
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// autoWidths sizes each column to its longest value, leaving a space between
+// columns
+func autoWidths(table [][]string, numCols int) []int {
+	widths := make([]int, numCols)
+	for _, row := range table {
+		for j, s := range row {
+			if n := utf8.RuneCountInString(s); n > widths[j] {
+				widths[j] = n
+			}
+		}
+	}
+	for j := range widths {
+		if widths[j] == 0 {
+			widths[j] = 1
+		}
+		if j < numCols-1 {
+			widths[j]++
+		}
+	}
+	return widths
+}
@@ -0,0 +1,56 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFixedWidth(t *testing.T) {
+	rows := []interface{}{
+		[]interface{}{"Avatar", 178, true},
+		[]interface{}{"Spectre", 148, nil},
+		[]interface{}{"Piraña", 99, false},
+	}
+
+	cases := []struct {
+		description string
+		widths      []int
+		overflow    string
+		expect      string
+		err         string
+	}{
+		{"auto widths", nil, "",
+			"Avatar  178 true \nSpectre 148      \nPiraña  99  false\n", ""},
+		{"fixed widths", []int{10, 5, 6}, "",
+			"Avatar    178  true  \nSpectre   148        \nPiraña    99   false \n", ""},
+		{"truncate", []int{4, 3, 1}, OverflowTruncate,
+			"Ava~178~\nSpe~148 \nPir~99 ~\n", ""},
+		{"overflow error", []int{4, 3, 5}, OverflowError,
+			"", `row 1 column 1: value "Avatar" is longer than width 4`},
+		{"widths mismatch", []int{4, 3}, "",
+			"", "got 2 widths for 3 columns"},
+		{"zero width", []int{4, 0, 5}, "",
+			"", "width of column 2 must be greater than zero"},
+		{"unknown overflow", nil, "wrap",
+			"", `unknown overflow "wrap", must be truncate or error`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			got, err := FixedWidth(rows, c.widths, c.overflow)
+			if c.err != "" {
+				if err == nil || err.Error() != c.err {
+					t.Fatalf("error mismatch. want: %q, got: %v", c.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(c.expect, string(got)); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -218,6 +218,18 @@ func buildBodyChanges(changes *ComponentChanges, parentPath string, deltas deepd
 	}
 }
 
+// ChangedRows counts the number of top-level body entries touched by a list
+// of body deltas. A row that's been replaced counts once
+func ChangedRows(bodyDeltas deepdiff.Deltas) int {
+	rows := map[string]struct{}{}
+	for _, d := range bodyDeltas {
+		if d.Type != deepdiff.DTContext || len(d.Deltas) > 0 {
+			rows[d.Path.String()] = struct{}{}
+		}
+	}
+	return len(rows)
+}
+
 func joinPath(parent, element string) string {
 	if parent == "" {
 		return element
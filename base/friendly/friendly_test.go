@@ -109,3 +109,21 @@ func getKeys(m map[string]*ComponentChanges) []string {
 	sort.Strings(keys)
 	return keys
 }
+
+func TestChangedRows(t *testing.T) {
+	deltas := deepdiff.Deltas{
+		{Type: deepdiff.DTContext, Path: deepdiff.IndexAddr(0)},
+		{Type: deepdiff.DTDelete, Path: deepdiff.IndexAddr(1), Value: []interface{}{"a", 1}},
+		{Type: deepdiff.DTInsert, Path: deepdiff.IndexAddr(1), Value: []interface{}{"a", 2}},
+		{Type: deepdiff.DTContext, Path: deepdiff.IndexAddr(2), Deltas: deepdiff.Deltas{
+			{Type: deepdiff.DTUpdate, Path: deepdiff.IndexAddr(1), Value: 4, SourceValue: 3},
+		}},
+		{Type: deepdiff.DTInsert, Path: deepdiff.IndexAddr(3), Value: []interface{}{"b", 5}},
+	}
+	if got := ChangedRows(deltas); got != 3 {
+		t.Errorf("expected 3 changed rows, got: %d", got)
+	}
+	if got := ChangedRows(nil); got != 0 {
+		t.Errorf("expected no changed rows for empty deltas, got: %d", got)
+	}
+}
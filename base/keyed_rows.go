@@ -0,0 +1,60 @@
+package base
+
+import (
+	"fmt"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+// SelectRowsByKey streams the body of a dataset, returning the rows whose
+// value in keyColumn is one of keys, in body order. Key values are compared
+// by their text form, so the key 7 matches a row with the number 7, and the
+// key 1000000 matches a float written as 1e6 in a JSON body. Keys
+// that don't match any row are returned as missing, in the order they were
+// given. Rows may be arrays, which need a tabular schema to locate keyColumn,
+// or objects
+func SelectRowsByKey(ds *dataset.Dataset, keyColumn string, keys []string) (rows []interface{}, missing []string, err error) {
+	if ds == nil {
+		return nil, nil, fmt.Errorf("can't load body from a nil dataset")
+	}
+	if keyColumn == "" {
+		return nil, nil, fmt.Errorf("a key column is required")
+	}
+	file := ds.BodyFile()
+	if file == nil {
+		return nil, nil, fmt.Errorf("no body file to read")
+	}
+
+	col := newRowColumn(ds.Structure, keyColumn)
+	want := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		want[k] = false
+	}
+
+	rows = []interface{}{}
+	err = eachRow(ds.Structure, file, func(i int, ent dsio.Entry) error {
+		val, found, err := col.value(i, ent.Value)
+		if err != nil || !found {
+			return err
+		}
+		k := keyText(val)
+		if _, ok := want[k]; ok {
+			want[k] = true
+			rows = append(rows, ent.Value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, k := range keys {
+		if !want[k] {
+			missing = append(missing, k)
+			// report each missing key once
+			want[k] = true
+		}
+	}
+	return rows, missing, nil
+}
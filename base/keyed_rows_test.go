@@ -0,0 +1,72 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestSelectRowsByKey(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "array",
+			"items": []interface{}{
+				map[string]interface{}{"title": "id", "type": "integer"},
+				map[string]interface{}{"title": "city", "type": "string"},
+			},
+		},
+	}
+	newDs := func(format, body string) *dataset.Dataset {
+		st := &dataset.Structure{Format: format, Schema: schema}
+		if format == "json" {
+			st.Schema = dataset.BaseSchemaArray
+		}
+		ds := &dataset.Dataset{Structure: st}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body."+format, []byte(body)))
+		return ds
+	}
+
+	ds := newDs("csv", "1,toronto\n2,new york\n3,chicago\n4,chatham\n")
+	rows, missing, err := SelectRowsByKey(ds, "id", []string{"3", "1", "9", "9"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectRows := []interface{}{
+		[]interface{}{int64(1), "toronto"},
+		[]interface{}{int64(3), "chicago"},
+	}
+	if diff := cmp.Diff(expectRows, rows); diff != "" {
+		t.Errorf("rows mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"9"}, missing); diff != "" {
+		t.Errorf("missing keys mismatch (-want +got):\n%s", diff)
+	}
+
+	ds = newDs("json", `[{"id":"a","n":1},{"id":"b","n":2},{"n":3}]`)
+	rows, missing, err = SelectRowsByKey(ds, "id", []string{"b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || len(missing) != 0 {
+		t.Errorf("expected 1 row & no missing keys, got %d rows & missing keys %v", len(rows), missing)
+	}
+
+	ds = newDs("json", `[{"id":1e6,"n":1},{"id":2.5,"n":2}]`)
+	rows, missing, err = SelectRowsByKey(ds, "id", []string{"1000000", "2.5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || len(missing) != 0 {
+		t.Errorf("expected float keys to match without exponents, got %d rows & missing keys %v", len(rows), missing)
+	}
+
+	ds = newDs("csv", "1,toronto\n")
+	_, _, err = SelectRowsByKey(ds, "population", []string{"1"})
+	expectErr := `unknown column "population", available columns: id, city`
+	if err == nil || err.Error() != expectErr {
+		t.Errorf("error mismatch. expected: %q, got: %v", expectErr, err)
+	}
+}
@@ -23,25 +23,7 @@ func DatasetLog(ctx context.Context, r repo.Repo, ref dsref.Ref, limit, offset i
 			if len(items) == 0 {
 				return nil, repo.ErrNoHistory
 			}
-			// Logbook doesn't store the CommitMessage and CommitTitle
-			// (see infoFromOp in logbook/logbook.go), so we need to load
-			// each dataset, and assign the CommitMessage and CommitTitle field.
-			for i, item := range items {
-				if item.Path != "" {
-					local, err := r.Filesystem().Has(ctx, item.Path)
-					if err != nil {
-						continue
-					}
-					if local {
-						if ds, err := dsfs.LoadDataset(ctx, r.Filesystem(), item.Path); err == nil {
-							if ds.Commit != nil {
-								items[i].CommitMessage = ds.Commit.Message
-							}
-						}
-					}
-					items[i].Foreign = !local
-				}
-			}
+			addStoredVersionDetails(ctx, r, items)
 			return items, nil
 		}
 	}
@@ -75,6 +57,52 @@ func DatasetLog(ctx context.Context, r repo.Repo, ref dsref.Ref, limit, offset i
 	return items, err
 }
 
+// DatasetLogWithDeleted fetches the version history of a dataset, keeping
+// versions deleted from history in the list, marked as deleted. Deletes are
+// only recorded in the logbook
+func DatasetLogWithDeleted(ctx context.Context, r repo.Repo, ref dsref.Ref, limit, offset int, term string) ([]dsref.VersionInfo, error) {
+	book := r.Logbook()
+	if book == nil {
+		return nil, fmt.Errorf("listing deleted versions requires a logbook")
+	}
+	items, err := book.ItemsWithDeleted(ctx, ref, offset, limit, term)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, repo.ErrNoHistory
+	}
+	addStoredVersionDetails(ctx, r, items)
+	return items, nil
+}
+
+// addStoredVersionDetails fills in version details the logbook doesn't
+// store. Logbook doesn't store the CommitMessage, CommitTitle, or BodyRows
+// (see infoFromOp in logbook/logbook.go), so we need to load each dataset,
+// and assign the CommitMessage, CommitTitle, and BodyRows fields.
+func addStoredVersionDetails(ctx context.Context, r repo.Repo, items []dsref.VersionInfo) {
+	for i, item := range items {
+		if item.Path != "" {
+			local, err := r.Filesystem().Has(ctx, item.Path)
+			if err != nil {
+				continue
+			}
+			if local {
+				if ds, err := dsfs.LoadDataset(ctx, r.Filesystem(), item.Path); err == nil {
+					if ds.Commit != nil {
+						items[i].CommitMessage = ds.Commit.Message
+					}
+					// logbook records body size, but not row counts
+					if ds.Structure != nil {
+						items[i].BodyRows = ds.Structure.Entries
+					}
+				}
+			}
+			items[i].Foreign = !local
+		}
+	}
+}
+
 // StoredHistoricalDatasets fetches the history of changes to a dataset by walking
 // backwards through dataset commits. if loadDatasets is true, dataset
 // information will be populated
@@ -236,6 +236,7 @@ func TestConstructDatasetLogFromHistory(t *testing.T) {
 		{
 			Username:      "peer",
 			BodySize:      0x9b,
+			BodyRows:      5,
 			ProfileID:     "QmZePf5LeXow3RW5U1AgEiNbW46YnRGhZ7HPvm1UmPFPwt",
 			Name:          "cities",
 			CommitTitle:   "initial commit",
@@ -244,6 +245,7 @@ func TestConstructDatasetLogFromHistory(t *testing.T) {
 		{
 			Username:      "peer",
 			BodySize:      0x9b,
+			BodyRows:      5,
 			ProfileID:     "QmZePf5LeXow3RW5U1AgEiNbW46YnRGhZ7HPvm1UmPFPwt",
 			Name:          "cities",
 			Path:          "/map/QmaTfAQNUKqtPe2EUcCELJNprRLJWswsVPHHNhiKgZoTMR",
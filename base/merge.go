@@ -0,0 +1,145 @@
+package base
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/tabular"
+	"github.com/qri-io/qfs"
+)
+
+// SchemaMismatches compares the schemas of two datasets that are to be
+// merged, returning one description per column that doesn't match. Tabular
+// schemas match when they have the same column titles in the same order, and
+// each column accepts the same types. Other schemas must be identical. Names
+// label each dataset in descriptions
+func SchemaMismatches(a, b *dataset.Structure, aName, bName string) []string {
+	if a == nil || a.Schema == nil || b == nil || b.Schema == nil {
+		return []string{"both datasets need a schema"}
+	}
+	aCols, _, aErr := tabular.ColumnsFromJSONSchema(a.Schema)
+	bCols, _, bErr := tabular.ColumnsFromJSONSchema(b.Schema)
+	if aErr != nil || bErr != nil {
+		if !reflect.DeepEqual(a.Schema, b.Schema) {
+			return []string{"schemas aren't tabular and aren't identical"}
+		}
+		return nil
+	}
+
+	var mismatches []string
+	for i := 0; i < len(aCols) || i < len(bCols); i++ {
+		switch {
+		case i >= len(bCols):
+			mismatches = append(mismatches, fmt.Sprintf("column %d %q is missing from %s", i, aCols[i].Title, bName))
+		case i >= len(aCols):
+			mismatches = append(mismatches, fmt.Sprintf("column %d %q is missing from %s", i, bCols[i].Title, aName))
+		case aCols[i].Title != bCols[i].Title:
+			mismatches = append(mismatches, fmt.Sprintf("column %d is %q in %s, %q in %s", i, aCols[i].Title, aName, bCols[i].Title, bName))
+		case !sameColTypes(aCols[i].Type, bCols[i].Type):
+			mismatches = append(mismatches, fmt.Sprintf("column %d %q is %s in %s, %s in %s", i, aCols[i].Title, colTypeString(aCols[i].Type), aName, colTypeString(bCols[i].Type), bName))
+		}
+	}
+	return mismatches
+}
+
+// sameColTypes reports whether two column types accept the same set of types
+func sameColTypes(a, b *tabular.ColType) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	as := append([]string{}, *a...)
+	bs := append([]string{}, *b...)
+	sort.Strings(as)
+	sort.Strings(bs)
+	return reflect.DeepEqual(as, bs)
+}
+
+// MergeCounts tallies the rows of a merged body. Counts are final once the
+// merged body has been read to the end
+type MergeCounts struct {
+	// rows written to the merged body
+	Rows int
+	// rows dropped for repeating a dedup key
+	Dropped int
+}
+
+// MergeBodies streams the rows of two dataset bodies into one body, a's rows
+// followed by b's, written in the format of a's structure. Bodies must be
+// arrays of rows. When dedupKey names a column, rows whose key matches an
+// earlier row are dropped & counted, so the first occurrence is kept. Keys
+// are compared by their text form, and rows without a key value are kept.
+// Rows are merged as the returned file is read, errors reading either body
+// are returned from Read. Callers should check schemas match with
+// SchemaMismatches first
+func MergeBodies(a, b *dataset.Dataset, dedupKey string) (qfs.File, *MergeCounts, error) {
+	for _, ds := range []*dataset.Dataset{a, b} {
+		if ds == nil || ds.Structure == nil {
+			return nil, nil, fmt.Errorf("can't merge a dataset without a structure")
+		}
+		if ds.BodyFile() == nil {
+			return nil, nil, fmt.Errorf("no body file to read")
+		}
+		if ds.Structure.Schema["type"] != "array" {
+			return nil, nil, fmt.Errorf("only bodies that are arrays of rows can be merged")
+		}
+	}
+
+	col := newRowColumn(a.Structure, dedupKey)
+	if dedupKey != "" {
+		if err := col.missing(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	r, pw := io.Pipe()
+	w, err := dsio.NewEntryWriter(a.Structure, pw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error allocating data writer: %s", err)
+	}
+
+	counts := &MergeCounts{}
+	go func() {
+		pw.CloseWithError(mergeRows(w, []*dataset.Dataset{a, b}, col, counts))
+	}()
+
+	name := "body." + strings.ToLower(a.Structure.Format)
+	return qfs.NewMemfileReader(name, r), counts, nil
+}
+
+// mergeRows writes the rows of each dataset body to w, closing w when all
+// rows are written
+func mergeRows(w dsio.EntryWriter, datasets []*dataset.Dataset, col rowColumn, counts *MergeCounts) error {
+	seen := map[string]bool{}
+	for _, ds := range datasets {
+		err := eachRow(ds.Structure, ds.BodyFile(), func(i int, ent dsio.Entry) error {
+			if col.title != "" {
+				key, _, err := col.value(i, ent.Value)
+				if err != nil {
+					return err
+				}
+				if key != nil {
+					k := keyText(key)
+					if seen[k] {
+						counts.Dropped++
+						return nil
+					}
+					seen[k] = true
+				}
+			}
+			if err := w.WriteEntry(dsio.Entry{Index: counts.Rows, Value: ent.Value}); err != nil {
+				return err
+			}
+			counts.Rows++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
@@ -0,0 +1,107 @@
+package base
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestSchemaMismatches(t *testing.T) {
+	schema := func(cols ...map[string]interface{}) *dataset.Structure {
+		items := []interface{}{}
+		for _, c := range cols {
+			items = append(items, c)
+		}
+		return &dataset.Structure{Schema: map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "array", "items": items},
+		}}
+	}
+	col := func(title string, t interface{}) map[string]interface{} {
+		return map[string]interface{}{"title": title, "type": t}
+	}
+
+	a := schema(col("id", "integer"), col("region", []interface{}{"string", "null"}), col("total", "number"))
+	b := schema(col("id", "integer"), col("region", []interface{}{"null", "string"}), col("total", "number"))
+	if got := SchemaMismatches(a, b, "a", "b"); len(got) != 0 {
+		t.Errorf("expected matching schemas, got: %v", got)
+	}
+
+	b = schema(col("id", "string"), col("area", "string"))
+	expect := []string{
+		`column 0 "id" is integer in a, string in b`,
+		`column 1 is "region" in a, "area" in b`,
+		`column 2 "total" is missing from b`,
+	}
+	if diff := cmp.Diff(expect, SchemaMismatches(a, b, "a", "b")); diff != "" {
+		t.Errorf("mismatches (-want +got):\n%s", diff)
+	}
+}
+
+func TestMergeBodies(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "csv",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "city", "type": "string"},
+				},
+			},
+		},
+	}
+	newDs := func(st *dataset.Structure, name, body string) *dataset.Dataset {
+		ds := &dataset.Dataset{Structure: st}
+		ds.SetBodyFile(qfs.NewMemfileBytes(name, []byte(body)))
+		return ds
+	}
+	jsonSt := &dataset.Structure{Format: "json", Schema: st.Schema}
+
+	body, counts, err := MergeBodies(newDs(st, "body.csv", "1,toronto\n2,chatham\n"), newDs(jsonSt, "body.json", `[[2,"chatham"],[3,"raleigh"]]`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("1,toronto\n2,chatham\n2,chatham\n3,raleigh\n", string(data)); diff != "" {
+		t.Errorf("body mismatch (-want +got):\n%s", diff)
+	}
+	if counts.Rows != 4 || counts.Dropped != 0 {
+		t.Errorf("expected 4 rows & 0 dropped, got %d & %d", counts.Rows, counts.Dropped)
+	}
+
+	body, counts, err = MergeBodies(newDs(st, "body.csv", "1,toronto\n1000000,chatham\n"), newDs(jsonSt, "body.json", `[[1e6,"chatham"],[3,"raleigh"]]`), "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err = ioutil.ReadAll(body); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff("1,toronto\n1000000,chatham\n3,raleigh\n", string(data)); diff != "" {
+		t.Errorf("deduplicated body mismatch (-want +got):\n%s", diff)
+	}
+	if counts.Rows != 3 || counts.Dropped != 1 {
+		t.Errorf("expected 3 rows & 1 dropped, got %d & %d", counts.Rows, counts.Dropped)
+	}
+
+	body, _, err = MergeBodies(newDs(st, "body.csv", "1,toronto\n"), newDs(jsonSt, "body.json", `[[2,`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ioutil.ReadAll(body); err == nil {
+		t.Error("expected reading a merge of a malformed body to error")
+	}
+
+	_, _, err = MergeBodies(newDs(st, "body.csv", "1,toronto\n"), newDs(st, "body.csv", "2,chatham\n"), "name")
+	expectErr := `unknown column "name", available columns: id, city`
+	if err == nil || err.Error() != expectErr {
+		t.Errorf("error mismatch. expected: %q, got: %v", expectErr, err)
+	}
+}
@@ -0,0 +1,180 @@
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/dataset"
+)
+
+// ErrInvalidMetaField indicates a meta field path that doesn't name a field of
+// the meta component
+var ErrInvalidMetaField = errors.New("invalid meta field")
+
+// ErrMissingRequiredMeta indicates a dataset is missing meta fields that must
+// be set before it can be saved
+var ErrMissingRequiredMeta = errors.New("missing required meta fields")
+
+// MissingMetaFields returns the dot-separated meta field paths in required
+// that are unset or empty in md, in the order given. Required fields use the
+// same paths as SetMetaField, naming a field meta doesn't define is an error
+func MissingMetaFields(md *dataset.Meta, required []string) ([]string, error) {
+	if len(required) == 0 {
+		return nil, nil
+	}
+
+	doc := map[string]interface{}{}
+	if md != nil {
+		data, err := json.Marshal(md)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+	}
+
+	var missing []string
+	for _, path := range required {
+		steps := strings.Split(path, ".")
+		field, ok := metaFieldName(steps[0])
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrInvalidMetaField, path)
+		}
+		var val interface{} = doc[field]
+		for _, step := range steps[1:] {
+			obj, _ := val.(map[string]interface{})
+			val = obj[step]
+		}
+		if isEmptyMetaValue(val) {
+			missing = append(missing, path)
+		}
+	}
+	return missing, nil
+}
+
+func isEmptyMetaValue(val interface{}) bool {
+	switch v := val.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// SetMetaField returns a copy of a meta component with the value at a
+// dot-separated field path replaced, leaving all other fields intact. The path
+// must start with a field the meta component defines, eg: "title" or
+// "license.type". value is decoded as JSON when possible & falls back to a
+// plain string, so both `["a","b"]` and `New Title` are accepted
+func SetMetaField(md *dataset.Meta, path, value string) (*dataset.Meta, error) {
+	steps := strings.Split(path, ".")
+	field, ok := metaFieldName(steps[0])
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrInvalidMetaField, path)
+	}
+	steps[0] = field
+
+	var val interface{}
+	if err := json.Unmarshal([]byte(value), &val); err != nil {
+		val = value
+	}
+
+	res, err := setMetaPathValue(md, steps, val)
+	if _, isString := val.(string); err != nil && !isString {
+		// a value like 1.0 decodes as JSON, but may be meant for a string field
+		if strRes, strErr := setMetaPathValue(md, steps, value); strErr == nil {
+			return strRes, nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("setting meta %s: %w", path, err)
+	}
+	return res, nil
+}
+
+// metaFieldName finds the JSON name of a settable meta field, ignoring case
+func metaFieldName(name string) (string, bool) {
+	t := reflect.TypeOf(dataset.Meta{})
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		// path & qri are derived, and can't be set
+		if tag == "" || tag == "-" || tag == "path" || tag == "qri" {
+			continue
+		}
+		if strings.EqualFold(tag, name) {
+			return tag, true
+		}
+	}
+	return "", false
+}
+
+func setMetaPathValue(md *dataset.Meta, steps []string, val interface{}) (*dataset.Meta, error) {
+	res := &dataset.Meta{}
+	res.Assign(md)
+	res.Path = ""
+
+	// round trip the field through JSON to build the new value of the top
+	// level field, then let the meta component check its type
+	data, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	fieldVal, err := setJSONPathValue(doc[steps[0]], steps[1:], val)
+	if err != nil {
+		return nil, err
+	}
+	if err := res.Set(steps[0], fieldVal); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// setJSONPathValue replaces the value at a path within decoded JSON, creating
+// objects along the way if they don't exist
+func setJSONPathValue(node interface{}, steps []string, val interface{}) (interface{}, error) {
+	if len(steps) == 0 {
+		return val, nil
+	}
+	step := steps[0]
+
+	switch n := node.(type) {
+	case nil:
+		child, err := setJSONPathValue(nil, steps[1:], val)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{step: child}, nil
+	case map[string]interface{}:
+		child, err := setJSONPathValue(n[step], steps[1:], val)
+		if err != nil {
+			return nil, err
+		}
+		n[step] = child
+		return n, nil
+	case []interface{}:
+		i, err := strconv.Atoi(step)
+		if err != nil || i < 0 || i >= len(n) {
+			return nil, fmt.Errorf("index %q out of range, list has %d entries", step, len(n))
+		}
+		if n[i], err = setJSONPathValue(n[i], steps[1:], val); err != nil {
+			return nil, err
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("cannot set %q on a %T value", step, node)
+	}
+}
@@ -0,0 +1,78 @@
+package base
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/qri-io/dataset"
+)
+
+func TestSetMetaField(t *testing.T) {
+	md := &dataset.Meta{
+		Path:     "/mem/QmMeta",
+		Title:    "old title",
+		Keywords: []string{"a"},
+		License:  &dataset.License{Type: "CC0"},
+	}
+
+	cases := []struct {
+		path, value string
+		expect      *dataset.Meta
+	}{
+		{"title", "New Title", &dataset.Meta{Title: "New Title", Keywords: []string{"a"}, License: &dataset.License{Type: "CC0"}}},
+		{"Keywords", `["b","c"]`, &dataset.Meta{Title: "old title", Keywords: []string{"b", "c"}, License: &dataset.License{Type: "CC0"}}},
+		{"version", "1.0", &dataset.Meta{Title: "old title", Keywords: []string{"a"}, License: &dataset.License{Type: "CC0"}, Version: "1.0"}},
+		{"license.url", "https://example.com", &dataset.Meta{Title: "old title", Keywords: []string{"a"}, License: &dataset.License{Type: "CC0", URL: "https://example.com"}}},
+	}
+
+	for _, c := range cases {
+		got, err := SetMetaField(md, c.path, c.value)
+		if err != nil {
+			t.Errorf("setting %q: unexpected error: %s", c.path, err)
+			continue
+		}
+		if diff := cmp.Diff(c.expect, got, cmpopts.IgnoreUnexported(dataset.Meta{})); diff != "" {
+			t.Errorf("setting %q result mismatch (-want +got):\n%s", c.path, diff)
+		}
+	}
+
+	if md.Title != "old title" || md.Path != "/mem/QmMeta" {
+		t.Errorf("expected input meta to be left unchanged, got: %#v", md)
+	}
+
+	if _, err := SetMetaField(md, "colour", "blue"); !errors.Is(err, ErrInvalidMetaField) {
+		t.Errorf("expected invalid meta field error, got: %v", err)
+	}
+	if _, err := SetMetaField(md, "keywords", `{"a":1}`); err == nil {
+		t.Error("expected setting keywords to an object to error")
+	}
+	if _, err := SetMetaField(md, "keywords.3", "d"); err == nil {
+		t.Error("expected setting an out of range keyword to error")
+	}
+}
+
+func TestMissingMetaFields(t *testing.T) {
+	md := &dataset.Meta{
+		Title:       "title",
+		Description: " ",
+		Keywords:    []string{},
+		License:     &dataset.License{Type: "CC0"},
+	}
+
+	got, err := MissingMetaFields(md, []string{"title", "Description", "keywords", "license.type", "license.url"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"Description", "keywords", "license.url"}, got); diff != "" {
+		t.Errorf("missing fields mismatch (-want +got):\n%s", diff)
+	}
+
+	if got, err = MissingMetaFields(nil, []string{"title"}); err != nil || len(got) != 1 {
+		t.Errorf("expected a nil meta to be missing title, got: %v, err: %v", got, err)
+	}
+	if _, err := MissingMetaFields(md, []string{"colour"}); !errors.Is(err, ErrInvalidMetaField) {
+		t.Errorf("expected invalid meta field error, got: %v", err)
+	}
+}
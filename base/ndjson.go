@@ -0,0 +1,79 @@
+package base
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+// WriteBodyNDJSON streams the body of a dataset to w as newline-delimited
+// JSON, one entry at a time, using limit, offset & all like GetBody. Each
+// entry of an object body is written as an object with a single key, in body
+// order
+func WriteBodyNDJSON(w io.Writer, ds *dataset.Dataset, limit, offset int, all bool) error {
+	if ds == nil {
+		return fmt.Errorf("can't load body from a nil dataset")
+	}
+	file := ds.BodyFile()
+	if file == nil {
+		return fmt.Errorf("no body file to read")
+	}
+
+	tlt, err := dsio.GetTopLevelType(ds.Structure)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	return eachRow(ds.Structure, file, func(i int, ent dsio.Entry) error {
+		if !all && i < offset {
+			return nil
+		}
+		if !all && limit >= 0 && i >= offset+limit {
+			return errStopRows
+		}
+		var row interface{} = ent.Value
+		if tlt == "object" {
+			row = map[string]interface{}{ent.Key: ent.Value}
+		}
+		return enc.Encode(row)
+	})
+}
+
+// BodyNDJSON encodes a go-native body as newline-delimited JSON, writing one
+// row per line. Bodies that don't need to be decoded first should be written
+// with WriteBodyNDJSON instead. Each entry of an object body is written as an object with a
+// single key, in key order. Any other value is written as a single line
+func BodyNDJSON(body interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	switch b := body.(type) {
+	case []interface{}:
+		for _, row := range b {
+			if err := enc.Encode(row); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(b))
+		for key := range b {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := enc.Encode(map[string]interface{}{key: b[key]}); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		if err := enc.Encode(b); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,64 @@
+package base
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestBodyNDJSON(t *testing.T) {
+	cases := []struct {
+		body   interface{}
+		expect string
+	}{
+		{[]interface{}{[]interface{}{"toronto", 40000000}, []interface{}{"chatham", nil}}, "[\"toronto\",40000000]\n[\"chatham\",null]\n"},
+		{map[string]interface{}{"b": 2, "a": []interface{}{1}}, "{\"a\":[1]}\n{\"b\":2}\n"},
+		{[]interface{}{}, ""},
+		{5, "5\n"},
+	}
+	for i, c := range cases {
+		got, err := BodyNDJSON(c.body)
+		if err != nil {
+			t.Fatalf("case %d: %s", i, err)
+		}
+		if string(got) != c.expect {
+			t.Errorf("case %d output mismatch. want: %q, got: %q", i, c.expect, string(got))
+		}
+	}
+}
+
+func TestWriteBodyNDJSON(t *testing.T) {
+	ds := &dataset.Dataset{Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaObject}}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(`{"b":2,"a":[1],"c":3}`)))
+	buf := &bytes.Buffer{}
+	if err := WriteBodyNDJSON(buf, ds, 2, 0, false); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "{\"b\":2}\n{\"a\":[1]}\n"; buf.String() != expect {
+		t.Errorf("output mismatch. want: %q, got: %q", expect, buf.String())
+	}
+
+	ds = &dataset.Dataset{Structure: &dataset.Structure{
+		Format: "csv",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "pop", "type": "integer"},
+				},
+			},
+		},
+	}}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte("toronto,1\nchatham,2\n")))
+	buf.Reset()
+	if err := WriteBodyNDJSON(buf, ds, 0, 1, true); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "[\"toronto\",1]\n[\"chatham\",2]\n"; buf.String() != expect {
+		t.Errorf("output mismatch. want: %q, got: %q", expect, buf.String())
+	}
+}
@@ -0,0 +1,268 @@
+package base
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/tabular"
+	"github.com/qri-io/qfs"
+)
+
+// RequiredColumns returns the titles of columns listed in the "required"
+// field of the row schema of a tabular schema. Columns that aren't listed are
+// optional, and may be missing from a body. ok is false when the schema has
+// no required list, in which case every column must be present
+func RequiredColumns(st *dataset.Structure) (titles []string, ok bool) {
+	if st == nil || st.Schema == nil {
+		return nil, false
+	}
+	items, _ := st.Schema["items"].(map[string]interface{})
+	if items == nil {
+		return nil, false
+	}
+	if _, isArr := items["items"].([]interface{}); !isArr {
+		return nil, false
+	}
+	var list []interface{}
+	switch x := items["required"].(type) {
+	case []interface{}:
+		list = x
+	case []string:
+		for _, t := range x {
+			list = append(list, t)
+		}
+	default:
+		return nil, false
+	}
+	for _, v := range list {
+		if t, ok := v.(string); ok {
+			titles = append(titles, t)
+		}
+	}
+	return titles, true
+}
+
+// FillMissingColumns streams a body whose schema marks some columns optional,
+// see RequiredColumns, returning a body in the same format with a value for
+// every schema column. Optional columns the body omits are filled with null,
+// which csv bodies write as an empty field & read back as null, see
+// newBodyReader. CSV bodies with a header row are matched to the schema by
+// column title, so omitted columns can come anywhere in the row. It's an
+// error for the body to omit a required column. A header without a required
+// column errors immediately, rows without one error when the returned body is
+// read. Bodies whose schema has no required list are returned unchanged
+func FillMissingColumns(st *dataset.Structure, body qfs.File) (qfs.File, error) {
+	if _, ok := RequiredColumns(st); !ok {
+		return body, nil
+	}
+	cf, err := newColumnFiller(st, body)
+	if err != nil {
+		return nil, err
+	}
+
+	r, pw := io.Pipe()
+	w, err := dsio.NewEntryWriter(st, pw)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		pw.CloseWithError(cf.fill(w))
+	}()
+	return qfs.NewMemfileReader(body.FileName(), r), nil
+}
+
+// columnFiller places the values of body rows in schema column order
+type columnFiller struct {
+	cols     tabular.Columns
+	required map[string]bool
+	// src maps each schema column to its position in body rows, -1 for
+	// columns the body omits
+	src       []int
+	reordered bool
+	// readSt is a copy of the structure that accepts rows of any length
+	readSt *dataset.Structure
+	body   io.Reader
+}
+
+// newColumnFiller reads the header row of csv bodies to match body columns to
+// the schema, returning an error if the header is missing a required column
+func newColumnFiller(st *dataset.Structure, body io.Reader) (*columnFiller, error) {
+	cols, _, err := tabular.ColumnsFromJSONSchema(st.Schema)
+	if err != nil {
+		return nil, err
+	}
+	titles, _ := RequiredColumns(st)
+	cf := &columnFiller{
+		cols:     cols,
+		required: map[string]bool{},
+		src:      make([]int, len(cols)),
+		readSt:   &dataset.Structure{},
+		body:     body,
+	}
+	for _, t := range titles {
+		cf.required[t] = true
+	}
+	for i := range cf.src {
+		cf.src[i] = i
+	}
+	colSchemas := st.Schema["items"].(map[string]interface{})["items"].([]interface{})
+
+	cf.readSt.Assign(st)
+	if st.DataFormat() != dataset.CSVDataFormat {
+		return cf, nil
+	}
+	fc := map[string]interface{}{}
+	for k, v := range st.FormatConfig {
+		fc[k] = v
+	}
+	fc["variadicFields"] = true
+	cf.readSt.FormatConfig = fc
+
+	if !dsio.HasHeaderRow(st) || st.Compression != "" {
+		return cf, nil
+	}
+	// keep the bytes read while finding the header, so the body can be read
+	// in full afterward
+	consumed := &bytes.Buffer{}
+	header, err := readCSVHeader(st, io.TeeReader(body, consumed))
+	if err != nil {
+		return nil, err
+	}
+	cf.body = io.MultiReader(consumed, body)
+	if header == nil {
+		return cf, nil
+	}
+
+	cf.reordered = true
+	readSchemas := make([]interface{}, len(header))
+	for i := range cf.src {
+		cf.src[i] = -1
+	}
+	index := map[string]int{}
+	for i, col := range cols {
+		index[col.Title] = i
+	}
+	for j, title := range header {
+		i, ok := index[title]
+		if !ok {
+			return nil, fmt.Errorf("body has column %q that the schema doesn't define", title)
+		}
+		cf.src[i] = j
+		readSchemas[j] = colSchemas[i]
+	}
+	cf.readSt.Schema = map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"type": "array", "items": readSchemas},
+	}
+	for i, col := range cols {
+		if cf.src[i] == -1 && cf.required[col.Title] {
+			return nil, fmt.Errorf("body is missing required column %q", col.Title)
+		}
+	}
+	return cf, nil
+}
+
+// fill writes body rows to w with a value for every schema column, closing w
+// when all rows are written
+func (cf *columnFiller) fill(w dsio.EntryWriter) error {
+	err := eachRow(cf.readSt, cf.body, func(n int, ent dsio.Entry) error {
+		row, ok := ent.Value.([]interface{})
+		if !ok {
+			return w.WriteEntry(dsio.Entry{Index: n, Value: ent.Value})
+		}
+		filled := make([]interface{}, len(cf.cols))
+		for i, col := range cf.cols {
+			if j := cf.src[i]; j >= 0 && j < len(row) {
+				filled[i] = row[j]
+			} else if cf.required[col.Title] {
+				return fmt.Errorf("row %d is missing required column %q", n, col.Title)
+			}
+		}
+		// keep extra values so validation can report them
+		if !cf.reordered && len(row) > len(cf.cols) {
+			filled = append(filled, row[len(cf.cols):]...)
+		}
+		return w.WriteEntry(dsio.Entry{Index: n, Value: filled})
+	})
+	if err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// readCSVHeader reads the header row of a csv body, nil if the body is empty
+func readCSVHeader(st *dataset.Structure, body io.Reader) ([]string, error) {
+	r := csv.NewReader(body)
+	r.FieldsPerRecord = -1
+	if opts, err := dataset.NewCSVOptions(st.FormatConfig); err == nil && opts != nil {
+		r.LazyQuotes = opts.LazyQuotes
+		if opts.Separator != rune(0) {
+			r.Comma = opts.Separator
+		}
+	}
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading csv header: %w", err)
+	}
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	return header, nil
+}
+
+// optionalColumnReader reads csv bodies whose schema marks some columns
+// optional. csv can't tell an empty field from a missing value, so empty
+// fields in optional columns read as null
+type optionalColumnReader struct {
+	dsio.EntryReader
+	optional []bool
+}
+
+var _ dsio.EntryReader = (*optionalColumnReader)(nil)
+
+// newBodyReader allocates an entry reader for a body, reading empty fields in
+// the optional columns of csv bodies as null
+func newBodyReader(st *dataset.Structure, file io.Reader) (dsio.EntryReader, error) {
+	rr, err := dsio.NewEntryReader(st, file)
+	if err != nil {
+		return nil, err
+	}
+	titles, ok := RequiredColumns(st)
+	if !ok || st.DataFormat() != dataset.CSVDataFormat {
+		return rr, nil
+	}
+	cols, _, err := tabular.ColumnsFromJSONSchema(st.Schema)
+	if err != nil {
+		return rr, nil
+	}
+	required := map[string]bool{}
+	for _, t := range titles {
+		required[t] = true
+	}
+	optional := make([]bool, len(cols))
+	for i, col := range cols {
+		optional[i] = !required[col.Title]
+	}
+	return &optionalColumnReader{EntryReader: rr, optional: optional}, nil
+}
+
+// ReadEntry implements the dsio.EntryReader interface
+func (r *optionalColumnReader) ReadEntry() (dsio.Entry, error) {
+	ent, err := r.EntryReader.ReadEntry()
+	if err != nil {
+		return ent, err
+	}
+	if row, ok := ent.Value.([]interface{}); ok {
+		for i, v := range row {
+			if i < len(r.optional) && r.optional[i] && v == "" {
+				row[i] = nil
+			}
+		}
+	}
+	return ent, nil
+}
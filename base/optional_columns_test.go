@@ -0,0 +1,125 @@
+package base
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestFillMissingColumns(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":     "array",
+			"required": []interface{}{"city", "pop"},
+			"items": []interface{}{
+				map[string]interface{}{"title": "city", "type": "string"},
+				map[string]interface{}{"title": "pop", "type": "integer"},
+				map[string]interface{}{"title": "notes", "type": []interface{}{"string", "null"}},
+			},
+		},
+	}
+	csvSt := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"headerRow": true},
+		Schema:       schema,
+	}
+	jsonSt := &dataset.Structure{Format: "json", Schema: schema}
+
+	fill := func(st *dataset.Structure, body string) (string, error) {
+		t.Helper()
+		f, err := FillMissingColumns(st, qfs.NewMemfileBytes("body."+st.Format, []byte(body)))
+		if err != nil {
+			return "", err
+		}
+		// rows are filled as the body is read, so row errors come from reading
+		data, err := ioutil.ReadAll(f)
+		return string(data), err
+	}
+
+	cases := []struct {
+		description string
+		st          *dataset.Structure
+		body        string
+		expect      string
+		err         string
+	}{
+		{"csv header omits optional column", csvSt, "pop,city\n10,toronto\n", "city,pop,notes\ntoronto,10,\n", ""},
+		{"csv header omits required column", csvSt, "city,notes\ntoronto,big\n", "", `body is missing required column "pop"`},
+		{"csv header has unknown column", csvSt, "city,pop,area\ntoronto,10,5\n", "", `body has column "area" that the schema doesn't define`},
+		{"json rows omit optional column", jsonSt, `[["toronto",10],["chatham",2,"small"]]`, `[["toronto",10,null],["chatham",2,"small"]]`, ""},
+		{"json row omits required column", jsonSt, `[["toronto"]]`, "", `row 0 is missing required column "pop"`},
+	}
+	for _, c := range cases {
+		got, err := fill(c.st, c.body)
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("%s: error mismatch. expected: %q, got: %v", c.description, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %s", c.description, err)
+		}
+		if diff := cmp.Diff(c.expect, got); diff != "" {
+			t.Errorf("%s: body mismatch (-want +got):\n%s", c.description, diff)
+		}
+	}
+
+	filled := &dataset.Structure{}
+	filled.Assign(csvSt)
+	filled.FormatConfig = map[string]interface{}{"headerRow": true}
+	ds := &dataset.Dataset{Structure: filled}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte("city,pop,notes\ntoronto,10,\nchatham,2,small\n")))
+	rows, err := GetBody(ds, 0, 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectRows := []interface{}{
+		[]interface{}{"toronto", int64(10), nil},
+		[]interface{}{"chatham", int64(2), "small"},
+	}
+	if diff := cmp.Diff(expectRows, rows); diff != "" {
+		t.Errorf("expected empty optional csv fields to read as null (-want +got):\n%s", diff)
+	}
+
+	strict := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+	body := qfs.NewMemfileBytes("body.json", []byte(`[[1]]`))
+	if got, err := FillMissingColumns(strict, body); err != nil || got != body {
+		t.Errorf("expected a schema without a required list to leave the body unchanged")
+	}
+}
+
+func TestValidateOptionalColumns(t *testing.T) {
+	st := &dataset.Structure{
+		Format:       "csv",
+		FormatConfig: map[string]interface{}{"headerRow": true},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":     "array",
+				"required": []interface{}{"city"},
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "pop", "type": []interface{}{"integer", "null"}},
+				},
+			},
+		},
+	}
+	errs, err := Validate(context.Background(), nil, qfs.NewMemfileBytes("body.csv", []byte("city\ntoronto\nchatham\n")), st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) > 0 {
+		t.Errorf("expected omitted optional column to validate, got errors: %v", errs)
+	}
+
+	_, err = Validate(context.Background(), nil, qfs.NewMemfileBytes("body.csv", []byte("pop\n10\n")), st)
+	if expect := `body is missing required column "city"`; err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+}
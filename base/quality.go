@@ -0,0 +1,237 @@
+package base
+
+import (
+	"fmt"
+
+	"github.com/qri-io/dataset"
+)
+
+const (
+	// DefaultMaxNullRate is the fraction of null entries a column can have
+	// before it's flagged
+	DefaultMaxNullRate = 0.5
+	// DefaultOutlierIQRMultiplier is the number of interquartile ranges a value
+	// can fall outside the middle half of a numeric column before it's flagged
+	// as an outlier. 3 matches the common definition of an "extreme" outlier
+	DefaultOutlierIQRMultiplier = 3.0
+)
+
+// QualityIssue enumerates the kinds of problems a quality check can flag
+type QualityIssue string
+
+const (
+	// QINullRate indicates a column has a high proportion of null values
+	QINullRate = QualityIssue("null_rate")
+	// QIConstant indicates every value in a column is the same
+	QIConstant = QualityIssue("constant")
+	// QIOutlier indicates a numeric column has values far outside the bulk of
+	// its distribution
+	QIOutlier = QualityIssue("outlier")
+)
+
+// QualityThresholds configure the heuristics used to flag quality issues
+type QualityThresholds struct {
+	// MaxNullRate is the highest acceptable fraction of null values in a
+	// column, between 0 and 1
+	MaxNullRate float64 `json:"maxNullRate"`
+	// OutlierIQRMultiplier sets how many interquartile ranges beyond the first
+	// & third quartiles a value must fall to be considered an outlier
+	OutlierIQRMultiplier float64 `json:"outlierIQRMultiplier"`
+}
+
+// DefaultQualityThresholds returns the thresholds qri uses when none are given
+func DefaultQualityThresholds() QualityThresholds {
+	return QualityThresholds{
+		MaxNullRate:          DefaultMaxNullRate,
+		OutlierIQRMultiplier: DefaultOutlierIQRMultiplier,
+	}
+}
+
+// QualityWarning describes a potential quality problem with a single column
+type QualityWarning struct {
+	Column  string       `json:"column"`
+	Issue   QualityIssue `json:"issue"`
+	Message string       `json:"message"`
+}
+
+// QualityReport inspects the stats component of a dataset, flagging columns
+// with high null rates, columns that only contain a single value, and numeric
+// columns with extreme outliers. Outliers are estimated from the column
+// histogram, so results are approximate
+func QualityReport(st *dataset.Structure, stats interface{}, th QualityThresholds) ([]QualityWarning, error) {
+	cols, err := statsColumns(st, stats)
+	if err != nil {
+		return nil, err
+	}
+
+	// the number of rows is the number of entries when the structure records
+	// it, otherwise the most values counted in any column
+	rows := 0
+	if st != nil {
+		rows = st.Entries
+	}
+	if rows == 0 {
+		for _, col := range cols {
+			if n := int(statNumber(col.stats["count"])); n > rows {
+				rows = n
+			}
+		}
+	}
+
+	warnings := []QualityWarning{}
+	for _, col := range cols {
+		count := statNumber(col.stats["count"])
+
+		nulls := float64(rows) - count
+		if col.stats["type"] == "null" {
+			// null columns only count nulls
+			nulls = count
+		}
+		if rows > 0 && nulls/float64(rows) > th.MaxNullRate {
+			warnings = append(warnings, QualityWarning{
+				Column:  col.name,
+				Issue:   QINullRate,
+				Message: fmt.Sprintf("%.1f%% of values are null", nulls/float64(rows)*100),
+			})
+		}
+
+		if count > 1 {
+			if val, ok := constantValue(col.stats); ok {
+				warnings = append(warnings, QualityWarning{
+					Column:  col.name,
+					Issue:   QIConstant,
+					Message: fmt.Sprintf("all %d values are %s", int(count), val),
+				})
+			}
+		}
+
+		if col.stats["type"] == "numeric" && th.OutlierIQRMultiplier > 0 {
+			if msg, ok := outliers(col.stats, th.OutlierIQRMultiplier); ok {
+				warnings = append(warnings, QualityWarning{
+					Column:  col.name,
+					Issue:   QIOutlier,
+					Message: msg,
+				})
+			}
+		}
+	}
+	return warnings, nil
+}
+
+// constantValue reports if a column's stats show it contains only one
+// distinct value, returning a description of that value
+func constantValue(stats map[string]interface{}) (string, bool) {
+	switch stats["type"] {
+	case "numeric":
+		min, max := statNumber(stats["min"]), statNumber(stats["max"])
+		if min == max {
+			return fmt.Sprintf("%g", min), true
+		}
+	case "string":
+		if statNumber(stats["unique"]) == 1 {
+			return "the same string", true
+		}
+	case "boolean":
+		if statNumber(stats["trueCount"]) == 0 {
+			return "false", true
+		}
+		if statNumber(stats["falseCount"]) == 0 {
+			return "true", true
+		}
+	}
+	return "", false
+}
+
+// outliers checks the range of a numeric column against quartiles estimated
+// from the column's histogram
+func outliers(stats map[string]interface{}, multiplier float64) (string, bool) {
+	bins, freqs := histogram(stats["histogram"])
+	q1, ok := histogramQuantile(bins, freqs, 0.25)
+	if !ok {
+		return "", false
+	}
+	q3, _ := histogramQuantile(bins, freqs, 0.75)
+	iqr := q3 - q1
+	if iqr <= 0 {
+		return "", false
+	}
+
+	lower, upper := q1-multiplier*iqr, q3+multiplier*iqr
+	min, max := statNumber(stats["min"]), statNumber(stats["max"])
+	switch {
+	case min < lower && max > upper:
+		return fmt.Sprintf("min %g and max %g fall outside the expected range %g to %g", min, max, lower, upper), true
+	case min < lower:
+		return fmt.Sprintf("min %g falls below the expected range %g to %g", min, lower, upper), true
+	case max > upper:
+		return fmt.Sprintf("max %g falls above the expected range %g to %g", max, lower, upper), true
+	}
+	return "", false
+}
+
+// histogram reads bins & frequencies from a histogram stat, which is a map
+// of slices when freshly calculated & a map of generic values when decoded
+func histogram(v interface{}) (bins, freqs []float64) {
+	switch h := v.(type) {
+	case map[string][]float64:
+		return h["bins"], h["frequencies"]
+	case map[string]interface{}:
+		return statNumbers(h["bins"]), statNumbers(h["frequencies"])
+	}
+	return nil, nil
+}
+
+// histogramQuantile estimates the value at quantile p from a histogram,
+// returning the first bin the cumulative frequency reaches p in
+func histogramQuantile(bins, freqs []float64, p float64) (float64, bool) {
+	if len(freqs) == 0 || len(bins) < len(freqs) {
+		return 0, false
+	}
+	total := 0.0
+	for _, f := range freqs {
+		total += f
+	}
+	if total == 0 {
+		return 0, false
+	}
+
+	sum := 0.0
+	for i, f := range freqs {
+		sum += f
+		if sum >= p*total {
+			return bins[i], true
+		}
+	}
+	return bins[len(freqs)-1], true
+}
+
+// statNumber converts a numeric stat value to a float64, returning 0 for
+// missing or non-numeric values
+func statNumber(v interface{}) float64 {
+	switch x := v.(type) {
+	case float64:
+		return x
+	case float32:
+		return float64(x)
+	case int:
+		return float64(x)
+	case int64:
+		return float64(x)
+	}
+	return 0
+}
+
+// statNumbers converts a list of numeric stat values to float64s
+func statNumbers(v interface{}) []float64 {
+	switch x := v.(type) {
+	case []float64:
+		return x
+	case []interface{}:
+		nums := make([]float64, len(x))
+		for i, n := range x {
+			nums[i] = statNumber(n)
+		}
+		return nums
+	}
+	return nil
+}
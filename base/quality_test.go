@@ -0,0 +1,71 @@
+package base
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+)
+
+func TestQualityReport(t *testing.T) {
+	st := &dataset.Structure{
+		Format:  "csv",
+		Entries: 10,
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "pop", "type": "integer"},
+					map[string]interface{}{"title": "country", "type": "string"},
+					map[string]interface{}{"title": "in_usa", "type": "boolean"},
+				},
+			},
+		},
+	}
+
+	var stats interface{}
+	data := `[
+		{"type":"string","count":4,"unique":4},
+		{"type":"numeric","count":10,"min":10,"max":5000,"histogram":{
+			"bins":[10,11,12,13,14,15,16,17,18,5000,5001],
+			"frequencies":[1,1,1,1,1,1,1,1,1,1]
+		}},
+		{"type":"string","count":10,"unique":1},
+		{"type":"boolean","count":10,"trueCount":7,"falseCount":3}
+	]`
+	if err := json.Unmarshal([]byte(data), &stats); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := QualityReport(st, stats, DefaultQualityThresholds())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []QualityWarning{
+		{Column: "city", Issue: QINullRate, Message: "60.0% of values are null"},
+		{Column: "pop", Issue: QIOutlier, Message: "max 5000 falls above the expected range -3 to 32"},
+		{Column: "country", Issue: QIConstant, Message: "all 10 values are the same string"},
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+
+	// loosening thresholds silences warnings
+	th := QualityThresholds{MaxNullRate: 0.75, OutlierIQRMultiplier: 1000}
+	if got, err = QualityReport(st, stats, th); err != nil {
+		t.Fatal(err)
+	}
+	expect = []QualityWarning{
+		{Column: "country", Issue: QIConstant, Message: "all 10 values are the same string"},
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("loose thresholds mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := QualityReport(st, "not stats", DefaultQualityThresholds()); err == nil {
+		t.Error("expected invalid stats to error")
+	}
+}
@@ -155,6 +155,75 @@ func RemoveNVersionsFromStore(ctx context.Context, r repo.Repo, author *profile.
 	return info, nil
 }
 
+// SquashVersions collapses the history of a dataset so only the most recent
+// keep versions and a single base version remain. The base version is the one
+// that immediately precedes the kept versions, all older versions are removed
+// from the store and recorded as squashed in the logbook. Squashing rewrites
+// history, peers holding the full log will no longer share it. SquashVersions
+// returns the number of versions removed
+func SquashVersions(ctx context.Context, r repo.Repo, author *profile.Profile, ref dsref.Ref, keep int) (int, error) {
+	if r == nil {
+		return 0, fmt.Errorf("need a repo")
+	}
+	if keep < 0 {
+		return 0, fmt.Errorf("invalid 'keep', must be greater than or equal to 0")
+	}
+
+	versions, err := storedVersions(ctx, r, ref)
+	if err != nil {
+		return 0, err
+	}
+	if len(versions) <= keep+1 {
+		return 0, fmt.Errorf("nothing to squash: %s has %d versions", ref.Human(), len(versions))
+	}
+
+	return squashAfter(ctx, r, author, ref, versions, keep)
+}
+
+// storedVersions lists the versions in a dataset's history, newest first,
+// leaving out runs that didn't save a version
+func storedVersions(ctx context.Context, r repo.Repo, ref dsref.Ref) ([]dsref.VersionInfo, error) {
+	items, err := DatasetLog(ctx, r, ref, -1, 0, "", false)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]dsref.VersionInfo, 0, len(items))
+	for _, vi := range items {
+		if vi.Path != "" {
+			versions = append(versions, vi)
+		}
+	}
+	return versions, nil
+}
+
+// squashAfter records every version older than versions[keep] as squashed
+// & removes them from the store. versions must be listed newest first
+func squashAfter(ctx context.Context, r repo.Repo, author *profile.Profile, ref dsref.Ref, versions []dsref.VersionInfo, keep int) (int, error) {
+	initID, err := r.Logbook().RefToInitID(ref)
+	if err != nil {
+		return 0, err
+	}
+
+	base := versions[keep]
+	squashed := versions[keep+1:]
+	if err = r.Logbook().WriteVersionSquash(ctx, author, initID, len(squashed), base.Path); err != nil {
+		return 0, err
+	}
+
+	for _, vi := range squashed {
+		if vi.Foreign {
+			continue
+		}
+		// blocks shared with kept versions remain stored under those versions
+		if err = r.Filesystem().Delete(ctx, vi.Path); err != nil {
+			log.Debugf("removing squashed version %q: %s", vi.Path, err)
+			return 0, err
+		}
+	}
+
+	return len(squashed), nil
+}
+
 // This is inefficient and not great style, use it here just as a convenience.
 func appendString(first, second string) string {
 	if first == "" {
@@ -6,8 +6,10 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/dstest"
 	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base/dsfs"
 	"github.com/qri-io/qri/dsref"
 	"github.com/qri-io/qri/repo"
 )
@@ -103,6 +105,64 @@ func TestRemoveNVersionsFromStore(t *testing.T) {
 
 }
 
+func TestSquashVersions(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+	ctx := run.Context
+	r := run.Repo
+	author := r.Logbook().Owner()
+
+	refs := []dsref.Ref{}
+	for i := 1; i <= 5; i++ {
+		ds := run.BuildDataset("squash_test", "json")
+		ds.Meta = &dataset.Meta{Title: fmt.Sprintf("version %d", i)}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(fmt.Sprintf("[%d]", i))))
+		ref, err := run.SaveDataset(ds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		refs = append(refs, ref)
+	}
+	head := refs[len(refs)-1]
+
+	if _, err := SquashVersions(ctx, r, author, head, -1); err == nil {
+		t.Error("expected negative keep to error")
+	}
+	if _, err := SquashVersions(ctx, r, author, head, 4); err == nil {
+		t.Error("expected squash that keeps all versions to error")
+	}
+
+	squashed, err := SquashVersions(ctx, r, author, head, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if squashed != 2 {
+		t.Errorf("squashed count mismatch. want: 2, got: %d", squashed)
+	}
+
+	items, err := DatasetLog(ctx, r, head, -1, 0, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, item := range items {
+		got = append(got, item.Path)
+	}
+	expect := []string{refs[4].Path, refs[3].Path, refs[2].Path}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("history mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, ref := range refs[:2] {
+		if _, err := dsfs.LoadDataset(ctx, r.Filesystem(), ref.Path); err == nil {
+			t.Errorf("expected squashed version %q to be removed from the store", ref.Path)
+		}
+	}
+	if _, err := dsfs.LoadDataset(ctx, r.Filesystem(), refs[2].Path); err != nil {
+		t.Errorf("expected base version to remain in the store: %s", err)
+	}
+}
+
 // takes store s, where datasets have been added/removed
 // takes a list of refs, where refs[0] is the initial (oldest) dataset
 // take int n where n is the number of MOST RECENT datasets that should
@@ -1,14 +1,20 @@
 package base
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
 	"io/ioutil"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/dsviz"
+	"github.com/qri-io/dataset/tabular"
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/repo"
 	"github.com/russross/blackfriday/v2"
@@ -160,6 +166,69 @@ func Render(ctx context.Context, r repo.Repo, ds *dataset.Dataset, tmplData []by
 	return ioutil.ReadAll(data)
 }
 
+// MaxInlineBodySize caps the size in bytes of a body RenderInline will embed,
+// keeping self-contained visualizations small enough to share
+const MaxInlineBodySize = 10 * 1024 * 1024
+
+// InlineBodyElementID is the id of the script element RenderInline writes the
+// body to. Templates can read the body with
+// JSON.parse(document.getElementById("qri-body").textContent)
+const InlineBodyElementID = "qri-body"
+
+// RenderInline renders a viz template like Render, producing a self-contained
+// HTML document with the full body of an opened dataset embedded as JSON in a
+// script element, so visualizations work without access to qri. Bodies larger
+// than MaxInlineBodySize are an error
+func RenderInline(ctx context.Context, r repo.Repo, ds *dataset.Dataset, tmplData []byte) ([]byte, error) {
+	body := ds.BodyFile()
+	if body == nil {
+		return nil, fmt.Errorf("no body to inline")
+	}
+	if ds.Structure != nil && ds.Structure.Length > MaxInlineBodySize {
+		return nil, fmt.Errorf("body is too large to inline: %d bytes is larger than %d", ds.Structure.Length, MaxInlineBodySize)
+	}
+
+	// read the body into memory so it can be both inlined & used by templates
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes(body.FileName(), data))
+	entries, err := GetBody(ds, -1, 0, true)
+	if err != nil {
+		return nil, err
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes(body.FileName(), data))
+
+	// json.Marshal escapes "<", ">" & "&", so the body can't close the script
+	bodyJSON, err := json.Marshal(entries)
+	if err != nil {
+		return nil, err
+	}
+	if len(bodyJSON) > MaxInlineBodySize {
+		return nil, fmt.Errorf("body is too large to inline: %d bytes is larger than %d", len(bodyJSON), MaxInlineBodySize)
+	}
+
+	html, err := Render(ctx, r, ds, tmplData)
+	if err != nil {
+		return nil, err
+	}
+
+	script := fmt.Sprintf("<script type=\"application/json\" id=\"%s\">%s</script>\n", InlineBodyElementID, bodyJSON)
+	lower := bytes.ToLower(html)
+	i := bytes.LastIndex(lower, []byte("</body>"))
+	if i == -1 {
+		i = bytes.LastIndex(lower, []byte("</html>"))
+	}
+	if i == -1 {
+		return append(html, script...), nil
+	}
+	res := make([]byte, 0, len(html)+len(script))
+	res = append(res, html[:i]...)
+	res = append(res, script...)
+	return append(res, html[i:]...), nil
+}
+
 // RenderReadme converts the markdown from the file into html.
 func RenderReadme(ctx context.Context, file qfs.File) ([]byte, error) {
 	data, err := ioutil.ReadAll(file)
@@ -173,3 +242,194 @@ func RenderReadme(ctx context.Context, file qfs.File) ([]byte, error) {
 	htmlBytes := bluemonday.UGCPolicy().SanitizeBytes(unsafe)
 	return htmlBytes, nil
 }
+
+// MaxBodyTableRows caps the number of rows RenderBodyTable writes, keeping
+// HTML previews of large bodies a reasonable size
+const MaxBodyTableRows = 1000
+
+var bodyTableTemplate = template.Must(template.New("body").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>{{ .Title }}</title>
+  <style type="text/css">
+    body { margin: 0; font-family: "avenir next", "avenir", sans-serif; font-size: 14px; }
+    table { border-collapse: collapse; width: 100%; }
+    th, td { padding: 4px 8px; border-bottom: 1px solid #EBEBEB; text-align: left; white-space: nowrap; }
+    th { position: sticky; top: 0; background: #0061A6; color: white; font-weight: normal; }
+    tr:nth-child(even) td { background: #F7F7F7; }
+    td.null { color: #BEBEBE; }
+    .note { padding: 8px; color: #999; }
+  </style>
+</head>
+<body>
+<table>
+  <thead>
+    <tr>{{ range .Headers }}<th>{{ . }}</th>{{ end }}</tr>
+  </thead>
+  <tbody>
+{{- range .Rows }}
+    <tr>{{ range . }}{{ if .Null }}<td class="null">null</td>{{ else }}<td>{{ .Text }}</td>{{ end }}{{ end }}</tr>
+{{- end }}
+  </tbody>
+</table>
+{{ with .Note }}<p class="note">{{ . }}</p>
+{{ end -}}
+</body>
+</html>
+`))
+
+// tableCell is a single formatted value of an HTML body table
+type tableCell struct {
+	Text string
+	Null bool
+}
+
+// RenderBodyTable writes the body of an opened dataset as a styled HTML
+// table, with column titles from the schema as headers. Rows are selected
+// with the same limit, offset & all semantics as GetBody, capped at
+// MaxBodyTableRows. When the table doesn't show every row of the body a note
+// saying which rows are included is added below the table
+func RenderBodyTable(ds *dataset.Dataset, limit, offset int, all bool) ([]byte, error) {
+	if all {
+		offset = 0
+		limit = MaxBodyTableRows
+	} else if limit > MaxBodyTableRows {
+		limit = MaxBodyTableRows
+	}
+
+	body, err := GetBody(ds, limit, offset, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		headers []string
+		rows    [][]tableCell
+	)
+	if ds.Structure != nil {
+		if cols, _, err := tabular.ColumnsFromJSONSchema(ds.Structure.Schema); err == nil {
+			headers = cols.Titles()
+		}
+	}
+
+	switch entries := body.(type) {
+	case []interface{}:
+		var keys []string
+		for _, entry := range entries {
+			if obj, ok := entry.(map[string]interface{}); ok {
+				keys = mergeKeys(keys, obj)
+			}
+		}
+		if keys != nil {
+			headers = keys
+		}
+		for _, entry := range entries {
+			switch e := entry.(type) {
+			case []interface{}:
+				rows = append(rows, tableRow(e))
+			case map[string]interface{}:
+				vals := make([]interface{}, len(keys))
+				for i, key := range keys {
+					vals[i] = e[key]
+				}
+				rows = append(rows, tableRow(vals))
+			default:
+				rows = append(rows, tableRow([]interface{}{e}))
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(entries))
+		for key := range entries {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		headers = []string{"key", "value"}
+		for _, key := range keys {
+			rows = append(rows, tableRow([]interface{}{key, entries[key]}))
+		}
+	}
+
+	// pad headers so every column has one
+	for _, row := range rows {
+		for i := len(headers); i < len(row); i++ {
+			headers = append(headers, fmt.Sprintf("field_%d", i+1))
+		}
+	}
+
+	title := ds.Name
+	if ds.Peername != "" {
+		title = fmt.Sprintf("%s/%s", ds.Peername, ds.Name)
+	}
+
+	buf := &bytes.Buffer{}
+	err = bodyTableTemplate.Execute(buf, map[string]interface{}{
+		"Title":   title,
+		"Headers": headers,
+		"Rows":    rows,
+		"Note":    bodyTableNote(ds, offset, len(rows)),
+	})
+	return buf.Bytes(), err
+}
+
+// bodyTableNote describes which rows a body table shows, returning the empty
+// string when the table shows the entire body
+func bodyTableNote(ds *dataset.Dataset, offset, shown int) string {
+	total := 0
+	if ds.Structure != nil {
+		total = ds.Structure.Entries
+	}
+	switch {
+	case total > 0 && (offset > 0 || shown < total):
+		if shown == 0 {
+			return fmt.Sprintf("preview truncated, showing 0 of %d rows", total)
+		}
+		return fmt.Sprintf("preview truncated, showing rows %d-%d of %d", offset+1, offset+shown, total)
+	case total == 0 && shown == MaxBodyTableRows:
+		return fmt.Sprintf("preview truncated, showing the first %d rows", shown)
+	}
+	return ""
+}
+
+func mergeKeys(keys []string, obj map[string]interface{}) []string {
+	added := make([]string, 0, len(obj))
+	for key := range obj {
+		if !containsString(keys, key) {
+			added = append(added, key)
+		}
+	}
+	sort.Strings(added)
+	return append(keys, added...)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// tableRow formats a row of values as table cells. nested values are written
+// as JSON
+func tableRow(vals []interface{}) []tableCell {
+	cells := make([]tableCell, len(vals))
+	for i, v := range vals {
+		switch x := v.(type) {
+		case nil:
+			cells[i] = tableCell{Null: true}
+		case string:
+			cells[i] = tableCell{Text: x}
+		case map[string]interface{}, []interface{}:
+			data, err := json.Marshal(x)
+			if err != nil {
+				data = []byte(fmt.Sprintf("%v", x))
+			}
+			cells[i] = tableCell{Text: string(data)}
+		default:
+			cells[i] = tableCell{Text: fmt.Sprintf("%v", x)}
+		}
+	}
+	return cells
+}
@@ -2,9 +2,11 @@ package base
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
 	"github.com/qri-io/qfs"
 )
 
@@ -76,3 +78,89 @@ done`))
 		t.Errorf("body component (-want +got):\n%s", diff)
 	}
 }
+
+func TestRenderBodyTable(t *testing.T) {
+	ds := &dataset.Dataset{
+		Peername: "me",
+		Name:     "cities",
+		Structure: &dataset.Structure{
+			Format:  "json",
+			Entries: 3,
+			Schema: map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "array",
+					"items": []interface{}{
+						map[string]interface{}{"title": "city", "type": "string"},
+						map[string]interface{}{"title": "pop", "type": "integer"},
+					},
+				},
+			},
+		},
+	}
+	setBody := func() {
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(`[["<toronto>",50000000],["new york",null],["chicago",300000]]`)))
+	}
+
+	setBody()
+	got, err := RenderBodyTable(ds, -1, 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(got)
+	for _, expect := range []string{
+		"<title>me/cities</title>",
+		"<tr><th>city</th><th>pop</th></tr>",
+		"<tr><td>&lt;toronto&gt;</td><td>50000000</td></tr>",
+		`<tr><td>new york</td><td class="null">null</td></tr>`,
+		"<tr><td>chicago</td><td>300000</td></tr>",
+	} {
+		if !strings.Contains(html, expect) {
+			t.Errorf("expected html to contain %q. got:\n%s", expect, html)
+		}
+	}
+	if strings.Contains(html, "truncated") {
+		t.Errorf("expected complete table not to be marked as truncated")
+	}
+
+	setBody()
+	if got, err = RenderBodyTable(ds, 1, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	html = string(got)
+	if strings.Contains(html, "toronto") || !strings.Contains(html, "new york") || strings.Contains(html, "chicago") {
+		t.Errorf("expected limit & offset to select only the second row. got:\n%s", html)
+	}
+	if expect := "preview truncated, showing rows 2-2 of 3"; !strings.Contains(html, expect) {
+		t.Errorf("expected html to contain note %q. got:\n%s", expect, html)
+	}
+}
+
+func TestRenderInline(t *testing.T) {
+	ctx := context.Background()
+	ds := &dataset.Dataset{
+		Name: "cities",
+		Structure: &dataset.Structure{
+			Format: "json",
+			Length: 44,
+			Schema: dataset.BaseSchemaArray,
+		},
+	}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(`[["<toronto>",50000000],["chicago",300000]]`)))
+
+	got, err := RenderInline(ctx, nil, ds, []byte(`<html><body><h1>{{ ds.name }}</h1>{{ len allBodyEntries }} rows</body></html>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `<html><body><h1>cities</h1>2 rows<script type="application/json" id="qri-body">[["\u003ctoronto\u003e",50000000],["chicago",300000]]</script>
+</body></html>`
+	if diff := cmp.Diff(expect, string(got)); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+
+	ds.Structure.Length = MaxInlineBodySize + 1
+	_, err = RenderInline(ctx, nil, ds, nil)
+	if err == nil || !strings.Contains(err.Error(), "body is too large to inline") {
+		t.Errorf("expected body size error, got: %v", err)
+	}
+}
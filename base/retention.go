@@ -0,0 +1,64 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/profile"
+	"github.com/qri-io/qri/repo"
+)
+
+// PruneVersions removes versions of a dataset that fall outside a retention
+// policy: versions past the keep most recent, or committed before now minus
+// maxAge. Zero values of keep & maxAge don't limit history. The latest
+// version is always kept, and so is any version pushed to a remote or listed
+// in pinned, along with every version newer than it, because versions are
+// pruned from the oldest end of history. pinned holds the paths of versions
+// that are in use, like versions being pushed. Pruned versions are recorded
+// as squashed in the logbook & removed from the store. PruneVersions returns
+// the number of versions removed
+func PruneVersions(ctx context.Context, r repo.Repo, author *profile.Profile, ref dsref.Ref, keep int, maxAge time.Duration, now time.Time, pinned map[string]bool) (int, error) {
+	if r == nil {
+		return 0, fmt.Errorf("need a repo")
+	}
+	if keep < 0 || maxAge < 0 {
+		return 0, fmt.Errorf("invalid retention policy, limits must not be negative")
+	}
+
+	versions, err := storedVersions(ctx, r, ref)
+	if err != nil {
+		return 0, err
+	}
+
+	// kept is the number of newest versions that stay in history
+	kept := len(versions)
+	if keep > 0 && keep < kept {
+		kept = keep
+	}
+	if maxAge > 0 {
+		cutoff := now.Add(-maxAge)
+		for i, vi := range versions[:kept] {
+			if vi.CommitTime.Before(cutoff) {
+				kept = i
+				break
+			}
+		}
+	}
+	if kept < 1 {
+		kept = 1
+	}
+	for i := len(versions) - 1; i >= kept; i-- {
+		if versions[i].Published || pinned[versions[i].Path] {
+			kept = i + 1
+			break
+		}
+	}
+	if kept >= len(versions) {
+		return 0, nil
+	}
+
+	// squashing keeps one base version past the kept count
+	return squashAfter(ctx, r, author, ref, versions, kept-1)
+}
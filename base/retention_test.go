@@ -0,0 +1,116 @@
+package base
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/dsref"
+)
+
+func TestPruneVersions(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+	ctx := run.Context
+	r := run.Repo
+	author := r.Logbook().Owner()
+
+	saveVersions := func(name string, n int) []dsref.Ref {
+		refs := []dsref.Ref{}
+		for i := 1; i <= n; i++ {
+			ds := run.BuildDataset(name, "json")
+			ds.Meta = &dataset.Meta{Title: fmt.Sprintf("version %d", i)}
+			ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(fmt.Sprintf("[%d]", i))))
+			ref, err := run.SaveDataset(ds)
+			if err != nil {
+				t.Fatal(err)
+			}
+			refs = append(refs, ref)
+		}
+		return refs
+	}
+	history := func(ref dsref.Ref) []string {
+		items, err := DatasetLog(ctx, r, ref, -1, 0, "", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths := []string{}
+		for _, item := range items {
+			paths = append(paths, item.Path)
+		}
+		return paths
+	}
+
+	refs := saveVersions("keep_test", 5)
+	head := refs[4]
+	if _, err := PruneVersions(ctx, r, author, head, -1, 0, time.Now(), nil); err == nil {
+		t.Error("expected a negative limit to error")
+	}
+	pruned, err := PruneVersions(ctx, r, author, head, 2, 0, time.Now(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 3 {
+		t.Errorf("pruned count mismatch. want: 3, got: %d", pruned)
+	}
+	if diff := cmp.Diff([]string{refs[4].Path, refs[3].Path}, history(head)); diff != "" {
+		t.Errorf("history mismatch (-want +got):\n%s", diff)
+	}
+	if pruned, err = PruneVersions(ctx, r, author, head, 2, 0, time.Now(), nil); err != nil || pruned != 0 {
+		t.Errorf("expected history within the policy to be left alone, got %d pruned, err: %v", pruned, err)
+	}
+
+	refs = saveVersions("age_test", 4)
+	head = refs[3]
+	items, err := DatasetLog(ctx, r, head, -1, 0, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// versions committed before the third version are too old
+	now := items[1].CommitTime.Add(time.Hour)
+	if pruned, err = PruneVersions(ctx, r, author, head, 0, time.Hour, now, nil); err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 2 {
+		t.Errorf("pruned count mismatch. want: 2, got: %d", pruned)
+	}
+	if pruned, err = PruneVersions(ctx, r, author, head, 0, time.Nanosecond, now.Add(time.Hour), nil); err != nil || pruned != 1 {
+		t.Errorf("expected the latest version to always be kept, got %d pruned, err: %v", pruned, err)
+	}
+
+	// push the second version, then save two more
+	refs = saveVersions("pushed_test", 2)
+	initID, err := r.Logbook().RefToInitID(refs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := r.Logbook().WriteRemotePush(ctx, author, initID, 1, "registry"); err != nil {
+		t.Fatal(err)
+	}
+	refs = append(refs, saveVersions("pushed_test", 2)...)
+	head = refs[3]
+	if pruned, err = PruneVersions(ctx, r, author, head, 1, 0, time.Now(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 1 {
+		t.Errorf("pruned count mismatch. want: 1, got: %d", pruned)
+	}
+	if diff := cmp.Diff([]string{refs[3].Path, refs[2].Path, refs[1].Path}, history(head)); diff != "" {
+		t.Errorf("expected pushed version & newer versions to be kept (-want +got):\n%s", diff)
+	}
+	// versions being pushed are kept like pushed versions
+	refs = saveVersions("pinned_test", 4)
+	head = refs[3]
+	if pruned, err = PruneVersions(ctx, r, author, head, 1, 0, time.Now(), map[string]bool{refs[1].Path: true}); err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 1 {
+		t.Errorf("pruned count mismatch. want: 1, got: %d", pruned)
+	}
+	if diff := cmp.Diff([]string{refs[3].Path, refs[2].Path, refs[1].Path}, history(head)); diff != "" {
+		t.Errorf("expected pinned version & newer versions to be kept (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,109 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/tabular"
+)
+
+// rowColumn locates a column in body rows. array rows are read at the
+// column's position in a tabular schema, object rows by key
+type rowColumn struct {
+	title  string
+	index  int
+	titles []string
+	// format is the column's schema "format" keyword, eg: "date"
+	format string
+}
+
+// newRowColumn looks up title in a structure's schema. index is -1 when the
+// schema isn't tabular or doesn't have the column
+func newRowColumn(st *dataset.Structure, title string) rowColumn {
+	c := rowColumn{title: title, index: -1}
+	if st == nil || st.Schema == nil {
+		return c
+	}
+	cols, _, err := tabular.ColumnsFromJSONSchema(st.Schema)
+	if err != nil {
+		return c
+	}
+	c.titles = cols.Titles()
+	for i, t := range c.titles {
+		if t == title {
+			c.index = i
+			c.format, _ = cols[i].Validation["format"].(string)
+			break
+		}
+	}
+	return c
+}
+
+// missing returns an error if the schema is tabular and doesn't have the
+// column
+func (c rowColumn) missing() error {
+	if c.titles != nil && c.index < 0 {
+		return unknownColumnError(c.title, c.titles)
+	}
+	return nil
+}
+
+// value reads the column from row i. found is false when the row doesn't
+// have the column
+func (c rowColumn) value(i int, row interface{}) (val interface{}, found bool, err error) {
+	switch r := row.(type) {
+	case []interface{}:
+		if c.index < 0 {
+			return nil, false, unknownColumnError(c.title, c.titles)
+		}
+		if c.index < len(r) {
+			return r[c.index], true, nil
+		}
+		return nil, false, nil
+	case map[string]interface{}:
+		val, found = r[c.title]
+		return val, found, nil
+	}
+	return nil, false, fmt.Errorf("row %d isn't an array or object, can't read column %q", i, c.title)
+}
+
+// keyText is the text form body values are compared by when matching keys.
+// floats are written without an exponent, so the float 1e6 & the integer
+// 1000000 have the same key
+func keyText(val interface{}) string {
+	if f, ok := val.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(val)
+}
+
+// errStopRows stops eachRow early without an error
+var errStopRows = errors.New("stop reading rows")
+
+// eachRow streams the entries of a body, calling fn with each entry in
+// order. fn can return errStopRows to stop reading early
+func eachRow(st *dataset.Structure, file io.Reader, fn func(i int, ent dsio.Entry) error) error {
+	rr, err := newBodyReader(st, file)
+	if err != nil {
+		return fmt.Errorf("error allocating data reader: %s", err)
+	}
+	defer rr.Close()
+
+	for i := 0; ; i++ {
+		ent, err := rr.ReadEntry()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := fn(i, ent); err == errStopRows {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package base
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+func TestEachRow(t *testing.T) {
+	st := &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}
+
+	got := []interface{}{}
+	err := eachRow(st, strings.NewReader(`[1,2,3]`), func(i int, ent dsio.Entry) error {
+		got = append(got, ent.Value)
+		if i == 1 {
+			return errStopRows
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("expected reading to stop after 2 rows, got: %v", got)
+	}
+
+	if err := eachRow(st, strings.NewReader(`[1,`), func(int, dsio.Entry) error { return nil }); err == nil {
+		t.Error("expected a malformed body to error")
+	}
+}
+
+func TestRowColumn(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "json",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "id", "type": "integer"},
+					map[string]interface{}{"title": "name", "type": "string"},
+				},
+			},
+		},
+	}
+
+	col := newRowColumn(st, "name")
+	if err := col.missing(); err != nil {
+		t.Fatal(err)
+	}
+	if val, found, err := col.value(0, []interface{}{1, "a"}); err != nil || !found || val != "a" {
+		t.Errorf("unexpected array row value: %v %t %v", val, found, err)
+	}
+	if _, found, err := col.value(0, []interface{}{1}); err != nil || found {
+		t.Errorf("expected a short row not to have the column, got: %t %v", found, err)
+	}
+	if val, found, err := col.value(0, map[string]interface{}{"name": "b"}); err != nil || !found || val != "b" {
+		t.Errorf("unexpected object row value: %v %t %v", val, found, err)
+	}
+	if _, _, err := col.value(3, "scalar"); err == nil {
+		t.Error("expected a scalar row to error")
+	}
+
+	if err := newRowColumn(st, "missing").missing(); err == nil {
+		t.Error("expected a missing column to error")
+	}
+}
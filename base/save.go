@@ -86,6 +86,12 @@ func SaveDataset(
 		}
 	}
 
+	if sw.AllowSchemaWiden {
+		if err = widenSchema(changes, prev); err != nil {
+			return nil, err
+		}
+	}
+
 	if !sw.Replace {
 		// Treat the changes as a set of patches applied to the previous dataset
 		mutable.Assign(changes)
@@ -97,6 +103,33 @@ func SaveDataset(
 		return
 	}
 
+	missing, err := MissingMetaFields(changes.Meta, sw.RequiredMeta)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMissingRequiredMeta, strings.Join(missing, ", "))
+	}
+
+	// store bodies that omit optional columns with those columns filled in
+	if changes.BodyFile() != nil && changes.Structure != nil {
+		var body qfs.File
+		if body, err = FillMissingColumns(changes.Structure, changes.BodyFile()); err != nil {
+			return nil, err
+		}
+		changes.SetBodyFile(body)
+	}
+
+	if err = setColumnBlocks(ctx, fs, writeDest, changes, prev, sw.ColumnarStorage); err != nil {
+		return nil, err
+	}
+
+	if sw.HashComponents {
+		if err = hashComponents(ctx, fs, changes, &sw); err != nil {
+			return nil, fmt.Errorf("hashing components: %w", err)
+		}
+	}
+
 	// let's make history, if it exists
 	changes.PreviousPath = prevPath
 
@@ -115,6 +148,70 @@ func SaveDataset(
 	return ds, nil
 }
 
+// widenSchema gives changes a schema that adds the columns of a new body the
+// previous version's schema doesn't define, recording added columns in the
+// commit message. changes that set a schema of their own are left as-is
+func widenSchema(changes, prev *dataset.Dataset) error {
+	if changes.BodyFile() == nil || prev.Structure == nil || prev.Structure.Schema == nil {
+		return nil
+	}
+	if changes.Structure != nil && changes.Structure.Schema != nil {
+		return nil
+	}
+	if err := detect.Structure(changes); err != nil {
+		return err
+	}
+
+	schema, added, err := WidenSchema(prev.Structure, changes.Structure)
+	if err != nil {
+		return err
+	}
+	changes.Structure.Schema = schema
+	if len(added) == 0 {
+		return nil
+	}
+
+	if changes.Commit == nil {
+		changes.Commit = &dataset.Commit{}
+	}
+	msg := fmt.Sprintf("widened schema, added columns: %s", strings.Join(added, ", "))
+	if changes.Commit.Message != "" {
+		msg = changes.Commit.Message + "\n\n" + msg
+	}
+	changes.Commit.Message = msg
+	return nil
+}
+
+// setColumnBlocks keeps the column block layout of a structure in step with
+// the body. a new body drops any inherited layout unless columnar storage is
+// requested, which writes blocks for the new body, or the previous body if the
+// body isn't changing and has no blocks yet
+func setColumnBlocks(ctx context.Context, fs, writeDest qfs.Filesystem, ds, prev *dataset.Dataset, columnar bool) error {
+	if ds.BodyFile() != nil {
+		DropColumnBlocks(ds.Structure)
+		if columnar {
+			return WriteColumnBlocks(ctx, writeDest, ds)
+		}
+		return nil
+	}
+	if !columnar || HasColumnBlocks(ds.Structure) {
+		return nil
+	}
+	if prev == nil || prev.BodyPath == "" || ds.Structure == nil {
+		return fmt.Errorf("columnar storage requires a body")
+	}
+
+	// build blocks from a fresh copy of the previous body, leaving the body of
+	// prev unread for comparison with this version
+	body, err := dsfs.LoadBody(ctx, fs, prev)
+	if err != nil {
+		return err
+	}
+	src := &dataset.Dataset{Structure: ds.Structure}
+	src.SetBodyFile(body)
+	return WriteColumnBlocks(ctx, writeDest, src)
+}
+
 // CreateDataset uses dsfs to add a dataset to a repo's store, updating the refstore
 func CreateDataset(ctx context.Context, r repo.Repo, writeDest qfs.Filesystem, author *profile.Profile, ds, dsPrev *dataset.Dataset, sw SaveSwitches) (res *dataset.Dataset, err error) {
 	log.Debugw("CreateDataset", "ds.ID", ds.ID)
@@ -136,7 +233,7 @@ func CreateDataset(ctx context.Context, r repo.Repo, writeDest qfs.Filesystem, a
 		return nil, fmt.Errorf("invalid dataset: %w", err)
 	}
 
-	if path, err = dsfs.CreateDataset(ctx, r.Filesystem(), writeDest, r.Bus(), ds, dsPrev, author.PrivKey, sw); err != nil {
+	if path, err = dsfs.CreateDataset(ctx, r.Filesystem(), writeDest, r.Bus(), ds, dsPrev, author.GetSigner(), sw); err != nil {
 		log.Debugf("dsfs.CreateDataset: %s", err)
 		return nil, err
 	}
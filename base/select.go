@@ -13,6 +13,9 @@ import (
 	"github.com/qri-io/qri/dsref"
 )
 
+// ErrPathNotFound indicates a selection path doesn't exist in a dataset
+var ErrPathNotFound = fmt.Errorf("path not found")
+
 // Select loads a dataset value specified by case.Sensitve.dot.separated.paths
 func Select(ctx context.Context, fs qfs.Filesystem, ref dsref.Ref, valuePath string) (interface{}, error) {
 	ds, err := dsfs.LoadDataset(ctx, fs, ref.Path)
@@ -24,7 +27,7 @@ func Select(ctx context.Context, fs qfs.Filesystem, ref dsref.Ref, valuePath str
 		return ds, nil
 	}
 
-	v, err := pathValue(ds, valuePath)
+	v, err := pathValue(ds, valuePath, false)
 	if err != nil {
 		return nil, err
 	}
@@ -39,18 +42,52 @@ func ApplyPath(ds *dataset.Dataset, path string) (interface{}, error) {
 		return ds, nil
 	}
 	var value reflect.Value
-	value, err := pathValue(ds, path)
+	value, err := pathValue(ds, path, false)
 	if err != nil {
 		return nil, err
 	}
 	return value.Interface(), nil
 }
 
-func pathValue(ds *dataset.Dataset, path string) (elem reflect.Value, err error) {
+// ApplyPathStrict gets a dataset value like ApplyPath, but distinguishes values
+// that don't exist from values that are null. Selecting an absent map key,
+// out-of-range index, or unset component or field returns an error wrapping
+// ErrPathNotFound. Map values that exist but are null return nil
+func ApplyPathStrict(ds *dataset.Dataset, path string) (interface{}, error) {
+	if path == "" {
+		return ds, nil
+	}
+	value, err := pathValue(ds, path, true)
+	if err != nil {
+		return nil, err
+	}
+	return value.Interface(), nil
+}
+
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	}
+	return false
+}
+
+// pathValue selects a value from a dataset. strict walks report absent values
+// with an error wrapping ErrPathNotFound instead of returning the first map
+// value found or panicking on out-of-range indexes
+func pathValue(ds *dataset.Dataset, path string, strict bool) (elem reflect.Value, err error) {
 	elem = reflect.ValueOf(ds)
+	notFound := fmt.Errorf("%w: %s", ErrPathNotFound, path)
 
 	for _, sel := range strings.Split(path, ".") {
-		if elem.Kind() == reflect.Ptr {
+		if strict {
+			for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Interface {
+				if elem.IsNil() {
+					return elem, notFound
+				}
+				elem = elem.Elem()
+			}
+		} else if elem.Kind() == reflect.Ptr {
 			elem = elem.Elem()
 		}
 
@@ -59,20 +96,42 @@ func pathValue(ds *dataset.Dataset, path string) (elem reflect.Value, err error)
 			elem = elem.FieldByNameFunc(func(str string) bool {
 				return strings.ToLower(str) == sel
 			})
-		case reflect.Slice:
+			// unset fields are omitted from dataset documents, treat them as absent
+			if strict && (!elem.IsValid() || !elem.CanInterface() || isNilValue(elem)) {
+				return elem, notFound
+			}
+		case reflect.Slice, reflect.Array:
 			index, err := strconv.Atoi(sel)
 			if err != nil {
 				return elem, fmt.Errorf("invalid index value: %s", sel)
 			}
+			if strict && (index < 0 || index >= elem.Len()) {
+				return elem, notFound
+			}
 			elem = elem.Index(index)
 		case reflect.Map:
+			found := false
 			for _, key := range elem.MapKeys() {
 				// we only support strings as keys
 				if strings.ToLower(key.String()) == sel {
-					return elem.MapIndex(key), nil
+					if !strict {
+						return elem.MapIndex(key), nil
+					}
+					elem = elem.MapIndex(key)
+					found = true
+					break
 				}
 			}
-			return elem, fmt.Errorf("invalid selection path: %s", path)
+			if !found {
+				if strict {
+					return elem, notFound
+				}
+				return elem, fmt.Errorf("invalid selection path: %s", path)
+			}
+		default:
+			if strict {
+				return elem, notFound
+			}
 		}
 
 		if elem.Kind() == reflect.Invalid {
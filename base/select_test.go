@@ -2,8 +2,10 @@ package base
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/qri-io/dataset"
 	"github.com/qri-io/qri/dsref"
 )
 
@@ -47,3 +49,48 @@ func TestApplyPath(t *testing.T) {
 		t.Error("expected body to not be nil")
 	}
 }
+
+func TestApplyPathStrict(t *testing.T) {
+	ds := &dataset.Dataset{
+		Meta: &dataset.Meta{Title: "cities"},
+		Structure: &dataset.Structure{
+			Schema: map[string]interface{}{
+				"type":        "array",
+				"description": nil,
+			},
+		},
+	}
+
+	good := []struct {
+		path   string
+		expect interface{}
+	}{
+		{"meta.title", "cities"},
+		{"structure.schema.type", "array"},
+		{"structure.schema.description", nil},
+	}
+	for _, c := range good {
+		got, err := ApplyPathStrict(ds, c.path)
+		if err != nil {
+			t.Errorf("path %q unexpected error: %s", c.path, err)
+			continue
+		}
+		if got != c.expect {
+			t.Errorf("path %q value mismatch. want: %v, got: %v", c.path, c.expect, got)
+		}
+	}
+
+	missing := []string{
+		"meta.nonexistent",
+		"meta.keywords",
+		"readme",
+		"readme.text",
+		"structure.schema.nonexistent",
+		"structure.schema.type.nonexistent",
+	}
+	for _, path := range missing {
+		if _, err := ApplyPathStrict(ds, path); !errors.Is(err, ErrPathNotFound) {
+			t.Errorf("path %q expected ErrPathNotFound, got: %v", path, err)
+		}
+	}
+}
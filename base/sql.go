@@ -0,0 +1,112 @@
+package base
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/sqlquery"
+)
+
+// SQLSourceTable is the table name SQL queries use to select from the body
+// of a source dataset
+const SQLSourceTable = "source"
+
+// SQLBody runs a SQL query against the body of an opened source dataset,
+// returning a JSON body file & tabular structure holding the result. The
+// source body must be an array of rows, either arrays with column titles
+// defined by the schema, or objects keyed by column name
+func SQLBody(src *dataset.Dataset, query string) (qfs.File, *dataset.Structure, error) {
+	table, err := sqlTable(src)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := sqlquery.Query(query, table)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := json.Marshal(res.Rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]interface{}, len(res.Columns))
+	for i, name := range res.Columns {
+		items[i] = map[string]interface{}{
+			"title": name,
+			"type":  sqlColumnType(res.Rows, i),
+		}
+	}
+	st := &dataset.Structure{
+		Format: dataset.JSONDataFormat.String(),
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type":  "array",
+				"items": items,
+			},
+		},
+	}
+	return qfs.NewMemfileBytes(st.BodyFilename(), data), st, nil
+}
+
+// sqlTable reads the body of a dataset into a table for querying
+func sqlTable(ds *dataset.Dataset) (sqlquery.Table, error) {
+	t := sqlquery.Table{Name: SQLSourceTable, Rows: [][]interface{}{}}
+	_, cols, rows, err := bodyColumnValues(ds)
+	if err != nil {
+		return t, fmt.Errorf("SQL queries %w", err)
+	}
+	t.Columns = cols
+	if rows != nil {
+		t.Rows = rows
+	}
+	return t, nil
+}
+
+// sqlColumnType infers the JSON schema type of a result column from its
+// values. columns with more than one type of value list all types
+func sqlColumnType(rows [][]interface{}, i int) interface{} {
+	types := map[string]bool{}
+	for _, row := range rows {
+		switch row[i].(type) {
+		case nil:
+			types["null"] = true
+		case int, int32, int64:
+			types["integer"] = true
+		case float32, float64:
+			types["number"] = true
+		case bool:
+			types["boolean"] = true
+		case string:
+			types["string"] = true
+		default:
+			types["object"] = true
+		}
+	}
+	if types["integer"] && types["number"] {
+		delete(types, "integer")
+	}
+
+	switch len(types) {
+	case 0:
+		return "string"
+	case 1:
+		for t := range types {
+			return t
+		}
+	}
+	list := make([]string, 0, len(types))
+	for t := range types {
+		list = append(list, t)
+	}
+	sort.Strings(list)
+	res := make([]interface{}, len(list))
+	for i, t := range list {
+		res[i] = t
+	}
+	return res
+}
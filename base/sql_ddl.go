@@ -0,0 +1,98 @@
+package base
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/tabular"
+)
+
+const (
+	// DDLDialectStandard writes DDL in ANSI SQL
+	DDLDialectStandard = ""
+	// DDLDialectPostgres writes DDL for PostgreSQL
+	DDLDialectPostgres = "postgres"
+	// DDLDialectMySQL writes DDL for MySQL
+	DDLDialectMySQL = "mysql"
+	// DDLDialectSQLite writes DDL for SQLite
+	DDLDialectSQLite = "sqlite"
+)
+
+// ddlTypes maps JSON schema types to column type names for each SQL dialect
+var ddlTypes = map[string]map[string]string{
+	DDLDialectStandard: {"string": "TEXT", "integer": "BIGINT", "number": "DOUBLE", "boolean": "BOOLEAN", "object": "TEXT", "array": "TEXT"},
+	DDLDialectPostgres: {"string": "TEXT", "integer": "BIGINT", "number": "DOUBLE PRECISION", "boolean": "BOOLEAN", "object": "JSONB", "array": "JSONB"},
+	DDLDialectMySQL:    {"string": "TEXT", "integer": "BIGINT", "number": "DOUBLE", "boolean": "BOOLEAN", "object": "JSON", "array": "JSON"},
+	DDLDialectSQLite:   {"string": "TEXT", "integer": "INTEGER", "number": "REAL", "boolean": "INTEGER", "object": "TEXT", "array": "TEXT"},
+}
+
+// invalidTableNameChars matches characters that are replaced when turning a
+// dataset name into a table name
+var invalidTableNameChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// StructureDDL writes a CREATE TABLE statement for the tabular schema of a
+// structure, for loading a dataset into a relational database. Column types
+// are mapped from schema types, names are quoted for the dialect. Columns
+// that allow more than one type besides null are TEXT
+func StructureDDL(st *dataset.Structure, tableName, dialect string) (string, error) {
+	types, ok := ddlTypes[dialect]
+	if !ok {
+		return "", fmt.Errorf("unknown sql dialect %q, expected one of postgres, mysql, sqlite", dialect)
+	}
+	if st == nil || st.Schema == nil {
+		return "", fmt.Errorf("dataset has no schema to describe as a table")
+	}
+	cols, _, err := tabular.ColumnsFromJSONSchema(st.Schema)
+	if err != nil {
+		return "", fmt.Errorf("sql ddl requires a tabular schema: %w", err)
+	}
+
+	tableName = invalidTableNameChars.ReplaceAllString(tableName, "_")
+	if tableName == "" {
+		tableName = "dataset"
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "CREATE TABLE %s (\n", quoteDDLIdent(tableName, dialect))
+	for i, col := range cols {
+		fmt.Fprintf(b, "  %s %s", quoteDDLIdent(col.Title, dialect), types[ddlColumnType(col)])
+		if i < len(cols)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(");\n")
+	return b.String(), nil
+}
+
+// ddlColumnType picks the schema type a column is mapped from, ignoring null
+func ddlColumnType(col tabular.Column) string {
+	if col.Type == nil {
+		return "string"
+	}
+	typ := ""
+	for _, t := range *col.Type {
+		if t == "null" {
+			continue
+		}
+		if typ != "" {
+			return "string"
+		}
+		typ = t
+	}
+	if typ == "" {
+		return "string"
+	}
+	return typ
+}
+
+// quoteDDLIdent quotes an identifier for a SQL dialect, escaping quote
+// characters within the name
+func quoteDDLIdent(name, dialect string) string {
+	if dialect == DDLDialectMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,71 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+)
+
+func TestStructureDDL(t *testing.T) {
+	st := &dataset.Structure{
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "pop", "type": []interface{}{"integer", "null"}},
+					map[string]interface{}{"title": "avg \"age\"", "type": "number"},
+					map[string]interface{}{"title": "in_usa", "type": "boolean"},
+					map[string]interface{}{"title": "mixed", "type": []interface{}{"integer", "string"}},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		dialect, expect string
+	}{
+		{"", `CREATE TABLE "b5_cities" (
+  "city" TEXT,
+  "pop" BIGINT,
+  "avg ""age""" DOUBLE,
+  "in_usa" BOOLEAN,
+  "mixed" TEXT
+);
+`},
+		{"postgres", `CREATE TABLE "b5_cities" (
+  "city" TEXT,
+  "pop" BIGINT,
+  "avg ""age""" DOUBLE PRECISION,
+  "in_usa" BOOLEAN,
+  "mixed" TEXT
+);
+`},
+		{"mysql", "CREATE TABLE `b5_cities` (\n  `city` TEXT,\n  `pop` BIGINT,\n  `avg \"age\"` DOUBLE,\n  `in_usa` BOOLEAN,\n  `mixed` TEXT\n);\n"},
+		{"sqlite", `CREATE TABLE "b5_cities" (
+  "city" TEXT,
+  "pop" INTEGER,
+  "avg ""age""" REAL,
+  "in_usa" INTEGER,
+  "mixed" TEXT
+);
+`},
+	}
+	for _, c := range cases {
+		got, err := StructureDDL(st, "b5-cities", c.dialect)
+		if err != nil {
+			t.Fatalf("dialect %q: %s", c.dialect, err)
+		}
+		if diff := cmp.Diff(c.expect, got); diff != "" {
+			t.Errorf("dialect %q result mismatch (-want +got):\n%s", c.dialect, diff)
+		}
+	}
+
+	_, err := StructureDDL(st, "cities", "oracle")
+	expectErr := `unknown sql dialect "oracle", expected one of postgres, mysql, sqlite`
+	if err == nil || err.Error() != expectErr {
+		t.Errorf("error mismatch. expected: %q, got: %v", expectErr, err)
+	}
+}
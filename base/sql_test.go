@@ -0,0 +1,77 @@
+package base
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/sqlquery"
+)
+
+func TestSQLBody(t *testing.T) {
+	src := &dataset.Dataset{
+		Structure: &dataset.Structure{
+			Format: "json",
+			Schema: map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "array",
+					"items": []interface{}{
+						map[string]interface{}{"title": "region", "type": "string"},
+						map[string]interface{}{"title": "amount", "type": "integer"},
+					},
+				},
+			},
+		},
+	}
+	src.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(`[["east",10],["west",5],["east",2]]`)))
+
+	file, st, err := SQLBody(src, "SELECT region, SUM(amount) AS total FROM source GROUP BY region ORDER BY total")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := `[["west",5],["east",12]]`; string(data) != expect {
+		t.Errorf("body mismatch. want: %s, got: %s", expect, data)
+	}
+
+	expectSt := &dataset.Structure{
+		Format: "json",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "region", "type": "string"},
+					map[string]interface{}{"title": "total", "type": "integer"},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectSt, st); diff != "" {
+		t.Errorf("structure mismatch (-want +got):\n%s", diff)
+	}
+
+	src.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(`[["east",10]]`)))
+	if _, _, err := SQLBody(src, "SELECT city FROM source"); !errors.Is(err, sqlquery.ErrUnknownColumn) {
+		t.Errorf("expected unknown column error, got: %v", err)
+	}
+
+	objects := &dataset.Dataset{Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}}
+	objects.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(`[{"a":1,"b":true},{"a":2,"c":"x"}]`)))
+	if file, _, err = SQLBody(objects, "SELECT a, c FROM source WHERE c IS NULL"); err != nil {
+		t.Fatal(err)
+	}
+	if data, err = ioutil.ReadAll(file); err != nil {
+		t.Fatal(err)
+	}
+	if expect := `[[1,null]]`; string(data) != expect {
+		t.Errorf("object rows body mismatch. want: %s, got: %s", expect, data)
+	}
+}
@@ -0,0 +1,142 @@
+package base
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/tabular"
+)
+
+// statsCSVHeader lists the columns of a stats csv. each name is paired with
+// the stat key it's read from. stats with a type are only read from columns
+// of that type. stats don't count nulls in typed columns, so null counts only
+// come from null columns, where "count" is the number of nulls
+var statsCSVHeader = []struct {
+	name, key, typ string
+}{
+	{"column", "", ""},
+	{"type", "type", ""},
+	{"count", "count", ""},
+	{"null_count", "count", "null"},
+	{"min", "min", ""},
+	{"max", "max", ""},
+	{"mean", "mean", ""},
+	{"unique", "unique", ""},
+}
+
+// StatsCSV reshapes per-column dataset statistics into a csv table with one
+// row per column and one column per statistic. Column names are taken from
+// the structure schema when it's tabular. Stats that don't apply to a column,
+// like the mean of a string column, are left blank
+func StatsCSV(st *dataset.Structure, stats interface{}) ([]byte, error) {
+	cols, err := statsColumns(st, stats)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	header := make([]string, len(statsCSVHeader))
+	for i, h := range statsCSVHeader {
+		header[i] = h.name
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, col := range cols {
+		row := make([]string, len(statsCSVHeader))
+		row[0] = col.name
+		for i, h := range statsCSVHeader[1:] {
+			if h.typ != "" && col.stats["type"] != h.typ {
+				continue
+			}
+			row[i+1] = statCSVValue(col.stats[h.key])
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// columnStats pairs the statistics for a single column with its name
+type columnStats struct {
+	name  string
+	stats map[string]interface{}
+}
+
+// statsColumns splits the stats component of a dataset into per-column
+// statistics. Column names are taken from the structure schema when it's
+// tabular, falling back to positional names
+func statsColumns(st *dataset.Structure, stats interface{}) ([]columnStats, error) {
+	var cols []columnStats
+	switch sts := stats.(type) {
+	case []interface{}:
+		for _, s := range sts {
+			m, ok := s.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("unexpected column stats type %T", s)
+			}
+			cols = append(cols, columnStats{stats: m})
+		}
+	case []map[string]interface{}:
+		for _, m := range sts {
+			cols = append(cols, columnStats{stats: m})
+		}
+	case map[string]interface{}:
+		for key, s := range sts {
+			m, ok := s.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("unexpected column stats type %T", s)
+			}
+			cols = append(cols, columnStats{name: key, stats: m})
+		}
+		sort.Slice(cols, func(i, j int) bool { return cols[i].name < cols[j].name })
+	default:
+		return nil, fmt.Errorf("unexpected stats type %T", stats)
+	}
+
+	var titles []string
+	if st != nil && st.Schema != nil {
+		if tcols, _, err := tabular.ColumnsFromJSONSchema(st.Schema); err == nil {
+			titles = tcols.Titles()
+		}
+	}
+	for i := range cols {
+		if cols[i].name != "" {
+			continue
+		}
+		if i < len(titles) {
+			cols[i].name = titles[i]
+		} else {
+			cols[i].name = fmt.Sprintf("col_%d", i)
+		}
+	}
+	return cols, nil
+}
+
+// statCSVValue formats a single statistic as a csv cell
+func statCSVValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(x), 'f', -1, 32)
+	case int:
+		return strconv.Itoa(x)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	default:
+		return fmt.Sprintf("%v", x)
+	}
+}
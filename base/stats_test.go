@@ -0,0 +1,69 @@
+package base
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+)
+
+func TestStatsCSV(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "csv",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "pop", "type": "integer"},
+					map[string]interface{}{"title": "in_usa", "type": "boolean"},
+				},
+			},
+		},
+	}
+
+	var stats interface{}
+	data := `[
+		{"type":"string","count":5,"minLength":7,"maxLength":8,"unique":5,"frequencies":{"toronto":1}},
+		{"type":"numeric","count":5,"min":35000,"max":40000000,"mean":9817000,"median":300000},
+		{"type":"boolean","count":5,"trueCount":4,"falseCount":1}
+	]`
+	if err := json.Unmarshal([]byte(data), &stats); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := StatsCSV(st, stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `column,type,count,null_count,min,max,mean,unique
+city,string,5,,,,,5
+pop,numeric,5,,35000,40000000,9817000,
+in_usa,boolean,5,,,,,
+`
+	if diff := cmp.Diff(expect, string(got)); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+
+	// object-shaped stats use keys as column names
+	objStats := map[string]interface{}{
+		"b": map[string]interface{}{"type": "null", "count": 2},
+		"a": map[string]interface{}{"type": "numeric", "count": 2, "min": 1.5, "max": 2, "mean": 1.75},
+	}
+	if got, err = StatsCSV(nil, objStats); err != nil {
+		t.Fatal(err)
+	}
+	expect = `column,type,count,null_count,min,max,mean,unique
+a,numeric,2,,1.5,2,1.75,
+b,null,2,2,,,,
+`
+	if diff := cmp.Diff(expect, string(got)); diff != "" {
+		t.Errorf("object stats mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := StatsCSV(st, "not stats"); err == nil {
+		t.Error("expected invalid stats to error")
+	}
+}
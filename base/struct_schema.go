@@ -0,0 +1,203 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrNotStruct indicates a value given to StructSchema or StructRows isn't a
+// struct or a slice of structs
+var ErrNotStruct = errors.New("value is not a struct or slice of structs")
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// structColumn maps a struct field to a column
+type structColumn struct {
+	index []int
+	title string
+	typ   interface{}
+}
+
+// StructSchema derives a tabular schema from the exported fields of a struct
+// type. v may be a struct, a pointer to a struct, or a slice of either.
+// Columns are ordered by field, and configured with a qri tag of the form
+// `qri:"column_name,type"`. Either part can be left empty: the title defaults
+// to the field name, and the type is inferred from the field's go type.
+// Fields tagged `qri:"-"` are skipped. Pointer fields accept null values
+func StructSchema(v interface{}) (map[string]interface{}, error) {
+	t, err := structElemType(v)
+	if err != nil {
+		return nil, err
+	}
+	cols, err := structColumns(t)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]interface{}, len(cols))
+	for i, col := range cols {
+		items[i] = map[string]interface{}{
+			"title": col.title,
+			"type":  col.typ,
+		}
+	}
+	return map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type":  "array",
+			"items": items,
+		},
+	}, nil
+}
+
+// StructRows converts a slice of structs into body rows, with row values in
+// the column order of StructSchema. Nil pointers become null values
+func StructRows(v interface{}) ([]interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, ErrNotStruct
+	}
+	t, err := structElemType(v)
+	if err != nil {
+		return nil, err
+	}
+	cols, err := structColumns(t)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]interface{}, rv.Len())
+	for i := range rows {
+		el := rv.Index(i)
+		for el.Kind() == reflect.Ptr {
+			if el.IsNil() {
+				return nil, fmt.Errorf("row %d is nil", i)
+			}
+			el = el.Elem()
+		}
+		row := make([]interface{}, len(cols))
+		for j, col := range cols {
+			row[j] = fieldValue(el.FieldByIndex(col.index))
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// IsStructSlice returns true if v is a slice of structs or struct pointers
+func IsStructSlice(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return false
+	}
+	_, err := structElemType(v)
+	return err == nil
+}
+
+// structElemType returns the struct type v describes, dereferencing pointers
+// and slice elements
+func structElemType(v interface{}) (reflect.Type, error) {
+	if v == nil {
+		return nil, ErrNotStruct
+	}
+	t := reflect.TypeOf(v)
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			t = t.Elem()
+			continue
+		case reflect.Struct:
+			if t == timeType {
+				return nil, ErrNotStruct
+			}
+			return t, nil
+		}
+		return nil, ErrNotStruct
+	}
+}
+
+// structColumns lists the columns of a struct type
+func structColumns(t reflect.Type) ([]structColumn, error) {
+	var cols []structColumn
+	titles := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+		tag := f.Tag.Get("qri")
+		if tag == "-" {
+			continue
+		}
+		col := structColumn{index: f.Index, title: f.Name}
+		name, typ := tag, ""
+		if j := strings.Index(tag, ","); j >= 0 {
+			name, typ = tag[:j], tag[j+1:]
+		}
+		if name != "" {
+			col.title = name
+		}
+		if typ != "" {
+			col.typ = typ
+		} else {
+			col.typ = goSchemaType(f.Type)
+		}
+		if f.Type.Kind() == reflect.Ptr {
+			col.typ = nullableType(col.typ)
+		}
+
+		if titles[col.title] {
+			return nil, fmt.Errorf("field %s: duplicate column %q", f.Name, col.title)
+		}
+		titles[col.title] = true
+		cols = append(cols, col)
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("struct %s has no exported fields", t)
+	}
+	return cols, nil
+}
+
+// goSchemaType maps a go type to a json schema type
+func goSchemaType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return "string"
+}
+
+// fieldValue returns the value of a struct field as a body value
+func fieldValue(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return v.Interface()
+}
@@ -0,0 +1,75 @@
+package base
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type cityRow struct {
+	Name      string    `qri:"city"`
+	Pop       int64     `qri:"pop,integer"`
+	Area      *float64  `qri:"area_km2"`
+	InUSA     bool      `qri:"in_usa"`
+	Founded   time.Time `qri:",string"`
+	Notes     string    `qri:"-"`
+	unexposed string
+}
+
+func TestStructSchema(t *testing.T) {
+	got, err := StructSchema([]cityRow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "array",
+			"items": []interface{}{
+				map[string]interface{}{"title": "city", "type": "string"},
+				map[string]interface{}{"title": "pop", "type": "integer"},
+				map[string]interface{}{"title": "area_km2", "type": []interface{}{"number", "null"}},
+				map[string]interface{}{"title": "in_usa", "type": "boolean"},
+				map[string]interface{}{"title": "Founded", "type": "string"},
+			},
+		},
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("schema mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := StructSchema("not a struct"); err != ErrNotStruct {
+		t.Errorf("expected error %q, got %v", ErrNotStruct, err)
+	}
+	type dup struct {
+		A string `qri:"a"`
+		B string `qri:"a"`
+	}
+	if _, err := StructSchema(dup{}); err == nil {
+		t.Error("expected duplicate column titles to error")
+	}
+}
+
+func TestStructRows(t *testing.T) {
+	area := 630.2
+	founded := time.Date(1834, 3, 6, 0, 0, 0, 0, time.UTC)
+	rows, err := StructRows([]*cityRow{
+		{Name: "toronto", Pop: 40000000, Area: &area, Founded: founded, Notes: "skipped"},
+		{Name: "chatham", Pop: 35000, InUSA: false, Founded: founded},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []interface{}{
+		[]interface{}{"toronto", int64(40000000), 630.2, false, "1834-03-06T00:00:00Z"},
+		[]interface{}{"chatham", int64(35000), nil, false, "1834-03-06T00:00:00Z"},
+	}
+	if diff := cmp.Diff(expect, rows); diff != "" {
+		t.Errorf("rows mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := StructRows(cityRow{}); err != ErrNotStruct {
+		t.Errorf("expected error %q, got %v", ErrNotStruct, err)
+	}
+}
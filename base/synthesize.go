@@ -0,0 +1,194 @@
+package base
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/tabular"
+	"github.com/qri-io/qfs"
+)
+
+// synthesizeEpoch is the earliest date synthesized date & date-time values
+// fall on
+var synthesizeEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// SynthesizeBody generates a body of rows filled with random values that
+// conform to the tabular schema of a structure: values are drawn from enums,
+// numbers stay within minimum & maximum, and strings respect length limits &
+// date formats. The same seed always generates the same body. The body is
+// written in the structure format, defaulting to JSON
+func SynthesizeBody(st *dataset.Structure, rows int, seed int64) (qfs.File, error) {
+	if st == nil || st.Schema == nil {
+		return nil, fmt.Errorf("synthesizing a body requires a structure with a schema")
+	}
+	if rows < 0 {
+		return nil, fmt.Errorf("can't synthesize a negative number of rows")
+	}
+	cols, _, err := tabular.ColumnsFromJSONSchema(st.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("synthesizing a body requires a tabular schema: %w", err)
+	}
+
+	wst := &dataset.Structure{}
+	wst.Assign(st)
+	if wst.Format == "" {
+		wst.Format = dataset.JSONDataFormat.String()
+	}
+	buf := &bytes.Buffer{}
+	w, err := dsio.NewEntryWriter(wst, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	for i := 0; i < rows; i++ {
+		row := make([]interface{}, len(cols))
+		for j, col := range cols {
+			row[j] = synthesizeValue(r, col)
+		}
+		if err := w.WriteEntry(dsio.Entry{Index: i, Value: row}); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return qfs.NewMemfileBytes(wst.BodyFilename(), buf.Bytes()), nil
+}
+
+// synthesizeValue generates a random value for a column
+func synthesizeValue(r *rand.Rand, col tabular.Column) interface{} {
+	if enum, ok := col.Validation["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[r.Intn(len(enum))]
+	}
+
+	typ := "string"
+	if col.Type != nil {
+		for _, t := range *col.Type {
+			if t != "null" {
+				typ = t
+				break
+			}
+		}
+	}
+
+	switch typ {
+	case "integer":
+		min, max := synthesizeRange(col.Validation, 0, 1000)
+		lo, hi := clampInt64(math.Ceil(min)), clampInt64(math.Floor(max))
+		if hi < lo {
+			return lo
+		}
+		return synthesizeInt(r, lo, hi)
+	case "number":
+		min, max := synthesizeRange(col.Validation, 0, 1000)
+		return math.Round((min+r.Float64()*(max-min))*100) / 100
+	case "boolean":
+		return r.Intn(2) == 1
+	case "object":
+		return map[string]interface{}{}
+	case "array":
+		return []interface{}{}
+	default:
+		return synthesizeString(r, col.Validation)
+	}
+}
+
+// synthesizeInt picks a random integer in [lo, hi]. the span is computed
+// unsigned, wide ranges overflow int64
+func synthesizeInt(r *rand.Rand, lo, hi int64) int64 {
+	span := uint64(hi) - uint64(lo)
+	if span < math.MaxInt64 {
+		return lo + r.Int63n(int64(span)+1)
+	}
+	for {
+		if v := r.Uint64(); v <= span {
+			return int64(uint64(lo) + v)
+		}
+	}
+}
+
+// clampInt64 converts a float to the nearest int64
+func clampInt64(f float64) int64 {
+	if f >= math.MaxInt64 {
+		return math.MaxInt64
+	} else if f <= math.MinInt64 {
+		return math.MinInt64
+	}
+	return int64(f)
+}
+
+// synthesizeRange reads the minimum & maximum of a numeric column, falling
+// back to defaults
+func synthesizeRange(validation map[string]interface{}, min, max float64) (float64, float64) {
+	if v, ok := synthesizeNumber(validation["minimum"]); ok {
+		min = v
+		if max < min {
+			max = min + 1000
+		}
+	}
+	if v, ok := synthesizeNumber(validation["maximum"]); ok {
+		max = v
+		if min > max {
+			min = max - 1000
+		}
+	}
+	return min, max
+}
+
+func synthesizeNumber(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case int:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case json.Number:
+		f, err := x.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+const synthesizeLetters = "abcdefghijklmnopqrstuvwxyz"
+
+// synthesizeString generates a random string, respecting date formats &
+// length limits
+func synthesizeString(r *rand.Rand, validation map[string]interface{}) string {
+	switch validation["format"] {
+	case "date":
+		return synthesizeEpoch.AddDate(0, 0, r.Intn(365*20)).Format("2006-01-02")
+	case "date-time":
+		return synthesizeEpoch.Add(time.Duration(r.Int63n(int64(20 * 365 * 24 * time.Hour)))).Format(time.RFC3339)
+	}
+
+	minLen, maxLen := 4, 10
+	if v, ok := synthesizeNumber(validation["minLength"]); ok {
+		minLen = int(v)
+		if maxLen < minLen {
+			maxLen = minLen
+		}
+	}
+	if v, ok := synthesizeNumber(validation["maxLength"]); ok {
+		maxLen = int(v)
+		if minLen > maxLen {
+			minLen = maxLen
+		}
+	}
+	n := minLen
+	if maxLen > minLen {
+		n += r.Intn(maxLen - minLen + 1)
+	}
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = synthesizeLetters[r.Intn(len(synthesizeLetters))]
+	}
+	return string(b)
+}
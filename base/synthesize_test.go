@@ -0,0 +1,107 @@
+package base
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/dataset/tabular"
+)
+
+func TestSynthesizeBody(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "json",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "name", "type": "string", "maxLength": 6},
+					map[string]interface{}{"title": "age", "type": "integer", "minimum": 18, "maximum": 65},
+					map[string]interface{}{"title": "score", "type": []interface{}{"number", "null"}},
+					map[string]interface{}{"title": "color", "type": "string", "enum": []interface{}{"red", "blue"}},
+					map[string]interface{}{"title": "member", "type": "boolean"},
+					map[string]interface{}{"title": "joined", "type": "string", "format": "date"},
+				},
+			},
+		},
+	}
+
+	synthesize := func(seed int64) string {
+		t.Helper()
+		f, err := SynthesizeBody(st, 20, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	body := synthesize(7)
+	if again := synthesize(7); again != body {
+		t.Errorf("expected the same seed to generate the same body")
+	}
+	if other := synthesize(8); other == body {
+		t.Errorf("expected different seeds to generate different bodies")
+	}
+
+	f, err := SynthesizeBody(st, 20, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs, err := Validate(context.Background(), nil, f, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) > 0 {
+		t.Errorf("expected synthesized body to conform to the schema, got errors: %v", errs)
+	}
+
+	csvSt := &dataset.Structure{Format: "csv", Schema: st.Schema}
+	if f, err = SynthesizeBody(csvSt, 3, 1); err != nil {
+		t.Fatal(err)
+	}
+	rr, err := dsio.NewEntryReader(csvSt, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := ReadEntries(rr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows.([]interface{})) != 3 {
+		t.Errorf("expected 3 csv rows, got %d", len(rows.([]interface{})))
+	}
+
+	if _, err := SynthesizeBody(&dataset.Structure{}, 1, 0); err == nil {
+		t.Error("expected synthesizing without a schema to error")
+	}
+}
+
+func TestSynthesizeIntWideRange(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	cases := []struct {
+		min, max float64
+	}{
+		{-9e18, 9e18},
+		{math.MinInt64, math.MaxInt64},
+		{-1e300, 1e300},
+		{0, 0},
+	}
+	for _, c := range cases {
+		col := tabular.Column{Type: &tabular.ColType{"integer"}, Validation: map[string]interface{}{"minimum": c.min, "maximum": c.max}}
+		for i := 0; i < 100; i++ {
+			v := synthesizeValue(r, col).(int64)
+			if float64(v) < math.Max(c.min, math.MinInt64) || float64(v) > math.Min(c.max, math.MaxInt64) {
+				t.Fatalf("value %d out of range [%g, %g]", v, c.min, c.max)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package base
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
+)
+
+// dateLayouts are the layouts timestamp values are parsed with, in order
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"2006/01/02",
+}
+
+// schemaDateLayouts are the layouts values in a column are parsed with when
+// the column's schema sets a date "format"
+var schemaDateLayouts = map[string][]string{
+	"date":      {"2006-01-02"},
+	"date-time": {time.RFC3339Nano},
+}
+
+// ParseTimeBound parses one end of a time range, like "2024-01-01" or
+// "2024-01-01T12:00:00Z". Ranges include their end, so when end is true a
+// bound given as a date covers that whole day, returning the start of the
+// following day as an exclusive bound
+func ParseTimeBound(s string, end bool) (time.Time, error) {
+	t, layout, err := parseTimeValue(s, dateLayouts)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		if layout == "2006-01-02" || layout == "2006/01/02" {
+			return t.AddDate(0, 0, 1), nil
+		}
+		return t.Add(time.Nanosecond), nil
+	}
+	return t, nil
+}
+
+// SelectRowsInTimeRange streams the body of a dataset, returning the rows
+// whose value in timeColumn is at or after from & before to, in body order.
+// A zero from or to leaves that end of the range open. String values are
+// parsed with the column's schema format when it's "date" or "date-time", and
+// as RFC 3339 timestamps or dates otherwise. Numbers are read as unix seconds.
+// Rows with a missing or unparseable value are skipped & counted, or are an
+// error when strict is true. Unless all is true, limit & offset page through
+// the matching rows, and reading stops once the page is full, so skipped only
+// counts rows read up to that point
+func SelectRowsInTimeRange(ds *dataset.Dataset, timeColumn string, from, to time.Time, limit, offset int, all, strict bool) (rows []interface{}, skipped int, err error) {
+	if ds == nil {
+		return nil, 0, fmt.Errorf("can't load body from a nil dataset")
+	}
+	if timeColumn == "" {
+		return nil, 0, fmt.Errorf("a time column is required")
+	}
+	file := ds.BodyFile()
+	if file == nil {
+		return nil, 0, fmt.Errorf("no body file to read")
+	}
+
+	col := newRowColumn(ds.Structure, timeColumn)
+	rows = []interface{}{}
+	matched := 0
+	err = eachRow(ds.Structure, file, func(i int, ent dsio.Entry) error {
+		if !all && len(rows) >= limit {
+			return errStopRows
+		}
+		val, _, err := col.value(i, ent.Value)
+		if err != nil {
+			return err
+		}
+		t, err := rowTime(val, col.format)
+		if err != nil {
+			if strict {
+				return fmt.Errorf("row %d: %w", i, err)
+			}
+			skipped++
+			return nil
+		}
+		if !from.IsZero() && t.Before(from) {
+			return nil
+		}
+		if !to.IsZero() && !t.Before(to) {
+			return nil
+		}
+		if matched++; !all && matched <= offset {
+			return nil
+		}
+		rows = append(rows, ent.Value)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return rows, skipped, nil
+}
+
+// rowTime reads a time from a body value, parsing strings with the layouts
+// for a schema format
+func rowTime(val interface{}, format string) (time.Time, error) {
+	switch x := val.(type) {
+	case string:
+		layouts, ok := schemaDateLayouts[format]
+		if !ok {
+			layouts = dateLayouts
+		}
+		t, _, err := parseTimeValue(x, layouts)
+		return t, err
+	case int:
+		return time.Unix(int64(x), 0).UTC(), nil
+	case int64:
+		return time.Unix(x, 0).UTC(), nil
+	case float64:
+		sec := int64(x)
+		return time.Unix(sec, int64((x-float64(sec))*1e9)).UTC(), nil
+	case nil:
+		return time.Time{}, fmt.Errorf("time value is missing")
+	}
+	return time.Time{}, fmt.Errorf("can't read %v as a time", val)
+}
+
+// parseTimeValue parses a timestamp string with the first of layouts that
+// fits, returning the layout used. values without a zone are UTC
+func parseTimeValue(s string, layouts []string) (time.Time, string, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, layout, nil
+		}
+	}
+	return time.Time{}, "", fmt.Errorf("can't parse %q as a date or time", s)
+}
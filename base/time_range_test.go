@@ -0,0 +1,109 @@
+package base
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestSelectRowsInTimeRange(t *testing.T) {
+	newSchema := func(dateCol map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					dateCol,
+					map[string]interface{}{"title": "reading", "type": "integer"},
+				},
+			},
+		}
+	}
+	schema := newSchema(map[string]interface{}{"title": "date", "type": "string"})
+	newDs := func(body string) *dataset.Dataset {
+		ds := &dataset.Dataset{Structure: &dataset.Structure{Format: "csv", Schema: schema}}
+		ds.SetBodyFile(qfs.NewMemfileBytes("body.csv", []byte(body)))
+		return ds
+	}
+	body := "2023-12-31,1\n2024-01-01,2\n2024-02-15T08:30:00Z,3\nsoon,4\n2024-03-31 23:59:59,5\n2024-04-01,6\n"
+
+	from, err := ParseTimeBound("2024-01-01", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := ParseTimeBound("2024-03-31", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, skipped, err := SelectRowsInTimeRange(newDs(body), "date", from, to, 0, 0, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []interface{}{
+		[]interface{}{"2024-01-01", int64(2)},
+		[]interface{}{"2024-02-15T08:30:00Z", int64(3)},
+		[]interface{}{"2024-03-31 23:59:59", int64(5)},
+	}
+	if diff := cmp.Diff(expect, rows); diff != "" {
+		t.Errorf("rows mismatch (-want +got):\n%s", diff)
+	}
+	if skipped != 1 {
+		t.Errorf("expected 1 skipped row, got %d", skipped)
+	}
+
+	rows, _, err = SelectRowsInTimeRange(newDs(body), "date", time.Time{}, from, 0, 0, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Errorf("expected an open start to select 1 row, got %d", len(rows))
+	}
+
+	// limit & offset page through matching rows
+	rows, _, err = SelectRowsInTimeRange(newDs(body), "date", from, to, 1, 1, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expect[1:2], rows); diff != "" {
+		t.Errorf("paged rows mismatch (-want +got):\n%s", diff)
+	}
+
+	// a "date" schema format only reads dates
+	dateDs := newDs(body)
+	dateDs.Structure.Schema = newSchema(map[string]interface{}{"title": "date", "type": "string", "format": "date"})
+	rows, skipped, err = SelectRowsInTimeRange(dateDs, "date", from, to, 0, 0, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expect[:1], rows); diff != "" {
+		t.Errorf("date format rows mismatch (-want +got):\n%s", diff)
+	}
+	if skipped != 3 {
+		t.Errorf("expected 3 rows that aren't dates to be skipped, got %d", skipped)
+	}
+
+	_, _, err = SelectRowsInTimeRange(newDs(body), "date", from, to, 0, 0, true, true)
+	expectErr := `row 3: can't parse "soon" as a date or time`
+	if err == nil || err.Error() != expectErr {
+		t.Errorf("error mismatch. expected: %q, got: %v", expectErr, err)
+	}
+
+	_, _, err = SelectRowsInTimeRange(newDs(body), "when", from, to, 0, 0, true, false)
+	expectErr = `unknown column "when", available columns: date, reading`
+	if err == nil || err.Error() != expectErr {
+		t.Errorf("error mismatch. expected: %q, got: %v", expectErr, err)
+	}
+
+	// numbers are unix seconds
+	ds := &dataset.Dataset{Structure: &dataset.Structure{Format: "json", Schema: dataset.BaseSchemaArray}}
+	ds.SetBodyFile(qfs.NewMemfileBytes("body.json", []byte(`[{"t":1704067200},{"t":1703980800}]`)))
+	if rows, _, err = SelectRowsInTimeRange(ds, "t", from, to, 0, 0, true, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Errorf("expected 1 row from unix timestamps, got %d", len(rows))
+	}
+}
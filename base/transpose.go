@@ -0,0 +1,52 @@
+package base
+
+import (
+	"fmt"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/tabular"
+)
+
+// MaxTransposeRows is the largest number of body rows TransposeBody accepts.
+// transposing reads the whole body into memory, and is meant for narrow,
+// metadata-style bodies displayed vertically
+const MaxTransposeRows = 1000
+
+// TransposeBody swaps the rows & columns of a tabular body. Each column
+// becomes a row, starting with the column title followed by the value of
+// that column in each body row. Rows may be arrays or objects. Bodies longer
+// than MaxTransposeRows are an error
+func TransposeBody(st *dataset.Structure, rows []interface{}) ([]interface{}, error) {
+	if len(rows) > MaxTransposeRows {
+		return nil, fmt.Errorf("body has %d rows, only bodies with %d rows or fewer can be transposed", len(rows), MaxTransposeRows)
+	}
+	if st == nil || st.Schema == nil {
+		return nil, fmt.Errorf("transposing requires a schema")
+	}
+	cols, _, err := tabular.ColumnsFromJSONSchema(st.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("transposing requires a tabular schema: %w", err)
+	}
+
+	out := make([]interface{}, len(cols))
+	for c, col := range cols {
+		row := make([]interface{}, 0, len(rows)+1)
+		row = append(row, col.Title)
+		for i, r := range rows {
+			var val interface{}
+			switch x := r.(type) {
+			case []interface{}:
+				if c < len(x) {
+					val = x[c]
+				}
+			case map[string]interface{}:
+				val = x[col.Title]
+			default:
+				return nil, fmt.Errorf("row %d isn't an array or object, can't transpose", i)
+			}
+			row = append(row, val)
+		}
+		out[c] = row
+	}
+	return out, nil
+}
@@ -0,0 +1,46 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+)
+
+func TestTransposeBody(t *testing.T) {
+	st := &dataset.Structure{
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "pop", "type": "integer"},
+				},
+			},
+		},
+	}
+	rows := []interface{}{
+		[]interface{}{"toronto", 40000000},
+		[]interface{}{"chatham"},
+		map[string]interface{}{"city": "raleigh", "pop": 250000},
+	}
+	got, err := TransposeBody(st, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []interface{}{
+		[]interface{}{"city", "toronto", "chatham", "raleigh"},
+		[]interface{}{"pop", 40000000, nil, 250000},
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := TransposeBody(st, make([]interface{}, MaxTransposeRows+1)); err == nil {
+		t.Error("expected a long body to error")
+	}
+	if _, err := TransposeBody(&dataset.Structure{Schema: dataset.BaseSchemaObject}, rows); err == nil {
+		t.Error("expected a non-tabular schema to error")
+	}
+}
@@ -0,0 +1,106 @@
+package base
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/detect"
+	"github.com/qri-io/qfs"
+)
+
+// tsvDetectRows is the number of rows read to detect a TSV schema, enough
+// for the CSV detector to read all the rows it samples
+const tsvDetectRows = 2002
+
+// IsTSVFilename returns true if filename has a tab-separated values
+// extension
+func IsTSVFilename(filename string) bool {
+	return strings.ToLower(filepath.Ext(filename)) == ".tsv"
+}
+
+// InferTSVStructure sets the structure of a dataset with a tab-separated body
+// file. TSV bodies are stored as CSV with a tab separator in the structure's
+// format config. detect.Structure doesn't recognize the .tsv extension, so a
+// missing schema is detected here by reading the first rows as CSV. Datasets
+// without a .tsv body file are left unchanged
+func InferTSVStructure(ds *dataset.Dataset) error {
+	body := ds.BodyFile()
+	if body == nil || !IsTSVFilename(body.FileName()) {
+		return nil
+	}
+
+	if ds.Structure == nil {
+		ds.Structure = &dataset.Structure{}
+	}
+	st := ds.Structure
+	if st.Format != "" && st.Format != dataset.CSVDataFormat.String() {
+		return fmt.Errorf("body file %q is tab-separated, but the structure format is %q. tab-separated bodies use the csv format", body.FileName(), st.Format)
+	}
+	st.Format = dataset.CSVDataFormat.String()
+	if st.FormatConfig == nil {
+		st.FormatConfig = map[string]interface{}{}
+	}
+	if _, ok := st.FormatConfig["separator"]; !ok {
+		st.FormatConfig["separator"] = "\t"
+	}
+	if st.Schema != nil {
+		return nil
+	}
+
+	// keep what's read for detection to glue back onto the body
+	buf := &bytes.Buffer{}
+	sample, err := tsvSampleAsCSV(io.TeeReader(body, buf))
+	if err != nil {
+		return fmt.Errorf("determining dataset structure: %w", err)
+	}
+	detected := &dataset.Structure{Format: st.Format}
+	if st.Schema, _, err = detect.CSVSchema(detected, sample); err != nil {
+		return fmt.Errorf("determining dataset structure: %w", err)
+	}
+	for key, val := range detected.FormatConfig {
+		if _, ok := st.FormatConfig[key]; !ok {
+			st.FormatConfig[key] = val
+		}
+	}
+
+	size := int64(-1)
+	if sizef, ok := body.(qfs.SizeFile); ok {
+		size = sizef.Size()
+	}
+	r := struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(buf, body), body}
+	ds.SetBodyFile(qfs.NewMemfileReaderSize(body.FileName(), r, size))
+	return nil
+}
+
+// tsvSampleAsCSV re-encodes the first rows of tab-separated data as comma
+// separated CSV
+func tsvSampleAsCSV(r io.Reader) (io.Reader, error) {
+	rr := csv.NewReader(r)
+	rr.Comma = '\t'
+	rr.FieldsPerRecord = -1
+	rr.LazyQuotes = true
+
+	out := &bytes.Buffer{}
+	w := csv.NewWriter(out)
+	for i := 0; i < tsvDetectRows; i++ {
+		rec, err := rr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if err := w.Write(rec); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return out, w.Error()
+}
@@ -0,0 +1,64 @@
+package base
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+)
+
+func TestInferTSVStructure(t *testing.T) {
+	text := "city\tpop\n\"toronto, on\"\t40000000\nchatham\t35000\n"
+	ds := &dataset.Dataset{}
+	ds.SetBodyFile(qfs.NewMemfileBytes("cities.tsv", []byte(text)))
+	if err := InferTSVStructure(ds); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := &dataset.Structure{
+		Format: "csv",
+		FormatConfig: map[string]interface{}{
+			"headerRow":  true,
+			"lazyQuotes": true,
+			"separator":  "\t",
+		},
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "pop", "type": "integer"},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expect, ds.Structure); diff != "" {
+		t.Errorf("structure mismatch (-want +got):\n%s", diff)
+	}
+
+	data, err := ioutil.ReadAll(ds.BodyFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != text {
+		t.Errorf("expected body to be readable after detection. want: %q, got: %q", text, string(data))
+	}
+
+	ds = &dataset.Dataset{Structure: &dataset.Structure{Format: "json"}}
+	ds.SetBodyFile(qfs.NewMemfileBytes("cities.tsv", []byte(text)))
+	if err := InferTSVStructure(ds); err == nil {
+		t.Error("expected a non-csv format to error")
+	}
+
+	ds = &dataset.Dataset{}
+	ds.SetBodyFile(qfs.NewMemfileBytes("cities.csv", []byte(text)))
+	if err := InferTSVStructure(ds); err != nil {
+		t.Fatal(err)
+	}
+	if ds.Structure != nil {
+		t.Error("expected a csv body file to be left unchanged")
+	}
+}
@@ -0,0 +1,183 @@
+package base
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/tabular"
+)
+
+// CoerceBodyTypes converts the values of body rows to the types their schema
+// columns declare, eg. the string "12" in an integer column becomes 12.
+// Empty strings in columns that aren't string typed become null. Values that
+// can't be converted become null & are counted, or are an error when strict
+// is true. Rows must be arrays or objects read from a tabular body
+func CoerceBodyTypes(st *dataset.Structure, rows interface{}, strict bool) (interface{}, int, error) {
+	if st == nil || st.Schema == nil {
+		return nil, 0, fmt.Errorf("converting body types requires a schema")
+	}
+	cols, _, err := tabular.ColumnsFromJSONSchema(st.Schema)
+	if err != nil {
+		return nil, 0, fmt.Errorf("converting body types requires a tabular schema: %w", err)
+	}
+	list, ok := rows.([]interface{})
+	if !ok {
+		return nil, 0, fmt.Errorf("expected body to be a list of rows, got %T", rows)
+	}
+
+	failed := 0
+	coerce := func(i int, col tabular.Column, v interface{}) (interface{}, error) {
+		got, ok := coerceValue(v, col.Type)
+		if ok {
+			return got, nil
+		}
+		if strict {
+			return nil, fmt.Errorf("row %d column %q: can't convert %v to %s", i, col.Title, v, colTypeString(col.Type))
+		}
+		failed++
+		return nil, nil
+	}
+
+	for i, row := range list {
+		switch r := row.(type) {
+		case []interface{}:
+			for j, v := range r {
+				if j >= len(cols) {
+					break
+				}
+				if r[j], err = coerce(i, cols[j], v); err != nil {
+					return nil, failed, err
+				}
+			}
+		case map[string]interface{}:
+			for _, col := range cols {
+				v, present := r[col.Title]
+				if !present {
+					continue
+				}
+				if r[col.Title], err = coerce(i, col, v); err != nil {
+					return nil, failed, err
+				}
+			}
+		}
+	}
+	return list, failed, nil
+}
+
+// coerceValue converts a value to the first of a column's types it can be
+// represented as, returning false if there isn't one
+func coerceValue(v interface{}, types *tabular.ColType) (interface{}, bool) {
+	if types == nil || len(*types) == 0 {
+		return v, true
+	}
+	if v == nil {
+		return nil, true
+	}
+	if s, ok := v.(string); ok && strings.TrimSpace(s) == "" && !types.HasType("string") {
+		return nil, true
+	}
+	// values that already have one of the column types are left as-is
+	for _, t := range *types {
+		if valueHasType(v, t) {
+			return v, true
+		}
+	}
+	for _, t := range *types {
+		if got, ok := convertValue(v, t); ok {
+			return got, true
+		}
+	}
+	return nil, false
+}
+
+func valueHasType(v interface{}, t string) bool {
+	switch t {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "integer":
+		switch x := v.(type) {
+		case int, int64:
+			return true
+		case float64:
+			return x == math.Trunc(x)
+		}
+	case "number":
+		switch v.(type) {
+		case int, int64, float64:
+			return true
+		}
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	}
+	return false
+}
+
+func convertValue(v interface{}, t string) (interface{}, bool) {
+	switch t {
+	case "string":
+		switch x := v.(type) {
+		case int:
+			return strconv.Itoa(x), true
+		case int64:
+			return strconv.FormatInt(x, 10), true
+		case float64:
+			return strconv.FormatFloat(x, 'f', -1, 64), true
+		case bool:
+			return strconv.FormatBool(x), true
+		}
+		return nil, false
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return nil, false
+	}
+	s = strings.TrimSpace(s)
+	switch t {
+	case "integer":
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, true
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil && f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+			return int64(f), true
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f, true
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, true
+		}
+	case "object":
+		obj := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(s), &obj); err == nil {
+			return obj, true
+		}
+	case "array":
+		arr := []interface{}{}
+		if err := json.Unmarshal([]byte(s), &arr); err == nil {
+			return arr, true
+		}
+	}
+	return nil, false
+}
+
+func colTypeString(types *tabular.ColType) string {
+	if types == nil {
+		return "any"
+	}
+	return strings.Join(*types, " or ")
+}
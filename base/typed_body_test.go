@@ -0,0 +1,72 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+)
+
+func TestCoerceBodyTypes(t *testing.T) {
+	st := &dataset.Structure{
+		Format: "csv",
+		Schema: map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "array",
+				"items": []interface{}{
+					map[string]interface{}{"title": "city", "type": "string"},
+					map[string]interface{}{"title": "pop", "type": "integer"},
+					map[string]interface{}{"title": "avg_age", "type": "number"},
+					map[string]interface{}{"title": "in_usa", "type": "boolean"},
+				},
+			},
+		},
+	}
+	newRows := func() []interface{} {
+		return []interface{}{
+			[]interface{}{"toronto", "40000000", "55.5", "false"},
+			[]interface{}{int64(12), int64(300), 44.4, true},
+			[]interface{}{"chatham", "", " 12 ", "nope"},
+			[]interface{}{"raleigh", "1,000", nil, "TRUE"},
+		}
+	}
+
+	got, failed, err := CoerceBodyTypes(st, newRows(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []interface{}{
+		[]interface{}{"toronto", int64(40000000), 55.5, false},
+		[]interface{}{"12", int64(300), 44.4, true},
+		[]interface{}{"chatham", nil, float64(12), nil},
+		[]interface{}{"raleigh", nil, nil, true},
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+	if failed != 2 {
+		t.Errorf("expected 2 failed conversions, got %d", failed)
+	}
+
+	objRows := []interface{}{map[string]interface{}{"city": "toronto", "pop": "5", "extra": "7"}}
+	got, _, err = CoerceBodyTypes(st, objRows, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect = []interface{}{map[string]interface{}{"city": "toronto", "pop": int64(5), "extra": "7"}}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("object rows mismatch (-want +got):\n%s", diff)
+	}
+
+	_, _, err = CoerceBodyTypes(st, newRows(), true)
+	expectErr := `row 2 column "in_usa": can't convert nope to boolean`
+	if err == nil || err.Error() != expectErr {
+		t.Errorf("error mismatch. expected: %q, got: %v", expectErr, err)
+	}
+
+	_, _, err = CoerceBodyTypes(&dataset.Structure{Schema: dataset.BaseSchemaObject}, map[string]interface{}{}, false)
+	if err == nil {
+		t.Error("expected non-tabular schema to error")
+	}
+}
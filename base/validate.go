@@ -3,9 +3,11 @@ package base
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 
 	"github.com/qri-io/dataset"
+	"github.com/qri-io/dataset/dsio"
 	"github.com/qri-io/jsonschema"
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/repo"
@@ -23,6 +25,25 @@ func Validate(ctx context.Context, r repo.Repo, body qfs.File, st *dataset.Struc
 		return nil, fmt.Errorf("structure.Schema passed to Validate must not be nil")
 	}
 
+	// bodies may omit optional columns, validate the body with those columns
+	// filled with null. missing required columns are an error
+	if _, ok := RequiredColumns(st); ok {
+		cf, err := newColumnFiller(st, body)
+		if err != nil {
+			return nil, err
+		}
+		st = &dataset.Structure{Format: "json", Schema: st.Schema}
+		r, pw := io.Pipe()
+		w, err := dsio.NewEntryWriter(st, pw)
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			pw.CloseWithError(cf.fill(w))
+		}()
+		body = qfs.NewMemfileReader("body.json", r)
+	}
+
 	// jsonschema assumes body is json, convert the format if necessary
 	if st.Format != "json" {
 		convert := dataset.Structure{
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/errors"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewAdoptCommand creates a new `qri adopt` cobra command for copying a dataset
+// into a new history owned by the active profile
+func NewAdoptCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &AdoptOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "adopt",
+		Short: "copy a dataset into a new dataset you own",
+		Long: `Adopt saves a copy of the latest version of a dataset under your own profile.
+
+Rename can't change the username of a dataset. Adopt can, by starting a
+brand new history: the copy has a single version containing the components
+of the original, and shares no history with it. The commit message of the
+new version records which dataset it was copied from. Use adopt when you
+want to make a dataset your own, not to track changes someone else makes.`,
+		Example: `  # Adopt a dataset pulled from b5 as your own:
+  $ qri adopt b5/world_bank me/world_bank`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	return cmd
+}
+
+// AdoptOptions encapsulates state for the adopt command
+type AdoptOptions struct {
+	ioes.IOStreams
+
+	From string
+	To   string
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *AdoptOptions) Complete(f Factory, args []string) (err error) {
+	if len(args) == 2 {
+		o.From = args[0]
+		o.To = args[1]
+	}
+	o.inst, err = f.Instance()
+	return
+}
+
+// Validate checks that all user input is valid
+func (o *AdoptOptions) Validate() error {
+	if o.From == "" || o.To == "" {
+		return errors.New(lib.ErrBadArgs, "please provide two dataset names, the original and the name for your copy, for example:\n    $ qri adopt b5/world_bank me/world_bank\nsee `qri adopt --help` for more details")
+	}
+	return nil
+}
+
+// Run executes the adopt command
+func (o *AdoptOptions) Run() error {
+	p := &lib.AdoptParams{
+		Current: o.From,
+		Next:    o.To,
+	}
+	ctx := context.TODO()
+	res, err := o.inst.WithSource("local").Dataset().Adopt(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	printSuccess(o.Out, "adopted %s as %s/%s", o.From, res.Peername, res.Name)
+	return nil
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAdopt(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_adopt", "qri_test_adopt")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+	run.MustExec(t, "qri save --file testdata/movies/meta_override.yaml me/movies")
+
+	output := run.MustExec(t, "qri adopt me/movies me/my_movies")
+	if !strings.Contains(output, "adopted me/movies as test_peer_adopt/my_movies") {
+		t.Errorf("unexpected output: %q", output)
+	}
+
+	output = run.MustExec(t, "qri log me/my_movies")
+	if strings.Count(output, "Commit:") != 1 {
+		t.Errorf("expected 1 version in adopted log. got:\n%s", output)
+	}
+	if !strings.Contains(output, "adopted from test_peer_adopt/movies") {
+		t.Errorf("expected log to note the dataset was adopted. got:\n%s", output)
+	}
+
+	if err := run.ExecCommand("qri adopt me/movies"); err == nil {
+		t.Error("expected adopt without a destination to error")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"path/filepath"
 	"strings"
 
@@ -24,12 +25,23 @@ func NewApplyCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
 the command completes.
 
 The apply command itself does not commit results to the repository. Use
-the --apply flag on the save command to commit results from transforms.`,
+the --apply flag on the save command to commit results from transforms.
+
+With --ref-pattern, apply runs the transform against every dataset with a name
+matching the given glob pattern and saves a new version of each. Failures are
+reported per-dataset and don't stop the rest of the batch.`,
 		Example: ` # Apply a transform and display the output:
  $ qri apply --file transform.star
 
  # Apply a transform using an existing dataset version:
- $ qri apply --file transform.star me/my_dataset`,
+ $ qri apply --file transform.star me/my_dataset
+
+ # Resume a transform from the state a previous run last checkpointed:
+ $ qri apply --file transform.star --resume RUN_ID me/my_dataset
+
+ # Apply a transform to every dataset with a name starting with "raw_",
+ # saving a new version of each:
+ $ qri apply --file normalize.star --ref-pattern "me/raw_*"`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -45,6 +57,8 @@ the --apply flag on the save command to commit results from transforms.`,
 	cmd.MarkFlagRequired("file")
 	cmd.Flags().StringSliceVar(&o.Secrets, "secrets", nil, "transform secrets as comma separated key,value,key,value,... sequence")
 	cmd.Flags().BoolVar(&o.Quiet, "quiet", false, "whether to suppress output from the application")
+	cmd.Flags().StringVar(&o.RefPattern, "ref-pattern", "", "apply & save to all datasets with names matching a glob pattern")
+	cmd.Flags().StringVar(&o.ResumeRunID, "resume", "", "resume from the state last checkpointed by the run with this ID")
 
 	return cmd
 }
@@ -55,10 +69,12 @@ type ApplyOptions struct {
 
 	Instance *lib.Instance
 
-	Refs     *RefSelect
-	FilePath string
-	Quiet    bool
-	Secrets  []string
+	Refs        *RefSelect
+	RefPattern  string
+	FilePath    string
+	Quiet       bool
+	Secrets     []string
+	ResumeRunID string
 }
 
 // Complete adds any missing configuration that can only be added just before calling Run
@@ -66,7 +82,15 @@ func (o *ApplyOptions) Complete(f Factory, args []string) (err error) {
 	if o.Instance, err = f.Instance(); err != nil {
 		return err
 	}
-	if o.Refs, err = GetCurrentRefSelect(f, args, -1); err != nil {
+	if o.RefPattern != "" {
+		if len(args) > 0 {
+			return errors.New("cannot use --ref-pattern with a dataset reference")
+		}
+		if o.ResumeRunID != "" {
+			return errors.New("cannot use --ref-pattern with --resume")
+		}
+		o.Refs = NewEmptyRefSelect()
+	} else if o.Refs, err = GetCurrentRefSelect(f, args, -1); err != nil {
 		// This error will be handled during validation
 		if err != repo.ErrEmptyRef {
 			return err
@@ -101,11 +125,16 @@ func (o *ApplyOptions) Run() (err error) {
 		}
 	}
 
+	if o.RefPattern != "" {
+		return o.runBatch(ctx, &tf)
+	}
+
 	params := lib.ApplyParams{
 		Ref:          o.Refs.Ref(),
 		Transform:    &tf,
 		ScriptOutput: o.Out,
 		Wait:         true,
+		ResumeRunID:  o.ResumeRunID,
 	}
 
 	terminalWidth, terminalHeight := sizeOfTerminal()
@@ -128,3 +157,36 @@ func (o *ApplyOptions) Run() (err error) {
 	}
 	return nil
 }
+
+// runBatch applies a transform to all datasets matching the ref pattern,
+// printing a summary of results
+func (o *ApplyOptions) runBatch(ctx context.Context, tf *dataset.Transform) error {
+	p := &lib.ApplyBatchParams{
+		RefPattern: o.RefPattern,
+		Transform:  tf,
+		Secrets:    tf.Secrets,
+	}
+	if !o.Quiet {
+		p.ScriptOutput = o.Out
+	}
+	res, err := o.Instance.Automation().ApplyBatch(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, r := range res {
+		if r.Error != "" {
+			failed++
+			printErr(o.ErrOut, fmt.Errorf("%s: %s", r.Ref, r.Error))
+			continue
+		}
+		printSuccess(o.ErrOut, "%s: saved %s", r.Ref, r.Path)
+	}
+
+	printInfo(o.ErrOut, "applied transform to %d datasets: %d succeeded, %d failed", len(res), len(res)-failed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d datasets failed", failed, len(res))
+	}
+	return nil
+}
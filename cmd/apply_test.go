@@ -86,3 +86,32 @@ func TestApplyModifyBody(t *testing.T) {
 		t.Errorf("contents mismatch, want: %s, got: %s", expectContains, output)
 	}
 }
+
+func TestApplyRefPattern(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_apply_ref_pattern", "qri_test_apply_ref_pattern")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/raw_movies")
+	run.MustExec(t, "qri save --body testdata/movies/body_twenty.csv me/raw_more_movies")
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+
+	output := run.MustExecCombinedOutErr(t, "qri apply --file testdata/movies/tf_set_meta.star --ref-pattern me/raw_*")
+	if !strings.Contains(output, "applied transform to 2 datasets: 2 succeeded, 0 failed") {
+		t.Errorf("expected summary in output, got: %s", output)
+	}
+
+	for _, ref := range []string{"me/raw_movies", "me/raw_more_movies"} {
+		if output = run.MustExec(t, "qri get meta.title "+ref); output != "Did Set Title\n\n" {
+			t.Errorf("expected %s title to be set by transform, got: %q", ref, output)
+		}
+	}
+	if err := run.ExecCommand("qri get meta.title me/movies"); err == nil {
+		if output := run.GetCommandOutput(); strings.Contains(output, "Did Set Title") {
+			t.Errorf("expected unmatched dataset to be unchanged")
+		}
+	}
+
+	if err := run.ExecCommand("qri apply --file testdata/movies/tf_set_meta.star --ref-pattern me/raw_* me/movies"); err == nil {
+		t.Error("expected combining --ref-pattern with a reference to error")
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewAttachCommand creates a `qri attach` command for adding files to a
+// dataset
+func NewAttachCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &AttachOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "attach DATASET FILE",
+		Short: "attach a file to a dataset",
+		Long: `Attach adds a supplementary file, like a PDF codebook or source documents, to
+a dataset & saves the change as a new version. Attachments are named with the
+base name of the file unless --name is given, attaching a file with the name
+of an existing attachment replaces it.
+
+Attachments are listed in the attachments field of the dataset meta, and are
+pushed & pulled along with the rest of the dataset. Use
+` + "`qri get attachment NAME DATASET`" + ` to read an attachment.`,
+		Example: `  # Attach a codebook to a dataset:
+  $ qri attach me/annual_pop codebook.pdf
+
+  # Attach a file with a different name:
+  $ qri attach me/annual_pop docs/v2.pdf --name codebook.pdf
+
+  # Write an attachment to a file:
+  $ qri get attachment codebook.pdf me/annual_pop -o codebook.pdf`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Name, "name", "", "name of the attachment, defaults to the file name")
+
+	return cmd
+}
+
+// AttachOptions encapsulates state for the attach command
+type AttachOptions struct {
+	ioes.IOStreams
+
+	Refs     *RefSelect
+	FilePath string
+	Name     string
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *AttachOptions) Complete(f Factory, args []string) (err error) {
+	if o.inst, err = f.Instance(); err != nil {
+		return err
+	}
+	if o.Refs, err = GetCurrentRefSelect(f, args[:1], 1); err != nil {
+		return err
+	}
+	o.FilePath, err = filepath.Abs(args[1])
+	return err
+}
+
+// Run executes the attach command
+func (o *AttachOptions) Run() error {
+	p := &lib.AttachParams{
+		Ref:      o.Refs.Ref(),
+		FilePath: o.FilePath,
+		Name:     o.Name,
+	}
+	res, err := o.inst.Dataset().Attach(context.TODO(), p)
+	if err != nil {
+		return err
+	}
+
+	name := o.Name
+	if name == "" {
+		name = filepath.Base(o.FilePath)
+	}
+	ref := dsref.ConvertDatasetToVersionInfo(res).SimpleRef()
+	printSuccess(o.ErrOut, "attached %s: %s", name, refString(ref))
+	return nil
+}
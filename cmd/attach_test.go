@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAttach(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_attach", "qri_test_attach")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/movies")
+
+	tmpDir := run.MakeTmpDir(t, "attach")
+	docPath := filepath.Join(tmpDir, "notes.txt")
+	run.MustWriteFile(t, docPath, "collected from the box office")
+
+	output := run.MustExecCombinedOutErr(t, fmt.Sprintf("qri attach me/movies %s --name codebook.txt", docPath))
+	if expect := "attached codebook.txt"; !strings.Contains(output, expect) {
+		t.Errorf("expected output to contain %q, got:\n%s", expect, output)
+	}
+
+	output = run.MustExec(t, "qri get attachment codebook.txt me/movies")
+	if expect := "collected from the box office\n"; output != expect {
+		t.Errorf("attachment mismatch. want: %q, got: %q", expect, output)
+	}
+
+	outPath := filepath.Join(tmpDir, "out.txt")
+	run.MustExec(t, fmt.Sprintf("qri get attachment codebook.txt me/movies -o %s", outPath))
+	data, err := ioutil.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "collected from the box office" {
+		t.Errorf("attachment file mismatch. got: %q", string(data))
+	}
+
+	err = run.ExecCommand("qri get attachment notes.txt me/movies")
+	if expect := `test_peer_attach/movies has no attachment named "notes.txt", attachments: codebook.txt`; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
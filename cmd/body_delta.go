@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewBodyDeltaCommand creates a new `qri body-delta` command that lists body
+// rows changed since an earlier version of a dataset
+func NewBodyDeltaCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &BodyDeltaOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "body-delta [DATASET]",
+		Short: "list body rows changed since an earlier version",
+		Long: `Body-delta compares the body of a dataset with the body of an earlier version
+and prints the rows that were added or modified, along with the keys of rows
+that were deleted. Where diff shows what changed, body-delta returns the
+changed data itself, which makes it useful for syncing updates into another
+system.
+
+Use --key to match rows across versions by the value of a column. Without a
+key rows are matched by position, and deleted keys are row indexes.`,
+		Example: `  # list rows of me/annual_pop changed since an earlier version:
+  $ qri body-delta me/annual_pop --since /ipfs/QmVersionPath
+
+  # match rows by the "country" column:
+  $ qri body-delta me/annual_pop --since /ipfs/QmVersionPath --key country`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.SincePath, "since", "", "path of the earlier version to compare against")
+	cmd.Flags().StringVar(&o.Key, "key", "", "column that identifies a row across versions")
+	cmd.MarkFlagRequired("since")
+
+	return cmd
+}
+
+// BodyDeltaOptions encapsulates state for the body-delta command
+type BodyDeltaOptions struct {
+	ioes.IOStreams
+
+	Refs      *RefSelect
+	SincePath string
+	Key       string
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *BodyDeltaOptions) Complete(f Factory, args []string) (err error) {
+	if o.inst, err = f.Instance(); err != nil {
+		return err
+	}
+	o.Refs, err = GetCurrentRefSelect(f, args, 1)
+	return err
+}
+
+// Run executes the body-delta command
+func (o *BodyDeltaOptions) Run() error {
+	ctx := context.TODO()
+	p := &lib.BodyDeltaParams{
+		Ref:       o.Refs.Ref(),
+		SincePath: o.SincePath,
+		Key:       o.Key,
+	}
+	res, err := o.inst.Dataset().BodyDelta(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return err
+	}
+	printInfo(o.Out, string(data))
+	return nil
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/qri/lib"
+)
+
+func TestBodyDelta(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_body_delta", "qri_test_body_delta")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_two.json me/movies")
+	sincePath := run.GetPathForDataset(t, 0)
+	run.MustExec(t, "qri save --body testdata/movies/body_four.json me/movies")
+
+	res := &lib.BodyDelta{}
+	output := run.MustExec(t, "qri body-delta me/movies --since "+sincePath)
+	if err := json.Unmarshal([]byte(output), res); err != nil {
+		t.Fatal(err)
+	}
+	expect := &lib.BodyDelta{
+		Added: []interface{}{
+			[]interface{}{"Spectre", float64(148)},
+			[]interface{}{"The Dark Knight Rises", float64(164)},
+		},
+		Modified: []interface{}{},
+		Deleted:  []string{},
+	}
+	if diff := cmp.Diff(expect, res); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+
+	err := run.ExecCommand("qri body-delta me/movies")
+	expectErr := `required flag(s) "since" not set`
+	if diff := cmp.Diff(expectErr, errorMessage(err)); diff != "" {
+		t.Errorf("error mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -17,6 +18,7 @@ import (
 	"github.com/qri-io/dataset/dstest"
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/dsref"
 	repotest "github.com/qri-io/qri/repo/test"
 )
 
@@ -490,6 +492,28 @@ func TestSaveTransformModifiedButSameBody(t *testing.T) {
 	}
 }
 
+// Test that runs started by something other than a person show what
+// triggered them in the log
+func TestSaveRunTrigger(t *testing.T) {
+	if err := confirmQriNotRunning(); err != nil {
+		t.Skip(err.Error())
+	}
+
+	run := NewTestRunner(t, "test_peer_run_trigger", "qri_test_run_trigger")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --apply --file=testdata/movies/tf_123.star me/test_ds")
+	run.MustExec(t, "qri save --apply --run-trigger webhook --file=testdata/movies/tf_modified.star me/test_ds")
+
+	output := run.MustExec(t, "qri log me/test_ds")
+	if count := strings.Count(output, "Trigger:"); count != 1 {
+		t.Errorf("expected only the webhook run to show a trigger, got %d in:\n%s", count, output)
+	}
+	if !strings.Contains(output, "Trigger: webhook") {
+		t.Errorf("expected log to show the webhook trigger, got:\n%s", output)
+	}
+}
+
 // Test that save can be called with a readme file
 func TestSaveReadmeFromFile(t *testing.T) {
 	run := NewTestRunner(t, "test_peer_save_readme_file", "qri_test_save_readme_file")
@@ -638,6 +662,46 @@ func TestListFormatJson(t *testing.T) {
 	}
 }
 
+// Test that list can sort by recent changes, name & size
+func TestListSort(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_list_sort", "list_sort")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/b_ds")
+	run.MustExec(t, "qri save --body=testdata/movies/body_two.json me/c_ds")
+	run.MustExec(t, "qri save --body=testdata/movies/body_four.json me/a_ds")
+
+	cases := []struct {
+		args   string
+		expect []string
+	}{
+		{"--sort recent", []string{"a_ds", "c_ds", "b_ds"}},
+		{"--sort recent --reverse", []string{"b_ds", "c_ds", "a_ds"}},
+		{"--sort name", []string{"a_ds", "b_ds", "c_ds"}},
+		{"--reverse", []string{"c_ds", "b_ds", "a_ds"}},
+		{"--sort size", []string{"b_ds", "a_ds", "c_ds"}},
+	}
+	for _, c := range cases {
+		infos := []dsref.VersionInfo{}
+		output := run.MustExec(t, "qri list --format json "+c.args)
+		if err := json.Unmarshal([]byte(output), &infos); err != nil {
+			t.Fatal(err)
+		}
+		got := make([]string, len(infos))
+		for i, vi := range infos {
+			got[i] = vi.Name
+		}
+		if diff := cmp.Diff(c.expect, got); diff != "" {
+			t.Errorf("qri list %s order mismatch (-want +got):\n%s", c.args, diff)
+		}
+	}
+
+	err := run.ExecCommand("qri list --sort oldest")
+	if diff := cmp.Diff(`invalid sort "oldest", must be one of [recent|name|size]`, errorMessage(err)); diff != "" {
+		t.Errorf("error mismatch (-want +got):\n%s", diff)
+	}
+}
+
 // Test that a dataset name with bad upper-case characters, even if it already exists,
 // produces an error and needs to be renamed
 func TestBadCaseIsAnError(t *testing.T) {
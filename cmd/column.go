@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewColumnCommand creates a new `qri column` command for maintaining the
+// columns of a dataset
+func NewColumnCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &ColumnOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "column",
+		Short: "change the columns of a dataset",
+		Long: `Column commands make targeted changes to the columns of a dataset, saving
+each change as a new version.`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+	}
+
+	rename := &cobra.Command{
+		Use:   "rename DATASET OLD_NAME NEW_NAME",
+		Short: "rename a dataset column",
+		Long: `Rename changes the name of a column in a single commit. The column title in
+the structure schema is always updated. If body rows are objects, like a json
+array of objects, the key of each row is renamed as well. Rename refuses to use
+a name that another column already has.`,
+		Example: `  # rename the "pop" column of me/annual_pop to "population":
+  $ qri column rename me/annual_pop pop population`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args[:1]); err != nil {
+				return err
+			}
+			return o.Rename(args[1], args[2])
+		},
+	}
+
+	describe := &cobra.Command{
+		Use:   "describe DATASET COLUMN",
+		Short: "set the description, unit or example of a column",
+		Long: `Describe records what a column holds in the column's schema, building a data
+dictionary alongside the data. Set a description, the unit values are
+measured in, an example value, or any combination in a single commit. Flags
+that aren't given leave existing values in place. Use 'qri get structure' to
+read column metadata back.`,
+		Example: `  # describe the amount column of me/sales:
+  $ qri column describe me/sales amount --unit USD --description "Transaction total"
+
+  # add an example value:
+  $ qri column describe me/sales region --example "north east"`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args[:1]); err != nil {
+				return err
+			}
+			return o.Describe(args[1])
+		},
+	}
+	describe.Flags().StringVar(&o.Description, "description", "", "what the column holds")
+	describe.Flags().StringVar(&o.Unit, "unit", "", "unit column values are measured in, eg. meters or USD")
+	describe.Flags().StringVar(&o.Example, "example", "", "an example column value")
+
+	cmd.AddCommand(describe, rename)
+	return cmd
+}
+
+// ColumnOptions encapsulates state for the column command
+type ColumnOptions struct {
+	ioes.IOStreams
+
+	Refs *RefSelect
+
+	Description string
+	Unit        string
+	Example     string
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *ColumnOptions) Complete(f Factory, args []string) (err error) {
+	if o.inst, err = f.Instance(); err != nil {
+		return err
+	}
+	o.Refs, err = GetCurrentRefSelect(f, args, 1)
+	return err
+}
+
+// Rename executes the column rename command
+func (o *ColumnOptions) Rename(from, to string) error {
+	ctx := context.TODO()
+	p := &lib.RenameColumnParams{
+		Ref:  o.Refs.Ref(),
+		From: from,
+		To:   to,
+	}
+	res, err := o.inst.Dataset().RenameColumn(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	ref := dsref.ConvertDatasetToVersionInfo(res).SimpleRef()
+	printSuccess(o.ErrOut, "renamed column %s to %s: %s", from, to, refString(ref))
+	return nil
+}
+
+// Describe executes the column describe command
+func (o *ColumnOptions) Describe(column string) error {
+	ctx := context.TODO()
+	p := &lib.DescribeColumnParams{
+		Ref:         o.Refs.Ref(),
+		Column:      column,
+		Description: o.Description,
+		Unit:        o.Unit,
+		Example:     o.Example,
+	}
+	res, err := o.inst.Dataset().DescribeColumn(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	ref := dsref.ConvertDatasetToVersionInfo(res).SimpleRef()
+	printSuccess(o.ErrOut, "described column %s: %s", column, refString(ref))
+	return nil
+}
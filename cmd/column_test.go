@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestColumnRename(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_column_rename", "qri_test_column_rename")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+
+	output := run.MustExecCombinedOutErr(t, "qri column rename me/movies duration length")
+	if !strings.Contains(output, "renamed column duration to length") {
+		t.Errorf("expected output to report rename, got: %q", output)
+	}
+
+	output = run.MustExec(t, "qri get structure.schema.items me/movies")
+	if diff := cmp.Diff("items:\n- title: movie_title\n  type: string\n- title: length\n  type: integer\ntype: array\n\n", output); diff != "" {
+		t.Errorf("column title mismatch (-want +got):\n%s", diff)
+	}
+
+	err := run.ExecCommand("qri column rename me/movies length movie_title")
+	if err == nil {
+		t.Fatal("expected renaming to an existing column to error")
+	}
+	if diff := cmp.Diff(`column "movie_title" already exists`, errorMessage(err)); diff != "" {
+		t.Errorf("error mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestColumnDescribe(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_column_describe", "qri_test_column_describe")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+
+	output := run.MustExecCombinedOutErr(t, "qri column describe me/movies duration --unit minutes --description runtime")
+	if !strings.Contains(output, "described column duration") {
+		t.Errorf("expected output to report column description, got: %q", output)
+	}
+	run.MustExec(t, "qri column describe me/movies duration --example 120")
+
+	output = run.MustExec(t, "qri get structure.schema.items me/movies")
+	expect := "items:\n- title: movie_title\n  type: string\n- description: runtime\n  examples:\n  - \"120\"\n  title: duration\n  type: integer\n  unit: minutes\ntype: array\n\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("column schema mismatch (-want +got):\n%s", diff)
+	}
+
+	err := run.ExecCommand("qri column describe me/movies length --unit minutes")
+	if diff := cmp.Diff(`column "length" not found`, errorMessage(err)); diff != "" {
+		t.Errorf("error mismatch (-want +got):\n%s", diff)
+	}
+
+	err = run.ExecCommand("qri column describe me/movies duration")
+	if diff := cmp.Diff("a description, unit or example is required", errorMessage(err)); diff != "" {
+		t.Errorf("error mismatch (-want +got):\n%s", diff)
+	}
+}
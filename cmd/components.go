@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewComponentsCommand creates a new `qri components` command that lists the
+// components present in a dataset version
+func NewComponentsCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &ComponentsOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "components [DATASET]",
+		Short: "list the components a dataset version has",
+		Long: `Components lists which components a version of a dataset has, along with the
+path and stored size of each one. Components are read from the dataset's list
+of references, so no component contents are loaded. This makes components a
+cheap way to check what a version contains before fetching it with get.`,
+		Example: `  # list the components of the latest version of me/annual_pop:
+  $ qri components me/annual_pop
+
+  # list components as json:
+  $ qri components me/annual_pop --format json`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Format, "format", "", "output format. one of [json]")
+
+	return cmd
+}
+
+// ComponentsOptions encapsulates state for the components command
+type ComponentsOptions struct {
+	ioes.IOStreams
+
+	Refs   *RefSelect
+	Format string
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *ComponentsOptions) Complete(f Factory, args []string) (err error) {
+	if o.inst, err = f.Instance(); err != nil {
+		return err
+	}
+	if o.Format != "" && o.Format != "json" {
+		return fmt.Errorf("invalid format %q, only 'json' is supported", o.Format)
+	}
+	o.Refs, err = GetCurrentRefSelect(f, args, 1)
+	return err
+}
+
+// Run executes the components command
+func (o *ComponentsOptions) Run() error {
+	ctx := context.TODO()
+	p := &lib.ComponentsParams{Ref: o.Refs.Ref()}
+	comps, err := o.inst.Dataset().Components(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	if o.Format == "json" {
+		data, err := json.MarshalIndent(comps, "", "  ")
+		if err != nil {
+			return err
+		}
+		printInfo(o.Out, string(data))
+		return nil
+	}
+
+	printInfo(o.Out, "components of: %s", o.Refs.Ref())
+	for _, name := range []string{"commit", "meta", "structure", "readme", "transform", "viz", "stats", "body"} {
+		c := comps[name]
+		if !c.Present {
+			printInfo(o.Out, "  %-10s absent", name)
+			continue
+		}
+		printInfo(o.Out, "  %-10s %-8s %s", name, humanize.Bytes(c.Size), c.Path)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qri-io/qri/lib"
+)
+
+func TestComponents(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_components", "qri_test_components")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+
+	comps := map[string]lib.ComponentInfo{}
+	output := run.MustExec(t, "qri components me/movies --format json")
+	if err := json.Unmarshal([]byte(output), &comps); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"body", "commit", "structure"} {
+		if c := comps[name]; !c.Present || c.Path == "" || c.Size == 0 {
+			t.Errorf("expected %s to be present with a path and size, got: %#v", name, c)
+		}
+	}
+	for _, name := range []string{"meta", "readme", "transform", "viz"} {
+		if comps[name].Present {
+			t.Errorf("expected %s to be absent", name)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewDedupCheckCommand creates a new `qri dedup-check` command that reports
+// duplicate rows of a dataset body
+func NewDedupCheckCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &DedupCheckOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "dedup-check [DATASET]",
+		Short: "report duplicate rows of a dataset body",
+		Long: `Dedup-check reads a dataset body and reports rows that repeat an earlier row,
+listing the index of each duplicate and the row it repeats. The first
+occurrence of a row is never counted as a duplicate. Only the first ` + fmt.Sprint(base.MaxDuplicateLocations) + `
+duplicates are listed, all of them are counted.
+
+By default whole rows must match. Use --key to compare rows by a single
+column instead, like an id. Rows without a value for the key are skipped.
+
+Dedup-check doesn't change the dataset unless --remove is given, which saves
+a new version of the dataset without the duplicate rows.`,
+		Example: `  # check me/orders for repeated rows:
+  $ qri dedup-check me/orders
+
+  # check for repeated order ids:
+  $ qri dedup-check me/orders --key order_id
+
+  # save a version of me/orders with repeated order ids removed:
+  $ qri dedup-check me/orders --key order_id --remove`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Key, "key", "", "column identifying a row, rows repeating an earlier key are duplicates")
+	cmd.Flags().BoolVar(&o.Remove, "remove", false, "save a new version without duplicate rows")
+	cmd.Flags().StringVar(&o.Format, "format", "", "output format. one of [json]")
+
+	return cmd
+}
+
+// DedupCheckOptions encapsulates state for the dedup-check command
+type DedupCheckOptions struct {
+	ioes.IOStreams
+
+	Refs   *RefSelect
+	Key    string
+	Remove bool
+	Format string
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *DedupCheckOptions) Complete(f Factory, args []string) (err error) {
+	if o.inst, err = f.Instance(); err != nil {
+		return err
+	}
+	if o.Format != "" && o.Format != "json" {
+		return fmt.Errorf("invalid format %q, only 'json' is supported", o.Format)
+	}
+	o.Refs, err = GetCurrentRefSelect(f, args, 1)
+	return err
+}
+
+// Run executes the dedup-check command
+func (o *DedupCheckOptions) Run() error {
+	ctx := context.TODO()
+	p := &lib.DuplicatesParams{
+		Ref: o.Refs.Ref(),
+		Key: o.Key,
+	}
+	find := o.inst.Dataset().Duplicates
+	if o.Remove {
+		find = o.inst.Dataset().RemoveDuplicates
+	}
+	res, err := find(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	if o.Format == "json" {
+		data, err := json.MarshalIndent(res.Report, "", "  ")
+		if err != nil {
+			return err
+		}
+		printInfo(o.Out, string(data))
+		return nil
+	}
+
+	r := res.Report
+	if r.Duplicates == 0 {
+		printSuccess(o.Out, "no duplicate rows found in %d rows of %s", r.Rows, o.Refs.Ref())
+		return nil
+	}
+	if o.Key != "" {
+		printWarning(o.Out, "%d of %d rows repeat a %s in %s:", r.Duplicates, r.Rows, o.Key, o.Refs.Ref())
+	} else {
+		printWarning(o.Out, "%d of %d rows are duplicates in %s:", r.Duplicates, r.Rows, o.Refs.Ref())
+	}
+	for _, loc := range r.Locations {
+		if o.Key != "" {
+			printInfo(o.Out, "  row %d repeats %s %v from row %d", loc.Index, o.Key, loc.Key, loc.FirstIndex)
+		} else {
+			printInfo(o.Out, "  row %d repeats row %d", loc.Index, loc.FirstIndex)
+		}
+	}
+	if len(r.Locations) < r.Duplicates {
+		printInfo(o.Out, "  ...and %d more", r.Duplicates-len(r.Locations))
+	}
+	if res.Dataset != nil {
+		printSuccess(o.Out, "removed %d duplicate rows from %s/%s", r.Duplicates, res.Dataset.Peername, res.Dataset.Name)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/qri/base"
+)
+
+func TestDedupCheck(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_dedup_check", "qri_test_dedup_check")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "dedup_check")
+	bodyPath := filepath.Join(tmpDir, "orders.csv")
+	run.MustWriteFile(t, bodyPath, "order_id,total\n1,10.5\n2,4.25\n2,4.25\n2,6\n3,8.75\n")
+	run.MustExec(t, "qri save --body "+bodyPath+" me/orders")
+
+	report := &base.DuplicateReport{}
+	output := run.MustExec(t, "qri dedup-check me/orders --format json")
+	if err := json.Unmarshal([]byte(output), report); err != nil {
+		t.Fatal(err)
+	}
+	expect := &base.DuplicateReport{
+		Rows:       5,
+		Duplicates: 1,
+		Locations:  []base.DuplicateRow{{Index: 2, FirstIndex: 1}},
+	}
+	if diff := cmp.Diff(expect, report); diff != "" {
+		t.Errorf("report mismatch (-want +got):\n%s", diff)
+	}
+
+	output = run.MustExec(t, "qri dedup-check me/orders --key order_id")
+	if !strings.Contains(output, "2 of 5 rows repeat a order_id") {
+		t.Errorf("expected output to count duplicate keys, got: %q", output)
+	}
+
+	output = run.MustExec(t, "qri dedup-check me/orders --key order_id --remove")
+	if !strings.Contains(output, "removed 2 duplicate rows from test_peer_dedup_check/orders") {
+		t.Errorf("expected output to report removed rows, got: %q", output)
+	}
+	output = run.MustExec(t, "qri get body me/orders")
+	if diff := cmp.Diff(`[[1,10.5],[2,4.25],[3,8.75]]`+"\n", output); diff != "" {
+		t.Errorf("body mismatch (-want +got):\n%s", diff)
+	}
+
+	output = run.MustExec(t, "qri dedup-check me/orders")
+	if !strings.Contains(output, "no duplicate rows found in 3 rows") {
+		t.Errorf("expected no duplicates after removing, got: %q", output)
+	}
+
+	err := run.ExecCommand("qri dedup-check me/orders --key region")
+	if expect := `unknown column "region", available columns: order_id, total`; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
@@ -48,7 +48,21 @@ Each change has a path that locates it within the document`,
   $ qri diff a.json b.json
 
   # Diff a json & csv file:
-  $ qri diff some_table.csv b.json`,
+  $ qri diff some_table.csv b.json
+
+  # Diff two dataset bodies, matching rows by the "id" column:
+  $ qri diff --key id me/population_2016 me/population_2017
+
+  # Review edits to a meta file before saving them:
+  $ qri diff meta me/annual_pop --against meta.json
+
+  # Write the changes between two versions to a patch file, which can be
+  # applied to another dataset with 'qri patch apply':
+  $ qri diff --format patch --key id me/annual_pop > changes.json
+
+  # Print changes as JSON, listing insert, delete & modify operations with
+  # their paths & values for each component, along with a summary:
+  $ qri diff --format json me/annual_pop`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -60,8 +74,11 @@ Each change has a path that locates it within the document`,
 		},
 	}
 
-	cmd.Flags().StringVarP(&o.Format, "format", "f", "pretty", "output format. one of [json,pretty]")
+	cmd.Flags().StringVarP(&o.Format, "format", "f", "pretty", "output format. one of [json,pretty,patch]")
 	cmd.Flags().BoolVar(&o.Summary, "summary", false, "just output the summary")
+	cmd.Flags().StringVar(&o.Key, "key", "", "column to match body rows by before diffing")
+	cmd.Flags().StringVar(&o.Against, "against", "", "json or yaml file to compare dataset meta to")
+	cmd.MarkFlagFilename("against", "json", "yaml", "yml")
 
 	return cmd
 }
@@ -74,6 +91,8 @@ type DiffOptions struct {
 	Selector string
 	Format   string
 	Summary  bool
+	Key      string
+	Against  string
 
 	inst *lib.Instance
 }
@@ -108,9 +127,16 @@ func (o *DiffOptions) Complete(f Factory, args []string) (err error) {
 func (o *DiffOptions) Run() (err error) {
 	p := &lib.DiffParams{
 		Selector: o.Selector,
+		Key:      o.Key,
+		Against:  o.Against,
 	}
 
-	if len(o.Refs.RefList()) == 1 {
+	if o.Against != "" {
+		// > qri diff meta me/example_ds --against meta.json
+		//
+		// left = me/example_ds@head   right = meta.json
+		p.LeftSide = o.Refs.Ref()
+	} else if len(o.Refs.RefList()) == 1 {
 		// > qri diff me/example_ds
 		//
 		// left = me/example_ds@previous   right = me/example_ds@head
@@ -127,14 +153,27 @@ func (o *DiffOptions) Run() (err error) {
 	}
 
 	ctx := context.TODO()
+	if o.Format == "patch" {
+		patch, err := o.inst.Diff().Patch(ctx, p)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(o.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(patch)
+	}
+
 	res, err := o.inst.Diff().Diff(ctx, p)
 	if err != nil {
 		return err
 	}
 
 	if o.Format == "json" {
-		json.NewEncoder(o.Out).Encode(res)
-		return
+		report := lib.NewDiffReport(p, res)
+		if o.Summary {
+			return json.NewEncoder(o.Out).Encode(report.Summary)
+		}
+		return json.NewEncoder(o.Out).Encode(report)
 	}
 
 	return printDiff(o.Out, res, o.Summary)
@@ -3,6 +3,8 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -92,7 +94,17 @@ func TestDiffRun(t *testing.T) {
 				Selector: "meta",
 				Format:   "json",
 			},
-			`{"stat":{"leftNodes":4,"rightNodes":4,"leftWeight":147,"rightWeight":145,"inserts":2,"deletes":2},"diff":[["-","path","/mem/QmZQNhYYVRx8LyMmPV9mqzVZVEeZKpso4Ywu7nwyWvT4X4"],["+","path","/mem/QmWX9MV7ms5QXVGt26gXAbp5z8TdfamUgVBdzxSqhWhPzV"],[" ","qri","md:0"],["-","title","example movie data"],["+","title","example city data"]]}
+			`{"summary":{"leftElements":4,"rightElements":4,"elements":0,"inserts":2,"updates":0,"deletes":2},"components":{"meta":[{"op":"delete","path":"/path","old":"/mem/QmZQNhYYVRx8LyMmPV9mqzVZVEeZKpso4Ywu7nwyWvT4X4"},{"op":"insert","path":"/path","new":"/mem/QmWX9MV7ms5QXVGt26gXAbp5z8TdfamUgVBdzxSqhWhPzV"},{"op":"delete","path":"/title","old":"example movie data"},{"op":"insert","path":"/title","new":"example city data"}]}}
+`,
+		},
+		{"diff json summary",
+			&DiffOptions{
+				Refs:     NewListOfRefSelects([]string{"me/movies", "me/cities"}),
+				Selector: "meta",
+				Format:   "json",
+				Summary:  true,
+			},
+			`{"leftElements":4,"rightElements":4,"elements":0,"inserts":2,"updates":0,"deletes":2}
 `,
 		},
 	}
@@ -256,3 +268,30 @@ func TestDiffKnownFilenameComponent(t *testing.T) {
 		t.Errorf("output mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestDiffAgainstMetaFile(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_diff_against", "qri_test_diff_against")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/test_movies")
+	run.MustExec(t, "qri meta set me/test_movies title Movies")
+
+	metaPath := filepath.Join(run.MakeTmpDir(t, "diff_against"), "meta.yaml")
+	run.MustWriteFile(t, metaPath, "title: Movies\ndescription: ten movies\n")
+
+	output := run.MustExec(t, fmt.Sprintf("qri diff meta me/test_movies --against %s", metaPath))
+	expect := `+1 element. 1 insert. 0 deletes.
+
++description: "ten movies"
+ qri: "md:0"
+ title: "Movies"
+`
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	err := run.ExecCommand(fmt.Sprintf("qri diff body me/test_movies --against %s", metaPath))
+	if expect := "can only diff meta against a file"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
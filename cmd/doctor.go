@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewDoctorCommand creates a `qri doctor` subcommand for checking the repo
+// for common problems
+func NewDoctorCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &DoctorOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "check your repo for common problems",
+		Long: `Doctor runs a set of checks against your qri repo and reports any problems
+it finds, along with a suggested fix. Doctor checks for:
+
+  - configured filesystems that aren't available
+  - dataset logs that don't resolve to a saved version
+  - datasets with a head version that isn't in the store
+  - a dataset cache that disagrees with the logbook
+
+Run with --fix to rebuild the dataset cache. When stranded dataset logs are
+found --fix asks before removing them, removing a log deletes its history
+and can't be undone. Other problems need to be fixed by hand.`,
+		Example: `  # Check the repo for problems:
+  $ qri doctor
+
+  # Fix the problems that can be fixed automatically:
+  $ qri doctor --fix`,
+		Annotations: map[string]string{
+			"group": "other",
+		},
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.Fix, "fix", false, "fix problems that can be fixed automatically")
+	cmd.Flags().StringVar(&o.Format, "format", "pretty", "output format [pretty|json]")
+
+	return cmd
+}
+
+// DoctorOptions encapsulates state for the doctor command
+type DoctorOptions struct {
+	ioes.IOStreams
+
+	Fix    bool
+	Format string
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *DoctorOptions) Complete(f Factory, args []string) (err error) {
+	if o.Format != "pretty" && o.Format != "json" {
+		return fmt.Errorf("unknown format %q, must be pretty or json", o.Format)
+	}
+	o.inst, err = f.Instance()
+	return err
+}
+
+// Run executes the doctor command
+func (o *DoctorOptions) Run() error {
+	ctx := context.TODO()
+	p := &lib.DoctorParams{Fix: o.Fix}
+	if o.Fix {
+		found, err := o.inst.Dataset().Doctor(ctx, &lib.DoctorParams{})
+		if err != nil {
+			return err
+		}
+		stranded := 0
+		for _, f := range found.Findings {
+			if f.Check == "logbook" {
+				stranded++
+			}
+		}
+		if stranded > 0 {
+			msg := fmt.Sprintf("remove %d stranded dataset logs? this deletes their history and can't be undone", stranded)
+			p.RemoveStrandedLogs = confirm(o.ErrOut, o.In, msg, false)
+		}
+	}
+
+	res, err := o.inst.Dataset().Doctor(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	if o.Format == "json" {
+		data, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		printInfo(o.Out, string(data))
+		return nil
+	}
+
+	if len(res.Findings) == 0 {
+		printSuccess(o.ErrOut, "no problems found")
+		return nil
+	}
+
+	unfixed := 0
+	for _, f := range res.Findings {
+		if f.Fixed {
+			printSuccess(o.Out, "fixed [%s] %s", f.Check, f.Problem)
+			continue
+		}
+		unfixed++
+		printWarning(o.Out, "[%s] %s", f.Check, f.Problem)
+		fmt.Fprintf(o.Out, "  %s\n", f.Suggestion)
+	}
+	if unfixed > 0 {
+		printInfo(o.ErrOut, "found %d problems", unfixed)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDoctor(t *testing.T) {
+	r := NewTestRunner(t, "test_peer_doctor", "qri_test_doctor")
+	defer r.Delete()
+
+	r.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/test_movies")
+
+	output := r.MustExecCombinedOutErr(t, "qri doctor")
+	if expect := "no problems found"; !strings.Contains(output, expect) {
+		t.Errorf("expected output to contain %q, got:\n%s", expect, output)
+	}
+
+	err := r.ExecCommand("qri doctor --format csv")
+	if expect := `unknown format "csv", must be pretty or json`; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/errors"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewExportGitCommand creates a new `qri export-git` cobra command for
+// writing dataset history as a git fast-import stream
+func NewExportGitCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &ExportGitOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "export-git DATASET",
+		Short: "write dataset history as a git fast-import stream",
+		Long: `Export-git writes the history of a dataset as a stream for git fast-import,
+mirroring the dataset into a git repository for tools that expect git.
+
+Each dataset version becomes one git commit, oldest first, holding the
+version's component files: body, meta.json, structure.json, readme.md,
+transform.star & template.html. Commit titles, messages, timestamps and
+authors carry over, and each commit message ends with a Qri-Path trailer
+recording the version it came from.
+
+Every version must be stored locally. Exporting again rewrites the branch
+from the start, so export to a fresh branch or repository.`,
+		Example: `  # Mirror a dataset into a new git repository:
+  $ git init annual_pop && cd annual_pop
+  $ qri export-git me/annual_pop | git fast-import
+  $ git checkout main
+
+  # Write the stream to a file, on a branch named qri:
+  $ qri export-git --branch qri -o annual_pop.fi me/annual_pop`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Branch, "branch", "main", "git branch to write commits to")
+	cmd.Flags().StringVarP(&o.Outfile, "outfile", "o", "", "file to write the stream to instead of stdout")
+
+	return cmd
+}
+
+// ExportGitOptions encapsulates state for the export-git command
+type ExportGitOptions struct {
+	ioes.IOStreams
+
+	Ref     string
+	Branch  string
+	Outfile string
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *ExportGitOptions) Complete(f Factory, args []string) (err error) {
+	if len(args) > 0 {
+		o.Ref = args[0]
+	}
+	o.inst, err = f.Instance()
+	return
+}
+
+// Validate checks that all user input is valid
+func (o *ExportGitOptions) Validate() error {
+	if o.Ref == "" {
+		return errors.New(lib.ErrBadArgs, "please provide a dataset to export, for example:\n    $ qri export-git me/dataset_name | git fast-import\nsee `qri export-git --help` for more details")
+	}
+	return nil
+}
+
+// Run executes the export-git command
+func (o *ExportGitOptions) Run() error {
+	p := &lib.ExportGitParams{
+		Ref:    o.Ref,
+		Branch: o.Branch,
+		Writer: o.Out,
+	}
+	if o.Outfile != "" {
+		f, err := os.Create(o.Outfile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		p.Writer = f
+	}
+
+	if _, err := o.inst.WithSource("local").Dataset().ExportGit(context.TODO(), p); err != nil {
+		return err
+	}
+	if o.Outfile != "" {
+		printSuccess(o.ErrOut, "wrote git fast-import stream to %s", o.Outfile)
+	}
+	return nil
+}
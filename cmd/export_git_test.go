@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportGit(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_export_git", "qri_test_export_git")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+	run.MustExec(t, "qri save --body testdata/movies/body_twenty.csv -t more_movies me/movies")
+
+	output := run.MustExec(t, "qri export-git me/movies")
+	if n := strings.Count(output, "commit refs/heads/main\n"); n != 2 {
+		t.Errorf("expected 2 commits in the stream, got %d", n)
+	}
+	if !strings.Contains(output, "M 644 inline body.csv\n") || !strings.Contains(output, "M 644 inline structure.json\n") {
+		t.Errorf("expected component files in the stream, got: %q", output)
+	}
+	if first, second := strings.Index(output, "created dataset"), strings.Index(output, "more_movies"); first == -1 || second < first {
+		t.Errorf("expected commits oldest first, got: %q", output)
+	}
+
+	tmpDir := run.MakeTmpDir(t, "export_git")
+	streamPath := filepath.Join(tmpDir, "movies.fi")
+	run.MustExec(t, "qri export-git --branch qri -o "+streamPath+" me/movies")
+	stream, err := ioutil.ReadFile(streamPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(stream), "commit refs/heads/qri\n") {
+		t.Errorf("expected stream file to write to branch qri, got: %q", stream)
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed, skipping import")
+	}
+	repoDir := filepath.Join(tmpDir, "repo")
+	gitCmd := func(stdin string, args ...string) string {
+		cmd := exec.Command("git", append([]string{"-C", repoDir}, args...)...)
+		cmd.Stdin = strings.NewReader(stdin)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %s\n%s", strings.Join(args, " "), err, out)
+		}
+		return string(out)
+	}
+	if out, err := exec.Command("git", "init", "-q", repoDir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %s\n%s", err, out)
+	}
+	gitCmd(string(stream), "fast-import", "--quiet")
+	if log := gitCmd("", "log", "--format=%s", "qri"); log != "more_movies\ncreated dataset from body_ten.csv\n" {
+		t.Errorf("git log mismatch. got: %q", log)
+	}
+	if files := gitCmd("", "ls-tree", "--name-only", "qri"); files != "body.csv\nstructure.json\n" {
+		t.Errorf("git tree mismatch. got: %q", files)
+	}
+}
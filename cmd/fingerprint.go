@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewFingerprintCommand creates a new `qri fingerprint` command that hashes
+// the content of a dataset
+func NewFingerprintCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &FingerprintOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "fingerprint [DATASET]",
+		Short: "print a hash of dataset content",
+		Long: `Fingerprint prints a hash of the content of a dataset version. Unlike a
+version path, a fingerprint doesn't depend on how the dataset is stored, so
+datasets with the same content have the same fingerprint even if they're
+stored in different formats or have different histories. Compare fingerprints
+to check that a copy of a dataset matches the original.
+
+The fingerprint covers the body, meta, schema, transform, readme & viz. Use
+--body-only to fingerprint just the body.`,
+		Example: `  # print the fingerprint of the latest version of me/annual_pop:
+  $ qri fingerprint me/annual_pop
+
+  # check if two datasets have the same body:
+  $ qri fingerprint --body-only me/annual_pop
+  $ qri fingerprint --body-only me/annual_pop_copy`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.BodyOnly, "body-only", false, "only fingerprint the body")
+
+	return cmd
+}
+
+// FingerprintOptions encapsulates state for the fingerprint command
+type FingerprintOptions struct {
+	ioes.IOStreams
+
+	Refs     *RefSelect
+	BodyOnly bool
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *FingerprintOptions) Complete(f Factory, args []string) (err error) {
+	if o.inst, err = f.Instance(); err != nil {
+		return err
+	}
+	o.Refs, err = GetCurrentRefSelect(f, args, 1)
+	return err
+}
+
+// Run executes the fingerprint command
+func (o *FingerprintOptions) Run() error {
+	p := &lib.FingerprintParams{
+		Ref:      o.Refs.Ref(),
+		BodyOnly: o.BodyOnly,
+	}
+	fp, err := o.inst.Dataset().Fingerprint(context.TODO(), p)
+	if err != nil {
+		return err
+	}
+	printInfo(o.Out, fp)
+	return nil
+}
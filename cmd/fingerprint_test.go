@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_fingerprint", "qri_test_fingerprint")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/movies")
+	run.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/movies_copy")
+	run.MustExec(t, "qri meta set me/movies_copy title Copied")
+
+	original := strings.TrimSpace(run.MustExec(t, "qri fingerprint --body-only me/movies"))
+	if len(original) != 64 {
+		t.Errorf("expected a hex sha256 digest, got %q", original)
+	}
+	if got := strings.TrimSpace(run.MustExec(t, "qri fingerprint --body-only me/movies_copy")); got != original {
+		t.Errorf("expected copies with the same body to have the same body fingerprint. want: %q, got: %q", original, got)
+	}
+
+	full := strings.TrimSpace(run.MustExec(t, "qri fingerprint me/movies"))
+	if got := strings.TrimSpace(run.MustExec(t, "qri fingerprint me/movies_copy")); got == full {
+		t.Errorf("expected datasets with different meta to have different fingerprints")
+	}
+}
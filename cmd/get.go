@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"strings"
 
 	"github.com/ghodss/yaml"
 	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/base/component"
 	"github.com/qri-io/qri/base/params"
 	"github.com/qri-io/qri/lib"
@@ -35,11 +37,69 @@ dataset and its fields.`,
   $ qri get meta me/annual_pop
 
   # Print the dataset body size to the console:
-  $ qri get structure.length me/annual_pop`,
+  $ qri get structure.length me/annual_pop
+
+  # Print a meta field, erroring if the field doesn't exist instead of
+  # printing null:
+  $ qri get meta.title --strict me/annual_pop
+
+  # Print per-column statistics as a csv table:
+  $ qri get stats --format csv me/annual_pop
+
+  # Write the first 100 rows of the body as an html table:
+  $ qri get body --format html --limit 100 -o preview.html me/annual_pop
+
+  # Print the meta as a DCAT JSON-LD document for open data catalogs:
+  $ qri get meta --format dcat me/annual_pop
+
+  # Print the structure as a CREATE TABLE statement for postgres:
+  $ qri get structure --format sql --dialect postgres me/annual_pop
+
+  # Print one body row per line, for piping into tools like jq:
+  $ qri get body --format ndjson me/annual_pop | jq -c '.[0]'
+
+  # Print the body as fixed-width text, with columns 20, 10 & 8 characters wide:
+  $ qri get body --format fixed --widths 20,10,8 me/annual_pop
+
+  # Write a file attached to the dataset to codebook.pdf:
+  $ qri get attachment codebook.pdf me/annual_pop -o codebook.pdf
+
+  # Print only the year and population columns of the body:
+  $ qri get body --columns year,population me/annual_pop
+
+  # Print the body as csv, writing nulls as \N & quoting empty strings:
+  $ qri get body --format csv --null-token '\N' me/annual_pop
+
+  # Write the body as a csv file that opens correctly in Excel on Windows:
+  $ qri get body --format csv --bom --crlf -o annual_pop.csv me/annual_pop
+
+  # Print body rows whose id column matches one of the ids listed in ids.txt,
+  # one id per line:
+  $ qri get body --keys-file ids.txt --key-column id me/annual_pop
+
+  # Print the value of a body with one row & one column, like a computed
+  # total, without wrapping it in a row:
+  $ qri get body --scalar me/total_pop
+
+  # Print the body as JSON with values converted to their schema column types,
+  # erroring on values that can't be converted instead of printing null:
+  $ qri get body --typed --typed-strict me/annual_pop
+
+  # Print body rows with a date column value in the first quarter of 2024,
+  # including all of March 31st:
+  $ qri get body --time-column date --from 2024-01-01 --to 2024-03-31 me/readings
+
+  # Print about 1000 rows of a large body for plotting, keeping the peaks &
+  # dips of the reading column:
+  $ qri get body --decimate 1000 --decimate-column reading me/readings
+
+  # Print a small body with rows and columns swapped, each column becoming a
+  # row that starts with the column title:
+  $ qri get body --transpose me/country_facts`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
-		Args: cobra.MaximumNArgs(2),
+		Args: cobra.MaximumNArgs(3),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := o.Complete(f, args); err != nil {
 				return err
@@ -48,12 +108,33 @@ dataset and its fields.`,
 		},
 	}
 
-	cmd.Flags().StringVarP(&o.Format, "format", "f", "", "set output format [json, yaml, csv, zip]. If format is set to 'zip' it will save the entire dataset as a zip archive.")
+	cmd.Flags().StringVarP(&o.Format, "format", "f", "", "set output format [json, yaml, csv, ndjson, html, fixed, zip, dcat, sql], or a registered body format. If format is set to 'zip' it will save the entire dataset as a zip archive.")
 	cmd.Flags().BoolVar(&o.Pretty, "pretty", false, "whether to print output with indentation, only for json format")
 	cmd.Flags().IntVar(&o.Limit, "limit", -1, "for body, limit how many entries to get per request")
 	cmd.Flags().IntVar(&o.Offset, "offset", -1, "for body, offset amount at which to get entries")
 	cmd.Flags().BoolVarP(&o.All, "all", "a", true, "for body, whether to get all entries")
 	cmd.Flags().StringVarP(&o.Outfile, "outfile", "o", "", "file to write output to")
+	cmd.Flags().BoolVar(&o.Strict, "strict", false, "error if a selected field doesn't exist instead of printing null")
+	cmd.Flags().StringSliceVar(&o.Columns, "columns", nil, "for body, only get these comma-separated columns")
+	cmd.Flags().IntSliceVar(&o.Widths, "widths", nil, "for fixed format, comma-separated column widths. defaults to the longest value in each column")
+	cmd.Flags().StringVar(&o.Overflow, "overflow", base.OverflowTruncate, "for fixed format, how to handle values longer than their column [truncate, error]")
+	cmd.Flags().BoolVar(&o.CSVNulls, "csv-nulls", false, "for csv format, write nulls as empty fields & quote empty strings")
+	cmd.Flags().StringVar(&o.NullToken, "null-token", "", "for csv format, text to write for null values. implies --csv-nulls")
+	cmd.Flags().BoolVar(&o.BOM, "bom", false, "for csv format, start output with a UTF-8 byte order mark")
+	cmd.Flags().BoolVar(&o.CRLF, "crlf", false, "for csv format, end lines with CRLF instead of LF")
+	cmd.Flags().StringVar(&o.KeysFile, "keys-file", "", "for body, only get rows matching keys listed one per line in this file")
+	cmd.Flags().StringVar(&o.KeyColumn, "key-column", "", "for body, column to match --keys-file keys against")
+	cmd.Flags().StringVar(&o.Dialect, "dialect", "", "for sql format, database to write DDL for [postgres, mysql, sqlite]. defaults to ANSI SQL")
+	cmd.Flags().BoolVar(&o.Scalar, "scalar", false, "for body, print the single value of a one row, one column body. errors if the body has more values")
+	cmd.Flags().BoolVar(&o.Typed, "typed", false, "for body, convert values to the types their schema columns declare. values that can't be converted print as null")
+	cmd.Flags().BoolVar(&o.TypedStrict, "typed-strict", false, "with --typed, error on values that can't be converted instead of printing null")
+	cmd.Flags().StringVar(&o.TimeColumn, "time-column", "", "for body, only get rows with a time in this column between --from and --to")
+	cmd.Flags().StringVar(&o.From, "from", "", "with --time-column, start of the time range as a date or RFC 3339 timestamp, inclusive")
+	cmd.Flags().StringVar(&o.To, "to", "", "with --time-column, end of the time range as a date or RFC 3339 timestamp, inclusive")
+	cmd.Flags().BoolVar(&o.TimeStrict, "time-strict", false, "with --time-column, error on rows with unreadable times instead of skipping them")
+	cmd.Flags().IntVar(&o.Decimate, "decimate", 0, "for body, down-sample to about this many rows for plotting, keeping the first and last rows")
+	cmd.Flags().StringVar(&o.DecimateColumn, "decimate-column", "", "with --decimate, numeric column whose shape to keep. without one rows are picked at an even stride")
+	cmd.Flags().BoolVar(&o.Transpose, "transpose", false, "for body, swap rows and columns. only for bodies of up to 1000 rows")
 
 	cmd.Flags().BoolVar(&o.Offline, "offline", false, "prevent network access")
 	cmd.Flags().StringVar(&o.Remote, "remote", "", "name to get any remote data from")
@@ -68,6 +149,9 @@ type GetOptions struct {
 	Refs     *RefSelect
 	Selector string
 	Format   string
+	// Attachment is the name of the attachment to get when Selector is
+	// "attachment"
+	Attachment string
 
 	Limit  int
 	Offset int
@@ -75,6 +159,37 @@ type GetOptions struct {
 
 	Pretty  bool
 	Outfile string
+	Strict  bool
+	Columns []string
+
+	Widths   []int
+	Overflow string
+
+	CSVNulls  bool
+	NullToken string
+	BOM       bool
+	CRLF      bool
+
+	KeysFile  string
+	KeyColumn string
+	keys      []string
+
+	Scalar bool
+
+	Typed       bool
+	TypedStrict bool
+
+	TimeColumn string
+	From       string
+	To         string
+	TimeStrict bool
+
+	Decimate       int
+	DecimateColumn string
+
+	Transpose bool
+
+	Dialect string
 
 	Offline bool
 	Remote  string
@@ -82,13 +197,41 @@ type GetOptions struct {
 	inst *lib.Instance
 }
 
+// exclusiveBodyFlags are the get flags that select or reshape body rows. each
+// only works when getting body, and only one can be used at a time. all but
+// --columns need json or yaml output
+var exclusiveBodyFlags = []struct {
+	name      string
+	set       func(o *GetOptions) bool
+	anyFormat bool
+}{
+	{"--columns", func(o *GetOptions) bool { return len(o.Columns) > 0 }, true},
+	{"--keys-file", func(o *GetOptions) bool { return o.KeysFile != "" }, false},
+	{"--scalar", func(o *GetOptions) bool { return o.Scalar }, false},
+	{"--time-column", func(o *GetOptions) bool { return o.TimeColumn != "" }, false},
+	{"--decimate", func(o *GetOptions) bool { return o.Decimate != 0 }, false},
+	{"--transpose", func(o *GetOptions) bool { return o.Transpose }, false},
+}
+
 // Complete adds any missing configuration that can only be added just before calling Run
 func (o *GetOptions) Complete(f Factory, args []string) (err error) {
 	if o.inst, err = f.Instance(); err != nil {
 		return
 	}
 
-	if len(args) > 0 {
+	if len(args) > 0 && args[0] == "attachment" {
+		if len(args) < 2 {
+			return fmt.Errorf("an attachment name is required")
+		}
+		if o.Format != "" {
+			return fmt.Errorf("can't use --format when getting an attachment")
+		}
+		o.Selector = args[0]
+		o.Attachment = args[1]
+		args = args[2:]
+	} else if len(args) > 2 {
+		return fmt.Errorf("accepts at most 2 arg(s), received %d", len(args))
+	} else if len(args) > 0 {
 		if component.IsDatasetField.MatchString(args[0]) {
 			o.Selector = args[0]
 			args = args[1:]
@@ -99,15 +242,33 @@ func (o *GetOptions) Complete(f Factory, args []string) (err error) {
 	}
 
 	if o.Selector == "body" {
-		if o.Limit != -1 && o.Offset == -1 {
-			o.Offset = 0
-		}
 		if o.Offset != -1 || o.Limit != -1 {
 			o.All = false
 		}
+		if !o.All {
+			if o.Offset == -1 {
+				o.Offset = 0
+			}
+			// a zero limit gets the configured default page size
+			if o.Limit == -1 {
+				o.Limit = 0
+			}
+		}
 	} else {
-		if o.Format == "csv" {
-			return fmt.Errorf("can only use --format=csv when getting body")
+		if o.Format == "csv" && o.Selector != "stats" {
+			return fmt.Errorf("can only use --format=csv when getting body or stats")
+		}
+		if o.Format == "html" {
+			return fmt.Errorf("can only use --format=html when getting body")
+		}
+		if o.Format == "ndjson" {
+			return fmt.Errorf("can only use --format=ndjson when getting body")
+		}
+		if o.Format == "fixed" {
+			return fmt.Errorf("can only use --format=fixed when getting body")
+		}
+		if isBodyCodec(o.Format) {
+			return fmt.Errorf("can only use --format=%s when getting body", o.Format)
 		}
 		if o.Limit != -1 {
 			return fmt.Errorf("can only use --limit flag when getting body")
@@ -119,10 +280,100 @@ func (o *GetOptions) Complete(f Factory, args []string) (err error) {
 			return fmt.Errorf("can only use --all flag when getting body")
 		}
 	}
+	if len(o.Widths) > 0 && o.Format != "fixed" {
+		return fmt.Errorf("can only use --widths flag with --format=fixed")
+	}
+	if (o.CSVNulls || o.NullToken != "") && (o.Format != "csv" || o.Selector != "body") {
+		return fmt.Errorf("can only use --csv-nulls and --null-token flags when getting body with --format=csv")
+	}
+	if (o.BOM || o.CRLF) && (o.Format != "csv" || o.Selector != "body") {
+		return fmt.Errorf("can only use --bom and --crlf flags when getting body with --format=csv")
+	}
+	if o.Format == "dcat" && o.Selector != "meta" {
+		return fmt.Errorf("can only use --format=dcat when getting meta")
+	}
+	if o.Format == "sql" && o.Selector != "structure" {
+		return fmt.Errorf("can only use --format=sql when getting structure")
+	}
+	if o.Dialect != "" && o.Format != "sql" {
+		return fmt.Errorf("can only use --dialect flag with --format=sql")
+	}
+	used := ""
+	for _, flag := range exclusiveBodyFlags {
+		if !flag.set(o) {
+			continue
+		}
+		if o.Selector != "body" {
+			return fmt.Errorf("can only use %s flag when getting body", flag.name)
+		}
+		if !flag.anyFormat && o.Format != "" && o.Format != "json" && o.Format != "yaml" {
+			return fmt.Errorf("can only use %s with --format=json or --format=yaml", flag.name)
+		}
+		if used != "" {
+			return fmt.Errorf("can't use %s and %s flags together", used, flag.name)
+		}
+		used = flag.name
+	}
+	if o.KeysFile != "" || o.KeyColumn != "" {
+		if err = o.completeKeys(); err != nil {
+			return err
+		}
+	}
+	if o.TypedStrict && !o.Typed {
+		return fmt.Errorf("can only use --typed-strict with --typed")
+	}
+	if o.Typed {
+		if o.Selector != "body" {
+			return fmt.Errorf("can only use --typed flag when getting body")
+		}
+		if o.Format != "" && o.Format != "json" && o.Format != "yaml" {
+			return fmt.Errorf("can only use --typed with --format=json or --format=yaml")
+		}
+		if len(o.Columns) > 0 || o.Scalar {
+			return fmt.Errorf("can't use --typed with --columns or --scalar flags")
+		}
+	}
+	if (o.From != "" || o.To != "" || o.TimeStrict) && o.TimeColumn == "" {
+		return fmt.Errorf("can only use --from, --to and --time-strict flags with --time-column")
+	}
+	if o.TimeColumn != "" && o.From == "" && o.To == "" {
+		return fmt.Errorf("--time-column requires --from, --to or both")
+	}
+	if o.DecimateColumn != "" && o.Decimate == 0 {
+		return fmt.Errorf("can only use --decimate-column with --decimate")
+	}
+	if o.Strict && (o.Selector == "" || o.Selector == "body" || o.Selector == "stats" || o.Selector == "attachment") {
+		return fmt.Errorf("can only use --strict flag when getting a field")
+	}
 
 	return
 }
 
+// completeKeys checks the flags for selecting body rows by key & reads the
+// keys file
+func (o *GetOptions) completeKeys() error {
+	if o.Selector != "body" {
+		return fmt.Errorf("can only use --keys-file and --key-column flags when getting body")
+	}
+	if o.KeysFile == "" || o.KeyColumn == "" {
+		return fmt.Errorf("--keys-file and --key-column flags must be used together")
+	}
+
+	data, err := ioutil.ReadFile(o.KeysFile)
+	if err != nil {
+		return fmt.Errorf("reading keys file: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if key := strings.TrimSpace(line); key != "" {
+			o.keys = append(o.keys, key)
+		}
+	}
+	if len(o.keys) == 0 {
+		return fmt.Errorf("keys file %q has no keys", o.KeysFile)
+	}
+	return nil
+}
+
 // Run executes the get command
 func (o *GetOptions) Run() (err error) {
 	if o.Offline {
@@ -139,9 +390,27 @@ func (o *GetOptions) Run() (err error) {
 
 	ctx := context.TODO()
 	p := &lib.GetParams{
-		Ref:      o.Refs.Ref(),
-		Selector: o.Selector,
-		All:      o.All,
+		Ref:            o.Refs.Ref(),
+		Selector:       o.Selector,
+		All:            o.All,
+		Strict:         o.Strict,
+		Columns:        o.Columns,
+		CSVNulls:       o.CSVNulls,
+		NullToken:      o.NullToken,
+		CSVBOM:         o.BOM,
+		CSVCRLF:        o.CRLF,
+		Keys:           o.keys,
+		KeyColumn:      o.KeyColumn,
+		Scalar:         o.Scalar,
+		Typed:          o.Typed,
+		TypedStrict:    o.TypedStrict,
+		TimeColumn:     o.TimeColumn,
+		From:           o.From,
+		To:             o.To,
+		TimeStrict:     o.TimeStrict,
+		Decimate:       o.Decimate,
+		DecimateColumn: o.DecimateColumn,
+		Transpose:      o.Transpose,
 		List: params.List{
 			Offset: o.Offset,
 			Limit:  o.Limit,
@@ -149,6 +418,14 @@ func (o *GetOptions) Run() (err error) {
 	}
 	var outBytes []byte
 	switch {
+	case o.Selector == "attachment":
+		outBytes, err = o.inst.WithSource(o.Remote).Dataset().GetAttachment(ctx, &lib.GetAttachmentParams{
+			Ref:  p.Ref,
+			Name: o.Attachment,
+		})
+		if err != nil {
+			return err
+		}
 	case o.Format == "zip":
 		zipResults, err := o.inst.Dataset().GetZip(ctx, p)
 		if err != nil {
@@ -163,14 +440,56 @@ func (o *GetOptions) Run() (err error) {
 		if err != nil {
 			return err
 		}
+	case o.Format == "html":
+		outBytes, err = o.inst.WithSource(o.Remote).Dataset().GetHTML(ctx, p)
+		if err != nil {
+			return err
+		}
+	case o.Format == "fixed":
+		outBytes, err = o.inst.Dataset().GetFixedWidth(ctx, &lib.GetFixedWidthParams{
+			GetParams: *p,
+			Widths:    o.Widths,
+			Overflow:  o.Overflow,
+		})
+		if err != nil {
+			return err
+		}
+	case o.Format == "dcat":
+		outBytes, err = o.inst.WithSource(o.Remote).Dataset().GetDCAT(ctx, p)
+		if err != nil {
+			return err
+		}
+	case o.Format == "sql":
+		outBytes, err = o.inst.WithSource(o.Remote).Dataset().GetSQL(ctx, &lib.GetSQLParams{
+			GetParams: *p,
+			Dialect:   o.Dialect,
+		})
+		if err != nil {
+			return err
+		}
 	default:
+		if o.Format == "ndjson" || isBodyCodec(o.Format) {
+			p.Format = o.Format
+		}
 		res, err := o.inst.WithSource(o.Remote).Dataset().Get(ctx, p)
 		if err != nil {
 			return err
 		}
+		if len(res.MissingKeys) > 0 {
+			printWarning(o.ErrOut, "%d keys not found: %s", len(res.MissingKeys), strings.Join(res.MissingKeys, ", "))
+		}
+		if res.TypeErrors > 0 {
+			printWarning(o.ErrOut, "%d values couldn't be converted to their column type and were set to null", res.TypeErrors)
+		}
+		if res.SkippedRows > 0 {
+			printWarning(o.ErrOut, "%d rows with unreadable times were skipped", res.SkippedRows)
+		}
 		switch {
-		case lib.IsSelectorScriptFile(o.Selector):
+		case lib.IsSelectorScriptFile(o.Selector), isBodyCodec(o.Format):
 			outBytes = res.Bytes
+		case o.Format == "ndjson":
+			// output always ends with a newline
+			outBytes = bytes.TrimSuffix(res.Bytes, []byte{'\n'})
 		case o.Format == "json" || (o.Selector == "body" && o.Format == ""):
 			if o.Pretty {
 				outBytes, err = json.MarshalIndent(res.Value, "", "  ")
@@ -204,3 +523,10 @@ func (o *GetOptions) Run() (err error) {
 	printToPager(o.Out, buf)
 	return nil
 }
+
+// isBodyCodec returns true if format names a body format registered with
+// base.RegisterBodyFormat
+func isBodyCodec(format string) bool {
+	_, ok := base.BodyCodecFor(format)
+	return ok
+}
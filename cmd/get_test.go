@@ -3,10 +3,15 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/qri-io/dataset/dstest"
+	"github.com/qri-io/qri/base"
 )
 
 func TestGetComplete(t *testing.T) {
@@ -438,6 +443,27 @@ func TestGetDatasetFromRepo(t *testing.T) {
 		t.Errorf("unexpected (-want +got):\n%s", diff)
 	}
 
+	// Get stats as a csv table
+	output = run.MustExec(t, "qri get stats me/my_ds --format csv")
+	expect = "column,type,count,null_count,min,max,mean,unique\nmovie_title,string,18,,,,,18\nduration,numeric,17,,100,183,150.94117647058823,\n\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("unexpected (-want +got):\n%s", diff)
+	}
+
+	// Get a page of the body as an html table
+	output = run.MustExec(t, "qri get body me/my_ds --format html --limit 2 --offset 1")
+	for _, expect := range []string{
+		"<tr><th>movie_title</th><th>duration</th></tr>",
+		"<tr><td>Pirates of the Caribbean: At World&#39;s End </td><td>169</td></tr>",
+		"preview truncated, showing rows 2-3 of 18",
+	} {
+		if !strings.Contains(output, expect) {
+			t.Errorf("expected html body to contain %q. got:\n%s", expect, output)
+		}
+	}
+	if err := run.ExecCommand("qri get meta me/my_ds --format html"); err == nil {
+		t.Error("expected html format of a non-body component to error")
+	}
 }
 
 func TestGetDatasetUsingDscache(t *testing.T) {
@@ -542,3 +568,459 @@ structure:
 		t.Errorf("repsonse mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestGetDefaultSource(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_get_default_source", "get_default_source")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/my_ds")
+
+	if err := run.ExecCommand("qri get --resolve-source local body me/my_ds --format csv"); err != nil {
+		t.Errorf("getting with local source: %s", err)
+	}
+
+	if err := run.ExecCommand("qri get --resolve-source not_a_remote me/my_ds"); err == nil {
+		t.Fatal("expected getting from an unknown source to fail")
+	}
+
+	// rename declares a local source, which the flag doesn't override
+	if err := run.ExecCommand("qri rename --resolve-source not_a_remote me/my_ds me/renamed_ds"); err != nil {
+		t.Errorf("renaming with a default source: %s", err)
+	}
+}
+
+func TestGetBodyColumns(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_get_body_columns", "get_body_columns")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --columnar --body=testdata/movies/body_ten.csv me/my_ds")
+
+	output := run.MustExec(t, "qri get body --columns duration --limit 3 me/my_ds")
+	expect := "[[178],[169],[148]]\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	output = run.MustExec(t, "qri get body --columns duration,movie_title --limit 2 --format csv me/my_ds")
+	expect = "duration,movie_title\n178,Avatar \n169,Pirates of the Caribbean: At World's End \n\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("csv output mismatch (-want +got):\n%s", diff)
+	}
+
+	err := run.ExecCommand("qri get body --columns rating me/my_ds")
+	if expect := `unknown column "rating", available columns: movie_title, duration`; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestGetBodyFixedWidth(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_get_body_fixed_width", "get_body_fixed_width")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/my_ds")
+
+	output := run.MustExec(t, "qri get body --format fixed --widths 12,4 --limit 3 me/my_ds")
+	expect := "Avatar      178 \nPirates of ~169 \nSpectre     148 \n\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	err := run.ExecCommand("qri get body --format fixed --widths 12,4 --overflow error me/my_ds")
+	if expect := `row 2 column 1: value "Pirates of the Caribbean: At World's End " is longer than width 12`; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+
+	err = run.ExecCommand("qri get meta --format fixed me/my_ds")
+	if expect := "can only use --format=fixed when getting body"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+
+	err = run.ExecCommand("qri get body --widths 12,4 me/my_ds")
+	if expect := "can only use --widths flag with --format=fixed"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestGetBodyCSVNulls(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_get_body_csv_nulls", "get_body_csv_nulls")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "get_body_csv_nulls")
+	bodyPath := filepath.Join(tmpDir, "body.json")
+	run.MustWriteFile(t, bodyPath, `[["a",""],["b",null]]`)
+	run.MustExec(t, fmt.Sprintf("qri save --body %s me/nulls", bodyPath))
+
+	output := run.MustExec(t, "qri get body --format csv --csv-nulls me/nulls")
+	if expect := "a,\"\"\nb,\n\n"; output != expect {
+		t.Errorf("output mismatch. want: %q, got: %q", expect, output)
+	}
+
+	output = run.MustExec(t, "qri get body --format csv --null-token NULL me/nulls")
+	if expect := "a,\"\"\nb,NULL\n\n"; output != expect {
+		t.Errorf("output mismatch. want: %q, got: %q", expect, output)
+	}
+
+	err := run.ExecCommand("qri get body --csv-nulls me/nulls")
+	if expect := "can only use --csv-nulls and --null-token flags when getting body with --format=csv"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestGetBodyCSVEncoding(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_get_body_csv_encoding", "get_body_csv_encoding")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "get_body_csv_encoding")
+	bodyPath := filepath.Join(tmpDir, "body.csv")
+	run.MustWriteFile(t, bodyPath, "city,note\nzürich,\"two\nlines\"\noslo,one\n")
+	run.MustExec(t, fmt.Sprintf("qri save --body %s me/cities", bodyPath))
+
+	output := run.MustExec(t, "qri get body --format csv --bom --crlf me/cities")
+	if expect := "\xEF\xBB\xBFcity,note\r\nzürich,\"two\r\nlines\"\r\noslo,one\r\n\n"; output != expect {
+		t.Errorf("output mismatch. want: %q, got: %q", expect, output)
+	}
+
+	err := run.ExecCommand("qri get body --crlf me/cities")
+	if expect := "can only use --bom and --crlf flags when getting body with --format=csv"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+	err = run.ExecCommand("qri get stats --format csv --bom me/cities")
+	if expect := "can only use --bom and --crlf flags when getting body with --format=csv"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestGetBodyNDJSON(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_get_body_ndjson", "get_body_ndjson")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/my_ds")
+
+	output := run.MustExec(t, "qri get body --format ndjson --limit 2 me/my_ds")
+	if expect := "[\"Avatar \",178]\n[\"Pirates of the Caribbean: At World's End \",169]\n"; output != expect {
+		t.Errorf("output mismatch. want: %q, got: %q", expect, output)
+	}
+
+	err := run.ExecCommand("qri get meta --format ndjson me/my_ds")
+	if expect := "can only use --format=ndjson when getting body"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestGetBodyKeys(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_get_body_keys", "get_body_keys")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/my_ds")
+
+	tmpDir := run.MakeTmpDir(t, "get_body_keys")
+	keysPath := filepath.Join(tmpDir, "keys.txt")
+	run.MustWriteFile(t, keysPath, "148\n\n178\n1\n")
+
+	output := run.MustExec(t, fmt.Sprintf("qri get body --keys-file %s --key-column duration me/my_ds", keysPath))
+	expect := `[["Avatar ",178],["Spectre ",148]]` + "\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	output = run.MustExecCombinedOutErr(t, fmt.Sprintf("qri get body --keys-file %s --key-column duration me/my_ds", keysPath))
+	if !strings.Contains(output, "1 keys not found: 1") {
+		t.Errorf("expected output to report missing keys, got: %q", output)
+	}
+
+	err := run.ExecCommand(fmt.Sprintf("qri get body --keys-file %s me/my_ds", keysPath))
+	if expect := "--keys-file and --key-column flags must be used together"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+
+	err = run.ExecCommand(fmt.Sprintf("qri get body --keys-file %s --key-column rating me/my_ds", keysPath))
+	if expect := `unknown column "rating", available columns: movie_title, duration`; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestGetBodyScalar(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_get_body_scalar", "get_body_scalar")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "get_body_scalar")
+	bodyPath := filepath.Join(tmpDir, "body.json")
+	run.MustWriteFile(t, bodyPath, `[[9085000]]`)
+	run.MustExec(t, fmt.Sprintf("qri save --body %s me/total", bodyPath))
+
+	output := run.MustExec(t, "qri get body --scalar me/total")
+	if expect := "9085000\n"; output != expect {
+		t.Errorf("output mismatch. want: %q, got: %q", expect, output)
+	}
+
+	run.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/movies")
+	err := run.ExecCommand("qri get body --scalar me/movies")
+	if expect := "body has more than one row, expected a single value"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+
+	err = run.ExecCommand("qri get meta --scalar me/total")
+	if expect := "can only use --scalar flag when getting body"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestGetBodyTyped(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_get_body_typed", "get_body_typed")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "get_body_typed")
+	dsPath := filepath.Join(tmpDir, "dataset.yaml")
+	run.MustWriteFile(t, dsPath, `name: counts
+structure:
+  format: json
+  schema:
+    type: array
+    items:
+      type: array
+      items:
+      - title: city
+        type: string
+      - title: count
+        type: integer
+      - title: open
+        type: boolean
+`)
+	bodyPath := filepath.Join(tmpDir, "body.json")
+	run.MustWriteFile(t, bodyPath, `[["toronto","12","true"],["chatham","1,000",false],["raleigh",3,""]]`)
+	run.MustExec(t, fmt.Sprintf("qri save --file %s --body %s me/counts", dsPath, bodyPath))
+
+	output := run.MustExec(t, "qri get body --typed me/counts")
+	expect := `[["toronto",12,true],["chatham",null,false],["raleigh",3,null]]` + "\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	output = run.MustExecCombinedOutErr(t, "qri get body --typed me/counts")
+	if !strings.Contains(output, "1 values couldn't be converted to their column type and were set to null") {
+		t.Errorf("expected output to report failed conversions, got: %q", output)
+	}
+
+	err := run.ExecCommand("qri get body --typed --typed-strict me/counts")
+	if expect := `row 1 column "count": can't convert 1,000 to integer`; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+
+	err = run.ExecCommand("qri get body --typed --format csv me/counts")
+	if expect := "can only use --typed with --format=json or --format=yaml"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestGetBodyTimeRange(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_get_body_time_range", "get_body_time_range")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "get_body_time_range")
+	bodyPath := filepath.Join(tmpDir, "body.csv")
+	run.MustWriteFile(t, bodyPath, "date,reading\n2023-12-31,1\n2024-01-01,2\nunknown,3\n2024-03-31T18:00:00Z,4\n2024-04-01,5\n")
+	run.MustExec(t, "qri save --body "+bodyPath+" me/readings")
+
+	output := run.MustExec(t, "qri get body --time-column date --from 2024-01-01 --to 2024-03-31 me/readings")
+	expect := `[["2024-01-01",2],["2024-03-31T18:00:00Z",4]]` + "\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	output = run.MustExec(t, "qri get body --time-column date --from 2024-01-01 --to 2024-03-31 --limit 1 --offset 1 me/readings")
+	expect = `[["2024-03-31T18:00:00Z",4]]` + "\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("paged output mismatch (-want +got):\n%s", diff)
+	}
+
+	output = run.MustExecCombinedOutErr(t, "qri get body --time-column date --to 2024-01-01 me/readings")
+	if !strings.Contains(output, "1 rows with unreadable times were skipped") {
+		t.Errorf("expected output to report skipped rows, got: %q", output)
+	}
+
+	err := run.ExecCommand("qri get body --time-column date --from 2024-01-01 --time-strict me/readings")
+	if expect := `row 2: can't parse "unknown" as a date or time`; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+
+	err = run.ExecCommand("qri get body --from 2024-01-01 me/readings")
+	if expect := "can only use --from, --to and --time-strict flags with --time-column"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+
+	err = run.ExecCommand("qri get body --time-column date --from 2024-04-01 --to 2024-01-01 me/readings")
+	if expect := "start of time range must be before the end"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestGetBodyDecimate(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_get_body_decimate", "get_body_decimate")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "get_body_decimate")
+	bodyPath := filepath.Join(tmpDir, "body.csv")
+	body := &strings.Builder{}
+	body.WriteString("t,reading\n")
+	for i := 0; i < 100; i++ {
+		reading := 1
+		if i == 42 {
+			reading = 50
+		}
+		fmt.Fprintf(body, "%d,%d\n", i, reading)
+	}
+	run.MustWriteFile(t, bodyPath, body.String())
+	run.MustExec(t, "qri save --body "+bodyPath+" me/readings")
+
+	output := run.MustExec(t, "qri get body --decimate 4 me/readings")
+	if diff := cmp.Diff(`[[0,1],[33,1],[66,1],[99,1]]`+"\n", output); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	output = run.MustExec(t, "qri get body --decimate 10 --decimate-column reading me/readings")
+	if !strings.Contains(output, "[42,50]") {
+		t.Errorf("expected decimating by reading to keep the spike, got: %q", output)
+	}
+
+	err := run.ExecCommand("qri get body --decimate-column reading me/readings")
+	if expect := "can only use --decimate-column with --decimate"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+
+	err = run.ExecCommand("qri get body --decimate 1 me/readings")
+	if expect := "decimating needs at least 2 rows to keep the first & last"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestGetBodyTranspose(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_get_body_transpose", "get_body_transpose")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "get_body_transpose")
+	bodyPath := filepath.Join(tmpDir, "body.csv")
+	run.MustWriteFile(t, bodyPath, "city,pop\ntoronto,40000000\nchatham,35000\n")
+	run.MustExec(t, "qri save --body "+bodyPath+" me/cities")
+
+	output := run.MustExec(t, "qri get body --transpose me/cities")
+	if diff := cmp.Diff(`[["city","toronto","chatham"],["pop",40000000,35000]]`+"\n", output); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	err := run.ExecCommand("qri get body --transpose --format csv me/cities")
+	if expect := "can only use --transpose with --format=json or --format=yaml"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+
+	err = run.ExecCommand("qri get body --transpose --decimate 10 me/cities")
+	if expect := "can't use --decimate and --transpose flags together"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+// lineCodec reads & writes bodies with one string value per line
+type lineCodec struct{}
+
+func (lineCodec) Decode(r io.Reader) (interface{}, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	rows := []interface{}{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		rows = append(rows, []interface{}{line})
+	}
+	return rows, nil
+}
+
+func (lineCodec) Encode(w io.Writer, body interface{}) error {
+	for _, row := range body.([]interface{}) {
+		if _, err := fmt.Fprintln(w, row.([]interface{})[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestGetBodyRegisteredFormat(t *testing.T) {
+	if err := base.RegisterBodyFormat("lines", lineCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	defer base.UnregisterBodyFormat("lines")
+
+	run := NewTestRunner(t, "test_peer_get_body_format", "get_body_format")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "get_body_format")
+	bodyPath := filepath.Join(tmpDir, "body.lines")
+	run.MustWriteFile(t, bodyPath, "one\ntwo\nthree\n")
+	run.MustExec(t, "qri save --body="+bodyPath+" me/my_ds")
+
+	output := run.MustExec(t, "qri get structure.format me/my_ds")
+	if expect := "json\n\n"; output != expect {
+		t.Errorf("expected body to be stored as json, got: %q", output)
+	}
+
+	output = run.MustExec(t, "qri get body --format lines me/my_ds")
+	if diff := cmp.Diff("one\ntwo\nthree\n\n", output); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	err := run.ExecCommand("qri get meta --format lines me/my_ds")
+	if expect := "can only use --format=lines when getting body"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestGetMetaDCAT(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_get_meta_dcat", "get_meta_dcat")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --file=testdata/movies/ds_ten.yaml me/my_ds")
+
+	output := run.MustExec(t, "qri get meta --format dcat me/my_ds")
+	expect := `{
+  "@context": {
+    "dcat": "http://www.w3.org/ns/dcat#",
+    "dct": "http://purl.org/dc/terms/",
+    "schema": "http://schema.org/"
+  },
+  "@type": [
+    "dcat:Dataset",
+    "schema:Dataset"
+  ],
+  "dct:title": "example movie data",
+  "schema:name": "example movie data"
+}
+`
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	err := run.ExecCommand("qri get structure --format dcat me/my_ds")
+	if expect := "can only use --format=dcat when getting meta"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestGetStructureSQL(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_get_structure_sql", "get_structure_sql")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --file=testdata/movies/ds_ten.yaml me/my_ds")
+
+	output := run.MustExec(t, "qri get structure --format sql --dialect mysql me/my_ds")
+	expect := "CREATE TABLE `my_ds` (\n  `movie_title` TEXT,\n  `duration` BIGINT\n);\n\n"
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+
+	err := run.ExecCommand("qri get meta --format sql me/my_ds")
+	if expect := "can only use --format=sql when getting structure"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+	err = run.ExecCommand("qri get structure --dialect mysql me/my_ds")
+	if expect := "can only use --dialect flag with --format=sql"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/errors"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewLinkCommand creates a new `qri link` cobra command for linking a
+// directory to a dataset
+func NewLinkCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &LinkOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "link DATASET [DIR]",
+		Short: "link an existing directory to a dataset",
+		Long: `Link connects an existing directory to a dataset without writing any
+component files, for directories that already hold files like meta.json or
+body.csv that belong to the dataset. DIR defaults to the current directory.
+
+Link writes a hidden .qri-ref file to the directory recording the dataset
+version it's linked to. Component files already in the directory are left
+untouched, and any that differ from the latest version of the dataset are
+listed so they can be reviewed before saving.`,
+		Example: `  # Link the current directory to a dataset:
+  $ qri link me/annual_pop .
+
+  # Link another directory:
+  $ qri link me/annual_pop ~/projects/annual_pop`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	return cmd
+}
+
+// LinkOptions encapsulates state for the link command
+type LinkOptions struct {
+	ioes.IOStreams
+
+	Ref string
+	Dir string
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *LinkOptions) Complete(f Factory, args []string) (err error) {
+	o.Dir = "."
+	if len(args) > 0 {
+		o.Ref = args[0]
+	}
+	if len(args) > 1 {
+		o.Dir = args[1]
+	}
+	o.inst, err = f.Instance()
+	return
+}
+
+// Validate checks that all user input is valid
+func (o *LinkOptions) Validate() error {
+	if o.Ref == "" {
+		return errors.New(lib.ErrBadArgs, "please provide a dataset to link, for example:\n    $ qri link me/dataset_name .\nsee `qri link --help` for more details")
+	}
+	return nil
+}
+
+// Run executes the link command
+func (o *LinkOptions) Run() error {
+	p := &lib.LinkParams{
+		Ref: o.Ref,
+		Dir: o.Dir,
+	}
+	res, err := o.inst.WithSource("local").Dataset().Link(context.TODO(), p)
+	if err != nil {
+		return err
+	}
+
+	printSuccess(o.Out, "linked %s to %s", res.Dir, res.Ref)
+	changed := []base.StatusItem{}
+	for _, si := range res.Status {
+		if si.Type != base.STUnmodified {
+			changed = append(changed, si)
+		}
+	}
+	if len(changed) > 0 {
+		printWarning(o.ErrOut, "files in the directory differ from the linked version and were left as-is:")
+		for _, si := range changed {
+			printWarning(o.ErrOut, "  %s: %s", si.Component, si.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLink(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_link", "qri_test_link")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+
+	linkDir := run.MakeTmpDir(t, "link_dir")
+	metaPath := filepath.Join(linkDir, "meta.json")
+	run.MustWriteFile(t, metaPath, `{"title":"my movies"}`)
+
+	out := run.MustExecCombinedOutErr(t, "qri link me/movies "+linkDir)
+	if !strings.Contains(out, "linked "+linkDir+" to test_peer_link/movies@") {
+		t.Errorf("expected link success message, got:\n%s", out)
+	}
+	if !strings.Contains(out, "meta: add") {
+		t.Errorf("expected the meta file to be reported as differing, got:\n%s", out)
+	}
+
+	if _, err := os.Stat(filepath.Join(linkDir, ".qri-ref")); err != nil {
+		t.Errorf("expected linkfile to be written: %s", err)
+	}
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"title":"my movies"}` {
+		t.Errorf("expected meta file to be left untouched, got: %s", data)
+	}
+	if _, err := os.Stat(filepath.Join(linkDir, "body.csv")); !os.IsNotExist(err) {
+		t.Errorf("expected link not to write component files")
+	}
+
+	err = run.ExecCommand("qri link me/movies " + linkDir)
+	expect := `directory "` + linkDir + `" is already linked to a dataset`
+	if err == nil || errorMessage(err) != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+}
@@ -36,6 +36,12 @@ must have ` + "`qri connect`" + ` running in a separate terminal window.`,
   # Show datasets with the substring "new" in their name:
   $ qri list new
 
+  # Show the most recently changed datasets first:
+  $ qri list --sort recent
+
+  # Show the smallest datasets first:
+  $ qri list --sort size --reverse
+
   # To view the list of a peer's datasets...
   # In one terminal window:
   $ qri connect
@@ -62,6 +68,8 @@ must have ` + "`qri connect`" + ` running in a separate terminal window.`,
 	cmd.Flags().StringVar(&o.Username, "user", "", "user whose datasets to list")
 	cmd.MarkFlagCustom("user", "__qri_get_user_flag_suggestions")
 	cmd.Flags().BoolVarP(&o.Raw, "raw", "r", false, "to show raw references")
+	cmd.Flags().StringVar(&o.Sort, "sort", "", "order results by one of [recent|name|size]")
+	cmd.Flags().BoolVar(&o.Reverse, "reverse", false, "reverse the sort order")
 
 	return cmd
 }
@@ -79,6 +87,8 @@ type ListOptions struct {
 	Public          bool
 	ShowNumVersions bool
 	Raw             bool
+	Sort            string
+	Reverse         bool
 
 	inst *lib.Instance
 }
@@ -88,10 +98,45 @@ func (o *ListOptions) Complete(f Factory, args []string) (err error) {
 	if len(args) > 0 {
 		o.Term = args[0]
 	}
+	if _, err = o.orderBy(); err != nil {
+		return err
+	}
 	o.inst, err = f.Instance()
 	return
 }
 
+// listSortOrders maps --sort values to collection orders. recent & size list
+// the newest & largest datasets first
+var listSortOrders = map[string]*params.Order{
+	"recent": {Key: "updated", Direction: params.OrderDESC},
+	"name":   {Key: "name", Direction: params.OrderASC},
+	"size":   {Key: "size", Direction: params.OrderDESC},
+}
+
+// orderBy converts the --sort & --reverse flags to list ordering
+func (o *ListOptions) orderBy() (params.OrderBy, error) {
+	sort := o.Sort
+	if sort == "" {
+		if !o.Reverse {
+			return nil, nil
+		}
+		sort = "name"
+	}
+	order, ok := listSortOrders[sort]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort %q, must be one of [recent|name|size]", o.Sort)
+	}
+	res := &params.Order{Key: order.Key, Direction: order.Direction}
+	if o.Reverse {
+		if res.Direction == params.OrderDESC {
+			res.Direction = params.OrderASC
+		} else {
+			res.Direction = params.OrderDESC
+		}
+	}
+	return params.OrderBy{res}, nil
+}
+
 // Run executes the list command
 func (o *ListOptions) Run() (err error) {
 	ctx := context.TODO()
@@ -105,12 +150,17 @@ func (o *ListOptions) Run() (err error) {
 		return nil
 	}
 
+	orderBy, err := o.orderBy()
+	if err != nil {
+		return err
+	}
 	p := &lib.CollectionListParams{
 		Term:     o.Term,
 		Username: o.Username,
 		List: params.List{
-			Offset: o.Offset,
-			Limit:  o.Limit,
+			OrderBy: orderBy,
+			Offset:  o.Offset,
+			Limit:   o.Limit,
 		},
 		Public: o.Public,
 	}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewLocateCommand creates a `qri locate` subcommand for finding the remotes
+// a dataset can be pulled from
+func NewLocateCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &LocateOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "locate DATASET",
+		Short: "list the remotes a dataset can be pulled from",
+		Long: `Locate asks each configured remote if it has a dataset, without pulling
+anything. Use it to decide where to pull from when more than one remote is
+configured. Remotes that don't respond within --timeout are listed as
+unreachable.`,
+		Example: `  # List configured remotes that have b5/world_bank:
+  $ qri locate b5/world_bank
+
+  # Also check the registry:
+  $ qri locate --registry b5/world_bank`,
+		Annotations: map[string]string{
+			"group": "network",
+		},
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.Registry, "registry", false, "also check the registry")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", lib.DefaultLocateTimeout, "how long to wait for each remote to respond")
+	cmd.Flags().StringVar(&o.Format, "format", "pretty", "output format [pretty|json]")
+
+	return cmd
+}
+
+// LocateOptions encapsulates state for the locate command
+type LocateOptions struct {
+	ioes.IOStreams
+
+	Refs     *RefSelect
+	Registry bool
+	Timeout  time.Duration
+	Format   string
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *LocateOptions) Complete(f Factory, args []string) (err error) {
+	if o.inst, err = f.Instance(); err != nil {
+		return err
+	}
+	o.Refs, err = GetCurrentRefSelect(f, args, 1)
+	return err
+}
+
+// Run executes the locate command
+func (o *LocateOptions) Run() error {
+	p := &lib.LocateParams{
+		Ref:      o.Refs.Ref(),
+		Registry: o.Registry,
+		Timeout:  o.Timeout,
+	}
+	res, err := o.inst.Remote().Locate(context.TODO(), p)
+	if err != nil {
+		return err
+	}
+
+	switch o.Format {
+	case "json":
+		data, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		printInfo(o.Out, string(data))
+		return nil
+	case "pretty":
+	default:
+		return fmt.Errorf("unknown format %q, must be pretty or json", o.Format)
+	}
+
+	w := tabwriter.NewWriter(o.Out, 0, 0, 2, ' ', 0)
+	for _, loc := range res {
+		switch {
+		case loc.Available:
+			fmt.Fprintf(w, "%s\tavailable\t%s\n", loc.Remote, loc.Path)
+		case loc.Error != "":
+			fmt.Fprintf(w, "%s\tunreachable\t%s\n", loc.Remote, loc.Error)
+		default:
+			fmt.Fprintf(w, "%s\tnot found\t\n", loc.Remote)
+		}
+	}
+	return w.Flush()
+}
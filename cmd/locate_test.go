@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/dsref"
+)
+
+func TestLocate(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_locate", "qri_test_locate")
+	defer run.Delete()
+
+	has := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"username":"test_peer_locate","name":"one_ds","path":"/ipfs/QmExample"}`))
+	}))
+	defer has.Close()
+	missing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(dsref.ErrRefNotFound.Error()))
+	}))
+	defer missing.Close()
+
+	run.RepoRoot.GetConfig().Remotes = &config.Remotes{
+		"has":     has.URL,
+		"missing": missing.URL,
+		"offline": "http://127.0.0.1:1",
+	}
+	if err := run.RepoRoot.WriteConfigFile(); err != nil {
+		t.Fatal(err)
+	}
+
+	output := run.MustExec(t, "qri locate me/one_ds")
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a line per remote, got: %q", output)
+	}
+	if expect := "has      available    /ipfs/QmExample"; lines[0] != expect {
+		t.Errorf("line mismatch. want: %q, got: %q", expect, lines[0])
+	}
+	if expect := "missing  not found"; strings.TrimSpace(lines[1]) != expect {
+		t.Errorf("line mismatch. want: %q, got: %q", expect, lines[1])
+	}
+	if !strings.HasPrefix(lines[2], "offline  unreachable  ") {
+		t.Errorf("expected offline remote to be unreachable, got: %q", lines[2])
+	}
+
+	if err := run.ExecCommand("qri locate --format xml me/one_ds"); err == nil {
+		t.Error("expected unknown format to error")
+	}
+}
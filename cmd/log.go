@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qri/base/params"
@@ -42,7 +43,14 @@ on the network at a remote.
   $ qri log ramfox/league_stats
 	
   # Show log for a dataset chriswhong/nyc_parking_tickets on a remote named "nycdatacollection"
-  $ qri log chriswhong/nyc_parking_tickets --source nycdatacollection`,
+  $ qri log chriswhong/nyc_parking_tickets --source nycdatacollection
+
+  # Write the log of b5/precip as an Atom feed, for subscribing in a feed reader
+  $ qri log b5/precip --format atom -o precip.atom
+
+  # Show the log of b5/precip including versions that were deleted, for
+  # auditing what was removed & when
+  $ qri log b5/precip --include-deleted`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -55,12 +63,15 @@ on the network at a remote.
 		},
 	}
 
-	// cmd.Flags().StringVarP(&o.Format, "format", "f", "", "set output format [json]")
+	cmd.Flags().StringVarP(&o.Format, "format", "f", "", "set output format [json, atom]")
+	cmd.Flags().StringVarP(&o.Outfile, "outfile", "o", "", "file to write json or atom output to")
 	cmd.Flags().IntVar(&o.Offset, "offset", 0, "skip this number of records from the results, default 0")
 	cmd.Flags().IntVar(&o.Limit, "limit", 25, "size of results, default 25")
 	cmd.Flags().StringVarP(&o.Source, "source", "", "", "name of source to fetch from, disables local actions. `registry` will search the default qri registry")
 	cmd.Flags().BoolVarP(&o.Local, "local", "l", false, "only fetch local logs, disables network actions")
 	cmd.Flags().BoolVarP(&o.Pull, "pull", "p", false, "fetch the latest logs from the network")
+	cmd.Flags().BoolVar(&o.ShowRows, "show-rows", false, "show the number of body rows in each version")
+	cmd.Flags().BoolVar(&o.IncludeDeleted, "include-deleted", false, "show versions deleted from history, marked as deleted")
 
 	return cmd
 }
@@ -75,6 +86,11 @@ type LogOptions struct {
 	Local  bool
 	Pull   bool
 
+	ShowRows       bool
+	IncludeDeleted bool
+	Format         string
+	Outfile        string
+
 	// remote fetching specific flags
 	Source     string
 	Unfetch    bool
@@ -94,6 +110,15 @@ func (o *LogOptions) Complete(f Factory, args []string) (err error) {
 		return errors.New(err, "cannot use 'local' flag with either the 'source' or 'pull' flags")
 	}
 
+	switch o.Format {
+	case "", "json", "atom":
+	default:
+		return fmt.Errorf("unknown format %q, must be json or atom", o.Format)
+	}
+	if o.Outfile != "" && o.Format == "" {
+		return fmt.Errorf("can only use --outfile flag with --format=json or --format=atom")
+	}
+
 	if o.Refs, err = GetCurrentRefSelect(f, args, AnyNumberOfReferences); err != nil {
 		if err == repo.ErrEmptyRef {
 			return errors.New(err, "please provide a dataset reference")
@@ -108,26 +133,55 @@ func (o *LogOptions) Run() error {
 
 	ctx := context.TODO()
 	p := &lib.ActivityParams{
-		Ref:  o.Refs.Ref(),
-		Pull: o.Pull,
+		Ref:            o.Refs.Ref(),
+		Pull:           o.Pull,
+		IncludeDeleted: o.IncludeDeleted,
 		List: params.List{
 			Offset: o.Offset,
 			Limit:  o.Limit,
 		},
 	}
 
-	res, err := o.Instance.WithSource(o.Source).Dataset().Activity(ctx, p)
-	if err != nil {
-		return err
+	var data []byte
+	switch o.Format {
+	case "atom":
+		res, err := o.Instance.WithSource(o.Source).Dataset().ActivityFeed(ctx, p)
+		if err != nil {
+			return err
+		}
+		data = res
+	default:
+		res, err := o.Instance.WithSource(o.Source).Dataset().Activity(ctx, p)
+		if err != nil {
+			return err
+		}
+		if o.Format == "" {
+			makeItemsAndPrint(res, o.Out, o.Offset, o.ShowRows)
+			return nil
+		}
+		if data, err = json.MarshalIndent(res, "", "  "); err != nil {
+			return err
+		}
 	}
 
-	makeItemsAndPrint(res, o.Out, o.Offset)
+	if o.Outfile != "" {
+		if err := ioutil.WriteFile(o.Outfile, data, 0644); err != nil {
+			return err
+		}
+		printSuccess(o.ErrOut, "wrote to file %q", o.Outfile)
+		return nil
+	}
+	printInfo(o.Out, string(data))
 	return nil
 }
 
-func makeItemsAndPrint(refs []dsref.VersionInfo, out io.Writer, offset int) {
+func makeItemsAndPrint(refs []dsref.VersionInfo, out io.Writer, offset int, showRows bool) {
 	items := make([]fmt.Stringer, len(refs))
 	for i, r := range refs {
+		if showRows {
+			items[i] = dslogItemWithRowsStringer(r)
+			continue
+		}
 		items[i] = dslogItemStringer(r)
 	}
 
@@ -168,6 +222,49 @@ The logbook command shows entries for a dataset, from newest to oldest.`,
 	cmd.Flags().BoolVar(&o.Raw, "raw", false, "full logbook in raw JSON format. overrides all other flags")
 	cmd.Flags().BoolVar(&o.Summary, "summary", false, "print one oplog per line in the format 'MODEL ID OPCOUNT NAME'. overrides all other flags")
 
+	graph := &cobra.Command{
+		Use:   "graph DATASET",
+		Short: "print the oplog of a dataset as a graph",
+		Long: `Graph prints the oplog of a dataset as a Graphviz DOT graph, linking the
+user, dataset, and branch logs and chaining the ops of each log in the order
+they were written. Render the output with Graphviz to see how init, commit,
+push, and remove ops build up a history.`,
+		Example: `  # Render the oplog of bob/precip as an svg image:
+  $ qri logbook graph bob/precip --format dot | dot -Tsvg > precip_oplog.svg`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Graph()
+		},
+	}
+	graph.Flags().StringVar(&o.Format, "format", "dot", "graph format. only dot is supported")
+	cmd.AddCommand(graph)
+
+	doctor := &cobra.Command{
+		Use:   "doctor",
+		Short: "find and remove stranded dataset logs",
+		Long: `Doctor checks your logbook for datasets that don't resolve to a saved
+version. A failed save can leave behind a dataset log with no commits, or a log
+whose latest version isn't in the store. Doctor lists these stranded logs, and
+removes them when run with --fix.`,
+		Example: `  # List stranded dataset logs:
+  $ qri logbook doctor
+
+  # Remove stranded dataset logs:
+  $ qri logbook doctor --fix`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if o.Instance, err = f.Instance(); err != nil {
+				return err
+			}
+			return o.Doctor()
+		},
+	}
+	doctor.Flags().BoolVar(&o.Fix, "fix", false, "remove stranded dataset logs")
+	cmd.AddCommand(doctor)
+
 	return cmd
 }
 
@@ -179,6 +276,8 @@ type LogbookOptions struct {
 	Limit        int
 	Refs         *RefSelect
 	Raw, Summary bool
+	Format       string
+	Fix          bool
 
 	Instance *lib.Instance
 }
@@ -264,3 +363,45 @@ func (o *LogbookOptions) LogbookSummary() error {
 	printToPager(o.Out, bytes.NewBufferString(*res))
 	return nil
 }
+
+// Graph prints the oplog of a dataset as a graph
+func (o *LogbookOptions) Graph() error {
+	ctx := context.TODO()
+	p := &lib.LogbookGraphParams{
+		Ref:    o.Refs.Ref(),
+		Format: o.Format,
+	}
+	res, err := o.Instance.Log().LogbookGraph(ctx, p)
+	if err != nil {
+		if err == repo.ErrEmptyRef {
+			return errors.New(err, "please provide a dataset reference")
+		}
+		return err
+	}
+
+	fmt.Fprint(o.Out, *res)
+	return nil
+}
+
+// Doctor lists stranded dataset logs, removing them if Fix is set
+func (o *LogbookOptions) Doctor() error {
+	ctx := context.TODO()
+	res, err := o.Instance.Log().LogbookDoctor(ctx, &lib.LogbookDoctorParams{Fix: o.Fix})
+	if err != nil {
+		return err
+	}
+
+	if len(res) == 0 {
+		printSuccess(o.ErrOut, "no stranded dataset logs found")
+		return nil
+	}
+	for _, s := range res {
+		fmt.Fprintf(o.Out, "%s/%s\t%s\n", s.Ref.Username, s.Ref.Name, s.Reason)
+	}
+	if o.Fix {
+		printSuccess(o.ErrOut, "removed %d stranded dataset logs", len(res))
+	} else {
+		printInfo(o.ErrOut, "run with --fix to remove %d stranded dataset logs", len(res))
+	}
+	return nil
+}
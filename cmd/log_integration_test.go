@@ -78,6 +78,40 @@ func TestLogAndDeletes(t *testing.T) {
 		t.Errorf("qri log (-want +got):\n%s", diff)
 	}
 
+	// Log with row counts should show the number of entries in each version
+	err = run.ExecCommand("qri log --show-rows me/log_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output = run.GetCommandOutput()
+	expect = dstest.Template(t, `1   Commit:  {{ .path1 }}
+    Date:    Sun Dec 31 20:02:01 EST 2000
+    Storage: local
+    Size:    137 B
+    Rows:    4
+
+    body added row 2 and added row 3
+    body:
+    	added row 2
+    	added row 3
+
+2   Commit:  {{ .path2 }}
+    Date:    Sun Dec 31 20:01:01 EST 2000
+    Storage: local
+    Size:    79 B
+    Rows:    2
+
+    created dataset from body_two.json
+
+`, map[string]string{
+		"path1": "/ipfs/QmZfKcJ9yAqwaYnVo9fqczLP11ScUtQY4tcQVQcMfp2o7Y",
+		"path2": "/ipfs/QmfU8fcG7DjpL94JvDvAvzo2zkWWXx2Lj8kiq3KhB7Kvat",
+	})
+
+	if diff := cmpTextLines(expect, output); diff != "" {
+		t.Errorf("qri log --show-rows (-want +got):\n%s", diff)
+	}
+
 	// Save anoter dataset version
 	err = run.ExecCommand("qri remove --revisions=1 me/log_test")
 	if err != nil {
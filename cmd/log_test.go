@@ -1,11 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/qri-io/dataset/dstest"
+	"github.com/qri-io/qri/dsref"
 )
 
 func TestLogbookCommand(t *testing.T) {
@@ -42,3 +48,108 @@ func TestLogbookCommand(t *testing.T) {
 		t.Errorf("unexpected (-want +got):\n%s", diff)
 	}
 }
+
+func TestLogbookGraph(t *testing.T) {
+	r := NewTestRunner(t, "test_peer_logbook_graph", "qri_test_logbook_graph")
+	defer r.Delete()
+
+	r.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/test_movies")
+
+	output := r.MustExec(t, "qri logbook graph me/test_movies --format dot")
+	for _, want := range []string{"digraph oplog {", `label="branch\nmain"`, "created dataset from body_ten.csv"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected graph to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	err := r.ExecCommand("qri logbook graph me/test_movies --format png")
+	if expect := `unsupported graph format "png". only dot is supported`; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestLogbookDoctor(t *testing.T) {
+	r := NewTestRunner(t, "test_peer_logbook_doctor", "qri_test_logbook_doctor")
+	defer r.Delete()
+
+	r.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/test_movies")
+
+	output := r.MustExecCombinedOutErr(t, "qri logbook doctor")
+	if expect := "no stranded dataset logs found"; !strings.Contains(output, expect) {
+		t.Errorf("expected output to contain %q, got:\n%s", expect, output)
+	}
+
+	err := r.ExecCommand("qri logbook doctor me/test_movies")
+	if expect := `unknown command "me/test_movies" for "qri logbook doctor"`; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestLogFeed(t *testing.T) {
+	r := NewTestRunner(t, "test_peer_log_feed", "qri_test_log_feed")
+	defer r.Delete()
+
+	r.MustExec(t, "qri save --file=testdata/movies/ds_ten.yaml me/test_movies")
+
+	output := r.MustExec(t, "qri log me/test_movies --format atom")
+	for _, expect := range []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom">`,
+		"<title>example movie data</title>",
+		"<id>urn:qri:test_peer_log_feed/test_movies</id>",
+		"<entry>",
+	} {
+		if !strings.Contains(output, expect) {
+			t.Errorf("expected feed to contain %q, got:\n%s", expect, output)
+		}
+	}
+
+	err := r.ExecCommand("qri log me/test_movies --format rss")
+	if expect := `unknown format "rss", must be json or atom`; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestLogIncludeDeleted(t *testing.T) {
+	r := NewTestRunner(t, "test_peer_log_include_deleted", "qri_test_log_include_deleted")
+	defer r.Delete()
+
+	r.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/test_movies")
+	r.MustExec(t, "qri save --body=testdata/movies/body_twenty.csv me/test_movies")
+	r.MustExec(t, "qri remove --revisions 1 me/test_movies")
+
+	tmpDir := r.MakeTmpDir(t, "log_include_deleted")
+	versions := func(args string) []dsref.VersionInfo {
+		t.Helper()
+		path := filepath.Join(tmpDir, "log.json")
+		r.MustExec(t, strings.TrimSpace(fmt.Sprintf("qri log me/test_movies --format json -o %s %s", path, args)))
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vis := []dsref.VersionInfo{}
+		if err := json.Unmarshal(data, &vis); err != nil {
+			t.Fatal(err)
+		}
+		return vis
+	}
+
+	if vis := versions(""); len(vis) != 1 {
+		t.Errorf("expected log to leave out the deleted version, got %d versions", len(vis))
+	}
+
+	vis := versions("--include-deleted")
+	if len(vis) != 2 {
+		t.Fatalf("expected log to list 2 versions, got %d", len(vis))
+	}
+	if !vis[0].Deleted || vis[0].DeleteTime == nil {
+		t.Errorf("expected newest version to be marked deleted with a delete time, got: %#v", vis[0])
+	}
+	if vis[1].Deleted {
+		t.Errorf("expected oldest version not to be marked deleted")
+	}
+
+	output := r.MustExec(t, "qri log me/test_movies --include-deleted")
+	if !strings.Contains(output, "deleted") {
+		t.Errorf("expected log output to mark the deleted version, got:\n%s", output)
+	}
+}
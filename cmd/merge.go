@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/errors"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewMergeCommand creates a new `qri merge` cobra command for combining the
+// bodies of two datasets into a new dataset
+func NewMergeCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &MergeOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "merge FIRST SECOND NEW_NAME",
+		Short: "combine the bodies of two datasets into a new dataset",
+		Long: `Merge saves a new dataset whose body is the rows of one dataset followed by
+the rows of another, like appending the data for one period or partition to
+the data for the one before it.
+
+The schemas of both datasets must match: the same columns in the same order,
+with the same types. Merge lists every column that doesn't match instead of
+saving. The new dataset keeps the structure of the first dataset and starts
+a brand new history.
+
+Use --dedup-key to drop rows whose value in a column repeats a row that came
+before it, keeping the first.`,
+		Example: `  # Combine two quarters of sales into one dataset:
+  $ qri merge a/sales_q1 a/sales_q2 me/sales_h1
+
+  # Drop rows of the second quarter with an order id already in the first:
+  $ qri merge a/sales_q1 a/sales_q2 me/sales_h1 --dedup-key order_id`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().StringVar(&o.DedupKey, "dedup-key", "", "column identifying a row, rows repeating an earlier key are dropped")
+
+	return cmd
+}
+
+// MergeOptions encapsulates state for the merge command
+type MergeOptions struct {
+	ioes.IOStreams
+
+	First    string
+	Second   string
+	Next     string
+	DedupKey string
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *MergeOptions) Complete(f Factory, args []string) (err error) {
+	if len(args) == 3 {
+		o.First = args[0]
+		o.Second = args[1]
+		o.Next = args[2]
+	}
+	o.inst, err = f.Instance()
+	return
+}
+
+// Validate checks that all user input is valid
+func (o *MergeOptions) Validate() error {
+	if o.First == "" || o.Second == "" || o.Next == "" {
+		return errors.New(lib.ErrBadArgs, "please provide the two datasets to merge and a name for the result, for example:\n    $ qri merge a/sales_q1 a/sales_q2 me/sales_h1\nsee `qri merge --help` for more details")
+	}
+	return nil
+}
+
+// Run executes the merge command
+func (o *MergeOptions) Run() error {
+	p := &lib.MergeParams{
+		First:    o.First,
+		Second:   o.Second,
+		Next:     o.Next,
+		DedupKey: o.DedupKey,
+	}
+	ctx := context.TODO()
+	res, err := o.inst.WithSource("local").Dataset().Merge(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	if res.DroppedRows > 0 {
+		printInfo(o.Out, "dropped %d rows with a repeated %s", res.DroppedRows, o.DedupKey)
+	}
+	printSuccess(o.Out, "merged %s and %s into %s/%s", o.First, o.Second, res.Dataset.Peername, res.Dataset.Name)
+	return nil
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMerge(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_merge", "qri_test_merge")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "merge")
+	q1Path := filepath.Join(tmpDir, "q1.csv")
+	run.MustWriteFile(t, q1Path, "order_id,total\n1,10.5\n2,4.25\n")
+	q2Path := filepath.Join(tmpDir, "q2.csv")
+	run.MustWriteFile(t, q2Path, "order_id,total\n2,4.25\n3,8.75\n")
+	run.MustExec(t, "qri save --body "+q1Path+" me/sales_q1")
+	run.MustExec(t, "qri save --body "+q2Path+" me/sales_q2")
+
+	output := run.MustExec(t, "qri merge me/sales_q1 me/sales_q2 me/sales_h1")
+	if !strings.Contains(output, "merged me/sales_q1 and me/sales_q2 into test_peer_merge/sales_h1") {
+		t.Errorf("unexpected output: %q", output)
+	}
+	output = run.MustExec(t, "qri get body me/sales_h1")
+	if diff := cmp.Diff(`[[1,10.5],[2,4.25],[2,4.25],[3,8.75]]`+"\n", output); diff != "" {
+		t.Errorf("body mismatch (-want +got):\n%s", diff)
+	}
+
+	output = run.MustExec(t, "qri merge --dedup-key order_id me/sales_q1 me/sales_q2 me/sales_h1_unique")
+	if !strings.Contains(output, "dropped 1 rows with a repeated order_id") {
+		t.Errorf("expected output to report dropped rows, got: %q", output)
+	}
+	output = run.MustExec(t, "qri get body me/sales_h1_unique")
+	if diff := cmp.Diff(`[[1,10.5],[2,4.25],[3,8.75]]`+"\n", output); diff != "" {
+		t.Errorf("body mismatch (-want +got):\n%s", diff)
+	}
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+	err := run.ExecCommand("qri merge me/sales_q1 me/movies me/mixed")
+	expect := `can't merge test_peer_merge/sales_q1 and test_peer_merge/movies, schemas don't match:
+  column 0 is "order_id" in test_peer_merge/sales_q1, "movie_title" in test_peer_merge/movies
+  column 1 is "total" in test_peer_merge/sales_q1, "duration" in test_peer_merge/movies`
+	if diff := cmp.Diff(expect, errorMessage(err)); diff != "" {
+		t.Errorf("error mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := run.ExecCommand("qri merge me/sales_q1 me/sales_q2"); err == nil {
+		t.Error("expected merge without a destination to error")
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewMetaCommand creates a new `qri meta` command for editing the meta
+// component of a dataset
+func NewMetaCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &MetaOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "meta",
+		Short: "change the metadata of a dataset",
+		Long: `Meta commands make targeted changes to the meta component of a dataset,
+saving each change as a new version.`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+	}
+
+	set := &cobra.Command{
+		Use:   "set DATASET FIELD VALUE",
+		Short: "set a single meta field",
+		Long: `Set changes one field of the meta component, keeping all other meta fields
+as they are. FIELD is a dot-separated path, like "title" or "license.type".
+VALUE is read as JSON if possible and as plain text otherwise, so lists like
+keywords can be set with a JSON array. Setting a field that meta doesn't
+define is an error.`,
+		Example: `  # set the title of me/annual_pop:
+  $ qri meta set me/annual_pop title "Annual Population"
+
+  # replace the keywords of me/annual_pop:
+  $ qri meta set me/annual_pop keywords '["population","census"]'`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args[:1]); err != nil {
+				return err
+			}
+			return o.Set(args[1], args[2])
+		},
+	}
+
+	cmd.AddCommand(set)
+	return cmd
+}
+
+// MetaOptions encapsulates state for the meta command
+type MetaOptions struct {
+	ioes.IOStreams
+
+	Refs *RefSelect
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *MetaOptions) Complete(f Factory, args []string) (err error) {
+	if o.inst, err = f.Instance(); err != nil {
+		return err
+	}
+	o.Refs, err = GetCurrentRefSelect(f, args, 1)
+	return err
+}
+
+// Set executes the meta set command
+func (o *MetaOptions) Set(field, value string) error {
+	ctx := context.TODO()
+	p := &lib.SetMetaParams{
+		Ref:   o.Refs.Ref(),
+		Field: field,
+		Value: value,
+	}
+	res, err := o.inst.Dataset().SetMeta(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	ref := dsref.ConvertDatasetToVersionInfo(res).SimpleRef()
+	printSuccess(o.ErrOut, "set meta %s: %s", field, refString(ref))
+	return nil
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMetaSet(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_meta_set", "qri_test_meta_set")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+
+	output := run.MustExecCombinedOutErr(t, "qri meta set me/movies title Movies")
+	if !strings.Contains(output, "set meta title") {
+		t.Errorf("expected output to report the change, got: %q", output)
+	}
+	run.MustExec(t, `qri meta set me/movies keywords ["film","runtime"]`)
+
+	output = run.MustExec(t, "qri get meta.title me/movies")
+	if diff := cmp.Diff("Movies\n\n", output); diff != "" {
+		t.Errorf("title mismatch (-want +got):\n%s", diff)
+	}
+	output = run.MustExec(t, "qri get meta.keywords me/movies")
+	if diff := cmp.Diff("- film\n- runtime\n\n", output); diff != "" {
+		t.Errorf("keywords mismatch (-want +got):\n%s", diff)
+	}
+
+	err := run.ExecCommand("qri meta set me/movies colour blue")
+	if err == nil {
+		t.Fatal("expected setting an unknown meta field to error")
+	}
+	if diff := cmp.Diff(`invalid meta field "colour"`, errorMessage(err)); diff != "" {
+		t.Errorf("error mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewOpsCommand creates a `qri ops` command for working with operations
+// running in the background
+func NewOpsCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &OpsOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "ops",
+		Short: "list & cancel background operations",
+		Long: `Ops commands show & control work qri is doing in the background, like
+pushes that run after a save, transform applies that don't wait for their
+result, and workflow runs. Operations belong to the qri instance that started
+them, run ops commands while ` + "`qri connect`" + ` is running to work with the
+operations of the running instance.
+
+Finished operations are listed for a short time before they're dropped.`,
+		Annotations: map[string]string{
+			"group": "other",
+		},
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "list background operations",
+		Example: `  # show operations:
+  $ qri ops list`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f); err != nil {
+				return err
+			}
+			return o.List()
+		},
+	}
+
+	cancel := &cobra.Command{
+		Use:   "cancel ID",
+		Short: "cancel a background operation",
+		Example: `  # cancel an operation, using an id from qri ops list:
+  $ qri ops cancel 0fe75ea6-9f21-44a7-a3a4-0e8ef6b0e7a6`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f); err != nil {
+				return err
+			}
+			return o.Cancel(args[0])
+		},
+	}
+
+	cmd.AddCommand(list, cancel)
+	return cmd
+}
+
+// OpsOptions encapsulates state for the ops command
+type OpsOptions struct {
+	ioes.IOStreams
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *OpsOptions) Complete(f Factory) (err error) {
+	o.inst, err = f.Instance()
+	return err
+}
+
+// List executes the ops list command
+func (o *OpsOptions) List() error {
+	ops, err := o.inst.Ops().List(context.TODO(), &lib.ListOpsParams{})
+	if err != nil {
+		return err
+	}
+	if len(ops) == 0 {
+		printInfo(o.ErrOut, "no operations")
+		return nil
+	}
+
+	data := make([][]string, len(ops))
+	for i, op := range ops {
+		data[i] = []string{op.ID, op.Type, op.Ref, op.Status, op.Progress, op.Started.Format(time.RFC3339)}
+	}
+	renderTable(o.Out, []string{"id", "type", "dataset", "status", "progress", "started"}, data)
+	return nil
+}
+
+// Cancel executes the ops cancel command
+func (o *OpsOptions) Cancel(id string) error {
+	if err := o.inst.Ops().Cancel(context.TODO(), &lib.CancelOpParams{ID: id}); err != nil {
+		return err
+	}
+	printSuccess(o.ErrOut, "canceled operation %s", id)
+	return nil
+}
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOps(t *testing.T) {
+	r := NewTestRunner(t, "test_peer_ops", "qri_test_ops")
+	defer r.Delete()
+
+	output := r.MustExecCombinedOutErr(t, "qri ops list")
+	if expect := "no operations"; !strings.Contains(output, expect) {
+		t.Errorf("expected output to contain %q, got:\n%s", expect, output)
+	}
+
+	err := r.ExecCommand("qri ops cancel not_an_op")
+	if expect := `operation not found: "not_an_op"`; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
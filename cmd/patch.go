@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewPatchCommand creates a new `qri patch` cobra command for applying
+// patches made with `qri diff --format patch`
+func NewPatchCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &PatchOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "patch",
+		Short: "apply changes made to one dataset to another",
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+	}
+
+	apply := &cobra.Command{
+		Use:   "apply DATASET PATCH_FILE",
+		Short: "apply a patch to a dataset as a new version",
+		Long: `Apply saves a new version of a dataset with the changes of a patch file
+made by 'qri diff --format patch'. Patches record meta & structure changes by
+field, and body changes by row.
+
+A change conflicts when the dataset doesn't have the value the patch was made
+against. Conflicting changes are skipped and listed, all other changes are
+saved.`,
+		Example: `  # Propose changes to a dataset you don't control:
+  $ qri diff --format patch --key id me/my_fork > changes.json
+  $ qri patch apply b5/world_bank changes.json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Apply()
+		},
+	}
+	apply.Flags().StringVarP(&o.Title, "title", "t", "", "title of commit message for save")
+	cmd.AddCommand(apply)
+
+	return cmd
+}
+
+// PatchOptions encapsulates state for the patch command
+type PatchOptions struct {
+	ioes.IOStreams
+
+	Refs      *RefSelect
+	PatchPath string
+	Title     string
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *PatchOptions) Complete(f Factory, args []string) (err error) {
+	if o.inst, err = f.Instance(); err != nil {
+		return err
+	}
+	o.PatchPath = args[1]
+	o.Refs, err = GetCurrentRefSelect(f, args[:1], 1)
+	return err
+}
+
+// Apply applies a patch file to a dataset
+func (o *PatchOptions) Apply() error {
+	p := &lib.ApplyPatchParams{
+		Ref:       o.Refs.Ref(),
+		PatchPath: o.PatchPath,
+		Title:     o.Title,
+	}
+	res, err := o.inst.Dataset().ApplyPatch(context.TODO(), p)
+	if err != nil {
+		return err
+	}
+
+	if !res.BaseMatch {
+		printWarning(o.ErrOut, "patch was made against a different version of the dataset, checked changes for conflicts")
+	}
+	for _, c := range res.Conflicts {
+		printWarning(o.ErrOut, "skipped conflicting change: %s", c)
+	}
+	ds := res.Dataset
+	printSuccess(o.Out, "applied patch to %s/%s@%s", ds.Peername, ds.Name, ds.Path)
+	return nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPatchApply(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_patch", "qri_test_patch")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "patch_apply")
+	run.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/movies")
+	run.MustExec(t, "qri save --body=testdata/movies/body_ten.csv me/fork")
+
+	edited := filepath.Join(tmpDir, "edited.csv")
+	run.MustWriteFile(t, edited, "movie_title,duration\nAvatar,162\nTangled,100\n")
+	run.MustExec(t, "qri save --body="+edited+" me/movies")
+
+	patch := run.MustExec(t, "qri diff --format patch --key movie_title me/movies")
+	if !strings.Contains(patch, `"qri": "pt:0"`) {
+		t.Fatalf("expected patch output, got:\n%s", patch)
+	}
+	patchPath := filepath.Join(tmpDir, "patch.json")
+	run.MustWriteFile(t, patchPath, patch)
+
+	output := run.MustExecCombinedOutErr(t, "qri patch apply me/fork "+patchPath)
+	if !strings.Contains(output, "applied patch to test_peer_patch/fork") {
+		t.Errorf("expected success message, got:\n%s", output)
+	}
+	if !strings.Contains(output, "patch was made against a different version") {
+		t.Errorf("expected base mismatch warning, got:\n%s", output)
+	}
+
+	body := run.MustExec(t, "qri get body me/fork")
+	if expect := `[["Avatar",162],["Tangled",100]]`; strings.TrimSpace(body) != expect {
+		t.Errorf("patched body mismatch. want: %s, got: %s", expect, body)
+	}
+
+	if err := run.ExecCommand("qri patch apply me/fork " + patchPath); err == nil {
+		t.Error("expected applying a patch a second time to error")
+	}
+}
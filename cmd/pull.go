@@ -24,7 +24,13 @@ dataset version(s). By default pull fetches the latest version of a dataset.
   $ qri pull b5/world_bank_population
 
   # pull a specific version from a remote by hash
-  $ qri pull ramfox b5/world_bank_population@/ipfs/QmFoo...`,
+  $ qri pull ramfox b5/world_bank_population@/ipfs/QmFoo...
+
+  # fetch only metadata & structure, the body is pulled when it's first read
+  $ qri pull --metadata-only b5/world_bank_population
+
+  # finish an interrupted pull, skipping blocks that were already fetched
+  $ qri pull --resume b5/world_bank_population`,
 		Annotations: map[string]string{
 			"group": "network",
 		},
@@ -40,6 +46,8 @@ dataset version(s). By default pull fetches the latest version of a dataset.
 	cmd.Flags().StringVar(&o.Source, "source", "", "location to pull from")
 	cmd.MarkFlagFilename("link")
 	cmd.Flags().BoolVar(&o.LogsOnly, "logs-only", false, "only fetch logs, skipping HEAD data")
+	cmd.Flags().BoolVar(&o.MetadataOnly, "metadata-only", false, "fetch all version data except the body")
+	cmd.Flags().BoolVar(&o.Resume, "resume", false, "only fetch blocks that aren't already stored locally")
 
 	return cmd
 }
@@ -47,9 +55,11 @@ dataset version(s). By default pull fetches the latest version of a dataset.
 // PullOptions encapsulates state for the add command
 type PullOptions struct {
 	ioes.IOStreams
-	LinkDir  string
-	Source   string
-	LogsOnly bool
+	LinkDir      string
+	Source       string
+	LogsOnly     bool
+	MetadataOnly bool
+	Resume       bool
 
 	inst *lib.Instance
 }
@@ -74,8 +84,10 @@ func (o *PullOptions) Run(args []string) error {
 
 	for _, arg := range args {
 		p := &lib.PullParams{
-			Ref:      arg,
-			LogsOnly: o.LogsOnly,
+			Ref:          arg,
+			LogsOnly:     o.LogsOnly,
+			MetadataOnly: o.MetadataOnly,
+			Resume:       o.Resume,
 		}
 
 		res, err := o.inst.WithSource(o.Source).Dataset().Pull(ctx, p)
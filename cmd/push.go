@@ -24,7 +24,10 @@ If no remote is specified, qri pushes to the registry.`,
   $ qri push me/dataset
 
   # push a specific version of a dataset to the registry:
-  $ qri push me/dataset@/ipfs/QmHashOfVersion`,
+  $ qri push me/dataset@/ipfs/QmHashOfVersion
+
+  # confirm the remote received every block before marking the dataset published:
+  $ qri push --verify me/dataset`,
 		Annotations: map[string]string{
 			"group": "network",
 		},
@@ -39,6 +42,7 @@ If no remote is specified, qri pushes to the registry.`,
 
 	cmd.Flags().BoolVarP(&o.Logs, "logs", "", false, "send only dataset history")
 	cmd.Flags().StringVarP(&o.Remote, "remote", "", "", "name of remote to push to")
+	cmd.Flags().BoolVar(&o.Verify, "verify", false, "check the remote has every block of the pushed version before marking it published")
 
 	return cmd
 }
@@ -50,6 +54,7 @@ type PushOptions struct {
 	Refs   *RefSelect
 	Logs   bool
 	Remote string
+	Verify bool
 
 	inst *lib.Instance
 }
@@ -72,6 +77,7 @@ func (o *PushOptions) Run() error {
 		p := lib.PushParams{
 			Ref:    ref,
 			Remote: o.Remote,
+			Verify: o.Verify,
 		}
 
 		// Though push is pushing to a remote, it has to resolve datasets
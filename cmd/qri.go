@@ -47,31 +47,53 @@ https://github.com/qri-io/qri/issues`,
 	cmd.PersistentFlags().BoolVarP(&opt.NoColor, "no-color", "", false, "disable colorized output")
 	cmd.PersistentFlags().StringVar(&opt.repoPath, "repo", repoPath, "filepath to load qri data from")
 	cmd.PersistentFlags().BoolVarP(&opt.LogAll, "log-all", "", false, "log all activity")
+	cmd.PersistentFlags().StringVar(&opt.Source, "resolve-source", "", "default source to resolve datasets against, eg: local or network")
 
 	cmd.AddCommand(
 		NewAccessCommand(opt, ioStreams),
+		NewAdoptCommand(opt, ioStreams),
 		NewAnalyzeTransformCommand(opt, ioStreams),
 		NewApplyCommand(opt, ioStreams),
+		NewAttachCommand(opt, ioStreams),
 		NewAutocompleteCommand(opt, ioStreams),
+		NewBodyDeltaCommand(opt, ioStreams),
+		NewColumnCommand(opt, ioStreams),
+		NewComponentsCommand(opt, ioStreams),
 		NewConfigCommand(opt, ioStreams),
 		NewConnectCommand(opt, ioStreams),
 		NewDAGCommand(opt, ioStreams),
+		NewDedupCheckCommand(opt, ioStreams),
 		NewDiffCommand(opt, ioStreams),
+		NewDoctorCommand(opt, ioStreams),
+		NewExportGitCommand(opt, ioStreams),
+		NewFingerprintCommand(opt, ioStreams),
 		NewGetCommand(opt, ioStreams),
+		NewLinkCommand(opt, ioStreams),
 		NewListCommand(opt, ioStreams),
+		NewLocateCommand(opt, ioStreams),
 		NewLogCommand(opt, ioStreams),
 		NewLogbookCommand(opt, ioStreams),
+		NewMergeCommand(opt, ioStreams),
+		NewMetaCommand(opt, ioStreams),
+		NewOpsCommand(opt, ioStreams),
+		NewPatchCommand(opt, ioStreams),
 		NewPushCommand(opt, ioStreams),
 		NewPullCommand(opt, ioStreams),
 		NewPeersCommand(opt, ioStreams),
 		NewPreviewCommand(opt, ioStreams),
+		NewQualityCommand(opt, ioStreams),
 		NewRegistryCommand(opt, ioStreams),
+		NewRemoteCommand(opt, ioStreams),
 		NewRemoveCommand(opt, ioStreams),
 		NewRenameCommand(opt, ioStreams),
 		NewRenderCommand(opt, ioStreams),
 		NewSaveCommand(opt, ioStreams),
 		NewSearchCommand(opt, ioStreams),
+		NewServeCommand(opt, ioStreams),
 		NewSetupCommand(opt, ioStreams),
+		NewSquashCommand(opt, ioStreams),
+		NewStorageCommand(opt, ioStreams),
+		NewTransformCommand(opt, ioStreams),
 		NewValidateCommand(opt, ioStreams),
 		NewVersionCommand(opt, ioStreams),
 		NewWhatChangedCommand(opt, ioStreams),
@@ -107,7 +129,10 @@ type QriOptions struct {
 	// path to configuration object
 	ConfigPath string
 	// Whether to log all activity by enabling logging for all packages
-	LogAll  bool
+	LogAll bool
+	// Source overrides the configured default source for methods that don't
+	// declare their own
+	Source  string
 	libOpts []lib.Option
 	// inst is the Instance that holds state needed by qri's methods
 	inst *lib.Instance
@@ -141,6 +166,7 @@ func (o *QriOptions) Init() (err error) {
 		lib.OptIOStreams(o.IOStreams), // transfer iostreams to instance
 		lib.OptCheckConfigMigrations(o.migrationApproval, (!o.Migrate && !o.NoPrompt)),
 		lib.OptSetLogAll(o.LogAll),
+		lib.OptDefaultSource(o.Source),
 		lib.OptRemoteServerOptions([]remote.OptionsFunc{
 			// look for a remote policy
 			remote.OptLoadPolicyFileIfExists(filepath.Join(o.repoPath, access.DefaultAccessControlPolicyFilename)),
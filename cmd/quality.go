@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewQualityCommand creates a new `qri quality` command that checks dataset
+// stats for potential data quality problems
+func NewQualityCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &QualityOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "quality [DATASET]",
+		Short: "check a dataset for potential data quality problems",
+		Long: `Quality calculates statistics for a dataset body and flags columns that look
+problematic. Three kinds of issues are reported:
+
+  * columns where the share of null values is higher than --max-null-rate
+  * columns where every value is the same
+  * numeric columns with extreme outliers. a value is an outlier when it falls
+    more than --outlier-iqr interquartile ranges outside the middle half of
+    the column. quartiles are estimated from the column histogram
+
+Quality checks are heuristics. A warning is a prompt to look closer, not
+proof something is wrong.`,
+		Example: `  # check the latest version of me/annual_pop:
+  $ qri quality me/annual_pop
+
+  # only flag columns that are more than 90% null:
+  $ qri quality me/annual_pop --max-null-rate 0.9`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().Float64Var(&o.MaxNullRate, "max-null-rate", base.DefaultMaxNullRate, "fraction of null values a column can have before it's flagged, between 0 and 1")
+	cmd.Flags().Float64Var(&o.OutlierIQRMultiplier, "outlier-iqr", base.DefaultOutlierIQRMultiplier, "interquartile ranges a value must fall outside the middle half of a column to be an outlier")
+	cmd.Flags().StringVar(&o.Format, "format", "", "output format. one of [json]")
+
+	return cmd
+}
+
+// QualityOptions encapsulates state for the quality command
+type QualityOptions struct {
+	ioes.IOStreams
+
+	Refs                 *RefSelect
+	MaxNullRate          float64
+	OutlierIQRMultiplier float64
+	Format               string
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *QualityOptions) Complete(f Factory, args []string) (err error) {
+	if o.inst, err = f.Instance(); err != nil {
+		return err
+	}
+	if o.Format != "" && o.Format != "json" {
+		return fmt.Errorf("invalid format %q, only 'json' is supported", o.Format)
+	}
+	o.Refs, err = GetCurrentRefSelect(f, args, 1)
+	return err
+}
+
+// Run executes the quality command
+func (o *QualityOptions) Run() error {
+	ctx := context.TODO()
+	p := &lib.QualityParams{
+		Ref:                  o.Refs.Ref(),
+		MaxNullRate:          &o.MaxNullRate,
+		OutlierIQRMultiplier: &o.OutlierIQRMultiplier,
+	}
+	warnings, err := o.inst.Dataset().Quality(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	if o.Format == "json" {
+		data, err := json.MarshalIndent(warnings, "", "  ")
+		if err != nil {
+			return err
+		}
+		printInfo(o.Out, string(data))
+		return nil
+	}
+
+	if len(warnings) == 0 {
+		printSuccess(o.Out, "no quality issues found in %s", o.Refs.Ref())
+		return nil
+	}
+	printInfo(o.Out, "%d potential quality issues in %s:", len(warnings), o.Refs.Ref())
+	for _, w := range warnings {
+		printWarning(o.Out, "  %s (%s): %s", w.Column, w.Issue, w.Message)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qri-io/qri/base"
+)
+
+func TestQuality(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_quality", "qri_test_quality")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+
+	warnings := []base.QualityWarning{}
+	output := run.MustExec(t, "qri quality me/movies --format json")
+	if err := json.Unmarshal([]byte(output), &warnings); err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range warnings {
+		if w.Issue == base.QINullRate {
+			t.Errorf("expected no null rate warnings, got: %#v", w)
+		}
+	}
+
+	if err := run.ExecCommand("qri quality me/movies --max-null-rate 1.5"); err == nil {
+		t.Error("expected out of range null rate to error")
+	}
+}
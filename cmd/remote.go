@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewRemoteCommand creates a `qri remote` command for working with
+// configured remotes
+func NewRemoteCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &RemoteDiffOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "remote",
+		Short: "work with configured remotes",
+		Long: `Remote commands inspect the remotes configured in the remotes section of the
+qri config.`,
+		Annotations: map[string]string{
+			"group": "network",
+		},
+	}
+
+	diff := &cobra.Command{
+		Use:   "diff REMOTE REMOTE DATASET",
+		Short: "compare the versions of a dataset two remotes have",
+		Long: `Remote diff lists the versions of a dataset one remote has that the other
+doesn't, using the logbook each remote exposes. Use it to check that remotes
+mirroring a dataset are in sync. A remote that doesn't respond within --timeout
+is reported as unreachable, and no versions are compared.`,
+		Example: `  # Check a mirror is in sync with the main remote:
+  $ qri remote diff production backup me/annual_pop`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+	diff.Flags().DurationVar(&o.Timeout, "timeout", lib.DefaultLocateTimeout, "how long to wait for each remote to respond")
+	diff.Flags().StringVar(&o.Format, "format", "pretty", "output format [pretty|json]")
+
+	cmd.AddCommand(diff)
+	return cmd
+}
+
+// RemoteDiffOptions encapsulates state for the remote diff command
+type RemoteDiffOptions struct {
+	ioes.IOStreams
+
+	Left    string
+	Right   string
+	Refs    *RefSelect
+	Timeout time.Duration
+	Format  string
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *RemoteDiffOptions) Complete(f Factory, args []string) (err error) {
+	if o.inst, err = f.Instance(); err != nil {
+		return err
+	}
+	o.Left, o.Right = args[0], args[1]
+	o.Refs, err = GetCurrentRefSelect(f, args[2:], 1)
+	return err
+}
+
+// Run executes the remote diff command
+func (o *RemoteDiffOptions) Run() error {
+	p := &lib.RemoteDiffParams{
+		Ref:     o.Refs.Ref(),
+		Left:    o.Left,
+		Right:   o.Right,
+		Timeout: o.Timeout,
+	}
+	res, err := o.inst.Remote().Diff(context.TODO(), p)
+	if err != nil {
+		return err
+	}
+
+	switch o.Format {
+	case "json":
+		data, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		printInfo(o.Out, string(data))
+		return nil
+	case "pretty":
+	default:
+		return fmt.Errorf("unknown format %q, must be pretty or json", o.Format)
+	}
+
+	w := tabwriter.NewWriter(o.Out, 0, 0, 2, ' ', 0)
+	for _, h := range []lib.RemoteHoldings{res.Left, res.Right} {
+		if h.Error != "" {
+			fmt.Fprintf(w, "%s\tunreachable\t%s\n", h.Remote, h.Error)
+		} else {
+			fmt.Fprintf(w, "%s\t%d versions\t\n", h.Remote, len(h.Versions))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	switch {
+	case !res.Reachable():
+		printWarning(o.ErrOut, "can't compare versions, a remote is unreachable")
+	case res.InSync():
+		printSuccess(o.ErrOut, "%s and %s are in sync", res.Left.Remote, res.Right.Remote)
+	default:
+		printRemoteDiffVersions(o, res.Left.Remote, res.OnlyLeft)
+		printRemoteDiffVersions(o, res.Right.Remote, res.OnlyRight)
+	}
+	return nil
+}
+
+func printRemoteDiffVersions(o *RemoteDiffOptions, remote string, versions []dsref.VersionInfo) {
+	if len(versions) == 0 {
+		return
+	}
+	fmt.Fprintf(o.Out, "\nonly on %s:\n", remote)
+	w := tabwriter.NewWriter(o.Out, 0, 0, 2, ' ', 0)
+	for _, vi := range versions {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", vi.Path, vi.CommitTime.Format(time.RFC3339), vi.CommitTitle)
+	}
+	w.Flush()
+}
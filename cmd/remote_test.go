@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/qri-io/qri/config"
+	"github.com/qri-io/qri/logbook"
+)
+
+func TestRemoteDiff(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_remote_diff", "qri_test_remote_diff")
+	defer run.Delete()
+
+	// servers without the dataset
+	empty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(logbook.ErrNotFound.Error()))
+	}))
+	defer empty.Close()
+
+	run.RepoRoot.GetConfig().Remotes = &config.Remotes{
+		"production": empty.URL,
+		"backup":     empty.URL,
+		"offline":    "http://127.0.0.1:1",
+	}
+	if err := run.RepoRoot.WriteConfigFile(); err != nil {
+		t.Fatal(err)
+	}
+
+	output := run.MustExecCombinedOutErr(t, "qri remote diff production backup me/one_ds")
+	if !strings.Contains(output, "production  0 versions") || !strings.Contains(output, "backup      0 versions") {
+		t.Errorf("expected version counts for each remote, got: %q", output)
+	}
+	if !strings.Contains(output, "production and backup are in sync") {
+		t.Errorf("expected remotes to be in sync, got: %q", output)
+	}
+
+	output = run.MustExecCombinedOutErr(t, "qri remote diff production offline me/one_ds")
+	if !strings.Contains(output, "offline     unreachable") {
+		t.Errorf("expected offline remote to be unreachable, got: %q", output)
+	}
+	if !strings.Contains(output, "a remote is unreachable") {
+		t.Errorf("expected unreachable warning, got: %q", output)
+	}
+
+	if err := run.ExecCommand("qri remote diff production production me/one_ds"); err == nil {
+		t.Error("expected comparing a remote with itself to error")
+	}
+}
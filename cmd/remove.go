@@ -51,6 +51,9 @@ effect on local data.`,
   # destroy a dataset named 'annual_pop'
   $ qri remove --all me/annual_pop
 
+  # destroy annual_pop only if no local transforms load it
+  $ qri remove --all --check-dependents me/annual_pop
+
   # ask the registry to delete a dataset
   $ qri remove --remote registry me/annual_pop`,
 		Annotations: map[string]string{
@@ -73,6 +76,7 @@ effect on local data.`,
 	cmd.Flags().BoolVarP(&o.All, "all", "a", false, "synonym for --revisions=all")
 	cmd.Flags().BoolVarP(&o.Force, "force", "f", false, "remove files even if a working directory is dirty")
 	cmd.Flags().StringVar(&o.Remote, "remote", "", "remote address to remove from")
+	cmd.Flags().BoolVar(&o.CheckDependents, "check-dependents", false, "don't remove if local dataset transforms load this dataset")
 
 	return cmd
 }
@@ -83,11 +87,12 @@ type RemoveOptions struct {
 
 	Refs *RefSelect
 
-	Remote        string
-	RevisionsText string
-	Revision      *dsref.Rev
-	All           bool
-	Force         bool
+	Remote          string
+	RevisionsText   string
+	Revision        *dsref.Rev
+	All             bool
+	Force           bool
+	CheckDependents bool
 
 	inst *lib.Instance
 }
@@ -147,6 +152,11 @@ func (o *RemoveOptions) Run() (err error) {
 	}
 
 	ctx := context.TODO()
+	if o.CheckDependents {
+		if err := checkDependents(ctx, o.inst, o.ErrOut, o.Refs.Ref(), "removed"); err != nil {
+			return err
+		}
+	}
 	res, err := o.inst.Dataset().Remove(ctx, &params)
 	if err != nil {
 		// TODO(b5): move this error handling down into lib
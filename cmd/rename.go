@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"io"
 
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qri/errors"
@@ -24,7 +26,10 @@ not have the updated name. While this won't break anything, it will
 confuse anyone who has added your dataset before the change. Try to keep
 renames to a minimum.`,
 		Example: `  # Rename a dataset named annual_pop to annual_population:
-  $ qri rename me/annual_pop me/annual_population`,
+  $ qri rename me/annual_pop me/annual_population
+
+  # Rename a dataset only if no local transforms load it:
+  $ qri rename --check-dependents me/annual_pop me/annual_population`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -39,6 +44,8 @@ renames to a minimum.`,
 		},
 	}
 
+	cmd.Flags().BoolVar(&o.CheckDependents, "check-dependents", false, "don't rename if local dataset transforms load this dataset")
+
 	return cmd
 }
 
@@ -46,8 +53,9 @@ renames to a minimum.`,
 type RenameOptions struct {
 	ioes.IOStreams
 
-	From string
-	To   string
+	From            string
+	To              string
+	CheckDependents bool
 
 	inst *lib.Instance
 }
@@ -77,6 +85,11 @@ func (o *RenameOptions) Run() error {
 		Next:    o.To,
 	}
 	ctx := context.TODO()
+	if o.CheckDependents {
+		if err := checkDependents(ctx, o.inst, o.ErrOut, o.From, "renamed"); err != nil {
+			return err
+		}
+	}
 	res, err := o.inst.WithSource("local").Dataset().Rename(ctx, p)
 	if err != nil {
 		return err
@@ -85,3 +98,20 @@ func (o *RenameOptions) Run() error {
 	printSuccess(o.Out, "renamed dataset to %s", res.Name)
 	return nil
 }
+
+// checkDependents errors if any local datasets have transforms that load the
+// dataset ref names, printing the dependents as a warning
+func checkDependents(ctx context.Context, inst *lib.Instance, w io.Writer, ref, action string) error {
+	deps, err := inst.Dataset().Dependents(ctx, &lib.DependentsParams{Ref: ref})
+	if err != nil {
+		return err
+	}
+	if len(deps) == 0 {
+		return nil
+	}
+	printWarning(w, "%d datasets have transforms that load %s:", len(deps), ref)
+	for _, vi := range deps {
+		fmt.Fprintf(w, "  %s\n", vi.SimpleRef().Alias())
+	}
+	return fmt.Errorf("dataset not %s, transforms of dependent datasets would break", action)
+}
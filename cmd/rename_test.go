@@ -2,6 +2,9 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/qri-io/qri/errors"
@@ -169,3 +172,38 @@ func TestRenameRun(t *testing.T) {
 		run.IOReset()
 	}
 }
+
+func TestRenameRemoveCheckDependents(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_check_dependents", "qri_test_check_dependents")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/source_ds")
+
+	tmpDir := run.MakeTmpDir(t, "check_dependents")
+	scriptPath := filepath.Join(tmpDir, "transform.star")
+	run.MustWriteFile(t, scriptPath, `
+src = load_dataset("me/source_ds")
+ds = dataset.latest()
+ds.body = src.body
+dataset.commit(ds)
+`)
+	run.MustExec(t, fmt.Sprintf("qri save --apply --file=%s me/derived_ds", scriptPath))
+
+	err := run.ExecCommand("qri rename --check-dependents me/source_ds me/renamed_ds")
+	expect := "dataset not renamed, transforms of dependent datasets would break"
+	if err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+	if !strings.Contains(run.GetCommandErrOutput(), "test_peer_check_dependents/derived_ds") {
+		t.Errorf("expected warning to list dependent dataset, got: %q", run.GetCommandErrOutput())
+	}
+
+	err = run.ExecCommand("qri remove --all --check-dependents me/source_ds")
+	expect = "dataset not removed, transforms of dependent datasets would break"
+	if err == nil || err.Error() != expect {
+		t.Errorf("error mismatch. expected: %q, got: %v", expect, err)
+	}
+
+	// datasets without dependents are renamed as usual
+	run.MustExec(t, "qri rename --check-dependents me/derived_ds me/derived_ds_2")
+}
@@ -26,13 +26,30 @@ Use the ` + "`--output`" + ` flag to save the rendered html to a file.
 
 Use the ` + "`--viz`" + ` flag to render the viz. Default is to use readme.
 
+Use the ` + "`--inline-body`" + ` flag with ` + "`--viz`" + ` to embed the body as JSON in the
+rendered html, making a single file that works without qri. Templates can read
+the body from the script element with the id "qri-body". Large bodies can't be
+inlined.
+
+Use the ` + "`--body`" + ` flag to render the body as an html table, which is
+truncated for large bodies.
+
 Use the ` + "`--template`" + ` flag to use a custom template. If no template is
 provided, Qri will render the dataset with a default template.`,
 		Example: `  # Render the readme of a dataset called me/schools:
   $ qri render -o=schools.html me/schools
 
   # Render a dataset with a custom template:
-  $ qri render --viz --template=template.html me/schools`,
+  $ qri render --viz --template=template.html me/schools
+
+  # Render a viz with the body inlined, to share as a single file:
+  $ qri render --viz --inline-body -o=schools.html me/schools
+
+  # Render the body of a dataset as an html table:
+  $ qri render --body -o=schools_body.html me/schools
+
+  # Render rows 100-149 of the body:
+  $ qri render --body --limit 50 --offset 100 me/schools`,
 		Annotations: map[string]string{
 			"group": "dataset",
 		},
@@ -47,6 +64,10 @@ provided, Qri will render the dataset with a default template.`,
 	cmd.Flags().StringVarP(&o.Template, "template", "t", "", "path to template file")
 	cmd.MarkFlagFilename("template")
 	cmd.Flags().BoolVarP(&o.UseViz, "viz", "v", false, "whether to use the viz component")
+	cmd.Flags().BoolVar(&o.UseBody, "body", false, "render the body as an html table")
+	cmd.Flags().BoolVar(&o.InlineBody, "inline-body", false, "embed the body in the rendered viz")
+	cmd.Flags().IntVar(&o.Limit, "limit", 0, "for --body, the number of rows to render")
+	cmd.Flags().IntVar(&o.Offset, "offset", 0, "for --body, the number of rows to skip")
 	cmd.Flags().StringVarP(&o.Output, "output", "o", "", "path to write output file")
 	cmd.MarkFlagFilename("output")
 
@@ -57,10 +78,14 @@ provided, Qri will render the dataset with a default template.`,
 type RenderOptions struct {
 	ioes.IOStreams
 
-	Refs     *RefSelect
-	Template string
-	UseViz   bool
-	Output   string
+	Refs       *RefSelect
+	Template   string
+	UseViz     bool
+	UseBody    bool
+	InlineBody bool
+	Limit      int
+	Offset     int
+	Output     string
 
 	inst *lib.Instance
 }
@@ -85,9 +110,19 @@ func (o *RenderOptions) Run() error {
 		return fmt.Errorf("you must specify --viz when using --template")
 	}
 
+	if o.UseBody && o.UseViz {
+		return fmt.Errorf("cannot use --body and --viz together")
+	}
+
+	if o.InlineBody && !o.UseViz {
+		return fmt.Errorf("you must specify --viz when using --inline-body")
+	}
+
 	p := &lib.RenderParams{}
 	var err error
-	if o.UseViz {
+	if o.UseBody {
+		p = o.bodyRenderParams()
+	} else if o.UseViz {
 		p, err = o.vizRenderParams()
 		if err != nil {
 			return err
@@ -121,12 +156,23 @@ func (o *RenderOptions) vizRenderParams() (p *lib.RenderParams, err error) {
 		}
 	}
 
+	return &lib.RenderParams{
+		Ref:        o.Refs.Ref(),
+		Template:   template,
+		Format:     "html",
+		Selector:   "viz",
+		InlineBody: o.InlineBody,
+	}, nil
+}
+
+func (o *RenderOptions) bodyRenderParams() *lib.RenderParams {
 	return &lib.RenderParams{
 		Ref:      o.Refs.Ref(),
-		Template: template,
 		Format:   "html",
-		Selector: "viz",
-	}, nil
+		Selector: "body",
+		Limit:    o.Limit,
+		Offset:   o.Offset,
+	}
 }
 
 func (o *RenderOptions) readmeRenderParams() *lib.RenderParams {
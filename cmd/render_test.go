@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/qri-io/qri/base"
@@ -140,3 +141,55 @@ func TestRenderRun(t *testing.T) {
 		run.IOReset()
 	}
 }
+
+func TestRenderBody(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_render_body", "qri_test_render_body")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+
+	output := run.MustExec(t, "qri render --body me/movies")
+	for _, expect := range []string{
+		"<title>test_peer_render_body/movies</title>",
+		"<tr><th>movie_title</th><th>duration</th></tr>",
+		"<tr><td>Avatar </td><td>178</td></tr>",
+	} {
+		if !strings.Contains(output, expect) {
+			t.Errorf("expected output to contain %q. got:\n%s", expect, output)
+		}
+	}
+
+	if err := run.ExecCommand("qri render --body --viz me/movies"); err == nil {
+		t.Error("expected combining --body and --viz to error")
+	}
+
+	output = run.MustExec(t, "qri render --body --limit 1 --offset 2 me/movies")
+	if expect := "<tr><td>Spectre </td><td>148</td></tr>"; !strings.Contains(output, expect) {
+		t.Errorf("expected output to contain %q. got:\n%s", expect, output)
+	}
+	if unexpect := "<td>Avatar </td>"; strings.Contains(output, unexpect) {
+		t.Errorf("expected output to skip offset rows. got:\n%s", output)
+	}
+}
+
+func TestRenderInlineBody(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_render_inline_body", "qri_test_render_inline_body")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+
+	output := run.MustExec(t, "qri render --viz --inline-body me/movies")
+	for _, expect := range []string{
+		`<script type="application/json" id="qri-body">[["Avatar ",178],`,
+		"</script>\n</body>",
+	} {
+		if !strings.Contains(output, expect) {
+			t.Errorf("expected output to contain %q. got:\n%s", expect, output)
+		}
+	}
+
+	err := run.ExecCommand("qri render --inline-body me/movies")
+	if expect := "you must specify --viz when using --inline-body"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
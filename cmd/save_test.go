@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/qri-io/dataset"
@@ -19,6 +20,7 @@ import (
 	"github.com/qri-io/qri/dscache"
 	qrierr "github.com/qri-io/qri/errors"
 	"github.com/qri-io/qri/event"
+	"github.com/qri-io/qri/lib"
 )
 
 func TestSaveComplete(t *testing.T) {
@@ -553,6 +555,26 @@ func TestSaveFilenameMeta(t *testing.T) {
 	}
 }
 
+func TestSaveTSVBody(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_save_tsv_body", "qri_test_save_tsv_body")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "save_tsv_body")
+	bodyPath := filepath.Join(tmpDir, "cities.tsv")
+	run.MustWriteFile(t, bodyPath, "city\tpop\n\"toronto, on\"\t40000000\nchatham\t35000\n")
+	run.MustExec(t, fmt.Sprintf("qri save --body %s me/cities", bodyPath))
+
+	output := run.MustExec(t, "qri get structure me/cities")
+	if expect := `separator: "\t"`; !strings.Contains(output, expect) {
+		t.Errorf("expected structure to contain %q, got: %q", expect, output)
+	}
+
+	output = run.MustExec(t, "qri get body me/cities")
+	if expect := `[["toronto, on",40000000],["chatham",35000]]` + "\n"; output != expect {
+		t.Errorf("body mismatch. want: %q, got: %q", expect, output)
+	}
+}
+
 func TestSaveDscacheFirstCommit(t *testing.T) {
 	t.Skip("TODO(dustmop): Need a way to enable Dscache without the Param field")
 
@@ -1256,3 +1278,289 @@ func errorMessage(err error) string {
 	}
 	return err.Error()
 }
+
+func TestSaveMinChangeRows(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_save_min_change_rows", "qri_test_save_min_change_rows")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+
+	err := run.ExecCommand("qri save --body testdata/movies/body_twenty.csv --min-change-rows 100 me/movies")
+	if err == nil {
+		t.Fatal("expected save below change threshold to error")
+	}
+	if !strings.Contains(err.Error(), "below change threshold") {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	run.MustExec(t, "qri save --body testdata/movies/body_twenty.csv --min-change-rows 100 --force me/movies")
+	output := run.MustExec(t, "qri log me/movies")
+	if strings.Count(output, "Commit:") != 2 {
+		t.Errorf("expected forced save to commit. got:\n%s", output)
+	}
+}
+
+func TestSaveColumnDescriptions(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_save_column_descriptions", "qri_test_save_column_descriptions")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "save_column_descriptions")
+	descPath := filepath.Join(tmpDir, "dict.csv")
+	run.MustWriteFile(t, descPath, "column,description\nduration,length in minutes\nrating,not a column\n")
+
+	run.MustExec(t, fmt.Sprintf("qri save --body testdata/movies/body_ten.csv --column-descriptions %s me/movies", descPath))
+	if expect := `column descriptions: no column named "rating" in dataset`; !strings.Contains(run.GetCommandErrOutput(), expect) {
+		t.Errorf("expected warning %q, got: %q", expect, run.GetCommandErrOutput())
+	}
+	if strings.Contains(run.GetCommandErrOutput(), `"duration"`) {
+		t.Errorf("expected no warning for described column, got: %q", run.GetCommandErrOutput())
+	}
+}
+
+func TestSaveCommitTime(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_save_commit_time", "qri_test_save_commit_time")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv --commit-time 2019-07-01T00:00:00Z me/movies")
+	output := run.MustExec(t, "qri get commit.timestamp me/movies")
+	if !strings.Contains(output, "2019-07-01T00:00:00Z") {
+		t.Errorf("expected commit timestamp to be set, got: %s", output)
+	}
+
+	err := run.ExecCommand("qri save --body testdata/movies/body_twenty.csv --commit-time yesterday me/movies")
+	if expect := `invalid --commit-time "yesterday", must be an RFC3339 timestamp like 2021-01-02T15:04:05Z`; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestSaveFromSQL(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_save_from_sql", "qri_test_save_from_sql")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+
+	// queries contain spaces, pass arguments directly
+	saveSQL := func(query, ref string) error {
+		cmd, shutdown := run.CreateCommandRunner(run.Context)
+		err := executeCommandC(cmd, "save", "--from-sql", query, "--sql-source", "me/movies", ref)
+		timedShutdown("save --from-sql", shutdown)
+		return err
+	}
+
+	if err := saveSQL("SELECT movie_title, duration FROM source WHERE duration > 170 ORDER BY duration DESC", "me/long_movies"); err != nil {
+		t.Fatal(err)
+	}
+	output := run.MustExec(t, "qri get body --format json me/long_movies")
+	if expect := "[[\"Avatar \",178]]\n"; output != expect {
+		t.Errorf("body mismatch. want: %q, got: %q", expect, output)
+	}
+
+	err := saveSQL("SELECT title FROM source", "me/bad_movies")
+	if err == nil {
+		t.Fatal("expected query with unknown column to error")
+	}
+	if !strings.Contains(err.Error(), `unknown column "title"`) {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestSaveSynthesize(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_save_synthesize", "qri_test_save_synthesize")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "save_synthesize")
+	stPath := filepath.Join(tmpDir, "structure.json")
+	run.MustWriteFile(t, stPath, `{
+  "qri": "st:0",
+  "format": "csv",
+  "formatConfig": { "headerRow": true },
+  "schema": {
+    "type": "array",
+    "items": {
+      "type": "array",
+      "items": [
+        { "title": "id", "type": "integer", "minimum": 1, "maximum": 100 },
+        { "title": "status", "type": "string", "enum": ["open", "closed"] }
+      ]
+    }
+  }
+}`)
+
+	run.MustExec(t, fmt.Sprintf("qri save --synthesize 5 --seed 42 --file %s me/synth_a", stPath))
+	run.MustExec(t, fmt.Sprintf("qri save --synthesize 5 --seed 42 --file %s me/synth_b", stPath))
+
+	bodyA := run.MustExec(t, "qri get body me/synth_a")
+	bodyB := run.MustExec(t, "qri get body me/synth_b")
+	if bodyA != bodyB {
+		t.Errorf("expected the same seed to synthesize the same body. a: %q b: %q", bodyA, bodyB)
+	}
+	if length := run.MustExec(t, "qri get structure.entries me/synth_a"); length != "5\n\n" {
+		t.Errorf("expected 5 synthesized rows, got: %q", length)
+	}
+
+	err := run.ExecCommand("qri save --synthesize 5 me/synth_none")
+	if expect := "synthesizing a body requires a structure with a schema"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestSaveWatch(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_save_watch", "qri_test_save_watch")
+	defer run.Delete()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	f, err := NewTestFactory(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inst, err := f.Instance()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bodyPath := filepath.Join(t.TempDir(), "body.csv")
+	if err := ioutil.WriteFile(bodyPath, []byte("city,pop\ntoronto,40\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opt := &SaveOptions{
+		IOStreams: run.Streams,
+		Refs:      NewRefSelect("me/watched"),
+		BodyPath:  bodyPath,
+		Watch:     true,
+		inst:      inst,
+	}
+	if err := opt.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	headPath := func() string {
+		res, err := inst.Dataset().Get(ctx, &lib.GetParams{Ref: "me/watched"})
+		if err != nil {
+			return ""
+		}
+		return res.Value.(*dataset.Dataset).Path
+	}
+
+	// saves that don't change anything are skipped with a note
+	p := &lib.SaveParams{Ref: "me/watched", BodyPath: bodyPath}
+	opt.saveWatched(ctx, p)
+	first := headPath()
+	if first == "" {
+		t.Fatal("expected the first save to create a version")
+	}
+	opt.saveWatched(ctx, p)
+	if got := headPath(); got != first {
+		t.Errorf("expected a save with no changes to be skipped, head moved from %q to %q", first, got)
+	}
+	if !strings.Contains(run.ErrStream.String(), "skipping save: saving failed: no changes") {
+		t.Errorf("expected a skipped save to be reported, got: %s", run.ErrStream.String())
+	}
+
+	watchCtx, stopWatching := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() {
+		done <- opt.watch(watchCtx, p)
+	}()
+
+	// fsnotify delivers events asynchronously & saves wait out the debounce,
+	// so poll for the version a change to the body saves
+	if err := ioutil.WriteFile(bodyPath, []byte("city,pop\ntoronto,40\nchicago,30\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	saved := false
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if got := headPath(); got != "" && got != first {
+			saved = true
+			break
+		}
+	}
+	if !saved {
+		t.Errorf("timed out waiting for a save after changing the body")
+	}
+
+	stopWatching()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(run.ErrStream.String(), "watching 1 files for changes") {
+		t.Errorf("expected watch to be announced, got: %s", run.ErrStream.String())
+	}
+
+	opt = &SaveOptions{Refs: NewRefSelect("me/watched"), BodyPath: "https://example.com/body.csv", Watch: true}
+	if err := opt.Validate(); err == nil {
+		t.Error("expected watching without local files to error")
+	}
+}
+
+func TestSaveHashComponents(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_save_hash_components", "qri_test_save_hash_components")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv --hash-components me/movies")
+	msg := run.MustExec(t, "qri get commit.message me/movies")
+	if strings.Contains(msg, "sha256") {
+		t.Errorf("expected component hashes to stay out of the commit message, got:\n%s", msg)
+	}
+
+	path := run.LookupVersionInfo(t, "me/movies").Path
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r, err := run.RepoRoot.Repo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prov, err := dsfs.LoadProvenance(ctx, r.Filesystem(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	<-r.Done()
+	for _, name := range []string{"body", "schema"} {
+		if prov.ComponentHashes[name] == "" {
+			t.Errorf("expected provenance to record a %s hash, got: %v", name, prov.ComponentHashes)
+		}
+	}
+}
+
+func TestSaveOptionalColumns(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_save_optional_columns", "qri_test_save_optional_columns")
+	defer run.Delete()
+
+	tmpDir := run.MakeTmpDir(t, "save_optional_columns")
+	stPath := filepath.Join(tmpDir, "structure.json")
+	run.MustWriteFile(t, stPath, `{
+  "qri": "st:0",
+  "format": "csv",
+  "formatConfig": { "headerRow": true },
+  "schema": {
+    "type": "array",
+    "items": {
+      "type": "array",
+      "required": ["movie_title"],
+      "items": [
+        { "title": "movie_title", "type": "string" },
+        { "title": "rating", "type": ["string", "null"] },
+        { "title": "duration", "type": "integer" }
+      ]
+    }
+  }
+}`)
+	bodyPath := filepath.Join(tmpDir, "body.csv")
+	run.MustWriteFile(t, bodyPath, "duration,movie_title\n178,Avatar\n169,Spectre\n")
+
+	run.MustExec(t, fmt.Sprintf("qri save --file %s --body %s me/movies", stPath, bodyPath))
+	got := run.MustExec(t, "qri get body me/movies")
+	expect := `[["Avatar",null,178],["Spectre",null,169]]`
+	if strings.TrimSpace(got) != expect {
+		t.Errorf("body mismatch. expected: %s, got: %s", expect, got)
+	}
+
+	run.MustWriteFile(t, bodyPath, "duration\n178\n")
+	err := run.ExecCommand(fmt.Sprintf("qri save --file %s --body %s me/movies", stPath, bodyPath))
+	if expect := `body is missing required column "movie_title"`; err == nil || !strings.Contains(err.Error(), expect) {
+		t.Errorf("expected error containing %q, got: %v", expect, err)
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/api"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewServeCommand creates a new `qri serve` cobra command for serving parts of
+// a dataset over HTTP
+func NewServeCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &ServeOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "serve dataset data over HTTP",
+		Long: `Serve starts a small, focused HTTP server that exposes part of a dataset at a
+URL. Unlike 'qri connect', serve doesn't start the full JSON API, making it a
+lightweight way to share data with tools that don't speak qri.`,
+		Annotations: map[string]string{
+			"group": "network",
+		},
+	}
+
+	body := &cobra.Command{
+		Use:   "body DATASET",
+		Short: "serve a dataset body over HTTP",
+		Long: `Serve the body of a dataset at the root path of a local HTTP server. The
+response format is chosen by content negotiation: requests with an
+"Accept: text/csv" header or a "format=csv" query param receive csv, all other
+requests receive json. Range requests are supported for large bodies.`,
+		Example: `  # serve the body of me/annual_pop on port 8080:
+  $ qri serve body me/annual_pop --port 8080
+
+  # in another terminal, fetch the body as csv:
+  $ curl -H "Accept: text/csv" http://localhost:8080/`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.RunBody()
+		},
+	}
+
+	body.Flags().IntVar(&o.Port, "port", 8080, "port to serve on")
+
+	cmd.AddCommand(body)
+	return cmd
+}
+
+// ServeOptions encapsulates state for the serve command
+type ServeOptions struct {
+	ioes.IOStreams
+
+	Refs *RefSelect
+	Port int
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *ServeOptions) Complete(f Factory, args []string) (err error) {
+	if o.inst, err = f.Instance(); err != nil {
+		return err
+	}
+	if o.Port <= 0 {
+		return fmt.Errorf("invalid port: %d", o.Port)
+	}
+	o.Refs, err = GetCurrentRefSelect(f, args, 1)
+	return err
+}
+
+// RunBody serves the body of the selected dataset until the process exits
+func (o *ServeOptions) RunBody() error {
+	ctx := context.Background()
+	addr := fmt.Sprintf(":%d", o.Port)
+	printInfo(o.Out, fmt.Sprintf("serving body of %s at http://localhost%s", o.Refs.Ref(), addr))
+
+	err := api.NewBodyServer(o.inst, o.Refs.Ref()).Serve(ctx, addr)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewSquashCommand creates a new `qri squash` command that compacts dataset
+// history
+func NewSquashCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &SquashOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "squash DATASET",
+		Short: "collapse old versions of a dataset into a single version",
+		Long: `Squash compacts the history of a dataset, keeping the most recent versions
+and collapsing everything before them into a single base version. The base
+version is the version immediately before the kept versions. Older versions
+are removed from the logbook and unpinned from the local store, reducing the
+space a dataset takes up and the amount of data that needs to be synced.
+
+Squash rewrites history. Peers & remotes that have the full log of a dataset
+won't share history with the squashed version, and won't be able to sync with
+it until they've removed & re-pulled the dataset.`,
+		Example: `  # keep the 5 most recent versions of me/annual_pop, plus one base version:
+  $ qri squash me/annual_pop --keep 5`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().IntVar(&o.Keep, "keep", 0, "number of most recent versions to keep as-is")
+
+	return cmd
+}
+
+// SquashOptions encapsulates state for the squash command
+type SquashOptions struct {
+	ioes.IOStreams
+
+	Refs *RefSelect
+	Keep int
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *SquashOptions) Complete(f Factory, args []string) (err error) {
+	if o.inst, err = f.Instance(); err != nil {
+		return err
+	}
+	if o.Keep < 0 {
+		return fmt.Errorf("--keep must be greater than or equal to 0")
+	}
+	o.Refs, err = GetCurrentRefSelect(f, args, 1)
+	return err
+}
+
+// Run executes the squash command
+func (o *SquashOptions) Run() error {
+	ctx := context.TODO()
+	printWarning(o.ErrOut, "squashing rewrites history, peers with the full log of %s won't be able to sync with it\n", o.Refs.Ref())
+
+	res, err := o.inst.Dataset().Squash(ctx, &lib.SquashParams{
+		Ref:  o.Refs.Ref(),
+		Keep: o.Keep,
+	})
+	if err != nil {
+		return err
+	}
+
+	printSuccess(o.Out, "squashed %d versions of %s", res.NumSquashed, res.Ref)
+	return nil
+}
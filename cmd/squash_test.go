@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSquash(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_squash", "qri_test_squash")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+	run.MustExec(t, "qri save --file testdata/movies/meta_override.yaml me/movies")
+	run.MustExec(t, "qri save --body testdata/movies/body_twenty.csv me/movies")
+
+	output := run.MustExec(t, "qri squash me/movies --keep 1")
+	if !strings.Contains(output, "squashed 1 versions of test_peer_squash/movies") {
+		t.Errorf("unexpected output: %q", output)
+	}
+
+	if err := run.ExecCommand("qri squash me/movies --keep 1"); err == nil {
+		t.Error("expected squashing a compacted history to error")
+	}
+
+	output = run.MustExec(t, "qri log me/movies")
+	if strings.Count(output, "/ipfs/") != 2 {
+		t.Errorf("expected 2 versions in log after squash. got:\n%s", output)
+	}
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewStorageCommand creates a new `qri storage` command that reports how much
+// space a dataset occupies
+func NewStorageCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &StorageOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "storage [DATASET]",
+		Short: "show how much space a dataset occupies",
+		Long: `Storage estimates the disk space a dataset occupies in the local repo.
+
+Qri stores data as content-addressed blocks, so data that doesn't change
+between versions is only stored once. Storage reports both the logical size of
+the requested versions and the size of the unique blocks they use, along with
+how much space deduplication saves. By default only the latest version is
+measured, use --all to include every version in the dataset's history.`,
+		Example: `  # show the footprint of the latest version of me/annual_pop:
+  $ qri storage me/annual_pop
+
+  # show the footprint of all versions:
+  $ qri storage me/annual_pop --all`,
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.AllVersions, "all", false, "include all versions in dataset history")
+	cmd.Flags().StringVar(&o.Format, "format", "", "output format. one of [json]")
+
+	return cmd
+}
+
+// StorageOptions encapsulates state for the storage command
+type StorageOptions struct {
+	ioes.IOStreams
+
+	Refs        *RefSelect
+	AllVersions bool
+	Format      string
+
+	inst *lib.Instance
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *StorageOptions) Complete(f Factory, args []string) (err error) {
+	if o.inst, err = f.Instance(); err != nil {
+		return err
+	}
+	if o.Format != "" && o.Format != "json" {
+		return fmt.Errorf("invalid format %q, only 'json' is supported", o.Format)
+	}
+	o.Refs, err = GetCurrentRefSelect(f, args, 1)
+	return err
+}
+
+// Run executes the storage command
+func (o *StorageOptions) Run() error {
+	ctx := context.TODO()
+	p := &lib.StorageInfoParams{
+		Ref:         o.Refs.Ref(),
+		AllVersions: o.AllVersions,
+	}
+	info, err := o.inst.Dataset().StorageInfo(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	if o.Format == "json" {
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return err
+		}
+		printInfo(o.Out, string(data))
+		return nil
+	}
+
+	printInfo(o.Out, "storage for: %s", o.Refs.Ref())
+	printInfo(o.Out, "versions:      %d", info.Versions)
+	printInfo(o.Out, "blocks:        %d (%d unique)", info.Blocks, info.UniqueBlocks)
+	printInfo(o.Out, "logical size:  %s", humanize.Bytes(info.LogicalSize))
+	printInfo(o.Out, "unique size:   %s", humanize.Bytes(info.UniqueSize))
+	printInfo(o.Out, "dedup savings: %s", humanize.Bytes(info.DedupSavings))
+	return nil
+}
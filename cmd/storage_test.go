@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qri-io/qri/lib"
+)
+
+func TestStorage(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_storage", "qri_test_storage")
+	defer run.Delete()
+
+	// two versions that share a body
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+	run.MustExec(t, "qri save --file testdata/movies/meta_override.yaml me/movies")
+
+	latest := lib.StorageInfo{}
+	output := run.MustExec(t, "qri storage me/movies --format json")
+	if err := json.Unmarshal([]byte(output), &latest); err != nil {
+		t.Fatal(err)
+	}
+	if latest.Versions != 1 {
+		t.Errorf("expected 1 version, got: %d", latest.Versions)
+	}
+	if latest.DedupSavings != 0 {
+		t.Errorf("expected a single version to have no dedup savings, got: %d", latest.DedupSavings)
+	}
+
+	all := lib.StorageInfo{}
+	output = run.MustExec(t, "qri storage me/movies --all --format json")
+	if err := json.Unmarshal([]byte(output), &all); err != nil {
+		t.Fatal(err)
+	}
+	if all.Versions != 2 {
+		t.Errorf("expected 2 versions, got: %d", all.Versions)
+	}
+	if all.UniqueBlocks >= all.Blocks {
+		t.Errorf("expected versions to share blocks. blocks: %d, unique: %d", all.Blocks, all.UniqueBlocks)
+	}
+	if all.DedupSavings == 0 || all.LogicalSize != all.UniqueSize+all.DedupSavings {
+		t.Errorf("unexpected sizes: %#v", all)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
+	"github.com/qri-io/qri/automation/run"
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/dsref"
 	"github.com/qri-io/qri/lib"
@@ -235,6 +236,18 @@ func (s logEntryStringer) String() string {
 type dslogItemStringer dsref.VersionInfo
 
 func (s dslogItemStringer) String() string {
+	return dslogItemString(dsref.VersionInfo(s), false)
+}
+
+// dslogItemWithRowsStringer is a dslogItemStringer that adds a line for
+// the number of body rows in the version
+type dslogItemWithRowsStringer dsref.VersionInfo
+
+func (s dslogItemWithRowsStringer) String() string {
+	return dslogItemString(dsref.VersionInfo(s), true)
+}
+
+func dslogItemString(s dsref.VersionInfo, showRows bool) string {
 	yellow := color.New(color.FgYellow).SprintFunc()
 	faint := color.New(color.Faint).SprintFunc()
 
@@ -243,7 +256,7 @@ func (s dslogItemStringer) String() string {
 		storage = faint("remote")
 	}
 
-	msg := fmt.Sprintf("%s%s\n%s%s\n%s%s\n%s%s\n\n%s\n",
+	msg := fmt.Sprintf("%s%s\n%s%s\n%s%s\n%s%s\n",
 		faint("Commit:  "),
 		yellow(s.Path),
 		faint("Date:    "),
@@ -252,8 +265,22 @@ func (s dslogItemStringer) String() string {
 		storage,
 		faint("Size:    "),
 		humanize.Bytes(uint64(s.BodySize)),
-		s.CommitTitle,
 	)
+	if showRows {
+		msg += fmt.Sprintf("%s%d\n", faint("Rows:    "), s.BodyRows)
+	}
+	// manual runs are the default, only call out runs something else started
+	if s.RunTrigger != "" && s.RunTrigger != run.TriggerManual {
+		msg += fmt.Sprintf("%s%s\n", faint("Trigger: "), s.RunTrigger)
+	}
+	if s.Deleted {
+		deleted := "deleted"
+		if s.DeleteTime != nil {
+			deleted = fmt.Sprintf("deleted %s", s.DeleteTime.In(StringerLocation).Format(time.UnixDate))
+		}
+		msg += fmt.Sprintf("%s%s\n", faint("Status:  "), color.New(color.FgRed).Sprint(deleted))
+	}
+	msg += fmt.Sprintf("\n%s\n", s.CommitTitle)
 	if s.CommitMessage != "" && s.CommitMessage != s.CommitTitle {
 		msg += fmt.Sprintf("%s\n", s.CommitMessage)
 	}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/automation/run"
+	"github.com/qri-io/qri/lib"
+	"github.com/spf13/cobra"
+)
+
+// NewTransformCommand creates a new `qri transform` cobra command for working
+// with the transforms of saved datasets
+func NewTransformCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
+	o := &TransformOptions{IOStreams: ioStreams}
+	cmd := &cobra.Command{
+		Use:   "transform",
+		Short: "work with the transforms of saved datasets",
+		Annotations: map[string]string{
+			"group": "dataset",
+		},
+	}
+
+	verify := &cobra.Command{
+		Use:   "verify DATASET",
+		Short: "check a transform reproduces a saved dataset version",
+		Long: `Verify re-runs the transform stored in a dataset version against the same
+versions of input datasets it originally loaded, and checks the output matches
+the stored body. Nothing is saved.
+
+Verification depends on the transform having recorded the datasets it loaded.
+A mismatch means the transform isn't deterministic, for example it fetches data
+over http or reads secrets that have changed, or that it loads inputs it didn't
+record.`,
+		Example: `  # Verify the latest version of a transform-driven dataset:
+  $ qri transform verify me/annual_pop
+
+  # Verify a specific version:
+  $ qri transform verify me/annual_pop@/ipfs/QmVersionPath`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(f, args); err != nil {
+				return err
+			}
+			return o.Verify()
+		},
+	}
+	verify.Flags().StringSliceVar(&o.Secrets, "secrets", nil, "transform secrets as comma separated key,value,key,value,... sequence")
+	verify.Flags().BoolVar(&o.Quiet, "quiet", false, "whether to suppress output from the transform")
+	cmd.AddCommand(verify)
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "list datasets with transforms & the status of their last run",
+		Long: `List shows every local dataset that has a transform, or has a record of
+running one, along with the status, start time and duration of its most recent
+run. Run records are read from the logbook.
+
+Use --sort failed to triage: datasets whose transforms failed most recently
+are listed first.`,
+		Example: `  # List transform-driven datasets by name:
+  $ qri transform list
+
+  # List datasets whose transforms failed most recently first:
+  $ qri transform list --sort failed`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if o.Instance, err = f.Instance(); err != nil {
+				return err
+			}
+			return o.List()
+		},
+	}
+	list.Flags().StringVar(&o.Sort, "sort", "", "order of the list [recent, failed]. defaults to dataset name")
+	list.Flags().StringVar(&o.Format, "format", "", "set output format [json]")
+	cmd.AddCommand(list)
+
+	return cmd
+}
+
+// TransformOptions encapsulates state for the transform command
+type TransformOptions struct {
+	ioes.IOStreams
+
+	Instance *lib.Instance
+
+	Refs    *RefSelect
+	Secrets []string
+	Quiet   bool
+
+	Sort   string
+	Format string
+}
+
+// Complete adds any missing configuration that can only be added just before calling Run
+func (o *TransformOptions) Complete(f Factory, args []string) (err error) {
+	if o.Instance, err = f.Instance(); err != nil {
+		return err
+	}
+	o.Refs, err = GetCurrentRefSelect(f, args, 1)
+	return err
+}
+
+// Verify replays the transform of a dataset version, erroring if the output
+// doesn't match the stored body
+func (o *TransformOptions) Verify() (err error) {
+	p := &lib.VerifyTransformParams{Ref: o.Refs.Ref()}
+	if len(o.Secrets) > 0 {
+		if p.Secrets, err = parseSecrets(o.Secrets...); err != nil {
+			return err
+		}
+	}
+	if !o.Quiet {
+		p.ScriptOutput = o.ErrOut
+	}
+
+	res, err := o.Instance.Automation().VerifyTransform(context.TODO(), p)
+	if err != nil {
+		return err
+	}
+
+	printInfo(o.ErrOut, "replayed transform of %s against %d inputs", res.Ref, len(res.Inputs))
+	for _, input := range res.Inputs {
+		fmt.Fprintf(o.ErrOut, "  %s\n", input)
+	}
+	if !res.Match {
+		return fmt.Errorf("transform output doesn't match the stored body: %s", res.Mismatch)
+	}
+	printSuccess(o.Out, "transform output matches the stored body")
+	return nil
+}
+
+// List prints datasets with transforms & the status of their last run
+func (o *TransformOptions) List() error {
+	if o.Format != "" && o.Format != "json" {
+		return fmt.Errorf("unknown format %q, only json is supported", o.Format)
+	}
+	res, err := o.Instance.Automation().ListTransforms(context.TODO(), &lib.ListTransformsParams{Sort: o.Sort})
+	if err != nil {
+		return err
+	}
+
+	if o.Format == "json" {
+		data, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.Out, string(data))
+		return nil
+	}
+
+	if len(res) == 0 {
+		printInfo(o.Out, "no datasets with transforms")
+		return nil
+	}
+	for i, st := range res {
+		fmt.Fprintf(o.Out, "%d  %s\n", i+1, st.Ref)
+		if st.LastRunStatus == "" {
+			fmt.Fprintf(o.Out, "   never run\n")
+			continue
+		}
+		started := "unknown time"
+		if st.LastRunStart != nil {
+			started = humanize.Time(*st.LastRunStart)
+		}
+		fmt.Fprintf(o.Out, "   last run: %s %s, took %s\n", st.LastRunStatus, started, time.Duration(st.LastRunDuration))
+		if st.LastFailure != nil && st.LastRunStatus != string(run.RSFailed) {
+			fmt.Fprintf(o.Out, "   last failed: %s\n", humanize.Time(*st.LastFailure))
+		}
+		fmt.Fprintf(o.Out, "   %d runs\n", st.RunCount)
+	}
+	return nil
+}
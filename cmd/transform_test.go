@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTransformVerify(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_transform_verify", "qri_test_transform_verify")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/source_ds")
+
+	tmpDir := run.MakeTmpDir(t, "transform_verify")
+	scriptPath := filepath.Join(tmpDir, "transform.star")
+	run.MustWriteFile(t, scriptPath, `
+src = load_dataset("me/source_ds")
+ds = dataset.latest()
+ds.body = src.body
+dataset.commit(ds)
+`)
+	run.MustExec(t, fmt.Sprintf("qri save --apply --file=%s me/derived_ds", scriptPath))
+	run.MustExec(t, "qri save --body testdata/movies/body_twenty.csv me/source_ds")
+
+	output := run.MustExecCombinedOutErr(t, "qri transform verify me/derived_ds")
+	if !strings.Contains(output, "against 1 inputs") {
+		t.Errorf("expected output to list the recorded input, got: %q", output)
+	}
+	if !strings.Contains(output, "transform output matches the stored body") {
+		t.Errorf("expected transform output to match, got: %q", output)
+	}
+
+	err := run.ExecCommand("qri transform verify me/source_ds")
+	if err == nil || !strings.Contains(err.Error(), "has no transform to verify") {
+		t.Errorf("expected verifying a dataset without a transform to error, got: %v", err)
+	}
+}
+
+func TestTransformList(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_transform_list", "qri_test_transform_list")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/no_transform")
+
+	tmpDir := run.MakeTmpDir(t, "transform_list")
+	scriptPath := filepath.Join(tmpDir, "transform.star")
+	run.MustWriteFile(t, scriptPath, `
+ds = dataset.latest()
+ds.body = [[1]]
+dataset.commit(ds)
+`)
+	run.MustExec(t, fmt.Sprintf("qri save --apply --file=%s me/b_derived", scriptPath))
+	run.MustExec(t, fmt.Sprintf("qri save --apply --file=%s me/a_derived", scriptPath))
+
+	output := run.MustExec(t, "qri transform list")
+	if strings.Contains(output, "no_transform") {
+		t.Errorf("expected datasets without transforms to be left out, got: %q", output)
+	}
+	if !strings.Contains(output, "1  test_peer_transform_list/a_derived") || !strings.Contains(output, "2  test_peer_transform_list/b_derived") {
+		t.Errorf("expected datasets listed by name, got: %q", output)
+	}
+	if !strings.Contains(output, "last run: succeeded") {
+		t.Errorf("expected last run status, got: %q", output)
+	}
+
+	output = run.MustExec(t, "qri transform list --sort recent")
+	if !strings.Contains(output, "1  test_peer_transform_list/a_derived") {
+		t.Errorf("expected most recently run dataset first, got: %q", output)
+	}
+
+	output = run.MustExec(t, "qri transform list --format json")
+	if !strings.Contains(output, `"runCount": 1`) {
+		t.Errorf("expected json output to count runs, got: %q", output)
+	}
+
+	err := run.ExecCommand("qri transform list --sort size")
+	if expect := `unknown sort "size", expected one of recent, failed`; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
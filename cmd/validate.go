@@ -58,7 +58,11 @@ You can get the current schema of a dataset by running the ` + "`qri get structu
 command.
 
 Note: --body and --schema or --structure flags will override the dataset
-if these flags are provided.`,
+if these flags are provided.
+
+Results of validating a dataset version against its own schema are cached, and
+are reused until the body or structure of the dataset changes. Use --no-cache
+to validate again anyway.`,
 		Example: `  # Show errors in an existing dataset:
   $ qri validate b5/comics
 
@@ -85,6 +89,7 @@ if these flags are provided.`,
 	cmd.Flags().StringVarP(&o.StructureFilepath, "structure", "", "", "json structure file to use for validation")
 	cmd.MarkFlagFilename("structure", "json")
 	cmd.Flags().StringVar(&o.Format, "format", "table", "output format. One of: [table|json|csv]")
+	cmd.Flags().BoolVar(&o.NoCache, "no-cache", false, "validate without using cached results")
 
 	return cmd
 }
@@ -98,6 +103,7 @@ type ValidateOptions struct {
 	SchemaFilepath    string
 	StructureFilepath string
 	Format            string
+	NoCache           bool
 
 	inst *lib.Instance
 }
@@ -133,6 +139,7 @@ func (o *ValidateOptions) Run() (err error) {
 		BodyFilename:      o.BodyFilepath,
 		SchemaFilename:    o.SchemaFilepath,
 		StructureFilename: o.StructureFilepath,
+		NoCache:           o.NoCache,
 	}
 
 	ctx := context.TODO()
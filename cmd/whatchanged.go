@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/qri-io/ioes"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/lib"
 	"github.com/spf13/cobra"
 )
@@ -19,7 +21,10 @@ func NewWhatChangedCommand(f Factory, ioStreams ioes.IOStreams) *cobra.Command {
 		Long: `Shows what changed for components at a particular commit, that is, which
 were added, modified or removed.`,
 		Example: `  # Show what changed for the head commit
-  $ qri whatchanged me/dataset_name`,
+  $ qri whatchanged me/dataset_name
+
+  # Show what changed as a json object of component: status, for scripting
+  $ qri whatchanged me/dataset_name --json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := o.Complete(f, args); err != nil {
 				return err
@@ -28,6 +33,8 @@ were added, modified or removed.`,
 		},
 	}
 
+	cmd.Flags().BoolVar(&o.JSON, "json", false, "print component statuses as a json object")
+
 	return cmd
 }
 
@@ -38,6 +45,7 @@ type WhatChangedOptions struct {
 	Instance *lib.Instance
 
 	Refs *RefSelect
+	JSON bool
 }
 
 // Complete adds any missing configuration that can only be added just before calling Run
@@ -56,6 +64,12 @@ func (o *WhatChangedOptions) Run() (err error) {
 
 	params := lib.WhatChangedParams{Ref: o.Refs.Ref()}
 	res, err := inst.Dataset().WhatChanged(ctx, &params)
+	if o.JSON {
+		if err != nil {
+			return err
+		}
+		return o.printJSON(params.Ref, res)
+	}
 	if err != nil {
 		printErr(o.ErrOut, err)
 		return nil
@@ -67,3 +81,18 @@ func (o *WhatChangedOptions) Run() (err error) {
 
 	return nil
 }
+
+// printJSON writes statuses as a single object mapping component names to
+// status types, with a "ref" key naming the version the statuses describe
+func (o *WhatChangedOptions) printJSON(ref string, items []base.StatusItem) error {
+	obj := map[string]string{"ref": ref}
+	for _, si := range items {
+		obj[si.Component] = si.Type
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	printInfo(o.Out, string(data))
+	return nil
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWhatChangedJSON(t *testing.T) {
+	run := NewTestRunner(t, "test_peer_whatchanged", "qri_test_whatchanged_json")
+	defer run.Delete()
+
+	run.MustExec(t, "qri save --body testdata/movies/body_ten.csv me/movies")
+	run.MustExec(t, "qri save --file testdata/movies/meta_override.yaml me/movies")
+
+	ref := "test_peer_whatchanged/movies@" + run.LookupVersionInfo(t, "me/movies").Path
+	output := run.MustExec(t, "qri whatchanged --json "+ref)
+
+	got := map[string]string{}
+	if err := json.Unmarshal([]byte(output), &got); err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{
+		"ref":       ref,
+		"meta":      "add",
+		"structure": "unmodified",
+		"body":      "unmodified",
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := run.ExecCommand("qri whatchanged --json me/movies"); err == nil {
+		t.Error("expected a reference without a path to error")
+	}
+}
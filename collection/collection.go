@@ -79,9 +79,65 @@ func (sm *SetMaintainer) subscribe(bus event.Bus) {
 		// log events
 		event.ETLogbookWriteCommit,
 		event.ETLogbookWriteRun,
+		event.ETLogbookBulkWrite,
 	)
 }
 
+// mergeCommitVersionInfo returns vi with fields that aren't tracked by the
+// logbook copied from the existing collection entry m
+func mergeCommitVersionInfo(vi, m dsref.VersionInfo) dsref.VersionInfo {
+	// preserve fields that are not tracked in `ETLogbookWriteCommit`
+	vi.WorkflowID = m.WorkflowID
+	vi.DownloadCount = m.DownloadCount
+	vi.RunCount = m.RunCount
+	vi.FollowerCount = m.FollowerCount
+	vi.OpenIssueCount = m.OpenIssueCount
+
+	// preserve "last run" information
+	if vi.RunID == "" {
+		vi.RunID = m.RunID
+		vi.RunStatus = m.RunStatus
+		vi.RunDuration = m.RunDuration
+		vi.RunStart = m.RunStart
+		vi.RunTrigger = m.RunTrigger
+	}
+	return vi
+}
+
+// rebuild brings the collection entries of the datasets a logbook bulk write
+// touched up to date. profileID is the profile that deleted datasets are
+// removed from
+func (sm *SetMaintainer) rebuild(ctx context.Context, profileID string, bw event.LogbookBulkWrite) error {
+	for _, vi := range bw.Versions {
+		pid, err := profile.IDB58Decode(vi.ProfileID)
+		if err != nil {
+			log.Debugw("parsing profile ID in bulk write", "initID", vi.InitID, "err", err)
+			continue
+		}
+		if m, err := sm.Get(ctx, pid, vi.InitID); err == nil {
+			vi = mergeCommitVersionInfo(vi, *m)
+		}
+		if err := sm.Add(ctx, pid, vi); err != nil {
+			log.Debugw("adding dataset to collection", "profileID", pid, "initID", vi.InitID, "err", err)
+		}
+	}
+
+	if len(bw.Deleted) == 0 || profileID == "" {
+		return nil
+	}
+	pid, err := profile.IDB58Decode(profileID)
+	if err != nil {
+		log.Debugw("parsing profile ID in bulk write", "err", err)
+		return err
+	}
+	for _, initID := range bw.Deleted {
+		if err := sm.Delete(ctx, pid, initID); err != nil {
+			log.Debugw("removing dataset from collection", "profileID", pid, "initID", initID, "err", err)
+		}
+	}
+	return nil
+}
+
 func (sm *SetMaintainer) handleEvent(_ context.Context, e event.Event) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
@@ -103,24 +159,13 @@ func (sm *SetMaintainer) handleEvent(_ context.Context, e event.Event) error {
 		// keep in mind commit changes can mean added OR removed versions
 		if vi, ok := e.Payload.(dsref.VersionInfo); ok {
 			sm.UpdateEverywhere(ctx, vi.InitID, func(m *dsref.VersionInfo) {
-				// preserve fields that are not tracked in `ETLogbookWriteCommit`
-				vi.WorkflowID = m.WorkflowID
-				vi.DownloadCount = m.DownloadCount
-				vi.RunCount = m.RunCount
-				vi.FollowerCount = m.FollowerCount
-				vi.OpenIssueCount = m.OpenIssueCount
-
-				// preserve "last run" information
-				if vi.RunID == "" {
-					vi.RunID = m.RunID
-					vi.RunStatus = m.RunStatus
-					vi.RunDuration = m.RunDuration
-					vi.RunStart = m.RunStart
-				}
-
-				*m = vi
+				*m = mergeCommitVersionInfo(vi, *m)
 			})
 		}
+	case event.ETLogbookBulkWrite:
+		if bw, ok := e.Payload.(event.LogbookBulkWrite); ok {
+			return sm.rebuild(ctx, e.ProfileID, bw)
+		}
 	case event.ETDatasetRename:
 		if rename, ok := e.Payload.(event.DsRename); ok {
 			sm.UpdateEverywhere(ctx, rename.InitID, func(vi *dsref.VersionInfo) {
@@ -228,6 +273,7 @@ func (sm *SetMaintainer) handleEvent(_ context.Context, e event.Event) error {
 					vi.RunStatus = "running"
 					vi.RunID = te.RunID
 					vi.RunStart = nil
+					vi.RunTrigger = ""
 				})
 				if err != nil {
 					log.Debugw("update dataset across all collections", "InitID", te.InitID, "err", err)
@@ -241,6 +287,7 @@ func (sm *SetMaintainer) handleEvent(_ context.Context, e event.Event) error {
 				v.RunID = vi.RunID
 				v.RunStatus = vi.RunStatus
 				v.RunDuration = vi.RunDuration
+				v.RunTrigger = vi.RunTrigger
 			})
 			if err != nil {
 				log.Debugw("update dataset across all collections", "InitID", vi.InitID, "err", err)
@@ -354,8 +401,8 @@ func NewLocalSet(ctx context.Context, repoDir string, options ...LocalSetOptionF
 	return s, err
 }
 
-// List currently supports ordering by name (ascending) and last update (descending)
-// default is name
+// List currently supports ordering by name, last update & body size, in
+// either direction. default is name, ascending
 func (s *localSet) List(ctx context.Context, pid profile.ID, lp params.List) ([]dsref.VersionInfo, error) {
 	s.Lock()
 	defer s.Unlock()
@@ -376,15 +423,7 @@ func (s *localSet) List(ctx context.Context, pid profile.ID, lp params.List) ([]
 	results := make([]dsref.VersionInfo, 0, lp.Limit)
 
 	if len(lp.OrderBy) != 0 {
-		switch lp.OrderBy[0].Key {
-		case "updated":
-			sortedCol := make([]dsref.VersionInfo, len(col))
-			copy(sortedCol, col)
-			sort.Sort(byUpdated(sortedCol))
-			col = sortedCol
-		case "name":
-		default:
-		}
+		col = sortCollection(col, lp.OrderBy[0])
 	}
 
 	for _, item := range col {
@@ -609,23 +648,48 @@ func isCollectionFilename(filename string) bool {
 	return strings.HasSuffix(filename, ".json")
 }
 
-// byUpdate sorts by updated time (commit or run), decending
-type byUpdated []dsref.VersionInfo
-
-func (u byUpdated) Len() int      { return len(u) }
-func (u byUpdated) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
-func (u byUpdated) Less(i, j int) bool {
-	ivi := u[i]
-	jvi := u[j]
-	iTime := ivi.CommitTime
-	jTime := jvi.CommitTime
+// sortCollection returns a copy of a name-ordered collection sorted by an
+// order key, one of "name", "updated" or "size". unrecognized keys leave
+// the collection in name order
+func sortCollection(col []dsref.VersionInfo, order *params.Order) []dsref.VersionInfo {
+	var less func(a, b dsref.VersionInfo) bool
+	switch order.Key {
+	case "updated":
+		less = func(a, b dsref.VersionInfo) bool { return updatedTime(a).Before(updatedTime(b)) }
+	case "size":
+		less = func(a, b dsref.VersionInfo) bool { return a.BodySize < b.BodySize }
+	case "name":
+		// collections are stored in name order
+	default:
+		return col
+	}
+	desc := order.Direction == params.OrderDESC
+	if less == nil && !desc {
+		return col
+	}
+
+	sorted := make([]dsref.VersionInfo, len(col))
+	copy(sorted, col)
+	if less == nil {
+		for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+			sorted[i], sorted[j] = sorted[j], sorted[i]
+		}
+		return sorted
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if desc {
+			return less(sorted[j], sorted[i])
+		}
+		return less(sorted[i], sorted[j])
+	})
+	return sorted
+}
 
-	if ivi.RunStart != nil {
-		iTime = ivi.RunStart.Add(time.Duration(ivi.RunDuration))
-	}
-	if jvi.RunStart != nil {
-		jTime = jvi.RunStart.Add(time.Duration(jvi.RunDuration))
+// updatedTime is the last time a dataset changed, the end of its latest
+// transform run if it has one, its commit time otherwise
+func updatedTime(vi dsref.VersionInfo) time.Time {
+	if vi.RunStart != nil {
+		return vi.RunStart.Add(time.Duration(vi.RunDuration))
 	}
-
-	return iTime.After(jTime)
+	return vi.CommitTime
 }
@@ -225,6 +225,66 @@ func AssertSetSpec(t *testing.T, constructor Constructor) {
 				CommitTime: time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC),
 			},
 		})
+
+		listByUpdatedAsc := params.List{
+			Limit:   -1,
+			OrderBy: params.NewOrderByFromString("+updated"),
+		}
+		assertCollectionList(ctx, t, missPiggy, listByUpdatedAsc, ec, []dsref.VersionInfo{
+			{
+				ProfileID:  missPiggy.ID.Encode(),
+				InitID:     "secret_muppet_friends_init_id",
+				Username:   "miss_piggy",
+				Name:       "secret_muppet_friends",
+				CommitTime: time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				ProfileID:  missPiggy.ID.Encode(),
+				InitID:     "famous_muppets_init_id",
+				Username:   "famous_muppets",
+				Name:       "famous_muppets",
+				CommitTime: time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				ProfileID:   missPiggy.ID.Encode(),
+				InitID:      "muppet_names_init_id",
+				Username:    "kermit",
+				Name:        "muppet_names",
+				CommitTime:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				RunStart:    &muppetNamesRunStart,
+				RunDuration: 1,
+			},
+		})
+
+		listByNameDesc := params.List{
+			Limit:   -1,
+			OrderBy: params.NewOrderByFromString("-name"),
+		}
+		assertCollectionList(ctx, t, missPiggy, listByNameDesc, ec, []dsref.VersionInfo{
+			{
+				ProfileID:  missPiggy.ID.Encode(),
+				InitID:     "secret_muppet_friends_init_id",
+				Username:   "miss_piggy",
+				Name:       "secret_muppet_friends",
+				CommitTime: time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				ProfileID:   missPiggy.ID.Encode(),
+				InitID:      "muppet_names_init_id",
+				Username:    "kermit",
+				Name:        "muppet_names",
+				CommitTime:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				RunStart:    &muppetNamesRunStart,
+				RunDuration: 1,
+			},
+			{
+				ProfileID:  missPiggy.ID.Encode(),
+				InitID:     "famous_muppets_init_id",
+				Username:   "famous_muppets",
+				Name:       "famous_muppets",
+				CommitTime: time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC),
+			},
+		})
 	})
 
 	t.Run("delete", func(t *testing.T) {
@@ -627,6 +687,70 @@ func AssertCollectionEventListenerSpec(t *testing.T, constructor Constructor) {
 		expect = []dsref.VersionInfo{}
 		assertCollectionList(ctx, t, missPiggy, params.ListAll, s, expect)
 	})
+
+	t.Run("user_4_bulk_write", func(t *testing.T) {
+		yolanda := profiletest.GetProfile("yolanda_the_rat")
+		keptInitID := "kept_init_id"
+		droppedInitID := "dropped_init_id"
+
+		mustPublish(ctx, t, bus, event.ETDatasetNameInit, dsref.VersionInfo{
+			InitID:    droppedInitID,
+			ProfileID: yolanda.ID.Encode(),
+			Username:  yolanda.Peername,
+			Name:      "dropped",
+		})
+		mustPublish(ctx, t, bus, event.ETDatasetDownload, droppedInitID)
+
+		// a bulk write adds new datasets & removes deleted ones
+		scopedCtx := profile.AddIDToContext(ctx, yolanda.ID.Encode())
+		mustPublish(scopedCtx, t, bus, event.ETLogbookBulkWrite, event.LogbookBulkWrite{
+			Events:  map[event.Type]int{event.ETDatasetNameInit: 1, event.ETLogbookWriteCommit: 2, event.ETDatasetDeleteAll: 1},
+			InitIDs: []string{keptInitID, droppedInitID},
+			Versions: []dsref.VersionInfo{
+				{
+					InitID:      keptInitID,
+					ProfileID:   yolanda.ID.Encode(),
+					Username:    yolanda.Peername,
+					Name:        "kept",
+					CommitCount: 2,
+					Path:        "/mem/PathToKeptVersionTwo",
+				},
+			},
+			Deleted: []string{droppedInitID},
+		})
+		expect := []dsref.VersionInfo{
+			{
+				InitID:      keptInitID,
+				ProfileID:   yolanda.ID.Encode(),
+				Username:    yolanda.Peername,
+				Name:        "kept",
+				CommitCount: 2,
+				Path:        "/mem/PathToKeptVersionTwo",
+			},
+		}
+		assertCollectionList(ctx, t, yolanda, params.ListAll, s, expect)
+
+		// rebuilt entries keep fields the logbook doesn't track
+		mustPublish(ctx, t, bus, event.ETDatasetDownload, keptInitID)
+		mustPublish(scopedCtx, t, bus, event.ETLogbookBulkWrite, event.LogbookBulkWrite{
+			Events:  map[event.Type]int{event.ETLogbookWriteCommit: 1},
+			InitIDs: []string{keptInitID},
+			Versions: []dsref.VersionInfo{
+				{
+					InitID:      keptInitID,
+					ProfileID:   yolanda.ID.Encode(),
+					Username:    yolanda.Peername,
+					Name:        "kept",
+					CommitCount: 3,
+					Path:        "/mem/PathToKeptVersionThree",
+				},
+			},
+		})
+		expect[0].CommitCount = 3
+		expect[0].Path = "/mem/PathToKeptVersionThree"
+		expect[0].DownloadCount = 1
+		assertCollectionList(ctx, t, yolanda, params.ListAll, s, expect)
+	})
 }
 
 func assertCollectionList(ctx context.Context, t *testing.T, p *profile.Profile, lp params.List, s collection.Set, expect []dsref.VersionInfo) {
@@ -26,6 +26,13 @@ type API struct {
 	ServeRemoteTraffic bool `json:"serveremotetraffic"`
 	// should the api provide the /webui endpoint? default is true
 	Webui bool `json:"webui"`
+	// number of body entries to get when a request doesn't set a limit. zero
+	// uses the default of 25
+	DefaultBodyPageSize int `json:"defaultbodypagesize,omitempty"`
+	// ReadOnly disables api methods that change the repo, like save, remove,
+	// rename & push. read-only servers can expose datasets publicly without
+	// risk of modification
+	ReadOnly bool `json:"readonly,omitempty"`
 }
 
 // SetArbitrary is an interface implementation of base/fill/struct in order to
@@ -56,6 +63,15 @@ func (a API) Validate() error {
         "description": "when true the /webui endpoint will serve a frontend app",
         "type": "boolean"
       },
+      "defaultbodypagesize": {
+        "description": "number of body entries to get when a request doesn't set a limit",
+        "type": "integer",
+        "minimum": 0
+      },
+      "readonly": {
+        "description": "when true the api only serves methods that don't change the repo",
+        "type": "boolean"
+      },
       "serveremotetraffic": {
         "description": "whether to allow requests from addresses other than localhost",
         "type": "boolean"
@@ -87,10 +103,12 @@ func DefaultAPI() *API {
 // Copy returns a deep copy of an API struct
 func (a *API) Copy() *API {
 	res := &API{
-		Enabled:            a.Enabled,
-		Address:            a.Address,
-		ServeRemoteTraffic: a.ServeRemoteTraffic,
-		Webui:              a.Webui,
+		Enabled:             a.Enabled,
+		Address:             a.Address,
+		ServeRemoteTraffic:  a.ServeRemoteTraffic,
+		Webui:               a.Webui,
+		DefaultBodyPageSize: a.DefaultBodyPageSize,
+		ReadOnly:            a.ReadOnly,
 	}
 	if a.AllowedOrigins != nil {
 		res.AllowedOrigins = make([]string, len(a.AllowedOrigins))
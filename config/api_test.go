@@ -21,6 +21,8 @@ func TestAPICopy(t *testing.T) {
 	a.Webui = !a.Webui
 	a.ServeRemoteTraffic = !a.ServeRemoteTraffic
 	a.AllowedOrigins = []string{"bar"}
+	a.DefaultBodyPageSize = 100
+	a.ReadOnly = !a.ReadOnly
 
 	if a.Enabled == b.Enabled {
 		t.Errorf("Enabled fields should not match")
@@ -34,6 +36,12 @@ func TestAPICopy(t *testing.T) {
 	if a.ServeRemoteTraffic == b.ServeRemoteTraffic {
 		t.Errorf("ServeRemoteTraffic fields should not match")
 	}
+	if a.DefaultBodyPageSize == b.DefaultBodyPageSize {
+		t.Errorf("DefaultBodyPageSize fields should not match")
+	}
+	if a.ReadOnly == b.ReadOnly {
+		t.Errorf("ReadOnly fields should not match")
+	}
 	if reflect.DeepEqual(a.AllowedOrigins, b.AllowedOrigins) {
 		t.Errorf("AllowedOrigins fields should not match")
 	}
@@ -31,6 +31,7 @@ type Config struct {
 	P2P         *P2P
 	Automation  *Automation
 	Stats       *Stats
+	Transform   *Transform
 
 	Registry     *Registry
 	Remotes      *Remotes
@@ -216,6 +217,7 @@ func (cfg Config) Validate() error {
 		cfg.API,
 		cfg.Logging,
 		cfg.Automation,
+		cfg.Transform,
 	}
 	for _, val := range validators {
 		// we need to check here because we're potentially calling methods on nil
@@ -274,6 +276,9 @@ func (cfg *Config) Copy() *Config {
 	if cfg.Automation != nil {
 		res.Automation = cfg.Automation.Copy()
 	}
+	if cfg.Transform != nil {
+		res.Transform = cfg.Transform.Copy()
+	}
 	if cfg.Filesystems != nil {
 		for _, fs := range cfg.Filesystems {
 			res.Filesystems = append(res.Filesystems, fs)
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/qri-io/jsonschema"
 )
 
@@ -8,6 +11,32 @@ import (
 type Repo struct {
 	Type string `json:"type"`
 	Path string `json:"path,omitempty"`
+	// DefaultSource is the source dataset methods resolve references against
+	// when neither the caller nor the method specifies one, eg: "local" or
+	// "network". empty uses the default resolver
+	DefaultSource string `json:"defaultSource,omitempty"`
+	// AutoPushOnSave lists remotes to push to after every successful save,
+	// eg: "registry". pushes run in the background & never fail a save
+	AutoPushOnSave []string `json:"autoPushOnSave,omitempty"`
+	// RequiredMeta lists meta fields every dataset must set to be saved, as
+	// dot-separated paths like "title" or "license.type"
+	RequiredMeta []string `json:"requiredMeta,omitempty"`
+	// Retention maps datasets to policies that prune their old versions after
+	// every save. keys are "username/name" references & may use glob
+	// patterns, eg: "me/sensor_*"
+	Retention map[string]*RetentionPolicy `json:"retention,omitempty"`
+}
+
+// RetentionPolicy limits how much of a dataset's history is kept locally.
+// Versions outside the policy are removed from the oldest end of history.
+// The latest version & versions pushed to a remote are always kept
+type RetentionPolicy struct {
+	// KeepVersions is the number of most recent versions to keep, 0 for no
+	// limit
+	KeepVersions int `json:"keepVersions,omitempty"`
+	// MaxAge is a duration like "720h". versions committed longer ago are
+	// removed, empty for no limit
+	MaxAge string `json:"maxAge,omitempty"`
 }
 
 // SetArbitrary is an interface implementation of base/fill/struct in order to safely
@@ -40,16 +69,83 @@ func (cfg Repo) Validate() error {
           "fs",
           "mem"
         ]
+      },
+      "defaultSource": {
+        "description": "Source to resolve dataset references against when a command doesn't specify one",
+        "type": "string"
+      },
+      "autoPushOnSave": {
+        "description": "Names of remotes to push to after every successful save",
+        "type": "array",
+        "items": {
+          "type": "string"
+        }
+      },
+      "requiredMeta": {
+        "description": "Meta fields every dataset must set to be saved",
+        "type": "array",
+        "items": {
+          "type": "string"
+        }
+      },
+      "retention": {
+        "description": "Policies pruning old versions of datasets after every save",
+        "type": "object",
+        "additionalProperties": {
+          "type": "object",
+          "properties": {
+            "keepVersions": {
+              "description": "Number of most recent versions to keep",
+              "type": "integer",
+              "minimum": 0
+            },
+            "maxAge": {
+              "description": "Duration after which versions are removed",
+              "type": "string"
+            }
+          }
+        }
       }
     }
   }`)
-	return validate(schema, &cfg)
+	if err := validate(schema, &cfg); err != nil {
+		return err
+	}
+	for ref, policy := range cfg.Retention {
+		if policy == nil || policy.MaxAge == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(policy.MaxAge); err != nil {
+			return fmt.Errorf("retention policy for %q: invalid maxAge: %w", ref, err)
+		}
+	}
+	return nil
 }
 
 // Copy returns a deep copy of the Repo struct
 func (cfg *Repo) Copy() *Repo {
 	res := &Repo{
-		Type: cfg.Type,
+		Type:          cfg.Type,
+		Path:          cfg.Path,
+		DefaultSource: cfg.DefaultSource,
+	}
+	if cfg.AutoPushOnSave != nil {
+		res.AutoPushOnSave = make([]string, len(cfg.AutoPushOnSave))
+		copy(res.AutoPushOnSave, cfg.AutoPushOnSave)
+	}
+	if cfg.RequiredMeta != nil {
+		res.RequiredMeta = make([]string, len(cfg.RequiredMeta))
+		copy(res.RequiredMeta, cfg.RequiredMeta)
+	}
+	if cfg.Retention != nil {
+		res.Retention = make(map[string]*RetentionPolicy, len(cfg.Retention))
+		for ref, policy := range cfg.Retention {
+			if policy != nil {
+				cpy := *policy
+				policy = &cpy
+			}
+			res.Retention[ref] = policy
+		}
 	}
 
 	return res
@@ -10,12 +10,41 @@ func TestRepoValidate(t *testing.T) {
 	if err != nil {
 		t.Errorf("error validating default repo: %s", err)
 	}
+
+	r := DefaultRepo()
+	r.DefaultSource = "network"
+	if err := r.Validate(); err != nil {
+		t.Errorf("error validating repo with default source: %s", err)
+	}
+
+	r.AutoPushOnSave = []string{"registry"}
+	if err := r.Validate(); err != nil {
+		t.Errorf("error validating repo with auto push remotes: %s", err)
+	}
+
+	r.RequiredMeta = []string{"title", "license.type"}
+	if err := r.Validate(); err != nil {
+		t.Errorf("error validating repo with required meta: %s", err)
+	}
+
+	r.Retention = map[string]*RetentionPolicy{"me/sensor_*": {KeepVersions: 10, MaxAge: "720h"}}
+	if err := r.Validate(); err != nil {
+		t.Errorf("error validating repo with a retention policy: %s", err)
+	}
+	r.Retention["me/sensor_*"].MaxAge = "a month"
+	if err := r.Validate(); err == nil {
+		t.Error("expected a retention policy with an invalid max age to error")
+	}
 }
 
 func TestRepoCopy(t *testing.T) {
 	// build off DefaultRepo so we can test that the repo Copy
 	// actually copies over correctly (ie, deeply)
 	r := DefaultRepo()
+	r.DefaultSource = "network"
+	r.AutoPushOnSave = []string{"registry"}
+	r.RequiredMeta = []string{"title"}
+	r.Retention = map[string]*RetentionPolicy{"me/sensor_*": {KeepVersions: 10}}
 
 	cases := []struct {
 		repo *Repo
@@ -33,5 +62,17 @@ func TestRepoCopy(t *testing.T) {
 			t.Errorf("Repo Copy test case %v, editing one repo struct should not affect the other: \ncopy: %v, \noriginal: %v", i, cpy, c.repo)
 			continue
 		}
+		cpy.AutoPushOnSave[0] = "other"
+		if c.repo.AutoPushOnSave[0] != "registry" {
+			t.Errorf("Repo Copy test case %v, editing copied auto push remotes should not affect the original", i)
+		}
+		cpy.RequiredMeta[0] = "description"
+		if c.repo.RequiredMeta[0] != "title" {
+			t.Errorf("Repo Copy test case %v, editing copied required meta should not affect the original", i)
+		}
+		cpy.Retention["me/sensor_*"].KeepVersions = 2
+		if c.repo.Retention["me/sensor_*"].KeepVersions != 10 {
+			t.Errorf("Repo Copy test case %v, editing copied retention policies should not affect the original", i)
+		}
 	}
 }
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"github.com/qri-io/jsonschema"
+)
+
+// Transform configures the environment transform scripts run in
+type Transform struct {
+	// AllowedHosts restricts the hosts a transform script may make HTTP requests
+	// to. An empty list places no restriction on hosts. Entries are matched
+	// against the request hostname, a leading "*." matches any subdomain
+	AllowedHosts []string `json:"allowedhosts,omitempty"`
+	// MaxMemoryMB caps the memory a transform script may allocate while it
+	// runs, in megabytes. Runs that exceed the cap are aborted. The cap is
+	// approximate: it's measured against process-wide heap growth, sampled
+	// periodically. Zero places no limit on memory
+	MaxMemoryMB int `json:"maxmemorymb,omitempty"`
+}
+
+// SetArbitrary is an interface implementation of base/fill/struct in order to safely
+// consume config files that have definitions beyond those specified in the struct.
+// This simply ignores all additional fields at read time.
+func (cfg *Transform) SetArbitrary(key string, val interface{}) error {
+	return nil
+}
+
+// DefaultTransform creates & returns a new default transform configuration
+func DefaultTransform() *Transform {
+	return &Transform{}
+}
+
+// Validate validates all the fields of transform returning all errors found.
+func (cfg Transform) Validate() error {
+	schema := jsonschema.Must(`{
+    "$schema": "http://json-schema.org/draft-06/schema#",
+    "title": "Transform",
+    "description": "Config for running transform scripts",
+    "type": "object",
+    "properties": {
+      "allowedhosts": {
+        "description": "Hosts transform scripts may make HTTP requests to. Empty allows all hosts",
+        "type": "array",
+        "items": {
+          "type": "string",
+          "minLength": 1
+        }
+      },
+      "maxmemorymb": {
+        "description": "Memory transform scripts may allocate, in megabytes. Zero places no limit",
+        "type": "integer",
+        "minimum": 0
+      }
+    }
+  }`)
+	return validate(schema, &cfg)
+}
+
+// Copy returns a deep copy of the Transform struct
+func (cfg *Transform) Copy() *Transform {
+	res := &Transform{
+		MaxMemoryMB: cfg.MaxMemoryMB,
+	}
+	if cfg.AllowedHosts != nil {
+		res.AllowedHosts = make([]string, len(cfg.AllowedHosts))
+		copy(res.AllowedHosts, cfg.AllowedHosts)
+	}
+	return res
+}
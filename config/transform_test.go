@@ -0,0 +1,46 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTransformValidate(t *testing.T) {
+	err := DefaultTransform().Validate()
+	if err != nil {
+		t.Errorf("error validating default transform: %s", err)
+	}
+
+	cfg := &Transform{AllowedHosts: []string{""}}
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("expected empty allowed host to error")
+	}
+
+	cfg = &Transform{MaxMemoryMB: -1}
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("expected negative memory limit to error")
+	}
+}
+
+func TestTransformCopy(t *testing.T) {
+	cases := []struct {
+		transform *Transform
+	}{
+		{DefaultTransform()},
+		{&Transform{AllowedHosts: []string{"api.example.com", "*.qri.io"}}},
+		{&Transform{MaxMemoryMB: 256}},
+	}
+	for i, c := range cases {
+		cpy := c.transform.Copy()
+		if !reflect.DeepEqual(cpy, c.transform) {
+			t.Errorf("Transform Copy test case %v, transform structs are not equal: \ncopy: %v, \noriginal: %v", i, cpy, c.transform)
+			continue
+		}
+		if len(cpy.AllowedHosts) > 0 {
+			cpy.AllowedHosts[0] = "changed"
+			if c.transform.AllowedHosts[0] == "changed" {
+				t.Errorf("Transform Copy test case %v, copy shares AllowedHosts with original", i)
+			}
+		}
+	}
+}
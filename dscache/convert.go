@@ -242,7 +242,10 @@ func convertHistoryToIndexAndRef(historyLog oplog.Log) (int, string) {
 	refs := make([]string, 0, len(historyLog.Ops))
 	// Collect references added and removed to get those that remain.
 	for _, op := range historyLog.Ops {
-		if op.Type == oplog.OpTypeRemove {
+		if logbook.IsSquashOp(op) {
+			// squashes remove versions from the start of history
+			refs = dropOldestRefs(refs, int(op.Size))
+		} else if op.Type == oplog.OpTypeRemove {
 			refs = refs[0 : len(refs)-int(op.Size)]
 		} else {
 			refs = append(refs, op.Ref)
@@ -260,6 +263,20 @@ func convertHistoryToIndexAndRef(historyLog oplog.Log) (int, string) {
 	return lastIndex, lastRef
 }
 
+// dropOldestRefs removes the first n version references, skipping entries
+// without a reference
+func dropOldestRefs(refs []string, n int) []string {
+	kept := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if n > 0 && ref != "" {
+			n--
+			continue
+		}
+		kept = append(kept, ref)
+	}
+	return kept
+}
+
 func findMatchingInfo(ref reporef.DatasetRef, entryInfoList []*entryInfo) *entryInfo {
 	for _, info := range entryInfoList {
 		if info == nil {
@@ -409,6 +409,56 @@ func TestConvertLogbookAndRefsWithNoHistoryDatasetAndDeletedDataset(t *testing.T
 	}
 }
 
+// Test that squashed versions are dropped from the start of history
+func TestConvertLogbookAndRefsSquashed(t *testing.T) {
+	run := NewDscacheTestRunner()
+	defer run.Delete()
+
+	ctx := context.Background()
+
+	keyData := testkeys.GetKeyData(0)
+	rootPath, err := ioutil.TempDir("", "logbook_squash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pro, err := profile.NewSparsePKProfile("test_user", keyData.PrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	builder := logbook.NewLogbookTempBuilder(t, pro, qfs.NewMemFS(), rootPath)
+	id := builder.DatasetInit(ctx, t, "squashed_ds")
+	builder.Commit(ctx, t, id, "initial commit", "QmHashOfVersion1")
+	builder.Commit(ctx, t, id, "second commit", "QmHashOfVersion2")
+	builder.Commit(ctx, t, id, "third commit", "QmHashOfVersion3")
+	book := builder.Logbook()
+	if err := book.WriteVersionSquash(ctx, pro, id, 2, "QmHashOfVersion3"); err != nil {
+		t.Fatal(err)
+	}
+
+	dsrefs := []reporef.DatasetRef{
+		{
+			Peername:  "test_user",
+			ProfileID: profile.IDFromPeerID(keyData.PeerID),
+			Name:      "squashed_ds",
+			Path:      "QmHashOfVersion3",
+		},
+	}
+	entryInfoList, err := convertLogbookAndRefs(ctx, book, dsrefs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entryInfoList) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entryInfoList))
+	}
+	got := entryInfoList[0]
+	if got.Path != "QmHashOfVersion3" {
+		t.Errorf("expected squashed head to be the newest version, got: %q", got.Path)
+	}
+	if got.TopIndex != 1 {
+		t.Errorf("expected top index 1 after squashing 2 of 3 versions, got: %d", got.TopIndex)
+	}
+}
+
 // Test the top-level build function, and that the references are alphabetized
 func TestBuildDscacheFromLogbookAndProfilesAndDsrefAlphabetized(t *testing.T) {
 	run := NewDscacheTestRunner()
@@ -60,7 +60,8 @@ func NewDscache(ctx context.Context, fsys qfs.Filesystem, bus event.Bus, usernam
 		event.ETLogbookWriteCommit,
 		event.ETDatasetDeleteAll,
 		event.ETDatasetRename,
-		event.ETDatasetCreateLink)
+		event.ETDatasetCreateLink,
+		event.ETLogbookBulkWrite)
 
 	return &cache
 }
@@ -299,11 +300,60 @@ func (d *Dscache) handler(_ context.Context, e event.Event) error {
 		}
 	case event.ETDatasetRename:
 		// TODO(dustmop): Handle renames
+	case event.ETLogbookBulkWrite:
+		bw, ok := e.Payload.(event.LogbookBulkWrite)
+		if !ok {
+			log.Error("dscache got an event with a payload that isn't a event.LogbookBulkWrite type: %v", e.Payload)
+			return nil
+		}
+		if err := d.updateBulkWrite(bw); err != nil && err != ErrNoDscache {
+			log.Error(err)
+		}
 	}
 
 	return nil
 }
 
+// updateBulkWrite rebuilds the entries of the datasets a logbook bulk write
+// touched, adding datasets that were created & removing ones that were deleted
+func (d *Dscache) updateBulkWrite(bw event.LogbookBulkWrite) error {
+	for _, act := range bw.Versions {
+		if !d.hasInitID(act.InitID) {
+			if err := d.updateInitDataset(act); err != nil {
+				return err
+			}
+		}
+		if act.CommitCount > 0 {
+			if err := d.updateChangeCursor(act); err != nil {
+				return err
+			}
+		}
+	}
+	for _, initID := range bw.Deleted {
+		if d.hasInitID(initID) {
+			if err := d.updateDeleteDataset(initID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hasInitID returns whether the dscache has an entry for a dataset
+func (d *Dscache) hasInitID(initID string) bool {
+	if d.IsEmpty() {
+		return false
+	}
+	r := dscachefb.RefEntryInfo{}
+	for i := 0; i < d.Root.RefsLength(); i++ {
+		d.Root.Refs(&r, i)
+		if string(r.InitID()) == initID {
+			return true
+		}
+	}
+	return false
+}
+
 func (d *Dscache) updateInitDataset(act dsref.VersionInfo) error {
 	if d.IsEmpty() {
 		// Only create a new dscache if that feature is enabled. This way no one is forced to
@@ -335,7 +385,7 @@ func (d *Dscache) updateInitDataset(act dsref.VersionInfo) error {
 		builder.AddUser(string(up.Username()), string(up.ProfileID()))
 	}
 	// copy ds versions
-	for i := 0; i < d.Root.UsersLength(); i++ {
+	for i := 0; i < d.Root.RefsLength(); i++ {
 		r := dscachefb.RefEntryInfo{}
 		d.Root.Refs(&r, i)
 		builder.AddDsVersionInfoWithIndexes(convertEntryToVersionInfo(&r), int(r.TopIndex()), int(r.CursorIndex()))
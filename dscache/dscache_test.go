@@ -8,9 +8,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qfs/localfs"
 	testkeys "github.com/qri-io/qri/auth/key/test"
+	"github.com/qri-io/qri/dscache/dscachefb"
 	"github.com/qri-io/qri/dsref"
 	dsrefspec "github.com/qri-io/qri/dsref/spec"
 	"github.com/qri-io/qri/event"
@@ -80,6 +82,46 @@ func TestDscacheAssignSaveAndLoad(t *testing.T) {
 	}
 }
 
+func TestDscacheBulkWrite(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := event.NewBus(ctx)
+
+	profileID := profile.IDFromPeerID(testkeys.GetKeyData(0).PeerID).Encode()
+	builder := NewBuilder()
+	builder.AddUser("test_user", profileID)
+	builder.AddDsVersionInfo(dsref.VersionInfo{InitID: "abcd1", ProfileID: profileID, Name: "kept"})
+	builder.AddDsVersionInfo(dsref.VersionInfo{InitID: "efgh2", ProfileID: profileID, Name: "dropped"})
+	cache := NewDscache(ctx, qfs.NewMemFS(), bus, "test_user", "")
+	cache.Assign(builder.Build())
+
+	err := bus.Publish(ctx, event.ETLogbookBulkWrite, event.LogbookBulkWrite{
+		InitIDs: []string{"abcd1", "ijkl3", "efgh2"},
+		Versions: []dsref.VersionInfo{
+			{InitID: "abcd1", ProfileID: profileID, Username: "test_user", Name: "kept", CommitCount: 2, Path: "/mem/QmVersionTwo"},
+			{InitID: "ijkl3", ProfileID: profileID, Username: "test_user", Name: "added"},
+		},
+		Deleted: []string{"efgh2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{}
+	r := dscachefb.RefEntryInfo{}
+	for i := 0; i < cache.Root.RefsLength(); i++ {
+		cache.Root.Refs(&r, i)
+		got[string(r.InitID())] = string(r.HeadRef())
+	}
+	expect := map[string]string{
+		"abcd1": "/mem/QmVersionTwo",
+		"ijkl3": "",
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("dscache refs mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestResolveRef(t *testing.T) {
 	tmpdir, err := ioutil.TempDir("", "")
 	if err != nil {
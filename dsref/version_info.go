@@ -51,6 +51,11 @@ type VersionInfo struct {
 	// If true, this reference doesn't exist locally. Only makes sense if path is set, as this
 	// flag refers to specific versions, not to entire dataset histories.
 	Foreign bool `json:"foreign,omitempty"`
+	// If true, this version was deleted from history. Deleted versions are
+	// only listed when asked for, for auditing
+	Deleted bool `json:"deleted,omitempty"`
+	// DeleteTime is when a deleted version was removed, if known
+	DeleteTime *time.Time `json:"deleteTime,omitempty"`
 	//
 	// Meta fields
 	//
@@ -105,6 +110,10 @@ type VersionInfo struct {
 	// RunStart is the start time of the run. It is not stored on a dataset version
 	// and instead must come from either run state or logbook
 	RunStart *time.Time `json:"runStart,omitempty"`
+	// RunTrigger describes what started the run, eg: "manual" or "scheduled".
+	// It is not stored on a dataset version and instead must come from either
+	// run state or logbook
+	RunTrigger string `json:"runTrigger,omitempty"`
 	//
 	//
 	// Aggregate Fields
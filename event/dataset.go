@@ -28,6 +28,10 @@ const (
 	// ETDatasetSaveCompleted occurs when a dataset save finishes
 	// payload will be a DsSaveEvent
 	ETDatasetSaveCompleted = Type("dataset:SaveCompleted")
+	// ETDatasetAutoPushCompleted occurs when a background push configured by
+	// repo.autoPushOnSave finishes, successfully or not
+	// payload will be a RemoteEvent, with Error populated if the push failed
+	ETDatasetAutoPushCompleted = Type("dataset:AutoPushCompleted")
 )
 
 // DsRename encapsulates fields from a dataset rename
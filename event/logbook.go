@@ -1,5 +1,7 @@
 package event
 
+import "github.com/qri-io/qri/dsref"
+
 const (
 	// ETLogbookWriteCommit occurs when the logbook writes an op of model
 	// `CommitModel`, indicating that a new dataset version has been saved
@@ -9,4 +11,23 @@ const (
 	// `RunModel`, indicating that a new run of a dataset has occured
 	// payload is a dsref.VersionInfo
 	ETLogbookWriteRun = Type("logbook:WriteRun")
+	// ETLogbookBulkWrite occurs at the end of a quiet period, when the logbook
+	// held back events during a bulk operation
+	// payload is a LogbookBulkWrite
+	ETLogbookBulkWrite = Type("logbook:BulkWrite")
 )
+
+// LogbookBulkWrite summarizes the events held back during a quiet period
+type LogbookBulkWrite struct {
+	// Events counts held back events by type
+	Events map[Type]int `json:"events"`
+	// InitIDs lists the datasets the held back events were about, in the
+	// order they were first seen
+	InitIDs []string `json:"initIDs"`
+	// Versions holds the head version of each listed dataset that still exists
+	// when the quiet period ends. subscribers rebuild their state for these
+	// datasets instead of replaying held back events
+	Versions []dsref.VersionInfo `json:"versions"`
+	// Deleted lists datasets that were removed by the end of the quiet period
+	Deleted []string `json:"deleted"`
+}
@@ -1,5 +1,7 @@
 package event
 
+import "encoding/json"
+
 const (
 	// ETTransformStart signals the start a transform execution
 	// Payload will be a TransformLifecycle
@@ -27,6 +29,10 @@ const (
 	// ETTransformDatasetPreview is an abbreviated dataset document in a transform
 	// Payload will be a *dataset.Dataset Preview
 	ETTransformDatasetPreview = Type("tf:DatasetPreview")
+	// ETTransformCheckpoint records intermediate state a transform can resume
+	// from.
+	// Payload will be a TransformCheckpoint
+	ETTransformCheckpoint = Type("tf:Checkpoint")
 
 	// ETTransformCanceled is for when a transform is canceled before
 	// it can complete its run
@@ -55,6 +61,12 @@ type TransformStepLifecycle struct {
 	Mode     string `json:"mode,omitempty"`
 }
 
+// TransformCheckpoint is the payload for checkpoint events, carrying the
+// json-encoded state a transform script asked to persist
+type TransformCheckpoint struct {
+	State json.RawMessage `json:"state"`
+}
+
 // TransformMsgLvl is an enumeration of all possible degrees of message
 // logging in an implicit hierarchy (levels)
 type TransformMsgLvl string
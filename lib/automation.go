@@ -1,10 +1,16 @@
 package lib
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/preview"
@@ -12,8 +18,11 @@ import (
 	"github.com/qri-io/qri/automation"
 	"github.com/qri-io/qri/automation/run"
 	"github.com/qri-io/qri/automation/workflow"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/base/dsfs"
+	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/dsref"
+	qerr "github.com/qri-io/qri/errors"
 	"github.com/qri-io/qri/event"
 	qhttp "github.com/qri-io/qri/lib/http"
 	"github.com/qri-io/qri/transform"
@@ -33,13 +42,16 @@ func (m AutomationMethods) Name() string {
 // Attributes defines attributes for each method
 func (m AutomationMethods) Attributes() map[string]AttributeSet {
 	return map[string]AttributeSet{
-		"apply":    {Endpoint: qhttp.AEApply, HTTPVerb: "POST"},
-		"deploy":   {Endpoint: qhttp.AEDeploy, HTTPVerb: "POST", DefaultSource: "local"},
-		"run":      {Endpoint: qhttp.AERun, HTTPVerb: "POST"},
-		"runinfo":  {Endpoint: qhttp.AERunInfo, HTTPVerb: "POST"},
-		"workflow": {Endpoint: qhttp.AEWorkflow, HTTPVerb: "POST"},
-		"remove":   {Endpoint: qhttp.AERemoveWorkflow, HTTPVerb: "POST"},
-		"cancel":   {Endpoint: qhttp.AECancel, HTTPVerb: "POST"},
+		"apply":           {Endpoint: qhttp.AEApply, HTTPVerb: "POST"},
+		"applybatch":      {Endpoint: qhttp.AEApplyBatch, HTTPVerb: "POST", DefaultSource: "local"},
+		"verifytransform": {Endpoint: qhttp.AEVerifyTransform, HTTPVerb: "POST", DefaultSource: "local"},
+		"listtransforms":  {Endpoint: qhttp.AEListTransforms, HTTPVerb: "POST", DefaultSource: "local", ReadOnly: true},
+		"deploy":          {Endpoint: qhttp.AEDeploy, HTTPVerb: "POST", DefaultSource: "local"},
+		"run":             {Endpoint: qhttp.AERun, HTTPVerb: "POST"},
+		"runinfo":         {Endpoint: qhttp.AERunInfo, HTTPVerb: "POST", ReadOnly: true},
+		"workflow":        {Endpoint: qhttp.AEWorkflow, HTTPVerb: "POST", ReadOnly: true},
+		"remove":          {Endpoint: qhttp.AERemoveWorkflow, HTTPVerb: "POST"},
+		"cancel":          {Endpoint: qhttp.AECancel, HTTPVerb: "POST"},
 
 		// NOTE: Temporary undocumented command for using the static analyzer
 		"analyzetransform": {Endpoint: qhttp.DenyHTTP},
@@ -58,6 +70,9 @@ type ApplyParams struct {
 	// size of the output area that the results will display on
 	OutputWidth  int `json:"outputWidth"`
 	OutputHeight int `json:"outputHeight"`
+	// ID of a previous run to resume. The transform script can access the
+	// state that run last checkpointed with qri.resume_state()
+	ResumeRunID string `json:"resumeRunID"`
 }
 
 // Validate returns an error if ApplyParams fields are in an invalid state
@@ -83,6 +98,151 @@ func (m AutomationMethods) Apply(ctx context.Context, p *ApplyParams) (*ApplyRes
 	return nil, dispatchReturnError(got, err)
 }
 
+// ApplyBatchParams are parameters for applying a transform to many datasets
+type ApplyBatchParams struct {
+	// RefPattern selects datasets by name with glob syntax; e.g. "me/raw_*"
+	RefPattern string             `json:"refPattern"`
+	Transform  *dataset.Transform `json:"transform"`
+	Secrets    map[string]string  `json:"secrets"`
+	// ScriptOutput receives print output from each dataset's transform. it's
+	// only available in-process
+	ScriptOutput io.Writer `json:"-"`
+}
+
+// Validate returns an error if ApplyBatchParams fields are in an invalid state
+func (p *ApplyBatchParams) Validate() error {
+	if p.RefPattern == "" {
+		return fmt.Errorf("ref pattern is required")
+	}
+	if p.Transform == nil {
+		return fmt.Errorf("transform is required")
+	}
+	if _, err := path.Match(p.RefPattern, ""); err != nil {
+		return fmt.Errorf("invalid ref pattern %q: %w", p.RefPattern, err)
+	}
+	return nil
+}
+
+// ApplyBatchResult is the outcome of applying a transform to one dataset in a
+// batch. Error is set if the dataset failed to save
+type ApplyBatchResult struct {
+	Ref   string `json:"ref"`
+	Path  string `json:"path,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ApplyBatch runs a transform script against every local dataset with a name
+// matching a pattern, saving a new version of each. A failure applying to one
+// dataset doesn't stop the batch, and is reported in that dataset's result
+func (m AutomationMethods) ApplyBatch(ctx context.Context, p *ApplyBatchParams) ([]ApplyBatchResult, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "applybatch"), p)
+	if res, ok := got.([]ApplyBatchResult); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+const (
+	// TransformSortName orders listed transforms by dataset name
+	TransformSortName = ""
+	// TransformSortRecent orders listed transforms by most recent run first
+	TransformSortRecent = "recent"
+	// TransformSortFailed orders listed transforms by most recent failure
+	// first, followed by datasets whose transforms haven't failed
+	TransformSortFailed = "failed"
+)
+
+// ListTransformsParams are parameters for listing transform-driven datasets
+type ListTransformsParams struct {
+	// Sort orders the list, one of "", "recent" or "failed"
+	Sort string `json:"sort"`
+}
+
+// Validate returns an error if ListTransformsParams fields are in an invalid
+// state
+func (p *ListTransformsParams) Validate() error {
+	switch p.Sort {
+	case TransformSortName, TransformSortRecent, TransformSortFailed:
+		return nil
+	}
+	return fmt.Errorf("unknown sort %q, expected one of recent, failed", p.Sort)
+}
+
+// TransformStatus describes a dataset with a transform & its most recent run,
+// read from the run records of the dataset's logbook
+type TransformStatus struct {
+	Ref string `json:"ref"`
+	// RunCount is the number of recorded runs
+	RunCount int `json:"runCount"`
+	// LastRunID, LastRunStatus, LastRunStart & LastRunDuration describe the
+	// most recent run. they're empty if the transform hasn't been run
+	LastRunID     string     `json:"lastRunID,omitempty"`
+	LastRunStatus string     `json:"lastRunStatus,omitempty"`
+	LastRunStart  *time.Time `json:"lastRunStart,omitempty"`
+	// LastRunDuration is in nanoseconds
+	LastRunDuration int64 `json:"lastRunDuration,omitempty"`
+	// LastFailure is the start time of the most recent failed run
+	LastFailure *time.Time `json:"lastFailure,omitempty"`
+}
+
+// ListTransforms lists local datasets that have a transform or a record of
+// running one, with the status of each dataset's most recent run
+func (m AutomationMethods) ListTransforms(ctx context.Context, p *ListTransformsParams) ([]TransformStatus, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "listtransforms"), p)
+	if res, ok := got.([]TransformStatus); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// ErrUnrecordedInput indicates a replayed transform loaded a dataset the
+// version being verified didn't record as an input
+var ErrUnrecordedInput = errors.New("transform loaded a dataset that wasn't recorded as an input")
+
+// VerifyTransformParams are parameters for verifying a transform reproduces
+// the body of a saved version
+type VerifyTransformParams struct {
+	// Ref is the dataset version to verify, defaults to the latest version
+	Ref     string            `json:"ref"`
+	Secrets map[string]string `json:"secrets"`
+	// ScriptOutput receives print output from the replayed transform. it's
+	// only available in-process
+	ScriptOutput io.Writer `json:"-"`
+}
+
+// Validate returns an error if VerifyTransformParams fields are in an invalid
+// state
+func (p *VerifyTransformParams) Validate() error {
+	if p.Ref == "" {
+		return fmt.Errorf("reference is required")
+	}
+	return nil
+}
+
+// VerifyTransformResult is the outcome of replaying a transform
+type VerifyTransformResult struct {
+	// Ref is the verified version, including its path
+	Ref string `json:"ref"`
+	// Inputs are the versioned references the transform was replayed against
+	Inputs []string `json:"inputs"`
+	// Match is true when the replayed body matches the stored body
+	Match bool `json:"match"`
+	// Mismatch describes how the bodies differ when they don't match
+	Mismatch string `json:"mismatch,omitempty"`
+}
+
+// VerifyTransform re-runs the transform stored in a dataset version against
+// the input versions it originally loaded, without saving, & checks the
+// output matches the stored body. A mismatch means the transform isn't
+// deterministic, or reads inputs it didn't record
+func (m AutomationMethods) VerifyTransform(ctx context.Context, p *VerifyTransformParams) (*VerifyTransformResult, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "verifytransform"), p)
+	if res, ok := got.(*VerifyTransformResult); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
 // DeployParams are parameters for the deploy command
 type DeployParams struct {
 	Run      bool // when Run is true, run the workflow after updating the dataset and workflow
@@ -115,6 +275,9 @@ type RunParams struct {
 	Ref        string `json:"ref"`
 	InitID     string `json:"initID"`
 	WorkflowID string `json:"workflowID"`
+	// ID of a previous run to resume. The transform script can access the
+	// state that run last checkpointed with qri.resume_state()
+	ResumeRunID string `json:"resumeRunID"`
 }
 
 // Validate returns an error if RunParams fields are in an invalid state
@@ -278,12 +441,23 @@ func (automationImpl) Apply(scope scope, p *ApplyParams) (*ApplyResult, error) {
 		OutputWidth:  p.OutputWidth,
 		OutputHeight: p.OutputHeight,
 	}
+	if p.ResumeRunID != "" {
+		state, err := scope.AutomationOrchestrator().ResumeState(scope.Context(), p.ResumeRunID)
+		if err != nil {
+			return nil, err
+		}
+		params.ResumeState = state
+	}
 
 	runID, err := scope.AutomationOrchestrator().ApplyWorkflow(ctx, p.Wait, p.ScriptOutput, wf, ds, params)
 	if err != nil {
 		return nil, err
 	}
 
+	if !ref.IsEmpty() {
+		scope.inst.ops.setRef(runID, OpTypeApply, ref.Alias())
+	}
+
 	res := &ApplyResult{}
 	if p.Wait {
 		ds, err := preview.Create(scope.Context(), ds)
@@ -451,6 +625,14 @@ func (automationImpl) Run(scope scope, p *RunParams) (string, error) {
 		return "", fmt.Errorf("profile %s can not write to dataset %s", scope.ActiveProfile().ID.Encode(), p.InitID)
 	}
 	runID := run.NewID()
+	if p.ResumeRunID != "" {
+		// check the run to resume has a checkpoint before starting
+		if _, err := scope.AutomationOrchestrator().ResumeState(scope.Context(), p.ResumeRunID); err != nil {
+			return "", err
+		}
+		go scope.AutomationOrchestrator().ResumeWorkflow(scope.AppContext(), workflow.ID(p.WorkflowID), runID, p.ResumeRunID)
+		return runID, nil
+	}
 	go scope.AutomationOrchestrator().RunWorkflow(scope.AppContext(), workflow.ID(p.WorkflowID), runID)
 	return runID, nil
 }
@@ -531,7 +713,9 @@ func (inst *Instance) run(ctx context.Context, streams ioes.IOStreams, w *workfl
 				RunID: runID,
 			},
 		},
-		Apply: true,
+		Apply:       true,
+		RunTrigger:  params.Trigger,
+		ResumeState: params.ResumeState,
 	}
 	dImpl := &datasetImpl{}
 	_, err = dImpl.Save(scope, p)
@@ -550,9 +734,320 @@ func (inst *Instance) apply(ctx context.Context, wait bool, runID string, wf *wo
 	}
 
 	transformer := transform.NewTransformer(ctx, scope.Filesystem(), scope.Loader(), scope.Bus(), sizeInfo)
+	transformer.SetAllowedHosts(transformAllowedHosts(scope.Config()))
+	transformer.SetMaxMemoryMB(transformMaxMemoryMB(scope.Config()))
+	transformer.SetResumeState(params.ResumeState)
+	transformer.SetSecretProviders(scope.SecretProviders())
+	transformer.SetStatsLoader(transformStatsLoader(scope))
 	return transformer.Apply(scope.Context(), ds, runID, wait, params.Secrets)
 }
 
+// transformAllowedHosts returns the configured list of hosts transforms may
+// make http requests to, nil if no restriction is configured
+func transformAllowedHosts(cfg *config.Config) []string {
+	if cfg == nil || cfg.Transform == nil {
+		return nil
+	}
+	return cfg.Transform.AllowedHosts
+}
+
+// transformMaxMemoryMB returns the configured cap on transform memory use in
+// megabytes, zero if no limit is configured
+func transformMaxMemoryMB(cfg *config.Config) int {
+	if cfg == nil || cfg.Transform == nil {
+		return 0
+	}
+	return cfg.Transform.MaxMemoryMB
+}
+
+// transformStatsLoader loads the stats of a stored version for
+// qri.prev_stats(), computing them with the stats service if the version
+// didn't store a stats component
+func transformStatsLoader(scope scope) transform.StatsLoader {
+	return func(ctx context.Context, path string) (*dataset.Stats, error) {
+		fs := scope.Filesystem()
+		ds, err := dsfs.LoadDataset(ctx, fs, path)
+		if err != nil {
+			return nil, err
+		}
+		if ds.Stats == nil {
+			if err := base.OpenDataset(ctx, fs, ds); err != nil {
+				return nil, err
+			}
+		}
+		return scope.Stats().Stats(ctx, ds)
+	}
+}
+
+// ApplyBatch runs a transform against all datasets matching a pattern
+func (automationImpl) ApplyBatch(scope scope, p *ApplyBatchParams) ([]ApplyBatchResult, error) {
+	refs, err := matchDatasetRefs(scope, p.RefPattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("no datasets match %q", p.RefPattern)
+	}
+
+	res := make([]ApplyBatchResult, 0, len(refs))
+	for _, ref := range refs {
+		r := ApplyBatchResult{Ref: ref.Human()}
+
+		// each save reads the transform script, so give every save its own copy
+		tf := &dataset.Transform{}
+		tf.Assign(p.Transform)
+		saveParams := &SaveParams{
+			Ref:                 ref.Human(),
+			Dataset:             &dataset.Dataset{Transform: tf},
+			Secrets:             p.Secrets,
+			ScriptOutput:        p.ScriptOutput,
+			Apply:               true,
+			ConvertFormatToPrev: true,
+		}
+
+		ds, err := datasetImpl{}.Save(scope, saveParams)
+		if err != nil {
+			log.Debugw("apply batch save", "ref", r.Ref, "err", err)
+			r.Error = err.Error()
+		} else {
+			r.Path = ds.Path
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// ListTransforms lists datasets with transforms & their last run
+func (automationImpl) ListTransforms(scope scope, p *ListTransformsParams) ([]TransformStatus, error) {
+	ctx := scope.Context()
+	refs, err := scope.Logbook().DatasetRefs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []TransformStatus{}
+	for _, ref := range refs {
+		runs, err := scope.Logbook().Items(ctx, ref, 0, -1, "run")
+		if err != nil {
+			log.Debugw("list transforms reading runs", "ref", ref.Human(), "err", err)
+			continue
+		}
+		if len(runs) == 0 && !headHasTransform(scope, ref) {
+			continue
+		}
+
+		st := TransformStatus{Ref: ref.Human(), RunCount: len(runs)}
+		if len(runs) > 0 {
+			last := runs[0]
+			st.LastRunID = last.RunID
+			st.LastRunStatus = last.RunStatus
+			st.LastRunStart = last.RunStart
+			st.LastRunDuration = last.RunDuration
+		}
+		for _, r := range runs {
+			if r.RunStatus == string(run.RSFailed) {
+				st.LastFailure = r.RunStart
+				break
+			}
+		}
+		res = append(res, st)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Ref < res[j].Ref })
+	switch p.Sort {
+	case TransformSortRecent:
+		sort.SliceStable(res, func(i, j int) bool { return timeAfter(res[i].LastRunStart, res[j].LastRunStart) })
+	case TransformSortFailed:
+		sort.SliceStable(res, func(i, j int) bool { return timeAfter(res[i].LastFailure, res[j].LastFailure) })
+	}
+	return res, nil
+}
+
+// headHasTransform reports if the latest version of a dataset has a transform
+func headHasTransform(scope scope, ref dsref.Ref) bool {
+	ds, err := scope.Loader().LoadDataset(scope.Context(), ref.Human())
+	if err != nil {
+		log.Debugw("list transforms loading head", "ref", ref.Human(), "err", err)
+		return false
+	}
+	return ds.Transform != nil
+}
+
+// timeAfter orders times newest first, placing nil times last
+func timeAfter(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a != nil
+	}
+	return a.After(*b)
+}
+
+// VerifyTransform replays the transform of a dataset version
+func (automationImpl) VerifyTransform(scope scope, p *VerifyTransformParams) (*VerifyTransformResult, error) {
+	ctx := scope.Context()
+	ref, _, err := scope.ParseAndResolveRef(ctx, p.Ref)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Path == "" {
+		return nil, qerr.New(dsref.ErrNoHistory, fmt.Sprintf("can't verify %q, it has no saved versions", ref.Human()))
+	}
+
+	ds, err := dsfs.LoadDataset(ctx, scope.Filesystem(), ref.Path)
+	if err != nil {
+		return nil, err
+	}
+	if ds.Transform == nil {
+		return nil, fmt.Errorf("version %s has no transform to verify", ref.Path)
+	}
+	if err := base.OpenDataset(ctx, scope.Filesystem(), ds); err != nil {
+		return nil, err
+	}
+	expect, err := base.GetBody(ds, -1, 0, true)
+	if err != nil {
+		return nil, fmt.Errorf("reading stored body: %w", err)
+	}
+
+	res := &VerifyTransformResult{
+		Ref:    dsref.Ref{Username: ref.Username, Name: ref.Name, Path: ref.Path}.String(),
+		Inputs: []string{},
+	}
+	// pin the dataset itself to the version the transform was applied to, and
+	// every loaded dataset to the version the transform recorded
+	pins := map[string]string{ref.Alias(): ""}
+	if ds.PreviousPath != "" {
+		pins[ref.Alias()] = fmt.Sprintf("%s@%s", ref.Alias(), ds.PreviousPath)
+	}
+	for _, rsc := range ds.Transform.Resources {
+		input, err := dsref.Parse(rsc.Path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transform resource %q: %w", rsc.Path, err)
+		}
+		pins[input.Alias()] = rsc.Path
+		res.Inputs = append(res.Inputs, rsc.Path)
+	}
+	sort.Strings(res.Inputs)
+
+	loader := &pinnedLoader{
+		loader:    scope.Loader(),
+		userOwner: scope.ActiveProfile().Peername,
+		pins:      pins,
+	}
+
+	tf := &dataset.Transform{}
+	tf.Assign(ds.Transform)
+	tf.Resources = nil
+	target := &dataset.Dataset{
+		Peername:  ref.Username,
+		Name:      ref.Name,
+		ID:        ref.InitID,
+		Transform: tf,
+	}
+
+	runID := run.NewID()
+	if p.ScriptOutput != nil {
+		scope.Bus().SubscribeID(func(ctx context.Context, e event.Event) error {
+			if msg, ok := e.Payload.(event.TransformMessage); ok && e.Type == event.ETTransformPrint {
+				io.WriteString(p.ScriptOutput, msg.Msg)
+				io.WriteString(p.ScriptOutput, "\n")
+			}
+			return nil
+		}, runID)
+	}
+
+	transformer := transform.NewTransformer(scope.AppContext(), scope.Filesystem(), loader, scope.Bus(), transform.SizeInfo{})
+	transformer.SetAllowedHosts(transformAllowedHosts(scope.Config()))
+	transformer.SetMaxMemoryMB(transformMaxMemoryMB(scope.Config()))
+	transformer.SetSecretProviders(scope.SecretProviders())
+	if err := transformer.Verify(ctx, ref.InitID, target, runID, p.Secrets); err != nil {
+		return nil, err
+	}
+
+	if target.BodyFile() == nil {
+		if err := target.OpenBodyFile(ctx, scope.Filesystem()); err != nil {
+			return nil, fmt.Errorf("opening replayed body: %w", err)
+		}
+	}
+	got, err := base.GetBody(target, -1, 0, true)
+	if err != nil {
+		return nil, fmt.Errorf("reading replayed body: %w", err)
+	}
+	res.Mismatch, err = bodyMismatch(expect, got)
+	if err != nil {
+		return nil, err
+	}
+	res.Match = res.Mismatch == ""
+	return res, nil
+}
+
+// bodyMismatch describes the first difference between two bodies, comparing
+// JSON encodings so numbers read from different formats compare as equal.
+// returns the empty string if the bodies match
+func bodyMismatch(expect, got interface{}) (string, error) {
+	expectData, err := json.Marshal(expect)
+	if err != nil {
+		return "", err
+	}
+	gotData, err := json.Marshal(got)
+	if err != nil {
+		return "", err
+	}
+	if bytes.Equal(expectData, gotData) {
+		return "", nil
+	}
+
+	expectRows, ok := expect.([]interface{})
+	gotRows, gotOk := got.([]interface{})
+	if !ok || !gotOk {
+		return "body differs", nil
+	}
+	if len(expectRows) != len(gotRows) {
+		return fmt.Sprintf("stored body has %d entries, transform produced %d", len(expectRows), len(gotRows)), nil
+	}
+	for i := range expectRows {
+		e, _ := json.Marshal(expectRows[i])
+		g, _ := json.Marshal(gotRows[i])
+		if !bytes.Equal(e, g) {
+			return fmt.Sprintf("entry %d differs: stored %s, transform produced %s", i, e, g), nil
+		}
+	}
+	return "body differs", nil
+}
+
+// matchDatasetRefs returns logbook references with names matching a glob
+// pattern of the form "username/name", sorted by name. A pattern without a
+// username, or with the "me" username matches datasets of the active profile
+func matchDatasetRefs(scope scope, pattern string) ([]dsref.Ref, error) {
+	userPattern, namePattern := "me", pattern
+	if i := strings.Index(pattern, "/"); i >= 0 {
+		userPattern, namePattern = pattern[:i], pattern[i+1:]
+	}
+	if userPattern == "me" {
+		userPattern = scope.ActiveProfile().Peername
+	}
+
+	all, err := scope.Logbook().DatasetRefs(scope.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	refs := []dsref.Ref{}
+	for _, ref := range all {
+		userMatch, err := path.Match(userPattern, ref.Username)
+		if err != nil {
+			return nil, err
+		}
+		nameMatch, err := path.Match(namePattern, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		if userMatch && nameMatch {
+			refs = append(refs, ref)
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Human() < refs[j].Human() })
+	return refs, nil
+}
+
 // AnalyzeTransform runs analysis on a transform script
 func (automationImpl) AnalyzeTransform(scope scope, p *AnalyzeTransformParams) (*AnalyzeTransformResult, error) {
 	ctx := scope.Context()
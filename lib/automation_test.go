@@ -84,6 +84,79 @@ dataset.commit(ds)
 	}
 }
 
+func TestApplyResume(t *testing.T) {
+	tr := newTestRunner(t)
+	defer tr.Delete()
+
+	var checkpointRunID string
+	tr.Instance.Bus().SubscribeTypes(func(_ context.Context, e event.Event) error {
+		checkpointRunID = e.SessionID
+		return nil
+	}, event.ETTransformCheckpoint)
+
+	// the first run checkpoints its progress, then is interrupted by an error
+	_, err := tr.ApplyWithParams(tr.Ctx, &ApplyParams{
+		Wait: true,
+		Transform: &dataset.Transform{
+			Text: `
+state = qri.resume_state()
+if state == None:
+  qri.checkpoint({"page": 3})
+  error("interrupted")
+`,
+		},
+	})
+	if err == nil {
+		t.Fatal("expected interrupted run to error")
+	}
+	if checkpointRunID == "" {
+		t.Fatal("expected interrupted run to checkpoint")
+	}
+	rs, err := tr.Instance.Automation().RunInfo(tr.Ctx, &RunInfoParams{ID: checkpointRunID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(`{"page":3}`, string(rs.Checkpoint)); diff != "" {
+		t.Errorf("checkpoint mismatch (-want +got):\n%s", diff)
+	}
+
+	// resuming starts from the checkpoint
+	res, err := tr.ApplyWithParams(tr.Ctx, &ApplyParams{
+		Wait:        true,
+		ResumeRunID: checkpointRunID,
+		Transform: &dataset.Transform{
+			Text: `
+load("dataframe.star", "dataframe")
+state = qri.resume_state()
+if state == None:
+  error("expected resume state")
+ds = dataset.latest()
+ds.body = dataframe.DataFrame([[state["page"]]])
+dataset.commit(ds)
+`,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(`[[3]]`, string(data)); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = tr.ApplyWithParams(tr.Ctx, &ApplyParams{
+		Wait:        true,
+		ResumeRunID: "unknown",
+		Transform:   &dataset.Transform{Text: `print("hi")`},
+	})
+	if err == nil {
+		t.Error("expected resuming an unknown run to error")
+	}
+}
+
 func TestApplyTransformValidationFailure(t *testing.T) {
 	tr := newTestRunner(t)
 	defer tr.Delete()
@@ -292,3 +365,179 @@ func errOnTimeout(t *testing.T, c chan string) <-chan string {
 	}()
 	return done
 }
+
+func TestApplyBatch(t *testing.T) {
+	tr := newTestRunner(t)
+	defer tr.Delete()
+
+	tr.MustSaveFromBody(t, "raw_a", "testdata/cities_2/body.csv")
+	tr.MustSaveFromBody(t, "raw_b", "testdata/cities_2/body.csv")
+	tr.MustSaveFromBody(t, "cities", "testdata/cities_2/body.csv")
+	notCities := tr.MustWriteTmpFile(t, "not_cities.csv", "a,b\n1,2\n")
+	tr.MustSaveFromBody(t, "raw_c", notCities)
+
+	script := `
+ds = dataset.latest()
+if "city" not in [c for c in ds.body.columns]:
+  error("not a cities dataset")
+ds.body = ds.body.append([["tokyo", 9200000, 48.5, False]])
+dataset.commit(ds)
+`
+	res, err := tr.Instance.Automation().ApplyBatch(tr.Ctx, &ApplyBatchParams{
+		RefPattern: "me/raw_*",
+		Transform:  &dataset.Transform{Text: script},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(res))
+	}
+	for i, name := range []string{"default_profile_for_testing/raw_a", "default_profile_for_testing/raw_b", "default_profile_for_testing/raw_c"} {
+		if res[i].Ref != name {
+			t.Errorf("result %d ref mismatch. want: %q, got: %q", i, name, res[i].Ref)
+		}
+	}
+	if res[0].Error != "" || res[1].Error != "" {
+		t.Errorf("expected raw_a & raw_b to succeed, got errors: %q, %q", res[0].Error, res[1].Error)
+	}
+	if !strings.Contains(res[2].Error, "not a cities dataset") {
+		t.Errorf("expected raw_c to fail with script error, got: %q", res[2].Error)
+	}
+
+	ds := tr.MustGet(t, "me/raw_a")
+	if ds.Path != res[0].Path {
+		t.Errorf("expected result path to be the new head. want: %q, got: %q", ds.Path, res[0].Path)
+	}
+	if ds.Structure.Entries != 6 {
+		t.Errorf("expected applied transform to add a row, got %d entries", ds.Structure.Entries)
+	}
+	if ds = tr.MustGet(t, "me/cities"); ds.Structure.Entries != 5 {
+		t.Errorf("expected unmatched dataset to be unchanged, got %d entries", ds.Structure.Entries)
+	}
+
+	if _, err := tr.Instance.Automation().ApplyBatch(tr.Ctx, &ApplyBatchParams{
+		RefPattern: "me/nope_*",
+		Transform:  &dataset.Transform{Text: script},
+	}); err == nil {
+		t.Error("expected a pattern with no matches to error")
+	}
+}
+
+func TestListTransforms(t *testing.T) {
+	tr := newTestRunner(t)
+	defer tr.Delete()
+
+	tr.MustSaveFromBody(t, "raw_a", "testdata/cities_2/body.csv")
+	tr.MustSaveFromBody(t, "cities", "testdata/cities_2/body.csv")
+	notCities := tr.MustWriteTmpFile(t, "not_cities.csv", "a,b\n1,2\n")
+	tr.MustSaveFromBody(t, "raw_b", notCities)
+
+	script := `
+ds = dataset.latest()
+if "city" not in [c for c in ds.body.columns]:
+  error("not a cities dataset")
+ds.body = ds.body.append([["tokyo", 9200000, 48.5, False]])
+dataset.commit(ds)
+`
+	if _, err := tr.Instance.Automation().ApplyBatch(tr.Ctx, &ApplyBatchParams{
+		RefPattern: "me/raw_*",
+		Transform:  &dataset.Transform{Text: script},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := tr.Instance.Automation().ListTransforms(tr.Ctx, &ListTransformsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 {
+		t.Fatalf("expected 2 datasets with transforms, got %d: %v", len(res), res)
+	}
+	if res[0].Ref != "default_profile_for_testing/raw_a" || res[0].RunCount != 1 || res[0].LastRunStart == nil {
+		t.Errorf("expected raw_a to be listed first with one run, got: %#v", res[0])
+	}
+	if res[1].LastRunStatus != string(run.RSFailed) || res[1].LastFailure == nil || res[1].RunCount != 1 {
+		t.Errorf("expected raw_b to have one failed run, got: %#v", res[1])
+	}
+
+	res, err = tr.Instance.Automation().ListTransforms(tr.Ctx, &ListTransformsParams{Sort: TransformSortFailed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res[0].Ref != "default_profile_for_testing/raw_b" {
+		t.Errorf("expected the failed transform to be listed first, got: %q", res[0].Ref)
+	}
+
+	if _, err := tr.Instance.Automation().ListTransforms(tr.Ctx, &ListTransformsParams{Sort: "size"}); err == nil {
+		t.Error("expected an unknown sort to error")
+	}
+}
+
+func TestVerifyTransform(t *testing.T) {
+	tr := newTestRunner(t)
+	defer tr.Delete()
+
+	tr.MustSaveFromBody(t, "source_ds", "testdata/cities_2/body.csv")
+	scriptPath := tr.MustWriteTmpFile(t, "transform.star", `
+src = load_dataset("me/source_ds")
+ds = dataset.latest()
+ds.body = src.body
+dataset.commit(ds)
+`)
+	if _, err := tr.SaveWithParams(&SaveParams{
+		Ref:       "me/derived_ds",
+		FilePaths: []string{scriptPath},
+		Apply:     true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	source := tr.MustGet(t, "me/source_ds")
+
+	// changing the input doesn't change the version the transform replays against
+	changed := tr.MustWriteTmpFile(t, "changed.csv", "city,pop,avg_age,in_usa\ntokyo,9200000,48.5,false\n")
+	tr.MustSaveFromBody(t, "source_ds", changed)
+
+	res, err := tr.Instance.Automation().VerifyTransform(tr.Ctx, &VerifyTransformParams{Ref: "me/derived_ds"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Match {
+		t.Errorf("expected replayed body to match, got mismatch: %q", res.Mismatch)
+	}
+	expectInputs := []string{fmt.Sprintf("default_profile_for_testing/source_ds@%s", source.Path)}
+	if diff := cmp.Diff(expectInputs, res.Inputs); diff != "" {
+		t.Errorf("inputs mismatch (-want +got):\n%s", diff)
+	}
+
+	secretPath := tr.MustWriteTmpFile(t, "secret.star", `
+ds = dataset.latest()
+ds.body = [[1, int(qri.get_secret("value"))]]
+dataset.commit(ds)
+`)
+	if _, err := tr.SaveWithParams(&SaveParams{
+		Ref:       "me/secret_ds",
+		FilePaths: []string{secretPath},
+		Secrets:   map[string]string{"value": "2"},
+		Apply:     true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	res, err = tr.Instance.Automation().VerifyTransform(tr.Ctx, &VerifyTransformParams{
+		Ref:     "me/secret_ds",
+		Secrets: map[string]string{"value": "3"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `entry 0 differs: stored [1,2], transform produced [1,3]`
+	if res.Match || res.Mismatch != expect {
+		t.Errorf("mismatch description. want: %q, got: %q", expect, res.Mismatch)
+	}
+
+	_, err = tr.Instance.Automation().VerifyTransform(tr.Ctx, &VerifyTransformParams{Ref: "me/source_ds"})
+	if err == nil || !strings.Contains(err.Error(), "has no transform to verify") {
+		t.Errorf("expected verifying a version without a transform to error, got: %v", err)
+	}
+}
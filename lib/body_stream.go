@@ -0,0 +1,136 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/base/dsfs"
+)
+
+// BodyStream is the body of a dataset encoded in a data format, readable from
+// any offset without holding the body in memory. It implements io.ReadSeeker
+// so bodies can be served with http.ServeContent, which handles range
+// requests. Entries are encoded as they're read, seeking backwards restarts
+// the stream
+type BodyStream struct {
+	open func() (io.ReadCloser, error)
+	size int64
+	// pos is the offset the next read starts at
+	pos int64
+	rc  io.ReadCloser
+	// rcPos is the offset rc is at
+	rcPos int64
+}
+
+// OpenBodyStream creates a stream of the body of the dataset at refStr in
+// format. The body is encoded once up front to find the size of the stream
+func (inst *Instance) OpenBodyStream(ctx context.Context, refStr string, format dataset.DataFormat) (*BodyStream, error) {
+	if format != dataset.CSVDataFormat && format != dataset.JSONDataFormat {
+		return nil, fmt.Errorf("can only stream bodies as csv or json")
+	}
+	ref, _, err := inst.ParseAndResolveRef(ctx, refStr, "")
+	if err != nil {
+		return nil, err
+	}
+	fs := inst.Repo().Filesystem()
+
+	s := &BodyStream{
+		open: func() (io.ReadCloser, error) {
+			ds, err := dsfs.LoadDataset(ctx, fs, ref.Path)
+			if err != nil {
+				return nil, err
+			}
+			if ds.BodyPath == "" || ds.Structure == nil {
+				return nil, fmt.Errorf("dataset has no body")
+			}
+			if err := base.OpenDataset(ctx, fs, ds); err != nil {
+				return nil, err
+			}
+			pr, pw := io.Pipe()
+			go func() {
+				defer ds.BodyFile().Close()
+				pw.CloseWithError(base.WriteBody(pw, ds, format))
+			}()
+			return pr, nil
+		},
+	}
+	rc, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	if s.size, err = io.Copy(ioutil.Discard, rc); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Read implements the io.Reader interface
+func (s *BodyStream) Read(p []byte) (int, error) {
+	if s.pos >= s.size {
+		return 0, io.EOF
+	}
+	if s.rc == nil || s.rcPos > s.pos {
+		if err := s.reopen(); err != nil {
+			return 0, err
+		}
+	}
+	if s.rcPos < s.pos {
+		n, err := io.CopyN(ioutil.Discard, s.rc, s.pos-s.rcPos)
+		s.rcPos += n
+		if err != nil {
+			return 0, err
+		}
+	}
+	n, err := s.rc.Read(p)
+	s.rcPos += int64(n)
+	s.pos = s.rcPos
+	return n, err
+}
+
+// Seek implements the io.Seeker interface
+func (s *BodyStream) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += s.pos
+	case io.SeekEnd:
+		offset += s.size
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("seeking to negative offset %d", offset)
+	}
+	s.pos = offset
+	return offset, nil
+}
+
+// Close releases the stream
+func (s *BodyStream) Close() error {
+	if s.rc == nil {
+		return nil
+	}
+	err := s.rc.Close()
+	s.rc = nil
+	return err
+}
+
+func (s *BodyStream) reopen() error {
+	if err := s.Close(); err != nil {
+		return err
+	}
+	rc, err := s.open()
+	if err != nil {
+		return err
+	}
+	s.rc = rc
+	s.rcPos = 0
+	return nil
+}
+
+var _ io.ReadSeeker = (*BodyStream)(nil)
@@ -0,0 +1,59 @@
+package lib
+
+import (
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/qri-io/dataset"
+)
+
+func TestBodyStream(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+
+	run.MustSaveFromBody(t, "cities_ds", "testdata/cities_2/body.csv")
+	res, err := run.Instance.Dataset().Get(run.Ctx, &GetParams{Ref: "me/cities_ds", Selector: "body", All: true, Format: "csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := string(res.Bytes)
+
+	s, err := run.Instance.OpenBodyStream(run.Ctx, "me/cities_ds", dataset.CSVDataFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	size, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != int64(len(expect)) {
+		t.Errorf("size mismatch. want: %d, got: %d", len(expect), size)
+	}
+
+	readAt := func(offset int64, n int) string {
+		t.Helper()
+		if _, err := s.Seek(offset, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(io.LimitReader(s, int64(n)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	// seeking backwards restarts the stream
+	if got := readAt(10, 5); got != expect[10:15] {
+		t.Errorf("read at 10 mismatch. want: %q, got: %q", expect[10:15], got)
+	}
+	if got := readAt(0, len(expect)); got != expect {
+		t.Errorf("read from start mismatch. want: %q, got: %q", expect, got)
+	}
+
+	if _, err := run.Instance.OpenBodyStream(run.Ctx, "me/cities_ds", dataset.XLSXDataFormat); err == nil {
+		t.Error("expected streaming an unsupported format to error")
+	}
+}
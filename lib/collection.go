@@ -33,9 +33,9 @@ func (m CollectionMethods) Name() string {
 // Attributes defines attributes for each method
 func (m CollectionMethods) Attributes() map[string]AttributeSet {
 	return map[string]AttributeSet{
-		"list":        {Endpoint: qhttp.AEList, HTTPVerb: "POST"},
-		"listrawrefs": {Endpoint: qhttp.DenyHTTP},
-		"get":         {Endpoint: qhttp.AECollectionGet, HTTPVerb: "POST"},
+		"list":        {Endpoint: qhttp.AEList, HTTPVerb: "POST", ReadOnly: true},
+		"listrawrefs": {Endpoint: qhttp.DenyHTTP, ReadOnly: true},
+		"get":         {Endpoint: qhttp.AECollectionGet, HTTPVerb: "POST", ReadOnly: true},
 	}
 }
 
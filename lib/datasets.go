@@ -8,9 +8,13 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/qri-io/dag"
@@ -24,14 +28,18 @@ import (
 	"github.com/qri-io/qri/automation/run"
 	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/base/archive"
+	"github.com/qri-io/qri/base/component"
 	"github.com/qri-io/qri/base/dsfs"
 	"github.com/qri-io/qri/base/fill"
+	"github.com/qri-io/qri/base/linkfile"
 	"github.com/qri-io/qri/base/params"
+	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/dsref"
 	qrierr "github.com/qri-io/qri/errors"
 	"github.com/qri-io/qri/event"
 	qhttp "github.com/qri-io/qri/lib/http"
 	"github.com/qri-io/qri/logbook"
+	"github.com/qri-io/qri/profile"
 	"github.com/qri-io/qri/remote"
 	"github.com/qri-io/qri/repo"
 	"github.com/qri-io/qri/transform"
@@ -52,21 +60,47 @@ func (m DatasetMethods) Name() string {
 // Attributes defines attributes for each method
 func (m DatasetMethods) Attributes() map[string]AttributeSet {
 	return map[string]AttributeSet{
-		"get":             {Endpoint: qhttp.AEGet, HTTPVerb: "POST"},
-		"getcsv":          {Endpoint: qhttp.DenyHTTP}, // getcsv is not part of the json api, but is handled in a separate `GetBodyCSVHandler` function
-		"getzip":          {Endpoint: qhttp.DenyHTTP}, // getzip is not part of the json api, but is handled is a separate `GetHandler` function
-		"activity":        {Endpoint: qhttp.AEActivity, HTTPVerb: "POST"},
-		"rename":          {Endpoint: qhttp.AERename, HTTPVerb: "POST", DefaultSource: "local"},
-		"save":            {Endpoint: qhttp.AESave, HTTPVerb: "POST"},
-		"pull":            {Endpoint: qhttp.AEPull, HTTPVerb: "POST", DefaultSource: "network"},
-		"push":            {Endpoint: qhttp.AEPush, HTTPVerb: "POST", DefaultSource: "local"},
-		"render":          {Endpoint: qhttp.AERender, HTTPVerb: "POST"},
-		"remove":          {Endpoint: qhttp.AERemove, HTTPVerb: "POST", DefaultSource: "local"},
-		"validate":        {Endpoint: qhttp.AEValidate, HTTPVerb: "POST", DefaultSource: "local"},
-		"manifest":        {Endpoint: qhttp.AEManifest, HTTPVerb: "POST", DefaultSource: "local"},
-		"manifestmissing": {Endpoint: qhttp.AEManifestMissing, HTTPVerb: "POST", DefaultSource: "local"},
-		"daginfo":         {Endpoint: qhttp.AEDAGInfo, HTTPVerb: "POST", DefaultSource: "local"},
-		"whatchanged":     {Endpoint: qhttp.AEWhatChanged, HTTPVerb: "POST", DefaultSource: "local"},
+		"get":              {Endpoint: qhttp.AEGet, HTTPVerb: "POST", ReadOnly: true},
+		"getcsv":           {Endpoint: qhttp.DenyHTTP, ReadOnly: true}, // getcsv is not part of the json api, but is handled in a separate `GetBodyCSVHandler` function
+		"getzip":           {Endpoint: qhttp.DenyHTTP, ReadOnly: true}, // getzip is not part of the json api, but is handled is a separate `GetHandler` function
+		"gethtml":          {Endpoint: qhttp.DenyHTTP, ReadOnly: true}, // gethtml is not part of the json api, but is handled in the separate `GetHandler` function
+		"getfixedwidth":    {Endpoint: qhttp.DenyHTTP, ReadOnly: true},
+		"activityfeed":     {Endpoint: qhttp.DenyHTTP, ReadOnly: true}, // activityfeed is not part of the json api, but is handled in the separate `ActivityFeedHandler` function
+		"getdcat":          {Endpoint: qhttp.DenyHTTP, ReadOnly: true}, // getdcat is not part of the json api, but is handled in the separate `GetHandler` function
+		"getsql":           {Endpoint: qhttp.DenyHTTP, ReadOnly: true},
+		"bodydelta":        {Endpoint: qhttp.AEBodyDelta, HTTPVerb: "POST", ReadOnly: true},
+		"dependents":       {Endpoint: qhttp.AEDependents, HTTPVerb: "POST", DefaultSource: "local", ReadOnly: true},
+		"activity":         {Endpoint: qhttp.AEActivity, HTTPVerb: "POST", ReadOnly: true},
+		"rename":           {Endpoint: qhttp.AERename, HTTPVerb: "POST", DefaultSource: "local"},
+		"adopt":            {Endpoint: qhttp.AEAdopt, HTTPVerb: "POST", DefaultSource: "local"},
+		"merge":            {Endpoint: qhttp.AEMerge, HTTPVerb: "POST", DefaultSource: "local"},
+		"duplicates":       {Endpoint: qhttp.AEDuplicates, HTTPVerb: "POST", DefaultSource: "local", ReadOnly: true},
+		"removeduplicates": {Endpoint: qhttp.AERemoveDuplicates, HTTPVerb: "POST", DefaultSource: "local"},
+		"renamecolumn":     {Endpoint: qhttp.AERenameColumn, HTTPVerb: "POST", DefaultSource: "local"},
+		"describecolumn":   {Endpoint: qhttp.AEDescribeColumn, HTTPVerb: "POST", DefaultSource: "local"},
+		"setmeta":          {Endpoint: qhttp.AESetMeta, HTTPVerb: "POST", DefaultSource: "local"},
+		"applypatch":       {Endpoint: qhttp.AEApplyPatch, HTTPVerb: "POST", DefaultSource: "local"},
+		"save":             {Endpoint: qhttp.AESave, HTTPVerb: "POST"},
+		"pull":             {Endpoint: qhttp.AEPull, HTTPVerb: "POST", DefaultSource: "network"},
+		"push":             {Endpoint: qhttp.AEPush, HTTPVerb: "POST", DefaultSource: "local"},
+		"render":           {Endpoint: qhttp.AERender, HTTPVerb: "POST", ReadOnly: true},
+		"remove":           {Endpoint: qhttp.AERemove, HTTPVerb: "POST", DefaultSource: "local"},
+		"squash":           {Endpoint: qhttp.AESquash, HTTPVerb: "POST", DefaultSource: "local"},
+		"validate":         {Endpoint: qhttp.AEValidate, HTTPVerb: "POST", DefaultSource: "local", ReadOnly: true},
+		"manifest":         {Endpoint: qhttp.AEManifest, HTTPVerb: "POST", DefaultSource: "local", ReadOnly: true},
+		"manifestmissing":  {Endpoint: qhttp.AEManifestMissing, HTTPVerb: "POST", DefaultSource: "local", ReadOnly: true},
+		"daginfo":          {Endpoint: qhttp.AEDAGInfo, HTTPVerb: "POST", DefaultSource: "local", ReadOnly: true},
+		"storageinfo":      {Endpoint: qhttp.AEStorageInfo, HTTPVerb: "POST", DefaultSource: "local", ReadOnly: true},
+		"components":       {Endpoint: qhttp.AEComponents, HTTPVerb: "POST", DefaultSource: "local", ReadOnly: true},
+		"quality":          {Endpoint: qhttp.AEQuality, HTTPVerb: "POST", DefaultSource: "local", ReadOnly: true},
+		"whatchanged":      {Endpoint: qhttp.AEWhatChanged, HTTPVerb: "POST", DefaultSource: "local", ReadOnly: true},
+		"logbytes":         {Endpoint: qhttp.DenyHTTP, DefaultSource: "local", ReadOnly: true},
+		"doctor":           {Endpoint: qhttp.DenyHTTP, DefaultSource: "local"},
+		"attach":           {Endpoint: qhttp.DenyHTTP, DefaultSource: "local"},
+		"link":             {Endpoint: qhttp.DenyHTTP, DefaultSource: "local"},
+		"exportgit":        {Endpoint: qhttp.DenyHTTP, DefaultSource: "local", ReadOnly: true},
+		"fingerprint":      {Endpoint: qhttp.AEFingerprint, HTTPVerb: "POST", DefaultSource: "local", ReadOnly: true},
+		"getattachment":    {Endpoint: qhttp.DenyHTTP, ReadOnly: true},
 	}
 }
 
@@ -81,6 +115,97 @@ type GetParams struct {
 	// loop over their `Cursor` in order to get all rows.
 	// TODO(ramfox): are we in a place to remove All?
 	All bool `json:"all" docs:"hidden"`
+	// if true, selecting a field that doesn't exist is an error instead of a
+	// null value, distinguishing absent fields from fields that are null
+	Strict bool `json:"strict"`
+	// names of body columns to return in the given order, only valid with the
+	// "body" selector. bodies saved with columnar storage only read the
+	// selected columns
+	Columns []string `json:"columns"`
+	// if true, CSV bodies keep null values apart from empty strings. nulls
+	// are written as NullToken without quotes & empty strings are quoted
+	CSVNulls bool `json:"csvNulls"`
+	// text written for null values in CSV bodies, setting a token implies
+	// CSVNulls
+	NullToken string `json:"nullToken"`
+	// if true, CSV output starts with a UTF-8 byte order mark, which
+	// spreadsheet programs like Excel need to read non-ASCII text
+	CSVBOM bool `json:"csvBOM"`
+	// if true, CSV records end with CRLF instead of LF
+	CSVCRLF bool `json:"csvCRLF"`
+	// return only body rows whose KeyColumn value is one of Keys, only valid
+	// with the "body" selector. the body is scanned in full, limit & offset
+	// don't apply
+	Keys []string `json:"keys"`
+	// name of the body column Keys are matched against
+	KeyColumn string `json:"keyColumn"`
+	// return the value of a body with a single row & column on its own,
+	// instead of nested in a row. it's an error if the body isn't a single
+	// value. only valid with the "body" selector
+	Scalar bool `json:"scalar"`
+	// convert body values to the types their schema columns declare, eg.
+	// numbers read from CSV as strings. values that can't be converted are
+	// set to null & counted in GetResult.TypeErrors. only valid with the
+	// "body" selector
+	Typed bool `json:"typed"`
+	// if true, a value that can't be converted to its column type is an
+	// error instead of null. requires Typed
+	TypedStrict bool `json:"typedStrict"`
+	// return only body rows whose TimeColumn value falls between From & To,
+	// only valid with the "body" selector. limit & offset page through the
+	// matching rows
+	TimeColumn string `json:"timeColumn"`
+	// start of the time range, inclusive. a date or RFC 3339 timestamp
+	From string `json:"from"`
+	// end of the time range, inclusive. a date covers that whole day
+	To string `json:"to"`
+	// if true, a row with a time that can't be parsed is an error instead of
+	// being skipped & counted in GetResult.SkippedRows. requires TimeColumn
+	TimeStrict bool `json:"timeStrict"`
+	// down-sample the body to about this many rows for plotting, keeping the
+	// first & last rows. only valid with the "body" selector, limit & offset
+	// don't apply
+	Decimate int `json:"decimate"`
+	// numeric column to keep the shape of when decimating, using the
+	// largest-triangle-three-buckets algorithm. without one, rows are picked
+	// at an even stride
+	DecimateColumn string `json:"decimateColumn"`
+	// swap the rows & columns of the body, each column becomes a row starting
+	// with the column title. only bodies of up to base.MaxTransposeRows rows
+	// can be transposed. only valid with the "body" selector
+	Transpose bool `json:"transpose"`
+	// output format of the body, one of the built-in "json", "csv" or "ndjson"
+	// formats or a format registered with base.RegisterBodyFormat. json, the
+	// default, returns the body as structured data in GetResult.Value. other
+	// formats are returned encoded in GetResult.Bytes, and are only valid with
+	// the "body" selector
+	Format string `json:"format"`
+}
+
+// getBodySelections are the GetParams options that select or reshape body
+// rows. each is only valid with the "body" selector, and only one can be used
+// at a time. options marked getOnly are only supported by Get
+var getBodySelections = []struct {
+	desc    string
+	set     func(p *GetParams) bool
+	getOnly bool
+}{
+	{"select columns", func(p *GetParams) bool { return len(p.Columns) > 0 }, false},
+	{"select rows by key", func(p *GetParams) bool { return len(p.Keys) > 0 }, true},
+	{"read the body as a scalar", func(p *GetParams) bool { return p.Scalar }, true},
+	{"select rows by time", func(p *GetParams) bool { return p.TimeColumn != "" }, true},
+	{"decimate the body", func(p *GetParams) bool { return p.Decimate != 0 }, true},
+	{"transpose the body", func(p *GetParams) bool { return p.Transpose }, true},
+}
+
+// bodySelection describes the body selection p uses, if any
+func (p *GetParams) bodySelection() string {
+	for _, sel := range getBodySelections {
+		if sel.set(p) {
+			return sel.desc
+		}
+	}
+	return ""
 }
 
 // SetNonZeroDefaults assigns default values
@@ -99,20 +224,107 @@ func (p *GetParams) SetNonZeroDefaults() {
 	}
 }
 
+// setDefaultBodyLimit sets the limit of a paged body request without one to
+// the configured default page size
+func setDefaultBodyLimit(cfg *config.Config, p *GetParams) {
+	if p.All || p.Limit > 0 {
+		return
+	}
+	p.Limit = params.DefaultListLimit
+	if cfg != nil && cfg.API != nil && cfg.API.DefaultBodyPageSize > 0 {
+		p.Limit = cfg.API.DefaultBodyPageSize
+	}
+}
+
 // Validate returns an error if GetParams fields are in an invalid state
 func (p *GetParams) Validate() error {
 	if !isValidSelector(p.Selector) {
 		return fmt.Errorf("could not parse request: invalid selector")
 	}
-	if p.Selector == "body" {
-		if !p.All && (p.Limit < 0 || p.Offset < 0) {
-			return fmt.Errorf("invalid limit / offset settings")
+	if p.Selector == "body" && !p.All && (p.Limit < 0 || p.Offset < 0) {
+		return fmt.Errorf("invalid limit / offset settings")
+	}
+	used := ""
+	for _, sel := range getBodySelections {
+		if !sel.set(p) {
+			continue
+		}
+		if p.Selector != "body" {
+			return fmt.Errorf("can only %s when getting the body", sel.desc)
+		}
+		if used != "" {
+			return fmt.Errorf("cannot %s and %s at the same time", used, sel.desc)
+		}
+		used = sel.desc
+	}
+	if len(p.Keys) > 0 && p.KeyColumn == "" {
+		return fmt.Errorf("a key column is required to select rows by key")
+	}
+	if p.TypedStrict && !p.Typed {
+		return fmt.Errorf("strict type conversion requires converting body types")
+	}
+	if p.Typed {
+		if p.Selector != "body" {
+			return fmt.Errorf("only body values can be converted to their column types")
+		}
+		if len(p.Columns) > 0 || p.Scalar {
+			return fmt.Errorf("cannot convert body types when selecting columns or reading a scalar")
+		}
+	}
+	if (p.From != "" || p.To != "") && p.TimeColumn == "" {
+		return fmt.Errorf("a time column is required to select rows by time")
+	}
+	if (p.CSVBOM || p.CSVCRLF) && p.Selector != "body" {
+		return fmt.Errorf("csv byte order marks & line endings only apply to the body")
+	}
+	if p.TimeStrict && p.TimeColumn == "" {
+		return fmt.Errorf("strict time parsing requires a time column")
+	}
+	if p.TimeColumn != "" {
+		if p.From == "" && p.To == "" {
+			return fmt.Errorf("selecting rows by time requires a start or end of the range")
+		}
+		if _, _, err := p.timeRange(); err != nil {
+			return err
+		}
+	}
+	if p.DecimateColumn != "" && p.Decimate == 0 {
+		return fmt.Errorf("a decimate column requires a number of rows to decimate to")
+	}
+	if p.Decimate != 0 && p.Decimate < 2 {
+		return fmt.Errorf("decimating needs at least 2 rows to keep the first & last")
+	}
+	if p.Format != "" {
+		if !base.IsBodyFormat(p.Format) {
+			return fmt.Errorf("invalid body format %q, must be one of %s", p.Format, strings.Join(base.BodyFormats(), ", "))
+		}
+		if p.Format != "json" && p.Selector != "body" {
+			return fmt.Errorf("only the body can be returned as %s", p.Format)
 		}
 	}
 
 	return nil
 }
 
+// timeRange parses the From & To bounds of a time range. the end of the
+// range is returned as an exclusive bound
+func (p *GetParams) timeRange() (from, to time.Time, err error) {
+	if p.From != "" {
+		if from, err = base.ParseTimeBound(p.From, false); err != nil {
+			return from, to, fmt.Errorf("invalid start of time range: %w", err)
+		}
+	}
+	if p.To != "" {
+		if to, err = base.ParseTimeBound(p.To, true); err != nil {
+			return from, to, fmt.Errorf("invalid end of time range: %w", err)
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return from, to, fmt.Errorf("start of time range must be before the end")
+	}
+	return from, to, nil
+}
+
 func isValidSelector(selector string) bool {
 	return validSelector.MatchString(selector)
 }
@@ -127,6 +339,14 @@ var validSelector = regexp.MustCompile(`^$|^[\w-\.]*[\w]$`)
 type GetResult struct {
 	Value interface{} `json:"value,omitempty"`
 	Bytes []byte      `json:"bytes,omitempty"`
+	// keys that didn't match a body row when selecting rows by key
+	MissingKeys []string `json:"missingKeys,omitempty"`
+	// number of body values set to null because they couldn't be converted
+	// to their column type
+	TypeErrors int `json:"typeErrors,omitempty"`
+	// number of body rows skipped because their time couldn't be parsed
+	// when selecting rows by time
+	SkippedRows int `json:"skippedRows,omitempty"`
 }
 
 // DataResponse is the struct used to respond to api requests made to the /body endpoint
@@ -151,7 +371,8 @@ func (m DatasetMethods) Get(ctx context.Context, p *GetParams) (*GetResult, erro
 	return nil, dispatchReturnError(got, err)
 }
 
-// GetCSV fetches the body as a csv encoded byte slice, it recognizes Limit, Offset, and All list params
+// GetCSV fetches the body as a csv encoded byte slice, it recognizes Limit, Offset, and All list params.
+// If the selector is "stats", GetCSV returns a table of statistics with one row per column instead
 func (m DatasetMethods) GetCSV(ctx context.Context, p *GetParams) ([]byte, error) {
 	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "getcsv"), p)
 	if res, ok := got.([]byte); ok {
@@ -160,6 +381,91 @@ func (m DatasetMethods) GetCSV(ctx context.Context, p *GetParams) ([]byte, error
 	return nil, dispatchReturnError(got, err)
 }
 
+// GetHTML fetches the body as an HTML table, it recognizes Limit, Offset, and
+// All list params. Tables are capped at base.MaxBodyTableRows rows, and note
+// when they don't include the entire body
+func (m DatasetMethods) GetHTML(ctx context.Context, p *GetParams) ([]byte, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "gethtml"), p)
+	if res, ok := got.([]byte); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// GetFixedWidthParams defines parameters for getting a body as fixed-width
+// text
+type GetFixedWidthParams struct {
+	GetParams
+	// width of each column in characters. when empty, columns are sized to fit
+	// their longest value
+	Widths []int `json:"widths"`
+	// how to handle values longer than their column, one of "truncate" or
+	// "error". defaults to "truncate"
+	Overflow string `json:"overflow"`
+}
+
+// GetFixedWidth fetches the body as fixed-width text, one line per row with
+// each column padded to its width. It recognizes Limit, Offset, All and
+// Columns params
+func (m DatasetMethods) GetFixedWidth(ctx context.Context, p *GetFixedWidthParams) ([]byte, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "getfixedwidth"), p)
+	if res, ok := got.([]byte); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// GetDCAT fetches the meta component as a DCAT Dataset JSON-LD document, for
+// publishing to open data catalogs. The selector must be "meta"
+func (m DatasetMethods) GetDCAT(ctx context.Context, p *GetParams) ([]byte, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "getdcat"), p)
+	if res, ok := got.([]byte); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// GetSQLParams defines parameters for getting the structure as SQL DDL
+type GetSQLParams struct {
+	GetParams
+	// SQL dialect to write column types & quoted names for, one of "postgres",
+	// "mysql", "sqlite". defaults to ANSI SQL
+	Dialect string `json:"dialect"`
+}
+
+// GetSQL fetches the structure schema as a CREATE TABLE statement named after
+// the dataset, for bootstrapping a database table. The selector must be
+// "structure"
+func (m DatasetMethods) GetSQL(ctx context.Context, p *GetSQLParams) ([]byte, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "getsql"), p)
+	if res, ok := got.([]byte); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// BodyDeltaParams defines parameters for listing body rows that changed since
+// an earlier version of a dataset
+type BodyDeltaParams struct {
+	Ref string `json:"ref"`
+	// SincePath is the path of the earlier version to compare against
+	SincePath string `json:"sincePath"`
+	// Key is the column that identifies a row across versions. Without a key
+	// rows are matched by position
+	Key string `json:"key"`
+}
+
+// BodyDelta returns the body rows a dataset version added or modified since
+// an earlier version, along with the keys of deleted rows. Unlike diff,
+// BodyDelta returns row data, for syncing changes into other systems
+func (m DatasetMethods) BodyDelta(ctx context.Context, p *BodyDeltaParams) (*BodyDelta, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "bodydelta"), p)
+	if res, ok := got.(*BodyDelta); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
 // GetZipResults is returned by `GetZip`
 // It contains a byte slice of the compressed data as well as a generated name based on the dataset
 type GetZipResults struct {
@@ -176,6 +482,24 @@ func (m DatasetMethods) GetZip(ctx context.Context, p *GetParams) (*GetZipResult
 	return nil, dispatchReturnError(got, err)
 }
 
+// LogBytesParams defines parameters for getting the signed log of a dataset
+type LogBytesParams struct {
+	// dataset reference to get the log of; e.g. "b5/world_bank_population"
+	Ref string `json:"ref"`
+}
+
+// LogBytes returns the log of a dataset and all its branches, rooted in the
+// author's user log, as a flatbuffer signed with the logbook owner's private
+// key. These are the bytes logsync transfers, letting external tools send and
+// verify logs without the full push & pull protocol
+func (m DatasetMethods) LogBytes(ctx context.Context, p *LogBytesParams) ([]byte, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "logbytes"), p)
+	if res, ok := got.([]byte); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
 func scriptFileSelection(ds *dataset.Dataset, selector string) (qfs.File, bool) {
 	parts := strings.Split(selector, ".")
 	if len(parts) != 2 {
@@ -200,6 +524,23 @@ func scriptFileSelection(ds *dataset.Dataset, selector string) (qfs.File, bool)
 	return nil, false
 }
 
+// DependentsParams defines parameters for the Dependents method
+type DependentsParams struct {
+	// dataset reference to find dependents of; e.g. "b5/world_bank_population"
+	Ref string `json:"ref"`
+}
+
+// Dependents lists local datasets with transforms that load any version of a
+// dataset. Renaming or removing a dataset with dependents will break the
+// transforms of those dependents
+func (m DatasetMethods) Dependents(ctx context.Context, p *DependentsParams) ([]dsref.VersionInfo, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "dependents"), p)
+	if res, ok := got.([]dsref.VersionInfo); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
 // ActivityParams defines parameters for the Activity method
 type ActivityParams struct {
 	params.List
@@ -209,6 +550,9 @@ type ActivityParams struct {
 	Ref string `json:"ref"`
 	// if true, pull any datasets that aren't stored locally; e.g. false
 	Pull bool `json:"pull"`
+	// if true, list versions deleted from history marked as deleted instead
+	// of leaving them out. only local history records deletes
+	IncludeDeleted bool `json:"includeDeleted,omitempty"`
 }
 
 // SetNonZeroDefaults sets a default limit and offset
@@ -244,6 +588,17 @@ func (m DatasetMethods) Activity(ctx context.Context, params *ActivityParams) ([
 	return nil, dispatchReturnError(got, err)
 }
 
+// ActivityFeed fetches dataset version history as an Atom feed document,
+// with the meta title & description of the latest version as feed-level
+// metadata
+func (m DatasetMethods) ActivityFeed(ctx context.Context, params *ActivityParams) ([]byte, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "activityfeed"), params)
+	if res, ok := got.([]byte); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
 // SaveParams encapsulates arguments to Save
 type SaveParams struct {
 	// dataset supplies params directly, all other param fields override values
@@ -256,8 +611,19 @@ type SaveParams struct {
 	Title string `json:"title"`
 	// commit message, defaults to blank; e.g. "reaname title & fill in supported langages"
 	Message string
+	// commit timestamp, defaults to the time of saving. Set it to date a
+	// version by the period its data covers, eg. when backfilling history
+	CommitTime *time.Time `json:"commitTime,omitempty"`
 	// path to body data
 	BodyPath string `json:"bodyPath" qri:"fspath"`
+	// go-native body entries, eg. [][]interface{} or []map[string]interface{}
+	// body will be serialized to InlineBodyFormat. Cannot be combined with BodyPath
+	// A slice of structs is saved as rows, with a schema derived from struct
+	// fields & `qri:"column_name,type"` tags if the dataset doesn't supply one.
+	// see base.StructSchema
+	InlineBody interface{} `json:"inlineBody"`
+	// data format to serialize InlineBody as, defaults to json
+	InlineBodyFormat string `json:"inlineBodyFormat"`
 	// absolute path or URL to the list of dataset files or components to load
 	FilePaths []string `json:"filePaths" qri:"fspath"`
 	// secrets for transform execution. Should be a set of key: value pairs
@@ -265,12 +631,24 @@ type SaveParams struct {
 	// optional writer to have transform script record standard output to
 	// note: this won't work over RPC, only on local calls
 	ScriptOutput io.Writer `json:"-"`
+	// optional reader to stream body data from, instead of reading a file or
+	// buffering the body in memory. takes precedence over BodyPath, which is
+	// only used to name the body when both are set, eg. for format detection.
+	// without a BodyPath the format comes from the dataset structure
+	// note: this won't work over RPC, only on local calls
+	BodyReader io.Reader `json:"-"`
 
 	// TODO(dustmop): add `Wait bool`, if false, run the save asynchronously
 	// and return events on the bus that provide the progress of the save operation
 
 	// Apply runs a transform script to create the next version to save
 	Apply bool `json:"apply"`
+	// RunTrigger records what started the transform run when Apply is true,
+	// eg: "manual", "scheduled" or "webhook". defaults to "manual"
+	RunTrigger string `json:"runTrigger"`
+	// ResumeState is json-encoded state checkpointed by a previous run, given
+	// to the transform script when Apply is true
+	ResumeState json.RawMessage `json:"resumeState,omitempty"`
 	// Replace writes the entire given dataset as a new snapshot instead of
 	// applying save params as augmentations to the existing history
 	Replace bool `json:"replace"`
@@ -283,10 +661,59 @@ type SaveParams struct {
 	Drop string `json:"drop"`
 	// force a new commit, even if no changes are detected
 	Force bool `json:"force"`
+	// only commit if at least this many body rows differ from the previous
+	// version. ignored when forcing a commit
+	MinChangeRows int `json:"minChangeRows"`
+	// AllowSchemaWiden adds columns the body has that the previous version's
+	// schema doesn't define to the schema, instead of keeping the previous
+	// schema. Added columns accept null values. Columns are never removed
+	AllowSchemaWiden bool `json:"allowSchemaWiden"`
+	// ColumnarStorage additionally stores each body column in its own block so
+	// selected columns can be read without loading the whole body.
+	// experimental
+	ColumnarStorage bool `json:"columnarStorage"`
+	// ChunkSize sets the size in bytes of the blocks the body is split into
+	// when it's written to storage. smaller chunks make partial reads more
+	// granular at the cost of more blocks, larger chunks lower the overhead
+	// of reading the whole body. zero uses the storage default. only stores
+	// that split files into blocks, like IPFS, support a chunk size; saving
+	// with one to any other store is an error
+	ChunkSize int `json:"chunkSize,omitempty"`
+	// HashComponents records a sha256 digest of the canonical content of each
+	// component in the version's provenance, so stored components can be
+	// checked for tampering independent of how they're stored, see
+	// dsfs.LoadProvenance
+	HashComponents bool `json:"hashComponents"`
 	// save a rendered version of the template along with the dataset
 	ShouldRender bool `json:"shouldRender"`
 	// new dataset only, don't create a commit on an existing dataset, name will be unused
 	NewName bool `json:"newName"`
+	// path to a csv file of "column,description" rows. descriptions are written
+	// to matching columns of the structure schema
+	ColumnDescriptionsPath string `json:"columnDescriptionsPath" qri:"fspath"`
+	// SQL query that generates the body to save. queries select from the table
+	// "source", which is the body of the SQLSource dataset. Cannot be combined
+	// with BodyPath or InlineBody. The query is recorded in the provenance of
+	// the saved version, see dsfs.LoadProvenance
+	FromSQL string `json:"fromSQL"`
+	// reference to the dataset FromSQL selects from
+	SQLSource string `json:"sqlSource"`
+	// database connection string, eg. "postgres://user@host/db". DBQuery
+	// runs against the database to generate the body to save. a driver for
+	// the database must be registered with database/sql. Cannot be combined
+	// with BodyPath, InlineBody or FromSQL. The query & connection string
+	// without credentials are recorded in the provenance of the saved version.
+	// FromDB is command-line only, HTTP requests that set it are rejected
+	FromDB string `json:"fromDB"`
+	// query to run against the FromDB database
+	DBQuery string `json:"dbQuery"`
+	// Synthesize generates a body of this many rows of random values that
+	// conform to the dataset's structure schema, for testing consumers of a
+	// dataset before real data exists. Cannot be combined with other bodies
+	Synthesize int `json:"synthesize"`
+	// SynthesizeSeed seeds the random values of a synthesized body. the same
+	// seed & schema always generate the same body
+	SynthesizeSeed int64 `json:"synthesizeSeed"`
 }
 
 // SetNonZeroDefaults sets basic save path params to defaults
@@ -294,6 +721,16 @@ func (p *SaveParams) SetNonZeroDefaults() {
 	p.ConvertFormatToPrev = true
 }
 
+// commandLineOnlyParam returns the json name of a set param that can't be
+// used over HTTP. running database queries with credentials supplied by the
+// caller is only allowed from the command line
+func (p *SaveParams) commandLineOnlyParam() string {
+	if p.FromDB != "" || p.DBQuery != "" {
+		return "fromDB"
+	}
+	return ""
+}
+
 // Save adds a history entry, updating a dataset
 func (m DatasetMethods) Save(ctx context.Context, p *SaveParams) (*dataset.Dataset, error) {
 	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "save"), p)
@@ -318,6 +755,319 @@ func (m DatasetMethods) Rename(ctx context.Context, p *RenameParams) (*dsref.Ver
 	return nil, dispatchReturnError(got, err)
 }
 
+// AdoptParams defines parameters for the Adopt method
+type AdoptParams struct {
+	// reference to the dataset to copy, can belong to any user
+	Current string `json:"current"`
+	// name to save the copy as, must belong to the active profile
+	Next string `json:"next"`
+}
+
+// Adopt copies the latest version of a dataset into a new dataset owned by
+// the active profile. Unlike a rename, adopting can cross usernames. The copy
+// starts a fresh history, with no link back to the original's versions
+func (m DatasetMethods) Adopt(ctx context.Context, p *AdoptParams) (*dataset.Dataset, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "adopt"), p)
+	if res, ok := got.(*dataset.Dataset); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// MergeParams defines parameters for the Merge method
+type MergeParams struct {
+	// references to the datasets to merge, rows of First come before Second
+	First  string `json:"first"`
+	Second string `json:"second"`
+	// name to save the merged dataset as, must be a new dataset
+	Next string `json:"next"`
+	// optional column that identifies a row. rows with a key already seen
+	// are dropped
+	DedupKey string `json:"dedupKey"`
+}
+
+// Validate returns an error if MergeParams fields are in an invalid state
+func (p *MergeParams) Validate() error {
+	if p.First == "" || p.Second == "" {
+		return fmt.Errorf("two datasets are required to merge")
+	}
+	if p.Next == "" {
+		return fmt.Errorf("a name for the merged dataset is required")
+	}
+	return nil
+}
+
+// MergeResult is the result of merging two datasets
+type MergeResult struct {
+	Dataset *dataset.Dataset `json:"dataset"`
+	// number of rows dropped for repeating a dedup key
+	DroppedRows int `json:"droppedRows"`
+}
+
+// Merge saves a new dataset whose body is the rows of two datasets with
+// matching schemas, one after the other. The new dataset keeps the structure
+// of the first dataset & starts a fresh history
+func (m DatasetMethods) Merge(ctx context.Context, p *MergeParams) (*MergeResult, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "merge"), p)
+	if res, ok := got.(*MergeResult); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// DuplicatesParams defines parameters for the Duplicates method
+type DuplicatesParams struct {
+	Ref string `json:"ref"`
+	// optional column that identifies a row. when set rows are duplicates if
+	// they repeat a key, otherwise whole rows must match
+	Key string `json:"key"`
+}
+
+// DuplicatesResult is the result of checking a dataset for duplicate rows
+type DuplicatesResult struct {
+	Report *base.DuplicateReport `json:"report"`
+	// version saved with duplicates removed, only set when removing found
+	// duplicates
+	Dataset *dataset.Dataset `json:"dataset,omitempty"`
+}
+
+// Duplicates reports body rows that repeat an earlier row
+func (m DatasetMethods) Duplicates(ctx context.Context, p *DuplicatesParams) (*DuplicatesResult, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "duplicates"), p)
+	if res, ok := got.(*DuplicatesResult); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// RemoveDuplicates saves a new version of a dataset without body rows that
+// repeat an earlier row. No version is saved when there are no duplicates
+func (m DatasetMethods) RemoveDuplicates(ctx context.Context, p *DuplicatesParams) (*DuplicatesResult, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "removeduplicates"), p)
+	if res, ok := got.(*DuplicatesResult); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// RenameColumnParams defines parameters for renaming a dataset column
+type RenameColumnParams struct {
+	Ref  string `json:"ref"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// DescribeColumnParams defines parameters for setting column metadata.
+// Empty fields leave the column's existing values untouched
+type DescribeColumnParams struct {
+	Ref         string `json:"ref"`
+	Column      string `json:"column"`
+	Description string `json:"description"`
+	Unit        string `json:"unit"`
+	Example     string `json:"example"`
+}
+
+// Validate returns an error if DescribeColumnParams fields are in an invalid
+// state
+func (p *DescribeColumnParams) Validate() error {
+	if p.Column == "" {
+		return fmt.Errorf("a column name is required")
+	}
+	if p.Description == "" && p.Unit == "" && p.Example == "" {
+		return fmt.Errorf("a description, unit or example is required")
+	}
+	return nil
+}
+
+// SetMetaParams defines parameters for setting a single meta field
+type SetMetaParams struct {
+	Ref string `json:"ref"`
+	// Field is a dot-separated path into the meta component, eg: "title" or
+	// "license.type"
+	Field string `json:"field"`
+	// Value is decoded as JSON if possible, otherwise used as a string
+	Value string `json:"value"`
+}
+
+// SetMeta updates one field of a dataset's meta component, keeping all other
+// meta fields, and saves the change as a new version
+func (m DatasetMethods) SetMeta(ctx context.Context, p *SetMetaParams) (*dataset.Dataset, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "setmeta"), p)
+	if res, ok := got.(*dataset.Dataset); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// AttachParams defines parameters for attaching a file to a dataset
+type AttachParams struct {
+	Ref string `json:"ref"`
+	// FilePath is the local path of the file to attach
+	FilePath string `json:"filePath"`
+	// Name of the attachment, defaults to the base name of FilePath
+	Name string `json:"name"`
+}
+
+// Attach adds a file to a dataset as a named attachment, saving the change as
+// a new version. Attaching a file with the name of an existing attachment
+// replaces it
+func (m DatasetMethods) Attach(ctx context.Context, p *AttachParams) (*dataset.Dataset, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "attach"), p)
+	if res, ok := got.(*dataset.Dataset); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// LinkParams defines parameters for linking a directory to a dataset
+type LinkParams struct {
+	Ref string `json:"ref"`
+	// Dir is the local directory to link, which may already hold component
+	// files like meta.json or body.csv
+	Dir string `json:"dir"`
+}
+
+// LinkResult describes a directory linked to a dataset
+type LinkResult struct {
+	// Ref is the version of the dataset the directory was linked at
+	Ref string `json:"ref"`
+	Dir string `json:"dir"`
+	// Status compares the component files in the directory to the linked
+	// version, empty if the directory has no component files
+	Status []base.StatusItem `json:"status"`
+}
+
+// Link connects an existing directory to a dataset by writing a .qri-ref
+// linkfile, without writing component files. Component files already in
+// the directory are compared to the head version of the dataset, and
+// differences are reported in the result status instead of being overwritten
+func (m DatasetMethods) Link(ctx context.Context, p *LinkParams) (*LinkResult, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "link"), p)
+	if res, ok := got.(*LinkResult); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// ExportGitParams defines parameters for exporting dataset history to git
+type ExportGitParams struct {
+	Ref string `json:"ref"`
+	// Branch is the git branch commits are written to, defaults to "main"
+	Branch string `json:"branch"`
+	// Writer receives the stream as each version is written. when set
+	// ExportGit returns no bytes
+	Writer io.Writer `json:"-"`
+}
+
+// Validate returns an error if ExportGitParams fields are in an invalid state
+func (p *ExportGitParams) Validate() error {
+	if p.Ref == "" {
+		return fmt.Errorf("reference is required")
+	}
+	if strings.ContainsAny(p.Branch, " \t\n~^:?*[\\") {
+		return fmt.Errorf("invalid branch name %q", p.Branch)
+	}
+	return nil
+}
+
+// ExportGit serializes the history of a dataset as a git fast-import stream,
+// one git commit per version with component files like body.csv & meta.json.
+// Piping the stream to "git fast-import" mirrors the dataset into a git repo
+func (m DatasetMethods) ExportGit(ctx context.Context, p *ExportGitParams) ([]byte, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "exportgit"), p)
+	if res, ok := got.([]byte); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// GetAttachmentParams defines parameters for reading a dataset attachment
+type GetAttachmentParams struct {
+	Ref  string `json:"ref"`
+	Name string `json:"name"`
+}
+
+// GetAttachment returns the contents of a file attached to a dataset
+func (m DatasetMethods) GetAttachment(ctx context.Context, p *GetAttachmentParams) ([]byte, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "getattachment"), p)
+	if res, ok := got.([]byte); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// FingerprintParams defines parameters for fingerprinting a dataset
+type FingerprintParams struct {
+	Ref string `json:"ref"`
+	// BodyOnly fingerprints the body, ignoring other components
+	BodyOnly bool `json:"bodyOnly"`
+}
+
+// Fingerprint returns a hash of a dataset version's content that doesn't
+// depend on how the dataset is stored, for checking if two datasets hold the
+// same data. See base.Fingerprint
+func (m DatasetMethods) Fingerprint(ctx context.Context, p *FingerprintParams) (string, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "fingerprint"), p)
+	if res, ok := got.(string); ok {
+		return res, err
+	}
+	return "", dispatchReturnError(got, err)
+}
+
+// ApplyPatchParams defines parameters for applying a patch to a dataset
+type ApplyPatchParams struct {
+	Ref string `json:"ref"`
+	// path to a patch file, as made by DiffMethods.Patch
+	PatchPath string `json:"patchPath" qri:"fspath"`
+	// Patch to apply instead of reading PatchPath
+	Patch *Patch `json:"patch"`
+	// commit title, defaults to "apply patch"
+	Title string `json:"title"`
+}
+
+// ApplyPatchResult is the outcome of applying a patch
+type ApplyPatchResult struct {
+	Dataset *dataset.Dataset `json:"dataset"`
+	// BaseMatch is true when the patch was made against the version it was
+	// applied to
+	BaseMatch bool `json:"baseMatch"`
+	// Conflicts describes changes that were skipped because the dataset
+	// doesn't have the value the patch expects
+	Conflicts []string `json:"conflicts"`
+}
+
+// ApplyPatch applies the changes of a patch to a dataset, saving the result as
+// a new version. Changes that conflict with the dataset are skipped & listed
+// in the result. Applying a patch with only conflicting changes is an error
+func (m DatasetMethods) ApplyPatch(ctx context.Context, p *ApplyPatchParams) (*ApplyPatchResult, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "applypatch"), p)
+	if res, ok := got.(*ApplyPatchResult); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// RenameColumn changes the name of a column in the structure schema of a
+// dataset, saving the change as a new version. When body rows are objects the
+// keys of each row are renamed as well
+func (m DatasetMethods) RenameColumn(ctx context.Context, p *RenameColumnParams) (*dataset.Dataset, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "renamecolumn"), p)
+	if res, ok := got.(*dataset.Dataset); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// DescribeColumn sets the description, unit & example of a column in the
+// structure schema of a dataset, saving the change as a new version
+func (m DatasetMethods) DescribeColumn(ctx context.Context, p *DescribeColumnParams) (*dataset.Dataset, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "describecolumn"), p)
+	if res, ok := got.(*dataset.Dataset); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
 // RemoveParams defines parameters for remove command
 type RemoveParams struct {
 	Ref      string     `json:"ref"`
@@ -352,11 +1102,51 @@ func (m DatasetMethods) Remove(ctx context.Context, p *RemoveParams) (*RemoveRes
 	return nil, dispatchReturnError(got, err)
 }
 
+// SquashParams defines parameters for compacting the history of a dataset
+type SquashParams struct {
+	Ref string `json:"ref"`
+	// number of most recent versions to leave as-is, all older versions are
+	// collapsed into a single base version
+	Keep int `json:"keep"`
+}
+
+// Validate checks SquashParams for errors
+func (p *SquashParams) Validate() error {
+	if p.Keep < 0 {
+		return fmt.Errorf("keep must be greater than or equal to 0")
+	}
+	return nil
+}
+
+// SquashResponse gives the results of a squash
+type SquashResponse struct {
+	Ref         string `json:"ref"`
+	NumSquashed int    `json:"numSquashed"`
+}
+
+// Squash collapses all but the most recent versions of a dataset into a single
+// base version, removing older versions from the store. Squashing rewrites
+// history: peers that hold the full log won't share history with the squashed
+// dataset
+func (m DatasetMethods) Squash(ctx context.Context, p *SquashParams) (*SquashResponse, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "squash"), p)
+	if res, ok := got.(*SquashResponse); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
 // PullParams encapsulates parameters to the add command
 type PullParams struct {
 	Ref string `json:"ref"`
 	// only fetch logbook data
 	LogsOnly bool `json:"logsOnly"`
+	// fetch every component except the body, storing a "headless" version.
+	// the body is pulled on demand when it's first read
+	MetadataOnly bool `json:"metadataOnly"`
+	// only fetch version blocks that aren't already stored locally, completing
+	// an earlier pull that was interrupted
+	Resume bool `json:"resume"`
 }
 
 // Pull downloads and stores an existing dataset to a peer's repository via
@@ -376,6 +1166,9 @@ type PushParams struct {
 	// All indicates all versions of a dataset and the dataset namespace should
 	// be either published or removed
 	All bool `json:"all"`
+	// Verify confirms the remote has every block of the pushed version before
+	// marking the dataset as published
+	Verify bool `json:"verify"`
 }
 
 // Push posts a dataset version to a remote
@@ -393,6 +1186,9 @@ type ValidateParams struct {
 	BodyFilename      string `json:"bodyFilename" qri:"fspath"`
 	SchemaFilename    string `json:"schemaFilename" qri:"fspath"`
 	StructureFilename string `json:"structureFilename" qri:"fspath"`
+	// NoCache recomputes validation results for a stored dataset instead of
+	// using a cached result
+	NoCache bool `json:"noCache"`
 }
 
 // ValidateResponse is the result of running validate against a dataset
@@ -455,21 +1251,106 @@ func (m DatasetMethods) DAGInfo(ctx context.Context, p *DAGInfoParams) (*dag.Inf
 	return nil, dispatchReturnError(got, err)
 }
 
-// RenderParams defines parameters for the Render method
-type RenderParams struct {
-	// Ref is a string reference to the dataset to render
+// StorageInfoParams defines parameters for the StorageInfo method
+type StorageInfoParams struct {
 	Ref string `json:"ref"`
-	// Optionally pass an entire dataset in for rendering, if providing a dataset,
-	// the Ref field must be empty
-	Dataset *dataset.Dataset `json:"dataset"`
-	// Optional template override
-	Template []byte `json:"template"`
-	// TODO (b5): investigate if this field is still in use
-	UseFSI bool `json:"useFSI"`
-	// Output format. defaults to "html"
-	Format string `json:"format"`
-	// Selector
-	Selector string `json:"selector"`
+	// include all versions in dataset history, not just the referenced version
+	AllVersions bool `json:"allVersions"`
+}
+
+// StorageInfo describes the space one or more versions of a dataset occupy.
+// Blocks are content-addressed, so versions that share data store it once.
+// LogicalSize is the size versions would occupy with no sharing, UniqueSize
+// is the size of the distinct blocks actually stored
+type StorageInfo struct {
+	Versions     int    `json:"versions"`
+	Blocks       int    `json:"blocks"`
+	UniqueBlocks int    `json:"uniqueBlocks"`
+	LogicalSize  uint64 `json:"logicalSize"`
+	UniqueSize   uint64 `json:"uniqueSize"`
+	DedupSavings uint64 `json:"dedupSavings"`
+}
+
+// StorageInfo estimates the storage footprint of a dataset, accounting for
+// blocks shared between versions
+func (m DatasetMethods) StorageInfo(ctx context.Context, p *StorageInfoParams) (*StorageInfo, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "storageinfo"), p)
+	if res, ok := got.(*StorageInfo); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// ComponentsParams defines parameters for the Components method
+type ComponentsParams struct {
+	Ref string `json:"ref"`
+}
+
+// ComponentInfo describes a single component of a dataset version
+type ComponentInfo struct {
+	Present bool   `json:"present"`
+	Path    string `json:"path,omitempty"`
+	// size in bytes of the component and any blocks it links to
+	Size uint64 `json:"size,omitempty"`
+}
+
+// componentNames lists the components reported by the Components method
+var componentNames = []string{"body", "commit", "meta", "readme", "stats", "structure", "transform", "viz"}
+
+// Components reports which components a dataset version has, without loading
+// the contents of any component
+func (m DatasetMethods) Components(ctx context.Context, p *ComponentsParams) (map[string]ComponentInfo, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "components"), p)
+	if res, ok := got.(map[string]ComponentInfo); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// QualityParams defines parameters for the Quality method
+type QualityParams struct {
+	Ref string `json:"ref"`
+	// fraction of null values a column can have before it's flagged, between
+	// 0 and 1. nil uses base.DefaultMaxNullRate, zero flags any nulls
+	MaxNullRate *float64 `json:"maxNullRate,omitempty"`
+	// number of interquartile ranges a value must fall outside the middle half
+	// of a numeric column to be flagged as an outlier. nil uses
+	// base.DefaultOutlierIQRMultiplier
+	OutlierIQRMultiplier *float64 `json:"outlierIQRMultiplier,omitempty"`
+}
+
+// Quality checks the stats of a dataset for potential data quality problems
+func (m DatasetMethods) Quality(ctx context.Context, p *QualityParams) ([]base.QualityWarning, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "quality"), p)
+	if res, ok := got.([]base.QualityWarning); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// RenderParams defines parameters for the Render method
+type RenderParams struct {
+	// Ref is a string reference to the dataset to render
+	Ref string `json:"ref"`
+	// Optionally pass an entire dataset in for rendering, if providing a dataset,
+	// the Ref field must be empty
+	Dataset *dataset.Dataset `json:"dataset"`
+	// Optional template override
+	Template []byte `json:"template"`
+	// TODO (b5): investigate if this field is still in use
+	UseFSI bool `json:"useFSI"`
+	// Output format. defaults to "html"
+	Format string `json:"format"`
+	// Selector
+	Selector string `json:"selector"`
+	// InlineBody embeds the full body as JSON in the rendered viz, producing a
+	// self-contained HTML file. Only valid with the viz selector
+	InlineBody bool `json:"inlineBody"`
+	// Limit & Offset select the rows of a rendered body table. a zero limit
+	// renders up to base.MaxBodyTableRows rows. Only valid with the body
+	// selector
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
 }
 
 // SetNonZeroDefaults assigns default values
@@ -488,7 +1369,16 @@ func (p *RenderParams) Validate() error {
 		return dsref.ErrEmptyRef
 	}
 	if p.Selector == "" {
-		return fmt.Errorf("selector must be one of 'viz' or 'readme'")
+		return fmt.Errorf("selector must be one of 'viz', 'readme' or 'body'")
+	}
+	if p.InlineBody && p.Selector != "viz" {
+		return fmt.Errorf("can only inline the body when rendering viz")
+	}
+	if p.Limit < 0 || p.Offset < 0 {
+		return fmt.Errorf("invalid limit / offset settings")
+	}
+	if (p.Limit != 0 || p.Offset != 0) && p.Selector != "body" {
+		return fmt.Errorf("can only use limit & offset when rendering the body")
 	}
 	return nil
 }
@@ -521,6 +1411,41 @@ type datasetImpl struct{}
 
 // Get retrieves datasets and components for a given reference.t
 func (datasetImpl) Get(scope scope, p *GetParams) (*GetResult, error) {
+	if p.Selector == "body" {
+		setDefaultBodyLimit(scope.Config(), p)
+	}
+	switch p.Format {
+	case "csv":
+		data, err := datasetImpl{}.GetCSV(scope, p)
+		if err != nil {
+			return nil, err
+		}
+		return &GetResult{Bytes: data}, nil
+	case "ndjson":
+		return getBodyNDJSON(scope, p)
+	case "", "json":
+	default:
+		return getBodyEncoded(scope, p)
+	}
+	if p.Selector == "body" && len(p.Columns) > 0 {
+		return getBodyColumns(scope, p)
+	}
+	if p.Selector == "body" && len(p.Keys) > 0 {
+		return getBodyKeys(scope, p)
+	}
+	if p.Selector == "body" && p.Scalar {
+		return getBodyScalar(scope, p)
+	}
+	if p.Selector == "body" && p.TimeColumn != "" {
+		return getBodyTimeRange(scope, p)
+	}
+	if p.Selector == "body" && p.Decimate > 0 {
+		return getBodyDecimated(scope, p)
+	}
+	if p.Selector == "body" && p.Transpose {
+		return getBodyTransposed(scope, p)
+	}
+
 	_, ds, err := openAndLoadDataset(scope, p)
 	if err != nil {
 		return nil, err
@@ -544,11 +1469,22 @@ func (datasetImpl) Get(scope scope, p *GetParams) (*GetResult, error) {
 		if err := ensureValidGetSize(ds, p.Limit, p.All); err != nil {
 			return nil, err
 		}
-		res.Value, err = base.GetBody(ds, p.Limit, p.Offset, p.All)
+		if !p.All && p.Limit == 1 {
+			// fetching a single row stops reading the body once the row is
+			// found, and is an error if the row doesn't exist
+			res.Value, err = base.GetBodyRow(ds, p.Offset)
+		} else {
+			res.Value, err = base.GetBody(ds, p.Limit, p.Offset, p.All)
+		}
 		if err != nil {
 			log.Debugf("Get dataset, base.GetBody %q failed, error: %s", ds, err)
 			return nil, err
 		}
+		if p.Typed {
+			if res.Value, res.TypeErrors, err = base.CoerceBodyTypes(ds.Structure, res.Value, p.TypedStrict); err != nil {
+				return nil, err
+			}
+		}
 	case p.Selector == "stats":
 		sa, err := scope.Stats().Stats(scope.Context(), ds)
 		if err != nil {
@@ -566,15 +1502,184 @@ func (datasetImpl) Get(scope scope, p *GetParams) (*GetResult, error) {
 			return nil, err
 		}
 		// `qri get <selector>` loads only the applicable component / field
-		res.Value, err = base.ApplyPath(ds, p.Selector)
+		if p.Strict {
+			res.Value, err = base.ApplyPathStrict(ds, p.Selector)
+		} else {
+			res.Value, err = base.ApplyPath(ds, p.Selector)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// getBodyNDJSON encodes the body as newline-delimited JSON. Bodies without
+// row selections or type conversion are streamed from the body entry reader,
+// others are encoded from the structured data Get returns
+func getBodyNDJSON(scope scope, p *GetParams) (*GetResult, error) {
+	jp := *p
+	jp.Format = ""
+	if jp.bodySelection() != "" || p.Typed {
+		res, err := datasetImpl{}.Get(scope, &jp)
 		if err != nil {
 			return nil, err
 		}
+		if res.Bytes, err = base.BodyNDJSON(res.Value); err != nil {
+			return nil, err
+		}
+		res.Value = nil
+		return res, nil
+	}
+
+	if !jp.All && (jp.Limit < 0 || jp.Offset < 0) {
+		return nil, fmt.Errorf("invalid limit / offset settings")
+	}
+	_, ds, err := openAndLoadDataset(scope, &jp)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureValidGetSize(ds, jp.Limit, jp.All); err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err := base.WriteBodyNDJSON(buf, ds, jp.Limit, jp.Offset, jp.All); err != nil {
+		return nil, err
+	}
+	return &GetResult{Bytes: buf.Bytes()}, nil
+}
+
+// getBodyColumns reads selected columns of a dataset body. Bodies stored with
+// column blocks are read without opening the full body
+func getBodyColumns(scope scope, p *GetParams) (*GetResult, error) {
+	ds, err := scope.Loader().LoadDataset(scope.Context(), p.Ref)
+	if err != nil {
+		return nil, err
+	}
+	if !base.HasColumnBlocks(ds.Structure) {
+		if _, ds, err = openAndLoadDataset(scope, p); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := base.ReadColumns(scope.Context(), scope.Filesystem(), ds, p.Columns, p.Limit, p.Offset, p.All)
+	if err != nil {
+		return nil, err
+	}
+	return &GetResult{Value: rows}, nil
+}
+
+// getBodyKeys scans a dataset body for rows matching a set of keys
+func getBodyKeys(scope scope, p *GetParams) (*GetResult, error) {
+	_, ds, err := openAndLoadDataset(scope, p)
+	if err != nil {
+		return nil, err
+	}
+	rows, missing, err := base.SelectRowsByKey(ds, p.KeyColumn, p.Keys)
+	if err != nil {
+		return nil, err
+	}
+	res := &GetResult{Value: rows, MissingKeys: missing}
+	if p.Typed {
+		if res.Value, res.TypeErrors, err = base.CoerceBodyTypes(ds.Structure, rows, p.TypedStrict); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// getBodyTimeRange scans a dataset body for rows with a time in a range
+func getBodyTimeRange(scope scope, p *GetParams) (*GetResult, error) {
+	from, to, err := p.timeRange()
+	if err != nil {
+		return nil, err
+	}
+	_, ds, err := openAndLoadDataset(scope, p)
+	if err != nil {
+		return nil, err
+	}
+	rows, skipped, err := base.SelectRowsInTimeRange(ds, p.TimeColumn, from, to, p.Limit, p.Offset, p.All, p.TimeStrict)
+	if err != nil {
+		return nil, err
+	}
+	res := &GetResult{Value: rows, SkippedRows: skipped}
+	if p.Typed {
+		if res.Value, res.TypeErrors, err = base.CoerceBodyTypes(ds.Structure, rows, p.TypedStrict); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// getBodyDecimated down-samples a dataset body for plotting
+func getBodyDecimated(scope scope, p *GetParams) (*GetResult, error) {
+	_, ds, err := openAndLoadDataset(scope, p)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := base.DecimateBody(ds, p.Decimate, p.DecimateColumn)
+	if err != nil {
+		return nil, err
+	}
+	res := &GetResult{Value: rows}
+	if p.Typed {
+		if res.Value, res.TypeErrors, err = base.CoerceBodyTypes(ds.Structure, rows, p.TypedStrict); err != nil {
+			return nil, err
+		}
 	}
+	return res, nil
+}
 
+// getBodyTransposed reads a whole body with rows & columns swapped
+func getBodyTransposed(scope scope, p *GetParams) (*GetResult, error) {
+	_, ds, err := openAndLoadDataset(scope, p)
+	if err != nil {
+		return nil, err
+	}
+	// check the recorded length before reading the whole body
+	if ds.Structure != nil && ds.Structure.Entries > base.MaxTransposeRows {
+		return nil, fmt.Errorf("body has %d rows, only bodies with %d rows or fewer can be transposed", ds.Structure.Entries, base.MaxTransposeRows)
+	}
+	// entries aren't always recorded, read one row past the limit to find
+	// bodies that are too long without reading all of them
+	body, err := base.GetBody(ds, base.MaxTransposeRows+1, 0, false)
+	if err != nil {
+		return nil, err
+	}
+	rows, ok := body.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("only bodies that are arrays of rows can be transposed")
+	}
+	if len(rows) > base.MaxTransposeRows {
+		return nil, fmt.Errorf("body has more than %d rows, only bodies with %d rows or fewer can be transposed", base.MaxTransposeRows, base.MaxTransposeRows)
+	}
+	res := &GetResult{}
+	if p.Typed {
+		if body, res.TypeErrors, err = base.CoerceBodyTypes(ds.Structure, rows, p.TypedStrict); err != nil {
+			return nil, err
+		}
+		rows = body.([]interface{})
+	}
+	if res.Value, err = base.TransposeBody(ds.Structure, rows); err != nil {
+		return nil, err
+	}
 	return res, nil
 }
 
+// getBodyScalar reads the single value of a 1x1 body
+func getBodyScalar(scope scope, p *GetParams) (*GetResult, error) {
+	_, ds, err := openAndLoadDataset(scope, p)
+	if err != nil {
+		return nil, err
+	}
+	val, err := base.BodyScalar(ds)
+	if err != nil {
+		return nil, err
+	}
+	return &GetResult{Value: val}, nil
+}
+
 // TODO(b5): pretty sure this can be factored away completely
 func openAndLoadDataset(scope scope, p *GetParams) (*dsref.Ref, *dataset.Dataset, error) {
 	ds, err := scope.Loader().LoadDataset(scope.Context(), p.Ref)
@@ -584,13 +1689,62 @@ func openAndLoadDataset(scope scope, p *GetParams) (*dsref.Ref, *dataset.Dataset
 
 	ref := dsref.ConvertDatasetToVersionInfo(ds).SimpleRef()
 
-	if err = base.OpenDataset(scope.Context(), scope.Filesystem(), ds); err != nil {
+	fs := scope.Filesystem()
+	if bodyMissing(scope.Context(), fs, ds) {
+		if p.Selector != "" && p.Selector != "body" && p.Selector != "stats" {
+			// headless version & the body isn't needed, leave it unopened
+			return &ref, ds, openHeadlessDataset(scope.Context(), fs, ds)
+		}
+		if err = pullMissingBody(scope, ref); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err = base.OpenDataset(scope.Context(), fs, ds); err != nil {
 		log.Debugf("base.OpenDataset failed, error: %s", err)
 		return nil, nil, err
 	}
 	return &ref, ds, nil
 }
 
+// bodyMissing reports if the body of a dataset isn't stored locally, which is
+// the case for "headless" versions fetched with a metadata-only pull
+func bodyMissing(ctx context.Context, fs qfs.Filesystem, ds *dataset.Dataset) bool {
+	if ds.BodyPath == "" || ds.BodyFile() != nil {
+		return false
+	}
+	has, err := fs.Has(ctx, ds.BodyPath)
+	if err != nil {
+		log.Debugf("checking for body %q: %s", ds.BodyPath, err)
+		return false
+	}
+	return !has
+}
+
+// openHeadlessDataset opens all files of a dataset except the body
+func openHeadlessDataset(ctx context.Context, fs qfs.Filesystem, ds *dataset.Dataset) error {
+	bodyPath := ds.BodyPath
+	ds.BodyPath = ""
+	defer func() { ds.BodyPath = bodyPath }()
+	return base.OpenDataset(ctx, fs, ds)
+}
+
+// pullMissingBody completes a headless version by pulling the full version
+// from the network
+func pullMissingBody(scope scope, ref dsref.Ref) error {
+	lookup := dsref.Ref{Username: ref.Username, Name: ref.Name}
+	location, err := scope.inst.ResolveReference(scope.Context(), &lookup, "network")
+	if err != nil {
+		return fmt.Errorf("body of %s isn't stored locally, finding a source to pull it from: %w", ref.Human(), err)
+	}
+	log.Infof("pulling missing body of %s from location: %s", ref.Human(), location)
+	lookup.Path = ref.Path
+	if _, err = scope.RemoteClient().PullDataset(scope.Context(), &lookup, location); err != nil {
+		return fmt.Errorf("pulling body of %s: %w", ref.Human(), err)
+	}
+	return nil
+}
+
 func inlineAllScriptFiles(ctx context.Context, ds *dataset.Dataset, resolver qfs.PathResolver) error {
 	if ds.Readme != nil {
 		if err := ds.Readme.InlineScriptFile(ctx, resolver); err != nil {
@@ -610,12 +1764,49 @@ func inlineAllScriptFiles(ctx context.Context, ds *dataset.Dataset, resolver qfs
 	return nil
 }
 
+// getOnlyBodyOptionsError returns an error if p uses body options only Get
+// supports, naming the output the caller is getting
+func getOnlyBodyOptionsError(p *GetParams, output string) error {
+	for _, sel := range getBodySelections {
+		if sel.getOnly && sel.set(p) {
+			return fmt.Errorf("cannot %s when getting %s", sel.desc, output)
+		}
+	}
+	if p.Typed {
+		return fmt.Errorf("cannot convert body types when getting %s", output)
+	}
+	return nil
+}
+
 func (datasetImpl) GetCSV(scope scope, p *GetParams) ([]byte, error) {
+	if err := getOnlyBodyOptionsError(p, "csv"); err != nil {
+		return nil, err
+	}
+	setDefaultBodyLimit(scope.Config(), p)
+	enc := base.CSVEncoding{BOM: p.CSVBOM, CRLF: p.CSVCRLF}
+	if len(p.Columns) > 0 {
+		res, err := getBodyColumns(scope, p)
+		if err != nil {
+			return nil, err
+		}
+		if p.CSVNulls || p.NullToken != "" {
+			return base.ColumnsNullCSV(p.Columns, res.Value.([]interface{}), p.NullToken, enc)
+		}
+		return base.ColumnsCSV(p.Columns, res.Value.([]interface{}), enc)
+	}
 	_, ds, err := openAndLoadDataset(scope, p)
 	if err != nil {
 		return nil, err
 	}
 
+	if p.Selector == "stats" {
+		sa, err := scope.Stats().Stats(scope.Context(), ds)
+		if err != nil {
+			return nil, err
+		}
+		return base.StatsCSV(ds.Structure, sa.Stats)
+	}
+
 	var fc dataset.FormatConfig
 
 	if ds.Structure != nil {
@@ -633,70 +1824,250 @@ func (datasetImpl) GetCSV(scope scope, p *GetParams) ([]byte, error) {
 		return nil, err
 	}
 
+	if p.CSVNulls || p.NullToken != "" {
+		return base.NullCSVBody(ds, p.Limit, p.Offset, p.All, p.NullToken, enc)
+	}
+
 	bodyBytes, err := base.ReadBodyBytes(ds, dataset.CSVDataFormat, fc, p.Limit, p.Offset, p.All)
 	if err != nil {
 		log.Debugf("lib.getBodyBytes, body, base.GetBody %q failed, error: %s", ds, err)
 		return nil, err
 	}
-	return bodyBytes, nil
+	return enc.Encode(bodyBytes)
 }
 
-func (datasetImpl) GetZip(scope scope, p *GetParams) (*GetZipResults, error) {
-	ref, ds, err := openAndLoadDataset(scope, p)
-	if err != nil {
-		return nil, err
+func (datasetImpl) GetHTML(scope scope, p *GetParams) ([]byte, error) {
+	if len(p.Columns) > 0 {
+		return nil, fmt.Errorf("cannot select columns when getting html")
 	}
-
-	var outBuf bytes.Buffer
-	var zipFile io.Writer
-	zipFile = &outBuf
-	// TODO(dustmop): This function is inefficient and a poor use of logbook, but it's
-	// necessary until dscache is in use.
-	initID, err := scope.Logbook().RefToInitID(*ref)
-	if err != nil {
+	if err := getOnlyBodyOptionsError(p, "html"); err != nil {
 		return nil, err
 	}
-	err = archive.WriteZip(scope.Context(), scope.Filesystem(), ds, "json", initID, *ref, zipFile)
-	if err != nil {
-		return nil, err
+	if p.Selector != "body" {
+		return nil, fmt.Errorf("can only get html of the body component, selector must be 'body'")
 	}
-	filename, err := archive.GenerateFilename(ds, "zip")
+	setDefaultBodyLimit(scope.Config(), p)
+	if !p.All && (p.Limit < 0 || p.Offset < 0) {
+		return nil, fmt.Errorf("invalid limit / offset settings")
+	}
+
+	// tables are capped at base.MaxBodyTableRows, so large bodies don't need
+	// to be rejected like they are when getting all of a body
+	_, ds, err := openAndLoadDataset(scope, p)
 	if err != nil {
 		return nil, err
 	}
-	return &GetZipResults{Bytes: outBuf.Bytes(), GeneratedName: filename}, nil
+	return base.RenderBodyTable(ds, p.Limit, p.Offset, p.All)
 }
 
-// maximum size of the body that is allowed to be returned by get. A variable
-// is used instead of a constant so that tests can override it.
-// TODO(dustmop): Move this to configuration so that users can override it or
-// customize their own limit
-var maxBodySizeToGetAll = 200 * 1024 * 1024
-
-func ensureValidGetSize(ds *dataset.Dataset, limit int, all bool) error {
-	if ds.Structure == nil {
-		return nil
+func (datasetImpl) GetFixedWidth(scope scope, p *GetFixedWidthParams) ([]byte, error) {
+	if p.Selector != "body" {
+		return nil, fmt.Errorf("can only get fixed-width text of the body component, selector must be 'body'")
 	}
-
-	if limit == -1 || all {
-		bodySize := ds.Structure.Length
-		if bodySize >= maxBodySizeToGetAll {
-			return fmt.Errorf("body is too large to get all: %d larger than %d",
-				bodySize, maxBodySizeToGetAll)
+	if err := getOnlyBodyOptionsError(&p.GetParams, "fixed-width text"); err != nil {
+		return nil, err
+	}
+	setDefaultBodyLimit(scope.Config(), &p.GetParams)
+	if len(p.Columns) > 0 {
+		res, err := getBodyColumns(scope, &p.GetParams)
+		if err != nil {
+			return nil, err
 		}
+		return base.FixedWidth(res.Value.([]interface{}), p.Widths, p.Overflow)
 	}
-	return nil
-}
 
-// Activity returns the activity and changes for a given dataset
-func (datasetImpl) Activity(scope scope, params *ActivityParams) ([]dsref.VersionInfo, error) {
-	// ensure valid limit value
-	if params.Limit <= 0 {
-		params.Limit = 25
+	_, ds, err := openAndLoadDataset(scope, &p.GetParams)
+	if err != nil {
+		return nil, err
 	}
-	// ensure valid offset value
-	if params.Offset < 0 {
-		params.Offset = 0
+	if err := ensureValidGetSize(ds, p.Limit, p.All); err != nil {
+		return nil, err
+	}
+	return base.FixedWidthBody(ds, p.Limit, p.Offset, p.All, p.Widths, p.Overflow)
+}
+
+// getBodyEncoded encodes the body in a format registered with
+// base.RegisterBodyFormat
+func getBodyEncoded(scope scope, p *GetParams) (*GetResult, error) {
+	if p.Selector != "body" {
+		return nil, fmt.Errorf("can only get the body component as %s, selector must be 'body'", p.Format)
+	}
+	if len(p.Columns) > 0 {
+		return nil, fmt.Errorf("cannot select columns when getting the body as %s", p.Format)
+	}
+	if err := getOnlyBodyOptionsError(p, "the body as "+p.Format); err != nil {
+		return nil, err
+	}
+	if _, ok := base.BodyCodecFor(p.Format); !ok {
+		return nil, fmt.Errorf("unknown body format %q", p.Format)
+	}
+
+	_, ds, err := openAndLoadDataset(scope, p)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureValidGetSize(ds, p.Limit, p.All); err != nil {
+		return nil, err
+	}
+	data, err := base.EncodeBody(ds, p.Format, p.Limit, p.Offset, p.All)
+	if err != nil {
+		return nil, err
+	}
+	return &GetResult{Bytes: data}, nil
+}
+
+func (datasetImpl) GetDCAT(scope scope, p *GetParams) ([]byte, error) {
+	if p.Selector != "meta" {
+		return nil, fmt.Errorf("can only get dcat of the meta component, selector must be 'meta'")
+	}
+	ds, err := scope.Loader().LoadDataset(scope.Context(), p.Ref)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(base.MetaDCAT(ds.Meta), "", "  ")
+}
+
+func (datasetImpl) GetSQL(scope scope, p *GetSQLParams) ([]byte, error) {
+	if p.Selector != "structure" {
+		return nil, fmt.Errorf("can only get sql of the structure component, selector must be 'structure'")
+	}
+	ds, err := scope.Loader().LoadDataset(scope.Context(), p.Ref)
+	if err != nil {
+		return nil, err
+	}
+	ddl, err := base.StructureDDL(ds.Structure, ds.Name, p.Dialect)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(ddl), nil
+}
+
+func (datasetImpl) BodyDelta(scope scope, p *BodyDeltaParams) (*BodyDelta, error) {
+	if p.SincePath == "" {
+		return nil, fmt.Errorf("a since path is required")
+	}
+	ctx := scope.Context()
+	ds, err := scope.Loader().LoadDataset(ctx, p.Ref)
+	if err != nil {
+		return nil, err
+	}
+	prev, err := dsfs.LoadDataset(ctx, scope.Filesystem(), p.SincePath)
+	if err != nil {
+		return nil, fmt.Errorf("loading since version: %w", err)
+	}
+	if err := base.OpenDataset(ctx, scope.Filesystem(), ds); err != nil {
+		return nil, err
+	}
+	if err := base.OpenDataset(ctx, scope.Filesystem(), prev); err != nil {
+		return nil, fmt.Errorf("opening since version: %w", err)
+	}
+	return bodyRowDelta(prev, ds, p.Key)
+}
+
+func (datasetImpl) GetZip(scope scope, p *GetParams) (*GetZipResults, error) {
+	if len(p.Columns) > 0 {
+		return nil, fmt.Errorf("cannot select columns when getting a zip archive")
+	}
+	if err := getOnlyBodyOptionsError(p, "a zip archive"); err != nil {
+		return nil, err
+	}
+	ref, ds, err := openAndLoadDataset(scope, p)
+	if err != nil {
+		return nil, err
+	}
+
+	var outBuf bytes.Buffer
+	var zipFile io.Writer
+	zipFile = &outBuf
+	// TODO(dustmop): This function is inefficient and a poor use of logbook, but it's
+	// necessary until dscache is in use.
+	initID, err := scope.Logbook().RefToInitID(*ref)
+	if err != nil {
+		return nil, err
+	}
+	err = archive.WriteZip(scope.Context(), scope.Filesystem(), ds, "json", initID, *ref, zipFile)
+	if err != nil {
+		return nil, err
+	}
+	filename, err := archive.GenerateFilename(ds, "zip")
+	if err != nil {
+		return nil, err
+	}
+	return &GetZipResults{Bytes: outBuf.Bytes(), GeneratedName: filename}, nil
+}
+
+// LogBytes returns the signed flatbuffer log of a dataset
+func (datasetImpl) LogBytes(scope scope, p *LogBytesParams) ([]byte, error) {
+	ref, _, err := scope.ParseAndResolveRef(scope.Context(), p.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	book := scope.Logbook()
+	if book.Owner() == nil || book.Owner().GetSigner() == nil {
+		return nil, fmt.Errorf("signing a log requires the logbook owner's private key or signer")
+	}
+	lg, err := book.UserDatasetBranchesLog(scope.Context(), ref.InitID)
+	if err != nil {
+		return nil, err
+	}
+	return book.LogBytes(lg, book.Owner().GetSigner())
+}
+
+// maximum size of the body that is allowed to be returned by get. A variable
+// is used instead of a constant so that tests can override it.
+// TODO(dustmop): Move this to configuration so that users can override it or
+// customize their own limit
+var maxBodySizeToGetAll = 200 * 1024 * 1024
+
+func ensureValidGetSize(ds *dataset.Dataset, limit int, all bool) error {
+	if ds.Structure == nil {
+		return nil
+	}
+
+	if limit == -1 || all {
+		bodySize := ds.Structure.Length
+		if bodySize >= maxBodySizeToGetAll {
+			return fmt.Errorf("body is too large to get all: %d larger than %d",
+				bodySize, maxBodySizeToGetAll)
+		}
+	}
+	return nil
+}
+
+// Dependents lists local datasets with transforms that load a dataset
+func (datasetImpl) Dependents(scope scope, p *DependentsParams) ([]dsref.VersionInfo, error) {
+	ctx := scope.Context()
+	ref, _, err := scope.ParseAndResolveRef(ctx, p.Ref)
+	if err != nil {
+		return nil, err
+	}
+	if scope.CollectionSet() == nil {
+		return nil, fmt.Errorf("no collection")
+	}
+
+	versionPaths := map[string]bool{}
+	if ref.Path != "" {
+		versionPaths[ref.Path] = true
+	}
+	if items, err := scope.Logbook().Items(ctx, ref, 0, -1, ""); err == nil {
+		for _, item := range items {
+			versionPaths[item.Path] = true
+		}
+	}
+
+	return base.Dependents(ctx, scope.Filesystem(), scope.CollectionSet(), scope.ActiveProfile().ID, ref, versionPaths)
+}
+
+// Activity returns the activity and changes for a given dataset
+func (datasetImpl) Activity(scope scope, params *ActivityParams) ([]dsref.VersionInfo, error) {
+	// ensure valid limit value
+	if params.Limit <= 0 {
+		params.Limit = 25
+	}
+	// ensure valid offset value
+	if params.Offset < 0 {
+		params.Offset = 0
 	}
 
 	if params.Pull && scope.SourceName() != "network" {
@@ -710,8 +2081,14 @@ func (datasetImpl) Activity(scope scope, params *ActivityParams) ([]dsref.Versio
 
 	if location == "" {
 		// local resolution
+		if params.IncludeDeleted {
+			return base.DatasetLogWithDeleted(scope.Context(), scope.Repo(), ref, params.Limit, params.Offset, params.Term)
+		}
 		return base.DatasetLog(scope.Context(), scope.Repo(), ref, params.Limit, params.Offset, params.Term, true)
 	}
+	if params.IncludeDeleted {
+		return nil, fmt.Errorf("deleted versions can only be listed from local history")
+	}
 
 	logs, err := scope.RemoteClient().FetchLogs(scope.Context(), ref, location)
 	if err != nil {
@@ -747,6 +2124,9 @@ func (datasetImpl) Activity(scope scope, params *ActivityParams) ([]dsref.Versio
 				if ds.Commit != nil {
 					items[i].CommitMessage = ds.Commit.Message
 				}
+				if ds.Structure != nil {
+					items[i].BodyRows = ds.Structure.Entries
+				}
 			}
 		}
 	}
@@ -754,6 +2134,31 @@ func (datasetImpl) Activity(scope scope, params *ActivityParams) ([]dsref.Versio
 	return items, nil
 }
 
+// ActivityFeed renders a dataset's history as an Atom feed, using meta from
+// the latest version for the feed title & description
+func (datasetImpl) ActivityFeed(scope scope, params *ActivityParams) ([]byte, error) {
+	versions, err := datasetImpl{}.Activity(scope, params)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := dsref.Parse(params.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var md *dataset.Meta
+	if len(versions) > 0 {
+		head := versions[0]
+		ref = dsref.Ref{Username: head.Username, Name: head.Name}
+		if ds, err := dsfs.LoadDataset(scope.Context(), scope.Filesystem(), head.Path); err == nil {
+			md = ds.Meta
+		} else {
+			log.Debugw("loading feed meta", "path", head.Path, "err", err)
+		}
+	}
+	return base.ActivityAtom(ref, md, versions)
+}
+
 // IsSelectorScriptFile takes a selector string and returns true if the selector contains "script"
 func IsSelectorScriptFile(selector string) bool {
 	if selector == "" {
@@ -784,6 +2189,12 @@ func (datasetImpl) Save(scope scope, p *SaveParams) (*dataset.Dataset, error) {
 	if p.Private {
 		return nil, fmt.Errorf("option to make dataset private not yet implemented, refer to https://github.com/qri-io/qri/issues/291 for updates")
 	}
+	if p.MinChangeRows < 0 {
+		return nil, fmt.Errorf("minimum changed rows cannot be negative")
+	}
+	if p.ChunkSize < 0 || p.ChunkSize > dsfs.MaxChunkSize {
+		return nil, fmt.Errorf("chunk size must be between 0 and %d bytes", dsfs.MaxChunkSize)
+	}
 
 	// If the dscache doesn't exist yet, it will only be created if the appropriate flag enables it.
 	if scope.UseDscache() {
@@ -803,6 +2214,9 @@ func (datasetImpl) Save(scope scope, p *SaveParams) (*dataset.Dataset, error) {
 			Message: p.Message,
 		},
 	})
+	if p.CommitTime != nil {
+		ds.Commit.Timestamp = p.CommitTime.UTC()
+	}
 
 	if len(p.FilePaths) > 0 {
 		// TODO (b5): handle this with a qfs.Filesystem
@@ -814,6 +2228,69 @@ func (datasetImpl) Save(scope scope, p *SaveParams) (*dataset.Dataset, error) {
 		ds = dsf
 	}
 
+	if p.BodyReader != nil {
+		if p.InlineBody != nil {
+			return nil, fmt.Errorf("cannot save with both a body reader and an inline body")
+		}
+		name, err := bodyReaderFilename(ds)
+		if err != nil {
+			return nil, err
+		}
+		// a body file that's already set is never opened from the body path
+		body, err := base.DecodeBodyFile(qfs.NewMemfileReader(name, p.BodyReader))
+		if err != nil {
+			return nil, err
+		}
+		ds.SetBodyFile(body)
+		if err := base.InferTSVStructure(ds); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.InlineBody != nil {
+		if ds.BodyPath != "" {
+			return nil, fmt.Errorf("cannot save with both an inline body and a body path")
+		}
+		df, err := dataset.ParseDataFormatString(p.InlineBodyFormat)
+		if err != nil {
+			return nil, fmt.Errorf("inline body format: %w", err)
+		}
+		body := p.InlineBody
+		if base.IsStructSlice(body) {
+			if body, err = structBody(ds, body, df); err != nil {
+				return nil, err
+			}
+		}
+		bodyFile, err := base.InlineBodyFile(body, df)
+		if err != nil {
+			return nil, err
+		}
+		ds.SetBodyFile(bodyFile)
+	}
+
+	var provenance *dsfs.Provenance
+	if p.FromSQL != "" || p.SQLSource != "" {
+		src, err := setSQLBody(scope, ds, p.FromSQL, p.SQLSource)
+		if err != nil {
+			return nil, err
+		}
+		provenance = &dsfs.Provenance{BodySource: src}
+	}
+
+	if p.FromDB != "" || p.DBQuery != "" {
+		src, err := setDBBody(scope, ds, p.FromDB, p.DBQuery)
+		if err != nil {
+			return nil, err
+		}
+		provenance = &dsfs.Provenance{BodySource: src}
+	}
+
+	if p.Synthesize != 0 {
+		if err := setSynthesizedBody(ds, p.Synthesize, p.SynthesizeSeed); err != nil {
+			return nil, err
+		}
+	}
+
 	manualChanges := make(map[string]struct{})
 	for comp := range ds.PathMap("dataset") {
 		manualChanges[comp] = struct{}{}
@@ -855,6 +2332,7 @@ func (datasetImpl) Save(scope scope, p *SaveParams) (*dataset.Dataset, error) {
 	if !p.Force &&
 		!p.Apply &&
 		p.Drop == "" &&
+		p.ColumnDescriptionsPath == "" &&
 		ds.BodyPath == "" &&
 		ds.Body == nil &&
 		ds.BodyFile() == nil &&
@@ -867,156 +2345,1116 @@ func (datasetImpl) Save(scope scope, p *SaveParams) (*dataset.Dataset, error) {
 		return nil, fmt.Errorf("no changes to save")
 	}
 
-	if err = base.OpenDataset(scope.Context(), scope.Filesystem(), ds); err != nil {
-		log.Debugw("save OpenDataset", "err", err.Error())
+	if err = base.OpenDataset(scope.Context(), scope.Filesystem(), ds); err != nil {
+		log.Debugw("save OpenDataset", "err", err.Error())
+		return nil, err
+	}
+
+	// If applying a transform, execute its script before saving
+	if p.Apply {
+		if ds.Transform == nil {
+			// if no transform component exists, load the latest transform component
+			// from history
+			if isNew {
+				return nil, fmt.Errorf("cannot apply while saving without a transform")
+			}
+
+			prevTransformDataset, err := base.LoadRevs(scope.Context(), scope.Filesystem(), ref, []*dsref.Rev{{Field: "tf", Gen: 1}})
+			if err != nil {
+				return nil, fmt.Errorf("loading transform component from history: %w", err)
+			}
+			ds.Transform = prevTransformDataset.Transform
+		}
+
+		scriptOut := p.ScriptOutput
+		secrets := p.Secrets
+		runID := ds.Commit.RunID
+		if runID == "" {
+			// if there is no given runID, allocate an ID for the transform,
+			// subscribe to print output & build up the run.State
+			runID = run.NewID()
+		}
+		runTrigger := p.RunTrigger
+		if runTrigger == "" {
+			runTrigger = run.TriggerManual
+		}
+		runState = &run.State{ID: runID, Trigger: runTrigger}
+
+		scope.Bus().SubscribeID(func(ctx context.Context, e event.Event) error {
+			runState.AddTransformEvent(e)
+			if e.Type == event.ETTransformPrint {
+				if msg, ok := e.Payload.(event.TransformMessage); ok {
+					if p.ScriptOutput != nil {
+						io.WriteString(scriptOut, msg.Msg)
+						io.WriteString(scriptOut, "\n")
+					}
+				}
+			}
+			return nil
+		}, runID)
+
+		// TODO(dustmop): Get actual size info from the proper place
+		sizeInfo := transform.SizeInfo{}
+
+		// apply the transform
+		shouldWait := true
+		transformer := transform.NewTransformer(scope.AppContext(), scope.Filesystem(), scope.Loader(), scope.Bus(), sizeInfo)
+		transformer.SetAllowedHosts(transformAllowedHosts(scope.Config()))
+		transformer.SetMaxMemoryMB(transformMaxMemoryMB(scope.Config()))
+		transformer.SetSecretProviders(scope.SecretProviders())
+		transformer.SetStatsLoader(transformStatsLoader(scope))
+		transformer.SetResumeState(p.ResumeState)
+		if err := transformer.Commit(scope.Context(), ref.InitID, ds, runID, shouldWait, secrets); err != nil {
+			log.Errorw("transform run error", "err", err.Error())
+			runState.Message = err.Error()
+			if err := scope.Logbook().WriteTransformRun(scope.Context(), scope.ActiveProfile(), ref.InitID, runState); err != nil {
+				log.Debugw("writing errored transform run to logbook:", "err", err.Error())
+				return nil, err
+			}
+
+			return nil, err
+		}
+
+		// compare manual changes to the changes made by the transform, make
+		// sure they don't conflict
+		if manualChanges != nil {
+			changes := transformer.Changes()
+			for comp := range changes {
+				if _, found := manualChanges[comp]; found {
+					return nil, fmt.Errorf("transform script and user-supplied dataset are both trying to set %s", comp)
+				}
+			}
+		}
+
+		ds.Commit.RunID = runID
+	}
+
+	if p.ColumnDescriptionsPath != "" {
+		if err := setColumnDescriptionsFromFile(scope, ds, ref, p.ColumnDescriptionsPath); err != nil {
+			return nil, err
+		}
+	}
+
+	fileHint := p.BodyPath
+	if len(p.FilePaths) > 0 {
+		fileHint = p.FilePaths[0]
+	}
+
+	switches := base.SaveSwitches{
+		FileHint:            fileHint,
+		Replace:             p.Replace,
+		Pin:                 true,
+		ConvertFormatToPrev: p.ConvertFormatToPrev,
+		ForceIfNoChanges:    p.Force,
+		MinChangeRows:       p.MinChangeRows,
+		ColumnarStorage:     p.ColumnarStorage,
+		ChunkSize:           p.ChunkSize,
+		AllowSchemaWiden:    p.AllowSchemaWiden,
+		HashComponents:      p.HashComponents,
+		RequiredMeta:        requiredMeta(scope.Config()),
+		ShouldRender:        p.ShouldRender,
+		NewName:             p.NewName,
+		Drop:                p.Drop,
+		Provenance:          provenance,
+	}
+	savedDs, err := base.SaveDataset(scope.Context(), scope.Repo(), writeDest, author, ref.InitID, ref.Path, ds, runState, switches)
+	if err != nil {
+		// datasets that are unchanged & have a runState record a record of no-changes
+		// to logbook
+		unchanged := errors.Is(err, dsfs.ErrNoChanges) || errors.Is(err, dsfs.ErrBelowChangeThreshold)
+		if unchanged && runState != nil {
+			runState.Status = run.RSUnchanged
+			runState.Message = err.Error()
+			if err := scope.Logbook().WriteTransformRun(scope.Context(), author, ref.InitID, runState); err != nil {
+				log.Debugw("writing unchanged transform run to logbook:", "err", err.Error())
+				return nil, err
+			}
+		}
+
+		log.Debugw("save base.SaveDataset", "err", err)
+		return nil, err
+	}
+
+	success = true
+	*res = *savedDs
+
+	savedRef := dsref.ConvertDatasetToVersionInfo(savedDs).SimpleRef()
+	pruneOnSave(scope, savedRef)
+	autoPushOnSave(scope, savedRef)
+	return res, nil
+}
+
+// pruneOnSave applies the repo.retention config policy matching a dataset,
+// removing old versions after a save. A failure to prune is logged, the
+// saved version is kept either way
+func pruneOnSave(scope scope, ref dsref.Ref) {
+	policy := retentionPolicy(scope.Config(), scope.ActiveProfile().Peername, ref)
+	if policy == nil {
+		return
+	}
+	var maxAge time.Duration
+	if policy.MaxAge != "" {
+		var err error
+		if maxAge, err = time.ParseDuration(policy.MaxAge); err != nil {
+			log.Errorf("retention policy for %s: invalid maxAge: %s", ref.Human(), err)
+			return
+		}
+	}
+	// auto pushes started by earlier saves may still be sending their
+	// versions, keep those versions until the pushes finish
+	pinned := scope.inst.autoPushes.paths()
+	pruned, err := base.PruneVersions(scope.Context(), scope.Repo(), scope.ActiveProfile(), ref, policy.KeepVersions, maxAge, time.Now(), pinned)
+	if err != nil {
+		log.Errorf("pruning versions of %s: %s", ref.Human(), err)
+		return
+	}
+	if pruned > 0 {
+		log.Infof("retention policy pruned %d versions of %s", pruned, ref.Human())
+	}
+}
+
+// retentionPolicy returns the repo.retention config policy whose pattern
+// matches a dataset reference, nil if there isn't one. patterns starting
+// with "me/" match datasets of the active profile
+func retentionPolicy(cfg *config.Config, peername string, ref dsref.Ref) *config.RetentionPolicy {
+	if cfg == nil || cfg.Repo == nil {
+		return nil
+	}
+	patterns := make([]string, 0, len(cfg.Repo.Retention))
+	for pattern := range cfg.Repo.Retention {
+		patterns = append(patterns, pattern)
+	}
+	// check patterns in a stable order when more than one matches
+	sort.Strings(patterns)
+	for _, key := range patterns {
+		pattern := key
+		if strings.HasPrefix(pattern, "me/") {
+			pattern = peername + strings.TrimPrefix(pattern, "me")
+		}
+		if ok, _ := path.Match(pattern, ref.Human()); ok {
+			return cfg.Repo.Retention[key]
+		}
+	}
+	return nil
+}
+
+// requiredMeta returns the meta fields the repo config requires datasets to set
+func requiredMeta(cfg *config.Config) []string {
+	if cfg == nil || cfg.Repo == nil {
+		return nil
+	}
+	return cfg.Repo.RequiredMeta
+}
+
+// autoPushOnSave pushes a newly saved version to each remote listed in the
+// repo.autoPushOnSave config. Pushes run in the background so they don't hold
+// up the save, and report results with ETDatasetAutoPushCompleted events.
+// A failed push is a warning, the saved version is kept either way
+func autoPushOnSave(scope scope, ref dsref.Ref) {
+	cfg := scope.Config()
+	if cfg == nil || cfg.Repo == nil || len(cfg.Repo.AutoPushOnSave) == 0 {
+		return
+	}
+
+	// the save request may end before the push does, run the push on the
+	// application context instead
+	pushScope := scope
+	pushScope.ctx = profile.AddIDToContext(scope.AppContext(), scope.ActiveProfile().ID.Encode())
+	pushScope.source = "local"
+
+	for _, name := range cfg.Repo.AutoPushOnSave {
+		// list the push as an operation so it can be canceled, shutdown also
+		// cancels pushes that outlast its timeout
+		opScope := pushScope
+		var cancel context.CancelFunc
+		opScope.ctx, cancel = context.WithCancel(pushScope.ctx)
+		done := scope.inst.autoPushes.start(ref.Path, cancel)
+		go func(name string) {
+			defer done()
+			opID := run.NewID()
+			scope.inst.ops.start(opID, OpTypePush, ref.Alias(), cancel)
+
+			evt := event.RemoteEvent{Ref: ref}
+			evt.RemoteAddr, evt.Error = remote.Address(cfg, name)
+			if evt.Error == nil {
+				_, evt.Error = datasetImpl{}.Push(opScope, &PushParams{Ref: ref.Alias(), Remote: name})
+			}
+			scope.inst.ops.done(opID, evt.Error)
+			if evt.Error != nil {
+				log.Warnw("auto push after save failed", "ref", ref.Alias(), "remote", name, "err", evt.Error)
+			}
+			if err := scope.Bus().Publish(pushScope.Context(), event.ETDatasetAutoPushCompleted, evt); err != nil {
+				log.Debugw("publishing auto push event", "err", err)
+			}
+		}(name)
+	}
+}
+
+// autoPushGroup tracks background pushes started by saves
+type autoPushGroup struct {
+	wg     sync.WaitGroup
+	lk     sync.Mutex
+	nextID int
+	pushes map[int]autoPush
+}
+
+// autoPush is a running push of a version
+type autoPush struct {
+	path   string
+	cancel context.CancelFunc
+}
+
+// start tracks a push of the version at path that stops when cancel is
+// called. the returned func must be called when the push finishes
+func (g *autoPushGroup) start(path string, cancel context.CancelFunc) (done func()) {
+	g.lk.Lock()
+	defer g.lk.Unlock()
+	if g.pushes == nil {
+		g.pushes = map[int]autoPush{}
+	}
+	id := g.nextID
+	g.nextID++
+	g.pushes[id] = autoPush{path: path, cancel: cancel}
+	g.wg.Add(1)
+
+	return func() {
+		g.lk.Lock()
+		delete(g.pushes, id)
+		g.lk.Unlock()
+		cancel()
+		g.wg.Done()
+	}
+}
+
+// paths returns the version paths of running pushes
+func (g *autoPushGroup) paths() map[string]bool {
+	g.lk.Lock()
+	defer g.lk.Unlock()
+	paths := make(map[string]bool, len(g.pushes))
+	for _, p := range g.pushes {
+		paths[p.path] = true
+	}
+	return paths
+}
+
+// wait blocks until all tracked pushes finish or timeout elapses, canceling
+// pushes that are still running at the timeout. wait returns false if any
+// pushes were canceled
+func (g *autoPushGroup) wait(timeout time.Duration) bool {
+	finished := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return true
+	case <-time.After(timeout):
+	}
+
+	g.lk.Lock()
+	defer g.lk.Unlock()
+	for _, p := range g.pushes {
+		p.cancel()
+	}
+	return false
+}
+
+// setSQLBody runs a SQL query against the body of a source dataset, making the
+// result the body of ds. It returns the query & the source version it ran
+// against, which the save records as provenance so the body can be
+// regenerated
+func setSQLBody(scope scope, ds *dataset.Dataset, query, source string) (*dsfs.BodySource, error) {
+	if query == "" {
+		return nil, fmt.Errorf("a SQL source requires a SQL query")
+	}
+	if source == "" {
+		return nil, fmt.Errorf("a SQL query requires a source dataset")
+	}
+	if ds.BodyPath != "" || ds.BodyFile() != nil {
+		return nil, fmt.Errorf("cannot save with both a SQL query and a body")
+	}
+
+	ref, src, err := openAndLoadDataset(scope, &GetParams{Ref: source, Selector: "body"})
+	if err != nil {
+		return nil, fmt.Errorf("loading SQL source: %w", err)
+	}
+	body, st, err := base.SQLBody(src, query)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.SetBodyFile(body)
+	if ds.Structure == nil {
+		ds.Structure = &dataset.Structure{}
+	}
+	ds.Structure.Format = st.Format
+	ds.Structure.Schema = st.Schema
+
+	if ds.Commit == nil {
+		ds.Commit = &dataset.Commit{}
+	}
+	msg := fmt.Sprintf("body generated by SQL query against version %s of %s", ref.Path, ref.Human())
+	if ds.Commit.Message != "" {
+		msg = ds.Commit.Message + "\n\n" + msg
+	}
+	ds.Commit.Message = msg
+	return &dsfs.BodySource{
+		Syntax:     "sql",
+		Query:      query,
+		Source:     ref.Human(),
+		SourcePath: ref.Path,
+	}, nil
+}
+
+// setDBBody sets the body of ds to the result of a database query. It returns
+// the query & the connection string without credentials, which the save
+// records as provenance so the body can be regenerated
+func setDBBody(scope scope, ds *dataset.Dataset, connString, query string) (*dsfs.BodySource, error) {
+	if connString == "" {
+		return nil, fmt.Errorf("a database query requires a database connection string")
+	}
+	if query == "" {
+		return nil, fmt.Errorf("a database connection string requires a query")
+	}
+	if ds.BodyPath != "" || ds.BodyFile() != nil {
+		return nil, fmt.Errorf("cannot save with both a database query and a body")
+	}
+
+	body, st, err := base.DBBody(scope.Context(), connString, query)
+	if err != nil {
+		return nil, err
+	}
+
+	ds.SetBodyFile(body)
+	if ds.Structure == nil {
+		ds.Structure = &dataset.Structure{}
+	}
+	ds.Structure.Format = st.Format
+	ds.Structure.Schema = st.Schema
+
+	return &dsfs.BodySource{
+		Syntax: "sql",
+		Query:  query,
+		Source: base.RedactConnString(connString),
+	}, nil
+}
+
+// setSynthesizedBody sets the body of ds to rows of random values generated
+// from its structure schema, recording the seed in the commit message
+func setSynthesizedBody(ds *dataset.Dataset, rows int, seed int64) error {
+	if rows < 0 {
+		return fmt.Errorf("can't synthesize a negative number of rows")
+	}
+	if ds.BodyPath != "" || ds.BodyFile() != nil {
+		return fmt.Errorf("cannot save with both a synthesized body and a body")
+	}
+	if ds.Structure == nil || ds.Structure.Schema == nil {
+		return fmt.Errorf("synthesizing a body requires a structure with a schema")
+	}
+
+	body, err := base.SynthesizeBody(ds.Structure, rows, seed)
+	if err != nil {
+		return err
+	}
+	ds.SetBodyFile(body)
+	if ds.Structure.Format == "" {
+		ds.Structure.Format = dataset.JSONDataFormat.String()
+	}
+
+	if ds.Commit == nil {
+		ds.Commit = &dataset.Commit{}
+	}
+	msg := fmt.Sprintf("body of %d rows synthesized from the schema with seed %d", rows, seed)
+	if ds.Commit.Message != "" {
+		msg = ds.Commit.Message + "\n\n" + msg
+	}
+	ds.Commit.Message = msg
+	return nil
+}
+
+// bodyReaderFilename names a body streamed from SaveParams.BodyReader, using
+// the body path or the structure format to give the name an extension
+func bodyReaderFilename(ds *dataset.Dataset) (string, error) {
+	if ds.BodyPath != "" {
+		return filepath.Base(ds.BodyPath), nil
+	}
+	if ds.Structure != nil && ds.Structure.Format != "" {
+		return "body." + strings.ToLower(ds.Structure.Format), nil
+	}
+	return "", fmt.Errorf("saving a body reader requires a body path or structure format to determine the body format")
+}
+
+// structBody converts a slice of structs to body rows. When ds has no schema
+// of its own, the schema is derived from the struct type
+func structBody(ds *dataset.Dataset, body interface{}, df dataset.DataFormat) ([]interface{}, error) {
+	rows, err := base.StructRows(body)
+	if err != nil {
+		return nil, err
+	}
+	if ds.Structure == nil {
+		ds.Structure = &dataset.Structure{}
+	}
+	if ds.Structure.Schema == nil {
+		if ds.Structure.Schema, err = base.StructSchema(body); err != nil {
+			return nil, err
+		}
+	}
+	if ds.Structure.Format == "" {
+		if df == dataset.UnknownDataFormat {
+			df = dataset.JSONDataFormat
+		}
+		ds.Structure.Format = df.String()
+	}
+	return rows, nil
+}
+
+// setColumnDescriptionsFromFile reads a column description sidecar file and
+// writes descriptions into the schema that ds will be saved with. When ds has
+// no schema of its own, the schema from the previous version is used,
+// falling back to a schema detected from the body of a new dataset
+func setColumnDescriptionsFromFile(scope scope, ds *dataset.Dataset, ref dsref.Ref, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening column descriptions: %w", err)
+	}
+	defer f.Close()
+
+	descriptions, err := base.ReadColumnDescriptions(f)
+	if err != nil {
+		return err
+	}
+
+	if ds.Structure == nil || ds.Structure.Schema == nil {
+		if ref.Path != "" {
+			prev, err := dsfs.LoadDataset(scope.Context(), scope.Filesystem(), ref.Path)
+			if err != nil {
+				return err
+			}
+			if prev.Structure != nil && prev.Structure.Schema != nil {
+				if ds.Structure == nil {
+					ds.Structure = &dataset.Structure{}
+				}
+				ds.Structure.Schema = prev.Structure.Schema
+			}
+		}
+		if ds.Structure == nil || ds.Structure.Schema == nil {
+			if err := detect.Structure(ds); err != nil {
+				return fmt.Errorf("column descriptions require a schema: %w", err)
+			}
+		}
+	}
+
+	unknown, err := base.SetColumnDescriptions(ds.Structure, descriptions)
+	if err != nil {
+		return err
+	}
+	for _, name := range unknown {
+		log.Warnf("column descriptions: no column named %q in dataset schema", name)
+	}
+	return nil
+}
+
+// Rename changes a user's given name for a dataset
+func (datasetImpl) Rename(scope scope, p *RenameParams) (*dsref.VersionInfo, error) {
+	if p.Current == "" {
+		return nil, fmt.Errorf("current name is required to rename a dataset")
+	}
+
+	if scope.SourceName() != "local" {
+		return nil, fmt.Errorf("can only rename using local source")
+	}
+
+	ref, err := dsref.ParseHumanFriendly(p.Current)
+	// Allow bad upper-case characters in the left-hand side name, because it's needed to let users
+	// fix badly named datasets.
+	if err != nil && err != dsref.ErrBadCaseName {
+		return nil, fmt.Errorf("original name: %w", err)
+	}
+	if _, err := scope.ResolveReference(scope.Context(), &ref); err != nil {
+		return nil, err
+	}
+
+	next, err := dsref.ParseHumanFriendly(p.Next)
+	if errors.Is(err, dsref.ErrNotHumanFriendly) {
+		return nil, fmt.Errorf("destination name: %w", err)
+	} else if err != nil {
+		return nil, fmt.Errorf("destination name: %w", dsref.ErrDescribeValidName)
+	}
+	if ref.Username != next.Username && next.Username != "me" {
+		return nil, fmt.Errorf("cannot change username or profileID of a dataset. use adopt to copy a dataset under your own profile")
+	}
+
+	// Update the reference stored in the repo
+	vi, err := base.RenameDatasetRef(scope.Context(), scope.Repo(), scope.ActiveProfile(), ref, next.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return vi, nil
+}
+
+// Adopt saves a copy of a dataset's current version as a new dataset
+func (datasetImpl) Adopt(scope scope, p *AdoptParams) (*dataset.Dataset, error) {
+	if p.Current == "" {
+		return nil, fmt.Errorf("current name is required to adopt a dataset")
+	}
+	if scope.SourceName() != "local" {
+		return nil, fmt.Errorf("can only adopt using local source")
+	}
+
+	next, err := dsref.ParseHumanFriendly(p.Next)
+	if errors.Is(err, dsref.ErrNotHumanFriendly) {
+		return nil, fmt.Errorf("destination name: %w", err)
+	} else if err != nil {
+		return nil, fmt.Errorf("destination name: %w", dsref.ErrDescribeValidName)
+	}
+
+	ref, ds, err := openAndLoadDataset(scope, &GetParams{Ref: p.Current})
+	if err != nil {
+		return nil, err
+	}
+
+	// strip everything that ties the copy to the original's history
+	body := ds.BodyFile()
+	ds.DropTransientValues()
+	ds.DropDerivedValues()
+	ds.ID = ""
+	ds.Commit = nil
+	ds.PreviousPath = ""
+	ds.BodyPath = ""
+	ds.Stats = nil
+	ds.SetBodyFile(body)
+
+	return datasetImpl{}.Save(scope, &SaveParams{
+		Ref:     next.Human(),
+		Dataset: ds,
+		Title:   fmt.Sprintf("adopted from %s", ref.Human()),
+		Message: fmt.Sprintf("copied from version %s of %s. this dataset does not share history with the original", ref.Path, ref.Human()),
+		NewName: true,
+	})
+}
+
+// Merge concatenates the bodies of two datasets into a new dataset
+func (datasetImpl) Merge(scope scope, p *MergeParams) (*MergeResult, error) {
+	if scope.SourceName() != "local" {
+		return nil, fmt.Errorf("can only merge using local source")
+	}
+
+	next, err := dsref.ParseHumanFriendly(p.Next)
+	if errors.Is(err, dsref.ErrNotHumanFriendly) {
+		return nil, fmt.Errorf("destination name: %w", err)
+	} else if err != nil {
+		return nil, fmt.Errorf("destination name: %w", dsref.ErrDescribeValidName)
+	}
+
+	firstRef, first, err := openAndLoadDataset(scope, &GetParams{Ref: p.First})
+	if err != nil {
+		return nil, err
+	}
+	secondRef, second, err := openAndLoadDataset(scope, &GetParams{Ref: p.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	if mismatches := base.SchemaMismatches(first.Structure, second.Structure, firstRef.Human(), secondRef.Human()); len(mismatches) > 0 {
+		return nil, fmt.Errorf("can't merge %s and %s, schemas don't match:\n  %s", firstRef.Human(), secondRef.Human(), strings.Join(mismatches, "\n  "))
+	}
+
+	body, counts, err := base.MergeBodies(first, second, p.DedupKey)
+	if err != nil {
+		return nil, err
+	}
+
+	st := &dataset.Structure{}
+	st.Assign(first.Structure)
+	st.DropDerivedValues()
+	ds := &dataset.Dataset{Structure: st}
+	ds.SetBodyFile(body)
+
+	// the merged body is streamed into the save, so the number of dropped
+	// rows isn't known until the save has read it
+	msg := fmt.Sprintf("rows of version %s of %s followed by version %s of %s", firstRef.Path, firstRef.Human(), secondRef.Path, secondRef.Human())
+	if p.DedupKey != "" {
+		msg += fmt.Sprintf(". rows with a repeated %s were dropped", p.DedupKey)
+	}
+	saved, err := datasetImpl{}.Save(scope, &SaveParams{
+		Ref:     next.Human(),
+		Dataset: ds,
+		Title:   fmt.Sprintf("merged %s and %s", firstRef.Human(), secondRef.Human()),
+		Message: msg,
+		NewName: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MergeResult{Dataset: saved, DroppedRows: counts.Dropped}, nil
+}
+
+// Duplicates streams a dataset body looking for repeated rows
+func (datasetImpl) Duplicates(scope scope, p *DuplicatesParams) (*DuplicatesResult, error) {
+	if scope.SourceName() != "local" {
+		return nil, fmt.Errorf("can only check for duplicates using local source")
+	}
+
+	_, ds, err := openAndLoadDataset(scope, &GetParams{Ref: p.Ref})
+	if err != nil {
+		return nil, err
+	}
+	report, err := base.FindDuplicateRows(ds, p.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &DuplicatesResult{Report: report}, nil
+}
+
+// RemoveDuplicates checks a dataset body for repeated rows, then streams the
+// body again to save a version without them
+func (datasetImpl) RemoveDuplicates(scope scope, p *DuplicatesParams) (*DuplicatesResult, error) {
+	if scope.SourceName() != "local" {
+		return nil, fmt.Errorf("can only remove duplicates using local source")
+	}
+
+	res, err := datasetImpl{}.Duplicates(scope, p)
+	if err != nil {
+		return nil, err
+	}
+	report := res.Report
+	if report.Duplicates == 0 {
+		return res, nil
+	}
+
+	ref, ds, err := openAndLoadDataset(scope, &GetParams{Ref: p.Ref})
+	if err != nil {
+		return nil, err
+	}
+	body, err := base.DropDuplicateRows(ds, p.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &dataset.Dataset{
+		Structure: &dataset.Structure{
+			Format: ds.Structure.Format,
+			Schema: ds.Structure.Schema,
+		},
+	}
+	changes.SetBodyFile(body)
+	title := fmt.Sprintf("remove %d duplicate rows", report.Duplicates)
+	if p.Key != "" {
+		title = fmt.Sprintf("remove %d rows with a duplicate %s", report.Duplicates, p.Key)
+	}
+	if res.Dataset, err = (datasetImpl{}).Save(scope, &SaveParams{
+		Ref:     ref.Human(),
+		Dataset: changes,
+		Title:   title,
+	}); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// RenameColumn changes the name of a dataset column, saving a new version
+func (datasetImpl) RenameColumn(scope scope, p *RenameColumnParams) (*dataset.Dataset, error) {
+	if scope.SourceName() != "local" {
+		return nil, fmt.Errorf("can only rename columns using local source")
+	}
+	ctx := scope.Context()
+
+	ref, _, err := scope.ParseAndResolveRef(ctx, p.Ref)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := dsfs.LoadDataset(ctx, scope.Filesystem(), ref.Path)
+	if err != nil {
+		return nil, err
+	}
+	if ds.Structure == nil {
+		return nil, fmt.Errorf("dataset has no structure")
+	}
+
+	objectRows, err := base.RenameColumn(ds.Structure, p.From, p.To)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &dataset.Dataset{
+		Structure: &dataset.Structure{
+			Format: ds.Structure.Format,
+			Schema: ds.Structure.Schema,
+		},
+	}
+	if objectRows {
+		body, err := dsfs.LoadBody(ctx, scope.Filesystem(), ds)
+		if err != nil {
+			return nil, err
+		}
+		renamed, err := base.RenameBodyKeys(ds.Structure, body, p.From, p.To)
+		if err != nil {
+			return nil, err
+		}
+		changes.SetBodyFile(renamed)
+	}
+
+	return datasetImpl{}.Save(scope, &SaveParams{
+		Ref:     ref.Human(),
+		Dataset: changes,
+		Title:   fmt.Sprintf("rename column %s to %s", p.From, p.To),
+	})
+}
+
+// DescribeColumn sets column metadata in the structure schema, saving a new
+// version
+func (datasetImpl) DescribeColumn(scope scope, p *DescribeColumnParams) (*dataset.Dataset, error) {
+	if scope.SourceName() != "local" {
+		return nil, fmt.Errorf("can only describe columns using local source")
+	}
+	ctx := scope.Context()
+
+	ref, _, err := scope.ParseAndResolveRef(ctx, p.Ref)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := dsfs.LoadDataset(ctx, scope.Filesystem(), ref.Path)
+	if err != nil {
+		return nil, err
+	}
+	if ds.Structure == nil {
+		return nil, fmt.Errorf("dataset has no structure")
+	}
+
+	meta := base.ColumnMeta{Description: p.Description, Unit: p.Unit, Example: p.Example}
+	if err := base.SetColumnMeta(ds.Structure, p.Column, meta); err != nil {
+		return nil, err
+	}
+
+	return datasetImpl{}.Save(scope, &SaveParams{
+		Ref: ref.Human(),
+		Dataset: &dataset.Dataset{
+			Structure: &dataset.Structure{
+				Format: ds.Structure.Format,
+				Schema: ds.Structure.Schema,
+			},
+		},
+		Title: fmt.Sprintf("describe column %s", p.Column),
+	})
+}
+
+// SetMeta changes a single field of the meta component, saving a new version
+func (datasetImpl) SetMeta(scope scope, p *SetMetaParams) (*dataset.Dataset, error) {
+	if scope.SourceName() != "local" {
+		return nil, fmt.Errorf("can only set meta fields using local source")
+	}
+	ctx := scope.Context()
+
+	ref, _, err := scope.ParseAndResolveRef(ctx, p.Ref)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := dsfs.LoadDataset(ctx, scope.Filesystem(), ref.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := base.SetMetaField(ds.Meta, p.Field, p.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return datasetImpl{}.Save(scope, &SaveParams{
+		Ref:     ref.Human(),
+		Dataset: &dataset.Dataset{Meta: md},
+		Title:   fmt.Sprintf("set meta %s", p.Field),
+	})
+}
+
+// Attach stores a file in the dataset's filesystem & records it as an
+// attachment in the meta component
+func (datasetImpl) Attach(scope scope, p *AttachParams) (*dataset.Dataset, error) {
+	if scope.SourceName() != "local" {
+		return nil, fmt.Errorf("can only attach files using local source")
+	}
+	ctx := scope.Context()
+
+	if p.FilePath == "" {
+		return nil, fmt.Errorf("a file to attach is required")
+	}
+	name := p.Name
+	if name == "" {
+		name = filepath.Base(p.FilePath)
+	}
+	if err := dsfs.ValidateAttachmentName(name); err != nil {
+		return nil, err
+	}
+
+	ref, _, err := scope.ParseAndResolveRef(ctx, p.Ref)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := dsfs.LoadDataset(ctx, scope.Filesystem(), ref.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(p.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading attachment: %w", err)
+	}
+	path, err := scope.Filesystem().DefaultWriteFS().Put(ctx, qfs.NewMemfileBytes(name, data))
+	if err != nil {
+		return nil, fmt.Errorf("writing attachment: %w", err)
+	}
+
+	md := &dataset.Meta{}
+	md.Assign(ds.Meta)
+	md.Path = ""
+	if err := dsfs.SetAttachment(md, name, path); err != nil {
+		return nil, err
+	}
+
+	return datasetImpl{}.Save(scope, &SaveParams{
+		Ref:     ref.Human(),
+		Dataset: &dataset.Dataset{Meta: md},
+		Title:   fmt.Sprintf("attach %s", name),
+	})
+}
+
+// Link writes a linkfile to an existing directory, reporting how component
+// files already in the directory differ from the linked version
+func (datasetImpl) Link(scope scope, p *LinkParams) (*LinkResult, error) {
+	if scope.SourceName() != "local" {
+		return nil, fmt.Errorf("can only link directories using local source")
+	}
+	ctx := scope.Context()
+
+	if p.Dir == "" {
+		return nil, fmt.Errorf("a directory to link is required")
+	}
+	dir, err := filepath.Abs(p.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if fi, err := os.Stat(dir); err != nil {
+		return nil, err
+	} else if !fi.IsDir() {
+		return nil, fmt.Errorf("%q is not a directory", p.Dir)
+	}
+	if linkfile.ExistsInDir(dir) {
+		return nil, fmt.Errorf("directory %q is already linked to a dataset", p.Dir)
+	}
+
+	ref, _, err := scope.ParseAndResolveRef(ctx, p.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := scope.ComponentStatus().DirectoryStatus(ctx, dir, ref.Path)
+	if errors.Is(err, component.ErrNoDatasetFiles) {
+		status, err = []base.StatusItem{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := linkfile.WriteHiddenInDir(dir, ref); err != nil {
+		return nil, err
+	}
+	return &LinkResult{Ref: ref.String(), Dir: dir, Status: status}, nil
+}
+
+// ExportGit writes dataset history as a git fast-import stream
+func (datasetImpl) ExportGit(scope scope, p *ExportGitParams) ([]byte, error) {
+	ctx := scope.Context()
+	ref, _, err := scope.ParseAndResolveRef(ctx, p.Ref)
+	if err != nil {
+		return nil, err
+	}
+	items, err := base.DatasetLog(ctx, scope.Repo(), ref, -1, 0, "history", false)
+	if err != nil {
 		return nil, err
 	}
 
-	// If applying a transform, execute its script before saving
-	if p.Apply {
-		if ds.Transform == nil {
-			// if no transform component exists, load the latest transform component
-			// from history
-			if isNew {
-				return nil, fmt.Errorf("cannot apply while saving without a transform")
-			}
-
-			prevTransformDataset, err := base.LoadRevs(scope.Context(), scope.Filesystem(), ref, []*dsref.Rev{{Field: "tf", Gen: 1}})
-			if err != nil {
-				return nil, fmt.Errorf("loading transform component from history: %w", err)
-			}
-			ds.Transform = prevTransformDataset.Transform
+	for _, item := range items {
+		if item.Foreign {
+			return nil, fmt.Errorf("version %s isn't stored locally, pull the dataset to export its full history", item.Path)
 		}
+	}
 
-		scriptOut := p.ScriptOutput
-		secrets := p.Secrets
-		runID := ds.Commit.RunID
-		if runID == "" {
-			// if there is no given runID, allocate an ID for the transform,
-			// subscribe to print output & build up the run.State
-			runID = run.NewID()
+	// history is listed newest first, commits are written oldest first.
+	// versions are loaded as they're written
+	fs := scope.Filesystem()
+	i := len(items)
+	next := func() (*dataset.Dataset, error) {
+		if i == 0 {
+			return nil, io.EOF
 		}
-		runState = &run.State{ID: runID}
-
-		scope.Bus().SubscribeID(func(ctx context.Context, e event.Event) error {
-			runState.AddTransformEvent(e)
-			if e.Type == event.ETTransformPrint {
-				if msg, ok := e.Payload.(event.TransformMessage); ok {
-					if p.ScriptOutput != nil {
-						io.WriteString(scriptOut, msg.Msg)
-						io.WriteString(scriptOut, "\n")
-					}
-				}
-			}
-			return nil
-		}, runID)
-
-		// TODO(dustmop): Get actual size info from the proper place
-		sizeInfo := transform.SizeInfo{}
+		i--
+		ds, err := dsfs.LoadDataset(ctx, fs, items[i].Path)
+		if err != nil {
+			return nil, fmt.Errorf("loading version %s: %w", items[i].Path, err)
+		}
+		if err := base.OpenDataset(ctx, fs, ds); err != nil {
+			return nil, fmt.Errorf("opening version %s: %w", items[i].Path, err)
+		}
+		ds.Peername = ref.Username
+		ds.Name = ref.Name
+		return ds, nil
+	}
 
-		// apply the transform
-		shouldWait := true
-		transformer := transform.NewTransformer(scope.AppContext(), scope.Filesystem(), scope.Loader(), scope.Bus(), sizeInfo)
-		if err := transformer.Commit(scope.Context(), ref.InitID, ds, runID, shouldWait, secrets); err != nil {
-			log.Errorw("transform run error", "err", err.Error())
-			runState.Message = err.Error()
-			if err := scope.Logbook().WriteTransformRun(scope.Context(), scope.ActiveProfile(), ref.InitID, runState); err != nil {
-				log.Debugw("writing errored transform run to logbook:", "err", err.Error())
-				return nil, err
-			}
+	if p.Writer != nil {
+		return nil, archive.WriteGitFastImport(p.Writer, p.Branch, next)
+	}
+	buf := &bytes.Buffer{}
+	if err := archive.WriteGitFastImport(buf, p.Branch, next); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
 
-			return nil, err
+// GetAttachment reads the contents of a dataset attachment
+func (datasetImpl) GetAttachment(scope scope, p *GetAttachmentParams) ([]byte, error) {
+	if err := dsfs.ValidateAttachmentName(p.Name); err != nil {
+		return nil, err
+	}
+	ref, ds, err := openAndLoadDataset(scope, &GetParams{Ref: p.Ref})
+	if err != nil {
+		return nil, err
+	}
+	f, err := dsfs.LoadAttachment(scope.Context(), scope.Filesystem(), ds, p.Name)
+	if errors.Is(err, dsfs.ErrNoAttachment) {
+		if names := dsfs.AttachmentNames(ds.Meta); len(names) > 0 {
+			return nil, fmt.Errorf("%s has no attachment named %q, attachments: %s", ref.Human(), p.Name, strings.Join(names, ", "))
 		}
+		return nil, fmt.Errorf("%s has no attachment named %q", ref.Human(), p.Name)
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
 
-		// compare manual changes to the changes made by the transform, make
-		// sure they don't conflict
-		if manualChanges != nil {
-			changes := transformer.Changes()
-			for comp := range changes {
-				if _, found := manualChanges[comp]; found {
-					return nil, fmt.Errorf("transform script and user-supplied dataset are both trying to set %s", comp)
-				}
-			}
-		}
+// Fingerprint hashes the content of a dataset version. versions are immutable,
+// so fingerprints are cached by version path
+func (datasetImpl) Fingerprint(scope scope, p *FingerprintParams) (string, error) {
+	if scope.SourceName() != "local" {
+		return "", fmt.Errorf("can only fingerprint datasets in local storage")
+	}
+	ctx := scope.Context()
 
-		ds.Commit.RunID = runID
+	ref, _, err := scope.ParseAndResolveRef(ctx, p.Ref)
+	if err != nil {
+		return "", err
+	}
+	if ref.Path == "" {
+		return "", qrierr.New(dsref.ErrNoHistory, fmt.Sprintf("can't fingerprint %q, it has no saved versions", ref.Human()))
 	}
 
-	fileHint := p.BodyPath
-	if len(p.FilePaths) > 0 {
-		fileHint = p.FilePaths[0]
+	key := ref.Path
+	if p.BodyOnly {
+		key += "/body"
+	}
+	if fp, ok := scope.inst.fingerprints.get(key); ok {
+		return fp, nil
 	}
 
-	switches := base.SaveSwitches{
-		FileHint:            fileHint,
-		Replace:             p.Replace,
-		Pin:                 true,
-		ConvertFormatToPrev: p.ConvertFormatToPrev,
-		ForceIfNoChanges:    p.Force,
-		ShouldRender:        p.ShouldRender,
-		NewName:             p.NewName,
-		Drop:                p.Drop,
+	ds, err := dsfs.LoadDataset(ctx, scope.Filesystem(), ref.Path)
+	if err != nil {
+		return "", err
 	}
-	savedDs, err := base.SaveDataset(scope.Context(), scope.Repo(), writeDest, author, ref.InitID, ref.Path, ds, runState, switches)
+	if err := base.OpenDataset(ctx, scope.Filesystem(), ds); err != nil {
+		return "", err
+	}
+	fp, err := base.Fingerprint(ds, p.BodyOnly)
 	if err != nil {
-		// datasets that are unchanged & have a runState record a record of no-changes
-		// to logbook
-		if errors.Is(err, dsfs.ErrNoChanges) && runState != nil {
-			runState.Status = run.RSUnchanged
-			runState.Message = err.Error()
-			if err := scope.Logbook().WriteTransformRun(scope.Context(), author, ref.InitID, runState); err != nil {
-				log.Debugw("writing unchanged transform run to logbook:", "err", err.Error())
-				return nil, err
-			}
-		}
-
-		log.Debugw("save base.SaveDataset", "err", err)
-		return nil, err
+		return "", err
 	}
+	scope.inst.fingerprints.set(key, fp)
+	return fp, nil
+}
 
-	success = true
-	*res = *savedDs
+// maxCachedFingerprints bounds the number of fingerprints an instance keeps.
+// A variable is used instead of a constant so that tests can override it
+var maxCachedFingerprints = 1024
 
-	return res, nil
+// fingerprintCache holds dataset fingerprints, keyed by version path. once
+// the cache is full the oldest fingerprint is evicted for each new one
+type fingerprintCache struct {
+	lk    sync.Mutex
+	fps   map[string]string
+	order []string
 }
 
-// Rename changes a user's given name for a dataset
-func (datasetImpl) Rename(scope scope, p *RenameParams) (*dsref.VersionInfo, error) {
-	if p.Current == "" {
-		return nil, fmt.Errorf("current name is required to rename a dataset")
+func (c *fingerprintCache) get(key string) (string, bool) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	fp, ok := c.fps[key]
+	return fp, ok
+}
+
+func (c *fingerprintCache) set(key, fp string) {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	if c.fps == nil {
+		c.fps = map[string]string{}
+	}
+	if _, ok := c.fps[key]; !ok {
+		c.order = append(c.order, key)
 	}
+	c.fps[key] = fp
+	for len(c.order) > maxCachedFingerprints {
+		delete(c.fps, c.order[0])
+		c.order = c.order[1:]
+	}
+}
 
+// ApplyPatch applies a patch to a dataset, saving a new version
+func (datasetImpl) ApplyPatch(scope scope, p *ApplyPatchParams) (*ApplyPatchResult, error) {
 	if scope.SourceName() != "local" {
-		return nil, fmt.Errorf("can only rename using local source")
+		return nil, fmt.Errorf("can only apply patches using local source")
 	}
 
-	ref, err := dsref.ParseHumanFriendly(p.Current)
-	// Allow bad upper-case characters in the left-hand side name, because it's needed to let users
-	// fix badly named datasets.
-	if err != nil && err != dsref.ErrBadCaseName {
-		return nil, fmt.Errorf("original name: %w", err)
-	}
-	if _, err := scope.ResolveReference(scope.Context(), &ref); err != nil {
-		return nil, err
+	patch := p.Patch
+	if patch == nil {
+		if p.PatchPath == "" {
+			return nil, fmt.Errorf("a patch is required")
+		}
+		var err error
+		if patch, err = readPatchFile(p.PatchPath); err != nil {
+			return nil, err
+		}
 	}
 
-	next, err := dsref.ParseHumanFriendly(p.Next)
-	if errors.Is(err, dsref.ErrNotHumanFriendly) {
-		return nil, fmt.Errorf("destination name: %w", err)
-	} else if err != nil {
-		return nil, fmt.Errorf("destination name: %w", dsref.ErrDescribeValidName)
+	ref, ds, err := openAndLoadDataset(scope, &GetParams{Ref: p.Ref})
+	if err != nil {
+		return nil, err
 	}
-	if ref.Username != next.Username && next.Username != "me" {
-		return nil, fmt.Errorf("cannot change username or profileID of a dataset")
+	changes, conflicts, err := applyPatch(ds, patch)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update the reference stored in the repo
-	vi, err := base.RenameDatasetRef(scope.Context(), scope.Repo(), scope.ActiveProfile(), ref, next.Name)
+	title := p.Title
+	if title == "" {
+		title = "apply patch"
+	}
+	saved, err := datasetImpl{}.Save(scope, &SaveParams{
+		Ref:     ref.Human(),
+		Dataset: changes,
+		Title:   title,
+		Message: patchMessage(patch, conflicts),
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	return vi, nil
+	if conflicts == nil {
+		conflicts = []string{}
+	}
+	return &ApplyPatchResult{
+		Dataset:   saved,
+		BaseMatch: ref.Path == patch.Base,
+		Conflicts: conflicts,
+	}, nil
 }
 
 // Remove a dataset entirely or remove a certain number of revisions
@@ -1095,6 +3533,25 @@ func (datasetImpl) Remove(scope scope, p *RemoveParams) (*RemoveResponse, error)
 	return res, nil
 }
 
+// Squash compacts dataset history
+func (datasetImpl) Squash(scope scope, p *SquashParams) (*SquashResponse, error) {
+	if scope.SourceName() != "local" {
+		return nil, fmt.Errorf("squash requires the 'local' source")
+	}
+
+	ref, _, err := scope.ParseAndResolveRef(scope.Context(), p.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := base.SquashVersions(scope.Context(), scope.Repo(), scope.ActiveProfile(), ref, p.Keep)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SquashResponse{Ref: ref.String(), NumSquashed: n}, nil
+}
+
 // Pull downloads and stores an existing dataset to a peer's repository via
 // a network connection
 func (datasetImpl) Pull(scope scope, p *PullParams) (*dataset.Dataset, error) {
@@ -1103,6 +3560,9 @@ func (datasetImpl) Pull(scope scope, p *PullParams) (*dataset.Dataset, error) {
 	if scope.SourceName() != "network" {
 		return nil, fmt.Errorf("pull requires the 'network' source")
 	}
+	if p.MetadataOnly && p.Resume {
+		return nil, fmt.Errorf("cannot resume a metadata-only pull")
+	}
 
 	ref, location, err := scope.ParseAndResolveRef(scope.Context(), p.Ref)
 	if err != nil {
@@ -1111,7 +3571,14 @@ func (datasetImpl) Pull(scope scope, p *PullParams) (*dataset.Dataset, error) {
 	}
 	log.Infof("pulling dataset from location: %s", location)
 
-	ds, err := scope.RemoteClient().PullDataset(scope.Context(), &ref, location)
+	var ds *dataset.Dataset
+	if p.MetadataOnly {
+		ds, err = scope.RemoteClient().PullDatasetMetadata(scope.Context(), &ref, location)
+	} else if p.Resume {
+		ds, err = scope.RemoteClient().ResumePullDataset(scope.Context(), &ref, location)
+	} else {
+		ds, err = scope.RemoteClient().PullDataset(scope.Context(), &ref, location)
+	}
 	if err != nil {
 		log.Debugf("pulling dataset: %s", err)
 		return nil, err
@@ -1143,6 +3610,12 @@ func (datasetImpl) Push(scope scope, p *PushParams) (*dsref.Ref, error) {
 		return nil, err
 	}
 
+	if p.Verify {
+		if err = scope.RemoteClient().VerifyDatasetVersion(scope.Context(), ref, addr); err != nil {
+			return nil, fmt.Errorf("verifying push: %w", err)
+		}
+	}
+
 	if err = base.SetPublishStatus(scope.Context(), scope.Repo(), author, ref, true); err != nil {
 		return nil, err
 	}
@@ -1188,12 +3661,30 @@ func (datasetImpl) Validate(scope scope, p *ValidateParams) (*ValidateResponse,
 		}
 	}
 
-	var ds *dataset.Dataset
+	var (
+		ds *dataset.Dataset
+		// results are only cached when validating a stored dataset with its
+		// own body & structure
+		cache                   *validationCache
+		bodyPath, structurePath string
+	)
 
 	if p.Ref != "" {
 		if ds, err = dsfs.LoadDataset(scope.Context(), scope.Filesystem(), ref.Path); err != nil {
 			return nil, fmt.Errorf("loading dataset: %w", err)
 		}
+		if p.BodyFilename == "" && schemaFlagType == "" {
+			cache = newValidationCache(scope.RepoPath())
+			if !p.NoCache {
+				if cached, ok := cache.get(ds); ok {
+					return cached, nil
+				}
+			}
+			bodyPath = ds.BodyPath
+			if ds.Structure != nil {
+				structurePath = ds.Structure.Path
+			}
+		}
 		if err = base.OpenDataset(scope.Context(), scope.Filesystem(), ds); err != nil {
 			return nil, err
 		}
@@ -1268,6 +3759,11 @@ func (datasetImpl) Validate(scope scope, p *ValidateParams) (*ValidateResponse,
 		Structure: st,
 		Errors:    valerrs,
 	}
+	if cache != nil {
+		if err := cache.put(bodyPath, structurePath, res); err != nil {
+			log.Debugw("caching validation result", "err", err)
+		}
+	}
 	return res, nil
 }
 
@@ -1320,6 +3816,143 @@ func (datasetImpl) DAGInfo(scope scope, p *DAGInfoParams) (*dag.Info, error) {
 	return res, nil
 }
 
+// StorageInfo estimates the storage footprint of a dataset
+func (datasetImpl) StorageInfo(scope scope, p *StorageInfoParams) (*StorageInfo, error) {
+	if scope.SourceName() != "local" {
+		return nil, fmt.Errorf("can only calculate storage info from local storage")
+	}
+	ctx := scope.Context()
+
+	ref, _, err := scope.ParseAndResolveRef(ctx, p.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{ref.Path}
+	if p.AllVersions {
+		items, err := base.DatasetLog(ctx, scope.Repo(), ref, -1, 0, "", false)
+		if err != nil {
+			return nil, err
+		}
+		paths = paths[:0]
+		for _, item := range items {
+			// skip versions that aren't stored locally
+			if item.Foreign {
+				continue
+			}
+			paths = append(paths, item.Path)
+		}
+	}
+
+	versions := make([]map[string]uint64, 0, len(paths))
+	for _, path := range paths {
+		sizes, err := scope.Node().BlockSizes(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, sizes)
+	}
+	return newStorageInfo(versions), nil
+}
+
+// newStorageInfo sums a list of per-version block sizes, counting blocks that
+// appear in more than one version once towards unique size
+func newStorageInfo(versions []map[string]uint64) *StorageInfo {
+	info := &StorageInfo{Versions: len(versions)}
+	seen := map[string]bool{}
+	for _, blocks := range versions {
+		for id, size := range blocks {
+			info.Blocks++
+			info.LogicalSize += size
+			if !seen[id] {
+				seen[id] = true
+				info.UniqueBlocks++
+				info.UniqueSize += size
+			}
+		}
+	}
+	info.DedupSavings = info.LogicalSize - info.UniqueSize
+	return info
+}
+
+// Components lists the components of a dataset version, using the dataset
+// file's component references & the sizes of the blocks they point to
+func (datasetImpl) Components(scope scope, p *ComponentsParams) (map[string]ComponentInfo, error) {
+	if scope.SourceName() != "local" {
+		return nil, fmt.Errorf("can only list components from local storage")
+	}
+	ctx := scope.Context()
+
+	ref, _, err := scope.ParseAndResolveRef(ctx, p.Ref)
+	if err != nil {
+		return nil, err
+	}
+	ds, err := dsfs.LoadDatasetRefs(ctx, scope.Filesystem(), ref.Path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := scope.Node().NewDAGInfo(ctx, ref.Path, "")
+	if err != nil {
+		return nil, err
+	}
+	return newComponentInfos(ds.PathMap("dataset"), info), nil
+}
+
+// newComponentInfos pairs component paths with sizes from a dag.Info.
+// components missing from paths are reported as absent
+func newComponentInfos(paths map[string]string, info *dag.Info) map[string]ComponentInfo {
+	sizes := map[string]uint64{}
+	if info != nil && info.Manifest != nil {
+		for i, id := range info.Manifest.Nodes {
+			if i < len(info.Sizes) {
+				sizes[id] = info.Sizes[i]
+			}
+		}
+	}
+
+	res := make(map[string]ComponentInfo, len(componentNames))
+	for _, name := range componentNames {
+		path, ok := paths[name]
+		if !ok {
+			res[name] = ComponentInfo{}
+			continue
+		}
+		res[name] = ComponentInfo{
+			Present: true,
+			Path:    path,
+			Size:    sizes[dsfs.GetHashBase(path)],
+		}
+	}
+	return res
+}
+
+// Quality calculates dataset stats & flags columns that look problematic
+func (datasetImpl) Quality(scope scope, p *QualityParams) ([]base.QualityWarning, error) {
+	th := base.DefaultQualityThresholds()
+	if p.MaxNullRate != nil {
+		if *p.MaxNullRate < 0 || *p.MaxNullRate > 1 {
+			return nil, fmt.Errorf("max null rate must be between 0 and 1")
+		}
+		th.MaxNullRate = *p.MaxNullRate
+	}
+	if p.OutlierIQRMultiplier != nil {
+		if *p.OutlierIQRMultiplier < 0 {
+			return nil, fmt.Errorf("outlier multiplier cannot be negative")
+		}
+		th.OutlierIQRMultiplier = *p.OutlierIQRMultiplier
+	}
+
+	_, ds, err := openAndLoadDataset(scope, &GetParams{Ref: p.Ref, Selector: "stats"})
+	if err != nil {
+		return nil, err
+	}
+	sa, err := scope.Stats().Stats(scope.Context(), ds)
+	if err != nil {
+		return nil, err
+	}
+	return base.QualityReport(ds.Structure, sa.Stats, th)
+}
+
 // Render renders a viz or readme component as html
 func (datasetImpl) Render(scope scope, p *RenderParams) (res []byte, err error) {
 	ds := p.Dataset
@@ -1332,7 +3965,11 @@ func (datasetImpl) Render(scope scope, p *RenderParams) (res []byte, err error)
 
 	switch p.Selector {
 	case "viz":
-		res, err = base.Render(scope.Context(), scope.Repo(), ds, p.Template)
+		if p.InlineBody {
+			res, err = base.RenderInline(scope.Context(), scope.Repo(), ds, p.Template)
+		} else {
+			res, err = base.Render(scope.Context(), scope.Repo(), ds, p.Template)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -1352,8 +3989,22 @@ func (datasetImpl) Render(scope scope, p *RenderParams) (res []byte, err error)
 		if err != nil {
 			return nil, err
 		}
+	case "body":
+		if err := base.OpenDataset(scope.Context(), scope.Filesystem(), ds); err != nil {
+			return nil, err
+		}
+		// tables are capped at base.MaxBodyTableRows, so rendering never
+		// reads past the selected rows of a large body
+		limit := p.Limit
+		if limit == 0 {
+			limit = base.MaxBodyTableRows
+		}
+		res, err = base.RenderBodyTable(ds, limit, p.Offset, false)
+		if err != nil {
+			return nil, err
+		}
 	default:
-		return nil, fmt.Errorf("selector must be one of 'viz' or 'readme'")
+		return nil, fmt.Errorf("selector must be one of 'viz', 'readme' or 'body'")
 	}
 	return res, nil
 }
@@ -4,9 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
 
+	"github.com/qri-io/dataset"
 	"github.com/qri-io/dataset/tabular"
 	"github.com/qri-io/deepdiff"
+	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/base/component"
 	"github.com/qri-io/qri/base/dsfs"
 	"github.com/qri-io/qri/dsref"
@@ -27,8 +33,9 @@ func (m DiffMethods) Name() string {
 // Attributes defines attributes for each method
 func (m DiffMethods) Attributes() map[string]AttributeSet {
 	return map[string]AttributeSet{
-		"changes": {Endpoint: qhttp.AEChanges, HTTPVerb: "POST"},
-		"diff":    {Endpoint: qhttp.AEDiff, HTTPVerb: "POST"},
+		"changes": {Endpoint: qhttp.AEChanges, HTTPVerb: "POST", ReadOnly: true},
+		"diff":    {Endpoint: qhttp.AEDiff, HTTPVerb: "POST", ReadOnly: true},
+		"patch":   {Endpoint: qhttp.AEDiffPatch, HTTPVerb: "POST", ReadOnly: true},
 	}
 }
 
@@ -40,9 +47,9 @@ type Delta = deepdiff.Delta
 // away from packages that depend on lib
 type DiffStat = deepdiff.Stats
 
-// DiffParams defines parameters for diffing two sources. There are three valid ways to use these
+// DiffParams defines parameters for diffing two sources. There are four valid ways to use these
 // parameters: 1) both LeftSide and RightSide set, 2) only LeftSide set with a WorkingDir, 3) only
-// LeftSide set with the UseLeftPrevVersion flag.
+// LeftSide set with the UseLeftPrevVersion flag, 4) only LeftSide set with an Against file.
 type DiffParams struct {
 	// File paths or reference to datasets
 	LeftSide  string `schema:"leftPath" json:"leftPath" qri:"dsrefOrFspath"`
@@ -54,6 +61,12 @@ type DiffParams struct {
 
 	// Which component or part of a dataset to compare
 	Selector string
+	// Column to align body rows by before diffing. When set, rows are compared
+	// by key value instead of by position. Only valid when diffing bodies
+	Key string `json:"key"`
+	// File to compare the meta component of LeftSide to, in JSON or YAML
+	// format. Used for reviewing meta edits before saving them
+	Against string `schema:"against" json:"against" qri:"fspath"`
 }
 
 // diffMode determinse
@@ -61,6 +74,18 @@ func (p *DiffParams) diffMode() (DiffMode, error) {
 	// Check parameters to make sure they fit one of the three cases that diff allows.
 	if p.LeftSide == "" && p.RightSide == "" {
 		return InvalidDiffMode, fmt.Errorf("nothing to diff")
+	} else if p.Against != "" {
+		// Comparing the meta of a dataset to a file
+		if !dsref.IsRefString(p.LeftSide) || p.RightSide != "" {
+			return InvalidDiffMode, fmt.Errorf("can only compare a single dataset against a file")
+		}
+		if p.WorkingDir != "" || p.UseLeftPrevVersion {
+			return InvalidDiffMode, fmt.Errorf("cannot use previous version or working directory when comparing against a file")
+		}
+		if p.Selector != "" && p.Selector != "meta" {
+			return InvalidDiffMode, fmt.Errorf("can only diff meta against a file")
+		}
+		return AgainstFileDiffMode, nil
 	} else if p.LeftSide != "" && p.RightSide != "" {
 		// Have two string parameters to compare. Should either both be references, or neither
 		// be references.
@@ -119,6 +144,8 @@ const (
 	WorkingDirectoryDiffMode
 	// PrevVersionDiffMode will diff a dataset head against its previous version
 	PrevVersionDiffMode
+	// AgainstFileDiffMode will diff a dataset meta component against a file
+	AgainstFileDiffMode
 )
 
 // Diff computes the diff of two sources
@@ -130,6 +157,18 @@ func (m DiffMethods) Diff(ctx context.Context, p *DiffParams) (*DiffResponse, er
 	return nil, dispatchReturnError(got, err)
 }
 
+// Patch describes the changes between two dataset versions as a patch that
+// can be applied to another dataset with DatasetMethods.ApplyPatch. Patches
+// compare dataset references or a dataset to its previous version, and cover
+// the meta, structure & body components
+func (m DiffMethods) Patch(ctx context.Context, p *DiffParams) (*Patch, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "patch"), p)
+	if res, ok := got.(*Patch); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
 func schemaDiff(ctx context.Context, left, right *component.BodyComponent) ([]*Delta, *DiffStat, error) {
 	dd := deepdiff.New()
 	if left.Format == ".csv" && right.Format == ".csv" {
@@ -187,6 +226,15 @@ func (diffImpl) Diff(scope scope, p *DiffParams) (*DiffResponse, error) {
 			return nil, err
 		}
 
+		if p.Key != "" {
+			if leftData, err = keyBodyRows(leftComp, leftData, p.Key); err != nil {
+				return nil, fmt.Errorf("left side: %w", err)
+			}
+			if rightData, err = keyBodyRows(rightComp, rightData, p.Key); err != nil {
+				return nil, fmt.Errorf("right side: %w", err)
+			}
+		}
+
 		dd := deepdiff.New()
 		res.Diff, res.Stat, err = dd.StatDiff(scope.Context(), leftData, rightData)
 		if err != nil {
@@ -207,6 +255,9 @@ func (diffImpl) Diff(scope scope, p *DiffParams) (*DiffResponse, error) {
 	// calling ds.DropDerivedValues is overzealous. investigate the right solution
 	ds.Name = ""
 	ds.Peername = ""
+	if diffMode == AgainstFileDiffMode && ds.Meta == nil {
+		ds.Meta = &dataset.Meta{}
+	}
 	leftComp := component.ConvertDatasetToComponents(ds, scope.Filesystem())
 
 	// Right side of diff laoded into a component
@@ -241,6 +292,23 @@ func (diffImpl) Diff(scope scope, p *DiffParams) (*DiffResponse, error) {
 			return nil, err
 		}
 		leftComp = component.ConvertDatasetToComponents(ds, scope.Filesystem())
+	case AgainstFileDiffMode:
+		format := strings.TrimPrefix(strings.ToLower(filepath.Ext(p.Against)), ".")
+		if format == "yml" {
+			format = "yaml"
+		}
+		if format != "json" && format != "yaml" {
+			return nil, fmt.Errorf("meta file must be json or yaml, got %q", filepath.Base(p.Against))
+		}
+		fc := &component.FilesysComponent{}
+		mc := fc.SetSubcomponent("meta", component.BaseComponent{SourceFile: p.Against, Format: format})
+		if err := mc.LoadAndFill(nil); err != nil {
+			return nil, fmt.Errorf("reading meta file: %w", err)
+		}
+		// derived values are never expected in an edited meta file
+		mc.DropDerivedValues()
+		leftComp.Base().GetSubcomponent("meta").DropDerivedValues()
+		rightComp = fc
 	case DatasetRefDiffMode:
 		ds, err = scope.Loader().LoadDataset(scope.Context(), p.RightSide)
 		if err != nil {
@@ -311,7 +379,16 @@ func (diffImpl) Diff(scope scope, p *DiffParams) (*DiffResponse, error) {
 	}
 
 	selector := p.Selector
-	if selector == "" {
+	if p.Key != "" {
+		if selector == "" {
+			selector = "body"
+		} else if selector != "body" {
+			return nil, fmt.Errorf("can only diff by key when comparing bodies")
+		}
+	}
+	if selector == "" && diffMode == AgainstFileDiffMode {
+		selector = "meta"
+	} else if selector == "" {
 		selector = "dataset"
 	}
 	leftComp = leftComp.Base().GetSubcomponent(selector)
@@ -329,6 +406,23 @@ func (diffImpl) Diff(scope scope, p *DiffParams) (*DiffResponse, error) {
 		return nil, err
 	}
 
+	if p.Key != "" {
+		leftBody, ok := leftComp.(*component.BodyComponent)
+		if !ok {
+			return nil, fmt.Errorf("can only diff by key when comparing bodies")
+		}
+		rightBody, ok := rightComp.(*component.BodyComponent)
+		if !ok {
+			return nil, fmt.Errorf("can only diff by key when comparing bodies")
+		}
+		if leftData, err = keyBodyRows(leftBody, leftData, p.Key); err != nil {
+			return nil, fmt.Errorf("left side: %w", err)
+		}
+		if rightData, err = keyBodyRows(rightBody, rightData, p.Key); err != nil {
+			return nil, fmt.Errorf("right side: %w", err)
+		}
+	}
+
 	dd := deepdiff.New()
 	res.Diff, res.Stat, err = dd.StatDiff(scope.Context(), leftData, rightData)
 	if err != nil {
@@ -336,3 +430,185 @@ func (diffImpl) Diff(scope scope, p *DiffParams) (*DiffResponse, error) {
 	}
 	return res, nil
 }
+
+// Patch describes the changes between two dataset versions as a patch
+func (diffImpl) Patch(scope scope, p *DiffParams) (*Patch, error) {
+	if p.Selector != "" {
+		return nil, fmt.Errorf("patches cover all components, cannot select %q", p.Selector)
+	}
+	diffMode, err := p.diffMode()
+	if err != nil {
+		return nil, err
+	}
+
+	var prev, next *dataset.Dataset
+	switch diffMode {
+	case DatasetRefDiffMode:
+		if _, prev, err = openAndLoadDataset(scope, &GetParams{Ref: p.LeftSide}); err != nil {
+			return nil, err
+		}
+		if _, next, err = openAndLoadDataset(scope, &GetParams{Ref: p.RightSide}); err != nil {
+			return nil, err
+		}
+	case PrevVersionDiffMode:
+		ref, head, err := openAndLoadDataset(scope, &GetParams{Ref: p.LeftSide})
+		if err != nil {
+			return nil, err
+		}
+		if head.PreviousPath == "" {
+			return nil, fmt.Errorf("dataset has only one version, nothing to diff against")
+		}
+		prevRef := dsref.Ref{Username: ref.Username, Name: ref.Name, Path: head.PreviousPath}
+		if _, prev, err = openAndLoadDataset(scope, &GetParams{Ref: prevRef.String()}); err != nil {
+			return nil, err
+		}
+		next = head
+	default:
+		return nil, fmt.Errorf("patches can only be made between two dataset versions")
+	}
+
+	return makePatch(prev, next, p.Key)
+}
+
+// keyBodyRows aligns the rows of a body by the value of a key column,
+// returning an object of key value: row. Rows of tabular bodies locate the key
+// column by schema title, rows of object-entry bodies by property name. A
+// row without the key or sharing a key with an earlier row is an error
+func keyBodyRows(bc *component.BodyComponent, data interface{}, key string) (map[string]interface{}, error) {
+	rows, ok := data.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("body must be an array to diff by key")
+	}
+	keys, err := bodyRowKeys(bc, rows, key)
+	if err != nil {
+		return nil, err
+	}
+	keyed := make(map[string]interface{}, len(rows))
+	for i, k := range keys {
+		keyed[k] = rows[i]
+	}
+	return keyed, nil
+}
+
+// bodyRowKeys returns the value of a key column for each row of a body as a
+// string, in row order. Keys must be present & unique
+func bodyRowKeys(bc *component.BodyComponent, rows []interface{}, key string) ([]string, error) {
+	keyIndex := -1
+	schema := bc.InferredSchema
+	if bc.Structure != nil && bc.Structure.Schema != nil {
+		schema = bc.Structure.Schema
+	}
+	if cols, _, err := tabular.ColumnsFromJSONSchema(schema); err == nil {
+		for i, title := range cols.Titles() {
+			if title == key {
+				keyIndex = i
+				break
+			}
+		}
+	}
+
+	keys := make([]string, len(rows))
+	seen := make(map[string]bool, len(rows))
+	for i, row := range rows {
+		var (
+			val   interface{}
+			found bool
+		)
+		switch r := row.(type) {
+		case []interface{}:
+			if keyIndex >= 0 && keyIndex < len(r) {
+				val, found = r[keyIndex], true
+			}
+		case map[string]interface{}:
+			val, found = r[key]
+		}
+		if !found {
+			return nil, fmt.Errorf("row %d has no value for key %q", i, key)
+		}
+
+		k := fmt.Sprintf("%v", val)
+		if seen[k] {
+			return nil, fmt.Errorf("duplicate key %q in row %d", k, i)
+		}
+		seen[k] = true
+		keys[i] = k
+	}
+	return keys, nil
+}
+
+// BodyDelta lists the body rows that changed between two versions of a
+// dataset. Added & Modified hold rows as they appear in the newer version,
+// Deleted holds the keys of rows the newer version no longer has
+type BodyDelta struct {
+	// Key is the column rows were matched by. Without a key rows are matched
+	// by position, and deleted keys are row indexes
+	Key      string        `json:"key,omitempty"`
+	Added    []interface{} `json:"added"`
+	Modified []interface{} `json:"modified"`
+	Deleted  []string      `json:"deleted"`
+}
+
+// bodyRowDelta compares the rows of two bodies, matching rows by key column
+// when key is set, by position otherwise
+func bodyRowDelta(prev, next *dataset.Dataset, key string) (*BodyDelta, error) {
+	prevRows, prevKeys, err := bodyDeltaRows(prev, key)
+	if err != nil {
+		return nil, fmt.Errorf("since version: %w", err)
+	}
+	nextRows, nextKeys, err := bodyDeltaRows(next, key)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &BodyDelta{
+		Key:      key,
+		Added:    []interface{}{},
+		Modified: []interface{}{},
+		Deleted:  []string{},
+	}
+	prevByKey := make(map[string]interface{}, len(prevRows))
+	for i, k := range prevKeys {
+		prevByKey[k] = prevRows[i]
+	}
+	nextHas := make(map[string]bool, len(nextRows))
+	for i, k := range nextKeys {
+		nextHas[k] = true
+		prevRow, ok := prevByKey[k]
+		if !ok {
+			res.Added = append(res.Added, nextRows[i])
+		} else if !reflect.DeepEqual(prevRow, nextRows[i]) {
+			res.Modified = append(res.Modified, nextRows[i])
+		}
+	}
+	for _, k := range prevKeys {
+		if !nextHas[k] {
+			res.Deleted = append(res.Deleted, k)
+		}
+	}
+	return res, nil
+}
+
+// bodyDeltaRows reads the rows of an opened dataset body along with the key
+// of each row
+func bodyDeltaRows(ds *dataset.Dataset, key string) ([]interface{}, []string, error) {
+	body, err := base.GetBody(ds, -1, 0, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, ok := body.([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("body must be an array to compare rows")
+	}
+	if key == "" {
+		keys := make([]string, len(rows))
+		for i := range rows {
+			keys[i] = strconv.Itoa(i)
+		}
+		return rows, keys, nil
+	}
+	keys, err := bodyRowKeys(&component.BodyComponent{Structure: ds.Structure}, rows, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, keys, nil
+}
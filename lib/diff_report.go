@@ -0,0 +1,155 @@
+package lib
+
+import (
+	"strings"
+
+	"github.com/qri-io/deepdiff"
+)
+
+const (
+	// DiffOpInsert adds a value
+	DiffOpInsert = "insert"
+	// DiffOpDelete removes a value
+	DiffOpDelete = "delete"
+	// DiffOpModify replaces a value
+	DiffOpModify = "modify"
+)
+
+// DiffReport is a machine-readable description of a diff. Where a
+// DiffResponse nests changes in a tree alongside unchanged context, a report
+// lists only the changes, each as a single operation grouped by the dataset
+// component it belongs to
+type DiffReport struct {
+	Summary *DiffSummary `json:"summary"`
+	// Components maps component names like "meta" & "body" to the operations
+	// that change them. Dataset fields that aren't part of a component, like
+	// "path", are listed under "dataset"
+	Components map[string][]*DiffOp `json:"components"`
+	// Schema lists changes to the schema of compared body files
+	Schema []*DiffOp `json:"schema,omitempty"`
+}
+
+// DiffSummary counts the elements a diff compares & changes
+type DiffSummary struct {
+	LeftElements  int `json:"leftElements"`
+	RightElements int `json:"rightElements"`
+	// Elements is the change in element count, right minus left
+	Elements int `json:"elements"`
+	Inserts  int `json:"inserts"`
+	Updates  int `json:"updates"`
+	Deletes  int `json:"deletes"`
+}
+
+// DiffOp is a single change. Path is a JSON pointer into the component. Old
+// is set for deletes & modifies, New for inserts & modifies
+type DiffOp struct {
+	Op   string      `json:"op"`
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// reportComponents are top-level dataset fields reported as components
+var reportComponents = map[string]bool{
+	"commit":    true,
+	"meta":      true,
+	"structure": true,
+	"body":      true,
+	"readme":    true,
+	"transform": true,
+	"viz":       true,
+	"stats":     true,
+}
+
+// NewDiffReport builds a report from the result of diffing with params p
+func NewDiffReport(p *DiffParams, res *DiffResponse) *DiffReport {
+	r := &DiffReport{
+		Summary:    &DiffSummary{},
+		Components: map[string][]*DiffOp{},
+	}
+	if res.Stat != nil {
+		r.Summary = &DiffSummary{
+			LeftElements:  res.Stat.Left,
+			RightElements: res.Stat.Right,
+			Elements:      res.Stat.NodeChange(),
+			Inserts:       res.Stat.Inserts,
+			Updates:       res.Stat.Updates,
+			Deletes:       res.Stat.Deletes,
+		}
+	}
+	if len(res.Schema) > 0 {
+		r.Schema = diffOps(nil, "", res.Schema)
+	}
+
+	mode, _ := p.diffMode()
+	switch {
+	case mode == FilepathDiffMode:
+		// comparing files only ever compares bodies
+		if ops := diffOps(nil, "", res.Diff); len(ops) > 0 {
+			r.Components["body"] = ops
+		}
+	case p.Selector != "":
+		// a selected component, or a field within one
+		parts := strings.Split(p.Selector, ".")
+		prefix := ""
+		for _, part := range parts[1:] {
+			prefix += "/" + escapePointer(part)
+		}
+		if ops := diffOps(nil, prefix, res.Diff); len(ops) > 0 {
+			r.Components[parts[0]] = ops
+		}
+	default:
+		for _, d := range res.Diff {
+			name := "dataset"
+			if key := d.Path.String(); reportComponents[key] {
+				name = key
+				if d.Type == deepdiff.DTContext {
+					r.Components[name] = diffOps(r.Components[name], "", d.Deltas)
+					continue
+				}
+				// a whole component was added or removed
+				r.Components[name] = appendDiffOp(r.Components[name], "", d)
+				continue
+			}
+			r.Components[name] = diffOps(r.Components[name], "", []*Delta{d})
+		}
+		for name, ops := range r.Components {
+			if len(ops) == 0 {
+				delete(r.Components, name)
+			}
+		}
+	}
+	return r
+}
+
+// diffOps appends the changes of a delta tree to ops, skipping unchanged
+// context. prefix is the JSON pointer deltas are located at
+func diffOps(ops []*DiffOp, prefix string, deltas []*Delta) []*DiffOp {
+	for _, d := range deltas {
+		path := prefix + "/" + escapePointer(d.Path.String())
+		if d.Type == deepdiff.DTContext {
+			ops = diffOps(ops, path, d.Deltas)
+			continue
+		}
+		ops = appendDiffOp(ops, path, d)
+	}
+	return ops
+}
+
+// appendDiffOp converts a single changed delta to an operation
+func appendDiffOp(ops []*DiffOp, path string, d *Delta) []*DiffOp {
+	switch d.Type {
+	case deepdiff.DTInsert:
+		return append(ops, &DiffOp{Op: DiffOpInsert, Path: path, New: d.Value})
+	case deepdiff.DTDelete:
+		return append(ops, &DiffOp{Op: DiffOpDelete, Path: path, Old: d.Value})
+	case deepdiff.DTUpdate:
+		return append(ops, &DiffOp{Op: DiffOpModify, Path: path, Old: d.SourceValue, New: d.Value})
+	}
+	return ops
+}
+
+// escapePointer escapes a JSON pointer reference token, see RFC 6901
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
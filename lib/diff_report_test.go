@@ -0,0 +1,59 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/deepdiff"
+)
+
+func TestNewDiffReport(t *testing.T) {
+	res := &DiffResponse{
+		Stat: &DiffStat{Left: 10, Right: 11, Inserts: 2, Updates: 1, Deletes: 1},
+		Diff: []*Delta{
+			{Type: deepdiff.DTDelete, Path: deepdiff.StringAddr("path"), Value: "/mem/QmPrev"},
+			{Type: deepdiff.DTInsert, Path: deepdiff.StringAddr("path"), Value: "/mem/QmNext"},
+			{Type: deepdiff.DTContext, Path: deepdiff.StringAddr("body"), Deltas: []*Delta{
+				{Type: deepdiff.DTContext, Path: deepdiff.IndexAddr(1), Deltas: []*Delta{
+					{Type: deepdiff.DTContext, Path: deepdiff.IndexAddr(0), Value: "chatham"},
+					{Type: deepdiff.DTUpdate, Path: deepdiff.IndexAddr(1), Value: float64(12), SourceValue: float64(10)},
+				}},
+			}},
+			{Type: deepdiff.DTInsert, Path: deepdiff.StringAddr("readme"), Value: map[string]interface{}{"text": "# hi"}},
+			{Type: deepdiff.DTContext, Path: deepdiff.StringAddr("meta"), Deltas: []*Delta{
+				{Type: deepdiff.DTContext, Path: deepdiff.StringAddr("title")},
+			}},
+		},
+	}
+
+	got := NewDiffReport(&DiffParams{LeftSide: "me/cities", UseLeftPrevVersion: true}, res)
+	expect := &DiffReport{
+		Summary: &DiffSummary{LeftElements: 10, RightElements: 11, Elements: 1, Inserts: 2, Updates: 1, Deletes: 1},
+		Components: map[string][]*DiffOp{
+			"dataset": {
+				{Op: DiffOpDelete, Path: "/path", Old: "/mem/QmPrev"},
+				{Op: DiffOpInsert, Path: "/path", New: "/mem/QmNext"},
+			},
+			"body": {
+				{Op: DiffOpModify, Path: "/1/1", Old: float64(10), New: float64(12)},
+			},
+			"readme": {
+				{Op: DiffOpInsert, Path: "", New: map[string]interface{}{"text": "# hi"}},
+			},
+		},
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("report mismatch (-want +got):\n%s", diff)
+	}
+
+	res = &DiffResponse{Diff: []*Delta{
+		{Type: deepdiff.DTUpdate, Path: deepdiff.StringAddr("a/b"), Value: "x", SourceValue: "y"},
+	}}
+	got = NewDiffReport(&DiffParams{LeftSide: "me/cities", RightSide: "me/towns", Selector: "structure.schema"}, res)
+	expectOps := map[string][]*DiffOp{
+		"structure": {{Op: DiffOpModify, Path: "/schema/a~1b", Old: "y", New: "x"}},
+	}
+	if diff := cmp.Diff(expectOps, got.Components); diff != "" {
+		t.Errorf("selected component mismatch (-want +got):\n%s", diff)
+	}
+}
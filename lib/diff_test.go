@@ -284,6 +284,175 @@ func TestDiffLocalJsonFiles(t *testing.T) {
 	}
 }
 
+func TestDiffAgainstMetaFile(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+
+	metaPath := run.MustWriteTmpFile(t, "meta.json", `{"title":"Cities","keywords":["cities"]}`)
+	if _, err := run.SaveWithParams(&SaveParams{
+		Ref:       "me/test_cities",
+		BodyPath:  "testdata/cities_2/body.csv",
+		FilePaths: []string{metaPath},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	jsonPath := run.MustWriteTmpFile(t, "new_meta.json", `{"title":"City Populations","keywords":["cities"]}`)
+	output, err := run.DiffWithParams(&DiffParams{LeftSide: "me/test_cities", Against: jsonPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"stat":{"leftNodes":5,"rightNodes":5,"leftWeight":41,"rightWeight":61,"inserts":1,"deletes":1},"diff":[[" ","keywords",["cities"]],[" ","qri","md:0"],["-","title","Cities"],["+","title","City Populations"]]}`
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	yamlPath := run.MustWriteTmpFile(t, "new_meta.yaml", "title: Cities\nkeywords:\n- cities\n- population\n")
+	output, err = run.DiffWithParams(&DiffParams{LeftSide: "me/test_cities", Against: yamlPath, Selector: "meta"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect = `{"stat":{"leftNodes":5,"rightNodes":6,"leftWeight":41,"rightWeight":71,"inserts":1},"diff":[[" ","keywords",null,[[" ",0,"cities"],["+",1,"population"]]],[" ","qri","md:0"],[" ","title","Cities"]]}`
+	if diff := cmp.Diff(expect, output); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = run.DiffWithParams(&DiffParams{LeftSide: "me/test_cities", Against: yamlPath, Selector: "body"})
+	if expect := "can only diff meta against a file"; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+	_, err = run.DiffWithParams(&DiffParams{LeftSide: "me/test_cities", Against: "testdata/cities_2/body.csv"})
+	if expect := `meta file must be json or yaml, got "body.csv"`; errorMessage(err) != expect {
+		t.Errorf("error mismatch. want: %q, got: %q", expect, errorMessage(err))
+	}
+}
+
+func TestDiffKeyed(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+
+	// insert a row at the top & change toronto's population
+	changedPath := run.MustWriteTmpFile(t, "cities_changed.csv", `city,pop,avg_age,in_usa
+los angeles,3990000,42.7,true
+toronto,40000000,55.5,false
+new york,8500000,44.4,true
+chicago,300000,44.4,true
+chatham,35000,65.25,true
+raleigh,250000,50.65,true
+`)
+
+	res, err := run.Instance.Diff().Diff(run.Ctx, &DiffParams{
+		LeftSide:  "testdata/cities_2/body.csv",
+		RightSide: changedPath,
+		Key:       "city",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// los angeles is inserted, toronto's population is deleted & re-inserted
+	if res.Stat.Inserts != 2 || res.Stat.Deletes != 1 {
+		t.Errorf("expected 2 inserts & 1 delete for keyed file diff, got: %#v", res.Stat)
+	}
+
+	run.MustSaveFromBody(t, "test_cities", "testdata/cities_2/body.csv")
+	run.MustSaveFromBody(t, "test_changed", changedPath)
+
+	res, err = run.Instance.Diff().Diff(run.Ctx, &DiffParams{
+		LeftSide:  "me/test_cities",
+		RightSide: "me/test_changed",
+		Key:       "city",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// los angeles is inserted, toronto's population is deleted & re-inserted
+	if res.Stat.Inserts != 2 || res.Stat.Deletes != 1 {
+		t.Errorf("expected 2 inserts & 1 delete for keyed dataset diff, got: %#v", res.Stat)
+	}
+
+	_, err = run.DiffWithParams(&DiffParams{
+		LeftSide:  "me/test_cities",
+		RightSide: "me/test_changed",
+		Selector:  "meta",
+		Key:       "city",
+	})
+	expectErr := `can only diff by key when comparing bodies`
+	if diff := cmp.Diff(expectErr, errorMessage(err)); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = run.DiffWithParams(&DiffParams{
+		LeftSide:  "testdata/cities_2/body.csv",
+		RightSide: changedPath,
+		Key:       "in_usa",
+	})
+	expectErr = `left side: duplicate key "true" in row 2`
+	if diff := cmp.Diff(expectErr, errorMessage(err)); diff != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBodyDelta(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+
+	prev := run.MustSaveFromBody(t, "test_cities", "testdata/cities_2/body.csv")
+	// add los angeles, change toronto's population & drop chicago
+	changedPath := run.MustWriteTmpFile(t, "cities_changed.csv", `city,pop,avg_age,in_usa
+los angeles,3990000,42.7,true
+toronto,40000000,55.5,false
+new york,8500000,44.4,true
+chatham,35000,65.25,true
+raleigh,250000,50.65,true
+`)
+	run.MustSaveFromBody(t, "test_cities", changedPath)
+
+	res, err := run.Instance.Dataset().BodyDelta(run.Ctx, &BodyDeltaParams{
+		Ref:       "me/test_cities",
+		SincePath: prev.Path,
+		Key:       "city",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := &BodyDelta{
+		Key:      "city",
+		Added:    []interface{}{[]interface{}{"los angeles", int64(3990000), 42.7, true}},
+		Modified: []interface{}{[]interface{}{"toronto", int64(40000000), 55.5, false}},
+		Deleted:  []string{"chicago"},
+	}
+	if diff := cmp.Diff(expect, res); diff != "" {
+		t.Errorf("keyed delta mismatch (-want +got):\n%s", diff)
+	}
+
+	// without a key rows are compared by position
+	res, err = run.Instance.Dataset().BodyDelta(run.Ctx, &BodyDeltaParams{
+		Ref:       "me/test_cities",
+		SincePath: prev.Path,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Added) != 0 || len(res.Modified) != 3 || len(res.Deleted) != 0 {
+		t.Errorf("expected 3 modified rows for positional delta, got: %#v", res)
+	}
+
+	_, err = run.Instance.Dataset().BodyDelta(run.Ctx, &BodyDeltaParams{Ref: "me/test_cities"})
+	if diff := cmp.Diff("a since path is required", errorMessage(err)); diff != "" {
+		t.Errorf("error mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = run.Instance.Dataset().BodyDelta(run.Ctx, &BodyDeltaParams{
+		Ref:       "me/test_cities",
+		SincePath: prev.Path,
+		Key:       "in_usa",
+	})
+	expectErr := `since version: duplicate key "true" in row 2`
+	if diff := cmp.Diff(expectErr, errorMessage(err)); diff != "" {
+		t.Errorf("error mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestDiffErrors(t *testing.T) {
 	run := newTestRunner(t)
 	defer run.Delete()
@@ -53,6 +53,9 @@ type AttributeSet struct {
 	DefaultSource string
 	// whether to deny RPC for this endpoint, normal HTTP may still be allowed
 	DenyRPC bool
+	// whether the method leaves the repo unchanged. API servers configured
+	// to be read-only only serve read-only methods
+	ReadOnly bool
 }
 
 // Dispatch is a system for handling calls to lib. Should only be called by top-level lib methods.
@@ -81,6 +84,19 @@ func (isw *InstanceSourceWrap) Dispatch(ctx context.Context, method string, para
 	return isw.inst.dispatchMethodCall(ctx, method, param, isw.source)
 }
 
+// defaultSource returns the source for methods that neither receive an
+// explicit source nor declare a default. the OptDefaultSource value takes
+// precedence over the repo.defaultSource config setting
+func (inst *Instance) defaultSource() string {
+	if inst.defaultSrc != "" {
+		return inst.defaultSrc
+	}
+	if inst.cfg != nil && inst.cfg.Repo != nil {
+		return inst.cfg.Repo.DefaultSource
+	}
+	return ""
+}
+
 func (inst *Instance) dispatchMethodCall(ctx context.Context, method string, param interface{}, source string) (res interface{}, cur Cursor, err error) {
 	if inst == nil {
 		return nil, nil, ErrDispatchNilInstance
@@ -120,6 +136,9 @@ func (inst *Instance) dispatchMethodCall(ctx context.Context, method string, par
 			if c.DenyRPC {
 				return nil, nil, qhttp.ErrUnsupportedRPC
 			}
+			if source == "" && c.Source == "" {
+				source = inst.defaultSource()
+			}
 			if c.OutType != nil {
 				out := reflect.New(c.OutType)
 				res = out.Interface()
@@ -148,10 +167,13 @@ func (inst *Instance) dispatchMethodCall(ctx context.Context, method string, par
 	// Look up the method for the given signifier
 	if c, ok := inst.regMethods.lookup(method); ok {
 		// If this method has a default source and no override exists, use that
-		// default instead
+		// default instead, falling back to the instance's configured default
 		if source == "" {
 			source = c.Source
 		}
+		if source == "" {
+			source = inst.defaultSource()
+		}
 		// Construct the isolated scope for this call
 		// TODO(dustmop): Add user authentication, profile, identity, etc
 		// TODO(dustmop): Also determine if the method is read-only vs read-write,
@@ -255,6 +277,7 @@ type callable struct {
 	Verb      string
 	Source    string
 	DenyRPC   bool
+	ReadOnly  bool
 }
 
 // AllMethods returns a method set for documentation purposes
@@ -268,6 +291,7 @@ func (inst *Instance) AllMethods() []MethodSet {
 		inst.Dataset(),
 		inst.Diff(),
 		inst.Log(),
+		inst.Ops(),
 		inst.Peer(),
 		inst.Profile(),
 		inst.Registry(),
@@ -288,6 +312,7 @@ func (inst *Instance) RegisterMethods() {
 	inst.registerOne("dataset", inst.Dataset(), datasetImpl{}, reg)
 	inst.registerOne("diff", inst.Diff(), diffImpl{}, reg)
 	inst.registerOne("log", inst.Log(), logImpl{}, reg)
+	inst.registerOne("ops", inst.Ops(), opsImpl{}, reg)
 	inst.registerOne("peer", inst.Peer(), peerImpl{}, reg)
 	inst.registerOne("profile", inst.Profile(), profileImpl{}, reg)
 	inst.registerOne("registry", inst.Registry(), registryImpl{}, reg)
@@ -441,6 +466,7 @@ func (inst *Instance) registerOne(ourName string, methods MethodSet, impl interf
 			Verb:      methodAttrs.HTTPVerb,
 			Source:    methodAttrs.DefaultSource,
 			DenyRPC:   methodAttrs.DenyRPC,
+			ReadOnly:  methodAttrs.ReadOnly,
 		}
 	}
 
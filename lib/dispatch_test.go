@@ -208,6 +208,39 @@ func TestDefaultSource(t *testing.T) {
 	if got != expect {
 		t.Errorf("value mismatch, expect: %s, got: %s", expect, got)
 	}
+
+	// A configured default source applies to methods without a default
+	inst.cfg.Repo.DefaultSource = "local"
+	got, err = m.One(ctx, &getSrcParams{})
+	if err != nil {
+		t.Fatalf("m.One call failed, err=%s", err)
+	}
+	expect = `one source="local"`
+	if got != expect {
+		t.Errorf("value mismatch, expect: %s, got: %s", expect, got)
+	}
+
+	// The instance default source takes precedence over configuration
+	inst.defaultSrc = "network"
+	got, err = m.One(ctx, &getSrcParams{})
+	if err != nil {
+		t.Fatalf("m.One call failed, err=%s", err)
+	}
+	expect = `one source="network"`
+	if got != expect {
+		t.Errorf("value mismatch, expect: %s, got: %s", expect, got)
+	}
+
+	// Methods that declare a default source keep it
+	inst.defaultSrc = "registry"
+	got, err = m.Two(ctx, &getSrcParams{})
+	if err != nil {
+		t.Fatalf("m.Two call failed, err=%s", err)
+	}
+	expect = `two source="network"`
+	if got != expect {
+		t.Errorf("value mismatch, expect: %s, got: %s", expect, got)
+	}
 }
 
 func serverConnectAndListen(t *testing.T, servInst *Instance, port int) (*qhttp.Client, func()) {
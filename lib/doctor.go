@@ -0,0 +1,246 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qri-io/qri/base/params"
+	"github.com/qri-io/qri/dscache/build"
+	"github.com/qri-io/qri/logbook"
+)
+
+// DoctorParams defines parameters for the Doctor method
+type DoctorParams struct {
+	// Fix rebuilds the dataset cache when it disagrees with the logbook
+	Fix bool `json:"fix"`
+	// RemoveStrandedLogs removes dataset logs that don't resolve to a saved
+	// version. removing a log deletes its history & can't be undone, so it
+	// must be set explicitly in addition to Fix
+	RemoveStrandedLogs bool `json:"removeStrandedLogs"`
+}
+
+// DoctorFinding is a problem found by a repo check
+type DoctorFinding struct {
+	// Check is the name of the check that found the problem
+	Check   string `json:"check"`
+	Problem string `json:"problem"`
+	// Suggestion describes how to fix the problem
+	Suggestion string `json:"suggestion"`
+	// Fixable is true when running with Fix set can fix the problem. stranded
+	// logs also need RemoveStrandedLogs set
+	Fixable bool `json:"fixable"`
+	Fixed   bool `json:"fixed"`
+}
+
+// DoctorReport lists the checks Doctor ran & the problems they found
+type DoctorReport struct {
+	Checks   []string         `json:"checks"`
+	Findings []*DoctorFinding `json:"findings"`
+}
+
+// Doctor checks the repo for common problems: configured filesystems that
+// aren't available, dataset logs that don't resolve to a saved version,
+// collection entries with head versions missing from the store, and a dataset
+// cache that disagrees with the logbook. Fix rebuilds the dataset cache, and
+// removes stranded logs when RemoveStrandedLogs is also set. other problems
+// are reported with a suggested fix
+func (m DatasetMethods) Doctor(ctx context.Context, p *DoctorParams) (*DoctorReport, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "doctor"), p)
+	if res, ok := got.(*DoctorReport); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// doctorCheck examines one part of the repo, fixing the problems it finds
+// when params ask for it
+type doctorCheck struct {
+	name string
+	run  func(scope scope, p *DoctorParams) ([]*DoctorFinding, error)
+}
+
+// doctorChecks are run in order. checks with fixes run before checks that
+// depend on the state they fix
+var doctorChecks = []doctorCheck{
+	{"store", checkStore},
+	{"logbook", checkStrandedLogs},
+	{"collection", checkCollectionHeads},
+	{"dscache", checkDscache},
+}
+
+// Doctor runs repo checks
+func (datasetImpl) Doctor(scope scope, p *DoctorParams) (*DoctorReport, error) {
+	res := &DoctorReport{
+		Checks:   []string{},
+		Findings: []*DoctorFinding{},
+	}
+	for _, c := range doctorChecks {
+		findings, err := c.run(scope, p)
+		if err != nil {
+			return nil, fmt.Errorf("%s check: %w", c.name, err)
+		}
+		for _, f := range findings {
+			f.Check = c.name
+		}
+		res.Checks = append(res.Checks, c.name)
+		res.Findings = append(res.Findings, findings...)
+	}
+	return res, nil
+}
+
+// checkStore reports configured filesystems the instance couldn't connect to
+func checkStore(scope scope, _ *DoctorParams) ([]*DoctorFinding, error) {
+	cfg := scope.Config()
+	if cfg == nil {
+		return nil, nil
+	}
+	var res []*DoctorFinding
+	fs := scope.Filesystem()
+	for _, fsCfg := range cfg.Filesystems {
+		if fs != nil && fs.Filesystem(fsCfg.Type) != nil {
+			continue
+		}
+		f := &DoctorFinding{
+			Problem:    fmt.Sprintf("filesystem %q is configured but not available", fsCfg.Type),
+			Suggestion: "check the filesystem configuration with 'qri config get filesystems'",
+		}
+		if fsCfg.Type == "ipfs" {
+			f.Suggestion = "check another process isn't using the ipfs repo, and that 'qri setup' has been run"
+		}
+		res = append(res, f)
+	}
+	return res, nil
+}
+
+// checkStrandedLogs reports dataset logs that don't resolve to a saved version.
+// logs are only removed when both Fix & RemoveStrandedLogs are set
+func checkStrandedLogs(scope scope, p *DoctorParams) ([]*DoctorFinding, error) {
+	book := scope.Logbook()
+	if book == nil {
+		return nil, nil
+	}
+	stranded, err := book.StrandedLogs(scope.Context(), scope.Filesystem())
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*DoctorFinding
+	for _, s := range stranded {
+		problem := fmt.Sprintf("dataset log %s/%s has no saved versions", s.Ref.Username, s.Ref.Name)
+		if s.Reason == logbook.StrandedMissingHead {
+			problem = fmt.Sprintf("dataset log %s/%s has head version %s, which isn't in the store", s.Ref.Username, s.Ref.Name, s.Ref.Path)
+		}
+		res = append(res, &DoctorFinding{
+			Problem:    problem,
+			Suggestion: "it's usually left behind by a failed save. run with --fix and confirm to remove the log & its history",
+			Fixable:    true,
+		})
+	}
+	if p.Fix && p.RemoveStrandedLogs && len(res) > 0 {
+		if _, err := (logImpl{}).LogbookDoctor(scope, &LogbookDoctorParams{Fix: true}); err != nil {
+			return nil, err
+		}
+		for _, f := range res {
+			f.Fixed = true
+		}
+	}
+	return res, nil
+}
+
+// checkCollectionHeads reports datasets in the active profile's collection
+// with a head version the store doesn't have
+func checkCollectionHeads(scope scope, _ *DoctorParams) ([]*DoctorFinding, error) {
+	set := scope.CollectionSet()
+	pro := scope.ActiveProfile()
+	if set == nil || pro == nil {
+		return nil, nil
+	}
+	ctx := scope.Context()
+	items, err := set.List(ctx, pro.ID, params.ListAll)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*DoctorFinding
+	for _, vi := range items {
+		if vi.Path == "" {
+			continue
+		}
+		has, err := scope.Filesystem().Has(ctx, vi.Path)
+		if err != nil {
+			log.Debugw("checking collection head", "ref", vi.SimpleRef(), "err", err)
+		}
+		if has {
+			continue
+		}
+		ref := vi.SimpleRef().Human()
+		res = append(res, &DoctorFinding{
+			Problem:    fmt.Sprintf("head version %s of %s isn't in the store", vi.Path, ref),
+			Suggestion: fmt.Sprintf("pull the dataset again with 'qri pull %s', or remove it with 'qri remove --all %s'", ref, ref),
+		})
+	}
+	return res, nil
+}
+
+// checkDscache reports datasets the dataset cache & logbook disagree about.
+// fixing rebuilds the cache from the repo
+func checkDscache(scope scope, p *DoctorParams) ([]*DoctorFinding, error) {
+	cache := scope.Dscache()
+	book := scope.Logbook()
+	if cache == nil || cache.IsEmpty() || book == nil {
+		return nil, nil
+	}
+	ctx := scope.Context()
+
+	cached, err := cache.ListRefs()
+	if err != nil {
+		return nil, err
+	}
+	logged, err := book.DatasetRefs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedKeys := map[string]bool{}
+	for _, ref := range cached {
+		cachedKeys[ref.ProfileID.Encode()+"/"+ref.Name] = true
+	}
+	loggedKeys := map[string]bool{}
+	for _, ref := range logged {
+		loggedKeys[ref.ProfileID+"/"+ref.Name] = true
+	}
+
+	var res []*DoctorFinding
+	for _, ref := range logged {
+		if !cachedKeys[ref.ProfileID+"/"+ref.Name] {
+			res = append(res, &DoctorFinding{
+				Problem:    fmt.Sprintf("%s is in the logbook but not the dataset cache", ref.Human()),
+				Suggestion: "run with --fix to rebuild the dataset cache",
+				Fixable:    true,
+			})
+		}
+	}
+	for _, ref := range cached {
+		if !loggedKeys[ref.ProfileID.Encode()+"/"+ref.Name] {
+			res = append(res, &DoctorFinding{
+				Problem:    fmt.Sprintf("%s/%s is in the dataset cache but not the logbook", ref.Peername, ref.Name),
+				Suggestion: "run with --fix to rebuild the dataset cache",
+				Fixable:    true,
+			})
+		}
+	}
+
+	if p.Fix && len(res) > 0 {
+		built, err := build.DscacheFromRepo(ctx, scope.Repo())
+		if err != nil {
+			return nil, err
+		}
+		if err := cache.Assign(built); err != nil {
+			return nil, err
+		}
+		for _, f := range res {
+			f.Fixed = true
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,94 @@
+package lib
+
+import (
+	"testing"
+)
+
+func TestDoctor(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+
+	run.MustSaveFromBody(t, "cities_ds", "testdata/cities_2/body.csv")
+
+	res, err := run.Instance.Dataset().Doctor(run.Ctx, &DoctorParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Findings) != 0 {
+		t.Fatalf("expected no findings for a healthy repo, got: %v", res.Findings)
+	}
+	if len(res.Checks) != len(doctorChecks) {
+		t.Errorf("expected %d checks to run, got: %v", len(doctorChecks), res.Checks)
+	}
+
+	book := run.Instance.logbook
+	if _, err := book.WriteDatasetInit(run.Ctx, book.Owner(), "never_saved"); err != nil {
+		t.Fatal(err)
+	}
+	lost := run.MustSaveFromBody(t, "lost_head", "testdata/cities_2/body.csv")
+	if err := run.Instance.Repo().Filesystem().Delete(run.Ctx, lost.Path); err != nil {
+		t.Fatal(err)
+	}
+
+	strandedFindings := func(res *DoctorReport) (found []*DoctorFinding) {
+		for _, f := range res.Findings {
+			if f.Check == "logbook" {
+				found = append(found, f)
+			}
+		}
+		return found
+	}
+
+	if res, err = run.Instance.Dataset().Doctor(run.Ctx, &DoctorParams{}); err != nil {
+		t.Fatal(err)
+	}
+	stranded := strandedFindings(res)
+	if len(stranded) != 2 {
+		t.Fatalf("expected 2 stranded log findings, got: %v", res.Findings)
+	}
+	for _, f := range stranded {
+		if !f.Fixable || f.Fixed {
+			t.Errorf("unexpected finding: %v", f)
+		}
+	}
+
+	// Fix alone must not remove logs
+	if res, err = run.Instance.Dataset().Doctor(run.Ctx, &DoctorParams{Fix: true}); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range strandedFindings(res) {
+		if f.Fixed {
+			t.Errorf("expected stranded log to be left in place without RemoveStrandedLogs, got: %v", f)
+		}
+	}
+	if refs, err := book.DatasetRefs(run.Ctx); err != nil {
+		t.Fatal(err)
+	} else if len(refs) != 3 {
+		t.Errorf("expected 3 dataset logs to remain, got: %v", refs)
+	}
+
+	if res, err = run.Instance.Dataset().Doctor(run.Ctx, &DoctorParams{Fix: true, RemoveStrandedLogs: true}); err != nil {
+		t.Fatal(err)
+	}
+	stranded = strandedFindings(res)
+	if len(stranded) != 2 {
+		t.Fatalf("expected 2 stranded log findings, got: %v", res.Findings)
+	}
+	for _, f := range stranded {
+		if !f.Fixed {
+			t.Errorf("expected finding to be fixed, got: %v", f)
+		}
+	}
+
+	if res, err = run.Instance.Dataset().Doctor(run.Ctx, &DoctorParams{}); err != nil {
+		t.Fatal(err)
+	}
+	if found := strandedFindings(res); len(found) != 0 {
+		t.Errorf("expected no stranded logs after fix, got: %v", found)
+	}
+	if refs, err := book.DatasetRefs(run.Ctx); err != nil {
+		t.Fatal(err)
+	} else if len(refs) != 1 {
+		t.Errorf("expected 1 dataset log to remain, got: %v", refs)
+	}
+}
@@ -35,6 +35,13 @@ func NewHTTPRequestHandler(inst *Instance, libMethod string) http.HandlerFunc {
 			return
 		}
 
+		if c, ok := p.(commandLineOnlyParams); ok {
+			if name := c.commandLineOnlyParam(); name != "" {
+				apiutil.WriteErrResponse(w, http.StatusBadRequest, fmt.Errorf("%s can only be used from the command line", name))
+				return
+			}
+		}
+
 		source := SourceFromRequest(r)
 		res, cursor, err := inst.WithSource(source).Dispatch(r.Context(), libMethod, p)
 		if err != nil {
@@ -58,6 +65,12 @@ func NewHTTPRequestHandler(inst *Instance, libMethod string) http.HandlerFunc {
 	}
 }
 
+// commandLineOnlyParams is implemented by params with fields that must not be
+// set by HTTP requests
+type commandLineOnlyParams interface {
+	commandLineOnlyParam() string
+}
+
 // SourceFromRequest retrieves from the http request the source for resolving refs
 func SourceFromRequest(r *http.Request) string {
 	return r.Header.Get(qhttp.SourceResolver)
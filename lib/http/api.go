@@ -40,6 +40,8 @@ const (
 	AEDiff APIEndpoint = "/diff"
 	// AEChanges is an endpoint for generating dataset change reports
 	AEChanges APIEndpoint = "/changes"
+	// AEDiffPatch is an endpoint for generating patches between dataset versions
+	AEDiffPatch APIEndpoint = "/diff/patch"
 
 	// auth endpoints
 
@@ -50,6 +52,12 @@ const (
 
 	// AEApply invokes a transform apply
 	AEApply APIEndpoint = "/auto/apply"
+	// AEApplyBatch invokes a transform on every dataset matching a pattern
+	AEApplyBatch APIEndpoint = "/auto/applybatch"
+	// AEVerifyTransform replays the transform of a dataset version
+	AEVerifyTransform APIEndpoint = "/auto/verify"
+	// AEListTransforms lists datasets with transforms & their last run status
+	AEListTransforms APIEndpoint = "/auto/transforms"
 	// AEDeploy creates, updates, or deploys a workflow
 	AEDeploy APIEndpoint = "/auto/deploy"
 	// AERun manually runs a workflow
@@ -69,10 +77,31 @@ const (
 
 	// AEGet is an endpoint for fetch individual dataset components
 	AEGet APIEndpoint = "/ds/get"
+	// AEBodyDelta lists body rows that changed since an earlier dataset version
+	AEBodyDelta APIEndpoint = "/ds/bodydelta"
+	// AEDependents lists local datasets with transforms that load a dataset
+	AEDependents APIEndpoint = "/ds/dependents"
 	// AEActivity is an endpoint that returns a dataset activity list
 	AEActivity APIEndpoint = "/ds/activity"
 	// AERename is an endpoint for renaming datasets
 	AERename APIEndpoint = "/ds/rename"
+	// AEAdopt copies a dataset into a new history owned by the active profile
+	AEAdopt APIEndpoint = "/ds/adopt"
+	// AEMerge combines the bodies of two datasets into a new dataset
+	AEMerge APIEndpoint = "/ds/merge"
+	// AEDuplicates reports duplicate rows of a dataset
+	AEDuplicates APIEndpoint = "/ds/duplicates"
+	// AERemoveDuplicates saves a version of a dataset without duplicate rows
+	AERemoveDuplicates APIEndpoint = "/ds/removeduplicates"
+	// AERenameColumn is an endpoint for renaming a column of a dataset
+	AERenameColumn APIEndpoint = "/ds/renamecolumn"
+	// AEDescribeColumn is an endpoint for setting the metadata of a dataset
+	// column
+	AEDescribeColumn APIEndpoint = "/ds/describecolumn"
+	// AESetMeta is an endpoint for setting a single field of a dataset's meta
+	AESetMeta APIEndpoint = "/ds/setmeta"
+	// AEApplyPatch is an endpoint for applying a patch to a dataset
+	AEApplyPatch APIEndpoint = "/ds/applypatch"
 	// AESave is an endpoint for saving a dataset
 	AESave APIEndpoint = "/ds/save"
 	// AEPull facilittates dataset pull requests from a remote
@@ -83,6 +112,8 @@ const (
 	AERender APIEndpoint = "/ds/render"
 	// AERemove exposes the dataset remove mechanics
 	AERemove APIEndpoint = "/ds/remove"
+	// AESquash compacts the history of a dataset
+	AESquash APIEndpoint = "/ds/squash"
 	// AEValidate is an endpoint for validating datasets
 	AEValidate APIEndpoint = "/ds/validate"
 	// AEManifest generates a manifest for a dataset path
@@ -91,9 +122,24 @@ const (
 	AEManifestMissing APIEndpoint = "/ds/manifest/missing"
 	// AEDAGInfo generates a dag.Info for a dataset path
 	AEDAGInfo APIEndpoint = "/ds/daginfo"
+	// AEStorageInfo estimates the storage footprint of a dataset
+	AEStorageInfo APIEndpoint = "/ds/storageinfo"
+	// AEFingerprint hashes the content of a dataset
+	AEFingerprint APIEndpoint = "/ds/fingerprint"
+	// AEComponents lists the components present in a dataset version
+	AEComponents APIEndpoint = "/ds/components"
+	// AEQuality flags potential data quality problems in a dataset
+	AEQuality APIEndpoint = "/ds/quality"
 	// AEWhatChanged gets what changed at a specific version in history
 	AEWhatChanged APIEndpoint = "/ds/whatchanged"
 
+	// operation endpoints
+
+	// AEListOps lists operations running in the background
+	AEListOps APIEndpoint = "/ops/list"
+	// AECancelOp cancels an operation running in the background
+	AECancelOp APIEndpoint = "/ops/cancel"
+
 	// peer endpoints
 
 	// AEPeer fetches a specific peer
@@ -124,6 +170,10 @@ const (
 	AEPreview APIEndpoint = "/remote/preview"
 	// AERemoteRemove removes a dataset from a given remote
 	AERemoteRemove APIEndpoint = "/remote/remove"
+	// AELocate lists the remotes a dataset is available from
+	AELocate APIEndpoint = "/remote/locate"
+	// AERemoteDiff compares the versions of a dataset two remotes have
+	AERemoteDiff APIEndpoint = "/remote/diff"
 	// AERegistryNew creates a new user on the registry
 	AERegistryNew APIEndpoint = "/remote/registry/profile/new"
 	// AERegistryProve links an the current peer with an existing
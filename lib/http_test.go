@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -84,3 +86,21 @@ func TestDecodeParams(t *testing.T) {
 		t.Fatalf("error mismatch, expected %q, got %q", params.ErrListParamsNotEmpty, err)
 	}
 }
+
+func TestHTTPRequestHandlerCommandLineOnlyParams(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+
+	body := `{"ref":"me/orders","fromDB":"postgres://localhost/shop","dbQuery":"SELECT * FROM orders"}`
+	r := httptest.NewRequest(http.MethodPost, "/ds/save", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	NewHTTPRequestHandler(run.Instance, "dataset.save")(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status code mismatch. want: %d, got: %d", http.StatusBadRequest, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "fromDB can only be used from the command line") {
+		t.Errorf("expected response to reject fromDB, got: %s", w.Body.String())
+	}
+}
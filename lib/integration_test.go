@@ -3,9 +3,12 @@ package lib
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/ioes"
 	"github.com/qri-io/qri/auth/key"
@@ -13,6 +16,8 @@ import (
 	"github.com/qri-io/qri/config"
 	"github.com/qri-io/qri/dsref"
 	dsrefspec "github.com/qri-io/qri/dsref/spec"
+	"github.com/qri-io/qri/event"
+	"github.com/qri-io/qri/logbook"
 	"github.com/qri-io/qri/registry"
 	"github.com/qri-io/qri/registry/regserver"
 	"github.com/qri-io/qri/remote"
@@ -94,6 +99,187 @@ func TestTwoActorRegistryIntegration(t *testing.T) {
 	)
 }
 
+func TestMetadataOnlyPull(t *testing.T) {
+	tr := NewNetworkIntegrationTestRunner(t, "integration_metadata_only_pull")
+	defer tr.Cleanup()
+
+	nasim := tr.InitNasim(t)
+	ref := InitWorldBankDataset(tr.Ctx, t, nasim)
+	PushToRegistry(tr.Ctx, t, nasim, ref.Alias())
+
+	hinshun := tr.InitHinshun(t)
+	res, err := hinshun.WithSource("network").Dataset().Pull(tr.Ctx, &PullParams{MetadataOnly: true, Ref: ref.Alias()})
+	if err != nil {
+		t.Fatalf("pulling metadata: %s", err)
+	}
+	fs := hinshun.Repo().Filesystem()
+	if has, _ := fs.Has(tr.Ctx, res.BodyPath); has {
+		t.Errorf("expected metadata-only pull to skip the body")
+	}
+
+	got, err := hinshun.WithSource("local").Dataset().Get(tr.Ctx, &GetParams{Ref: ref.Alias(), Selector: "meta"})
+	if err != nil {
+		t.Fatalf("getting meta of headless version: %s", err)
+	}
+	if meta, ok := got.Value.(*dataset.Meta); !ok || meta.Title == "" {
+		t.Errorf("expected meta with a title. got: %#v", got.Value)
+	}
+	if has, _ := fs.Has(tr.Ctx, res.BodyPath); has {
+		t.Errorf("expected getting meta not to pull the body")
+	}
+
+	if _, err = hinshun.WithSource("local").Dataset().Get(tr.Ctx, &GetParams{Ref: ref.Alias(), Selector: "body", All: true}); err != nil {
+		t.Fatalf("getting body of headless version: %s", err)
+	}
+	if has, _ := fs.Has(tr.Ctx, res.BodyPath); !has {
+		t.Errorf("expected getting the body to pull it")
+	}
+}
+
+func TestAutoPushOnSave(t *testing.T) {
+	tr := NewNetworkIntegrationTestRunner(t, "integration_auto_push_on_save")
+	defer tr.Cleanup()
+
+	nasim := tr.InitNasim(t)
+	nasim.cfg.Repo.AutoPushOnSave = []string{"registry", "missing"}
+
+	pushed := make(chan event.RemoteEvent, 2)
+	nasim.Bus().SubscribeTypes(func(_ context.Context, e event.Event) error {
+		pushed <- e.Payload.(event.RemoteEvent)
+		return nil
+	}, event.ETDatasetAutoPushCompleted)
+
+	ref := InitWorldBankDataset(tr.Ctx, t, nasim)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case evt := <-pushed:
+			if evt.Ref.Path != ref.Path {
+				t.Errorf("expected auto push of %q, got %q", ref.Path, evt.Ref.Path)
+			}
+			if evt.RemoteAddr == tr.RegistryHTTPServer.URL {
+				if evt.Error != nil {
+					t.Errorf("unexpected registry push error: %s", evt.Error)
+				}
+			} else if expect := `remote name "missing" not found`; evt.Error == nil || evt.Error.Error() != expect {
+				t.Errorf("expected error %q pushing to a missing remote, got: %v", expect, evt.Error)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for auto push")
+		}
+	}
+
+	if err := AssertLogsEqual(nasim, tr.RegistryInst, ref); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	tr := NewNetworkIntegrationTestRunner(t, "integration_locate")
+	defer tr.Cleanup()
+
+	nasim := tr.InitNasim(t)
+	ref := InitWorldBankDataset(tr.Ctx, t, nasim)
+	PushToRegistry(tr.Ctx, t, nasim, ref.Alias())
+
+	// a server that never answers
+	stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer stalled.Close()
+
+	hinshun := tr.InitHinshun(t)
+	hinshun.cfg.Remotes = &config.Remotes{
+		"stalled":  stalled.URL,
+		"the_main": tr.RegistryHTTPServer.URL,
+	}
+
+	res, err := hinshun.Remote().Locate(tr.Ctx, &LocateParams{
+		Ref:      ref.Alias(),
+		Registry: true,
+		Timeout:  200 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []Location{
+		{Remote: "stalled", Address: stalled.URL, Error: "no response after 200ms"},
+		{Remote: "the_main", Address: tr.RegistryHTTPServer.URL, Available: true, Path: ref.Path},
+		{Remote: "registry", Address: tr.RegistryHTTPServer.URL, Available: true, Path: ref.Path},
+	}
+	if diff := cmp.Diff(expect, res); diff != "" {
+		t.Errorf("locations mismatch (-want +got):\n%s", diff)
+	}
+
+	res, err = hinshun.Remote().Locate(tr.Ctx, &LocateParams{Ref: "nasim/not_a_dataset", Timeout: 200 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 || res[1].Available || res[1].Error != "" {
+		t.Errorf("expected missing dataset to be unavailable without an error, got: %#v", res)
+	}
+}
+
+func TestRemoteDiff(t *testing.T) {
+	tr := NewNetworkIntegrationTestRunner(t, "integration_remote_diff")
+	defer tr.Cleanup()
+
+	nasim := tr.InitNasim(t)
+	ref := InitWorldBankDataset(tr.Ctx, t, nasim)
+	PushToRegistry(tr.Ctx, t, nasim, ref.Alias())
+
+	// a server that never answers
+	stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer stalled.Close()
+	// a server without the dataset
+	empty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(logbook.ErrNotFound.Error()))
+	}))
+	defer empty.Close()
+
+	hinshun := tr.InitHinshun(t)
+	hinshun.cfg.Remotes = &config.Remotes{
+		"the_main": tr.RegistryHTTPServer.URL,
+		"mirror":   tr.RegistryHTTPServer.URL,
+		"stalled":  stalled.URL,
+		"empty":    empty.URL,
+	}
+
+	diff := func(left, right string) *RemoteDiff {
+		t.Helper()
+		res, err := hinshun.Remote().Diff(tr.Ctx, &RemoteDiffParams{Ref: ref.Alias(), Left: left, Right: right, Timeout: 200 * time.Millisecond})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	res := diff("the_main", "mirror")
+	if !res.InSync() || len(res.Left.Versions) != 1 {
+		t.Errorf("expected remotes with the same versions to be in sync, got: %#v", res)
+	}
+
+	res = diff("the_main", "empty")
+	if res.InSync() || len(res.OnlyLeft) != 1 || res.OnlyLeft[0].Path != ref.Path || len(res.OnlyRight) != 0 {
+		t.Errorf("expected the main remote to have a version the empty remote lacks, got: %#v", res)
+	}
+
+	res = diff("the_main", "stalled")
+	if expect := "no response after 200ms"; res.Right.Error != expect {
+		t.Errorf("unreachable remote error mismatch. want: %q, got: %q", expect, res.Right.Error)
+	}
+	if res.Reachable() || res.OnlyLeft != nil || res.OnlyRight != nil {
+		t.Errorf("expected versions not to be compared when a remote is unreachable, got: %#v", res)
+	}
+
+	if _, err := hinshun.Remote().Diff(tr.Ctx, &RemoteDiffParams{Ref: ref.Alias(), Left: "the_main", Right: "unknown"}); err == nil {
+		t.Errorf("expected unknown remote to error")
+	}
+}
+
 func TestReferencePulling(t *testing.T) {
 	tr := NewNetworkIntegrationTestRunner(t, "integration_reference_pulling")
 	defer tr.Cleanup()
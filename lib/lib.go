@@ -46,6 +46,7 @@ import (
 	"github.com/qri-io/qri/repo"
 	"github.com/qri-io/qri/repo/buildrepo"
 	"github.com/qri-io/qri/stats"
+	"github.com/qri-io/qri/transform"
 )
 
 var (
@@ -55,6 +56,10 @@ var (
 	ErrNoRepo = errors.New("no repo exists")
 
 	log = golog.Logger("lib")
+
+	// AutoPushShutdownTimeout is how long instance shutdown waits for pushes
+	// started by repo.autoPushOnSave to finish before canceling them
+	AutoPushShutdownTimeout = time.Second * 30
 )
 
 // Methods is a related set of library functions
@@ -85,12 +90,17 @@ type InstanceOptions struct {
 	remoteClientConstructor remote.ClientConstructor
 	logbook                 *logbook.Book
 	keyStore                key.Store
+	signer                  key.Signer
 	profiles                profile.Store
 	bus                     event.Bus
 	collectionSet           collection.Set
 	tokenProvider           token.Provider
 	logAll                  bool
+	defaultSource           string
 	automationOptions       *automation.OrchestratorOptions
+	secretProviders         map[string]transform.SecretProvider
+	refResolversFirst       []dsref.Resolver
+	refResolversLast        []dsref.Resolver
 
 	remoteMockClient bool
 	// use OptRemoteOptions to set this
@@ -225,6 +235,62 @@ func OptSetLogAll(logAll bool) Option {
 	}
 }
 
+// OptDefaultSource sets the source used to resolve references for methods
+// that don't declare a default source of their own, overriding the
+// repo.defaultSource configuration value
+func OptDefaultSource(source string) Option {
+	return func(o *InstanceOptions) error {
+		o.defaultSource = source
+		return nil
+	}
+}
+
+// OptSecretProvider registers a provider transform scripts can read secrets
+// from by scheme, eg: qri.get_secret("vault://path/to/key") reads "path/to/key"
+// from the provider registered with the scheme "vault"
+func OptSecretProvider(scheme string, p transform.SecretProvider) Option {
+	return func(o *InstanceOptions) error {
+		if o.secretProviders == nil {
+			o.secretProviders = map[string]transform.SecretProvider{}
+		}
+		o.secretProviders[scheme] = p
+		return nil
+	}
+}
+
+// RefResolverOrder places a custom reference resolver relative to the
+// default resolvers
+type RefResolverOrder int
+
+const (
+	// ResolveBeforeDefault consults a custom resolver before the dscache,
+	// repo & registry
+	ResolveBeforeDefault RefResolverOrder = iota
+	// ResolveAfterDefault consults a custom resolver only when no default
+	// resolver finds the reference
+	ResolveAfterDefault
+)
+
+// OptRefResolver registers a custom resolver consulted when resolving dataset
+// references with the default & "local" sources, for example a service that
+// maps organizational names to dataset paths. Resolvers must return
+// dsref.ErrRefNotFound for references they don't know, passing resolution on
+// to the next resolver. Multiple resolvers with the same order are consulted
+// in the order they're registered
+func OptRefResolver(r dsref.Resolver, order RefResolverOrder) Option {
+	return func(o *InstanceOptions) error {
+		if r == nil {
+			return fmt.Errorf("ref resolver cannot be nil")
+		}
+		if order == ResolveAfterDefault {
+			o.refResolversLast = append(o.refResolversLast, r)
+		} else {
+			o.refResolversFirst = append(o.refResolversFirst, r)
+		}
+		return nil
+	}
+}
+
 // OptRemoteClientConstructor provides a constructor function for creating a
 // remote client, which will be used when creating the instance. Use this to
 // override the remoteClient implementation used by instance
@@ -337,6 +403,25 @@ func OptKeyStore(keys key.Store) Option {
 	}
 }
 
+// OptSigner signs commits & logs on behalf of the owner profile in place of
+// its private key, like when signing requests need to be routed through a
+// hardware security module for auditing. The signer must sign with the
+// owner's key, or signatures won't verify.
+//
+// OptSigner doesn't remove the need for a private key: the configured profile
+// must still include one, because the logbook is encrypted at rest with it &
+// access tokens are signed with it. keys that can't be loaded into the
+// process at all aren't supported yet
+func OptSigner(s key.Signer) Option {
+	return func(o *InstanceOptions) error {
+		if s == nil {
+			return fmt.Errorf("signer cannot be nil")
+		}
+		o.signer = s
+		return nil
+	}
+}
+
 // OptBus overrides the configured `event.Bus` with a manually provided one
 func OptBus(bus event.Bus) Option {
 	return func(o *InstanceOptions) error {
@@ -449,6 +534,11 @@ func NewInstance(ctx context.Context, repoPath string, opts ...Option) (qri *Ins
 		profiles:      o.profiles,
 		bus:           o.bus,
 		appCtx:        ctx,
+		defaultSrc:    o.defaultSource,
+
+		secretProviders:   o.secretProviders,
+		refResolversFirst: o.refResolversFirst,
+		refResolversLast:  o.refResolversLast,
 	}
 	qri = inst
 
@@ -532,6 +622,9 @@ func NewInstance(ctx context.Context, repoPath string, opts ...Option) (qri *Ins
 	}
 
 	pro := inst.profiles.Owner(ctx)
+	if o.signer != nil {
+		pro.Signer = o.signer
+	}
 
 	if inst.logbook == nil {
 		inst.logbook, err = newLogbook(inst.qfs, cfg, inst.bus, pro, inst.repoPath)
@@ -663,6 +756,9 @@ func NewInstance(ctx context.Context, repoPath string, opts ...Option) (qri *Ins
 	if err != nil {
 		return nil, err
 	}
+	inst.ops = newOpRegistry(inst.bus, func(runID string) {
+		inst.automation.CancelRun(inst.appCtx, runID)
+	})
 
 	go inst.waitForAllDone()
 	go func() {
@@ -812,6 +908,9 @@ func NewInstanceFromConfigAndNodeAndBusAndOrchestratorOpts(ctx context.Context,
 		cancel()
 		panic(err)
 	}
+	inst.ops = newOpRegistry(inst.bus, func(runID string) {
+		inst.automation.CancelRun(inst.appCtx, runID)
+	})
 
 	inst.remoteClient, err = remote.NewClient(ctx, node, inst.bus)
 	if err != nil {
@@ -875,6 +974,13 @@ type Instance struct {
 	keystore key.Store
 
 	remoteOptsFuncs []remote.OptionsFunc
+	// source to use when neither the caller nor the method picks one
+	defaultSrc string
+	// providers transform scripts read secrets from, keyed by scheme
+	secretProviders map[string]transform.SecretProvider
+	// custom resolvers consulted before & after the default resolvers
+	refResolversFirst []dsref.Resolver
+	refResolversLast  []dsref.Resolver
 
 	http *qhttp.Client
 
@@ -882,6 +988,13 @@ type Instance struct {
 	doneCh    chan struct{}
 	doneErr   error
 	releasers sync.WaitGroup
+	// autoPushes tracks background pushes started by saves, shutdown waits for
+	// them to finish
+	autoPushes autoPushGroup
+	// ops tracks operations running in the background
+	ops *opRegistry
+	// fingerprints caches the results of DatasetMethods.Fingerprint
+	fingerprints fingerprintCache
 }
 
 // ErrP2PDisabled error indicates p2p connectivity is disabled by configuration
@@ -997,6 +1110,11 @@ func (inst *Instance) Log() LogMethods {
 	return LogMethods{d: inst}
 }
 
+// Ops returns the OpsMethods that Instance has registered
+func (inst *Instance) Ops() OpsMethods {
+	return OpsMethods{d: inst}
+}
+
 // Peer returns the PeerMethods that Instance has registered
 func (inst *Instance) Peer() PeerMethods {
 	return PeerMethods{d: inst}
@@ -1083,6 +1201,12 @@ func (inst *Instance) GetConfig() *config.Config {
 // timeout
 func (inst *Instance) Shutdown() <-chan error {
 	errCh := make(chan error)
+	// let pushes configured to run after each save finish before their context
+	// is cancelled, so a save from the command line is still backed up. pushes
+	// that take longer than AutoPushShutdownTimeout are canceled
+	if !inst.autoPushes.wait(AutoPushShutdownTimeout) {
+		log.Warnw("canceled pushes still running at shutdown", "timeout", AutoPushShutdownTimeout)
+	}
 	// NOTE: the remote client may have gotten its context from the `Connect` func
 	// not the context that the instance itself was built around.
 	// The instance must clean up the remoteClient, since it cannot rely on the
@@ -2,6 +2,7 @@ package lib
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -365,6 +366,62 @@ func TestNewInstanceWithCollectionOption(t *testing.T) {
 	}
 }
 
+// countingSigner signs with a private key, counting calls
+type countingSigner struct {
+	pk    crypto.PrivKey
+	calls int
+	last  []byte
+}
+
+func (s *countingSigner) Sign(data []byte) (sig []byte, err error) {
+	s.calls++
+	s.last, err = s.pk.Sign(data)
+	return s.last, err
+}
+
+func TestNewInstanceWithSigner(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tr, err := repotest.NewTempRepo("test_signer", "TestNewInstanceWithSigner", repotest.NewTestCrypto())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Delete()
+
+	pk, err := key.DecodeB64PrivKey(tr.GetConfig().Profile.PrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := &countingSigner{pk: pk}
+	inst, err := NewInstance(ctx, tr.QriPath, OptSigner(signer))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds, err := inst.Dataset().Save(ctx, &SaveParams{Ref: "me/cities", BodyPath: "testdata/cities_2/body.csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer.calls != 1 {
+		t.Errorf("expected saving to sign once, got %d calls", signer.calls)
+	}
+	if expect := base64.StdEncoding.EncodeToString(signer.last); ds.Commit.Signature != expect {
+		t.Errorf("expected commit signature to come from the signer. want: %q, got: %q", expect, ds.Commit.Signature)
+	}
+
+	if _, err := inst.Dataset().LogBytes(ctx, &LogBytesParams{Ref: "me/cities"}); err != nil {
+		t.Fatal(err)
+	}
+	if signer.calls != 2 {
+		t.Errorf("expected getting log bytes to sign, got %d calls", signer.calls)
+	}
+
+	if _, err := NewInstance(ctx, tr.QriPath, OptSigner(nil)); err == nil {
+		t.Error("expected a nil signer to error")
+	}
+}
+
 // NewMemTestInstance creates an in-memory instance
 // TODO(b5): currently "NewInstance" hard-requires a repo-path, even if we can
 // provide a configuration that specifies entirely in-memory stores. We should
@@ -108,10 +108,49 @@ func (d *datasetLoader) loadLocalDataset(ctx context.Context, ref dsref.Ref) (*d
 	ds.Peername = ref.Username
 	ds.ID = ref.InitID
 
-	if err = base.OpenDataset(ctx, d.inst.repo.Filesystem(), ds); err != nil {
+	fs := d.inst.repo.Filesystem()
+	if bodyMissing(ctx, fs, ds) {
+		// headless versions have no local body, it's left unopened until needed
+		return ds, openHeadlessDataset(ctx, fs, ds)
+	}
+
+	if err = base.OpenDataset(ctx, fs, ds); err != nil {
 		log.Debugf("Get dataset, base.OpenDataset failed, error: %s", err)
 		return nil, err
 	}
 
 	return ds, nil
 }
+
+// pinnedLoader loads datasets at the versions a transform recorded loading,
+// so replaying a transform reads the same inputs as the run that saved it.
+// pins maps "username/name" aliases to path-qualified references, an empty
+// reference pins a dataset that had no history
+type pinnedLoader struct {
+	loader    dsref.Loader
+	userOwner string
+	pins      map[string]string
+}
+
+// LoadDataset implements the dsref.Loader interface
+func (l *pinnedLoader) LoadDataset(ctx context.Context, refstr string) (*dataset.Dataset, error) {
+	ref, err := dsref.Parse(refstr)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a valid dataset reference: %w", refstr, err)
+	}
+	if ref.Path != "" {
+		return l.loader.LoadDataset(ctx, refstr)
+	}
+	if ref.Username == "me" {
+		ref.Username = l.userOwner
+	}
+
+	pinned, ok := l.pins[ref.Alias()]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnrecordedInput, ref.Alias())
+	}
+	if pinned == "" {
+		return nil, qerr.New(dsref.ErrNoHistory, fmt.Sprintf("can't load dataset %q, it has no saved versions", ref.Human()))
+	}
+	return l.loader.LoadDataset(ctx, pinned)
+}
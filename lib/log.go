@@ -2,9 +2,11 @@ package lib
 
 import (
 	"context"
+	"fmt"
 
 	qhttp "github.com/qri-io/qri/lib/http"
 	"github.com/qri-io/qri/logbook"
+	"github.com/qri-io/qri/repo"
 )
 
 // LogMethods extends a lib.Instance with business logic for working with lists
@@ -21,9 +23,11 @@ func (m LogMethods) Name() string {
 // Attributes defines attributes for each method
 func (m LogMethods) Attributes() map[string]AttributeSet {
 	return map[string]AttributeSet{
-		"log":            {Endpoint: qhttp.DenyHTTP},
-		"rawlogbook":     {Endpoint: qhttp.DenyHTTP},
-		"logbooksummary": {Endpoint: qhttp.DenyHTTP},
+		"log":            {Endpoint: qhttp.DenyHTTP, ReadOnly: true},
+		"rawlogbook":     {Endpoint: qhttp.DenyHTTP, ReadOnly: true},
+		"logbooksummary": {Endpoint: qhttp.DenyHTTP, ReadOnly: true},
+		"logbookgraph":   {Endpoint: qhttp.DenyHTTP, ReadOnly: true},
+		"logbookdoctor":  {Endpoint: qhttp.DenyHTTP},
 	}
 }
 
@@ -74,6 +78,43 @@ func (m LogMethods) LogbookSummary(ctx context.Context, p *struct{}) (*string, e
 	return nil, dispatchReturnError(got, err)
 }
 
+// LogbookGraphParams encapsulates parameters for the LogbookGraph method
+type LogbookGraphParams struct {
+	// String value of a dataset reference
+	Ref string
+	// Format of the graph. only "dot" is supported
+	Format string
+}
+
+// LogbookGraph renders the oplog of a dataset and all its branches as a graph
+// description, for visualizing synced histories
+func (m LogMethods) LogbookGraph(ctx context.Context, p *LogbookGraphParams) (*string, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "logbookgraph"), p)
+	if res, ok := got.(*string); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// LogbookDoctorParams encapsulates parameters for the LogbookDoctor method
+type LogbookDoctorParams struct {
+	// Fix removes stranded logs after finding them
+	Fix bool
+}
+
+// StrandedLog is a dataset log that doesn't resolve to a saved version
+type StrandedLog = logbook.StrandedLog
+
+// LogbookDoctor lists dataset logs that have no commits or a head version
+// missing from the store, removing them when Fix is set
+func (m LogMethods) LogbookDoctor(ctx context.Context, p *LogbookDoctorParams) ([]StrandedLog, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "logbookdoctor"), p)
+	if res, ok := got.([]StrandedLog); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
 // logImpl holds the method implementations for LogMethods
 type logImpl struct{}
 
@@ -113,3 +154,53 @@ func (logImpl) LogbookSummary(scope scope, p *struct{}) (*string, error) {
 	res = scope.Logbook().SummaryString(scope.Context())
 	return &res, nil
 }
+
+// LogbookGraph renders the oplog of a dataset and all its branches as a graph
+func (logImpl) LogbookGraph(scope scope, p *LogbookGraphParams) (*string, error) {
+	if p.Format != "" && p.Format != "dot" {
+		return nil, fmt.Errorf("unsupported graph format %q. only dot is supported", p.Format)
+	}
+
+	ref, _, err := scope.ParseAndResolveRef(scope.Context(), p.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	lg, err := scope.Logbook().UserDatasetBranchesLog(scope.Context(), ref.InitID)
+	if err != nil {
+		return nil, err
+	}
+	res := logbook.NewPlainLog(lg).DOT()
+	return &res, nil
+}
+
+// LogbookDoctor lists dataset logs that have no commits or a head version
+// missing from the store, removing them when Fix is set
+func (logImpl) LogbookDoctor(scope scope, p *LogbookDoctorParams) ([]StrandedLog, error) {
+	ctx := scope.Context()
+	book := scope.Logbook()
+	res, err := book.StrandedLogs(ctx, scope.Filesystem())
+	if err != nil {
+		return nil, err
+	}
+	if !p.Fix {
+		return res, nil
+	}
+
+	if err := book.RemoveStrandedLogs(ctx, res); err != nil {
+		return nil, err
+	}
+	// stranded refs may also have made it into the refstore
+	r := scope.Repo()
+	for _, s := range res {
+		ref := s.Ref
+		ref.Path = ""
+		if _, err := repo.GetVersionInfoShim(r, ref); err != nil {
+			continue
+		}
+		if _, err := repo.DeleteVersionInfoShim(ctx, r, ref); err != nil {
+			log.Debugw("removing stranded reference from refstore", "ref", ref, "err", err)
+		}
+	}
+	return res, nil
+}
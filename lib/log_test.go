@@ -2,6 +2,7 @@ package lib
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	testcfg "github.com/qri-io/qri/config/test"
 	"github.com/qri-io/qri/dsref"
 	"github.com/qri-io/qri/event"
+	"github.com/qri-io/qri/logbook"
 	"github.com/qri-io/qri/p2p"
 	reporef "github.com/qri-io/qri/repo/ref"
 	testrepo "github.com/qri-io/qri/repo/test"
@@ -38,7 +40,6 @@ func TestHistoryRequestsLog(t *testing.T) {
 		ds := r.Dataset
 		items[i] = reporef.ConvertToVersionInfo(&r)
 		items[i].MetaTitle = ""
-		items[i].BodyRows = 0
 		items[i].NumErrors = 0
 		items[i].BodyFormat = ""
 		if ds != nil && ds.Commit != nil {
@@ -140,3 +141,60 @@ func TestHistoryRequestsLogEntries(t *testing.T) {
 		t.Errorf("result mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestLogbookGraph(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+
+	run.MustSaveFromBody(t, "cities_ds", "testdata/cities_2/body.csv")
+	ds := run.MustGet(t, "me/cities_ds")
+
+	res, err := run.Instance.Log().LogbookGraph(run.Ctx, &LogbookGraphParams{Ref: "me/cities_ds", Format: "dot"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"digraph oplog {", `label="dataset\ncities_ds"`, ds.Path} {
+		if !strings.Contains(*res, want) {
+			t.Errorf("expected graph to contain %q, got:\n%s", want, *res)
+		}
+	}
+
+	if _, err := run.Instance.Log().LogbookGraph(run.Ctx, &LogbookGraphParams{Ref: "me/cities_ds", Format: "svg"}); err == nil {
+		t.Error("expected unsupported graph format to error")
+	}
+}
+
+func TestLogbookDoctor(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+
+	run.MustSaveFromBody(t, "cities_ds", "testdata/cities_2/body.csv")
+	book := run.Instance.logbook
+	if _, err := book.WriteDatasetInit(run.Ctx, book.Owner(), "never_saved"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := run.Instance.Log().LogbookDoctor(run.Ctx, &LogbookDoctorParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 {
+		t.Fatalf("expected 1 stranded log, got: %v", res)
+	}
+	if res[0].Ref.Name != "never_saved" || res[0].Reason != logbook.StrandedNoCommits {
+		t.Errorf("unexpected stranded log: %v", res[0])
+	}
+
+	if _, err := run.Instance.Log().LogbookDoctor(run.Ctx, &LogbookDoctorParams{Fix: true}); err != nil {
+		t.Fatal(err)
+	}
+	if res, err = run.Instance.Log().LogbookDoctor(run.Ctx, &LogbookDoctorParams{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 0 {
+		t.Errorf("expected fix to remove stranded logs, got: %v", res)
+	}
+	if _, err := run.Instance.Log().LogbookGraph(run.Ctx, &LogbookGraphParams{Ref: "me/cities_ds"}); err != nil {
+		t.Errorf("expected saved dataset to remain after fix: %s", err)
+	}
+}
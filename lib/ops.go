@@ -0,0 +1,346 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/event"
+	qhttp "github.com/qri-io/qri/lib/http"
+	"github.com/qri-io/qri/transform"
+)
+
+// OpRetention is how long a finished operation stays in the list of
+// operations before it's dropped
+var OpRetention = 30 * time.Second
+
+// ErrOpNotFound indicates an operation isn't in the list of operations
+var ErrOpNotFound = errors.New("operation not found")
+
+const (
+	// OpTypePush is a background push started by a save
+	OpTypePush = "push"
+	// OpTypeApply is a transform apply that doesn't wait for its result
+	OpTypeApply = "apply"
+	// OpTypeRun is a workflow run
+	OpTypeRun = "run"
+)
+
+const (
+	// OpStatusQueued is an operation waiting to start
+	OpStatusQueued = "queued"
+	// OpStatusRunning is an operation that has started
+	OpStatusRunning = "running"
+	// OpStatusSucceeded is an operation that finished without error
+	OpStatusSucceeded = "succeeded"
+	// OpStatusFailed is an operation that finished with an error
+	OpStatusFailed = "failed"
+	// OpStatusCanceled is an operation that was canceled before it finished
+	OpStatusCanceled = "canceled"
+)
+
+// Operation describes long-running work an instance is doing in the
+// background
+type Operation struct {
+	ID       string     `json:"id"`
+	Type     string     `json:"type"`
+	Ref      string     `json:"ref,omitempty"`
+	Status   string     `json:"status"`
+	Progress string     `json:"progress,omitempty"`
+	Started  time.Time  `json:"started"`
+	Finished *time.Time `json:"finished,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// OpsMethods lists & cancels operations an instance is running in the
+// background
+type OpsMethods struct {
+	d dispatcher
+}
+
+// Name returns the name of this method group
+func (m OpsMethods) Name() string {
+	return "ops"
+}
+
+// Attributes defines attributes for each method
+func (m OpsMethods) Attributes() map[string]AttributeSet {
+	return map[string]AttributeSet{
+		"list":   {Endpoint: qhttp.AEListOps, HTTPVerb: "POST", DefaultSource: "local", ReadOnly: true},
+		"cancel": {Endpoint: qhttp.AECancelOp, HTTPVerb: "POST", DefaultSource: "local"},
+	}
+}
+
+// ListOpsParams are parameters for listing operations
+type ListOpsParams struct {
+	// no options yet
+}
+
+// List returns running operations, and operations that finished within the
+// retention window, oldest first
+func (m OpsMethods) List(ctx context.Context, p *ListOpsParams) ([]Operation, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "list"), p)
+	if res, ok := got.([]Operation); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// CancelOpParams are parameters for canceling an operation
+type CancelOpParams struct {
+	ID string `json:"id"`
+}
+
+// Validate returns an error if CancelOpParams fields are in an invalid state
+func (p *CancelOpParams) Validate() error {
+	if p.ID == "" {
+		return fmt.Errorf("operation id required")
+	}
+	return nil
+}
+
+// Cancel stops an operation that hasn't finished
+func (m OpsMethods) Cancel(ctx context.Context, p *CancelOpParams) error {
+	_, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "cancel"), p)
+	return dispatchReturnError(nil, err)
+}
+
+// opsImpl holds the method implementations for OpsMethods
+type opsImpl struct{}
+
+// List returns operations
+func (opsImpl) List(scope scope, p *ListOpsParams) ([]Operation, error) {
+	ops := scope.inst.ops.list()
+	for i, op := range ops {
+		// workflow runs only know the dataset they're running against by id
+		if op.Ref == "" && op.initID != "" {
+			ref := dsref.Ref{InitID: op.initID}
+			if _, err := scope.ResolveReference(scope.Context(), &ref); err == nil {
+				ops[i].Ref = ref.Alias()
+			}
+		}
+	}
+	res := make([]Operation, len(ops))
+	for i, op := range ops {
+		res[i] = op.Operation
+	}
+	return res, nil
+}
+
+// Cancel stops an operation
+func (opsImpl) Cancel(scope scope, p *CancelOpParams) error {
+	return scope.inst.ops.cancel(p.ID)
+}
+
+// trackedOp is an operation & the state needed to cancel & report progress
+// on it
+type trackedOp struct {
+	Operation
+	initID    string
+	steps     int
+	stepsDone int
+	cancel    func()
+}
+
+// opRegistry tracks operations running in the background. Background pushes
+// add themselves, workflow runs & applies are tracked by listening for
+// automation & transform events
+type opRegistry struct {
+	lk  sync.Mutex
+	ops map[string]*trackedOp
+	// cancelRun stops an automation run
+	cancelRun func(runID string)
+}
+
+func newOpRegistry(bus event.Bus, cancelRun func(runID string)) *opRegistry {
+	r := &opRegistry{
+		ops:       map[string]*trackedOp{},
+		cancelRun: cancelRun,
+	}
+	bus.SubscribeTypes(r.handleEvent,
+		event.ETAutomationRunQueuePush,
+		event.ETAutomationApplyQueuePush,
+		event.ETTransformStart,
+		event.ETTransformStepStop,
+		event.ETTransformStop,
+		event.ETTransformCanceled,
+	)
+	return r
+}
+
+func (r *opRegistry) handleEvent(ctx context.Context, e event.Event) error {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	switch e.Type {
+	case event.ETAutomationRunQueuePush:
+		r.queueRun(e.SessionID, OpTypeRun)
+		return nil
+	case event.ETAutomationApplyQueuePush:
+		r.queueRun(e.SessionID, OpTypeApply)
+		return nil
+	}
+
+	// only runs & applies that went through the run queue are tracked. this
+	// includes applies a caller waits on, they're listed until they finish
+	op, ok := r.ops[e.SessionID]
+	if !ok {
+		return nil
+	}
+	if e.Type == event.ETTransformCanceled {
+		// canceled runs also stop with a failed status
+		op.finish(OpStatusCanceled, nil)
+		return nil
+	}
+	if op.Finished != nil {
+		return nil
+	}
+	switch e.Type {
+	case event.ETTransformStart:
+		if lc, ok := e.Payload.(event.TransformLifecycle); ok {
+			op.initID = lc.InitID
+			op.steps = lc.StepCount
+		}
+		op.Status = OpStatusRunning
+		op.Progress = op.progress()
+	case event.ETTransformStepStop:
+		op.stepsDone++
+		op.Progress = op.progress()
+	case event.ETTransformStop:
+		status := OpStatusFailed
+		if lc, ok := e.Payload.(event.TransformLifecycle); ok && lc.Status == transform.StatusSucceeded {
+			status = OpStatusSucceeded
+		}
+		op.finish(status, nil)
+	}
+	return nil
+}
+
+// queueRun tracks a run waiting in the automation run queue. must be called
+// while holding the lock
+func (r *opRegistry) queueRun(runID, typ string) {
+	op := r.add(runID, typ)
+	op.cancel = func() { r.cancelRun(runID) }
+}
+
+// add tracks a new operation, returning the existing operation if one is
+// already tracked with the same id. adding drops operations that finished
+// outside the retention window, so the registry doesn't grow when
+// operations are never listed. must be called while holding the lock
+func (r *opRegistry) add(id, typ string) *trackedOp {
+	if op, ok := r.ops[id]; ok {
+		return op
+	}
+	r.prune()
+	op := &trackedOp{
+		Operation: Operation{
+			ID:      id,
+			Type:    typ,
+			Status:  OpStatusQueued,
+			Started: time.Now(),
+		},
+	}
+	r.ops[id] = op
+	return op
+}
+
+// start tracks an operation that has begun
+func (r *opRegistry) start(id, typ, ref string, cancel func()) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	op := r.add(id, typ)
+	op.Ref = ref
+	op.Status = OpStatusRunning
+	op.cancel = cancel
+}
+
+// setRef records the dataset an operation works on
+func (r *opRegistry) setRef(id, typ, ref string) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	r.add(id, typ).Ref = ref
+}
+
+// done marks an operation as finished, failed if err isn't nil
+func (r *opRegistry) done(id string, err error) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	op, ok := r.ops[id]
+	if !ok || op.Finished != nil {
+		return
+	}
+	if err != nil {
+		op.finish(OpStatusFailed, err)
+		return
+	}
+	op.finish(OpStatusSucceeded, nil)
+}
+
+// cancel stops an unfinished operation
+func (r *opRegistry) cancel(id string) error {
+	r.lk.Lock()
+	op, ok := r.ops[id]
+	if !ok {
+		r.lk.Unlock()
+		return fmt.Errorf("%w: %q", ErrOpNotFound, id)
+	}
+	if op.Finished != nil {
+		r.lk.Unlock()
+		return fmt.Errorf("operation %q already %s", id, op.Status)
+	}
+	op.finish(OpStatusCanceled, nil)
+	cancel := op.cancel
+	r.lk.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	return nil
+}
+
+// list returns unfinished operations and operations that finished within the
+// retention window, dropping any others
+func (r *opRegistry) list() []trackedOp {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+	r.prune()
+	ops := make([]trackedOp, 0, len(r.ops))
+	for _, op := range r.ops {
+		ops = append(ops, *op)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].Started.Before(ops[j].Started)
+	})
+	return ops
+}
+
+// prune drops operations that finished outside the retention window. must be
+// called while holding the lock
+func (r *opRegistry) prune() {
+	cutoff := time.Now().Add(-OpRetention)
+	for id, op := range r.ops {
+		if op.Finished != nil && op.Finished.Before(cutoff) {
+			delete(r.ops, id)
+		}
+	}
+}
+
+func (op *trackedOp) finish(status string, err error) {
+	now := time.Now()
+	op.Status = status
+	op.Finished = &now
+	if err != nil {
+		op.Error = err.Error()
+	}
+}
+
+func (op *trackedOp) progress() string {
+	if op.steps == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d steps", op.stepsDone, op.steps)
+}
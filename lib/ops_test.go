@@ -0,0 +1,121 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/qri-io/qri/event"
+)
+
+func TestOpsListAndCancel(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+
+	ctx := run.Ctx
+	inst := run.Instance
+	bus := inst.bus
+
+	canceledRuns := make(chan string, 1)
+	inst.ops.cancelRun = func(runID string) { canceledRuns <- runID }
+
+	mustPublish := func(typ event.Type, id string, payload interface{}) {
+		t.Helper()
+		if err := bus.PublishID(ctx, typ, id, payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustPublish(event.ETAutomationApplyQueuePush, "apply_run", nil)
+	mustPublish(event.ETTransformStart, "apply_run", event.TransformLifecycle{RunID: "apply_run", StepCount: 3})
+	mustPublish(event.ETTransformStepStop, "apply_run", event.TransformStepLifecycle{})
+	mustPublish(event.ETAutomationRunQueuePush, "workflow_run", nil)
+
+	pushCanceled := false
+	inst.ops.start("push_op", OpTypePush, "peer/cities", func() { pushCanceled = true })
+
+	ops, err := inst.Ops().List(ctx, &ListOpsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 3 {
+		t.Fatalf("expected 3 operations, got %d: %v", len(ops), ops)
+	}
+	expect := []struct{ id, typ, status, progress string }{
+		{"apply_run", OpTypeApply, OpStatusRunning, "1/3 steps"},
+		{"workflow_run", OpTypeRun, OpStatusQueued, ""},
+		{"push_op", OpTypePush, OpStatusRunning, ""},
+	}
+	for i, e := range expect {
+		op := ops[i]
+		if op.ID != e.id || op.Type != e.typ || op.Status != e.status || op.Progress != e.progress {
+			t.Errorf("operation %d mismatch. expected %v, got: %v", i, e, op)
+		}
+	}
+
+	if err := inst.Ops().Cancel(ctx, &CancelOpParams{ID: "workflow_run"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-canceledRuns; got != "workflow_run" {
+		t.Errorf("expected run %q to be canceled, got %q", "workflow_run", got)
+	}
+	if err := inst.Ops().Cancel(ctx, &CancelOpParams{ID: "push_op"}); err != nil {
+		t.Fatal(err)
+	}
+	if !pushCanceled {
+		t.Error("expected push to be canceled")
+	}
+	if err := inst.Ops().Cancel(ctx, &CancelOpParams{ID: "push_op"}); err == nil {
+		t.Error("expected canceling a finished operation to fail")
+	}
+	if err := inst.Ops().Cancel(ctx, &CancelOpParams{ID: "unknown"}); !errors.Is(err, ErrOpNotFound) {
+		t.Errorf("expected error %q, got: %v", ErrOpNotFound, err)
+	}
+
+	mustPublish(event.ETTransformStop, "apply_run", event.TransformLifecycle{RunID: "apply_run", Status: "succeeded"})
+	ops, err = inst.Ops().List(ctx, &ListOpsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, op := range ops {
+		expectStatus := OpStatusCanceled
+		if op.ID == "apply_run" {
+			expectStatus = OpStatusSucceeded
+		}
+		if op.Status != expectStatus || op.Finished == nil {
+			t.Errorf("expected operation %q to finish with status %q, got %q", op.ID, expectStatus, op.Status)
+		}
+	}
+
+	// finished operations are dropped after the retention window
+	prevRetention := OpRetention
+	defer func() { OpRetention = prevRetention }()
+	OpRetention = time.Millisecond
+	time.Sleep(5 * time.Millisecond)
+	if ops, err = inst.Ops().List(context.Background(), &ListOpsParams{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 0 {
+		t.Errorf("expected finished operations to be dropped, got: %v", ops)
+	}
+}
+
+func TestOpRegistryPrunesOnAdd(t *testing.T) {
+	prevRetention := OpRetention
+	defer func() { OpRetention = prevRetention }()
+	OpRetention = time.Millisecond
+
+	r := &opRegistry{ops: map[string]*trackedOp{}}
+	r.start("first", OpTypePush, "peer/cities", nil)
+	r.done("first", nil)
+	time.Sleep(5 * time.Millisecond)
+
+	// operations that are never listed are still dropped once they finish
+	r.start("second", OpTypePush, "peer/cities", nil)
+	if _, ok := r.ops["first"]; ok {
+		t.Error("expected finished operation to be dropped when another is added")
+	}
+	if len(r.ops) != 1 {
+		t.Errorf("expected 1 tracked operation, got %d", len(r.ops))
+	}
+}
@@ -0,0 +1,411 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/base"
+)
+
+// PatchFormat identifies the patch document format & version
+const PatchFormat = "pt:0"
+
+const (
+	// PatchRowAdd adds a row to the body
+	PatchRowAdd = "add"
+	// PatchRowUpdate replaces a row of the body
+	PatchRowUpdate = "update"
+	// PatchRowDelete removes a row from the body
+	PatchRowDelete = "delete"
+)
+
+// Patch is a serializable description of the changes between two dataset
+// versions that can be applied to another dataset. Meta & structure changes
+// are recorded per top-level field, body changes per row
+type Patch struct {
+	Qri string `json:"qri"`
+	// Base is the path of the version the patch was made against
+	Base string `json:"base"`
+	// Target is the path of the version the patch was made from
+	Target    string                  `json:"target"`
+	Meta      map[string]*FieldChange `json:"meta,omitempty"`
+	Structure map[string]*FieldChange `json:"structure,omitempty"`
+	Body      *BodyPatch              `json:"body,omitempty"`
+}
+
+// FieldChange sets a component field. From is the value in the base version,
+// null when the field was added. To is null when the field was removed
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// BodyPatch lists the row operations that change a body
+type BodyPatch struct {
+	// Key is the column rows are matched by. Without a key rows are matched by
+	// position, and row keys are row indexes
+	Key  string       `json:"key,omitempty"`
+	Rows []*RowChange `json:"rows"`
+}
+
+// RowChange is a single row operation. From is the row in the base version,
+// set for updates & deletes. Row is the new row, set for adds & updates
+type RowChange struct {
+	Op   string      `json:"op"`
+	Key  string      `json:"key"`
+	From interface{} `json:"from,omitempty"`
+	Row  interface{} `json:"row,omitempty"`
+}
+
+// derivedStructureFields are structure fields calculated on save, which a
+// patch never sets
+var derivedStructureFields = []string{"checksum", "depth", "entries", "errCount", "length"}
+
+// makePatch describes the changes from prev to next. Bodies are compared when
+// both datasets have an opened body file
+func makePatch(prev, next *dataset.Dataset, key string) (*Patch, error) {
+	p := &Patch{
+		Qri:    PatchFormat,
+		Base:   prev.Path,
+		Target: next.Path,
+	}
+
+	prevMeta, err := componentFields(prev.Meta)
+	if err != nil {
+		return nil, err
+	}
+	nextMeta, err := componentFields(next.Meta)
+	if err != nil {
+		return nil, err
+	}
+	p.Meta = fieldChanges(prevMeta, nextMeta)
+
+	prevSt, err := componentFields(prev.Structure, derivedStructureFields...)
+	if err != nil {
+		return nil, err
+	}
+	nextSt, err := componentFields(next.Structure, derivedStructureFields...)
+	if err != nil {
+		return nil, err
+	}
+	p.Structure = fieldChanges(prevSt, nextSt)
+
+	if prev.BodyFile() == nil || next.BodyFile() == nil {
+		return p, nil
+	}
+	prevRows, prevKeys, err := bodyDeltaRows(prev, key)
+	if err != nil {
+		return nil, fmt.Errorf("base version: %w", err)
+	}
+	nextRows, nextKeys, err := bodyDeltaRows(next, key)
+	if err != nil {
+		return nil, err
+	}
+
+	bp := &BodyPatch{Key: key, Rows: []*RowChange{}}
+	prevByKey := make(map[string]interface{}, len(prevRows))
+	for i, k := range prevKeys {
+		prevByKey[k] = prevRows[i]
+	}
+	nextHas := make(map[string]bool, len(nextRows))
+	for i, k := range nextKeys {
+		nextHas[k] = true
+		prevRow, ok := prevByKey[k]
+		if !ok {
+			bp.Rows = append(bp.Rows, &RowChange{Op: PatchRowAdd, Key: k, Row: nextRows[i]})
+		} else if !jsonEqual(prevRow, nextRows[i]) {
+			bp.Rows = append(bp.Rows, &RowChange{Op: PatchRowUpdate, Key: k, From: prevRow, Row: nextRows[i]})
+		}
+	}
+	for i, k := range prevKeys {
+		if !nextHas[k] {
+			bp.Rows = append(bp.Rows, &RowChange{Op: PatchRowDelete, Key: k, From: prevRows[i]})
+		}
+	}
+	if len(bp.Rows) > 0 {
+		p.Body = bp
+	}
+	return p, nil
+}
+
+// applyPatch applies the changes of a patch to an opened dataset, returning
+// the components that changed. Changes that expect a value ds doesn't have
+// are conflicts, which are skipped & described in the returned list
+func applyPatch(ds *dataset.Dataset, p *Patch) (changes *dataset.Dataset, conflicts []string, err error) {
+	if p.Qri != PatchFormat {
+		return nil, nil, fmt.Errorf("unsupported patch format %q, expected %q", p.Qri, PatchFormat)
+	}
+	changes = &dataset.Dataset{}
+
+	if len(p.Meta) > 0 {
+		fields, err := componentFields(ds.Meta)
+		if err != nil {
+			return nil, nil, err
+		}
+		applied, skipped := applyFieldChanges("meta", fields, p.Meta)
+		conflicts = append(conflicts, skipped...)
+		if applied {
+			changes.Meta = &dataset.Meta{}
+			if err := remarshal(fields, changes.Meta); err != nil {
+				return nil, nil, fmt.Errorf("applying meta changes: %w", err)
+			}
+		}
+	}
+
+	st := ds.Structure
+	if len(p.Structure) > 0 {
+		fields, err := componentFields(ds.Structure, derivedStructureFields...)
+		if err != nil {
+			return nil, nil, err
+		}
+		applied, skipped := applyFieldChanges("structure", fields, p.Structure)
+		conflicts = append(conflicts, skipped...)
+		if applied {
+			changes.Structure = &dataset.Structure{}
+			if err := remarshal(fields, changes.Structure); err != nil {
+				return nil, nil, fmt.Errorf("applying structure changes: %w", err)
+			}
+			st = changes.Structure
+		}
+	}
+
+	if p.Body != nil && len(p.Body.Rows) > 0 {
+		rows, keys, err := bodyDeltaRows(ds, p.Body.Key)
+		if err != nil {
+			return nil, nil, err
+		}
+		next, applied, skipped := applyRowChanges(rows, keys, p.Body.Rows)
+		conflicts = append(conflicts, skipped...)
+		if applied {
+			body, err := rowsBodyFile(st, next)
+			if err != nil {
+				return nil, nil, err
+			}
+			changes.SetBodyFile(body)
+			if changes.Structure == nil {
+				// the body is written in the format of the current structure
+				changes.Structure = &dataset.Structure{Format: st.Format, Schema: st.Schema}
+			}
+		}
+	}
+
+	if changes.Meta == nil && changes.Structure == nil && changes.BodyFile() == nil {
+		if len(conflicts) > 0 {
+			return nil, conflicts, fmt.Errorf("no patch changes apply, all conflict:\n  %s", strings.Join(conflicts, "\n  "))
+		}
+		return nil, nil, fmt.Errorf("no changes to apply, the dataset already matches the patch")
+	}
+	return changes, conflicts, nil
+}
+
+// applyFieldChanges sets the fields of a component. A change applies when the
+// field has the change's From value. Fields that already have the To value
+// are left as is
+func applyFieldChanges(component string, fields map[string]interface{}, changes map[string]*FieldChange) (applied bool, conflicts []string) {
+	names := make([]string, 0, len(changes))
+	for name := range changes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ch := changes[name]
+		cur := fields[name]
+		if jsonEqual(cur, ch.To) {
+			continue
+		}
+		if !jsonEqual(cur, ch.From) {
+			conflicts = append(conflicts, fmt.Sprintf("%s.%s: expected %s, found %s", component, name, jsonString(ch.From), jsonString(cur)))
+			continue
+		}
+		if ch.To == nil {
+			delete(fields, name)
+		} else {
+			fields[name] = ch.To
+		}
+		applied = true
+	}
+	return applied, conflicts
+}
+
+// applyRowChanges applies row operations to body rows. Updated rows keep
+// their position, added rows are appended
+func applyRowChanges(rows []interface{}, keys []string, changes []*RowChange) (next []interface{}, applied bool, conflicts []string) {
+	index := make(map[string]int, len(keys))
+	for i, k := range keys {
+		index[k] = i
+	}
+	next = append([]interface{}{}, rows...)
+	deleted := map[int]bool{}
+
+	for _, ch := range changes {
+		i, exists := index[ch.Key]
+		if exists && deleted[i] {
+			exists = false
+		}
+		switch ch.Op {
+		case PatchRowAdd:
+			if exists {
+				if !jsonEqual(next[i], ch.Row) {
+					conflicts = append(conflicts, fmt.Sprintf("body row %s: added row already exists as %s", ch.Key, jsonString(next[i])))
+				}
+				continue
+			}
+			index[ch.Key] = len(next)
+			next = append(next, ch.Row)
+		case PatchRowUpdate:
+			if !exists {
+				conflicts = append(conflicts, fmt.Sprintf("body row %s: updated row not found", ch.Key))
+				continue
+			}
+			if jsonEqual(next[i], ch.Row) {
+				continue
+			}
+			if !jsonEqual(next[i], ch.From) {
+				conflicts = append(conflicts, fmt.Sprintf("body row %s: expected %s, found %s", ch.Key, jsonString(ch.From), jsonString(next[i])))
+				continue
+			}
+			next[i] = ch.Row
+		case PatchRowDelete:
+			if !exists {
+				continue
+			}
+			if !jsonEqual(next[i], ch.From) {
+				conflicts = append(conflicts, fmt.Sprintf("body row %s: expected %s, found %s", ch.Key, jsonString(ch.From), jsonString(next[i])))
+				continue
+			}
+			deleted[i] = true
+		default:
+			conflicts = append(conflicts, fmt.Sprintf("body row %s: unknown operation %q", ch.Key, ch.Op))
+			continue
+		}
+		applied = true
+	}
+
+	if len(deleted) > 0 {
+		kept := next[:0]
+		for i, row := range next {
+			if !deleted[i] {
+				kept = append(kept, row)
+			}
+		}
+		next = kept
+	}
+	return next, applied, conflicts
+}
+
+// rowsBodyFile writes body rows to a file in the format of a structure
+func rowsBodyFile(st *dataset.Structure, rows []interface{}) (qfs.File, error) {
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, err
+	}
+	jsonSt := &dataset.Structure{Format: dataset.JSONDataFormat.String(), Schema: st.Schema}
+	jsonFile := qfs.NewMemfileBytes(jsonSt.BodyFilename(), data)
+	if st.Format == jsonSt.Format {
+		return jsonFile, nil
+	}
+	return base.ConvertBodyFormat(jsonFile, jsonSt, st)
+}
+
+// componentFields converts a component to a map of its top-level fields,
+// dropping the qri & path fields along with any extra named fields
+func componentFields(comp interface{}, drop ...string) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	data, err := json.Marshal(comp)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		// a nil component marshals as null
+		fields = map[string]interface{}{}
+	}
+	delete(fields, "qri")
+	delete(fields, "path")
+	for _, name := range drop {
+		delete(fields, name)
+	}
+	return fields, nil
+}
+
+// fieldChanges lists the fields that differ between two sets of component
+// fields, returning nil if there are none
+func fieldChanges(prev, next map[string]interface{}) map[string]*FieldChange {
+	changes := map[string]*FieldChange{}
+	for name, v := range next {
+		if !jsonEqual(prev[name], v) {
+			changes[name] = &FieldChange{From: prev[name], To: v}
+		}
+	}
+	for name, v := range prev {
+		if _, ok := next[name]; !ok {
+			changes[name] = &FieldChange{From: v}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
+
+// remarshal decodes a json-compatible value into dst
+func remarshal(v, dst interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// jsonEqual compares values by their json encoding, so values decoded from a
+// patch file compare equal to values read from a body
+func jsonEqual(a, b interface{}) bool {
+	ad, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bd, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(ad, bd)
+}
+
+func jsonString(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// readPatchFile decodes a patch from a json file
+func readPatchFile(path string) (*Patch, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading patch: %w", err)
+	}
+	p := &Patch{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("decoding patch: %w", err)
+	}
+	return p, nil
+}
+
+// patchMessage describes an applied patch for a commit message
+func patchMessage(p *Patch, conflicts []string) string {
+	msg := fmt.Sprintf("applied patch made from version %s against %s", p.Target, p.Base)
+	if len(conflicts) > 0 {
+		msg += "\n\nskipped conflicting changes:\n" + strings.Join(conflicts, "\n")
+	}
+	return msg
+}
@@ -0,0 +1,94 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/qri-io/dataset"
+)
+
+func TestPatch(t *testing.T) {
+	run := newTestRunner(t)
+	defer run.Delete()
+
+	run.MustSaveFromBody(t, "cities", "testdata/cities_2/body.csv")
+	run.MustSaveFromBody(t, "fork", "testdata/cities_2/body.csv")
+
+	// update toronto, remove chicago & add boston
+	edited := run.MustWriteTmpFile(t, "edited.csv", `city,pop,avg_age,in_usa
+toronto,51000000,55.5,false
+new york,8500000,44.4,true
+chatham,35000,65.25,true
+raleigh,250000,50.65,true
+boston,680000,36.5,true
+`)
+	if _, err := run.SaveWithParams(&SaveParams{
+		Ref:      "me/cities",
+		BodyPath: edited,
+		Dataset:  &dataset.Dataset{Meta: &dataset.Meta{Title: "cities"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	patch, err := run.Instance.Diff().Patch(run.Ctx, &DiffParams{LeftSide: "me/cities", UseLeftPrevVersion: true, Key: "city"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch := patch.Meta["title"]; ch == nil || ch.From != nil || ch.To != "cities" {
+		t.Errorf("expected patch to set meta title, got: %v", patch.Meta)
+	}
+	ops := map[string]string{}
+	for _, row := range patch.Body.Rows {
+		ops[row.Key] = row.Op
+	}
+	expectOps := map[string]string{"toronto": PatchRowUpdate, "chicago": PatchRowDelete, "boston": PatchRowAdd}
+	if diff := cmp.Diff(expectOps, ops); diff != "" {
+		t.Errorf("body row operations mismatch (-want +got):\n%s", diff)
+	}
+
+	// change the fork so the toronto update conflicts
+	forked := run.MustWriteTmpFile(t, "forked.csv", `city,pop,avg_age,in_usa
+toronto,49000000,55.5,false
+new york,8500000,44.4,true
+chicago,300000,44.4,true
+chatham,35000,65.25,true
+raleigh,250000,50.65,true
+`)
+	if _, err := run.SaveWithParams(&SaveParams{Ref: "me/fork", BodyPath: forked}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := run.Instance.Dataset().ApplyPatch(run.Ctx, &ApplyPatchParams{Ref: "me/fork", Patch: patch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.BaseMatch {
+		t.Error("expected patch base not to match the fork")
+	}
+	expectConflicts := []string{`body row toronto: expected ["toronto",50000000,55.5,false], found ["toronto",49000000,55.5,false]`}
+	if diff := cmp.Diff(expectConflicts, res.Conflicts); diff != "" {
+		t.Errorf("conflicts mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err := run.Instance.Dataset().Get(run.Ctx, &GetParams{Ref: "me/fork", Selector: "body", All: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectBody := []interface{}{
+		[]interface{}{"toronto", int64(49000000), 55.5, false},
+		[]interface{}{"new york", int64(8500000), 44.4, true},
+		[]interface{}{"chatham", int64(35000), 65.25, true},
+		[]interface{}{"raleigh", int64(250000), 50.65, true},
+		[]interface{}{"boston", int64(680000), 36.5, true},
+	}
+	if diff := cmp.Diff(expectBody, got.Value); diff != "" {
+		t.Errorf("patched body mismatch (-want +got):\n%s", diff)
+	}
+	if title := run.MustGet(t, "me/fork").Meta.Title; title != "cities" {
+		t.Errorf("expected patched meta title %q, got %q", "cities", title)
+	}
+
+	if _, err := run.Instance.Dataset().ApplyPatch(run.Ctx, &ApplyPatchParams{Ref: "me/fork", Patch: patch}); err == nil {
+		t.Error("expected applying a patch with only conflicting changes to error")
+	}
+}
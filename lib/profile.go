@@ -30,7 +30,7 @@ func (m ProfileMethods) Name() string {
 // Attributes defines attributes for each method
 func (m ProfileMethods) Attributes() map[string]AttributeSet {
 	return map[string]AttributeSet{
-		"getprofile":      {Endpoint: qhttp.AEGetProfile, HTTPVerb: "POST", DenyRPC: true},
+		"getprofile":      {Endpoint: qhttp.AEGetProfile, HTTPVerb: "POST", DenyRPC: true, ReadOnly: true},
 		"setprofile":      {Endpoint: qhttp.AESetProfile, HTTPVerb: "POST", DenyRPC: true},
 		"setprofilephoto": {Endpoint: qhttp.AESetProfilePhoto, HTTPVerb: "POST", DenyRPC: true},
 		"setposterphoto":  {Endpoint: qhttp.AESetPosterPhoto, HTTPVerb: "POST", DenyRPC: true},
@@ -2,12 +2,17 @@ package lib
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/qri/base"
 	"github.com/qri-io/qri/dsref"
 	qhttp "github.com/qri-io/qri/lib/http"
+	"github.com/qri-io/qri/logbook"
 	"github.com/qri-io/qri/remote"
 )
 
@@ -27,9 +32,11 @@ func (m RemoteMethods) Name() string {
 // Attributes defines attributes for each method
 func (m RemoteMethods) Attributes() map[string]AttributeSet {
 	return map[string]AttributeSet{
-		"feeds":   {Endpoint: qhttp.AEFeeds, HTTPVerb: "POST"},
-		"preview": {Endpoint: qhttp.AEPreview, HTTPVerb: "POST"},
+		"feeds":   {Endpoint: qhttp.AEFeeds, HTTPVerb: "POST", ReadOnly: true},
+		"preview": {Endpoint: qhttp.AEPreview, HTTPVerb: "POST", ReadOnly: true},
 		"remove":  {Endpoint: qhttp.AERemoteRemove, HTTPVerb: "POST", DefaultSource: "network"},
+		"locate":  {Endpoint: qhttp.AELocate, HTTPVerb: "POST", ReadOnly: true},
+		"diff":    {Endpoint: qhttp.AERemoteDiff, HTTPVerb: "POST", ReadOnly: true},
 	}
 }
 
@@ -66,6 +73,126 @@ func (m RemoteMethods) Remove(ctx context.Context, p *PushParams) (*dsref.Ref, e
 	return nil, dispatchReturnError(got, err)
 }
 
+// DefaultLocateTimeout is how long Locate waits for each remote to respond
+const DefaultLocateTimeout = 10 * time.Second
+
+// LocateParams provides arguments to the locate method
+type LocateParams struct {
+	Ref string `json:"ref"`
+	// Registry also checks the configured registry
+	Registry bool `json:"registry"`
+	// Timeout is how long to wait for each remote, defaults to
+	// DefaultLocateTimeout
+	Timeout time.Duration `json:"timeout"`
+}
+
+// Validate returns an error if LocateParams fields are in an invalid state
+func (p *LocateParams) Validate() error {
+	if p.Ref == "" {
+		return fmt.Errorf("reference is required")
+	}
+	if p.Timeout < 0 {
+		return fmt.Errorf("timeout cannot be negative")
+	}
+	return nil
+}
+
+// Location reports if a remote has a dataset
+type Location struct {
+	// Remote is the configured name of the remote, "registry" for the registry
+	Remote  string `json:"remote"`
+	Address string `json:"address"`
+	// Available is true when the remote has the dataset
+	Available bool `json:"available"`
+	// Path is the latest version of the dataset the remote has
+	Path string `json:"path,omitempty"`
+	// Error is set when the remote couldn't be reached
+	Error string `json:"error,omitempty"`
+}
+
+// Locate asks each configured remote if it has a dataset without pulling
+// anything, listing remotes by name. Remotes are asked in parallel, a remote
+// that doesn't answer in time is reported with an error
+func (m RemoteMethods) Locate(ctx context.Context, p *LocateParams) ([]Location, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "locate"), p)
+	if res, ok := got.([]Location); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
+// RemoteDiffParams provides arguments to the diff method
+type RemoteDiffParams struct {
+	Ref string `json:"ref"`
+	// Left & Right are the configured names of the remotes to compare
+	Left  string `json:"left"`
+	Right string `json:"right"`
+	// Timeout is how long to wait for each remote, defaults to
+	// DefaultLocateTimeout
+	Timeout time.Duration `json:"timeout"`
+}
+
+// Validate returns an error if RemoteDiffParams fields are in an invalid state
+func (p *RemoteDiffParams) Validate() error {
+	if p.Ref == "" {
+		return fmt.Errorf("reference is required")
+	}
+	if p.Left == "" || p.Right == "" {
+		return fmt.Errorf("two remotes are required")
+	}
+	if p.Left == p.Right {
+		return fmt.Errorf("can't compare remote %q with itself", p.Left)
+	}
+	if p.Timeout < 0 {
+		return fmt.Errorf("timeout cannot be negative")
+	}
+	return nil
+}
+
+// RemoteHoldings lists the versions of a dataset a remote has
+type RemoteHoldings struct {
+	// Remote is the configured name of the remote
+	Remote  string `json:"remote"`
+	Address string `json:"address"`
+	// Versions the remote has, newest first
+	Versions []dsref.VersionInfo `json:"versions"`
+	// Error is set when the remote couldn't be reached
+	Error string `json:"error,omitempty"`
+}
+
+// RemoteDiff compares the versions of a dataset two remotes have
+type RemoteDiff struct {
+	Left  RemoteHoldings `json:"left"`
+	Right RemoteHoldings `json:"right"`
+	// OnlyLeft lists versions the left remote has that the right remote
+	// doesn't, OnlyRight the reverse. Both are nil if either remote couldn't be
+	// reached
+	OnlyLeft  []dsref.VersionInfo `json:"onlyLeft"`
+	OnlyRight []dsref.VersionInfo `json:"onlyRight"`
+}
+
+// Reachable is true when both remotes answered
+func (d *RemoteDiff) Reachable() bool {
+	return d.Left.Error == "" && d.Right.Error == ""
+}
+
+// InSync is true when both remotes answered with the same versions
+func (d *RemoteDiff) InSync() bool {
+	return d.Reachable() && len(d.OnlyLeft) == 0 && len(d.OnlyRight) == 0
+}
+
+// Diff compares the versions of a dataset two remotes have, using the
+// logbook each remote exposes. It's used to check that remotes mirroring a
+// dataset are in sync. Remotes are asked in parallel, a remote that doesn't
+// answer in time is reported with an error instead of having no versions
+func (m RemoteMethods) Diff(ctx context.Context, p *RemoteDiffParams) (*RemoteDiff, error) {
+	got, _, err := m.d.Dispatch(ctx, dispatchMethodName(m, "diff"), p)
+	if res, ok := got.(*RemoteDiff); ok {
+		return res, err
+	}
+	return nil, dispatchReturnError(got, err)
+}
+
 // remoteImpl holds the method implementations for RemoteMethods
 type remoteImpl struct{}
 
@@ -104,6 +231,155 @@ func (remoteImpl) Preview(scope scope, p *PreviewParams) (*dataset.Dataset, erro
 	return res, nil
 }
 
+// Locate asks each configured remote if it has a dataset
+func (remoteImpl) Locate(scope scope, p *LocateParams) ([]Location, error) {
+	ref, err := dsref.Parse(p.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a valid dataset reference: %w", p.Ref, err)
+	}
+	if ref.Username == "me" {
+		ref.Username = scope.ActiveProfile().Peername
+	}
+
+	cfg := scope.Config()
+	res := []Location{}
+	if cfg.Remotes != nil {
+		names := make([]string, 0, len(*cfg.Remotes))
+		for name := range *cfg.Remotes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			res = append(res, Location{Remote: name, Address: (*cfg.Remotes)[name]})
+		}
+	}
+	if p.Registry && cfg.Registry != nil && cfg.Registry.Location != "" {
+		res = append(res, Location{Remote: "registry", Address: cfg.Registry.Location})
+	}
+	if len(res) == 0 {
+		return nil, fmt.Errorf("no remotes configured")
+	}
+
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = DefaultLocateTimeout
+	}
+
+	wg := sync.WaitGroup{}
+	for i := range res {
+		wg.Add(1)
+		go func(loc *Location) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(scope.Context(), timeout)
+			defer cancel()
+
+			r := ref
+			_, err := scope.RemoteClient().NewRemoteRefResolver(loc.Address).ResolveRef(ctx, &r)
+			switch {
+			case err == nil:
+				loc.Available = true
+				loc.Path = r.Path
+			case errors.Is(err, dsref.ErrRefNotFound):
+			case errors.Is(err, context.DeadlineExceeded):
+				loc.Error = fmt.Sprintf("no response after %s", timeout)
+			default:
+				loc.Error = err.Error()
+			}
+		}(&res[i])
+	}
+	wg.Wait()
+	return res, nil
+}
+
+// Diff compares the versions of a dataset two remotes have
+func (remoteImpl) Diff(scope scope, p *RemoteDiffParams) (*RemoteDiff, error) {
+	ref, err := dsref.Parse(p.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a valid dataset reference: %w", p.Ref, err)
+	}
+	if ref.Username == "me" {
+		ref.Username = scope.ActiveProfile().Peername
+	}
+
+	res := &RemoteDiff{
+		Left:  RemoteHoldings{Remote: p.Left},
+		Right: RemoteHoldings{Remote: p.Right},
+	}
+	for _, h := range []*RemoteHoldings{&res.Left, &res.Right} {
+		if h.Address, err = remote.Address(scope.Config(), h.Remote); err != nil {
+			return nil, err
+		}
+	}
+
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = DefaultLocateTimeout
+	}
+
+	wg := sync.WaitGroup{}
+	for _, h := range []*RemoteHoldings{&res.Left, &res.Right} {
+		wg.Add(1)
+		go func(h *RemoteHoldings) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(scope.Context(), timeout)
+			defer cancel()
+
+			versions, err := remoteVersions(ctx, scope, ref, h.Address)
+			switch {
+			case err == nil:
+				h.Versions = versions
+			case errors.Is(err, context.DeadlineExceeded):
+				h.Error = fmt.Sprintf("no response after %s", timeout)
+			default:
+				h.Error = err.Error()
+			}
+		}(h)
+	}
+	wg.Wait()
+
+	if res.Reachable() {
+		res.OnlyLeft = versionsMissingFrom(res.Left.Versions, res.Right.Versions)
+		res.OnlyRight = versionsMissingFrom(res.Right.Versions, res.Left.Versions)
+	}
+	return res, nil
+}
+
+// remoteVersions fetches the versions of a dataset a remote has from the
+// logbook the remote exposes. A remote without the dataset has no versions
+func remoteVersions(ctx context.Context, scope scope, ref dsref.Ref, addr string) ([]dsref.VersionInfo, error) {
+	logs, err := scope.RemoteClient().FetchLogs(ctx, ref, addr)
+	if err != nil {
+		if errors.Is(err, logbook.ErrNotFound) {
+			return []dsref.VersionInfo{}, nil
+		}
+		return nil, err
+	}
+	// descend from the user log to the dataset branch log, see
+	// datasetImpl.Activity
+	if len(logs.Logs) > 0 {
+		logs = logs.Logs[0]
+		if len(logs.Logs) > 0 {
+			logs = logs.Logs[0]
+		}
+	}
+	return logbook.ConvertLogsToVersionInfos(logs, ref), nil
+}
+
+// versionsMissingFrom lists versions in a that b doesn't have
+func versionsMissingFrom(a, b []dsref.VersionInfo) []dsref.VersionInfo {
+	has := make(map[string]bool, len(b))
+	for _, vi := range b {
+		has[vi.Path] = true
+	}
+	missing := []dsref.VersionInfo{}
+	for _, vi := range a {
+		if !has[vi.Path] {
+			missing = append(missing, vi)
+		}
+	}
+	return missing
+}
+
 // Remove asks a remote to remove a dataset
 func (remoteImpl) Remove(scope scope, p *PushParams) (*dsref.Ref, error) {
 	ref, err := dsref.ParseHumanFriendly(p.Ref)
@@ -247,7 +247,34 @@ func TestRenderValidationFailure(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
-	expect = "selector must be one of 'viz' or 'readme'"
+	expect = "selector must be one of 'viz', 'readme' or 'body'"
+	if diff := cmp.Diff(expect, err.Error()); diff != "" {
+		t.Errorf("err mismatch (-want +got):\n%s", diff)
+	}
+
+	params = RenderParams{
+		Ref:        "peer/my_dataset",
+		Selector:   "readme",
+		InlineBody: true,
+	}
+	_, err = runner.Instance.Dataset().Render(runner.Context, &params)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	expect = "can only inline the body when rendering viz"
+	if diff := cmp.Diff(expect, err.Error()); diff != "" {
+		t.Errorf("err mismatch (-want +got):\n%s", diff)
+	}
+	params = RenderParams{
+		Ref:      "peer/my_dataset",
+		Selector: "viz",
+		Limit:    10,
+	}
+	_, err = runner.Instance.Dataset().Render(runner.Context, &params)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	expect = "can only use limit & offset when rendering the body"
 	if diff := cmp.Diff(expect, err.Error()); diff != "" {
 		t.Errorf("err mismatch (-want +got):\n%s", diff)
 	}
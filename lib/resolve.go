@@ -49,12 +49,12 @@ func (inst *Instance) ResolveReference(ctx context.Context, ref *dsref.Ref, sour
 func (inst *Instance) resolverForSource(source string) (dsref.Resolver, error) {
 	switch source {
 	case "":
-		return inst.defaultResolver(), nil
+		return inst.withCustomResolvers(inst.defaultResolver()), nil
 	case "local":
-		return dsref.SequentialResolver(
+		return inst.withCustomResolvers(dsref.SequentialResolver(
 			inst.dscache,
 			inst.repo,
-		), nil
+		)), nil
 	case "network":
 		return dsref.ParallelResolver(
 			inst.registryResolver(),
@@ -89,6 +89,18 @@ func (inst *Instance) defaultResolver() dsref.Resolver {
 	)
 }
 
+// withCustomResolvers chains registered custom resolvers around a resolver
+func (inst *Instance) withCustomResolvers(r dsref.Resolver) dsref.Resolver {
+	if len(inst.refResolversFirst) == 0 && len(inst.refResolversLast) == 0 {
+		return r
+	}
+	chain := make([]dsref.Resolver, 0, len(inst.refResolversFirst)+len(inst.refResolversLast)+1)
+	chain = append(chain, inst.refResolversFirst...)
+	chain = append(chain, r)
+	chain = append(chain, inst.refResolversLast...)
+	return dsref.SequentialResolver(chain...)
+}
+
 func (inst *Instance) registryResolver() dsref.Resolver {
 	var location string
 	if inst.cfg.Registry != nil {
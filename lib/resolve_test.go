@@ -38,3 +38,84 @@ want: %q
 got:  %q`, dsref.ErrRefNotFound, err)
 	}
 }
+
+// mapResolver resolves references from a map of human-friendly names to
+// dataset paths
+type mapResolver map[string]string
+
+func (m mapResolver) ResolveRef(ctx context.Context, ref *dsref.Ref) (string, error) {
+	path, ok := m[ref.Human()]
+	if !ok {
+		return "", dsref.ErrRefNotFound
+	}
+	ref.InitID = "custom_" + ref.Name
+	ref.Path = path
+	return "", nil
+}
+
+func TestResolveReferenceCustomResolvers(t *testing.T) {
+	tr, err := repotest.NewTempRepo("ruh_roh", "inst_resolve_custom", repotest.NewTestCrypto())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Delete()
+
+	cfg := tr.GetConfig()
+	cfg.Registry = nil
+	tr.WriteConfigFile()
+
+	ctx := context.Background()
+	first := mapResolver{
+		"corp/revenue":   "/mem/QmRevenue",
+		"ruh_roh/movies": "/mem/QmShadowed",
+	}
+	last := mapResolver{"corp/costs": "/mem/QmCosts"}
+	inst, err := NewInstance(ctx, tr.QriPath,
+		OptRefResolver(last, ResolveAfterDefault),
+		OptRefResolver(first, ResolveBeforeDefault),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		ref, source, expectPath string
+	}{
+		{"corp/revenue", "", "/mem/QmRevenue"},
+		{"corp/costs", "local", "/mem/QmCosts"},
+		// resolvers registered before the default take precedence
+		{"ruh_roh/movies", "", "/mem/QmShadowed"},
+	}
+	for _, c := range cases {
+		ref, _, err := inst.ParseAndResolveRef(ctx, c.ref, c.source)
+		if err != nil {
+			t.Errorf("resolving %q: %s", c.ref, err)
+			continue
+		}
+		if ref.Path != c.expectPath {
+			t.Errorf("resolving %q: expected path %q, got %q", c.ref, c.expectPath, ref.Path)
+		}
+	}
+
+	// local datasets still resolve with custom resolvers registered
+	saved, err := inst.Dataset().Save(ctx, &SaveParams{Ref: "me/cities", BodyPath: "testdata/cities_2/body.csv"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, _, err := inst.ParseAndResolveRef(ctx, "ruh_roh/cities", "local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.Path != saved.Path {
+		t.Error("expected the default resolver to resolve a local dataset before a custom resolver")
+	}
+
+	// custom resolvers don't apply to network sources
+	if _, _, err := inst.ParseAndResolveRef(ctx, "corp/revenue", "registry"); err == nil {
+		t.Error("expected registry source to ignore custom resolvers")
+	}
+
+	if _, err := NewInstance(ctx, tr.QriPath, OptRefResolver(nil, ResolveBeforeDefault)); err == nil {
+		t.Error("expected a nil resolver to error")
+	}
+}
@@ -1,15 +1,24 @@
 package lib
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gorilla/mux"
+	apiutil "github.com/qri-io/qri/api/util"
 	qhttp "github.com/qri-io/qri/lib/http"
 )
 
-// GiveAPIServer creates an API server that gives access to lib's registered methods
+// ErrReadOnly is returned by API servers configured to be read-only when a
+// request calls a method that changes the repo
+var ErrReadOnly = fmt.Errorf("qri server is read-only")
+
+// GiveAPIServer creates an API server that gives access to lib's registered
+// methods. When the API is configured to be read-only, methods that aren't
+// read-only respond with http status 403
 func (inst *Instance) GiveAPIServer(middleware func(handler http.HandlerFunc) http.HandlerFunc, ignoreMethods []string) *mux.Router {
 	m := mux.NewRouter()
+	readOnly := inst.ReadOnlyAPI()
 	for methodName, call := range inst.regMethods.reg {
 		if arrayContainsString(ignoreMethods, methodName) {
 			continue
@@ -18,6 +27,9 @@ func (inst *Instance) GiveAPIServer(middleware func(handler http.HandlerFunc) ht
 			continue
 		}
 		handler := middleware(NewHTTPRequestHandler(inst, methodName))
+		if readOnly && !call.ReadOnly {
+			handler = middleware(ReadOnlyHandler)
+		}
 		// All endpoints use POST verb
 		httpVerb := http.MethodPost
 		m.Handle(string(call.Endpoint), handler).Methods(httpVerb, http.MethodOptions)
@@ -33,3 +45,16 @@ func arrayContainsString(searchSpace []string, target string) bool {
 	}
 	return false
 }
+
+// ReadOnlyAPI reports if the instance API is configured to only serve
+// methods that don't change the repo
+func (inst *Instance) ReadOnlyAPI() bool {
+	cfg := inst.GetConfig()
+	return cfg != nil && cfg.API != nil && cfg.API.ReadOnly
+}
+
+// ReadOnlyHandler responds to requests for methods that read-only API servers
+// don't serve
+func ReadOnlyHandler(w http.ResponseWriter, r *http.Request) {
+	apiutil.WriteErrResponse(w, http.StatusForbidden, ErrReadOnly)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/qri-io/qri/remote"
 	"github.com/qri-io/qri/repo"
 	"github.com/qri-io/qri/stats"
+	"github.com/qri-io/qri/transform"
 )
 
 // scope represents the lifetime of a method call, abstractly connected to the
@@ -205,6 +206,12 @@ func (s *scope) RemoteClient() remote.Client {
 	return s.inst.remoteClient
 }
 
+// SecretProviders returns the providers transform scripts can read secrets
+// from, keyed by scheme
+func (s *scope) SecretProviders() map[string]transform.SecretProvider {
+	return s.inst.secretProviders
+}
+
 // Repo returns the repo store
 func (s *scope) Repo() repo.Repo {
 	return s.inst.repo
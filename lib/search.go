@@ -23,7 +23,7 @@ func (m SearchMethods) Name() string {
 // Attributes defines attributes for each method
 func (m SearchMethods) Attributes() map[string]AttributeSet {
 	return map[string]AttributeSet{
-		"search": {Endpoint: qhttp.AESearch, HTTPVerb: "POST"},
+		"search": {Endpoint: qhttp.AESearch, HTTPVerb: "POST", ReadOnly: true},
 	}
 }
 
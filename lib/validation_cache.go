@@ -0,0 +1,97 @@
+package lib
+
+import (
+	"encoding/base32"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/qri-io/dataset"
+	"github.com/qri-io/jsonschema"
+)
+
+// validationCacheDirname is the directory within the repo that stores
+// cached validation results
+const validationCacheDirname = "validation"
+
+// validationCache stores the results of validating stored datasets on disk,
+// so validation results outlive the process that computed them. Results
+// are keyed by the body & structure paths they were computed from. Saving a
+// new version changes at least one of these paths, which invalidates the
+// cached result
+type validationCache struct {
+	root string
+}
+
+// newValidationCache creates a validation cache in the validation directory
+// of a repo. validation isn't cached when repoPath is empty
+func newValidationCache(repoPath string) *validationCache {
+	if repoPath == "" {
+		return &validationCache{}
+	}
+	return &validationCache{root: filepath.Join(repoPath, validationCacheDirname)}
+}
+
+// validationCacheEntry is the on-disk format of a cached result
+type validationCacheEntry struct {
+	BodyPath      string                `json:"bodyPath"`
+	StructurePath string                `json:"structurePath"`
+	Structure     *dataset.Structure    `json:"structure"`
+	Errors        []jsonschema.KeyError `json:"errors"`
+}
+
+var validationCacheEnc = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+func (c *validationCache) filepath(bodyPath, structurePath string) string {
+	key := validationCacheEnc.EncodeToString([]byte(bodyPath + "\n" + structurePath))
+	return filepath.Join(c.root, fmt.Sprintf("%s.json", key))
+}
+
+// enabled reports if results for a dataset can be cached. both paths are
+// required to key the result
+func (c *validationCache) enabled(ds *dataset.Dataset) bool {
+	return c.root != "" && ds != nil && ds.BodyPath != "" && ds.Structure != nil && ds.Structure.Path != ""
+}
+
+// get returns the cached result of validating ds, if any
+func (c *validationCache) get(ds *dataset.Dataset) (*ValidateResponse, bool) {
+	if !c.enabled(ds) {
+		return nil, false
+	}
+	data, err := ioutil.ReadFile(c.filepath(ds.BodyPath, ds.Structure.Path))
+	if err != nil {
+		return nil, false
+	}
+	entry := &validationCacheEntry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		log.Debugw("decoding cached validation result", "err", err)
+		return nil, false
+	}
+	if entry.BodyPath != ds.BodyPath || entry.StructurePath != ds.Structure.Path {
+		return nil, false
+	}
+	return &ValidateResponse{Structure: entry.Structure, Errors: entry.Errors}, true
+}
+
+// put caches the result of validating ds. bodyPath & structurePath must be
+// read before validation, which may alter the dataset structure
+func (c *validationCache) put(bodyPath, structurePath string, res *ValidateResponse) error {
+	if c.root == "" || bodyPath == "" || structurePath == "" {
+		return nil
+	}
+	data, err := json.Marshal(validationCacheEntry{
+		BodyPath:      bodyPath,
+		StructurePath: structurePath,
+		Structure:     res.Structure,
+		Errors:        res.Errors,
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.root, os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.filepath(bodyPath, structurePath), data, 0644)
+}
@@ -0,0 +1,98 @@
+package logbook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/dsref"
+)
+
+const (
+	// StrandedNoCommits is the reason given for a dataset log that was
+	// initialized but has no saved versions
+	StrandedNoCommits = "no commits"
+	// StrandedMissingHead is the reason given for a dataset log with a head
+	// version the store doesn't have
+	StrandedMissingHead = "head version not found"
+)
+
+// StrandedLog is a dataset log that doesn't resolve to a saved version, often
+// left behind by a failed save
+type StrandedLog struct {
+	// Ref has Username, ProfileID, Name & InitID set. Path is set to the head
+	// version when there is one
+	Ref    dsref.Ref `json:"ref"`
+	Reason string    `json:"reason"`
+}
+
+// StrandedLogs scans the datasets of the logbook owner for logs that can't
+// resolve a saved version: logs with only init ops, and logs with a head path
+// fs doesn't have. Logs authored by other users aren't checked, fetched logs
+// commonly reference versions that were never pulled
+func (book *Book) StrandedLogs(ctx context.Context, fs qfs.Filesystem) ([]StrandedLog, error) {
+	if book == nil {
+		return nil, ErrNoLogbook
+	}
+	profileID := book.owner.ID.Encode()
+	authorLog, err := book.userLog(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []StrandedLog{}
+	for _, dsLog := range authorLog.l.Logs {
+		if dsLog.Model() != DatasetModel || dsLog.Removed() {
+			continue
+		}
+		ref := dsref.Ref{
+			Username:  authorLog.l.Name(),
+			ProfileID: profileID,
+			Name:      dsLog.Name(),
+			InitID:    dsLog.ID(),
+		}
+
+		if len(dsLog.Logs) > 0 {
+			ref.Path = book.latestSavePath(dsLog.Logs[0])
+		}
+		if ref.Path == "" {
+			res = append(res, StrandedLog{Ref: ref, Reason: StrandedNoCommits})
+			continue
+		}
+
+		// only a missing head is stranded. errors checking the store are
+		// returned, they don't say anything about the version
+		has, err := fs.Has(ctx, ref.Path)
+		if err != nil {
+			return nil, fmt.Errorf("checking head version of %s: %w", ref.Alias(), err)
+		}
+		if !has {
+			res = append(res, StrandedLog{Ref: ref, Reason: StrandedMissingHead})
+		}
+	}
+	return res, nil
+}
+
+// RemoveStrandedLogs removes the dataset logs of stranded references from
+// the logbook with RemoveLog. Logs are matched by name & InitID, a stranded
+// reference that no longer names the same log is an error
+func (book *Book) RemoveStrandedLogs(ctx context.Context, stranded []StrandedLog) error {
+	if book == nil {
+		return ErrNoLogbook
+	}
+	for _, s := range stranded {
+		ref := s.Ref
+		dsLog, err := book.store.HeadRef(ctx, dsRefToLogPath(ref)...)
+		if err != nil {
+			return fmt.Errorf("finding stranded reference %s: %w", ref.Alias(), err)
+		}
+		if dsLog.ID() != ref.InitID {
+			return fmt.Errorf("stranded reference %s no longer refers to dataset %s", ref.Alias(), ref.InitID)
+		}
+		log.Debugw("removing stranded reference", "initID", ref.InitID, "name", ref.Name)
+		if err := book.RemoveLog(ctx, ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
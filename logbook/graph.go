@@ -0,0 +1,63 @@
+package logbook
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DOT renders a plain log hierarchy as a Graphviz DOT directed graph. Each
+// log is a box linked to its child logs, and the ops of a log are chained
+// in the order they were appended, starting from the log that holds them
+func (l PlainLog) DOT() string {
+	b := &strings.Builder{}
+	b.WriteString("digraph oplog {\n")
+	b.WriteString("  node [fontname=\"Helvetica\" fontsize=10];\n")
+	writeDOTLog(b, l, "log")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func writeDOTLog(b *strings.Builder, l PlainLog, id string) {
+	label := "log"
+	if len(l.Ops) > 0 {
+		// the first op of a log initializes it, naming the model & log name
+		label = fmt.Sprintf("%s\n%s", l.Ops[0].Model, l.Ops[0].Name)
+	}
+	fmt.Fprintf(b, "  %s [shape=box style=bold label=%s];\n", id, dotQuote(label))
+
+	prev := id
+	for i, op := range l.Ops {
+		opID := fmt.Sprintf("%s_op%d", id, i)
+		fmt.Fprintf(b, "  %s [shape=ellipse label=%s];\n", opID, dotQuote(dotOpLabel(op)))
+		fmt.Fprintf(b, "  %s -> %s [style=dashed];\n", prev, opID)
+		prev = opID
+	}
+
+	for i, child := range l.Logs {
+		childID := fmt.Sprintf("%s_%d", id, i)
+		writeDOTLog(b, child, childID)
+		fmt.Fprintf(b, "  %s -> %s;\n", id, childID)
+	}
+}
+
+func dotOpLabel(op PlainOp) string {
+	lines := []string{fmt.Sprintf("%s %s", op.Type, op.Model)}
+	if op.Name != "" {
+		lines = append(lines, op.Name)
+	}
+	if op.Ref != "" {
+		lines = append(lines, op.Ref)
+	}
+	if op.Note != "" {
+		lines = append(lines, op.Note)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// dotQuote formats a string as a DOT quoted string, keeping line breaks
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
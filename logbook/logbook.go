@@ -20,6 +20,7 @@ import (
 	crypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/qfs"
+	"github.com/qri-io/qri/auth/key"
 	"github.com/qri-io/qri/automation/run"
 	"github.com/qri-io/qri/dsref"
 	"github.com/qri-io/qri/event"
@@ -73,6 +74,12 @@ const (
 	// related runID will have op.Relations = [...,"runID:run-uuid-string",...],
 	// This prefix disambiguates from other types of identifiers
 	runIDRelPrefix = "runID:"
+	// runTriggerRelPrefix is a string prefix for op.Relations when recording run
+	// ops that have a non-empty run.State.Trigger field
+	runTriggerRelPrefix = "trigger:"
+	// squashOpName is the op.Name of commit remove operations that drop the
+	// oldest versions of a branch instead of the most recent ones
+	squashOpName = "squash"
 )
 
 // ModelString gets a unique string descriptor for an integral model identifier
@@ -104,14 +111,38 @@ type Book struct {
 	publisher  event.Publisher
 	fs         qfs.Filesystem
 	fsLocation string
+	batch      *writeBatch
+	quiet      *quietEvents
+	refs       *refIndex
 }
 
+// writeBatch tracks logbook transactions. while depth is above zero, saves skip
+// writing the logbook to fs & set dirty instead
+type writeBatch struct {
+	sync.Mutex
+	depth int
+	dirty bool
+}
+
+// quietEvents collects the events held back during a quiet period
+type quietEvents struct {
+	sync.Mutex
+	counts  map[event.Type]int
+	initIDs []string
+	seen    map[string]bool
+}
+
+// quietCtxKey is the context key for the quietEvents of a quiet period
+type quietCtxKey struct{}
+
 // NewBook creates a book with a user-provided logstore
 func NewBook(owner profile.Profile, bus event.Publisher, store oplog.Logstore) *Book {
 	return &Book{
 		owner:     &owner,
 		store:     store,
 		publisher: bus,
+		batch:     &writeBatch{},
+		refs:      &refIndex{},
 	}
 }
 
@@ -139,6 +170,8 @@ func NewJournal(owner profile.Profile, bus event.Publisher, fs qfs.Filesystem, f
 		owner:      &owner,
 		fsLocation: fsLocation,
 		publisher:  bus,
+		batch:      &writeBatch{},
+		refs:       &refIndex{},
 	}
 
 	if err := book.load(ctx); err != nil {
@@ -148,6 +181,7 @@ func NewJournal(owner profile.Profile, bus event.Publisher, fs qfs.Filesystem, f
 		}
 		return nil, err
 	}
+	book.refs.index(ctx, book.store)
 
 	return book, nil
 }
@@ -179,6 +213,8 @@ func NewJournalOverwriteWithProfile(owner profile.Profile, bus event.Publisher,
 		fs:         fs,
 		fsLocation: fsLocation,
 		publisher:  bus,
+		batch:      &writeBatch{},
+		refs:       &refIndex{},
 	}
 
 	err := book.initialize(ctx)
@@ -204,6 +240,7 @@ func (book *Book) initialize(ctx context.Context) error {
 	if err := book.store.MergeLog(ctx, ownerOplog); err != nil {
 		return err
 	}
+	book.refs.reset()
 
 	return book.save(ctx, &UserLog{l: ownerOplog}, nil)
 }
@@ -215,6 +252,7 @@ func (book *Book) ReplaceAll(ctx context.Context, lg *oplog.Log) error {
 	if err != nil {
 		return err
 	}
+	book.refs.reset()
 	return book.save(ctx, nil, nil)
 }
 
@@ -244,6 +282,22 @@ func (book *Book) save(ctx context.Context, authorLog *UserLog, blog *BranchLog)
 		}
 	}
 
+	if book.batch == nil {
+		// books that weren't built by a constructor can't batch, write now
+		return book.writeFile(ctx)
+	}
+	book.batch.Lock()
+	defer book.batch.Unlock()
+	if book.batch.depth > 0 {
+		book.batch.dirty = true
+		return nil
+	}
+	return book.writeFile(ctx)
+}
+
+// writeFile encrypts the logbook & writes it to book.fsLocation. callers must
+// hold the batch lock
+func (book *Book) writeFile(ctx context.Context) (err error) {
 	if al, ok := book.store.(oplog.AuthorLogstore); ok {
 		ciphertext, err := al.FlatbufferCipher(book.owner.PrivKey)
 		if err != nil {
@@ -255,7 +309,150 @@ func (book *Book) save(ctx context.Context, authorLog *UserLog, blog *BranchLog)
 		log.Debugw("saved author logbook", "err", err)
 		return err
 	}
-	return err
+	return nil
+}
+
+// Transaction batches logbook writes. Operations run by fn update the logbook
+// in memory, and the logbook is written to the filesystem once when fn
+// returns, instead of once per operation. The write happens even if fn errors,
+// keeping the stored logbook in sync with operations that did complete.
+// Transactions can be nested, only the outermost transaction writes. Saves
+// from other goroutines during a transaction are also deferred until it ends.
+// Books that weren't created with a constructor run fn without batching
+func (book *Book) Transaction(ctx context.Context, fn func() error) error {
+	if book == nil {
+		return ErrNoLogbook
+	}
+	if book.batch == nil {
+		return fn()
+	}
+	book.batch.Lock()
+	book.batch.depth++
+	book.batch.Unlock()
+
+	fnErr := fn()
+
+	book.batch.Lock()
+	defer book.batch.Unlock()
+	book.batch.depth--
+	if book.batch.depth > 0 || !book.batch.dirty {
+		return fnErr
+	}
+	book.batch.dirty = false
+	if err := book.writeFile(ctx); err != nil {
+		if fnErr != nil {
+			return fmt.Errorf("%w. writing logbook: %s", fnErr, err)
+		}
+		return err
+	}
+	return fnErr
+}
+
+// Quiet suppresses event publishing for bulk operations. fn is called with a
+// context that scopes the quiet period: operations run with that context
+// count the events they would publish instead of publishing them, while
+// operations run with other contexts publish as usual. When fn returns, even
+// if fn errors, a single ETLogbookBulkWrite event is published with the head
+// version of each dataset the held back events were about. Calling Quiet with
+// a context that's already quiet joins the outer period, only the outermost
+// publishes. Combine with Transaction to also batch logbook writes
+func (book *Book) Quiet(ctx context.Context, fn func(ctx context.Context) error) error {
+	if book == nil {
+		return ErrNoLogbook
+	}
+	if _, ok := ctx.Value(quietCtxKey{}).(*quietEvents); ok {
+		return fn(ctx)
+	}
+
+	q := &quietEvents{
+		counts: map[event.Type]int{},
+		seen:   map[string]bool{},
+	}
+	fnErr := fn(context.WithValue(ctx, quietCtxKey{}, q))
+
+	q.Lock()
+	defer q.Unlock()
+	if len(q.counts) == 0 {
+		return fnErr
+	}
+	summary := event.LogbookBulkWrite{
+		Events:  q.counts,
+		InitIDs: q.initIDs,
+	}
+	for _, initID := range q.initIDs {
+		vi, err := book.headVersionInfo(ctx, initID)
+		if errors.Is(err, dsref.ErrRefNotFound) {
+			summary.Deleted = append(summary.Deleted, initID)
+			continue
+		} else if err != nil {
+			log.Debugw("getting head version for bulk write", "initID", initID, "err", err)
+			continue
+		}
+		summary.Versions = append(summary.Versions, vi)
+	}
+
+	if err := book.publisher.Publish(ctx, event.ETLogbookBulkWrite, summary); err != nil {
+		log.Error(err)
+	}
+	return fnErr
+}
+
+// publish sends an event to subscribers, or counts it if ctx is quiet
+func (book *Book) publish(ctx context.Context, typ event.Type, payload interface{}) error {
+	q, ok := ctx.Value(quietCtxKey{}).(*quietEvents)
+	if !ok {
+		return book.publisher.Publish(ctx, typ, payload)
+	}
+
+	q.Lock()
+	defer q.Unlock()
+	q.counts[typ]++
+	var initID string
+	switch p := payload.(type) {
+	case dsref.VersionInfo:
+		initID = p.InitID
+	case event.DsRename:
+		initID = p.InitID
+	case string:
+		initID = p
+	}
+	if initID != "" && !q.seen[initID] {
+		q.seen[initID] = true
+		q.initIDs = append(q.initIDs, initID)
+	}
+	return nil
+}
+
+// headVersionInfo describes the latest version of a dataset, returning
+// dsref.ErrRefNotFound if the dataset doesn't exist or has been deleted
+func (book *Book) headVersionInfo(ctx context.Context, initID string) (dsref.VersionInfo, error) {
+	dsLog, err := book.datasetLog(ctx, initID)
+	if errors.Is(err, oplog.ErrNotFound) || (err == nil && dsLog.l.Removed()) {
+		return dsref.VersionInfo{}, dsref.ErrRefNotFound
+	} else if err != nil {
+		return dsref.VersionInfo{}, err
+	}
+
+	ref, err := book.Ref(ctx, initID)
+	if err != nil {
+		return dsref.VersionInfo{}, err
+	}
+	branchLog, err := book.branchLog(ctx, initID)
+	if err != nil {
+		return dsref.VersionInfo{}, err
+	}
+
+	vi := dsref.VersionInfo{}
+	items := branchToVersionInfos(branchLog, ref, false, false)
+	if len(items) > 0 {
+		vi = items[len(items)-1]
+	}
+	vi.InitID = initID
+	vi.Username = ref.Username
+	vi.ProfileID = ref.ProfileID
+	vi.Name = ref.Name
+	vi.CommitCount = len(items)
+	return vi, nil
 }
 
 // load reads the book dataset from book.fsLocation
@@ -304,6 +501,8 @@ func (book *Book) WriteAuthorRename(ctx context.Context, author *profile.Profile
 		Name:      newName,
 		Timestamp: NewTimestamp(),
 	})
+	// references resolve by username, every entry for the author is stale
+	book.refs.reset()
 
 	if err := book.save(ctx, authorLog, nil); err != nil {
 		return err
@@ -367,8 +566,9 @@ func (book *Book) WriteDatasetInit(ctx context.Context, author *profile.Profile,
 	dsLog.AddChild(branch)
 	authorLog.AddChild(dsLog)
 	initID := dsLog.ID()
+	book.refs.set(author.Peername, dsName, dsLog)
 
-	err = book.publisher.Publish(ctx, event.ETDatasetNameInit, dsref.VersionInfo{
+	err = book.publish(ctx, event.ETDatasetNameInit, dsref.VersionInfo{
 		InitID:    initID,
 		Username:  author.Peername,
 		ProfileID: profileID,
@@ -409,7 +609,7 @@ func (book *Book) WriteDatasetRename(ctx context.Context, author *profile.Profil
 		Timestamp: NewTimestamp(),
 	})
 
-	err = book.publisher.Publish(ctx, event.ETDatasetRename, event.DsRename{
+	err = book.publish(ctx, event.ETDatasetRename, event.DsRename{
 		InitID:  initID,
 		OldName: oldName,
 		NewName: newName,
@@ -424,13 +624,15 @@ func (book *Book) WriteDatasetRename(ctx context.Context, author *profile.Profil
 	}
 
 	authorLog.AddChild(dsLog.l)
+	book.refs.remove(author.Peername, oldName)
+	book.refs.set(author.Peername, newName, dsLog.l)
 
 	return book.save(ctx, authorLog, nil)
 }
 
-// RefToInitID converts a dsref to an initID by iterating the entire logbook looking for a match.
-// This function is inefficient, iterating the entire set of operations in a log. Replacing this
-// function call with mechanisms in dscache will fix this problem.
+// RefToInitID converts a dsref to an initID. References are looked up in an
+// index of dataset logs, falling back to iterating the entire logbook looking
+// for a match when the index doesn't have the reference.
 // TODO(dustmop): Don't depend on this function permanently, use a higher level resolver and
 // convert all callers of this function to use that resolver's initID instead of converting a
 // dsref yet again.
@@ -442,6 +644,10 @@ func (book *Book) RefToInitID(ref dsref.Ref) (string, error) {
 	// NOTE: Bad to retrieve the background context here, but HeadRef just ignores it anyway.
 	ctx := context.Background()
 
+	if dsLog, ok := book.refs.get(ctx, book.store, ref.Username, ref.Name); ok {
+		return dsLog.ID(), nil
+	}
+
 	// HeadRef is inefficient, iterates the top two levels of the logbook.
 	// Runs in O(M*N) where M = number of users, N = number of datasets per user.
 	dsLog, err := book.store.HeadRef(ctx, ref.Username, ref.Name)
@@ -451,6 +657,7 @@ func (book *Book) RefToInitID(ref dsref.Ref) (string, error) {
 		}
 		return "", err
 	}
+	book.refs.set(ref.Username, ref.Name, dsLog)
 	return dsLog.ID(), nil
 }
 
@@ -540,8 +747,9 @@ func (book *Book) WriteDatasetDeleteAll(ctx context.Context, pro *profile.Profil
 		Model:     DatasetModel,
 		Timestamp: NewTimestamp(),
 	})
+	book.refs.remove(pro.Peername, dsLog.l.Name())
 
-	err = book.publisher.Publish(ctx, event.ETDatasetDeleteAll, initID)
+	err = book.publish(ctx, event.ETDatasetDeleteAll, initID)
 	if err != nil {
 		log.Error(err)
 	}
@@ -585,33 +793,104 @@ func (book *Book) WriteVersionSave(ctx context.Context, author *profile.Profile,
 	}
 
 	info := dsref.ConvertDatasetToVersionInfo(ds)
-	commitCount := int64(0)
-	for _, op := range branchLog.Ops() {
-		if op.Model == CommitModel {
-			switch op.Type {
-			case oplog.OpTypeInit:
-				commitCount++
-			case oplog.OpTypeAmend:
-				continue
-			case oplog.OpTypeRemove:
-				commitCount = commitCount - op.Size
-			}
-		}
-	}
-	info.CommitCount = int(commitCount)
+	info.CommitCount = commitCount(branchLog)
 	if rs != nil {
 		info.RunID = rs.ID
 		info.RunDuration = rs.Duration
 		info.RunStatus = string(rs.Status)
+		info.RunTrigger = rs.Trigger
 	}
 
-	if err = book.publisher.Publish(ctx, event.ETLogbookWriteCommit, info); err != nil {
+	if err = book.publish(ctx, event.ETLogbookWriteCommit, info); err != nil {
 		log.Error(err)
 	}
 
 	return nil
 }
 
+// AppendVersions adds a commit operation for each dataset in history to an
+// existing dataset log, writing the logbook once instead of once per
+// version. It's the batch form of WriteVersionSave for importing history
+// from another source. the given history MUST be ordered from oldest to
+// newest commits
+func (book *Book) AppendVersions(ctx context.Context, author *profile.Profile, initID string, history []*dataset.Dataset) error {
+	if book == nil {
+		return ErrNoLogbook
+	}
+
+	log.Debugw("AppendVersions", "authorID", author.ID.Encode(), "initID", initID, "versions", len(history))
+	branchLog, err := book.branchLog(ctx, initID)
+	if err != nil {
+		return err
+	}
+
+	if err := book.hasWriteAccess(ctx, branchLog.l, author); err != nil {
+		return err
+	}
+
+	for _, ds := range history {
+		if ds.Commit == nil {
+			return fmt.Errorf("version %q has no commit", ds.Path)
+		}
+	}
+
+	count := commitCount(branchLog)
+	for _, ds := range history {
+		book.appendVersionSave(branchLog, ds)
+	}
+	if err = book.save(ctx, nil, branchLog); err != nil {
+		return err
+	}
+
+	for _, ds := range history {
+		count++
+		info := dsref.ConvertDatasetToVersionInfo(ds)
+		info.InitID = initID
+		info.CommitCount = count
+		if err = book.publish(ctx, event.ETLogbookWriteCommit, info); err != nil {
+			log.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// CommitCount returns the number of live commits in a dataset's history,
+// without building the list of versions Items returns. Amended versions
+// count once, removed & squashed versions aren't counted
+func (book *Book) CommitCount(ctx context.Context, initID string) (int, error) {
+	if book == nil {
+		return 0, ErrNoLogbook
+	}
+	branchLog, err := book.branchLog(ctx, initID)
+	if err != nil {
+		return 0, err
+	}
+	return commitCount(branchLog), nil
+}
+
+// commitCount returns the number of commits in a branch log, accounting for
+// removed versions
+func commitCount(blog *BranchLog) int {
+	count := int64(0)
+	for _, op := range blog.Ops() {
+		if op.Model == CommitModel {
+			switch op.Type {
+			case oplog.OpTypeInit:
+				count++
+			case oplog.OpTypeAmend:
+				continue
+			case oplog.OpTypeRemove:
+				count = count - op.Size
+				if count < 0 {
+					count = 0
+				}
+			}
+		}
+	}
+	return int(count)
+}
+
 // WriteTransformRun adds an operation to a log marking the execution of a
 // dataset transform script
 func (book *Book) WriteTransformRun(ctx context.Context, author *profile.Profile, initID string, rs *run.State) error {
@@ -639,8 +918,9 @@ func (book *Book) WriteTransformRun(ctx context.Context, author *profile.Profile
 		RunStatus:   string(rs.Status),
 		RunDuration: rs.Duration,
 		RunStart:    rs.StartTime,
+		RunTrigger:  rs.Trigger,
 	}
-	if err = book.publisher.Publish(ctx, event.ETLogbookWriteRun, vi); err != nil {
+	if err = book.publish(ctx, event.ETLogbookWriteRun, vi); err != nil {
 		log.Error(err)
 	}
 	// TODO(dlong): Think about how to handle a failure exactly here, what needs to be rolled back?
@@ -682,6 +962,9 @@ func (book *Book) appendTransformRun(blog *BranchLog, rs *run.State) int {
 		Note: string(rs.Status),
 	}
 
+	if rs.Trigger != "" {
+		op.Relations = []string{fmt.Sprintf("%s%s", runTriggerRelPrefix, rs.Trigger)}
+	}
 	if rs.StartTime != nil {
 		op.Timestamp = rs.StartTime.UnixNano()
 	}
@@ -738,21 +1021,67 @@ func (book *Book) WriteVersionDelete(ctx context.Context, author *profile.Profil
 	}
 
 	branchLog.Append(oplog.Op{
-		Type:  oplog.OpTypeRemove,
-		Model: CommitModel,
-		Size:  int64(revisions),
+		Type:      oplog.OpTypeRemove,
+		Model:     CommitModel,
+		Size:      int64(revisions),
+		Timestamp: NewTimestamp(),
 		// TODO (b5) - finish
 	})
 
 	// Calculate the commits after collapsing deletions found at the tail of history (most recent).
-	items := branchToVersionInfos(branchLog, dsref.Ref{}, false)
+	items := branchToVersionInfos(branchLog, dsref.Ref{}, false, false)
 
 	if len(items) > 0 {
 		lastItem := items[len(items)-1]
 		lastItem.InitID = initID
 		lastItem.CommitCount = len(items)
 
-		if err = book.publisher.Publish(ctx, event.ETLogbookWriteCommit, lastItem); err != nil {
+		if err = book.publish(ctx, event.ETLogbookWriteCommit, lastItem); err != nil {
+			log.Error(err)
+		}
+	}
+
+	return book.save(ctx, nil, nil)
+}
+
+// WriteVersionSquash adds an operation to a log marking the oldest versions of
+// a branch as removed, leaving the version at basePath as the first version in
+// history. Squashes are recorded as remove operations named "squash", size is
+// the number of versions removed from the start of history
+func (book *Book) WriteVersionSquash(ctx context.Context, author *profile.Profile, initID string, squashed int, basePath string) error {
+	if book == nil {
+		return ErrNoLogbook
+	}
+	log.Debugf("WriteVersionSquash: %s, squashed: %d, base: %q", initID, squashed, basePath)
+	if squashed < 1 {
+		return fmt.Errorf("squash must remove at least one version")
+	}
+
+	branchLog, err := book.branchLog(ctx, initID)
+	if err != nil {
+		return err
+	}
+	if err := book.hasWriteAccess(ctx, branchLog.l, author); err != nil {
+		return err
+	}
+
+	branchLog.Append(oplog.Op{
+		Type:      oplog.OpTypeRemove,
+		Model:     CommitModel,
+		Name:      squashOpName,
+		Ref:       basePath,
+		Size:      int64(squashed),
+		Timestamp: NewTimestamp(),
+		Note:      fmt.Sprintf("squashed %d versions", squashed),
+	})
+
+	items := branchToVersionInfos(branchLog, dsref.Ref{}, false, false)
+	if len(items) > 0 {
+		head := items[0]
+		head.InitID = initID
+		head.CommitCount = len(items)
+
+		if err = book.publish(ctx, event.ETLogbookWriteCommit, head); err != nil {
 			log.Error(err)
 		}
 	}
@@ -760,6 +1089,12 @@ func (book *Book) WriteVersionDelete(ctx context.Context, author *profile.Profil
 	return book.save(ctx, nil, nil)
 }
 
+// IsSquashOp returns true for operations written by WriteVersionSquash, which
+// remove versions from the start of history instead of the end
+func IsSquashOp(op oplog.Op) bool {
+	return op.Model == CommitModel && op.Type == oplog.OpTypeRemove && op.Name == squashOpName
+}
+
 // WriteRemotePush adds an operation to a log marking the publication of a
 // number of versions to a remote address. It returns a rollback function that
 // removes the operation when called
@@ -882,6 +1217,34 @@ func (book Book) ListAllLogs(ctx context.Context) ([]*oplog.Log, error) {
 	return book.store.Logs(ctx, 0, -1)
 }
 
+// DatasetRefs lists a reference for every dataset in the logbook that hasn't
+// been removed. Refs have Username, ProfileID, Name and InitID set
+func (book Book) DatasetRefs(ctx context.Context) ([]dsref.Ref, error) {
+	logs, err := book.ListAllLogs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := []dsref.Ref{}
+	for _, userLog := range logs {
+		if userLog.Model() != UserModel || len(userLog.Ops) == 0 {
+			continue
+		}
+		for _, dsLog := range userLog.Logs {
+			if dsLog.Model() != DatasetModel || dsLog.Removed() {
+				continue
+			}
+			refs = append(refs, dsref.Ref{
+				Username:  userLog.Name(),
+				ProfileID: userLog.Ops[0].AuthorID,
+				Name:      dsLog.Name(),
+				InitID:    dsLog.ID(),
+			})
+		}
+	}
+	return refs, nil
+}
+
 // AllReferencedDatasetPaths scans an entire logbook looking for dataset paths
 func (book *Book) AllReferencedDatasetPaths(ctx context.Context) (map[string]struct{}, error) {
 	paths := map[string]struct{}{}
@@ -904,7 +1267,11 @@ func addReferencedPaths(log *oplog.Log, paths map[string]struct{}) {
 			case oplog.OpTypeInit:
 				ps = append(ps, op.Ref)
 			case oplog.OpTypeRemove:
-				ps = ps[:len(ps)-int(op.Size)]
+				if IsSquashOp(op) {
+					ps = ps[op.Size:]
+				} else {
+					ps = ps[:len(ps)-int(op.Size)]
+				}
 			case oplog.OpTypeAmend:
 				ps[len(ps)-1] = op.Ref
 			}
@@ -1015,7 +1382,7 @@ func (book *Book) latestSavePath(branchLog *oplog.Log) string {
 
 	for i := len(branchLog.Ops) - 1; i >= 0; i-- {
 		op := branchLog.Ops[i]
-		if op.Model == CommitModel {
+		if op.Model == CommitModel && !IsSquashOp(op) {
 			switch op.Type {
 			case oplog.OpTypeRemove:
 				removes += int(op.Size)
@@ -1095,8 +1462,8 @@ func (book Book) BranchRef(ctx context.Context, ref dsref.Ref) (*oplog.Log, erro
 }
 
 // LogBytes signs a log and writes it to a flatbuffer
-func (book Book) LogBytes(log *oplog.Log, signingKey crypto.PrivKey) ([]byte, error) {
-	if err := log.Sign(signingKey); err != nil {
+func (book Book) LogBytes(log *oplog.Log, signer key.Signer) ([]byte, error) {
+	if err := log.Sign(signer); err != nil {
 		return nil, err
 	}
 	return log.FlatbufferBytes(), nil
@@ -1143,6 +1510,7 @@ func (book *Book) MergeLog(ctx context.Context, sender crypto.PubKey, lg *oplog.
 	if err := book.store.MergeLog(ctx, lg); err != nil {
 		return err
 	}
+	book.refs.reset()
 
 	return book.save(ctx, nil, nil)
 }
@@ -1153,6 +1521,7 @@ func (book *Book) RemoveLog(ctx context.Context, ref dsref.Ref) error {
 		return ErrNoLogbook
 	}
 	book.store.RemoveLog(ctx, dsRefToLogPath(ref)...)
+	book.refs.reset()
 	return book.save(ctx, nil, nil)
 }
 
@@ -1182,18 +1551,20 @@ func (book *Book) ConstructDatasetLog(ctx context.Context, author *profile.Profi
 		return ErrLogTooShort
 	}
 
-	initID, err := book.WriteDatasetInit(ctx, author, ref.Name)
-	if err != nil {
-		return err
-	}
-	branchLog, err := book.branchLog(ctx, initID)
-	if err != nil {
-		return err
-	}
-	for _, ds := range history {
-		book.appendVersionSave(branchLog, ds)
-	}
-	return book.save(ctx, nil, nil)
+	return book.Transaction(ctx, func() error {
+		initID, err := book.WriteDatasetInit(ctx, author, ref.Name)
+		if err != nil {
+			return err
+		}
+		branchLog, err := book.branchLog(ctx, initID)
+		if err != nil {
+			return err
+		}
+		for _, ds := range history {
+			book.appendVersionSave(branchLog, ds)
+		}
+		return book.save(ctx, nil, nil)
+	})
 }
 
 func commitOpRunID(op oplog.Op) string {
@@ -1205,6 +1576,15 @@ func commitOpRunID(op oplog.Op) string {
 	return ""
 }
 
+func runOpTrigger(op oplog.Op) string {
+	for _, str := range op.Relations {
+		if strings.HasPrefix(str, runTriggerRelPrefix) {
+			return strings.TrimPrefix(str, runTriggerRelPrefix)
+		}
+	}
+	return ""
+}
+
 func versionInfoFromOp(ref dsref.Ref, op oplog.Op) dsref.VersionInfo {
 	return dsref.VersionInfo{
 		Username:    ref.Username,
@@ -1227,6 +1607,7 @@ func runItemFromOp(ref dsref.Ref, op oplog.Op) dsref.VersionInfo {
 		RunID:       op.Ref,
 		RunStatus:   op.Note,
 		RunDuration: int64(op.Size),
+		RunTrigger:  runOpTrigger(op),
 		// TODO(B5): When using qrimatic, I'd like to store the run number as a
 		// name string here, but we currently don't have a way to plumb a run number
 		// down from the qrimatic scheduler
@@ -1244,6 +1625,16 @@ func addCommitDetailsToRunItem(li dsref.VersionInfo, op oplog.Op) dsref.VersionI
 
 // Items collapses the history of a dataset branch into linear log items
 func (book Book) Items(ctx context.Context, ref dsref.Ref, offset, limit int, term string) ([]dsref.VersionInfo, error) {
+	return book.items(ctx, ref, offset, limit, term, false)
+}
+
+// ItemsWithDeleted is Items, keeping deleted versions in the list marked as
+// deleted instead of removing them, for auditing what was removed & when
+func (book Book) ItemsWithDeleted(ctx context.Context, ref dsref.Ref, offset, limit int, term string) ([]dsref.VersionInfo, error) {
+	return book.items(ctx, ref, offset, limit, term, true)
+}
+
+func (book Book) items(ctx context.Context, ref dsref.Ref, offset, limit int, term string, includeDeleted bool) ([]dsref.VersionInfo, error) {
 	initID, err := book.RefToInitID(dsref.Ref{Username: ref.Username, Name: ref.Name})
 	if err != nil {
 		return nil, err
@@ -1253,24 +1644,25 @@ func (book Book) Items(ctx context.Context, ref dsref.Ref, offset, limit int, te
 		return nil, err
 	}
 
-	return filteredBranchToVersionInfos(branchLog, ref, offset, limit, term, true), nil
+	return filteredBranchToVersionInfos(branchLog, ref, offset, limit, term, true, includeDeleted), nil
 }
 
 // ConvertLogsToVersionInfos collapses the history of a dataset branch into linear log items
 func ConvertLogsToVersionInfos(l *oplog.Log, ref dsref.Ref) []dsref.VersionInfo {
-	return branchToVersionInfos(newBranchLog(l), ref, true)
+	return branchToVersionInfos(newBranchLog(l), ref, true, false)
 }
 
 // filteredBranchToVersionInfos filters and paginates a branchLog as a list of
 // VersionInfos. If collapseAllDeletes is true, all delete operations will remove
 // the refs before them. Otherwise, only refs at the end of history will be removed
-// in this manner.
+// in this manner. If includeDeleted is true, deleted refs are kept & marked as
+// deleted instead.
 // TODO (ramfox): this is not the "optimal" way of doing filtering on the log, since
 // this version requires iterating over the full list after it has already been
 // generated. Can refactor for better performance (examining the log Model as we
 // iterate) in the future
-func filteredBranchToVersionInfos(blog *BranchLog, ref dsref.Ref, offset, limit int, term string, collapseAllDeletes bool) []dsref.VersionInfo {
-	refs := branchToVersionInfos(blog, ref, collapseAllDeletes)
+func filteredBranchToVersionInfos(blog *BranchLog, ref dsref.Ref, offset, limit int, term string, collapseAllDeletes, includeDeleted bool) []dsref.VersionInfo {
+	refs := branchToVersionInfos(blog, ref, collapseAllDeletes, includeDeleted)
 	filteredRefs := []dsref.VersionInfo{}
 
 	// TODO (ramfox): when we learn what other potential things a user could want
@@ -1304,10 +1696,12 @@ func filteredBranchToVersionInfos(blog *BranchLog, ref dsref.Ref, offset, limit
 	return refs
 }
 
-// branchToVersionInfos collapses the history of a dataset branch into linear log items
+// branchToVersionInfos collapses the history of a dataset branch into linear log items.
+// when includeDeleted is true delete operations mark versions as deleted
+// instead of collapsing them out of the list
 // If collapseAllDeletes is true, all delete operations will remove the refs before them. Otherwise,
 // only refs at the end of history will be removed in this manner.
-func branchToVersionInfos(blog *BranchLog, ref dsref.Ref, collapseAllDeletes bool) []dsref.VersionInfo {
+func branchToVersionInfos(blog *BranchLog, ref dsref.Ref, collapseAllDeletes, includeDeleted bool) []dsref.VersionInfo {
 	refs := []dsref.VersionInfo{}
 	deleteAtEnd := 0
 	for _, op := range blog.Ops() {
@@ -1321,16 +1715,22 @@ func branchToVersionInfos(blog *BranchLog, ref dsref.Ref, collapseAllDeletes boo
 				// from this commit, combine them into one Log item that describes both
 				// the run and the save
 				commitRunID := commitOpRunID(op)
-				if commitRunID != "" && len(refs) > 0 && commitRunID == refs[len(refs)-1].RunID {
-					refs[len(refs)-1] = addCommitDetailsToRunItem(refs[len(refs)-1], op)
+				if last := lastLiveVersion(refs); commitRunID != "" && last >= 0 && commitRunID == refs[last].RunID {
+					refs[last] = addCommitDetailsToRunItem(refs[last], op)
 				} else {
 					refs = append(refs, versionInfoFromOp(ref, op))
 				}
 			case oplog.OpTypeAmend:
 				deleteAtEnd = 0
-				refs[len(refs)-1] = versionInfoFromOp(ref, op)
+				if i := lastLiveVersion(refs); i >= 0 {
+					refs[i] = versionInfoFromOp(ref, op)
+				}
 			case oplog.OpTypeRemove:
-				if collapseAllDeletes {
+				if IsSquashOp(op) {
+					refs = dropOldestVersions(refs, int(op.Size))
+				} else if includeDeleted {
+					markDeletedVersions(refs, int(op.Size), op.Timestamp)
+				} else if collapseAllDeletes {
 					refs = refs[:len(refs)-int(op.Size)]
 				} else {
 					deleteAtEnd += int(op.Size)
@@ -1342,13 +1742,9 @@ func branchToVersionInfos(blog *BranchLog, ref dsref.Ref, collapseAllDeletes boo
 		case PushModel:
 			switch op.Type {
 			case oplog.OpTypeInit:
-				for i := 1; i <= int(op.Size); i++ {
-					refs[len(refs)-i].Published = true
-				}
+				setPublished(refs, int(op.Size), true)
 			case oplog.OpTypeRemove:
-				for i := 1; i <= int(op.Size); i++ {
-					refs[len(refs)-i].Published = false
-				}
+				setPublished(refs, int(op.Size), false)
 			}
 		}
 	}
@@ -1370,6 +1766,59 @@ func branchToVersionInfos(blog *BranchLog, ref dsref.Ref, collapseAllDeletes boo
 	return refs
 }
 
+// lastLiveVersion returns the index of the newest version in an oldest-first
+// list that isn't marked as deleted, or -1 if there isn't one
+func lastLiveVersion(refs []dsref.VersionInfo) int {
+	i := len(refs) - 1
+	for i >= 0 && refs[i].Deleted {
+		i--
+	}
+	return i
+}
+
+// setPublished sets the published state of the n newest versions that aren't
+// marked as deleted in an oldest-first list. deleted versions were removed
+// before the push was written, so they aren't part of it
+func setPublished(refs []dsref.VersionInfo, n int, published bool) {
+	for i := len(refs) - 1; i >= 0 && n > 0; i-- {
+		if refs[i].Deleted {
+			continue
+		}
+		refs[i].Published = published
+		n--
+	}
+}
+
+// markDeletedVersions marks the n newest versions that aren't already deleted
+// in an oldest-first list as deleted at timestamp
+func markDeletedVersions(refs []dsref.VersionInfo, n int, timestamp int64) {
+	for i := len(refs) - 1; i >= 0 && n > 0; i-- {
+		if refs[i].Deleted {
+			continue
+		}
+		refs[i].Deleted = true
+		if timestamp != 0 {
+			t := time.Unix(0, timestamp)
+			refs[i].DeleteTime = &t
+		}
+		n--
+	}
+}
+
+// dropOldestVersions removes n commits from the start of an oldest-first list,
+// along with any runs that were recorded before the last removed commit
+func dropOldestVersions(refs []dsref.VersionInfo, n int) []dsref.VersionInfo {
+	for i, vi := range refs {
+		if n == 0 {
+			return refs[i:]
+		}
+		if vi.Path != "" {
+			n--
+		}
+	}
+	return []dsref.VersionInfo{}
+}
+
 // LogEntry is a simplified representation of a log operation
 type LogEntry struct {
 	Timestamp time.Time
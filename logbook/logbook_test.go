@@ -228,6 +228,9 @@ func TestNilCallable(t *testing.T) {
 	if err = book.WriteVersionSave(ctx, nil, nil, nil); err != logbook.ErrNoLogbook {
 		t.Errorf("expected '%s', got: %v", logbook.ErrNoLogbook, err)
 	}
+	if err = book.Transaction(ctx, func() error { return nil }); err != logbook.ErrNoLogbook {
+		t.Errorf("expected '%s', got: %v", logbook.ErrNoLogbook, err)
+	}
 	if _, err = book.ResolveRef(ctx, nil); err != dsref.ErrRefNotFound {
 		t.Errorf("expected '%s', got: %v", dsref.ErrRefNotFound, err)
 	}
@@ -247,6 +250,243 @@ func TestResolveRef(t *testing.T) {
 	})
 }
 
+func TestRefToInitID(t *testing.T) {
+	ctx := context.Background()
+	owner := testProfile(t)
+	fs := &putCountFS{Filesystem: qfs.NewMemFS()}
+	book, err := logbook.NewJournal(*owner, event.NilBus, fs, "/mem/logbook.qfb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectInitID := func(username, name, expect string) {
+		t.Helper()
+		got, err := book.RefToInitID(dsref.Ref{Username: username, Name: name})
+		if expect == "" {
+			if !errors.Is(err, logbook.ErrNotFound) {
+				t.Errorf("expected %s/%s not to be found, got initID %q, err: %v", username, name, got, err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != expect {
+			t.Errorf("%s/%s initID mismatch. want: %q, got: %q", username, name, expect, got)
+		}
+	}
+
+	initID, err := book.WriteDatasetInit(ctx, owner, "first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectInitID("test_author", "first", initID)
+	expectInitID("test_author", "unknown", "")
+
+	if err := book.WriteDatasetRename(ctx, owner, initID, "renamed"); err != nil {
+		t.Fatal(err)
+	}
+	expectInitID("test_author", "first", "")
+	expectInitID("test_author", "renamed", initID)
+
+	if err := book.WriteAuthorRename(ctx, owner, "new_author"); err != nil {
+		t.Fatal(err)
+	}
+	expectInitID("test_author", "renamed", "")
+	expectInitID("new_author", "renamed", initID)
+
+	// a reloaded book indexes existing logs
+	if book, err = logbook.NewJournal(*owner, event.NilBus, fs, fs.lastPut); err != nil {
+		t.Fatal(err)
+	}
+	expectInitID("new_author", "renamed", initID)
+
+	if err := book.WriteDatasetDeleteAll(ctx, owner, initID); err != nil {
+		t.Fatal(err)
+	}
+	expectInitID("new_author", "renamed", "")
+
+	// a new dataset can reuse a deleted name
+	nextID, err := book.WriteDatasetInit(ctx, owner, "renamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectInitID("new_author", "renamed", nextID)
+
+	// removing logs drops them from the index. RemoveLog removes the first log
+	// with a name, here the deleted log
+	ref := dsref.Ref{Username: "new_author", Name: "renamed"}
+	if err := book.RemoveLog(ctx, ref); err != nil {
+		t.Fatal(err)
+	}
+	expectInitID("new_author", "renamed", nextID)
+	if err := book.RemoveLog(ctx, ref); err != nil {
+		t.Fatal(err)
+	}
+	expectInitID("new_author", "renamed", "")
+}
+
+// headRefCountStore counts calls to HeadRef
+type headRefCountStore struct {
+	oplog.Logstore
+	headRefs int
+}
+
+func (s *headRefCountStore) HeadRef(ctx context.Context, names ...string) (*oplog.Log, error) {
+	s.headRefs++
+	return s.Logstore.HeadRef(ctx, names...)
+}
+
+func TestRefToInitIDIndexHit(t *testing.T) {
+	ctx := context.Background()
+	owner := testProfile(t)
+	store := &headRefCountStore{Logstore: &oplog.Journal{}}
+	userLog := oplog.InitLog(oplog.Op{
+		Type:      oplog.OpTypeInit,
+		Model:     logbook.UserModel,
+		Name:      owner.Peername,
+		AuthorID:  owner.ID.Encode(),
+		Timestamp: logbook.NewTimestamp(),
+	})
+	if err := store.MergeLog(ctx, userLog); err != nil {
+		t.Fatal(err)
+	}
+	book := logbook.NewBook(*owner, event.NilBus, store)
+
+	initID, err := book.WriteDatasetInit(ctx, owner, "first")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.headRefs = 0
+	for i := 0; i < 2; i++ {
+		got, err := book.RefToInitID(dsref.Ref{Username: "test_author", Name: "first"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != initID {
+			t.Errorf("initID mismatch. want: %q, got: %q", initID, got)
+		}
+	}
+	if store.headRefs != 0 {
+		t.Errorf("expected indexed refs to resolve without calling HeadRef, got %d calls", store.headRefs)
+	}
+}
+
+func TestDatasetRefs(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	tr.WriteWorldBankExample(t)
+	tr.WriteRenameExample(t)
+
+	got, err := tr.Book.DatasetRefs(tr.Ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pid := tr.Owner.ID.Encode()
+	expect := []dsref.Ref{
+		{Username: tr.Owner.Peername, ProfileID: pid, Name: "world_bank_population", InitID: tr.WorldBankRef().InitID},
+		{Username: tr.Owner.Peername, ProfileID: pid, Name: "renamed_dataset", InitID: tr.RenameRef().InitID},
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestStrandedLogs(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	store := qfs.NewMemFS()
+	storedPath, err := store.Put(tr.Ctx, qfs.NewMemfileBytes("dataset.json", []byte(`{}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// head version QmHashOfVersion3 isn't in the store
+	tr.WriteWorldBankExample(t)
+
+	blankInitID, err := tr.Book.WriteDatasetInit(tr.Ctx, tr.Owner, "blank")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storedInitID, err := tr.Book.WriteDatasetInit(tr.Ctx, tr.Owner, "stored")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := &dataset.Dataset{
+		ID:       storedInitID,
+		Peername: tr.Owner.Peername,
+		Name:     "stored",
+		Commit:   &dataset.Commit{Title: "initial commit"},
+		Path:     storedPath,
+	}
+	if err := tr.Book.WriteVersionSave(tr.Ctx, tr.Owner, ds, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := tr.Book.StrandedLogs(tr.Ctx, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pid := tr.Owner.ID.Encode()
+	expect := []logbook.StrandedLog{
+		{
+			Ref:    dsref.Ref{Username: tr.Owner.Peername, ProfileID: pid, Name: "world_bank_population", InitID: tr.worldBankInitID, Path: "QmHashOfVersion3"},
+			Reason: logbook.StrandedMissingHead,
+		},
+		{
+			Ref:    dsref.Ref{Username: tr.Owner.Peername, ProfileID: pid, Name: "blank", InitID: blankInitID},
+			Reason: logbook.StrandedNoCommits,
+		},
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := tr.Book.RemoveStrandedLogs(tr.Ctx, got); err != nil {
+		t.Fatal(err)
+	}
+	if got, err = tr.Book.StrandedLogs(tr.Ctx, store); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no stranded logs after removal, got: %v", got)
+	}
+
+	refs, err := tr.Book.DatasetRefs(tr.Ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectRefs := []dsref.Ref{
+		{Username: tr.Owner.Peername, ProfileID: pid, Name: "stored", InitID: storedInitID},
+	}
+	if diff := cmp.Diff(expectRefs, refs); diff != "" {
+		t.Errorf("remaining refs mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := tr.Book.RefToInitID(dsref.Ref{Username: tr.Owner.Peername, Name: "blank"}); err == nil {
+		t.Error("expected removed stranded reference not to resolve")
+	}
+
+	// errors checking the store aren't mistaken for missing versions
+	if _, err := tr.Book.StrandedLogs(tr.Ctx, hasErrFS{store}); err == nil {
+		t.Error("expected an error checking the store to be returned")
+	}
+}
+
+// hasErrFS fails every Has check
+type hasErrFS struct {
+	qfs.Filesystem
+}
+
+func (hasErrFS) Has(ctx context.Context, path string) (bool, error) {
+	return false, fmt.Errorf("store unavailable")
+}
+
 func TestBookLogEntries(t *testing.T) {
 	tr, cleanup := newTestRunner(t)
 	defer cleanup()
@@ -272,7 +512,7 @@ func TestBookLogEntries(t *testing.T) {
 		"12:00AM\ttest_author\tsave commit\tadded body data",
 		"12:03AM\ttest_author\tpublish\t",
 		"12:04AM\ttest_author\tunpublish\t",
-		"12:00AM\ttest_author\tremove commit\t",
+		"12:05AM\ttest_author\tremove commit\t",
 		"12:00AM\ttest_author\tamend commit\tadded meta info",
 	}
 
@@ -309,6 +549,42 @@ func TestUserDatasetBranchesLog(t *testing.T) {
 	}
 }
 
+func TestPlainLogDOT(t *testing.T) {
+	lg := logbook.PlainLog{
+		Ops: []logbook.PlainOp{{Type: "init", Model: "user", Name: "b5"}},
+		Logs: []logbook.PlainLog{{
+			Ops: []logbook.PlainOp{{Type: "init", Model: "dataset", Name: "world_bank"}},
+			Logs: []logbook.PlainLog{{
+				Ops: []logbook.PlainOp{
+					{Type: "init", Model: "branch", Name: "main"},
+					{Type: "init", Model: "commit", Ref: "QmHashOfVersion1", Note: `initial "commit"`},
+				},
+			}},
+		}},
+	}
+
+	expect := `digraph oplog {
+  node [fontname="Helvetica" fontsize=10];
+  log [shape=box style=bold label="user\nb5"];
+  log_op0 [shape=ellipse label="init user\nb5"];
+  log -> log_op0 [style=dashed];
+  log_0 [shape=box style=bold label="dataset\nworld_bank"];
+  log_0_op0 [shape=ellipse label="init dataset\nworld_bank"];
+  log_0 -> log_0_op0 [style=dashed];
+  log_0_0 [shape=box style=bold label="branch\nmain"];
+  log_0_0_op0 [shape=ellipse label="init branch\nmain"];
+  log_0_0 -> log_0_0_op0 [style=dashed];
+  log_0_0_op1 [shape=ellipse label="init commit\nQmHashOfVersion1\ninitial \"commit\""];
+  log_0_0_op0 -> log_0_0_op1 [style=dashed];
+  log_0 -> log_0_0;
+  log -> log_0;
+}
+`
+	if diff := cmp.Diff(expect, lg.DOT()); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestLogBytes(t *testing.T) {
 	tr, cleanup := newTestRunner(t)
 	defer cleanup()
@@ -424,6 +700,9 @@ func TestWritePermissions(t *testing.T) {
 	if err := tr.Book.WriteVersionDelete(ctx, author, initID, 1); !errors.Is(err, logbook.ErrAccessDenied) {
 		t.Errorf("WriteVersionDelete to an oplog the book author doesn't own must return a wrap of logbook.ErrAccessDenied")
 	}
+	if err := tr.Book.AppendVersions(ctx, author, initID, []*dataset.Dataset{ds}); !errors.Is(err, logbook.ErrAccessDenied) {
+		t.Errorf("AppendVersions to an oplog the book author doesn't own must return a wrap of logbook.ErrAccessDenied")
+	}
 	if _, _, err := tr.Book.WriteRemotePush(ctx, author, initID, 1, "https://registry.example.com"); !errors.Is(err, logbook.ErrAccessDenied) {
 		t.Errorf("WriteRemotePush to an oplog the book author doesn't own must return a wrap of logbook.ErrAccessDenied")
 	}
@@ -928,6 +1207,181 @@ func TestItems(t *testing.T) {
 	}
 }
 
+func TestItemsWithDeleted(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	initID := tr.WriteWorldBankExample(t)
+	tr.WriteMoreWorldBankCommits(t, initID)
+	book := tr.Book
+
+	items, err := book.ItemsWithDeleted(tr.Ctx, tr.WorldBankRef(), 0, 10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := []string{}
+	for _, item := range items {
+		paths = append(paths, item.Path)
+	}
+	expectPaths := []string{"QmHashOfVersion5", "QmHashOfVersion4", "QmHashOfVersion2", "QmHashOfVersion3"}
+	if diff := cmp.Diff(expectPaths, paths); diff != "" {
+		t.Fatalf("paths mismatch (-want +got):\n%s", diff)
+	}
+	for i, item := range items {
+		deleted := item.Path == "QmHashOfVersion2"
+		if item.Deleted != deleted {
+			t.Errorf("item %d (%s): expected deleted to be %t", i, item.Path, deleted)
+		}
+		if deleted && item.DeleteTime == nil {
+			t.Errorf("item %d (%s): expected a delete time", i, item.Path)
+		}
+	}
+
+	items, err = book.Items(tr.Ctx, tr.WorldBankRef(), 0, 10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 {
+		t.Errorf("expected Items to leave out deleted versions, got %d items", len(items))
+	}
+}
+
+func TestItemsWithDeletedThenPublished(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	initID := tr.WriteWorldBankExample(t)
+	tr.WriteMoreWorldBankCommits(t, initID)
+	book := tr.Book
+
+	if err := book.WriteVersionDelete(tr.Ctx, tr.Owner, initID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := book.WriteRemotePush(tr.Ctx, tr.Owner, initID, 2, "https://registry.example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := book.ItemsWithDeleted(tr.Ctx, tr.WorldBankRef(), 0, 10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	type state struct {
+		Path      string
+		Deleted   bool
+		Published bool
+	}
+	got := []state{}
+	for _, item := range items {
+		got = append(got, state{item.Path, item.Deleted, item.Published})
+	}
+	expect := []state{
+		{"QmHashOfVersion5", true, false},
+		{"QmHashOfVersion4", false, true},
+		{"QmHashOfVersion2", true, false},
+		{"QmHashOfVersion3", false, true},
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("items mismatch (-want +got):\n%s", diff)
+	}
+
+	items, err = book.Items(tr.Ctx, tr.WorldBankRef(), 0, 10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range items {
+		if item.Path == "QmHashOfVersion4" && !item.Published {
+			t.Errorf("expected Items to list %s as published", item.Path)
+		}
+	}
+}
+
+func TestWriteVersionSquash(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	initID := tr.WriteWorldBankExample(t)
+	tr.WriteMoreWorldBankCommits(t, initID)
+	book := tr.Book
+
+	if err := book.WriteVersionSquash(tr.Ctx, tr.Owner, initID, 0, "QmHashOfVersion4"); err == nil {
+		t.Error("expected squashing zero versions to error")
+	}
+	if err := book.WriteVersionSquash(tr.Ctx, tr.Owner, initID, 1, "QmHashOfVersion4"); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := book.Items(tr.Ctx, tr.WorldBankRef(), 0, 10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, item := range items {
+		got = append(got, item.Path)
+	}
+	if diff := cmp.Diff([]string{"QmHashOfVersion5", "QmHashOfVersion4"}, got); diff != "" {
+		t.Errorf("item paths mismatch (-want +got):\n%s", diff)
+	}
+	if count, err := book.CommitCount(tr.Ctx, initID); err != nil {
+		t.Fatal(err)
+	} else if count != 2 {
+		t.Errorf("expected squash to leave 2 commits, got %d", count)
+	}
+
+	ref := dsref.Ref{Username: tr.Owner.Peername, Name: "world_bank_population"}
+	if _, err := book.ResolveRef(tr.Ctx, &ref); err != nil {
+		t.Fatal(err)
+	}
+	if ref.Path != "QmHashOfVersion5" {
+		t.Errorf("expected squash to leave head path unchanged. got: %q", ref.Path)
+	}
+
+	paths, err := book.AllReferencedDatasetPaths(tr.Ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := paths["QmHashOfVersion3"]; ok {
+		t.Error("expected squashed version not to be referenced")
+	}
+	if _, ok := paths["QmHashOfVersion4"]; !ok {
+		t.Error("expected squash base version to be referenced")
+	}
+}
+
+func TestCommitCount(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+	book := tr.Book
+
+	expectCount := func(initID string) {
+		t.Helper()
+		items, err := book.Items(tr.Ctx, tr.WorldBankRef(), 0, -1, "history")
+		if err != nil {
+			t.Fatal(err)
+		}
+		count, err := book.CommitCount(tr.Ctx, initID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != len(items) {
+			t.Errorf("expected commit count to match %d history items, got %d", len(items), count)
+		}
+	}
+
+	initID := tr.WriteWorldBankExample(t)
+	expectCount(initID)
+	tr.WriteMoreWorldBankCommits(t, initID)
+	expectCount(initID)
+	if err := book.WriteVersionDelete(tr.Ctx, tr.Owner, initID, 2); err != nil {
+		t.Fatal(err)
+	}
+	expectCount(initID)
+
+	if _, err := book.CommitCount(tr.Ctx, "unknown"); err == nil {
+		t.Error("expected counting commits of an unknown dataset to error")
+	}
+}
+
 func TestFilteredItems(t *testing.T) {
 	tr, cleanup := newTestRunner(t)
 	defer cleanup()
@@ -951,6 +1405,7 @@ func TestFilteredItems(t *testing.T) {
 			RunStart:    &run2Start,
 			RunDuration: time.Minute.Nanoseconds(),
 			RunID:       "run2",
+			RunTrigger:  "scheduled",
 		},
 		{
 			Username:    "test_author",
@@ -981,6 +1436,7 @@ func TestFilteredItems(t *testing.T) {
 			RunStart:    &run2Start,
 			RunDuration: time.Minute.Nanoseconds(),
 			RunID:       "run2",
+			RunTrigger:  "scheduled",
 		},
 		{
 			Username:    "test_author",
@@ -995,6 +1451,177 @@ func TestFilteredItems(t *testing.T) {
 	}
 }
 
+// putCountFS counts writes to a filesystem
+type putCountFS struct {
+	qfs.Filesystem
+	puts    int
+	lastPut string
+}
+
+func (fs *putCountFS) Put(ctx context.Context, f qfs.File) (path string, err error) {
+	fs.puts++
+	path, err = fs.Filesystem.Put(ctx, f)
+	fs.lastPut = path
+	return path, err
+}
+
+func TestTransaction(t *testing.T) {
+	ctx := context.Background()
+	owner := testProfile(t)
+	fs := &putCountFS{Filesystem: qfs.NewMemFS()}
+	book, err := logbook.NewJournal(*owner, event.NilBus, fs, "/mem/logbook.qfb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs.puts = 0
+
+	err = book.Transaction(ctx, func() error {
+		if _, err := book.WriteDatasetInit(ctx, owner, "first"); err != nil {
+			return err
+		}
+		return book.Transaction(ctx, func() error {
+			_, err := book.WriteDatasetInit(ctx, owner, "second")
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs.puts != 1 {
+		t.Errorf("expected a transaction to write the logbook once, got %d writes", fs.puts)
+	}
+
+	// a failed transaction still writes operations that completed
+	errFailed := fmt.Errorf("failed")
+	err = book.Transaction(ctx, func() error {
+		if _, err := book.WriteDatasetInit(ctx, owner, "third"); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Errorf("expected transaction error to be returned, got: %v", err)
+	}
+	if fs.puts != 2 {
+		t.Errorf("expected a failed transaction to write the logbook, got %d writes", fs.puts)
+	}
+
+	// writes outside a transaction aren't batched
+	if _, err := book.WriteDatasetInit(ctx, owner, "fourth"); err != nil {
+		t.Fatal(err)
+	}
+	if fs.puts != 3 {
+		t.Errorf("expected an unbatched write, got %d writes", fs.puts)
+	}
+
+	reloaded, err := logbook.NewJournal(*owner, event.NilBus, fs, fs.lastPut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs, err := reloaded.DatasetRefs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, ref := range refs {
+		got = append(got, ref.Name)
+	}
+	if diff := cmp.Diff([]string{"first", "second", "third", "fourth"}, got); diff != "" {
+		t.Errorf("reloaded dataset names mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTransactionZeroBook(t *testing.T) {
+	ctx := context.Background()
+	book := &logbook.Book{}
+	expect := fmt.Errorf("oh noes")
+	ran := false
+	err := book.Transaction(ctx, func() error {
+		ran = true
+		return expect
+	})
+	if !ran {
+		t.Error("expected transaction func to run")
+	}
+	if !errors.Is(err, expect) {
+		t.Errorf("error mismatch. want: %v, got: %v", expect, err)
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	owner := testProfile(t)
+	bus := event.NewBus(ctx)
+	book, err := logbook.NewJournal(*owner, bus, qfs.NewMemFS(), "/mem/logbook.qfb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := []event.Event{}
+	bus.SubscribeTypes(func(_ context.Context, e event.Event) error {
+		got = append(got, e)
+		return nil
+	}, event.ETDatasetNameInit, event.ETLogbookWriteCommit, event.ETLogbookBulkWrite)
+
+	var firstID, secondID, otherID string
+	err = book.Quiet(ctx, func(qctx context.Context) error {
+		if firstID, err = book.WriteDatasetInit(qctx, owner, "first"); err != nil {
+			return err
+		}
+		// writes that don't use the quiet context aren't held back
+		if otherID, err = book.WriteDatasetInit(ctx, owner, "other"); err != nil {
+			return err
+		}
+		return book.Quiet(qctx, func(qctx context.Context) error {
+			if secondID, err = book.WriteDatasetInit(qctx, owner, "second"); err != nil {
+				return err
+			}
+			return book.WriteDatasetDeleteAll(qctx, owner, secondID)
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected a quiet period to publish 2 events, got %d: %v", len(got), got)
+	}
+	if got[0].Type != event.ETDatasetNameInit || got[0].Payload.(dsref.VersionInfo).InitID != otherID {
+		t.Errorf("expected an init event for a write outside the quiet period, got %v", got[0])
+	}
+	if got[1].Type != event.ETLogbookBulkWrite {
+		t.Errorf("expected a bulk write event, got %q", got[1].Type)
+	}
+	expect := event.LogbookBulkWrite{
+		Events:  map[event.Type]int{event.ETDatasetNameInit: 2, event.ETDatasetDeleteAll: 1},
+		InitIDs: []string{firstID, secondID},
+		Versions: []dsref.VersionInfo{
+			{InitID: firstID, Username: owner.Peername, ProfileID: owner.ID.Encode(), Name: "first"},
+		},
+		Deleted: []string{secondID},
+	}
+	if diff := cmp.Diff(expect, got[1].Payload); diff != "" {
+		t.Errorf("summary mismatch (-want +got):\n%s", diff)
+	}
+
+	// a quiet period without events publishes nothing
+	if err := book.Quiet(ctx, func(context.Context) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("expected an empty quiet period not to publish, got %d events", len(got))
+	}
+
+	// events outside a quiet period are published
+	if _, err := book.WriteDatasetInit(ctx, owner, "third"); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[2].Type != event.ETDatasetNameInit {
+		t.Errorf("expected an init event to be published, got: %v", got)
+	}
+}
+
 func TestConstructDatasetLog(t *testing.T) {
 	tr, cleanup := newTestRunner(t)
 	defer cleanup()
@@ -1057,6 +1684,78 @@ func TestConstructDatasetLog(t *testing.T) {
 	}
 }
 
+func TestAppendVersions(t *testing.T) {
+	ctx := context.Background()
+	owner := testProfile(t)
+	fs := &putCountFS{Filesystem: qfs.NewMemFS()}
+	book, err := logbook.NewJournal(*owner, event.NilBus, fs, "/mem/logbook.qfb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := "imported"
+	initID, err := book.WriteDatasetInit(ctx, owner, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := &dataset.Dataset{
+		ID:       initID,
+		Peername: owner.Peername,
+		Name:     name,
+		Commit: &dataset.Commit{
+			Timestamp: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Title:     "initial commit",
+		},
+		Path: "HashOfVersion1",
+	}
+	if err := book.WriteVersionSave(ctx, owner, ds, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	history := []*dataset.Dataset{}
+	for i := 2; i <= 4; i++ {
+		history = append(history, &dataset.Dataset{
+			Peername: owner.Peername,
+			Name:     name,
+			Commit: &dataset.Commit{
+				Timestamp: time.Date(2000, time.January, i, 0, 0, 0, 0, time.UTC),
+				Title:     fmt.Sprintf("commit %d", i),
+			},
+			Path:         fmt.Sprintf("HashOfVersion%d", i),
+			PreviousPath: fmt.Sprintf("HashOfVersion%d", i-1),
+		})
+	}
+
+	fs.puts = 0
+	if err := book.AppendVersions(ctx, owner, initID, history); err != nil {
+		t.Fatal(err)
+	}
+	if fs.puts != 1 {
+		t.Errorf("expected appending versions to write the logbook once, got %d writes", fs.puts)
+	}
+
+	items, err := book.Items(ctx, dsref.Ref{Username: owner.Peername, Name: name}, 0, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, item := range items {
+		got = append(got, item.Path)
+	}
+	expect := []string{"HashOfVersion4", "HashOfVersion3", "HashOfVersion2", "HashOfVersion1"}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("version paths mismatch (-want +got):\n%s", diff)
+	}
+
+	// a version without a commit writes nothing
+	err = book.AppendVersions(ctx, owner, initID, []*dataset.Dataset{{Path: "HashOfVersion5", Commit: &dataset.Commit{}}, {Path: "HashOfVersion6"}})
+	if expectErr := `version "HashOfVersion6" has no commit`; err == nil || err.Error() != expectErr {
+		t.Errorf("error mismatch. want: %q, got: %v", expectErr, err)
+	}
+	if items, _ = book.Items(ctx, dsref.Ref{Username: owner.Peername, Name: name}, 0, 100, ""); len(items) != 4 {
+		t.Errorf("expected a failed append to add no versions, got %d versions", len(items))
+	}
+}
+
 func mustTime(str string) time.Time {
 	t, err := time.Parse(time.RFC3339, str)
 	if err != nil {
@@ -1288,6 +1987,7 @@ func (tr *testRunner) WriteBabyNamesExample(t *testing.T) string {
 
 	rs.ID = "run2"
 	rs.Number = 2
+	rs.Trigger = run.TriggerScheduled
 	rs.StopTime = &ds.Commit.Timestamp
 	rs.Duration = time.Minute.Nanoseconds()
 	runStart = time.Date(2000, time.January, 1, 4, 0, 0, 0, time.UTC)
@@ -1447,7 +2147,7 @@ func (tr *testRunner) WorldBankPlainLog() logbook.PlainLog {
 							{
 								Type:      "remove",
 								Model:     "commit",
-								Timestamp: mustTime("1969-12-31T19:00:00-05:00"),
+								Timestamp: mustTime("1999-12-31T19:05:00-05:00"),
 								Size:      1,
 							},
 							{
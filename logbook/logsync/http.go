@@ -98,6 +98,10 @@ func (c *httpClient) get(ctx context.Context, author profile.Author, ref dsref.R
 		return nil, nil, err
 	}
 
+	if res.StatusCode == http.StatusNotFound {
+		// the other end of the wire doesn't have a log for this ref
+		return nil, nil, logbook.ErrNotFound
+	}
 	if res.StatusCode != http.StatusOK {
 		log.Debugf("httpClient.get statusCode=%d", res.StatusCode)
 		if errmsg, err := ioutil.ReadAll(res.Body); err == nil {
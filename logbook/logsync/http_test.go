@@ -1,6 +1,7 @@
 package logsync
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -8,6 +9,7 @@ import (
 
 	cmp "github.com/google/go-cmp/cmp"
 	"github.com/qri-io/qri/dsref"
+	"github.com/qri-io/qri/logbook"
 	"github.com/qri-io/qri/profile"
 )
 
@@ -118,8 +120,8 @@ func TestHTTPClientErrors(t *testing.T) {
 	defer server.Close()
 
 	c.URL = server.URL
-	if _, _, err := c.get(tr.Ctx, authorA, dsref.Ref{}); err == nil {
-		t.Error("expected error to exist")
+	if _, _, err := c.get(tr.Ctx, authorA, dsref.Ref{}); !errors.Is(err, logbook.ErrNotFound) {
+		t.Errorf("expected a not found response to return logbook.ErrNotFound, got: %v", err)
 	}
 
 	if err := c.put(tr.Ctx, authorA, dsref.Ref{}, nil); err == nil {
@@ -279,7 +279,7 @@ func (lsync *Logsync) get(ctx context.Context, author profile.Author, ref dsref.
 		return lsync.Author(), nil, err
 	}
 
-	data, err := lsync.book.LogBytes(l, lsync.book.Owner().PrivKey)
+	data, err := lsync.book.LogBytes(l, lsync.book.Owner().GetSigner())
 	if err != nil {
 		log.Debugf("LogBytes error=%q initID=%q", err, ref.InitID)
 		return nil, nil, err
@@ -356,7 +356,7 @@ func (p *Push) Do(ctx context.Context) error {
 		return err
 	}
 
-	data, err := p.book.LogBytes(l, p.book.Owner().PrivKey)
+	data, err := p.book.LogBytes(l, p.book.Owner().GetSigner())
 	if err != nil {
 		if rollbackErr := rollback(ctx); rollbackErr != nil {
 			log.Errorf("rolling back dataset log: %q", rollbackErr)
@@ -24,6 +24,7 @@ import (
 
 	flatbuffers "github.com/google/flatbuffers/go"
 	crypto "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/qri-io/qri/auth/key"
 	"github.com/qri-io/qri/logbook/oplog/logfb"
 	"golang.org/x/crypto/blake2b"
 )
@@ -661,11 +662,11 @@ func (lg Log) Verify(pub crypto.PubKey) error {
 }
 
 // Sign assigns the log signature by signing the logging checksum with a given
-// private key
+// signer, usually a private key
 // TODO (b5) - this is assuming the log is authored by this private key. as soon
 // as we add collaborators, this won't be true
-func (lg *Log) Sign(pk crypto.PrivKey) (err error) {
-	lg.Signature, err = pk.Sign(lg.SigningBytes())
+func (lg *Log) Sign(signer key.Signer) (err error) {
+	lg.Signature, err = signer.Sign(lg.SigningBytes())
 	if err != nil {
 		return err
 	}
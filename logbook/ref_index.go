@@ -0,0 +1,118 @@
+package logbook
+
+import (
+	"context"
+	"sync"
+
+	"github.com/qri-io/qri/logbook/oplog"
+)
+
+// refIndex maps "username/name" dataset references to dataset logs, so
+// resolving a reference doesn't walk every log in the store. Entries are
+// checked against the name & removal status of their log before they're
+// used. Removing logs and changes that can replace or re-parent logs, like
+// merges, drop the whole index, which is rebuilt on the next lookup
+type refIndex struct {
+	sync.Mutex
+	logs map[string]*oplog.Log
+}
+
+func refIndexKey(username, name string) string {
+	return username + "/" + name
+}
+
+// index builds the index from the logs in store
+func (idx *refIndex) index(ctx context.Context, store oplog.Logstore) {
+	if idx == nil {
+		return
+	}
+	idx.Lock()
+	defer idx.Unlock()
+	idx.build(ctx, store)
+}
+
+// get returns the dataset log for a reference, building the index if it's
+// been dropped
+func (idx *refIndex) get(ctx context.Context, store oplog.Logstore, username, name string) (*oplog.Log, bool) {
+	if idx == nil {
+		return nil, false
+	}
+	idx.Lock()
+	defer idx.Unlock()
+	if idx.logs == nil {
+		idx.build(ctx, store)
+	}
+
+	key := refIndexKey(username, name)
+	l, ok := idx.logs[key]
+	if !ok {
+		return nil, false
+	}
+	if l.Name() != name || l.Removed() {
+		delete(idx.logs, key)
+		return nil, false
+	}
+	return l, true
+}
+
+// build indexes the datasets of each user log, mirroring the order HeadRef
+// searches logs in: the first user log with a name wins, and within it the
+// first dataset log with a name. callers must hold the lock
+func (idx *refIndex) build(ctx context.Context, store oplog.Logstore) {
+	idx.logs = map[string]*oplog.Log{}
+	logs, err := store.Logs(ctx, 0, -1)
+	if err != nil {
+		log.Debugw("building ref index", "err", err)
+		return
+	}
+
+	seenUsers := map[string]bool{}
+	for _, ul := range logs {
+		if ul.Model() != UserModel || ul.Removed() || seenUsers[ul.Name()] {
+			continue
+		}
+		username := ul.Name()
+		seenUsers[username] = true
+		for _, dsl := range ul.Logs {
+			if dsl.Model() != DatasetModel || dsl.Removed() {
+				continue
+			}
+			key := refIndexKey(username, dsl.Name())
+			if _, ok := idx.logs[key]; !ok {
+				idx.logs[key] = dsl
+			}
+		}
+	}
+}
+
+// set records the dataset log a reference resolves to
+func (idx *refIndex) set(username, name string, l *oplog.Log) {
+	if idx == nil {
+		return
+	}
+	idx.Lock()
+	defer idx.Unlock()
+	if idx.logs != nil {
+		idx.logs[refIndexKey(username, name)] = l
+	}
+}
+
+// remove drops a reference from the index
+func (idx *refIndex) remove(username, name string) {
+	if idx == nil {
+		return
+	}
+	idx.Lock()
+	defer idx.Unlock()
+	delete(idx.logs, refIndexKey(username, name))
+}
+
+// reset drops the index, rebuilding it on the next lookup
+func (idx *refIndex) reset() {
+	if idx == nil {
+		return
+	}
+	idx.Lock()
+	defer idx.Unlock()
+	idx.logs = nil
+}
@@ -40,6 +40,36 @@ func (node *QriNode) MissingManifest(ctx context.Context, m *dag.Manifest) (miss
 	return dag.Missing(ctx, ng, m)
 }
 
+// BlockSizes returns the stored size in bytes of each block in the DAG rooted
+// at path, keyed by block CID. Unlike the sizes in a dag.Info, which are
+// cumulative sizes of a node and all its descendants, these are the sizes of
+// individual blocks, and can be summed to get the space a DAG occupies
+func (node *QriNode) BlockSizes(ctx context.Context, path string) (map[string]uint64, error) {
+	ng, err := newNodeGetter(node)
+	if err != nil {
+		return nil, err
+	}
+
+	mfst, err := node.NewManifest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]uint64, len(mfst.Nodes))
+	for _, idStr := range mfst.Nodes {
+		id, err := cid.Parse(idStr)
+		if err != nil {
+			return nil, err
+		}
+		nd, err := ng.Get(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("getting block %s: %w", idStr, err)
+		}
+		sizes[idStr] = uint64(len(nd.RawData()))
+	}
+	return sizes, nil
+}
+
 // NewDAGInfo generates a DAGInfo for a given node. If a label is given, it will generate a sub-DAGInfo at thea label.
 func (node *QriNode) NewDAGInfo(ctx context.Context, path, label string) (*dag.Info, error) {
 	ng, err := newNodeGetter(node)
@@ -110,3 +110,35 @@ func TestNewDAGInfo(t *testing.T) {
 		t.Errorf("result mismatch. (-want +got):\n%s", diff)
 	}
 }
+
+func TestBlockSizes(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	node := tr.IPFSBackedQriNode(t, "dag_tests_peer")
+	ref := writeWorldBankPopulation(tr.Ctx, t, node.Repo)
+
+	sizes, err := node.BlockSizes(tr.Ctx, ref.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	di, err := node.NewDAGInfo(tr.Ctx, ref.Path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sizes) != len(di.Manifest.Nodes) {
+		t.Fatalf("block count mismatch. want: %d, got: %d", len(di.Manifest.Nodes), len(sizes))
+	}
+
+	// leaf blocks have no links, so their individual sizes should match the
+	// cumulative sizes reported by dag info
+	for i, id := range di.Manifest.Nodes[1:] {
+		if sizes[id] != di.Sizes[i+1] {
+			t.Errorf("leaf block %s size mismatch. want: %d, got: %d", id, di.Sizes[i+1], sizes[id])
+		}
+	}
+	if sizes[di.Manifest.Nodes[0]] == 0 {
+		t.Errorf("expected root block to have a non-zero size")
+	}
+}
@@ -21,6 +21,11 @@ type Profile struct {
 	// All Profiles are built on public key infrastructure
 	// PrivKey is the peer's private key, should only be present for the current peer
 	PrivKey crypto.PrivKey `json:"_,omitempty"`
+	// Signer signs commits & logs on behalf of the peer in place of PrivKey.
+	// it only replaces PrivKey for signing: loading the profile, encrypting
+	// the logbook & creating access tokens still need PrivKey. Signer is
+	// never serialized
+	Signer key.Signer `json:"-"`
 	// PubKey is the peer's public key
 	PubKey crypto.PubKey `json:"key,omitempty"`
 	// KeyID is the key identifier used for the keystore
@@ -210,6 +215,18 @@ func (p *Profile) ValidOwnerProfile() error {
 	return nil
 }
 
+// GetSigner returns the profile Signer, falling back to the private key if
+// no signer is present
+func (p *Profile) GetSigner() key.Signer {
+	if p.Signer != nil {
+		return p.Signer
+	}
+	if p.PrivKey == nil {
+		return nil
+	}
+	return p.PrivKey
+}
+
 // GetKeyID returns a KeyID assigned to the profile or falls back
 // to the profile ID if none is present
 func (p *Profile) GetKeyID() key.ID {
@@ -1,6 +1,7 @@
 package remote
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -10,9 +11,12 @@ import (
 	"strings"
 	"time"
 
+	cid "github.com/ipfs/go-cid"
 	coreiface "github.com/ipfs/interface-go-ipfs-core"
+	options "github.com/ipfs/interface-go-ipfs-core/options"
 	crypto "github.com/libp2p/go-libp2p-core/crypto"
 	peer "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/qri-io/dag"
 	"github.com/qri-io/dag/dsync"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/qfs"
@@ -35,6 +39,9 @@ var (
 	ErrNoRemoteClient = fmt.Errorf("remote: no client to make remote requests")
 	// ErrRemoteNotFound indicates a specified remote couldn't be located
 	ErrRemoteNotFound = fmt.Errorf("remote not found")
+	// ErrMissingBlocks indicates a remote doesn't have every block of a dataset
+	// version, which happens when a push only partially completes
+	ErrMissingBlocks = fmt.Errorf("remote is missing blocks")
 )
 
 // ClientConstructor is a factory function that creates client implementations
@@ -61,9 +68,19 @@ type Client interface {
 	// PushDataset synchronizes a dataset with a remote, synchronizing logbook
 	// data  and pulling the dataset version specified by ref.Path
 	PushDataset(ctx context.Context, ref dsref.Ref, remoteAddr string) error
+	// VerifyDatasetVersion confirms a remote stores every block of the dataset
+	// version specified by ref.Path, returning ErrMissingBlocks if it doesn't
+	VerifyDatasetVersion(ctx context.Context, ref dsref.Ref, remoteAddr string) error
 	// PullDataset fetches & stores a dataset from a remote, synchronizing logbook
 	// data and pulling the dataset version data associated with ref.Path
 	PullDataset(ctx context.Context, ref *dsref.Ref, remoteAddr string) (*dataset.Dataset, error)
+	// PullDatasetMetadata fetches & stores a "headless" dataset version from a
+	// remote: logbook data and every component of the version except the body
+	PullDatasetMetadata(ctx context.Context, ref *dsref.Ref, remoteAddr string) (*dataset.Dataset, error)
+	// ResumePullDataset fetches & stores a dataset from a remote like
+	// PullDataset, only requesting version blocks that aren't already stored
+	// locally. Rerunning an interrupted pull skips blocks that already landed
+	ResumePullDataset(ctx context.Context, ref *dsref.Ref, remoteAddr string) (*dataset.Dataset, error)
 	// RemoveDataset removes a dataset from a remote entirely, delete logbook data
 	// on the remote and requesting the remote drop all stored dataset versions
 	RemoveDataset(ctx context.Context, ref dsref.Ref, remoteAddr string) error
@@ -487,6 +504,67 @@ func (c *client) pushDatasetVersion(ctx context.Context, ref dsref.Ref, remoteAd
 	return c.events.Publish(ctx, event.ETRemoteClientPushVersionCompleted, progEvt)
 }
 
+// VerifyDatasetVersion fetches the manifest of a dataset version from a remote,
+// checking it lists every block in the local manifest of the same version
+func (c *client) VerifyDatasetVersion(ctx context.Context, ref dsref.Ref, remoteAddr string) error {
+	log.Debugf("client.VerifyDatasetVersion ref=%q remoteAddr=%q", ref, remoteAddr)
+	if c == nil {
+		return ErrNoRemoteClient
+	}
+	if c.capi == nil {
+		return fmt.Errorf("remote: cannot verify, missing IPFS subsystem")
+	}
+	if addressType(remoteAddr) != "http" {
+		return fmt.Errorf("push verification is only supported over HTTP")
+	}
+
+	id, err := cid.Parse(ref.Path)
+	if err != nil {
+		return err
+	}
+	lng, err := dsync.NewLocalNodeGetter(c.capi)
+	if err != nil {
+		return err
+	}
+	local, err := dag.NewManifest(ctx, lng, id)
+	if err != nil {
+		return fmt.Errorf("building local manifest: %w", err)
+	}
+
+	params, err := sigParams(c.pk, c.profile.Peername, ref)
+	if err != nil {
+		return err
+	}
+	rem := &dsync.HTTPClient{URL: remoteAddr + "/remote/dsync"}
+	info, err := rem.GetDagInfo(ctx, ref.Path, params)
+	if err != nil {
+		// remotes can't build a manifest for a DAG they don't fully have
+		return fmt.Errorf("%w: fetching remote manifest: %s", ErrMissingBlocks, err)
+	}
+
+	if missing := missingBlocks(local, info.Manifest); len(missing) > 0 {
+		return fmt.Errorf("%w: %d of %d blocks not found, first missing block: %s", ErrMissingBlocks, len(missing), len(local.Nodes), missing[0])
+	}
+	return nil
+}
+
+// missingBlocks lists the nodes of a manifest that aren't present in another
+func missingBlocks(want, have *dag.Manifest) []string {
+	found := map[string]bool{}
+	if have != nil {
+		for _, id := range have.Nodes {
+			found[id] = true
+		}
+	}
+	var missing []string
+	for _, id := range want.Nodes {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
 // PullDataset fetches & pins a dataset to the store, adding it to the list of
 // stored refs
 func (c *client) PullDataset(ctx context.Context, ref *dsref.Ref, remoteAddr string) (ds *dataset.Dataset, err error) {
@@ -511,6 +589,65 @@ func (c *client) PullDataset(ctx context.Context, ref *dsref.Ref, remoteAddr str
 	}
 	node.LocalStreams.PrintErr(fmt.Sprintf("🗼 fetched from remote %q\n", remoteAddr))
 
+	return c.storePulledVersion(ctx, ref, remoteAddr)
+}
+
+// PullDatasetMetadata fetches & pins the non-body blocks of a dataset version,
+// adding it to the list of stored refs. The body of the resulting version isn't
+// available locally until it's fetched with PullDataset
+func (c *client) PullDatasetMetadata(ctx context.Context, ref *dsref.Ref, remoteAddr string) (ds *dataset.Dataset, err error) {
+	log.Debugf("client.PullDatasetMetadata ref=%q addr=%q", ref, remoteAddr)
+	if c == nil {
+		return nil, ErrNoRemoteClient
+	}
+	if c.capi == nil {
+		return nil, fmt.Errorf("remote: cannot pull, missing IPFS subsystem")
+	}
+
+	if err := c.pullLogs(ctx, *ref, remoteAddr); err != nil {
+		log.Debugf("client.pullLogs error=%q", err)
+		return nil, err
+	}
+
+	if err := c.pullDatasetMetadata(ctx, ref, remoteAddr); err != nil {
+		log.Debugf("client.pullDatasetMetadata error=%q", err)
+		return nil, err
+	}
+	c.node.LocalStreams.PrintErr(fmt.Sprintf("🗼 fetched metadata from remote %q\n", remoteAddr))
+
+	return c.storePulledVersion(ctx, ref, remoteAddr)
+}
+
+// ResumePullDataset fetches & pins the blocks of a dataset version that
+// aren't stored locally, adding it to the list of stored refs. Each block is
+// pinned as it lands, so blocks fetched by an interrupted pull are kept
+func (c *client) ResumePullDataset(ctx context.Context, ref *dsref.Ref, remoteAddr string) (ds *dataset.Dataset, err error) {
+	log.Debugf("client.ResumePullDataset ref=%q addr=%q", ref, remoteAddr)
+	if c == nil {
+		return nil, ErrNoRemoteClient
+	}
+	if c.capi == nil {
+		return nil, fmt.Errorf("remote: cannot pull, missing IPFS subsystem")
+	}
+
+	if err := c.pullLogs(ctx, *ref, remoteAddr); err != nil {
+		log.Debugf("client.pullLogs error=%q", err)
+		return nil, err
+	}
+
+	if err := c.resumePullDatasetVersion(ctx, ref, remoteAddr); err != nil {
+		log.Debugf("client.resumePullDatasetVersion error=%q", err)
+		return nil, err
+	}
+	c.node.LocalStreams.PrintErr(fmt.Sprintf("🗼 fetched from remote %q\n", remoteAddr))
+
+	return c.storePulledVersion(ctx, ref, remoteAddr)
+}
+
+// storePulledVersion finishes a pull, adding a fetched version to the repo if
+// it's more recent than any existing reference
+func (c *client) storePulledVersion(ctx context.Context, ref *dsref.Ref, remoteAddr string) (ds *dataset.Dataset, err error) {
+	node := c.node
 	err = c.events.Publish(ctx, event.ETRemoteClientPullDatasetCompleted, event.RemoteEvent{
 		Ref:        *ref,
 		RemoteAddr: remoteAddr,
@@ -659,6 +796,175 @@ func (c *client) pullDatasetVersion(ctx context.Context, ref *dsref.Ref, remoteA
 	return c.events.Publish(ctx, event.ETRemoteClientPullVersionCompleted, progEvt)
 }
 
+// pullDatasetMetadata fetches every block of a dataset version from a remote
+// except the blocks that make up the body. Blocks are pinned individually,
+// leaving the DAG incomplete until the body is pulled
+func (c *client) pullDatasetMetadata(ctx context.Context, ref *dsref.Ref, remoteAddr string) error {
+	log.Debugf("client.pullDatasetMetadata: ref=%q remoteAddr=%q", ref, remoteAddr)
+
+	if addressType(remoteAddr) != "http" {
+		return fmt.Errorf("metadata-only pulls are only supported over HTTP")
+	}
+
+	if ref.Path == "" {
+		if _, err := c.NewRemoteRefResolver(remoteAddr).ResolveRef(ctx, ref); err != nil {
+			log.Errorf("resolving head ref: %s", err.Error())
+			return err
+		}
+	}
+
+	params, err := sigParams(c.pk, c.profile.Peername, *ref)
+	if err != nil {
+		log.Debugf("generating sig params error=%q ", err)
+		return err
+	}
+
+	rem := &dsync.HTTPClient{URL: remoteAddr + "/remote/dsync"}
+	info, err := rem.GetDagInfo(ctx, ref.Path, params)
+	if err != nil {
+		return err
+	}
+	mfst := info.Manifest
+	if len(mfst.Nodes) == 0 {
+		return fmt.Errorf("remote returned an empty manifest for %q", ref.Path)
+	}
+
+	// the root block lists the files in the dataset package, fetch it first to
+	// learn which link is the body
+	if err := c.putRemoteBlock(ctx, rem, mfst.Nodes[0]); err != nil {
+		return err
+	}
+	root, err := c.capi.Dag().Get(ctx, mfst.RootCID())
+	if err != nil {
+		return err
+	}
+	skip := map[int]bool{}
+	for _, lnk := range root.Links() {
+		if strings.HasPrefix(lnk.Name, "body.") {
+			skip[mfst.IDIndex(lnk.Cid.String())] = true
+		}
+	}
+
+	for _, idx := range metadataNodes(mfst, skip)[1:] {
+		if err := c.putRemoteBlock(ctx, rem, mfst.Nodes[idx]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resumePullDatasetVersion diffs the manifest of a dataset version on a remote
+// against local block presence, fetching only the missing blocks
+func (c *client) resumePullDatasetVersion(ctx context.Context, ref *dsref.Ref, remoteAddr string) error {
+	log.Debugf("client.resumePullDatasetVersion: ref=%q remoteAddr=%q", ref, remoteAddr)
+
+	if addressType(remoteAddr) != "http" {
+		return fmt.Errorf("resuming pulls is only supported over HTTP")
+	}
+
+	if ref.Path == "" {
+		if _, err := c.NewRemoteRefResolver(remoteAddr).ResolveRef(ctx, ref); err != nil {
+			log.Errorf("resolving head ref: %s", err.Error())
+			return err
+		}
+	}
+
+	params, err := sigParams(c.pk, c.profile.Peername, *ref)
+	if err != nil {
+		log.Debugf("generating sig params error=%q ", err)
+		return err
+	}
+
+	rem := &dsync.HTTPClient{URL: remoteAddr + "/remote/dsync"}
+	info, err := rem.GetDagInfo(ctx, ref.Path, params)
+	if err != nil {
+		return err
+	}
+
+	missing, err := localMissingBlocks(ctx, c.node.Repo.Filesystem(), info.Manifest)
+	if err != nil {
+		return err
+	}
+	log.Debugf("resuming pull of %q, %d of %d blocks missing", ref.Path, len(missing.Nodes), len(info.Manifest.Nodes))
+
+	progEvt := event.RemoteEvent{
+		Ref:        *ref,
+		RemoteAddr: remoteAddr,
+		Progress:   dag.NewCompletion(info.Manifest, missing),
+	}
+	for _, id := range missing.Nodes {
+		if err := c.putRemoteBlock(ctx, rem, id); err != nil {
+			return err
+		}
+		progEvt.Progress[info.Manifest.IDIndex(id)] = 100
+		if err := c.events.Publish(ctx, event.ETRemoteClientPullVersionProgress, progEvt); err != nil {
+			log.Debugw("ignored error while publishing pullVersionProgress", "err", err)
+		}
+	}
+
+	// with every block present, pin the complete DAG
+	if pinner, ok := c.node.Repo.Filesystem().Filesystem("ipfs").(qfs.PinningFS); ok {
+		if err := pinner.Pin(ctx, ref.Path, true); err != nil {
+			return err
+		}
+	}
+
+	return c.events.Publish(ctx, event.ETRemoteClientPullVersionCompleted, progEvt)
+}
+
+// localMissingBlocks lists the nodes of a manifest that aren't stored in a
+// filesystem
+func localMissingBlocks(ctx context.Context, fs qfs.Filesystem, mfst *dag.Manifest) (*dag.Manifest, error) {
+	missing := &dag.Manifest{}
+	for _, id := range mfst.Nodes {
+		has, err := fs.Has(ctx, "/ipfs/"+id)
+		if err != nil {
+			return nil, fmt.Errorf("checking for local block %s: %w", id, err)
+		}
+		if !has {
+			missing.Nodes = append(missing.Nodes, id)
+		}
+	}
+	return missing, nil
+}
+
+// metadataNodes lists the indexes of manifest nodes reachable from the root
+// without passing through any skipped node, starting with the root itself
+func metadataNodes(mfst *dag.Manifest, skip map[int]bool) []int {
+	children := map[int][]int{}
+	for _, l := range mfst.Links {
+		children[l[0]] = append(children[l[0]], l[1])
+	}
+
+	visited := map[int]bool{0: true}
+	queue := []int{0}
+	for i := 0; i < len(queue); i++ {
+		for _, child := range children[queue[i]] {
+			if !visited[child] && !skip[child] {
+				visited[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+	return queue
+}
+
+// putRemoteBlock fetches a single block from a remote & pins it locally
+func (c *client) putRemoteBlock(ctx context.Context, rem dsync.DagSyncable, id string) error {
+	data, err := rem.GetBlock(ctx, id)
+	if err != nil {
+		return fmt.Errorf("fetching block %s: %w", id, err)
+	}
+	st, err := c.capi.Block().Put(ctx, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if got := st.Path().Cid().String(); got != id {
+		return fmt.Errorf("hash integrity mismatch. expected %s, got: %s", id, got)
+	}
+	return c.capi.Pin().Add(ctx, st.Path(), options.Pin.Recursive(false))
+}
+
 // RemoveDataset requests a remote remove logbook data from an address
 func (c *client) RemoveDataset(ctx context.Context, ref dsref.Ref, remoteAddr string) error {
 	log.Debugf("client.RemoveDataset ref=%q remoteAddr=%q", ref, remoteAddr)
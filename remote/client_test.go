@@ -58,6 +58,12 @@ func TestErrNoClient(t *testing.T) {
 	if _, err := client.PullDataset(ctx, &dsref.Ref{}, ""); err != ErrNoRemoteClient {
 		t.Errorf("error mismatch expected: %q, got: %q", ErrNoRemoteClient, err)
 	}
+	if _, err := client.PullDatasetMetadata(ctx, &dsref.Ref{}, ""); err != ErrNoRemoteClient {
+		t.Errorf("error mismatch expected: %q, got: %q", ErrNoRemoteClient, err)
+	}
+	if _, err := client.ResumePullDataset(ctx, &dsref.Ref{}, ""); err != ErrNoRemoteClient {
+		t.Errorf("error mismatch expected: %q, got: %q", ErrNoRemoteClient, err)
+	}
 	if err := client.RemoveDataset(ctx, dsref.Ref{}, ""); err != ErrNoRemoteClient {
 		t.Errorf("error mismatch expected: %q, got: %q", ErrNoRemoteClient, err)
 	}
@@ -73,6 +79,9 @@ func TestErrNoClient(t *testing.T) {
 	if err := client.PushDataset(ctx, dsref.Ref{}, ""); err != ErrNoRemoteClient {
 		t.Errorf("error mismatch expected: %q, got: %q", ErrNoRemoteClient, err)
 	}
+	if err := client.VerifyDatasetVersion(ctx, dsref.Ref{}, ""); err != ErrNoRemoteClient {
+		t.Errorf("error mismatch expected: %q, got: %q", ErrNoRemoteClient, err)
+	}
 }
 
 func TestNewRemoteRefResolver(t *testing.T) {
@@ -109,6 +109,11 @@ func (c *Client) PushDataset(ctx context.Context, ref dsref.Ref, remoteAddr stri
 	return ErrNotImplemented
 }
 
+// VerifyDatasetVersion is not implemented
+func (c *Client) VerifyDatasetVersion(ctx context.Context, ref dsref.Ref, remoteAddr string) error {
+	return ErrNotImplemented
+}
+
 // RemoveDataset is not implemented
 func (c *Client) RemoveDataset(ctx context.Context, ref dsref.Ref, remoteAddr string) error {
 	return ErrNotImplemented
@@ -153,6 +158,16 @@ func (c *Client) PullDataset(ctx context.Context, ref *dsref.Ref, remoteAddr str
 	return ds, err
 }
 
+// PullDatasetMetadata is not implemented
+func (c *Client) PullDatasetMetadata(ctx context.Context, ref *dsref.Ref, remoteAddr string) (*dataset.Dataset, error) {
+	return nil, ErrNotImplemented
+}
+
+// ResumePullDataset pulls a dataset the same way PullDataset does
+func (c *Client) ResumePullDataset(ctx context.Context, ref *dsref.Ref, remoteAddr string) (*dataset.Dataset, error) {
+	return c.PullDataset(ctx, ref, remoteAddr)
+}
+
 func (c *Client) createTheirDataset(ctx context.Context, ref *dsref.Ref) error {
 	other := c.otherPeer(ref.Username)
 
@@ -4,13 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/gorilla/mux"
+	cid "github.com/ipfs/go-cid"
 	core "github.com/ipfs/go-ipfs/core"
+	"github.com/qri-io/dag"
+	"github.com/qri-io/dag/dsync"
 	"github.com/qri-io/dataset"
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/base"
@@ -180,6 +184,133 @@ func TestDatasetPullPushDeleteFeedsPreviewHTTP(t *testing.T) {
 	}
 }
 
+func TestDatasetPullMetadataHTTP(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	rem := tr.NodeARemote(t)
+	server := tr.RemoteTestServer(rem)
+	defer server.Close()
+
+	wbp := writeWorldBankPopulation(tr.Ctx, t, tr.NodeA.Repo)
+	cli := tr.NodeBClient(t)
+	fs := tr.NodeB.Repo.Filesystem()
+
+	ds, err := cli.PullDatasetMetadata(tr.Ctx, &wbp, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ds.Meta == nil || ds.Meta.Title != "World Bank Population" {
+		t.Errorf("expected meta to be pulled. got: %v", ds.Meta)
+	}
+	if ds.Structure == nil || ds.Structure.Format != "json" {
+		t.Errorf("expected structure to be pulled. got: %v", ds.Structure)
+	}
+	if has, _ := fs.Has(tr.Ctx, ds.BodyPath); has {
+		t.Errorf("expected body %q not to be pulled", ds.BodyPath)
+	}
+	if _, err := tr.NodeB.Repo.GetRef(reporef.DatasetRef{Peername: wbp.Username, Name: wbp.Name}); err != nil {
+		t.Errorf("expected headless version to be added to the repo. got: %s", err)
+	}
+
+	if ds, err = cli.PullDataset(tr.Ctx, &wbp, server.URL); err != nil {
+		t.Fatal(err)
+	}
+	if has, _ := fs.Has(tr.Ctx, ds.BodyPath); !has {
+		t.Errorf("expected body %q to be pulled", ds.BodyPath)
+	}
+}
+
+func TestDatasetResumePullHTTP(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	rem := tr.NodeARemote(t)
+	server := tr.RemoteTestServer(rem)
+	defer server.Close()
+
+	wbp := writeWorldBankPopulation(tr.Ctx, t, tr.NodeA.Repo)
+	cli := tr.NodeBClient(t)
+	fs := tr.NodeB.Repo.Filesystem()
+
+	// a metadata pull leaves every block but the body, like an interrupted pull
+	if _, err := cli.PullDatasetMetadata(tr.Ctx, &wbp, server.URL); err != nil {
+		t.Fatal(err)
+	}
+	capi, err := tr.NodeA.IPFSCoreAPI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lng, err := dsync.NewLocalNodeGetter(capi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := cid.Parse(wbp.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mfst, err := dag.NewManifest(tr.Ctx, lng, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, err := localMissingBlocks(tr.Ctx, fs, mfst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing.Nodes) == 0 || len(missing.Nodes) == len(mfst.Nodes) {
+		t.Fatalf("expected some of %d blocks to be missing, got: %d", len(mfst.Nodes), len(missing.Nodes))
+	}
+
+	ds, err := cli.ResumePullDataset(tr.Ctx, &wbp, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if has, _ := fs.Has(tr.Ctx, ds.BodyPath); !has {
+		t.Errorf("expected body %q to be pulled", ds.BodyPath)
+	}
+	if missing, err = localMissingBlocks(tr.Ctx, fs, mfst); err != nil {
+		t.Fatal(err)
+	} else if len(missing.Nodes) != 0 {
+		t.Errorf("expected no missing blocks after resuming, got: %v", missing.Nodes)
+	}
+}
+
+func TestVerifyDatasetVersionHTTP(t *testing.T) {
+	tr, cleanup := newTestRunner(t)
+	defer cleanup()
+
+	rem := tr.NodeARemote(t)
+	server := tr.RemoteTestServer(rem)
+	defer server.Close()
+
+	cli := tr.NodeBClient(t)
+	pushed := writeVideoViewStats(tr.Ctx, t, tr.NodeB.Repo)
+	if err := cli.PushDataset(tr.Ctx, pushed, server.URL); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.VerifyDatasetVersion(tr.Ctx, pushed, server.URL); err != nil {
+		t.Errorf("expected pushed version to verify, got: %s", err)
+	}
+
+	unpushed := writeWorldBankPopulation(tr.Ctx, t, tr.NodeB.Repo)
+	if err := cli.VerifyDatasetVersion(tr.Ctx, unpushed, server.URL); !errors.Is(err, ErrMissingBlocks) {
+		t.Errorf("expected unpushed version to be missing blocks, got: %v", err)
+	}
+}
+
+func TestMissingBlocks(t *testing.T) {
+	want := &dag.Manifest{Nodes: []string{"a", "b", "c"}}
+	if got := missingBlocks(want, &dag.Manifest{Nodes: []string{"c", "b", "a"}}); len(got) != 0 {
+		t.Errorf("expected no missing blocks, got: %v", got)
+	}
+	if diff := cmp.Diff([]string{"b"}, missingBlocks(want, &dag.Manifest{Nodes: []string{"a", "c"}})); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want.Nodes, missingBlocks(want, nil)); diff != "" {
+		t.Errorf("nil manifest mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestAddress(t *testing.T) {
 	if _, err := Address(&config.Config{}, ""); err == nil {
 		t.Error("expected error, got nil")
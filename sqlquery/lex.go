@@ -0,0 +1,131 @@
+package sqlquery
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tkEOF tokenKind = iota
+	tkIdent
+	tkKeyword
+	tkNumber
+	tkString
+	tkSymbol
+)
+
+// token is a lexical unit of a query. keywords are upper-cased, all other
+// tokens keep their text as written, minus any quotes
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tkEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+var keywords = map[string]bool{
+	"AND":    true,
+	"AS":     true,
+	"ASC":    true,
+	"BY":     true,
+	"DESC":   true,
+	"FALSE":  true,
+	"FROM":   true,
+	"GROUP":  true,
+	"IS":     true,
+	"LIMIT":  true,
+	"NOT":    true,
+	"NULL":   true,
+	"OR":     true,
+	"ORDER":  true,
+	"SELECT": true,
+	"TRUE":   true,
+	"WHERE":  true,
+}
+
+// lex splits a query into tokens, ending with an EOF token
+func lex(query string) ([]token, error) {
+	var (
+		toks []token
+		rs   = []rune(query)
+	)
+
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '_' || unicode.IsLetter(r):
+			for i < len(rs) && (rs[i] == '_' || unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i])) {
+				i++
+			}
+			text := string(rs[start:i])
+			if upper := strings.ToUpper(text); keywords[upper] {
+				toks = append(toks, token{kind: tkKeyword, text: upper, pos: start})
+			} else {
+				toks = append(toks, token{kind: tkIdent, text: text, pos: start})
+			}
+		case r == '"' || r == '\'':
+			text, end, ok := quoted(rs, i)
+			if !ok {
+				return nil, fmt.Errorf("%w: unterminated quote at position %d", ErrInvalidQuery, start)
+			}
+			kind := tkString
+			if r == '"' {
+				kind = tkIdent
+			}
+			toks = append(toks, token{kind: kind, text: text, pos: start})
+			i = end
+		case unicode.IsDigit(r) || ((r == '-' || r == '.') && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			i++
+			for i < len(rs) && (unicode.IsDigit(rs[i]) || rs[i] == '.') {
+				i++
+			}
+			toks = append(toks, token{kind: tkNumber, text: string(rs[start:i]), pos: start})
+		default:
+			sym := string(r)
+			if i+1 < len(rs) {
+				switch two := string(rs[i : i+2]); two {
+				case "!=", "<>", "<=", ">=":
+					sym = two
+				}
+			}
+			if !strings.Contains(",()*=<>;", sym) && len(sym) == 1 {
+				return nil, fmt.Errorf("%w: unexpected character %q at position %d", ErrInvalidQuery, r, start)
+			}
+			toks = append(toks, token{kind: tkSymbol, text: sym, pos: start})
+			i += len([]rune(sym))
+		}
+	}
+
+	return append(toks, token{kind: tkEOF, pos: len(rs)}), nil
+}
+
+// quoted reads a quoted string starting at rs[i]. a doubled quote character
+// escapes a quote within the string
+func quoted(rs []rune, i int) (text string, end int, ok bool) {
+	q := rs[i]
+	b := strings.Builder{}
+	for i++; i < len(rs); i++ {
+		if rs[i] == q {
+			if i+1 < len(rs) && rs[i+1] == q {
+				b.WriteRune(q)
+				i++
+				continue
+			}
+			return b.String(), i + 1, true
+		}
+		b.WriteRune(rs[i])
+	}
+	return "", 0, false
+}
@@ -0,0 +1,59 @@
+package sqlquery
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLex(t *testing.T) {
+	toks, err := lex(`select "Rep Name", 'it''s' AS q FROM source WHERE amount >= -1.5 AND x <> 2;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []token{
+		{kind: tkKeyword, text: "SELECT", pos: 0},
+		{kind: tkIdent, text: "Rep Name", pos: 7},
+		{kind: tkSymbol, text: ",", pos: 17},
+		{kind: tkString, text: "it's", pos: 19},
+		{kind: tkKeyword, text: "AS", pos: 27},
+		{kind: tkIdent, text: "q", pos: 30},
+		{kind: tkKeyword, text: "FROM", pos: 32},
+		{kind: tkIdent, text: "source", pos: 37},
+		{kind: tkKeyword, text: "WHERE", pos: 44},
+		{kind: tkIdent, text: "amount", pos: 50},
+		{kind: tkSymbol, text: ">=", pos: 57},
+		{kind: tkNumber, text: "-1.5", pos: 60},
+		{kind: tkKeyword, text: "AND", pos: 65},
+		{kind: tkIdent, text: "x", pos: 69},
+		{kind: tkSymbol, text: "<>", pos: 71},
+		{kind: tkNumber, text: "2", pos: 74},
+		{kind: tkSymbol, text: ";", pos: 75},
+		{kind: tkEOF, pos: 76},
+	}
+	if diff := cmp.Diff(expect, toks, cmp.AllowUnexported(token{})); diff != "" {
+		t.Errorf("tokens mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLexErrors(t *testing.T) {
+	cases := []struct {
+		query, msg string
+	}{
+		{"SELECT a FROM source WHERE a = 'open", "invalid SQL: unterminated quote at position 31"},
+		{`SELECT "a FROM source`, "invalid SQL: unterminated quote at position 7"},
+		{"SELECT a + b FROM source", "invalid SQL: unexpected character '+' at position 9"},
+		{"SELECT a FROM source WHERE a ! b", "invalid SQL: unexpected character '!' at position 29"},
+	}
+	for _, c := range cases {
+		_, err := lex(c.query)
+		if !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("%q: expected ErrInvalidQuery, got: %v", c.query, err)
+			continue
+		}
+		if err.Error() != c.msg {
+			t.Errorf("%q: error mismatch.\nwant: %s\ngot:  %s", c.query, c.msg, err)
+		}
+	}
+}
@@ -0,0 +1,403 @@
+package sqlquery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// statement is a parsed SELECT query
+type statement struct {
+	items   []selectItem
+	from    string
+	where   condition
+	groupBy []*column
+	orderBy []orderTerm
+	limit   int
+}
+
+// selectItem is a single entry in the SELECT list
+type selectItem struct {
+	star  bool
+	expr  expr
+	alias string
+}
+
+// orderTerm is a single entry in the ORDER BY list
+type orderTerm struct {
+	name string
+	pos  int
+	desc bool
+}
+
+// expr is a value-producing expression
+type expr interface {
+	String() string
+}
+
+// column references a table column by name. idx is set when the statement
+// is resolved against a table
+type column struct {
+	name string
+	pos  int
+	idx  int
+}
+
+func (c *column) String() string { return c.name }
+
+// literal is a constant value
+type literal struct {
+	value interface{}
+	text  string
+}
+
+func (l *literal) String() string { return l.text }
+
+// aggregate is a call to an aggregate function. a nil arg means the function
+// was called with *
+type aggregate struct {
+	fn  string
+	arg *column
+}
+
+func (a *aggregate) String() string {
+	if a.arg == nil {
+		return strings.ToLower(a.fn)
+	}
+	return strings.ToLower(a.fn) + "_" + a.arg.name
+}
+
+var aggregateFuncs = map[string]bool{
+	"AVG":   true,
+	"COUNT": true,
+	"MAX":   true,
+	"MIN":   true,
+	"SUM":   true,
+}
+
+// condition is a boolean expression used in WHERE clauses
+type condition interface{}
+
+type comparison struct {
+	op          string
+	left, right expr
+}
+
+type isNull struct {
+	expr expr
+	not  bool
+}
+
+type logical struct {
+	op          string
+	left, right condition
+}
+
+type negation struct {
+	cond condition
+}
+
+// parser builds a statement from a list of tokens
+type parser struct {
+	toks []token
+	i    int
+}
+
+// parse reads a single SELECT statement from a query string
+func parse(query string) (*statement, error) {
+	toks, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	stmt, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+	p.acceptSymbol(";")
+	if t := p.peek(); t.kind != tkEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+	return stmt, nil
+}
+
+func (p *parser) statement() (*statement, error) {
+	stmt := &statement{limit: -1}
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+
+	for {
+		item, err := p.selectItem()
+		if err != nil {
+			return nil, err
+		}
+		stmt.items = append(stmt.items, item)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	if err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	t := p.next()
+	if t.kind != tkIdent {
+		return nil, p.errorf(t, "expected table name, got %s", t)
+	}
+	stmt.from = t.text
+
+	if p.acceptKeyword("WHERE") {
+		cond, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		stmt.where = cond
+	}
+
+	if p.acceptKeyword("GROUP") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			t := p.next()
+			if t.kind != tkIdent {
+				return nil, p.errorf(t, "expected column name in GROUP BY, got %s", t)
+			}
+			stmt.groupBy = append(stmt.groupBy, &column{name: t.text, pos: t.pos})
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+
+	if p.acceptKeyword("ORDER") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			t := p.next()
+			if t.kind != tkIdent {
+				return nil, p.errorf(t, "expected column name in ORDER BY, got %s", t)
+			}
+			term := orderTerm{name: t.text, pos: t.pos}
+			if p.acceptKeyword("DESC") {
+				term.desc = true
+			} else {
+				p.acceptKeyword("ASC")
+			}
+			stmt.orderBy = append(stmt.orderBy, term)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+
+	if p.acceptKeyword("LIMIT") {
+		t := p.next()
+		n, err := strconv.Atoi(t.text)
+		if t.kind != tkNumber || err != nil || n < 0 {
+			return nil, p.errorf(t, "LIMIT must be a positive whole number, got %s", t)
+		}
+		stmt.limit = n
+	}
+
+	return stmt, nil
+}
+
+func (p *parser) selectItem() (selectItem, error) {
+	if p.acceptSymbol("*") {
+		return selectItem{star: true}, nil
+	}
+	e, err := p.expr()
+	if err != nil {
+		return selectItem{}, err
+	}
+	item := selectItem{expr: e}
+	if p.acceptKeyword("AS") {
+		t := p.next()
+		if t.kind != tkIdent {
+			return selectItem{}, p.errorf(t, "expected name after AS, got %s", t)
+		}
+		item.alias = t.text
+	}
+	return item, nil
+}
+
+func (p *parser) expr() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tkIdent:
+		if fn := strings.ToUpper(t.text); aggregateFuncs[fn] && p.peek().text == "(" && p.peek().kind == tkSymbol {
+			return p.aggregate(fn)
+		}
+		return &column{name: t.text, pos: t.pos}, nil
+	case tkNumber:
+		return numberLiteral(t.text)
+	case tkString:
+		return &literal{value: t.text, text: t.text}, nil
+	case tkKeyword:
+		switch t.text {
+		case "NULL":
+			return &literal{value: nil, text: "null"}, nil
+		case "TRUE":
+			return &literal{value: true, text: "true"}, nil
+		case "FALSE":
+			return &literal{value: false, text: "false"}, nil
+		}
+	}
+	return nil, p.errorf(t, "expected a column, value or function, got %s", t)
+}
+
+func (p *parser) aggregate(fn string) (expr, error) {
+	p.next() // consume "("
+	agg := &aggregate{fn: fn}
+	if p.acceptSymbol("*") {
+		if fn != "COUNT" {
+			return nil, fmt.Errorf("%w: %s(*) is not supported, only COUNT(*)", ErrInvalidQuery, fn)
+		}
+	} else {
+		t := p.next()
+		if t.kind != tkIdent {
+			return nil, p.errorf(t, "expected column name in %s, got %s", fn, t)
+		}
+		agg.arg = &column{name: t.text, pos: t.pos}
+	}
+	if !p.acceptSymbol(")") {
+		t := p.peek()
+		return nil, p.errorf(t, "expected \")\" to close %s, got %s", fn, t)
+	}
+	return agg, nil
+}
+
+// or parses a chain of conditions joined by OR, which binds loosest
+func (p *parser) or() (condition, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("OR") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (condition, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("AND") {
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) not() (condition, error) {
+	if p.acceptKeyword("NOT") {
+		cond, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &negation{cond: cond}, nil
+	}
+	if p.acceptSymbol("(") {
+		cond, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptSymbol(")") {
+			t := p.peek()
+			return nil, p.errorf(t, "expected \")\", got %s", t)
+		}
+		return cond, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (condition, error) {
+	left, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.acceptKeyword("IS") {
+		not := p.acceptKeyword("NOT")
+		if err := p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+		return &isNull{expr: left, not: not}, nil
+	}
+
+	t := p.next()
+	switch t.text {
+	case "=", "!=", "<>", "<", "<=", ">", ">=":
+		if t.kind != tkSymbol {
+			break
+		}
+		right, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		return &comparison{op: t.text, left: left, right: right}, nil
+	}
+	return nil, p.errorf(t, "expected comparison operator, got %s", t)
+}
+
+func numberLiteral(text string) (expr, error) {
+	if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return &literal{value: i, text: text}, nil
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid number %q", ErrInvalidQuery, text)
+	}
+	return &literal{value: f, text: text}, nil
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tkEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) acceptKeyword(kw string) bool {
+	if t := p.peek(); t.kind == tkKeyword && t.text == kw {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) acceptSymbol(sym string) bool {
+	if t := p.peek(); t.kind == tkSymbol && t.text == sym {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(kw string) error {
+	if !p.acceptKeyword(kw) {
+		t := p.peek()
+		return p.errorf(t, "expected %s, got %s", kw, t)
+	}
+	return nil
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at position %d", ErrInvalidQuery, fmt.Sprintf(format, args...), t.pos)
+}
@@ -0,0 +1,92 @@
+package sqlquery
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	stmt, err := parse("SELECT region, COUNT(*) AS n, SUM(amount) FROM source WHERE NOT (a = 1 OR b IS NOT NULL) AND c < 'x' GROUP BY region ORDER BY n DESC, region LIMIT 3;")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stmt.items) != 3 {
+		t.Fatalf("expected 3 select items, got %d", len(stmt.items))
+	}
+	if col, ok := stmt.items[0].expr.(*column); !ok || col.name != "region" {
+		t.Errorf("expected first item to be column region, got %#v", stmt.items[0].expr)
+	}
+	if agg, ok := stmt.items[1].expr.(*aggregate); !ok || agg.fn != "COUNT" || agg.arg != nil || stmt.items[1].alias != "n" {
+		t.Errorf("expected second item to be COUNT(*) AS n, got %#v", stmt.items[1])
+	}
+	if got := stmt.items[2].expr.String(); got != "sum_amount" {
+		t.Errorf("expected third item to be named sum_amount, got %q", got)
+	}
+	if stmt.from != "source" {
+		t.Errorf("expected from source, got %q", stmt.from)
+	}
+
+	// AND binds tighter than OR, parentheses group & NOT applies to the group
+	and, ok := stmt.where.(*logical)
+	if !ok || and.op != "AND" {
+		t.Fatalf("expected WHERE to be an AND, got %#v", stmt.where)
+	}
+	neg, ok := and.left.(*negation)
+	if !ok {
+		t.Fatalf("expected left of AND to be a negation, got %#v", and.left)
+	}
+	if or, ok := neg.cond.(*logical); !ok || or.op != "OR" {
+		t.Errorf("expected negated condition to be an OR, got %#v", neg.cond)
+	} else if isn, ok := or.right.(*isNull); !ok || !isn.not {
+		t.Errorf("expected IS NOT NULL, got %#v", or.right)
+	}
+	if cmp, ok := and.right.(*comparison); !ok || cmp.op != "<" {
+		t.Errorf("expected right of AND to be a < comparison, got %#v", and.right)
+	}
+
+	if len(stmt.groupBy) != 1 || stmt.groupBy[0].name != "region" {
+		t.Errorf("expected GROUP BY region, got %#v", stmt.groupBy)
+	}
+	if len(stmt.orderBy) != 2 || !stmt.orderBy[0].desc || stmt.orderBy[1].desc {
+		t.Errorf("expected ORDER BY n DESC, region, got %#v", stmt.orderBy)
+	}
+	if stmt.limit != 3 {
+		t.Errorf("expected LIMIT 3, got %d", stmt.limit)
+	}
+
+	if stmt, err = parse("SELECT * FROM source"); err != nil {
+		t.Fatal(err)
+	}
+	if stmt.limit != -1 {
+		t.Errorf("expected a missing LIMIT to be -1, got %d", stmt.limit)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		query, msg string
+	}{
+		{"SELECT a FROM source source", `invalid SQL: unexpected "source" at position 21`},
+		{"SELECT a FROM 'source'", `invalid SQL: expected table name, got "source" at position 14`},
+		{"SELECT a AS FROM source", `invalid SQL: expected name after AS, got "FROM" at position 12`},
+		{"SELECT SUM(*) FROM source", "invalid SQL: SUM(*) is not supported, only COUNT(*)"},
+		{"SELECT COUNT(a FROM source", `invalid SQL: expected ")" to close COUNT, got "FROM" at position 15`},
+		{"SELECT a FROM source WHERE (a = 1", `invalid SQL: expected ")", got end of query at position 33`},
+		{"SELECT a FROM source WHERE a", "invalid SQL: expected comparison operator, got end of query at position 28"},
+		{"SELECT a FROM source WHERE a IS 1", `invalid SQL: expected NULL, got "1" at position 32`},
+		{"SELECT a FROM source GROUP a", `invalid SQL: expected BY, got "a" at position 27`},
+		{"SELECT a FROM source ORDER BY 1", `invalid SQL: expected column name in ORDER BY, got "1" at position 30`},
+		{"SELECT a FROM source LIMIT 1.5", `invalid SQL: LIMIT must be a positive whole number, got "1.5" at position 27`},
+	}
+	for _, c := range cases {
+		_, err := parse(c.query)
+		if !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("%q: expected ErrInvalidQuery, got: %v", c.query, err)
+			continue
+		}
+		if err.Error() != c.msg {
+			t.Errorf("%q: error mismatch.\nwant: %s\ngot:  %s", c.query, c.msg, err)
+		}
+	}
+}
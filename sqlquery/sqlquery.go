@@ -0,0 +1,540 @@
+// Package sqlquery runs a small subset of SQL against a table of rows held in
+// memory. It exists to derive dataset bodies from other datasets, and makes
+// no attempt to be a general purpose database
+package sqlquery
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrInvalidQuery indicates a query couldn't be parsed or isn't supported
+	ErrInvalidQuery = errors.New("invalid SQL")
+	// ErrUnknownColumn indicates a query references a column the table doesn't
+	// have
+	ErrUnknownColumn = errors.New("unknown column")
+	// ErrUnknownTable indicates a query selects from a table other than the one
+	// it's run against
+	ErrUnknownTable = errors.New("unknown table")
+)
+
+// Table is a named set of rows to run a query against. Every row must have
+// one value per column
+type Table struct {
+	Name    string
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Result is the output of a query
+type Result struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Query parses a SELECT statement and runs it against a table. The supported
+// syntax is:
+//
+//   SELECT item [AS alias], ... FROM table
+//     [WHERE condition]
+//     [GROUP BY column, ...]
+//     [ORDER BY column [ASC|DESC], ...]
+//     [LIMIT n]
+//
+// items are *, column names, literal values, or one of the aggregate
+// functions COUNT, SUM, AVG, MIN and MAX applied to a column. COUNT(*) counts
+// rows. conditions compare values with =, !=, <>, <, <=, >, >= or IS [NOT]
+// NULL, and combine with AND, OR, NOT and parentheses. Comparisons with null
+// are never true. Keywords are case-insensitive, identifiers that clash with
+// keywords or contain spaces can be double-quoted.
+//
+// Unaliased aggregate columns are named function_column, or "count" for
+// COUNT(*). ORDER BY matches result column names first, falling back to table
+// columns for queries that don't aggregate
+func Query(query string, t Table) (*Result, error) {
+	stmt, err := parse(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.run(t)
+}
+
+// outputColumn is a single column of a result
+type outputColumn struct {
+	name string
+	expr expr
+}
+
+func (s *statement) run(t Table) (*Result, error) {
+	if !strings.EqualFold(s.from, t.Name) {
+		return nil, fmt.Errorf("%w %q, queries can only select from %q", ErrUnknownTable, s.from, t.Name)
+	}
+
+	cols, grouped, err := s.resolve(t.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]interface{}, 0, len(t.Rows))
+	for _, row := range t.Rows {
+		if len(row) != len(t.Columns) {
+			return nil, fmt.Errorf("row has %d values, expected %d", len(row), len(t.Columns))
+		}
+		if s.where == nil || evalCondition(s.where, row) {
+			rows = append(rows, row)
+		}
+	}
+
+	var recs []record
+	if grouped {
+		if recs, err = s.aggregate(cols, rows); err != nil {
+			return nil, err
+		}
+	} else {
+		recs = make([]record, len(rows))
+		for i, row := range rows {
+			out := make([]interface{}, len(cols))
+			for j, c := range cols {
+				out[j] = evalExpr(c.expr, row)
+			}
+			recs[i] = record{src: row, out: out}
+		}
+	}
+
+	if err := s.sort(recs, cols, t.Columns, grouped); err != nil {
+		return nil, err
+	}
+	if s.limit >= 0 && s.limit < len(recs) {
+		recs = recs[:s.limit]
+	}
+
+	res := &Result{
+		Columns: make([]string, len(cols)),
+		Rows:    make([][]interface{}, len(recs)),
+	}
+	for i, c := range cols {
+		res.Columns[i] = c.name
+	}
+	for i, rec := range recs {
+		res.Rows[i] = rec.out
+	}
+	return res, nil
+}
+
+// resolve matches column references to table columns, expanding * and
+// naming output columns. grouped is true if the query aggregates rows
+func (s *statement) resolve(tableCols []string) (cols []outputColumn, grouped bool, err error) {
+	resolveCol := func(c *column) error {
+		idx := columnIndex(tableCols, c.name)
+		if idx < 0 {
+			return fmt.Errorf("%w %q at position %d", ErrUnknownColumn, c.name, c.pos)
+		}
+		c.idx = idx
+		c.name = tableCols[idx]
+		return nil
+	}
+
+	grouped = len(s.groupBy) > 0
+	for _, c := range s.groupBy {
+		if err := resolveCol(c); err != nil {
+			return nil, false, err
+		}
+	}
+
+	for _, item := range s.items {
+		if item.star {
+			for i, name := range tableCols {
+				cols = append(cols, outputColumn{name: name, expr: &column{name: name, idx: i}})
+			}
+			continue
+		}
+		switch e := item.expr.(type) {
+		case *column:
+			if err := resolveCol(e); err != nil {
+				return nil, false, err
+			}
+		case *aggregate:
+			grouped = true
+			if e.arg != nil {
+				if err := resolveCol(e.arg); err != nil {
+					return nil, false, err
+				}
+			}
+		}
+		name := item.alias
+		if name == "" {
+			name = item.expr.String()
+		}
+		cols = append(cols, outputColumn{name: name, expr: item.expr})
+	}
+
+	if err := resolveCondition(s.where, resolveCol); err != nil {
+		return nil, false, err
+	}
+
+	seen := map[string]bool{}
+	for _, c := range cols {
+		if seen[c.name] {
+			return nil, false, fmt.Errorf("%w: duplicate result column %q, use AS to rename it", ErrInvalidQuery, c.name)
+		}
+		seen[c.name] = true
+	}
+
+	if grouped {
+		for _, item := range s.items {
+			if item.star {
+				return nil, false, fmt.Errorf("%w: cannot select * in a query that uses GROUP BY or aggregate functions", ErrInvalidQuery)
+			}
+			if c, ok := item.expr.(*column); ok && !inGroupBy(s.groupBy, c) {
+				return nil, false, fmt.Errorf("%w: column %q must appear in GROUP BY or be used in an aggregate function", ErrInvalidQuery, c.name)
+			}
+		}
+	}
+
+	return cols, grouped, nil
+}
+
+func resolveCondition(cond condition, resolveCol func(*column) error) error {
+	resolveExpr := func(e expr) error {
+		switch x := e.(type) {
+		case *column:
+			return resolveCol(x)
+		case *aggregate:
+			return fmt.Errorf("%w: aggregate functions are not allowed in WHERE", ErrInvalidQuery)
+		}
+		return nil
+	}
+
+	switch c := cond.(type) {
+	case *comparison:
+		if err := resolveExpr(c.left); err != nil {
+			return err
+		}
+		return resolveExpr(c.right)
+	case *isNull:
+		return resolveExpr(c.expr)
+	case *logical:
+		if err := resolveCondition(c.left, resolveCol); err != nil {
+			return err
+		}
+		return resolveCondition(c.right, resolveCol)
+	case *negation:
+		return resolveCondition(c.cond, resolveCol)
+	}
+	return nil
+}
+
+// columnIndex finds a column by name, preferring an exact match over a
+// case-insensitive one. returns -1 if no column matches
+func columnIndex(cols []string, name string) int {
+	for i, c := range cols {
+		if c == name {
+			return i
+		}
+	}
+	for i, c := range cols {
+		if strings.EqualFold(c, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func inGroupBy(groupBy []*column, c *column) bool {
+	for _, g := range groupBy {
+		if g.idx == c.idx {
+			return true
+		}
+	}
+	return false
+}
+
+// record pairs a result row with the table row it came from. src is nil for
+// aggregated rows
+type record struct {
+	src []interface{}
+	out []interface{}
+}
+
+// aggregate groups rows by the GROUP BY columns, in order of first appearance,
+// calculating one output row per group. queries that aggregate without
+// GROUP BY produce a single row, even if there are no input rows
+func (s *statement) aggregate(cols []outputColumn, rows [][]interface{}) ([]record, error) {
+	var (
+		keys   []string
+		groups = map[string][][]interface{}{}
+	)
+	if len(s.groupBy) == 0 {
+		keys = []string{""}
+		groups[""] = rows
+	} else {
+		for _, row := range rows {
+			vals := make([]interface{}, len(s.groupBy))
+			for i, c := range s.groupBy {
+				vals[i] = row[c.idx]
+			}
+			key := fmt.Sprintf("%#v", vals)
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], row)
+		}
+	}
+
+	recs := make([]record, len(keys))
+	for i, key := range keys {
+		group := groups[key]
+		out := make([]interface{}, len(cols))
+		for j, c := range cols {
+			switch e := c.expr.(type) {
+			case *aggregate:
+				val, err := e.calc(group)
+				if err != nil {
+					return nil, err
+				}
+				out[j] = val
+			default:
+				// grouped columns have the same value for every row in the group
+				if len(group) > 0 {
+					out[j] = evalExpr(e, group[0])
+				} else {
+					out[j] = evalExpr(e, nil)
+				}
+			}
+		}
+		recs[i] = record{out: out}
+	}
+	return recs, nil
+}
+
+// calc applies an aggregate function to a group of rows. null values are
+// ignored. SUM, AVG, MIN and MAX of no values is null
+func (a *aggregate) calc(rows [][]interface{}) (interface{}, error) {
+	if a.arg == nil {
+		return int64(len(rows)), nil
+	}
+
+	var vals []interface{}
+	for _, row := range rows {
+		if v := row[a.arg.idx]; v != nil {
+			vals = append(vals, v)
+		}
+	}
+
+	switch a.fn {
+	case "COUNT":
+		return int64(len(vals)), nil
+	case "SUM", "AVG":
+		if len(vals) == 0 {
+			return nil, nil
+		}
+		var (
+			sum     float64
+			intSum  int64
+			allInts = true
+		)
+		for _, v := range vals {
+			f, ok := toFloat(v)
+			if !ok {
+				return nil, fmt.Errorf("cannot %s non-numeric value %v in column %q", strings.ToLower(a.fn), v, a.arg.name)
+			}
+			sum += f
+			if i, ok := toInt(v); ok {
+				intSum += i
+			} else {
+				allInts = false
+			}
+		}
+		if a.fn == "AVG" {
+			return sum / float64(len(vals)), nil
+		}
+		if allInts {
+			return intSum, nil
+		}
+		return sum, nil
+	case "MIN", "MAX":
+		var best interface{}
+		for _, v := range vals {
+			if best == nil {
+				best = v
+				continue
+			}
+			c, ok := compare(v, best)
+			if !ok {
+				return nil, fmt.Errorf("cannot %s column %q, it mixes values of type %T and %T", strings.ToLower(a.fn), a.arg.name, best, v)
+			}
+			if (a.fn == "MIN" && c < 0) || (a.fn == "MAX" && c > 0) {
+				best = v
+			}
+		}
+		return best, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported function %s", ErrInvalidQuery, a.fn)
+}
+
+// sort orders records by the ORDER BY clause, leaving them in input order
+// when there's no clause
+func (s *statement) sort(recs []record, cols []outputColumn, tableCols []string, grouped bool) error {
+	if len(s.orderBy) == 0 {
+		return nil
+	}
+
+	keys := make([]func(record) interface{}, len(s.orderBy))
+	for i, term := range s.orderBy {
+		names := make([]string, len(cols))
+		for j, c := range cols {
+			names[j] = c.name
+		}
+		if idx := columnIndex(names, term.name); idx >= 0 {
+			keys[i] = func(r record) interface{} { return r.out[idx] }
+		} else if idx := columnIndex(tableCols, term.name); idx >= 0 && !grouped {
+			keys[i] = func(r record) interface{} { return r.src[idx] }
+		} else {
+			return fmt.Errorf("%w %q in ORDER BY at position %d", ErrUnknownColumn, term.name, term.pos)
+		}
+	}
+
+	sort.SliceStable(recs, func(i, j int) bool {
+		for k, term := range s.orderBy {
+			c := order(keys[k](recs[i]), keys[k](recs[j]))
+			if c == 0 {
+				continue
+			}
+			if term.desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+	return nil
+}
+
+// order compares any two values for sorting. nulls sort first, values of
+// types that can't be compared sort by type name
+func order(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if c, ok := compare(a, b); ok {
+		return c
+	}
+	return strings.Compare(fmt.Sprintf("%T", a), fmt.Sprintf("%T", b))
+}
+
+func evalExpr(e expr, row []interface{}) interface{} {
+	switch x := e.(type) {
+	case *column:
+		return row[x.idx]
+	case *literal:
+		return x.value
+	}
+	return nil
+}
+
+func evalCondition(cond condition, row []interface{}) bool {
+	switch c := cond.(type) {
+	case *comparison:
+		a, b := evalExpr(c.left, row), evalExpr(c.right, row)
+		if a == nil || b == nil {
+			return false
+		}
+		cmp, ok := compare(a, b)
+		switch c.op {
+		case "=":
+			return ok && cmp == 0
+		case "!=", "<>":
+			return !ok || cmp != 0
+		case "<":
+			return ok && cmp < 0
+		case "<=":
+			return ok && cmp <= 0
+		case ">":
+			return ok && cmp > 0
+		case ">=":
+			return ok && cmp >= 0
+		}
+	case *isNull:
+		return (evalExpr(c.expr, row) == nil) != c.not
+	case *logical:
+		if c.op == "AND" {
+			return evalCondition(c.left, row) && evalCondition(c.right, row)
+		}
+		return evalCondition(c.left, row) || evalCondition(c.right, row)
+	case *negation:
+		return !evalCondition(c.cond, row)
+	}
+	return false
+}
+
+// compare orders two non-null values of the same kind. ok is false when the
+// values can't be compared
+func compare(a, b interface{}) (c int, ok bool) {
+	if fa, aok := toFloat(a); aok {
+		fb, bok := toFloat(b)
+		if !bok {
+			return 0, false
+		}
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0, true
+			case !x:
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case float32:
+		return float64(x), true
+	case int:
+		return float64(x), true
+	case int32:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	}
+	return 0, false
+}
+
+func toInt(v interface{}) (int64, bool) {
+	switch x := v.(type) {
+	case int:
+		return int64(x), true
+	case int32:
+		return int64(x), true
+	case int64:
+		return x, true
+	}
+	return 0, false
+}
@@ -0,0 +1,107 @@
+package sqlquery
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var sales = Table{
+	Name:    "source",
+	Columns: []string{"region", "rep", "amount", "closed"},
+	Rows: [][]interface{}{
+		{"east", "ana", int64(100), true},
+		{"west", "bo", int64(250), true},
+		{"east", "cy", int64(50), false},
+		{"north", "di", nil, false},
+		{"west", "ed", 12.5, true},
+	},
+}
+
+func TestQuery(t *testing.T) {
+	cases := []struct {
+		query  string
+		expect *Result
+	}{
+		{"SELECT * FROM source LIMIT 1", &Result{
+			Columns: []string{"region", "rep", "amount", "closed"},
+			Rows:    [][]interface{}{{"east", "ana", int64(100), true}},
+		}},
+		{"select rep, amount as total from source where amount >= 100 order by total desc", &Result{
+			Columns: []string{"rep", "total"},
+			Rows:    [][]interface{}{{"bo", int64(250)}, {"ana", int64(100)}},
+		}},
+		{"SELECT region, SUM(amount) FROM source GROUP BY region", &Result{
+			Columns: []string{"region", "sum_amount"},
+			Rows:    [][]interface{}{{"east", int64(150)}, {"west", 262.5}, {"north", nil}},
+		}},
+		{"SELECT region, COUNT(*), COUNT(amount) AS priced FROM source GROUP BY region ORDER BY region", &Result{
+			Columns: []string{"region", "count", "priced"},
+			Rows:    [][]interface{}{{"east", int64(2), int64(2)}, {"north", int64(1), int64(0)}, {"west", int64(2), int64(2)}},
+		}},
+		{"SELECT MIN(amount), MAX(rep), AVG(amount) FROM source WHERE closed = true;", &Result{
+			Columns: []string{"min_amount", "max_rep", "avg_amount"},
+			Rows:    [][]interface{}{{12.5, "ed", 362.5 / 3}},
+		}},
+		{"SELECT COUNT(*) FROM source WHERE region = 'south'", &Result{
+			Columns: []string{"count"},
+			Rows:    [][]interface{}{{int64(0)}},
+		}},
+		{`SELECT rep FROM source WHERE (region = 'east' OR amount IS NULL) AND NOT "rep" = 'cy' ORDER BY amount`, &Result{
+			Columns: []string{"rep"},
+			Rows:    [][]interface{}{{"di"}, {"ana"}},
+		}},
+		{"SELECT rep FROM source WHERE amount < 75 OR amount > 200", &Result{
+			Columns: []string{"rep"},
+			Rows:    [][]interface{}{{"bo"}, {"cy"}, {"ed"}},
+		}},
+	}
+
+	for _, c := range cases {
+		got, err := Query(c.query, sales)
+		if err != nil {
+			t.Errorf("query %q unexpected error: %s", c.query, err)
+			continue
+		}
+		if diff := cmp.Diff(c.expect, got); diff != "" {
+			t.Errorf("query %q result mismatch (-want +got):\n%s", c.query, diff)
+		}
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	cases := []struct {
+		query  string
+		target error
+		msg    string
+	}{
+		{"SELEC region FROM source", ErrInvalidQuery, `invalid SQL: expected SELECT, got "SELEC" at position 0`},
+		{"SELECT region FROM source WHERE", ErrInvalidQuery, "invalid SQL: expected a column, value or function, got end of query at position 31"},
+		{"SELECT region FROM source LIMIT -1", ErrInvalidQuery, `invalid SQL: LIMIT must be a positive whole number, got "-1" at position 32`},
+		{"SELECT 'region FROM source", ErrInvalidQuery, "invalid SQL: unterminated quote at position 7"},
+		{"SELECT region, amount FROM source GROUP BY region", ErrInvalidQuery, `invalid SQL: column "amount" must appear in GROUP BY or be used in an aggregate function`},
+		{"SELECT * FROM source GROUP BY region", ErrInvalidQuery, "invalid SQL: cannot select * in a query that uses GROUP BY or aggregate functions"},
+		{"SELECT rep FROM source WHERE SUM(amount) > 1", ErrInvalidQuery, "invalid SQL: aggregate functions are not allowed in WHERE"},
+		{"SELECT rep, rep FROM source", ErrInvalidQuery, `invalid SQL: duplicate result column "rep", use AS to rename it`},
+		{"SELECT city FROM source", ErrUnknownColumn, `unknown column "city" at position 7`},
+		{"SELECT region, SUM(price) FROM source GROUP BY region", ErrUnknownColumn, `unknown column "price" at position 19`},
+		{"SELECT region, COUNT(*) FROM source GROUP BY region ORDER BY rep", ErrUnknownColumn, `unknown column "rep" in ORDER BY at position 61`},
+		{"SELECT region FROM sales", ErrUnknownTable, `unknown table "sales", queries can only select from "source"`},
+	}
+
+	for _, c := range cases {
+		_, err := Query(c.query, sales)
+		if !errors.Is(err, c.target) {
+			t.Errorf("query %q expected error %q, got: %v", c.query, c.target, err)
+			continue
+		}
+		if err.Error() != c.msg {
+			t.Errorf("query %q error message mismatch.\nwant: %s\ngot:  %s", c.query, c.msg, err)
+		}
+	}
+
+	if _, err := Query("SELECT SUM(rep) FROM source", sales); err == nil {
+		t.Error("expected summing strings to error")
+	}
+}
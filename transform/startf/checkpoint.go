@@ -0,0 +1,87 @@
+package startf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/qri-io/qri/event"
+	"github.com/qri-io/starlib/util"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// qriStruct returns the "qri" global, which holds builtins for persisting
+// script state across runs & reading secrets
+func (r *StepRunner) qriStruct(ctx context.Context) *starlarkstruct.Struct {
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"checkpoint":   starlark.NewBuiltin("checkpoint", r.checkpoint),
+		"resume_state": starlark.NewBuiltin("resume_state", r.resumeStateFunc),
+		"get_secret":   starlark.NewBuiltin("get_secret", r.getSecretFunc(ctx)),
+		"prev_stats":   starlark.NewBuiltin("prev_stats", r.prevStatsFunc(ctx)),
+	})
+}
+
+// checkpoint records a json-serializable value as the state a later run can
+// resume from
+func (r *StepRunner) checkpoint(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var state starlark.Value
+	if err := starlark.UnpackPositionalArgs("checkpoint", args, kwargs, 1, &state); err != nil {
+		return starlark.None, err
+	}
+
+	val, err := util.Unmarshal(state)
+	if err != nil {
+		return starlark.None, fmt.Errorf("checkpoint: %w", err)
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return starlark.None, fmt.Errorf("checkpoint state must be json-serializable: %w", err)
+	}
+
+	if r.eventsCh != nil {
+		r.eventsCh <- event.Event{
+			Type:    event.ETTransformCheckpoint,
+			Payload: event.TransformCheckpoint{State: data},
+		}
+	}
+	return starlark.None, nil
+}
+
+// resumeStateFunc returns the state checkpointed by the run being resumed,
+// None if this run isn't resuming
+func (r *StepRunner) resumeStateFunc(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs("resume_state", args, kwargs, 0); err != nil {
+		return starlark.None, err
+	}
+	if r.resumeState == nil {
+		return starlark.None, nil
+	}
+
+	var val interface{}
+	if err := json.Unmarshal(r.resumeState, &val); err != nil {
+		return starlark.None, fmt.Errorf("decoding resume state: %w", err)
+	}
+	return util.Marshal(val)
+}
+
+// prevStatsFunc returns the stats of the previous version of the dataset,
+// None if the dataset has no previous version
+func (r *StepRunner) prevStatsFunc(ctx context.Context) func(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	return func(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		if err := starlark.UnpackPositionalArgs("prev_stats", args, kwargs, 0); err != nil {
+			return starlark.None, err
+		}
+		if r.prevStats == nil {
+			return starlark.None, nil
+		}
+		sa, err := r.prevStats(ctx)
+		if err != nil {
+			return starlark.None, fmt.Errorf("prev_stats: %w", err)
+		}
+		if sa == nil {
+			return starlark.None, nil
+		}
+		return util.Marshal(sa.Stats)
+	}
+}
@@ -73,6 +73,7 @@ var dsMethods = map[string]*starlark.Builtin{
 	"get_meta":      starlark.NewBuiltin("get_meta", dsGetMeta),
 	"get_structure": starlark.NewBuiltin("get_structure", dsGetStructure),
 	"set_structure": starlark.NewBuiltin("set_structure", dsSetStructure),
+	"each_row":      starlark.NewBuiltin("each_row", dsEachRow),
 }
 
 // NewDataset creates a dataset object, intended to be called from go-land to prepare datasets
@@ -198,13 +199,39 @@ func dsGetMeta(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwa
 	return util.Marshal(jsonData)
 }
 
-// dsSetMeta sets a dataset meta field
-func dsSetMeta(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+// standardMetaFields are the lower-cased keys dataset.Meta stores in its own
+// fields. Meta.Set keeps any other key as an arbitrary field
+var standardMetaFields = map[string]bool{
+	"qri":                true,
+	"accessurl":          true,
+	"accrualperiodicity": true,
+	"citations":          true,
+	"contributors":       true,
+	"description":        true,
+	"downloadurl":        true,
+	"homeurl":            true,
+	"identifier":         true,
+	"keywords":           true,
+	"language":           true,
+	"license":            true,
+	"path":               true,
+	"readmeurl":          true,
+	"theme":              true,
+	"title":              true,
+	"version":            true,
+}
+
+// dsSetMeta sets dataset meta fields, either from a dict of fields or a
+// single key & value. Keys that aren't standard meta fields print a warning,
+// or are an error when strict is true. Every field is checked before any are
+// set, so a bad field leaves meta unchanged
+func dsSetMeta(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var (
-		keyx starlark.String
-		valx starlark.Value
+		metax  starlark.Value
+		valx   starlark.Value
+		strict bool
 	)
-	if err := starlark.UnpackPositionalArgs("set_meta", args, kwargs, 2, &keyx, &valx); err != nil {
+	if err := starlark.UnpackArgs("set_meta", args, kwargs, "meta", &metax, "value?", &valx, "strict?", &strict); err != nil {
 		return nil, err
 	}
 	self := b.Receiver().(*Dataset)
@@ -212,20 +239,60 @@ func dsSetMeta(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwa
 	if self.frozen {
 		return starlark.None, fmt.Errorf("cannot call set_meta on frozen dataset")
 	}
-	self.changes["meta"] = struct{}{}
 
-	key := keyx.GoString()
+	var fields []starlark.Tuple
+	switch x := metax.(type) {
+	case starlark.String:
+		if valx == nil {
+			return starlark.None, fmt.Errorf("set_meta: missing value for key %s", x)
+		}
+		fields = []starlark.Tuple{{x, valx}}
+	case starlark.IterableMapping:
+		if valx != nil {
+			return starlark.None, fmt.Errorf("set_meta: can't set a value when setting meta from a %s", x.Type())
+		}
+		fields = x.Items()
+	default:
+		return starlark.None, fmt.Errorf("set_meta: expected a dict or string key, got %s", metax.Type())
+	}
 
-	val, err := util.Unmarshal(valx)
-	if err != nil {
-		return nil, err
+	keys := make([]string, len(fields))
+	vals := make([]interface{}, len(fields))
+	// setting values on a scratch meta checks they convert to their fields
+	scratch := &dataset.Meta{}
+	for i, field := range fields {
+		key, ok := starlark.AsString(field[0])
+		if !ok {
+			return starlark.None, fmt.Errorf("set_meta: meta keys must be strings, got %s", field[0].Type())
+		}
+		if !standardMetaFields[strings.ToLower(strings.TrimSpace(key))] {
+			if strict {
+				return starlark.None, fmt.Errorf("set_meta: %q isn't a standard meta field", key)
+			}
+			if thread.Print != nil {
+				thread.Print(thread, fmt.Sprintf("warning: set_meta: %q isn't a standard meta field", key))
+			}
+		}
+		val, err := util.Unmarshal(field[1])
+		if err != nil {
+			return starlark.None, err
+		}
+		if err := scratch.Set(key, val); err != nil {
+			return starlark.None, fmt.Errorf("set_meta: %q: %w", key, err)
+		}
+		keys[i], vals[i] = key, val
 	}
 
+	self.changes["meta"] = struct{}{}
 	if self.ds.Meta == nil {
 		self.ds.Meta = &dataset.Meta{}
 	}
-
-	return starlark.None, self.ds.Meta.Set(key, val)
+	for i, key := range keys {
+		if err := self.ds.Meta.Set(key, vals[i]); err != nil {
+			return starlark.None, err
+		}
+	}
+	return starlark.None, nil
 }
 
 // dsGetStructure gets a dataset structure component
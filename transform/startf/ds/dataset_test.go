@@ -2,6 +2,8 @@ package ds
 
 import (
 	"fmt"
+	"io/ioutil"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -73,6 +75,128 @@ func TestFile(t *testing.T) {
 	}
 }
 
+func TestEachRow(t *testing.T) {
+	thread := &starlark.Thread{Load: newLoader()}
+	ds := csvDataset()
+
+	script := `
+titles = []
+def collect(row):
+  titles.append(row[0])
+
+ds.each_row(collect)
+
+def double(row):
+  if row[1] > 1:
+    return [row[0], row[1] * 2, row[2]]
+
+ds.each_row(double)
+`
+	globals, err := starlark.ExecFile(thread, "each_row.star", script, starlark.StringDict{"ds": ds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(`["foo", "bar", "bat"]`, globals["titles"].String()); diff != "" {
+		t.Errorf("titles mismatch (-want +got):\n%s", diff)
+	}
+	if _, ok := ds.Changes()["body"]; !ok {
+		t.Error("expected replacing rows to change the body")
+	}
+
+	data, err := ioutil.ReadAll(ds.Dataset().BodyFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "title,count,is great\nfoo,1,true\nbar,4,false\nbat,6,meh\n"
+	if diff := cmp.Diff(expect, string(data)); diff != "" {
+		t.Errorf("body mismatch (-want +got):\n%s", diff)
+	}
+
+	// reading rows without replacing them keeps the body as-is
+	ds = csvDataset()
+	ds.Freeze()
+	noop := starlark.NewBuiltin("noop", func(*starlark.Thread, *starlark.Builtin, starlark.Tuple, []starlark.Tuple) (starlark.Value, error) {
+		return starlark.None, nil
+	})
+	if _, err := callMethod(thread, ds, "each_row", starlark.Tuple{noop}); err != nil {
+		t.Fatal(err)
+	}
+	bd, err := ds.Attr("body")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(bd.String(), "bat") {
+		t.Errorf("expected body to be readable after each_row, got: %s", bd)
+	}
+
+	// frozen datasets can't replace rows
+	ds = csvDataset()
+	ds.Freeze()
+	replace := starlark.NewBuiltin("replace", func(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, _ []starlark.Tuple) (starlark.Value, error) {
+		return args[0], nil
+	})
+	_, err = callMethod(thread, ds, "each_row", starlark.Tuple{replace})
+	if expect := "cannot replace rows with each_row on frozen dataset"; err == nil || err.Error() != expect {
+		t.Errorf("expected error %q, got: %v", expect, err)
+	}
+}
+
+func TestSetMeta(t *testing.T) {
+	var printed []string
+	thread := &starlark.Thread{
+		Load:  newLoader(),
+		Print: func(_ *starlark.Thread, msg string) { printed = append(printed, msg) },
+	}
+	ds := NewDataset(&dataset.Dataset{}, nil)
+
+	script := `
+ds.set_meta({"title": "dataset title", "keywords": ["a", "b"], "titel": "typo"})
+ds.set_meta("description", "about the dataset")
+`
+	if _, err := starlark.ExecFile(thread, "set_meta.star", script, starlark.StringDict{"ds": ds}); err != nil {
+		t.Fatal(err)
+	}
+	md := ds.Dataset().Meta
+	if md.Title != "dataset title" || md.Description != "about the dataset" || len(md.Keywords) != 2 {
+		t.Errorf("meta fields weren't set, got: %#v", md)
+	}
+	if expect := []string{`warning: set_meta: "titel" isn't a standard meta field`}; !cmp.Equal(expect, printed) {
+		t.Errorf("printed mismatch (-want +got):\n%s", cmp.Diff(expect, printed))
+	}
+
+	// strict rejects non-standard fields without setting any of them
+	ds = NewDataset(&dataset.Dataset{}, nil)
+	script = `ds.set_meta({"title": "dataset title", "titel": "typo"}, strict=True)`
+	_, err := starlark.ExecFile(thread, "set_meta_strict.star", script, starlark.StringDict{"ds": ds})
+	if expect := `set_meta: "titel" isn't a standard meta field`; err == nil || !strings.Contains(err.Error(), expect) {
+		t.Errorf("expected error containing %q, got: %v", expect, err)
+	}
+	if ds.Dataset().Meta != nil {
+		t.Errorf("expected strict error to leave meta unset, got: %#v", ds.Dataset().Meta)
+	}
+
+	// values that can't be set are an error without setting any fields
+	ds = NewDataset(&dataset.Dataset{}, nil)
+	script = `ds.set_meta({"title": "dataset title", "contributors": "not a list"})`
+	_, err = starlark.ExecFile(thread, "set_meta_invalid.star", script, starlark.StringDict{"ds": ds})
+	if expect := `set_meta: "contributors": contributors: expected interface slice`; err == nil || !strings.Contains(err.Error(), expect) {
+		t.Errorf("expected error containing %q, got: %v", expect, err)
+	}
+	if ds.Dataset().Meta != nil {
+		t.Errorf("expected invalid value to leave meta unset, got: %#v", ds.Dataset().Meta)
+	}
+
+	// path is a standard field
+	printed = nil
+	ds = NewDataset(&dataset.Dataset{}, nil)
+	if _, err := starlark.ExecFile(thread, "set_meta_path.star", `ds.set_meta("path", "/ipfs/QmMeta")`, starlark.StringDict{"ds": ds}); err != nil {
+		t.Fatal(err)
+	}
+	if len(printed) > 0 {
+		t.Errorf("expected no warnings setting path, got: %v", printed)
+	}
+}
+
 // load implements the 'load' operation as used in the evaluator tests.
 func newLoader() func(thread *starlark.Thread, module string) (starlark.StringDict, error) {
 	return testdata.NewLoader(LoadModule, ModuleName)
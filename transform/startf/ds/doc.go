@@ -8,21 +8,23 @@
       Dataset
         a qri dataset. Datasets can be either read-only or read-write. By default datasets are read-write
         methods:
-          set_meta(meta dict)
-            set dataset meta component
+          set_meta(meta dict|string, value=None, strict=False)
+            set dataset meta fields from a dict, or a single field from a key & value. keys that aren't standard
+            meta fields print a warning, or are an error when strict is True
           get_meta() dict|None
             get dataset meta component
           get_structure() dict|None
             get dataset structure component if one is defined
           set_structure(structure) structure
             set dataset structure component
-          get_body() dict|list|None
-            get dataset body component if one is defined
-          set_body(data dict|list, parse_as? string) body
-            set dataset body component. set_body has only one optional argument: 'parse_as', which defaults to the
-            empty string. By default qri assumes the data value provided to set_body is an iterable starlark data
-            structure (tuple, set, list, dict). When parse_as is set, set_body assumes the provided body value will
-            be a string of serialized structured data in the given format. valid parse_as values are "json", "csv",
-            "cbor", "xlsx".
+          each_row(fn callable)
+            call fn with each row of the body, reading rows one at a time instead of loading the whole body. when
+            fn returns a value other than None, that value replaces the row. each_row can't be used once the body
+            is loaded as a DataFrame with ds.body
+        fields:
+          body DataFrame
+            dataset body component as a DataFrame. assigning a DataFrame or an iterable starlark data structure
+            (tuple, list, dict) replaces the body. serialized data like csv or json must be parsed into one of
+            these before it's assigned
 */
 package ds
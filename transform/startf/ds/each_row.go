@@ -0,0 +1,168 @@
+package ds
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/qri-io/dataset/dsio"
+	"github.com/qri-io/qfs"
+	"github.com/qri-io/starlib/util"
+	"go.starlark.net/starlark"
+)
+
+// dsEachRow streams the dataset body, calling a function with each row.
+// Rows are read one at a time from the body file instead of loading the body
+// into a DataFrame. When the function returns a value other than None, that
+// value replaces the row
+func dsEachRow(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var fn starlark.Callable
+	if err := starlark.UnpackPositionalArgs("each_row", args, kwargs, 1, &fn); err != nil {
+		return nil, err
+	}
+	self := b.Receiver().(*Dataset)
+
+	if self.bodyFrame != nil {
+		return starlark.None, fmt.Errorf("each_row can't stream a body that's loaded as a DataFrame, use ds.body instead")
+	}
+	bodyfile := self.ds.BodyFile()
+	if bodyfile == nil {
+		return starlark.None, nil
+	}
+	st := self.ds.Structure
+	if st == nil {
+		return starlark.None, fmt.Errorf("error: no structure for dataset")
+	}
+
+	// keep the bytes of the body as they're read, so the body can be read
+	// again if no rows are replaced. both copies are spooled to disk
+	orig, err := newTempBodyFile()
+	if err != nil {
+		return starlark.None, err
+	}
+	src := io.TeeReader(bodyfile, orig)
+	rr, err := dsio.NewEntryReader(st, qfs.NewMemfileReader(bodyfile.FileName(), src))
+	if err != nil {
+		orig.Close()
+		return starlark.None, fmt.Errorf("error allocating data reader: %s", err)
+	}
+
+	out, err := newTempBodyFile()
+	if err != nil {
+		orig.Close()
+		return starlark.None, err
+	}
+	w, err := dsio.NewEntryWriter(st, out)
+	if err != nil {
+		orig.Close()
+		out.Close()
+		return starlark.None, fmt.Errorf("error allocating data writer: %s", err)
+	}
+
+	replaced, err := eachRow(thread, fn, rr, w, self.frozen)
+	if err == nil {
+		err = w.Close()
+	}
+	if err == nil {
+		// read the remainder of the body, so the kept copy is complete
+		_, err = io.Copy(ioutil.Discard, src)
+	}
+	bodyfile.Close()
+	if err != nil {
+		orig.Close()
+		out.Close()
+		return starlark.None, err
+	}
+
+	name := fmt.Sprintf("body.%s", strings.ToLower(st.Format))
+	if replaced == 0 {
+		out.Close()
+		self.ds.SetBodyFile(orig.file(name))
+		return starlark.None, nil
+	}
+
+	orig.Close()
+	self.changes["body"] = struct{}{}
+	self.ds.SetBodyFile(out.file(name))
+	self.ds.Structure.Length = int(out.size)
+	return starlark.None, nil
+}
+
+// eachRow calls fn with every entry read from rr, writing the row or its
+// replacement to w. it returns the number of replaced rows
+func eachRow(thread *starlark.Thread, fn starlark.Callable, rr dsio.EntryReader, w dsio.EntryWriter, frozen bool) (replaced int, err error) {
+	for i := 0; ; i++ {
+		ent, err := rr.ReadEntry()
+		if err != nil {
+			if err == io.EOF || err.Error() == "EOF" {
+				return replaced, nil
+			}
+			return replaced, fmt.Errorf("each_row: reading row %d: %w", i, err)
+		}
+
+		row, err := util.Marshal(ent.Value)
+		if err != nil {
+			return replaced, fmt.Errorf("each_row: row %d: %w", i, err)
+		}
+		res, err := starlark.Call(thread, fn, starlark.Tuple{row}, nil)
+		if err != nil {
+			return replaced, err
+		}
+		if res != starlark.None {
+			if frozen {
+				return replaced, fmt.Errorf("cannot replace rows with each_row on frozen dataset")
+			}
+			if ent.Value, err = util.Unmarshal(res); err != nil {
+				return replaced, fmt.Errorf("each_row: row %d: %w", i, err)
+			}
+			replaced++
+		}
+		if err := w.WriteEntry(ent); err != nil {
+			return replaced, err
+		}
+	}
+}
+
+// tempBodyFile spools body bytes to a temporary file on disk. the file is
+// unlinked as soon as it's created where the platform allows, so it's
+// cleaned up even if the body file is never closed
+type tempBodyFile struct {
+	*os.File
+	size int64
+}
+
+func newTempBodyFile() (*tempBodyFile, error) {
+	f, err := ioutil.TempFile("", "qri_each_row")
+	if err != nil {
+		return nil, err
+	}
+	// removing an open file fails on windows, Close tries again
+	os.Remove(f.Name())
+	return &tempBodyFile{File: f}, nil
+}
+
+func (f *tempBodyFile) Write(p []byte) (int, error) {
+	n, err := f.File.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// file rewinds the temp file, returning it as a body file. closing the body
+// file removes the temp file
+func (f *tempBodyFile) file(name string) qfs.File {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		log.Debugw("rewinding temp body file", "err", err)
+	}
+	return qfs.NewMemfileReaderSize(name, f, f.size)
+}
+
+// Close closes & removes the temp file
+func (f *tempBodyFile) Close() error {
+	err := f.File.Close()
+	if rmErr := os.Remove(f.Name()); rmErr != nil && !os.IsNotExist(rmErr) {
+		log.Debugw("removing temp body file", "err", rmErr)
+	}
+	return err
+}
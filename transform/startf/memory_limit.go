@@ -0,0 +1,63 @@
+package startf
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"go.starlark.net/starlark"
+)
+
+// ErrMemoryLimitExceeded is returned when a transform step allocates more
+// memory than the configured limit allows
+var ErrMemoryLimitExceeded = errors.New("transform exceeded memory limit")
+
+// memoryCheckInterval is how often a running step's memory use is checked
+var memoryCheckInterval = 50 * time.Millisecond
+
+// memoryWatch cancels a starlark thread when heap allocation grows more than
+// limit bytes past the heap size when the watch started. This makes the limit
+// approximate: heap use is process-wide, so allocations made outside the
+// script count toward the limit while the script runs, and a script can
+// overshoot the limit by whatever it allocates between checks
+type memoryWatch struct {
+	exceeded int32
+	done     chan struct{}
+}
+
+// watchMemory starts monitoring memory use for the duration of a step. stop
+// must be called once the step finishes
+func watchMemory(thread *starlark.Thread, limit uint64) *memoryWatch {
+	w := &memoryWatch{done: make(chan struct{})}
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	ceiling := ms.HeapAlloc + limit
+
+	go func() {
+		ticker := time.NewTicker(memoryCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+				runtime.ReadMemStats(&ms)
+				if ms.HeapAlloc > ceiling {
+					log.Debugw("transform exceeded memory limit", "heapAlloc", ms.HeapAlloc, "ceiling", ceiling)
+					atomic.StoreInt32(&w.exceeded, 1)
+					thread.Cancel(ErrMemoryLimitExceeded.Error())
+					return
+				}
+			}
+		}
+	}()
+	return w
+}
+
+// stop ends monitoring, reporting if the limit was exceeded
+func (w *memoryWatch) stop() bool {
+	close(w.done)
+	return atomic.LoadInt32(&w.exceeded) == 1
+}
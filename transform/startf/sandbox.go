@@ -1,6 +1,7 @@
 package startf
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -16,19 +17,80 @@ var (
 	ErrNtwkDisabled = fmt.Errorf("network use is disabled. http can only be used during download step")
 )
 
+// allowedHostsKey is the thread-local key for a list of hosts http requests
+// are restricted to
+const allowedHostsKey = "__allowedHosts"
+
+// allowedHostsCtxKey carries a thread's allowlist on the requests it makes, so
+// redirects can be checked against it
+type allowedHostsCtxKey struct{}
+
 // HTTPGuard protects network requests, only allowing when network is enabled
+// and the requested host is permitted by the thread's allowlist, if one is set
 type HTTPGuard struct {
 	NetworkEnabled bool
 }
 
 // Allowed implements starlib/http RequestGuard
-func (h *HTTPGuard) Allowed(_ *starlark.Thread, req *http.Request) (*http.Request, error) {
+func (h *HTTPGuard) Allowed(thread *starlark.Thread, req *http.Request) (*http.Request, error) {
 	if !h.NetworkEnabled {
 		return nil, ErrNtwkDisabled
 	}
+	if thread != nil {
+		if hosts, ok := thread.Local(allowedHostsKey).([]string); ok && len(hosts) > 0 {
+			host := req.URL.Hostname()
+			if !hostAllowed(hosts, host) {
+				return nil, hostNotAllowedError(hosts, host)
+			}
+			return req.WithContext(context.WithValue(req.Context(), allowedHostsCtxKey{}, hosts)), nil
+		}
+	}
 	return req, nil
 }
 
+// checkRedirect applies the allowlist of the request that started a chain of
+// redirects to every redirect in the chain
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	// match the default policy of http.Client
+	if len(via) >= 10 {
+		return fmt.Errorf("stopped after 10 redirects")
+	}
+	if hosts, ok := req.Context().Value(allowedHostsCtxKey{}).([]string); ok {
+		host := req.URL.Hostname()
+		if !hostAllowed(hosts, host) {
+			return hostNotAllowedError(hosts, host)
+		}
+	}
+	return nil
+}
+
+func hostNotAllowedError(allowed []string, host string) error {
+	return fmt.Errorf("http request to host %q is not allowed. allowed hosts are: %s", host, strings.Join(allowed, ", "))
+}
+
+// SetAllowedHosts restricts http requests made from a thread to the given
+// list of hosts. An empty list allows requests to any host
+func SetAllowedHosts(thread *starlark.Thread, hosts []string) {
+	thread.SetLocal(allowedHostsKey, hosts)
+}
+
+// hostAllowed checks a hostname against an allowlist. Entries are either an
+// exact hostname or a "*." prefixed domain that matches all subdomains
+func hostAllowed(allowed []string, host string) bool {
+	host = strings.ToLower(host)
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if strings.HasPrefix(a, "*.") {
+			if strings.HasSuffix(host, a[1:]) {
+				return true
+			}
+		} else if a == host {
+			return true
+		}
+	}
+	return false
+}
+
 // EnableNtwk allows network calls
 func (h *HTTPGuard) EnableNtwk() {
 	h.NetworkEnabled = true
@@ -42,6 +104,8 @@ func (h *HTTPGuard) DisableNtwk() {
 func init() {
 	// connect httpGuard instance to starlib http guard
 	starhttp.Guard = httpGuard
+	// redirects skip the guard, check them with the client instead
+	starhttp.Client = &http.Client{CheckRedirect: checkRedirect}
 }
 
 type config map[string]interface{}
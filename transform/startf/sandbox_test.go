@@ -1,8 +1,13 @@
 package startf
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	starhttp "github.com/qri-io/starlib/http"
 	"github.com/qri-io/starlib/testdata"
 	"go.starlark.net/starlark"
 	"go.starlark.net/starlarktest"
@@ -53,3 +58,81 @@ assert.eq(secrets.get("c"), "string")
 		t.Fatal(err)
 	}
 }
+
+func TestHTTPGuardAllowedHosts(t *testing.T) {
+	guard := &HTTPGuard{NetworkEnabled: true}
+
+	cases := []struct {
+		url     string
+		allowed []string
+		expect  bool
+	}{
+		{"https://example.com/data.csv", nil, true},
+		{"https://example.com/data.csv", []string{"example.com"}, true},
+		{"https://EXAMPLE.com:8080/data.csv", []string{"example.com"}, true},
+		{"https://api.example.com/data.csv", []string{"*.example.com"}, true},
+		{"https://api.example.com/data.csv", []string{"example.com"}, false},
+		{"https://badexample.com/data.csv", []string{"*.example.com"}, false},
+		{"https://other.org/data.csv", []string{"example.com", "qri.io"}, false},
+	}
+
+	for _, c := range cases {
+		thread := &starlark.Thread{}
+		SetAllowedHosts(thread, c.allowed)
+		req := httptest.NewRequest("GET", c.url, nil)
+		_, err := guard.Allowed(thread, req)
+		if c.expect && err != nil {
+			t.Errorf("%s with allowlist %v: unexpected error: %s", c.url, c.allowed, err)
+		} else if !c.expect && err == nil {
+			t.Errorf("%s with allowlist %v: expected error, got nil", c.url, c.allowed)
+		}
+	}
+}
+
+func TestHTTPGuardAllowedHostsRedirect(t *testing.T) {
+	guard := &HTTPGuard{NetworkEnabled: true}
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer target.Close()
+	port := target.URL[strings.LastIndex(target.URL, ":")+1:]
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := "127.0.0.1"
+		if r.URL.Path == "/elsewhere" {
+			host = "localhost"
+		}
+		http.Redirect(w, r, fmt.Sprintf("http://%s:%s/", host, port), http.StatusFound)
+	}))
+	defer s.Close()
+
+	cases := []struct {
+		path   string
+		expect bool
+	}{
+		{"/", true},
+		{"/elsewhere", false},
+	}
+
+	for _, c := range cases {
+		thread := &starlark.Thread{}
+		SetAllowedHosts(thread, []string{"127.0.0.1"})
+		req, err := http.NewRequest("GET", s.URL+c.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if req, err = guard.Allowed(thread, req); err != nil {
+			t.Fatal(err)
+		}
+		res, err := starhttp.Client.Do(req)
+		if err == nil {
+			res.Body.Close()
+		}
+		if c.expect && err != nil {
+			t.Errorf("%s: unexpected error: %s", c.path, err)
+		} else if !c.expect && err == nil {
+			t.Errorf("%s: expected redirect to a host that isn't allowed to error, got nil", c.path)
+		}
+	}
+}
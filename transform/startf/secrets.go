@@ -0,0 +1,99 @@
+package startf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.starlark.net/starlark"
+)
+
+// ErrSecretNotFound indicates a secret provider has no value for a key
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretProvider resolves secrets scripts reference with qri.get_secret.
+// Providers are registered by scheme, a script calling
+// qri.get_secret("vault://path/to/key") asks the provider registered as
+// "vault" for the key "path/to/key"
+type SecretProvider interface {
+	// Secret returns the secret value stored at key, or ErrSecretNotFound
+	Secret(ctx context.Context, key string) (string, error)
+}
+
+// MapSecretProvider serves secrets from a map. The secrets a run is given are
+// the default provider, resolving references that have no scheme
+type MapSecretProvider map[string]interface{}
+
+// Secret implements the SecretProvider interface
+func (p MapSecretProvider) Secret(_ context.Context, key string) (string, error) {
+	val, ok := p[key]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return fmt.Sprintf("%v", val), nil
+}
+
+// EnvSecretProvider reads secrets from environment variables, the key is the
+// variable name
+type EnvSecretProvider struct{}
+
+// Secret implements the SecretProvider interface
+func (EnvSecretProvider) Secret(_ context.Context, key string) (string, error) {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return val, nil
+}
+
+// FileSecretProvider reads secrets from files within a directory, the key is
+// a path relative to Dir. Surrounding whitespace is trimmed from file contents
+type FileSecretProvider struct {
+	Dir string
+}
+
+// Secret implements the SecretProvider interface
+func (p FileSecretProvider) Secret(_ context.Context, key string) (string, error) {
+	path := filepath.Join(p.Dir, filepath.FromSlash(key))
+	if rel, err := filepath.Rel(p.Dir, path); err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("secret path %q is outside the secrets directory", key)
+	}
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrSecretNotFound
+	} else if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// getSecretFunc returns an implementation of the qri.get_secret builtin
+func (r *StepRunner) getSecretFunc(ctx context.Context) func(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	return func(thread *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var ref string
+		if err := starlark.UnpackPositionalArgs("get_secret", args, kwargs, 1, &ref); err != nil {
+			return starlark.None, err
+		}
+
+		var provider SecretProvider = MapSecretProvider(r.secrets)
+		key := ref
+		if i := strings.Index(ref, "://"); i >= 0 {
+			scheme := ref[:i]
+			key = ref[i+len("://"):]
+			var ok bool
+			if provider, ok = r.secretProviders[scheme]; !ok {
+				return starlark.None, fmt.Errorf("get_secret: no secret provider for %q", scheme)
+			}
+		}
+
+		val, err := provider.Secret(ctx, key)
+		if err != nil {
+			return starlark.None, fmt.Errorf("get_secret %q: %w", ref, err)
+		}
+		return starlark.String(val), nil
+	}
+}
@@ -2,6 +2,7 @@ package startf
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -51,6 +52,8 @@ type ExecOpts struct {
 	AllowNestedDef bool
 	// passed-in secrets (eg: API keys)
 	Secrets map[string]interface{}
+	// providers qri.get_secret resolves references with, keyed by scheme
+	SecretProviders map[string]SecretProvider
 	// global values to pass for script execution
 	Globals starlark.StringDict
 	// provide a writer to record script "stderr" output to
@@ -64,8 +67,21 @@ type ExecOpts struct {
 	// the size of the output area, for stringifying large objects
 	OutputWidth  int
 	OutputHeight int
+	// hosts scripts may make http requests to. empty allows all hosts
+	AllowedHosts []string
+	// json-encoded state checkpointed by a previous run, returned to scripts
+	// by qri.resume_state()
+	ResumeState json.RawMessage
+	// bytes of memory a step may allocate before it's aborted. zero is no limit
+	MaxMemory uint64
+	// loads the stats of the previous version for qri.prev_stats()
+	PrevStats PrevStatsFunc
 }
 
+// PrevStatsFunc loads the stats component of the version a transform builds
+// on. It returns nil stats if there's no previous version
+type PrevStatsFunc func(ctx context.Context) (*dataset.Stats, error)
+
 // AddDatasetLoader is required to enable the load_dataset starlark builtin
 func AddDatasetLoader(loader dsref.Loader) func(o *ExecOpts) {
 	return func(o *ExecOpts) {
@@ -110,6 +126,14 @@ func SetSecrets(secrets map[string]string) func(o *ExecOpts) {
 	}
 }
 
+// AddSecretProviders registers providers scripts can read secrets from with
+// qri.get_secret, keyed by the scheme that references them, eg: "vault"
+func AddSecretProviders(providers map[string]SecretProvider) func(o *ExecOpts) {
+	return func(o *ExecOpts) {
+		o.SecretProviders = providers
+	}
+}
+
 // SetErrWriter provides a writer to record the "stderr" diagnostic output of
 // the transform script
 func SetErrWriter(w io.Writer) func(o *ExecOpts) {
@@ -133,6 +157,41 @@ func SizeInfo(outWidth, outHeight int) func(o *ExecOpts) {
 	}
 }
 
+// AllowHosts restricts http requests made by a script to a list of hosts
+func AllowHosts(hosts []string) func(o *ExecOpts) {
+	return func(o *ExecOpts) {
+		o.AllowedHosts = hosts
+	}
+}
+
+// SetResumeState provides state checkpointed by a previous run for the script
+// to resume from
+func SetResumeState(state json.RawMessage) func(o *ExecOpts) {
+	return func(o *ExecOpts) {
+		o.ResumeState = state
+	}
+}
+
+// LimitMemory aborts steps that allocate more than max bytes of memory. A
+// limit of zero places no restriction on memory. The limit is approximate:
+// starlark doesn't account allocations per thread, so memory is measured as
+// growth of the process heap, sampled every memoryCheckInterval. Steps can
+// overshoot the limit between samples, and allocations other goroutines make
+// while the step runs count against it
+func LimitMemory(max uint64) func(o *ExecOpts) {
+	return func(o *ExecOpts) {
+		o.MaxMemory = max
+	}
+}
+
+// SetPrevStats provides the stats of the previous version to scripts through
+// qri.prev_stats()
+func SetPrevStats(fn PrevStatsFunc) func(o *ExecOpts) {
+	return func(o *ExecOpts) {
+		o.PrevStats = fn
+	}
+}
+
 // DefaultExecOpts applies default options to an ExecOpts pointer
 func DefaultExecOpts(o *ExecOpts) {
 	o.AllowFloat = true
@@ -145,17 +204,21 @@ func DefaultExecOpts(o *ExecOpts) {
 
 // StepRunner is able to run individual transform steps
 type StepRunner struct {
-	config       map[string]interface{}
-	secrets      map[string]interface{}
-	fs           qfs.Filesystem
-	dsLoader     dsref.Loader
-	stards       *stards.BoundDataset
-	globals      starlark.StringDict
-	eventsCh     chan event.Event
-	writer       io.Writer
-	thread       *starlark.Thread
-	changeSet    map[string]struct{}
-	commitCalled bool
+	config          map[string]interface{}
+	secrets         map[string]interface{}
+	secretProviders map[string]SecretProvider
+	fs              qfs.Filesystem
+	dsLoader        dsref.Loader
+	stards          *stards.BoundDataset
+	globals         starlark.StringDict
+	eventsCh        chan event.Event
+	writer          io.Writer
+	thread          *starlark.Thread
+	changeSet       map[string]struct{}
+	resumeState     json.RawMessage
+	maxMemory       uint64
+	prevStats       PrevStatsFunc
+	commitCalled    bool
 }
 
 // NewStepRunner returns a new StepRunner for the given dataset
@@ -195,20 +258,26 @@ func NewStepRunner(target *dataset.Dataset, opts ...func(o *ExecOpts)) *StepRunn
 		},
 	}
 
+	SetAllowedHosts(thread, o.AllowedHosts)
+
 	// Store the OutputConfig on the starlark thread. This allows functions
 	// such as the DataFrame constructor to get this configuration
 	outconf := dataframe.SetOutputSize(thread, o.OutputWidth, o.OutputHeight)
 
 	r := &StepRunner{
-		config:    target.Transform.Config,
-		secrets:   o.Secrets,
-		fs:        o.Filesystem,
-		dsLoader:  o.DatasetLoader,
-		eventsCh:  o.EventsCh,
-		writer:    o.ErrWriter,
-		thread:    thread,
-		globals:   starlark.StringDict{},
-		changeSet: o.ChangeSet,
+		config:          target.Transform.Config,
+		secrets:         o.Secrets,
+		secretProviders: o.SecretProviders,
+		fs:              o.Filesystem,
+		dsLoader:        o.DatasetLoader,
+		eventsCh:        o.EventsCh,
+		writer:          o.ErrWriter,
+		thread:          thread,
+		globals:         starlark.StringDict{},
+		changeSet:       o.ChangeSet,
+		resumeState:     o.ResumeState,
+		maxMemory:       o.MaxMemory,
+		prevStats:       o.PrevStats,
 	}
 	r.stards = stards.NewBoundDataset(target, outconf, r.onCommit)
 
@@ -221,6 +290,7 @@ func (r *StepRunner) RunStep(ctx context.Context, ds *dataset.Dataset, st *datas
 	r.globals["dataset"] = r.stards
 	r.globals["config"] = config(r.config)
 	r.globals["secrets"] = secrets(r.secrets)
+	r.globals["qri"] = r.qriStruct(ctx)
 
 	script, ok := st.Script.(string)
 	if !ok {
@@ -245,7 +315,27 @@ func (r *StepRunner) RunStep(ctx context.Context, ds *dataset.Dataset, st *datas
 
 	r.printFinalStatement(file)
 
+	var watch *memoryWatch
+	if r.maxMemory > 0 {
+		watch = watchMemory(r.thread, r.maxMemory)
+	}
+	// stop the script when the step is canceled
+	stepDone := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			r.thread.Cancel(ctx.Err().Error())
+		case <-stepDone:
+		}
+	}()
 	globals, err := mod.Init(r.thread, r.globals)
+	close(stepDone)
+	if watch != nil && watch.stop() {
+		return fmt.Errorf("%w of %d MB", ErrMemoryLimitExceeded, r.maxMemory/(1024*1024))
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
 	if err != nil {
 		if evalErr, ok := err.(*starlark.EvalError); ok {
 			return fmt.Errorf(evalErr.Backtrace())
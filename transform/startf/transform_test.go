@@ -2,10 +2,16 @@ package startf
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/qri-io/dataset"
@@ -13,6 +19,7 @@ import (
 	"github.com/qri-io/dataset/stepfile"
 	"github.com/qri-io/qfs"
 	"github.com/qri-io/qri/base"
+	"github.com/qri-io/qri/event"
 	"github.com/qri-io/qri/repo"
 	repoTest "github.com/qri-io/qri/repo/test"
 	"github.com/qri-io/starlib"
@@ -206,6 +213,121 @@ func TestLoadDataset(t *testing.T) {
 	}
 }
 
+func TestCheckpoint(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		resume json.RawMessage
+		expect string
+	}{
+		{nil, `{"page":1}`},
+		{json.RawMessage(`{"page":4}`), `{"page":5}`},
+	}
+
+	for _, c := range cases {
+		ds := &dataset.Dataset{
+			Transform: &dataset.Transform{},
+		}
+		ds.Transform.SetScriptFile(scriptFile(t, "testdata/checkpoint.star"))
+
+		eventsCh := make(chan event.Event, 1)
+		if err := ExecScript(ctx, ds, AddEventsChannel(eventsCh), SetResumeState(c.resume)); err != nil {
+			t.Fatal(err)
+		}
+
+		e := <-eventsCh
+		if e.Type != event.ETTransformCheckpoint {
+			t.Fatalf("expected checkpoint event, got: %q", e.Type)
+		}
+		got := string(e.Payload.(event.TransformCheckpoint).State)
+		if got != c.expect {
+			t.Errorf("checkpoint state mismatch. expected: %s, got: %s", c.expect, got)
+		}
+	}
+}
+
+type testSecretProvider map[string]string
+
+func (p testSecretProvider) Secret(_ context.Context, key string) (string, error) {
+	if val, ok := p[key]; ok {
+		return val, nil
+	}
+	return "", ErrSecretNotFound
+}
+
+func TestGetSecret(t *testing.T) {
+	ctx := context.Background()
+	newDataset := func() *dataset.Dataset {
+		ds := &dataset.Dataset{Transform: &dataset.Transform{}}
+		ds.Transform.SetScriptFile(scriptFile(t, "testdata/get_secret.star"))
+		return ds
+	}
+
+	ds := newDataset()
+	err := ExecScript(ctx, ds,
+		SetSecrets(map[string]string{"api_key": "abc"}),
+		AddSecretProviders(map[string]SecretProvider{"test": testSecretProvider{"token": "xyz"}}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadAll(ds.BodyFile())
+	if expect := "abc,xyz\n"; string(data) != expect {
+		t.Errorf("body mismatch. expected: %q, got: %q", expect, string(data))
+	}
+
+	err = ExecScript(ctx, newDataset(), SetSecrets(map[string]string{"api_key": "abc"}))
+	if err == nil || !strings.Contains(err.Error(), `get_secret: no secret provider for "test"`) {
+		t.Errorf("expected missing provider error, got: %v", err)
+	}
+
+	err = ExecScript(ctx, newDataset())
+	if err == nil || !strings.Contains(err.Error(), `get_secret "api_key": secret not found`) {
+		t.Errorf("expected secret not found error, got: %v", err)
+	}
+}
+
+func TestPrevStats(t *testing.T) {
+	ctx := context.Background()
+	run := func(opts ...func(o *ExecOpts)) string {
+		t.Helper()
+		ds := &dataset.Dataset{Transform: &dataset.Transform{}}
+		ds.Transform.SetScriptFile(scriptFile(t, "testdata/prev_stats.star"))
+		if err := ExecScript(ctx, ds, opts...); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := ioutil.ReadAll(ds.BodyFile())
+		return string(data)
+	}
+
+	if got := run(); got != "none\n" {
+		t.Errorf("expected None without previous stats, got body: %q", got)
+	}
+
+	prev := &dataset.Stats{Stats: []interface{}{map[string]interface{}{"count": 12}}}
+	got := run(SetPrevStats(func(context.Context) (*dataset.Stats, error) { return prev, nil }))
+	if got != "12\n" {
+		t.Errorf("expected previous row count, got body: %q", got)
+	}
+}
+
+func TestFileSecretProvider(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "token"), []byte("xyz\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	p := FileSecretProvider{Dir: dir}
+	if got, err := p.Secret(context.Background(), "token"); err != nil || got != "xyz" {
+		t.Errorf("expected secret %q, got: %q, err: %v", "xyz", got, err)
+	}
+	if _, err := p.Secret(context.Background(), "missing"); !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("expected secret not found error, got: %v", err)
+	}
+	if _, err := p.Secret(context.Background(), "../token"); err == nil {
+		t.Error("expected reading a secret outside the directory to error")
+	}
+}
+
 func TestGetMetaNilPrev(t *testing.T) {
 	ctx := context.Background()
 	ds := &dataset.Dataset{
@@ -292,3 +414,33 @@ func ExecScript(ctx context.Context, ds *dataset.Dataset, opts ...func(o *ExecOp
 	}
 	return nil
 }
+
+func TestMemoryLimit(t *testing.T) {
+	// the limited script allocates far more than the limit, and can't finish
+	// before it's canceled no matter how memory checks are scheduled. the
+	// timeout fails the test instead of hanging if the limit is never hit
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	newDataset := func(rows int) *dataset.Dataset {
+		script := fmt.Sprintf(`
+rows = []
+for i in range(%d):
+  rows.append("row number %%d" %% i)
+`, rows)
+		ds := &dataset.Dataset{Transform: &dataset.Transform{}}
+		ds.Transform.SetScriptFile(qfs.NewMemfileBytes("tf.star", []byte(script)))
+		return ds
+	}
+
+	err := ExecScript(ctx, newDataset(1000000000), LimitMemory(1024*1024))
+	if !errors.Is(err, ErrMemoryLimitExceeded) {
+		t.Fatalf("expected ErrMemoryLimitExceeded, got: %v", err)
+	}
+	if expect := "transform exceeded memory limit of 1 MB"; err.Error() != expect {
+		t.Errorf("error message mismatch. want: %q, got: %q", expect, err.Error())
+	}
+
+	if err := ExecScript(ctx, newDataset(100000), LimitMemory(0)); err != nil {
+		t.Errorf("expected no limit to run to completion, got: %s", err)
+	}
+}
@@ -2,6 +2,7 @@ package transform
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -47,6 +48,10 @@ const (
 	// meaning, the transform was run with the intension to save the
 	// output dataset
 	RMCommit = "commit"
+	// RMVerify indicates the transform was executed as a "verify"
+	// meaning, the transform was replayed to check it reproduces a saved
+	// dataset version, with no intension to save the output dataset
+	RMVerify = "verify"
 )
 
 // Transformer holds dependencies needed for applying a transform
@@ -57,8 +62,25 @@ type Transformer struct {
 	pub      event.Publisher
 	sizeInfo SizeInfo
 	changes  map[string]struct{}
+	// hosts transform scripts may make http requests to
+	allowedHosts []string
+	// state checkpointed by a previous run to resume from
+	resumeState json.RawMessage
+	// providers scripts read secrets from with qri.get_secret, keyed by scheme
+	secretProviders map[string]SecretProvider
+	// bytes of memory a step may allocate, zero is no limit
+	maxMemory uint64
+	// loads stats of the previous version for qri.prev_stats()
+	statsLoader StatsLoader
 }
 
+// StatsLoader loads the stats component of the dataset version at path
+type StatsLoader func(ctx context.Context, path string) (*dataset.Stats, error)
+
+// SecretProvider resolves secret references in transform scripts, see
+// startf.SecretProvider
+type SecretProvider = startf.SecretProvider
+
 // SizeInfo is info about the size of the area that output is displayed on
 type SizeInfo struct {
 	OutputWidth  int
@@ -76,6 +98,54 @@ func NewTransformer(appCtx context.Context, fs qfs.Filesystem, loader dsref.Load
 	}
 }
 
+// SetAllowedHosts restricts the hosts transform scripts can make http requests
+// to. An empty list allows requests to any host
+func (t *Transformer) SetAllowedHosts(hosts []string) {
+	t.allowedHosts = hosts
+}
+
+// SetResumeState provides json-encoded state checkpointed by a previous run.
+// Scripts access this state with qri.resume_state()
+func (t *Transformer) SetResumeState(state json.RawMessage) {
+	t.resumeState = state
+}
+
+// SetSecretProviders registers providers transform scripts can read secrets
+// from with qri.get_secret, keyed by the scheme that references them.
+// References without a scheme read from the secrets a run is given
+func (t *Transformer) SetSecretProviders(providers map[string]SecretProvider) {
+	t.secretProviders = providers
+}
+
+// SetMaxMemoryMB caps the memory each transform step may allocate, in
+// megabytes. Steps that exceed the cap fail with
+// startf.ErrMemoryLimitExceeded. The cap is approximate, see
+// startf.LimitMemory. Zero removes the limit
+func (t *Transformer) SetMaxMemoryMB(mb int) {
+	if mb < 0 {
+		mb = 0
+	}
+	t.maxMemory = uint64(mb) * 1024 * 1024
+}
+
+// SetStatsLoader provides stats of the version a transform builds on to
+// scripts through qri.prev_stats(). Without a loader qri.prev_stats() returns
+// None
+func (t *Transformer) SetStatsLoader(loader StatsLoader) {
+	t.statsLoader = loader
+}
+
+// prevStatsFunc loads stats of the version at prevPath, nil when there's no
+// previous version
+func (t *Transformer) prevStatsFunc(prevPath string) startf.PrevStatsFunc {
+	return func(ctx context.Context) (*dataset.Stats, error) {
+		if prevPath == "" || t.statsLoader == nil {
+			return nil, nil
+		}
+		return t.statsLoader(ctx, prevPath)
+	}
+}
+
 // Apply applies the transform script to a target dataset
 func (t *Transformer) Apply(
 	ctx context.Context,
@@ -100,6 +170,20 @@ func (t *Transformer) Commit(
 	return t.apply(ctx, initID, target, runID, wait, secrets, RMCommit)
 }
 
+// Verify applies the transform script to a target dataset, waiting for the
+// transform to finish & associating all events with the "verify" RunMode. It
+// runs the same way as Commit, but the output is only used for comparison with
+// a saved version
+func (t *Transformer) Verify(
+	ctx context.Context,
+	initID string,
+	target *dataset.Dataset,
+	runID string,
+	secrets map[string]string,
+) error {
+	return t.apply(ctx, initID, target, runID, true, secrets, RMVerify)
+}
+
 func (t *Transformer) apply(
 	ctx context.Context,
 	initID string,
@@ -123,6 +207,7 @@ func (t *Transformer) apply(
 
 	ownerID := profile.IDFromCtx(ctx)
 
+	prevPath := ""
 	if target.Name != "" {
 		head, err := t.loader.LoadDataset(ctx, fmt.Sprintf("%s/%s", target.Peername, target.Name))
 		if errors.Is(err, dsref.ErrRefNotFound) || errors.Is(err, dsref.ErrNoHistory) {
@@ -132,6 +217,7 @@ func (t *Transformer) apply(
 		} else if err != nil {
 			return err
 		}
+		prevPath = head.Path
 
 		head.DropTransientValues()
 		head.DropDerivedValues()
@@ -157,6 +243,11 @@ func (t *Transformer) apply(
 		startf.AddEventsChannel(eventsCh),
 		startf.TrackChanges(t.changes),
 		startf.SizeInfo(t.sizeInfo.OutputWidth, t.sizeInfo.OutputHeight),
+		startf.AllowHosts(t.allowedHosts),
+		startf.SetResumeState(t.resumeState),
+		startf.AddSecretProviders(t.secretProviders),
+		startf.LimitMemory(t.maxMemory),
+		startf.SetPrevStats(t.prevStatsFunc(prevPath)),
 	}
 
 	doneCh := make(chan error)